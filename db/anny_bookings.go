@@ -39,8 +39,18 @@ func (db *DB) AllAnnyBookings(ctx context.Context) ([]*model.AnnyBooking, error)
 	return db.annyBookings(ctx, nil)
 }
 
+// AnnyBookingsForDatabase returns all Anny bookings stored in a specific database
+// (by exact name), e.g. for comparing with another semester.
+func (db *DB) AnnyBookingsForDatabase(ctx context.Context, database string) ([]*model.AnnyBooking, error) {
+	return db.annyBookingsFrom(ctx, database, nil)
+}
+
 func (db *DB) annyBookings(ctx context.Context, room *string) ([]*model.AnnyBooking, error) {
-	collection := db.Client.Database(db.databaseName).Collection(collectionAnnyBookings)
+	return db.annyBookingsFrom(ctx, db.databaseName, room)
+}
+
+func (db *DB) annyBookingsFrom(ctx context.Context, database string, room *string) ([]*model.AnnyBooking, error) {
+	collection := db.Client.Database(database).Collection(collectionAnnyBookings)
 
 	filter := bson.M{}
 	if room != nil && strings.TrimSpace(*room) != "" {
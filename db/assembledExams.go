@@ -55,7 +55,16 @@ func (db *DB) DropAssembledExams(ctx context.Context) (int64, error) {
 }
 
 func (db *DB) GetAssembledExams(ctx context.Context) ([]*model.AssembledExam, error) {
-	collection := db.Client.Database(db.databaseName).Collection(collectionAssembledExams)
+	return db.assembledExamsFrom(ctx, db.databaseName)
+}
+
+// AssembledExamsForDatabase returns the assembled exams of another semester's database.
+func (db *DB) AssembledExamsForDatabase(ctx context.Context, database string) ([]*model.AssembledExam, error) {
+	return db.assembledExamsFrom(ctx, database)
+}
+
+func (db *DB) assembledExamsFrom(ctx context.Context, database string) ([]*model.AssembledExam, error) {
+	collection := db.Client.Database(database).Collection(collectionAssembledExams)
 
 	cur, err := collection.Find(ctx, bson.M{})
 	if err != nil {
@@ -0,0 +1,178 @@
+package db
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The campus model (campuses, buildings, travel times, room locations) is global
+// (plexams DB) like the rooms.
+
+// Campuses returns all campuses, sorted by name.
+func (db *DB) Campuses(ctx context.Context) ([]*model.Campus, error) {
+	collection := db.Client.Database("plexams").Collection(collectionCampuses)
+	cur, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		log.Error().Err(err).Str("collection", collectionCampuses).Msg("MongoDB Find")
+		return nil, err
+	}
+	campuses := make([]*model.Campus, 0)
+	if err := cur.All(ctx, &campuses); err != nil {
+		log.Error().Err(err).Str("collection", collectionCampuses).Msg("cannot decode campuses")
+		return nil, err
+	}
+	return campuses, nil
+}
+
+// SetCampus stores (or replaces) a campus (key: name). A default campus clears the
+// default flag of all other campuses.
+func (db *DB) SetCampus(ctx context.Context, campus *model.Campus) error {
+	collection := db.Client.Database("plexams").Collection(collectionCampuses)
+	if campus.IsDefault {
+		if _, err := collection.UpdateMany(ctx, bson.M{"name": bson.M{"$ne": campus.Name}},
+			bson.M{"$set": bson.M{"isdefault": false}}); err != nil {
+			log.Error().Err(err).Str("campus", campus.Name).Msg("cannot clear default campus")
+			return err
+		}
+	}
+	if _, err := collection.ReplaceOne(ctx, bson.M{"name": campus.Name}, campus, options.Replace().SetUpsert(true)); err != nil {
+		log.Error().Err(err).Str("campus", campus.Name).Msg("cannot set campus")
+		return err
+	}
+	return nil
+}
+
+// RemoveCampus deletes a campus; returns false when there was none.
+func (db *DB) RemoveCampus(ctx context.Context, name string) (bool, error) {
+	collection := db.Client.Database("plexams").Collection(collectionCampuses)
+	res, err := collection.DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		log.Error().Err(err).Str("campus", name).Msg("cannot remove campus")
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+// Buildings returns all buildings, sorted by name.
+func (db *DB) Buildings(ctx context.Context) ([]*model.Building, error) {
+	collection := db.Client.Database("plexams").Collection(collectionBuildings)
+	cur, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		log.Error().Err(err).Str("collection", collectionBuildings).Msg("MongoDB Find")
+		return nil, err
+	}
+	buildings := make([]*model.Building, 0)
+	if err := cur.All(ctx, &buildings); err != nil {
+		log.Error().Err(err).Str("collection", collectionBuildings).Msg("cannot decode buildings")
+		return nil, err
+	}
+	return buildings, nil
+}
+
+// SetBuilding stores (or replaces) a building (key: name).
+func (db *DB) SetBuilding(ctx context.Context, building *model.Building) error {
+	collection := db.Client.Database("plexams").Collection(collectionBuildings)
+	if _, err := collection.ReplaceOne(ctx, bson.M{"name": building.Name}, building, options.Replace().SetUpsert(true)); err != nil {
+		log.Error().Err(err).Str("building", building.Name).Msg("cannot set building")
+		return err
+	}
+	return nil
+}
+
+// RemoveBuilding deletes a building; returns false when there was none.
+func (db *DB) RemoveBuilding(ctx context.Context, name string) (bool, error) {
+	collection := db.Client.Database("plexams").Collection(collectionBuildings)
+	res, err := collection.DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		log.Error().Err(err).Str("building", name).Msg("cannot remove building")
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+// CampusTravelTimes returns the travel-time matrix, sorted by from and to.
+func (db *DB) CampusTravelTimes(ctx context.Context) ([]*model.CampusTravelTime, error) {
+	collection := db.Client.Database("plexams").Collection(collectionCampusTravelTimes)
+	cur, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "from", Value: 1}, {Key: "to", Value: 1}}))
+	if err != nil {
+		log.Error().Err(err).Str("collection", collectionCampusTravelTimes).Msg("MongoDB Find")
+		return nil, err
+	}
+	travelTimes := make([]*model.CampusTravelTime, 0)
+	if err := cur.All(ctx, &travelTimes); err != nil {
+		log.Error().Err(err).Str("collection", collectionCampusTravelTimes).Msg("cannot decode travel times")
+		return nil, err
+	}
+	return travelTimes, nil
+}
+
+// SetCampusTravelTime stores (or replaces) the travel time between two campuses. The
+// matrix is symmetric, so an entry stored in the other direction is replaced as well.
+func (db *DB) SetCampusTravelTime(ctx context.Context, travelTime *model.CampusTravelTime) error {
+	if _, err := db.RemoveCampusTravelTime(ctx, travelTime.From, travelTime.To); err != nil {
+		return err
+	}
+	collection := db.Client.Database("plexams").Collection(collectionCampusTravelTimes)
+	if _, err := collection.InsertOne(ctx, travelTime); err != nil {
+		log.Error().Err(err).Str("from", travelTime.From).Str("to", travelTime.To).Msg("cannot set travel time")
+		return err
+	}
+	return nil
+}
+
+// RemoveCampusTravelTime deletes the travel time between two campuses (either
+// direction); returns false when there was none.
+func (db *DB) RemoveCampusTravelTime(ctx context.Context, from, to string) (bool, error) {
+	collection := db.Client.Database("plexams").Collection(collectionCampusTravelTimes)
+	res, err := collection.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"from": from, "to": to},
+		bson.M{"from": to, "to": from},
+	}})
+	if err != nil {
+		log.Error().Err(err).Str("from", from).Str("to", to).Msg("cannot remove travel time")
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+// RoomLocations returns the building/floor overrides of rooms, sorted by room.
+func (db *DB) RoomLocations(ctx context.Context) ([]*model.RoomLocation, error) {
+	collection := db.Client.Database("plexams").Collection(collectionRoomLocations)
+	cur, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "room", Value: 1}}))
+	if err != nil {
+		log.Error().Err(err).Str("collection", collectionRoomLocations).Msg("MongoDB Find")
+		return nil, err
+	}
+	locations := make([]*model.RoomLocation, 0)
+	if err := cur.All(ctx, &locations); err != nil {
+		log.Error().Err(err).Str("collection", collectionRoomLocations).Msg("cannot decode room locations")
+		return nil, err
+	}
+	return locations, nil
+}
+
+// SetRoomLocation stores (or replaces) the location override of a room (key: room).
+func (db *DB) SetRoomLocation(ctx context.Context, location *model.RoomLocation) error {
+	collection := db.Client.Database("plexams").Collection(collectionRoomLocations)
+	if _, err := collection.ReplaceOne(ctx, bson.M{"room": location.Room}, location, options.Replace().SetUpsert(true)); err != nil {
+		log.Error().Err(err).Str("room", location.Room).Msg("cannot set room location")
+		return err
+	}
+	return nil
+}
+
+// RemoveRoomLocation deletes the location override of a room; returns false when there
+// was none.
+func (db *DB) RemoveRoomLocation(ctx context.Context, room string) (bool, error) {
+	collection := db.Client.Database("plexams").Collection(collectionRoomLocations)
+	res, err := collection.DeleteOne(ctx, bson.M{"room": room})
+	if err != nil {
+		log.Error().Err(err).Str("room", room).Msg("cannot remove room location")
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
@@ -48,6 +48,12 @@ const (
 	collectionSyncLog         = "sync_log"
 	collectionMutationLog     = "mutation_log"
 	collectionRoomsBlocked    = "rooms_blocked"
+	collectionRoomLayouts     = "room_layouts" // global (plexams DB)
+	// global (plexams DB), the campus model:
+	collectionCampuses          = "campuses"
+	collectionBuildings         = "buildings"
+	collectionCampusTravelTimes = "campus_travel_times"
+	collectionRoomLocations     = "room_locations"
 
 	collectionInvigilatorRequirements = "invigilator_requirements"
 	collectionInvigilatorConstraints  = "invigilator_constraints"
@@ -71,6 +77,19 @@ const (
 	collectionPlanningState = "planning_state"
 
 	collectionNtaRoomAloneWaivers = "nta_room_alone_waivers"
+
+	collectionSolverRuns = "solver_runs"
+
+	collectionRoomOutages             = "room_outages"
+	collectionRoomChangeNotifications = "room_change_notifications"
+
+	collectionInvigilationSwaps = "invigilation_swaps"
+
+	collectionExamDayEvents = "exam_day_events"
+	collectionExamProtocols = "exam_protocols"
+
+	collectionStudentAttendance = "student_attendance"
+	collectionStudentHistory    = "student_history" // global (plexams DB), pseudonymized
 )
 
 type PrimussType string
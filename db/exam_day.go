@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExamDayEvents returns the exam-day events of the exam times from..until (exclusive) in
+// the order they happened.
+func (db *DB) ExamDayEvents(ctx context.Context, from, until time.Time) ([]*model.ExamDayEvent, error) {
+	collection := db.getCollectionSemester(collectionExamDayEvents)
+	filter := bson.M{"starttime": bson.M{"$gte": from, "$lt": until}}
+	cur, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "at", Value: 1}, {Key: "createdat", Value: 1}}))
+	if err != nil {
+		log.Error().Err(err).Str("collection", collectionExamDayEvents).Msg("MongoDB Find")
+		return nil, err
+	}
+	events := make([]*model.ExamDayEvent, 0)
+	if err := cur.All(ctx, &events); err != nil {
+		log.Error().Err(err).Str("collection", collectionExamDayEvents).Msg("cannot decode exam-day events")
+		return nil, err
+	}
+	return events, nil
+}
+
+// AddExamDayEvent stores a recorded exam-day event.
+func (db *DB) AddExamDayEvent(ctx context.Context, event *model.ExamDayEvent) error {
+	collection := db.getCollectionSemester(collectionExamDayEvents)
+	if _, err := collection.InsertOne(ctx, event); err != nil {
+		log.Error().Err(err).Str("kind", string(event.Kind)).Msg("cannot add exam-day event")
+		return err
+	}
+	return nil
+}
+
+// RemoveExamDayEvent deletes an exam-day event and returns it; nil when there was none.
+func (db *DB) RemoveExamDayEvent(ctx context.Context, id string) (*model.ExamDayEvent, error) {
+	collection := db.getCollectionSemester(collectionExamDayEvents)
+	var event model.ExamDayEvent
+	err := collection.FindOneAndDelete(ctx, bson.M{"_id": id}).Decode(&event)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Str("id", id).Msg("cannot remove exam-day event")
+		return nil, err
+	}
+	return &event, nil
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExamProtocols returns the exam protocols of the semester, of one exam or all, sorted
+// by start time, ancode and room.
+func (db *DB) ExamProtocols(ctx context.Context, ancode *int) ([]*model.ExamProtocol, error) {
+	filter := bson.M{}
+	if ancode != nil {
+		filter["ancode"] = *ancode
+	}
+	return db.examProtocols(ctx, db.getCollectionSemester(collectionExamProtocols), filter)
+}
+
+// ExamProtocolsForDatabase returns all exam protocols of another semester's database.
+func (db *DB) ExamProtocolsForDatabase(ctx context.Context, database string) ([]*model.ExamProtocol, error) {
+	return db.examProtocols(ctx, db.Client.Database(database).Collection(collectionExamProtocols), bson.M{})
+}
+
+func (db *DB) examProtocols(ctx context.Context, collection *mongo.Collection, filter bson.M) ([]*model.ExamProtocol, error) {
+	cur, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{
+		{Key: "starttime", Value: 1}, {Key: "ancode", Value: 1}, {Key: "roomname", Value: 1},
+	}))
+	if err != nil {
+		log.Error().Err(err).Str("collection", collectionExamProtocols).Msg("MongoDB Find")
+		return nil, err
+	}
+	protocols := make([]*model.ExamProtocol, 0)
+	if err := cur.All(ctx, &protocols); err != nil {
+		log.Error().Err(err).Str("collection", collectionExamProtocols).Msg("cannot decode exam protocols")
+		return nil, err
+	}
+	return protocols, nil
+}
+
+// SaveExamProtocol upserts the protocol of an exam in a room (key: ancode, room and start
+// time); a replaced protocol keeps its id.
+func (db *DB) SaveExamProtocol(ctx context.Context, protocol *model.ExamProtocol) error {
+	collection := db.getCollectionSemester(collectionExamProtocols)
+	filter := bson.M{"ancode": protocol.Ancode, "roomname": protocol.RoomName, "starttime": protocol.Starttime}
+	var existing model.ExamProtocol
+	err := collection.FindOne(ctx, filter).Decode(&existing)
+	if err != nil && err != mongo.ErrNoDocuments {
+		log.Error().Err(err).Int("ancode", protocol.Ancode).Str("room", protocol.RoomName).Msg("cannot get exam protocol")
+		return err
+	}
+	if err == nil {
+		protocol.ID = existing.ID
+	}
+	if _, err := collection.ReplaceOne(ctx, filter, protocol, options.Replace().SetUpsert(true)); err != nil {
+		log.Error().Err(err).Int("ancode", protocol.Ancode).Str("room", protocol.RoomName).Msg("cannot save exam protocol")
+		return err
+	}
+	return nil
+}
+
+// RemoveExamProtocol deletes the protocols of an exam in a room; returns false when there
+// was none.
+func (db *DB) RemoveExamProtocol(ctx context.Context, ancode int, room string) (bool, error) {
+	collection := db.getCollectionSemester(collectionExamProtocols)
+	res, err := collection.DeleteMany(ctx, bson.M{"ancode": ancode, "roomname": room})
+	if err != nil {
+		log.Error().Err(err).Int("ancode", ancode).Str("room", room).Msg("cannot remove exam protocol")
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
@@ -10,20 +10,44 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// GetGenerationConfig returns the (single, global) generation config, or nil when
-// none is stored yet.
-func (db *DB) GetGenerationConfig(ctx context.Context) (*model.GenerationConfig, error) {
+// GetGenerationConfig returns the (single, global) generation config together with the
+// keys stored in its document, or nil when none is stored yet. The keys tell a field
+// added later (missing in an older config) apart from a stored 0.
+func (db *DB) GetGenerationConfig(ctx context.Context) (*model.GenerationConfig, map[string]bool, error) {
 	collection := db.Client.Database("plexams").Collection(collectionGenerationConfig)
-	var cfg model.GenerationConfig
-	err := collection.FindOne(ctx, bson.M{}).Decode(&cfg)
+	var raw bson.Raw
+	err := collection.FindOne(ctx, bson.M{}).Decode(&raw)
 	if err == mongo.ErrNoDocuments {
-		return nil, nil
+		return nil, nil, nil
 	}
 	if err != nil {
 		log.Error().Err(err).Msg("cannot get generation config")
-		return nil, err
+		return nil, nil, err
+	}
+	cfg, stored, err := DecodeGenerationConfig(raw)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot decode generation config")
+		return nil, nil, err
+	}
+	return cfg, stored, nil
+}
+
+// DecodeGenerationConfig decodes a stored generation config document and returns the
+// keys it has (lower-case field names, as the driver stores them).
+func DecodeGenerationConfig(raw bson.Raw) (*model.GenerationConfig, map[string]bool, error) {
+	var cfg model.GenerationConfig
+	if err := bson.Unmarshal(raw, &cfg); err != nil {
+		return nil, nil, err
+	}
+	elements, err := raw.Elements()
+	if err != nil {
+		return nil, nil, err
+	}
+	stored := make(map[string]bool, len(elements))
+	for _, e := range elements {
+		stored[e.Key()] = true
 	}
-	return &cfg, nil
+	return &cfg, stored, nil
 }
 
 // SetGenerationConfig upserts the (single, global) generation config.
@@ -66,21 +66,32 @@ func TestRoomStorageStarttime(t *testing.T) {
 		t.Errorf("PlannedRoomNamesAt(st) = %v (err %v), want [R1.234]", names, err)
 	}
 
-	// blocked room: keyed by room + starttime
+	// blocked room: keyed by room + starttime + until, a slot block and a time-range
+	// block at the same start are two blocks
 	if err := d.BlockRoomForSlot(ctx, &model.BlockedRoom{Starttime: &st, Room: "R1.234"}); err != nil {
 		t.Fatal(err)
 	}
+	until := st.Add(3 * time.Hour)
+	if err := d.BlockRoomForSlot(ctx, &model.BlockedRoom{Starttime: &st, Until: &until, Room: "R1.234"}); err != nil {
+		t.Fatal(err)
+	}
 	blocked, err := d.BlockedRooms(ctx)
-	if err != nil || len(blocked) != 1 {
-		t.Fatalf("BlockedRooms = %d (err %v), want 1", len(blocked), err)
+	if err != nil || len(blocked) != 2 {
+		t.Fatalf("BlockedRooms = %d (err %v), want 2", len(blocked), err)
 	}
 	if blocked[0].Starttime == nil || !blocked[0].Starttime.Equal(st) {
 		t.Errorf("blocked starttime not round-tripped, got %v", blocked[0].Starttime)
 	}
-	removed, err := d.UnblockRoomForSlot(ctx, "R1.234", st)
+	removed, err := d.UnblockRoomForSlot(ctx, "R1.234", st, nil)
 	if err != nil || !removed {
 		t.Errorf("UnblockRoomForSlot = %v (err %v), want true", removed, err)
 	}
+	if blocked, err = d.BlockedRooms(ctx); err != nil || len(blocked) != 1 || blocked[0].Until == nil {
+		t.Fatalf("after removing the slot block: %+v (err %v), want the time-range block", blocked, err)
+	}
+	if removed, err = d.UnblockRoomForSlot(ctx, "R1.234", st, &until); err != nil || !removed {
+		t.Errorf("UnblockRoomForSlot(until) = %v (err %v), want true", removed, err)
+	}
 }
 
 // TestInvigilationStarttimeStorage verifies that pre-planned invigilations persist
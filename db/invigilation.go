@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/obcode/plexams.go/graph/model"
@@ -12,6 +13,8 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// GetInvigilatorAt returns the (lead) invigilator of a room (or "reserve") at a start
+// time, i.e. the one at position 0; nil when none is assigned.
 func (db *DB) GetInvigilatorAt(ctx context.Context, roomname string, starttime time.Time) (*model.Teacher, error) {
 	invigilations, err := db.GetInvigilationsAt(ctx, roomname, starttime)
 	if err != nil {
@@ -19,7 +22,7 @@ func (db *DB) GetInvigilatorAt(ctx context.Context, roomname string, starttime t
 		return nil, err
 	}
 
-	if len(invigilations) > 1 {
+	if len(invigilations) > 1 && invigilations[0].Position == invigilations[1].Position {
 		log.Error().Str("room", roomname).Time("starttime", starttime).
 			Interface("invigilations", invigilations).
 			Msg("found more than one invigilation")
@@ -32,6 +35,27 @@ func (db *DB) GetInvigilatorAt(ctx context.Context, roomname string, starttime t
 	return db.GetTeacher(ctx, invigilations[0].InvigilatorID)
 }
 
+// GetInvigilatorsAt returns all invigilators of a room (or "reserve") at a start time,
+// ordered by position.
+func (db *DB) GetInvigilatorsAt(ctx context.Context, roomname string, starttime time.Time) ([]*model.Teacher, error) {
+	invigilations, err := db.GetInvigilationsAt(ctx, roomname, starttime)
+	if err != nil {
+		log.Error().Err(err).Str("room", roomname).Time("starttime", starttime).Msg("cannot get invigilations")
+		return nil, err
+	}
+	teachers := make([]*model.Teacher, 0, len(invigilations))
+	for _, inv := range invigilations {
+		teacher, err := db.GetTeacher(ctx, inv.InvigilatorID)
+		if err != nil {
+			return nil, err
+		}
+		teachers = append(teachers, teacher)
+	}
+	return teachers, nil
+}
+
+// GetInvigilationsAt returns the invigilations (self and other) of a room (or
+// "reserve") at a start time, ordered by position.
 func (db *DB) GetInvigilationsAt(ctx context.Context, roomname string, starttime time.Time) ([]*model.Invigilation, error) {
 	invigilations, err := db.getInvigilationsAt(ctx, collectionSelfInvigilations, roomname, starttime)
 	if err != nil {
@@ -45,7 +69,9 @@ func (db *DB) GetInvigilationsAt(ctx context.Context, roomname string, starttime
 		return nil, err
 	}
 
-	return append(invigilations, other...), nil
+	invigilations = append(invigilations, other...)
+	sort.SliceStable(invigilations, func(i, j int) bool { return invigilations[i].Position < invigilations[j].Position })
+	return invigilations, nil
 }
 
 func (db *DB) getInvigilationsAt(ctx context.Context, collectionName, roomname string, starttime time.Time) ([]*model.Invigilation, error) {
@@ -186,7 +212,9 @@ func (db *DB) GetOtherInvigilations(ctx context.Context) ([]*model.Invigilation,
 	return invigilations, nil
 }
 
-func (db *DB) AddInvigilationAt(ctx context.Context, room string, starttime time.Time, invigilatorID int) error {
+// AddInvigilationAt sets the invigilator of a room (or "reserve") at a start time.
+// position selects the seat of a room with several invigilators (0 = lead).
+func (db *DB) AddInvigilationAt(ctx context.Context, room string, starttime time.Time, position, invigilatorID int) error {
 	collection := db.getCollectionSemester(collectionOtherInvigilations)
 
 	var filter primitive.M
@@ -209,6 +237,7 @@ func (db *DB) AddInvigilationAt(ctx context.Context, room string, starttime time
 				{"roomname": room},
 				{"isreserve": false},
 				{"starttime": starttime},
+				{"position": positionValue(position)},
 			},
 		}
 		duration = db.getMaxDurationForRoomAt(ctx, room, starttime)
@@ -222,6 +251,7 @@ func (db *DB) AddInvigilationAt(ctx context.Context, room string, starttime time
 		model.Invigilation{
 			Starttime:          &starttime,
 			RoomName:           roomname,
+			Position:           position,
 			Duration:           duration,
 			InvigilatorID:      invigilatorID,
 			IsReserve:          isReserve,
@@ -238,8 +268,55 @@ func (db *DB) AddInvigilationAt(ctx context.Context, room string, starttime time
 	return nil
 }
 
-// SetInvigilationPrePlannedAt sets the prePlanned flag on the invigilation for a
-// room (roomName != nil) or the reserve (roomName == nil) at a start time in the
+// positionValue is the stored value of an invigilation position for a filter: position
+// 0 is not stored (omitempty), so it matches a missing field.
+func positionValue(position int) interface{} {
+	if position == 0 {
+		return nil
+	}
+	return position
+}
+
+// MoveInvigilationAt moves the invigilations (self and other) of a room at a start time
+// to another room, taking over that room's longest exam duration. Reports whether there
+// was an invigilation to move.
+func (db *DB) MoveInvigilationAt(ctx context.Context, starttime time.Time, from, to string) (bool, error) {
+	duration := db.getMaxDurationForRoomAt(ctx, to, starttime)
+	moved := false
+	for _, collectionName := range []string{collectionOtherInvigilations, collectionSelfInvigilations} {
+		collection := db.getCollectionSemester(collectionName)
+		res, err := collection.UpdateMany(ctx,
+			bson.M{"roomname": from, "isreserve": false, "starttime": starttime},
+			bson.M{"$set": bson.M{"roomname": to, "duration": duration}})
+		if err != nil {
+			log.Error().Err(err).Str("collection", collectionName).Str("from", from).Str("to", to).
+				Time("starttime", starttime).Msg("cannot move invigilation")
+			return false, err
+		}
+		moved = moved || res.ModifiedCount > 0
+	}
+	return moved, nil
+}
+
+// RemoveInvigilationAt deletes the invigilations (self and other) of a room at a start
+// time. Reports whether there was one.
+func (db *DB) RemoveInvigilationAt(ctx context.Context, starttime time.Time, room string) (bool, error) {
+	removed := false
+	for _, collectionName := range []string{collectionOtherInvigilations, collectionSelfInvigilations} {
+		collection := db.getCollectionSemester(collectionName)
+		res, err := collection.DeleteMany(ctx, bson.M{"roomname": room, "isreserve": false, "starttime": starttime})
+		if err != nil {
+			log.Error().Err(err).Str("collection", collectionName).Str("room", room).
+				Time("starttime", starttime).Msg("cannot remove invigilation")
+			return false, err
+		}
+		removed = removed || res.DeletedCount > 0
+	}
+	return removed, nil
+}
+
+// SetInvigilationPrePlannedAt sets the prePlanned flag on the (lead) invigilation for
+// a room (roomName != nil) or the reserve (roomName == nil) at a start time in the
 // invigilations_other collection.
 func (db *DB) SetInvigilationPrePlannedAt(ctx context.Context, starttime time.Time, roomName *string, prePlanned bool) error {
 	collection := db.getCollectionSemester(collectionOtherInvigilations)
@@ -247,6 +324,7 @@ func (db *DB) SetInvigilationPrePlannedAt(ctx context.Context, starttime time.Ti
 		"roomname":  roomName,
 		"isreserve": roomName == nil,
 		"starttime": starttime,
+		"position":  nil,
 	}
 	res, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"preplanned": prePlanned}})
 	if err != nil {
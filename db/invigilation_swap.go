@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InvigilationSwaps returns the invigilation swaps of the semester, newest first; openOnly
+// restricts them to the proposed and accepted ones.
+func (db *DB) InvigilationSwaps(ctx context.Context, openOnly bool) ([]*model.InvigilationSwap, error) {
+	collection := db.getCollectionSemester(collectionInvigilationSwaps)
+	filter := bson.M{}
+	if openOnly {
+		filter = bson.M{"status": bson.M{"$in": []model.InvigilationSwapStatus{
+			model.InvigilationSwapStatusProposed, model.InvigilationSwapStatusAccepted,
+		}}}
+	}
+	cur, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdat", Value: -1}}))
+	if err != nil {
+		log.Error().Err(err).Str("collection", collectionInvigilationSwaps).Msg("MongoDB Find")
+		return nil, err
+	}
+	swaps := make([]*model.InvigilationSwap, 0)
+	if err := cur.All(ctx, &swaps); err != nil {
+		log.Error().Err(err).Str("collection", collectionInvigilationSwaps).Msg("cannot decode invigilation swaps")
+		return nil, err
+	}
+	return swaps, nil
+}
+
+// InvigilationSwap returns one invigilation swap, or nil.
+func (db *DB) InvigilationSwap(ctx context.Context, id string) (*model.InvigilationSwap, error) {
+	collection := db.getCollectionSemester(collectionInvigilationSwaps)
+	var swap model.InvigilationSwap
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&swap)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Str("id", id).Msg("cannot get invigilation swap")
+		return nil, err
+	}
+	return &swap, nil
+}
+
+// SaveInvigilationSwap upserts an invigilation swap (key: id).
+func (db *DB) SaveInvigilationSwap(ctx context.Context, swap *model.InvigilationSwap) error {
+	collection := db.getCollectionSemester(collectionInvigilationSwaps)
+	if _, err := collection.ReplaceOne(ctx, bson.M{"_id": swap.ID}, swap, options.Replace().SetUpsert(true)); err != nil {
+		log.Error().Err(err).Str("id", swap.ID).Msg("cannot save invigilation swap")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RoomLayouts returns the seating layouts of all rooms, sorted by room. Layouts are
+// global (plexams DB) like the rooms themselves.
+func (db *DB) RoomLayouts(ctx context.Context) ([]*model.RoomLayout, error) {
+	collection := db.Client.Database("plexams").Collection(collectionRoomLayouts)
+	cur, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "room", Value: 1}}))
+	if err != nil {
+		log.Error().Err(err).Str("collection", collectionRoomLayouts).Msg("MongoDB Find")
+		return nil, err
+	}
+	layouts := make([]*model.RoomLayout, 0)
+	if err := cur.All(ctx, &layouts); err != nil {
+		log.Error().Err(err).Str("collection", collectionRoomLayouts).Msg("cannot decode room layouts")
+		return nil, err
+	}
+	return layouts, nil
+}
+
+// SetRoomLayout stores (or replaces) the layout of a room (key: room).
+func (db *DB) SetRoomLayout(ctx context.Context, layout *model.RoomLayout) error {
+	collection := db.Client.Database("plexams").Collection(collectionRoomLayouts)
+	if _, err := collection.ReplaceOne(ctx, bson.M{"room": layout.Room}, layout, options.Replace().SetUpsert(true)); err != nil {
+		log.Error().Err(err).Str("room", layout.Room).Msg("cannot set room layout")
+		return err
+	}
+	return nil
+}
+
+// RemoveRoomLayout deletes the layout of a room; returns false when there was none.
+func (db *DB) RemoveRoomLayout(ctx context.Context, room string) (bool, error) {
+	collection := db.Client.Database("plexams").Collection(collectionRoomLayouts)
+	res, err := collection.DeleteOne(ctx, bson.M{"room": room})
+	if err != nil {
+		log.Error().Err(err).Str("room", room).Msg("cannot remove room layout")
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RoomOutages returns the room outages of the semester (previews and confirmed), newest
+// first.
+func (db *DB) RoomOutages(ctx context.Context) ([]*model.RoomOutage, error) {
+	collection := db.getCollectionSemester(collectionRoomOutages)
+	cur, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "createdat", Value: -1}}))
+	if err != nil {
+		log.Error().Err(err).Str("collection", collectionRoomOutages).Msg("MongoDB Find")
+		return nil, err
+	}
+	outages := make([]*model.RoomOutage, 0)
+	if err := cur.All(ctx, &outages); err != nil {
+		log.Error().Err(err).Str("collection", collectionRoomOutages).Msg("cannot decode room outages")
+		return nil, err
+	}
+	return outages, nil
+}
+
+// RoomOutage returns one room outage, or nil.
+func (db *DB) RoomOutage(ctx context.Context, id string) (*model.RoomOutage, error) {
+	collection := db.getCollectionSemester(collectionRoomOutages)
+	var outage model.RoomOutage
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&outage)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Str("id", id).Msg("cannot get room outage")
+		return nil, err
+	}
+	return &outage, nil
+}
+
+// SaveRoomOutage upserts a room outage (key: id).
+func (db *DB) SaveRoomOutage(ctx context.Context, outage *model.RoomOutage) error {
+	collection := db.getCollectionSemester(collectionRoomOutages)
+	if _, err := collection.ReplaceOne(ctx, bson.M{"_id": outage.ID}, outage, options.Replace().SetUpsert(true)); err != nil {
+		log.Error().Err(err).Str("id", outage.ID).Msg("cannot save room outage")
+		return err
+	}
+	return nil
+}
+
+// RemoveRoomOutage deletes a room outage; returns false when there was none.
+func (db *DB) RemoveRoomOutage(ctx context.Context, id string) (bool, error) {
+	collection := db.getCollectionSemester(collectionRoomOutages)
+	res, err := collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		log.Error().Err(err).Str("id", id).Msg("cannot remove room outage")
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+// RoomChangeNotifications returns the queued room-change notifications, oldest first;
+// pendingOnly restricts them to the ones not sent yet.
+func (db *DB) RoomChangeNotifications(ctx context.Context, pendingOnly bool) ([]*model.RoomChangeNotification, error) {
+	collection := db.getCollectionSemester(collectionRoomChangeNotifications)
+	filter := bson.M{}
+	if pendingOnly {
+		filter = bson.M{"sentat": nil}
+	}
+	cur, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdat", Value: 1}}))
+	if err != nil {
+		log.Error().Err(err).Str("collection", collectionRoomChangeNotifications).Msg("MongoDB Find")
+		return nil, err
+	}
+	notifications := make([]*model.RoomChangeNotification, 0)
+	if err := cur.All(ctx, &notifications); err != nil {
+		log.Error().Err(err).Str("collection", collectionRoomChangeNotifications).Msg("cannot decode room change notifications")
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// AddRoomChangeNotifications queues notifications.
+func (db *DB) AddRoomChangeNotifications(ctx context.Context, notifications []*model.RoomChangeNotification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, 0, len(notifications))
+	for _, n := range notifications {
+		docs = append(docs, n)
+	}
+	collection := db.getCollectionSemester(collectionRoomChangeNotifications)
+	if _, err := collection.InsertMany(ctx, docs); err != nil {
+		log.Error().Err(err).Int("count", len(docs)).Msg("cannot queue room change notifications")
+		return err
+	}
+	return nil
+}
+
+// SetRoomChangeNotificationSent marks a notification as sent.
+func (db *DB) SetRoomChangeNotificationSent(ctx context.Context, id string, sentAt time.Time) error {
+	collection := db.getCollectionSemester(collectionRoomChangeNotifications)
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"sentat": sentAt}}); err != nil {
+		log.Error().Err(err).Str("id", id).Msg("cannot mark room change notification as sent")
+		return err
+	}
+	return nil
+}
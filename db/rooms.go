@@ -259,18 +259,28 @@ func (db *DB) PlannedRoomNamesAt(ctx context.Context, starttime time.Time) ([]st
 }
 
 func (db *DB) PlannedRooms(ctx context.Context) ([]*model.PlannedRoom, error) {
-	collection := db.getCollectionSemester(collectionRoomsPlanned)
+	return db.plannedRoomsFrom(ctx, db.databaseName)
+}
+
+// PlannedRoomsForDatabase returns the planned rooms stored in a specific database
+// (by exact name), e.g. for comparing with another semester.
+func (db *DB) PlannedRoomsForDatabase(ctx context.Context, database string) ([]*model.PlannedRoom, error) {
+	return db.plannedRoomsFrom(ctx, database)
+}
+
+func (db *DB) plannedRoomsFrom(ctx context.Context, database string) ([]*model.PlannedRoom, error) {
+	collection := db.Client.Database(database).Collection(collectionRoomsPlanned)
 
 	cur, err := collection.Find(ctx, bson.M{})
 	if err != nil {
-		log.Error().Err(err).Msg("cannot find planned rooms")
+		log.Error().Err(err).Str("database", database).Msg("cannot find planned rooms")
 		return nil, err
 	}
 
 	plannedRooms := make([]*model.PlannedRoom, 0)
 	err = cur.All(ctx, &plannedRooms)
 	if err != nil {
-		log.Error().Err(err).Msg("cannot decode planned rooms")
+		log.Error().Err(err).Str("database", database).Msg("cannot decode planned rooms")
 		return nil, err
 	}
 
@@ -27,11 +27,12 @@ func (db *DB) BlockedRooms(ctx context.Context) ([]*model.BlockedRoom, error) {
 	return blocked, nil
 }
 
-// BlockRoomForSlot stores (or updates) a room block (key: room + starttime). The
+// BlockRoomForSlot stores (or updates) a room block (key: room + starttime + until, so
+// a slot block and a time-range block starting at the same time are kept apart). The
 // block's Starttime must be set by the caller (from the slot's start time).
 func (db *DB) BlockRoomForSlot(ctx context.Context, block *model.BlockedRoom) error {
 	collection := db.getCollectionSemester(collectionRoomsBlocked)
-	filter := bson.M{"room": block.Room, "starttime": block.Starttime}
+	filter := bson.M{"room": block.Room, "starttime": block.Starttime, "until": block.Until}
 	if _, err := collection.ReplaceOne(ctx, filter, block, options.Replace().SetUpsert(true)); err != nil {
 		log.Error().Err(err).Str("room", block.Room).Msg("cannot block room for slot")
 		return err
@@ -39,11 +40,11 @@ func (db *DB) BlockRoomForSlot(ctx context.Context, block *model.BlockedRoom) er
 	return nil
 }
 
-// UnblockRoomForSlot removes a room block (key: room + starttime). It reports
-// whether a block was actually removed.
-func (db *DB) UnblockRoomForSlot(ctx context.Context, room string, starttime time.Time) (bool, error) {
+// UnblockRoomForSlot removes a room block (key: room + starttime + until; until nil is
+// the slot block). It reports whether a block was actually removed.
+func (db *DB) UnblockRoomForSlot(ctx context.Context, room string, starttime time.Time, until *time.Time) (bool, error) {
 	collection := db.getCollectionSemester(collectionRoomsBlocked)
-	res, err := collection.DeleteOne(ctx, bson.M{"room": room, "starttime": starttime})
+	res, err := collection.DeleteOne(ctx, bson.M{"room": room, "starttime": starttime, "until": until})
 	if err != nil {
 		log.Error().Err(err).Str("room", room).Msg("cannot unblock room for slot")
 		return false, err
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SolverRuns returns the checkpointed optimizer runs of the semester, newest first.
+func (db *DB) SolverRuns(ctx context.Context) ([]*model.SolverRun, error) {
+	collection := db.getCollectionSemester(collectionSolverRuns)
+	cur, err := collection.Find(ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "startedAt", Value: -1}}).SetProjection(bson.M{"assign": 0}))
+	if err != nil {
+		log.Error().Err(err).Msg("cannot get solver runs")
+		return nil, err
+	}
+	runs := make([]*model.SolverRun, 0)
+	if err := cur.All(ctx, &runs); err != nil {
+		log.Error().Err(err).Msg("cannot decode solver runs")
+		return nil, err
+	}
+	return runs, nil
+}
+
+// SolverRun returns one run including its checkpointed assignment, or nil.
+func (db *DB) SolverRun(ctx context.Context, id string) (*model.SolverRun, error) {
+	collection := db.getCollectionSemester(collectionSolverRuns)
+	var run model.SolverRun
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&run)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Str("id", id).Msg("cannot get solver run")
+		return nil, err
+	}
+	return &run, nil
+}
+
+// SaveSolverRun upserts a run (key: id); called for every checkpoint.
+func (db *DB) SaveSolverRun(ctx context.Context, run *model.SolverRun) error {
+	collection := db.getCollectionSemester(collectionSolverRuns)
+	_, err := collection.ReplaceOne(ctx, bson.M{"_id": run.ID}, run, options.Replace().SetUpsert(true))
+	if err != nil {
+		log.Error().Err(err).Str("id", run.ID).Msg("cannot save solver run")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// StudentAttendance returns the imported attendance of the semester.
+func (db *DB) StudentAttendance(ctx context.Context) ([]*model.StudentAttendance, error) {
+	return db.studentAttendanceFrom(ctx, db.databaseName)
+}
+
+// StudentAttendanceForDatabase returns the imported attendance of another semester's
+// database.
+func (db *DB) StudentAttendanceForDatabase(ctx context.Context, database string) ([]*model.StudentAttendance, error) {
+	return db.studentAttendanceFrom(ctx, database)
+}
+
+func (db *DB) studentAttendanceFrom(ctx context.Context, database string) ([]*model.StudentAttendance, error) {
+	collection := db.Client.Database(database).Collection(collectionStudentAttendance)
+	cur, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "ancode", Value: 1}, {Key: "mtknr", Value: 1}}))
+	if err != nil {
+		log.Error().Err(err).Str("database", database).Str("collection", collectionStudentAttendance).Msg("MongoDB Find")
+		return nil, err
+	}
+	attendance := make([]*model.StudentAttendance, 0)
+	if err := cur.All(ctx, &attendance); err != nil {
+		log.Error().Err(err).Str("database", database).Msg("cannot decode student attendance")
+		return nil, err
+	}
+	return attendance, nil
+}
+
+// SaveStudentAttendance replaces the attendance of the exams in the list (an exam
+// uploaded again gets exactly the new rows; other exams are kept).
+func (db *DB) SaveStudentAttendance(ctx context.Context, attendance []*model.StudentAttendance) error {
+	collection := db.getCollectionSemester(collectionStudentAttendance)
+	ancodes := make([]int, 0)
+	seen := make(map[int]bool)
+	docs := make([]interface{}, 0, len(attendance))
+	for _, a := range attendance {
+		if !seen[a.Ancode] {
+			seen[a.Ancode] = true
+			ancodes = append(ancodes, a.Ancode)
+		}
+		docs = append(docs, a)
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	if _, err := collection.DeleteMany(ctx, bson.M{"ancode": bson.M{"$in": ancodes}}); err != nil {
+		log.Error().Err(err).Ints("ancodes", ancodes).Msg("cannot remove student attendance")
+		return err
+	}
+	if _, err := collection.InsertMany(ctx, docs); err != nil {
+		log.Error().Err(err).Msg("cannot save student attendance")
+		return err
+	}
+	return nil
+}
+
+// StudentHistories returns the pseudonymized student histories. They live in the global
+// "plexams" database, since they span the semester workspaces.
+func (db *DB) StudentHistories(ctx context.Context) ([]*model.StudentHistory, error) {
+	collection := db.Client.Database("plexams").Collection(collectionStudentHistory)
+	cur, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		log.Error().Err(err).Str("collection", collectionStudentHistory).Msg("MongoDB Find")
+		return nil, err
+	}
+	histories := make([]*model.StudentHistory, 0)
+	if err := cur.All(ctx, &histories); err != nil {
+		log.Error().Err(err).Msg("cannot decode student histories")
+		return nil, err
+	}
+	return histories, nil
+}
+
+// ReplaceStudentHistories replaces all stored student histories.
+func (db *DB) ReplaceStudentHistories(ctx context.Context, histories []*model.StudentHistory) error {
+	collection := db.Client.Database("plexams").Collection(collectionStudentHistory)
+	if err := collection.Drop(ctx); err != nil {
+		log.Error().Err(err).Msg("cannot drop student histories")
+		return err
+	}
+	if len(histories) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, 0, len(histories))
+	for _, h := range histories {
+		docs = append(docs, h)
+	}
+	if _, err := collection.InsertMany(ctx, docs); err != nil {
+		log.Error().Err(err).Msg("cannot save student histories")
+		return err
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "pseudonym", Value: 1}}, Options: options.Index().SetUnique(true),
+	}); err != nil {
+		log.Error().Err(err).Msg("cannot create student history index")
+		return err
+	}
+	return nil
+}
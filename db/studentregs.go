@@ -18,14 +18,24 @@ func (db *DB) CountStudentRegsPlanned(ctx context.Context) (int64, error) {
 }
 
 func (db *DB) StudentRegsPerStudentPlanned(ctx context.Context) ([]*model.Student, error) {
-	collection := db.Client.Database(db.databaseName).Collection(collectionStudentRegsPerStudentPlanned)
+	return db.studentRegsPerStudentPlannedFrom(ctx, db.databaseName)
+}
+
+// StudentRegsPerStudentPlannedForDatabase returns the planned student registrations of
+// another semester's database.
+func (db *DB) StudentRegsPerStudentPlannedForDatabase(ctx context.Context, database string) ([]*model.Student, error) {
+	return db.studentRegsPerStudentPlannedFrom(ctx, database)
+}
+
+func (db *DB) studentRegsPerStudentPlannedFrom(ctx context.Context, database string) ([]*model.Student, error) {
+	collection := db.Client.Database(database).Collection(collectionStudentRegsPerStudentPlanned)
 
 	findOptions := options.Find()
 	findOptions.SetSort(bson.D{{Key: "name", Value: 1}})
 
 	cur, err := collection.Find(ctx, bson.M{}, findOptions)
 	if err != nil {
-		log.Error().Err(err).Str("semester", db.semester).Msg("MongoDB Find")
+		log.Error().Err(err).Str("database", database).Msg("MongoDB Find")
 		return nil, err
 	}
 	defer cur.Close(ctx) //nolint:errcheck
@@ -34,7 +44,7 @@ func (db *DB) StudentRegsPerStudentPlanned(ctx context.Context) ([]*model.Studen
 
 	err = cur.All(ctx, &studentRegs)
 	if err != nil {
-		log.Error().Err(err).Str("semester", db.semester).Interface("cur", cur).
+		log.Error().Err(err).Str("database", database).Interface("cur", cur).
 			Msg("Cannot decode to studentRegs")
 		return nil, err
 	}
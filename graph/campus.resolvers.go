@@ -0,0 +1,66 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// SetCampus is the resolver for the setCampus field.
+func (r *mutationResolver) SetCampus(ctx context.Context, name string, isDefault bool) (*model.Campus, error) {
+	return r.plexams.SetCampus(ctx, name, isDefault)
+}
+
+// RemoveCampus is the resolver for the removeCampus field.
+func (r *mutationResolver) RemoveCampus(ctx context.Context, name string) (bool, error) {
+	return r.plexams.RemoveCampus(ctx, name)
+}
+
+// SetBuilding is the resolver for the setBuilding field.
+func (r *mutationResolver) SetBuilding(ctx context.Context, name string, campus string) (*model.Building, error) {
+	return r.plexams.SetBuilding(ctx, name, campus)
+}
+
+// RemoveBuilding is the resolver for the removeBuilding field.
+func (r *mutationResolver) RemoveBuilding(ctx context.Context, name string) (bool, error) {
+	return r.plexams.RemoveBuilding(ctx, name)
+}
+
+// SetCampusTravelTime is the resolver for the setCampusTravelTime field.
+func (r *mutationResolver) SetCampusTravelTime(ctx context.Context, from string, to string, minutes int) (*model.CampusTravelTime, error) {
+	return r.plexams.SetCampusTravelTime(ctx, from, to, minutes)
+}
+
+// RemoveCampusTravelTime is the resolver for the removeCampusTravelTime field.
+func (r *mutationResolver) RemoveCampusTravelTime(ctx context.Context, from string, to string) (bool, error) {
+	return r.plexams.RemoveCampusTravelTime(ctx, from, to)
+}
+
+// SetRoomLocation is the resolver for the setRoomLocation field.
+func (r *mutationResolver) SetRoomLocation(ctx context.Context, room string, building string, floor int) (*model.RoomLocation, error) {
+	return r.plexams.SetRoomLocation(ctx, room, building, floor)
+}
+
+// RemoveRoomLocation is the resolver for the removeRoomLocation field.
+func (r *mutationResolver) RemoveRoomLocation(ctx context.Context, room string) (bool, error) {
+	return r.plexams.RemoveRoomLocation(ctx, room)
+}
+
+// CampusModel is the resolver for the campusModel field.
+func (r *queryResolver) CampusModel(ctx context.Context) (*model.CampusModel, error) {
+	return r.plexams.CampusModel(ctx)
+}
+
+// RoomSites is the resolver for the roomSites field.
+func (r *queryResolver) RoomSites(ctx context.Context) ([]*model.RoomSite, error) {
+	return r.plexams.RoomSites(ctx)
+}
+
+// CampusTravelMinutes is the resolver for the campusTravelMinutes field.
+func (r *queryResolver) CampusTravelMinutes(ctx context.Context, from string, to string) (int, error) {
+	return r.plexams.CampusTravelMinutes(ctx, from, to)
+}
@@ -83,16 +83,16 @@ func (r *subscriptionResolver) SendEmailPublishedInvigilations(ctx context.Conte
 }
 
 // SendEmailCoverPages is the resolver for the sendEmailCoverPages field.
-func (r *subscriptionResolver) SendEmailCoverPages(ctx context.Context, run bool) (<-chan *model.LogLine, error) {
+func (r *subscriptionResolver) SendEmailCoverPages(ctx context.Context, run bool, withAttendanceLists *bool) (<-chan *model.LogLine, error) {
 	return r.runEmailOp(ctx, run, func(ctx context.Context, reporter plexams.Reporter) error {
-		return r.plexams.SendCoverPagesMails(ctx, run, reporter)
+		return r.plexams.SendCoverPagesMails(ctx, run, withAttendanceLists != nil && *withAttendanceLists, reporter)
 	}), nil
 }
 
 // SendEmailCoverPage is the resolver for the sendEmailCoverPage field.
-func (r *subscriptionResolver) SendEmailCoverPage(ctx context.Context, teacherID int, run bool) (<-chan *model.LogLine, error) {
+func (r *subscriptionResolver) SendEmailCoverPage(ctx context.Context, teacherID int, run bool, withAttendanceLists *bool) (<-chan *model.LogLine, error) {
 	return r.runEmailOp(ctx, run, func(ctx context.Context, reporter plexams.Reporter) error {
-		return r.plexams.SendCoverPageMail(ctx, teacherID, run, reporter)
+		return r.plexams.SendCoverPageMail(ctx, teacherID, run, withAttendanceLists != nil && *withAttendanceLists, reporter)
 	}), nil
 }
 
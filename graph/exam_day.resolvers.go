@@ -0,0 +1,62 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// CheckInInvigilator is the resolver for the checkInInvigilator field.
+func (r *mutationResolver) CheckInInvigilator(ctx context.Context, starttime time.Time, roomName *string, invigilatorID int, at *time.Time) (*model.ExamDayEvent, error) {
+	return r.plexams.CheckInInvigilator(ctx, starttime, roomName, invigilatorID, at)
+}
+
+// DispatchReserve is the resolver for the dispatchReserve field.
+func (r *mutationResolver) DispatchReserve(ctx context.Context, starttime time.Time, invigilatorID int, roomName string, note *string) (*model.ExamDayEvent, error) {
+	return r.plexams.DispatchReserve(ctx, starttime, invigilatorID, roomName, note)
+}
+
+// RecordExamStart is the resolver for the recordExamStart field.
+func (r *mutationResolver) RecordExamStart(ctx context.Context, starttime time.Time, roomName string, at *time.Time) (*model.ExamDayEvent, error) {
+	return r.plexams.RecordExamStart(ctx, starttime, roomName, at)
+}
+
+// RecordExamEnd is the resolver for the recordExamEnd field.
+func (r *mutationResolver) RecordExamEnd(ctx context.Context, starttime time.Time, roomName string, at *time.Time) (*model.ExamDayEvent, error) {
+	return r.plexams.RecordExamEnd(ctx, starttime, roomName, at)
+}
+
+// RecordAbsentStudents is the resolver for the recordAbsentStudents field.
+func (r *mutationResolver) RecordAbsentStudents(ctx context.Context, starttime time.Time, roomName string, ancode int, count int) (*model.ExamDayEvent, error) {
+	return r.plexams.RecordAbsentStudents(ctx, starttime, roomName, ancode, count)
+}
+
+// RecordExamDayIncident is the resolver for the recordExamDayIncident field.
+func (r *mutationResolver) RecordExamDayIncident(ctx context.Context, starttime time.Time, roomName *string, ancode *int, note string, at *time.Time) (*model.ExamDayEvent, error) {
+	return r.plexams.RecordExamDayIncident(ctx, starttime, roomName, ancode, note, at)
+}
+
+// RemoveExamDayEvent is the resolver for the removeExamDayEvent field.
+func (r *mutationResolver) RemoveExamDayEvent(ctx context.Context, id string) (bool, error) {
+	return r.plexams.RemoveExamDayEvent(ctx, id)
+}
+
+// ExamDayDashboard is the resolver for the examDayDashboard field.
+func (r *queryResolver) ExamDayDashboard(ctx context.Context, date time.Time) (*model.ExamDayDashboard, error) {
+	return r.plexams.ExamDayDashboard(ctx, date)
+}
+
+// ExamDayEvents is the resolver for the examDayEvents field.
+func (r *queryResolver) ExamDayEvents(ctx context.Context, date time.Time) ([]*model.ExamDayEvent, error) {
+	return r.plexams.ExamDayEvents(ctx, date)
+}
+
+// ExamDayDashboardUpdates is the resolver for the examDayDashboardUpdates field.
+func (r *subscriptionResolver) ExamDayDashboardUpdates(ctx context.Context, date time.Time) (<-chan *model.ExamDayDashboard, error) {
+	return r.plexams.WatchExamDayDashboard(ctx, date)
+}
@@ -0,0 +1,16 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// ExamPlacementExplanation is the resolver for the examPlacementExplanation field.
+func (r *queryResolver) ExamPlacementExplanation(ctx context.Context, ancode int) (*model.ExamPlacementExplanation, error) {
+	return r.plexams.ExamPlacementExplanation(ctx, ancode)
+}
@@ -0,0 +1,37 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// SetExamProtocol is the resolver for the setExamProtocol field.
+func (r *mutationResolver) SetExamProtocol(ctx context.Context, input model.ExamProtocolInput) (*model.ExamProtocol, error) {
+	return r.plexams.SetExamProtocol(ctx, input)
+}
+
+// RemoveExamProtocol is the resolver for the removeExamProtocol field.
+func (r *mutationResolver) RemoveExamProtocol(ctx context.Context, ancode int, roomName string) (bool, error) {
+	return r.plexams.RemoveExamProtocol(ctx, ancode, roomName)
+}
+
+// TakeOverExamDayProtocols is the resolver for the takeOverExamDayProtocols field.
+func (r *mutationResolver) TakeOverExamDayProtocols(ctx context.Context, date time.Time) ([]*model.ExamProtocol, error) {
+	return r.plexams.TakeOverExamDayProtocols(ctx, date)
+}
+
+// ExamProtocols is the resolver for the examProtocols field.
+func (r *queryResolver) ExamProtocols(ctx context.Context, ancode *int) ([]*model.ExamProtocol, error) {
+	return r.plexams.ExamProtocols(ctx, ancode)
+}
+
+// NoShowStatistics is the resolver for the noShowStatistics field.
+func (r *queryResolver) NoShowStatistics(ctx context.Context, workspace *string) (*model.NoShowStatistics, error) {
+	return r.plexams.NoShowStatistics(ctx, workspace)
+}
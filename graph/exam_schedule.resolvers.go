@@ -31,7 +31,10 @@ func (r *mutationResolver) ResetExamSchedule(ctx context.Context) (int, error) {
 
 // ExamScheduleConstraints is the resolver for the examScheduleConstraints field.
 func (r *queryResolver) ExamScheduleConstraints(ctx context.Context) ([]*model.OptimizerConstraint, error) {
-	infos := r.plexams.ExamScheduleConstraints()
+	infos, err := r.plexams.ExamScheduleConstraints(ctx)
+	if err != nil {
+		return nil, err
+	}
 	out := make([]*model.OptimizerConstraint, 0, len(infos))
 	for _, i := range infos {
 		out = append(out, &model.OptimizerConstraint{
@@ -52,7 +55,7 @@ func (r *queryResolver) ExamRoomsPhaseState(ctx context.Context) (*model.ExamRoo
 // line. The operation runs on a background context so a started (non-dry-run) run
 // finishes and writes even if the client disconnects; the subscription context only
 // governs the streaming.
-func (r *subscriptionResolver) GenerateExamSchedule(ctx context.Context, dryRun bool, seed *int, iterations *int, ignoreRatings *bool, keepAssigned *bool) (<-chan *model.LogLine, error) {
+func (r *subscriptionResolver) GenerateExamSchedule(ctx context.Context, dryRun bool, seed *int, iterations *int, ignoreRatings *bool, keepAssigned *bool, useForecast *bool) (<-chan *model.LogLine, error) {
 	ch := make(chan *model.LogLine, 256)
 
 	var seedVal int64
@@ -65,11 +68,12 @@ func (r *subscriptionResolver) GenerateExamSchedule(ctx context.Context, dryRun
 	}
 	ignore := ignoreRatings != nil && *ignoreRatings
 	keep := keepAssigned != nil && *keepAssigned
+	forecast := useForecast != nil && *useForecast
 	reporter := newStreamReporter(ctx, ch)
 
 	go func() {
 		defer close(ch)
-		result, err := r.plexams.GenerateExamSchedule(context.Background(), dryRun, seedVal, iterVal, ignore, keep, reporter)
+		result, err := r.plexams.GenerateExamSchedule(context.Background(), dryRun, seedVal, iterVal, ignore, keep, forecast, reporter)
 		if err != nil {
 			log.Error().Err(err).Msg("generate exam schedule failed")
 			reporter.emit(model.LogLevelError, "error: "+err.Error())
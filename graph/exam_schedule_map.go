@@ -5,6 +5,7 @@ import (
 
 	"github.com/obcode/plexams.go/graph/model"
 	"github.com/obcode/plexams.go/plexams"
+	"github.com/obcode/plexams.go/plexams/examplan"
 )
 
 // examScheduleReport maps the plexams result to the GraphQL ExamScheduleReport.
@@ -45,6 +46,7 @@ func examScheduleReport(r *plexams.ExamScheduleResult) *model.ExamScheduleReport
 			StarttimesUsed:       d.SlotsUsed,
 			SlotsOverThreshold:   d.SlotsOverThreshold,
 			MaxExamsAt:           d.MaxExamsPerSlot,
+			ByProgram:            examSchedulePrograms(d.Programs),
 		},
 		Conflicts:         r.Conflicts,
 		ResolvedConflicts: r.ResolvedConflicts,
@@ -61,3 +63,20 @@ func unplacedReasons(rs []*plexams.UnplacedExamReason) []*model.UnplacedExamReas
 	}
 	return out
 }
+
+// examSchedulePrograms maps the per-program diagnostics to the GraphQL type.
+func examSchedulePrograms(ps []examplan.ProgramDiagnostics) []*model.ExamScheduleProgram {
+	out := make([]*model.ExamScheduleProgram, 0, len(ps))
+	for _, p := range ps {
+		out = append(out, &model.ExamScheduleProgram{
+			Program:              p.Program,
+			Students:             p.Students,
+			MeanCost:             p.MeanPenalty,
+			P90Cost:              p.P90Penalty,
+			StudentsWithTooClose: p.StudentsWithAdjacent,
+			StudentsWithSameDay:  p.StudentsWithSameDay,
+			InEquity:             p.InEquity,
+		})
+	}
+	return out
+}
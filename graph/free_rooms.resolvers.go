@@ -0,0 +1,22 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// ReserveFreeRoom is the resolver for the reserveFreeRoom field.
+func (r *mutationResolver) ReserveFreeRoom(ctx context.Context, room string, from time.Time, until time.Time, reason *string) (*model.FreeRoomReservation, error) {
+	return r.plexams.ReserveFreeRoom(ctx, room, from, until, reason)
+}
+
+// FreeRooms is the resolver for the freeRooms field.
+func (r *queryResolver) FreeRooms(ctx context.Context, from time.Time, until time.Time, seats int, tags []string) ([]*model.FreeRoom, error) {
+	return r.plexams.FreeRooms(ctx, from, until, seats, tags)
+}
@@ -12,6 +12,26 @@ import (
 
 // SetGenerationConfig is the resolver for the setGenerationConfig field.
 func (r *mutationResolver) SetGenerationConfig(ctx context.Context, input model.GenerationConfigInput) (*model.GenerationConfig, error) {
+	softRules, err := r.softRulesFromInput(ctx, input.SoftRules)
+	if err != nil {
+		return nil, err
+	}
+	staffingRules, err := r.staffingRulesFromInput(ctx, input.StaffingRules)
+	if err != nil {
+		return nil, err
+	}
+	swapAutoApprove, swapMaxMinutesDelta, err := r.swapSettingsFromInput(ctx, input.SwapAutoApprove, input.SwapMaxMinutesDelta)
+	if err != nil {
+		return nil, err
+	}
+	roomSizing, roomSizingMargin, roomSizingSemesters, err := r.roomSizingFromInput(ctx, input.RoomSizing, input.RoomSizingMargin, input.RoomSizingSemesters)
+	if err != nil {
+		return nil, err
+	}
+	examCurriculum, err := r.examCurriculumFromInput(ctx, input.ExamCurriculum)
+	if err != nil {
+		return nil, err
+	}
 	return r.plexams.SetGenerationConfig(ctx, &model.GenerationConfig{
 		Iterations:              input.Iterations,
 		StartTemp:               input.StartTemp,
@@ -45,7 +65,16 @@ func (r *mutationResolver) SetGenerationConfig(ctx context.Context, input model.
 		ExamTbauFill:            input.ExamTbauFill,
 		ExamHole:                input.ExamHole,
 		ExamClosenessFalloffMin: input.ExamClosenessFalloffMin,
+		ExamEquity:              input.ExamEquity,
+		ExamCurriculum:          examCurriculum,
 		PreplanCapacityFactor:   input.PreplanCapacityFactor,
+		SoftRules:               softRules,
+		StaffingRules:           staffingRules,
+		SwapAutoApprove:         swapAutoApprove,
+		SwapMaxMinutesDelta:     swapMaxMinutesDelta,
+		RoomSizing:              roomSizing,
+		RoomSizingMargin:        roomSizingMargin,
+		RoomSizingSemesters:     roomSizingSemesters,
 	})
 }
 
@@ -53,3 +82,12 @@ func (r *mutationResolver) SetGenerationConfig(ctx context.Context, input model.
 func (r *queryResolver) GenerationConfig(ctx context.Context) (*model.GenerationConfig, error) {
 	return r.plexams.GenerationConfig(ctx)
 }
+
+// SoftRuleAttributes is the resolver for the softRuleAttributes field.
+func (r *queryResolver) SoftRuleAttributes(ctx context.Context, target *model.SoftRuleTarget) ([]*model.SoftRuleAttribute, error) {
+	t := model.SoftRuleTargetExams
+	if target != nil {
+		t = *target
+	}
+	return r.plexams.SoftRuleAttributes(t), nil
+}
@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// softRulesFromInput maps the soft rules of a GenerationConfigInput. A nil list (a client
+// that does not know about soft rules yet) keeps the stored rules instead of wiping them;
+// a rule without a target is an exam-schedule rule.
+func (r *mutationResolver) softRulesFromInput(ctx context.Context, input []*model.SoftRuleInput) ([]*model.SoftRule, error) {
+	if input == nil {
+		cfg, err := r.plexams.GenerationConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return cfg.SoftRules, nil
+	}
+	rules := make([]*model.SoftRule, 0, len(input))
+	for _, in := range input {
+		target := model.SoftRuleTargetExams
+		if in.Target != nil {
+			target = *in.Target
+		}
+		rules = append(rules, &model.SoftRule{
+			Name:        in.Name,
+			Description: in.Description,
+			Target:      target,
+			Condition:   in.Condition,
+			Weight:      in.Weight,
+			PerSeat:     in.PerSeat,
+			Enabled:     in.Enabled,
+		})
+	}
+	return rules, nil
+}
+
+// staffingRulesFromInput maps the staffing rules of the input; nil keeps the stored rules
+// (older clients that do not know them).
+func (r *mutationResolver) staffingRulesFromInput(ctx context.Context, input []*model.StaffingRuleInput) ([]*model.StaffingRule, error) {
+	if input == nil {
+		cfg, err := r.plexams.GenerationConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return cfg.StaffingRules, nil
+	}
+	rules := make([]*model.StaffingRule, 0, len(input))
+	for _, in := range input {
+		rules = append(rules, &model.StaffingRule{
+			Name:         in.Name,
+			MinSeats:     in.MinSeats,
+			MinStudents:  in.MinStudents,
+			ExamType:     in.ExamType,
+			MinNtas:      in.MinNtas,
+			Invigilators: in.Invigilators,
+			Enabled:      in.Enabled,
+		})
+	}
+	return rules, nil
+}
+
+// swapSettingsFromInput maps the swap thresholds of the input; nil keeps the stored value
+// (older clients that do not know them).
+func (r *mutationResolver) swapSettingsFromInput(ctx context.Context, autoApprove *bool, maxMinutesDelta *int) (bool, int, error) {
+	if autoApprove != nil && maxMinutesDelta != nil {
+		return *autoApprove, *maxMinutesDelta, nil
+	}
+	cfg, err := r.plexams.GenerationConfig(ctx)
+	if err != nil {
+		return false, 0, err
+	}
+	if autoApprove == nil {
+		autoApprove = &cfg.SwapAutoApprove
+	}
+	if maxMinutesDelta == nil {
+		maxMinutesDelta = &cfg.SwapMaxMinutesDelta
+	}
+	return *autoApprove, *maxMinutesDelta, nil
+}
+
+// roomSizingFromInput maps the room sizing of the input; nil keeps the stored value (older
+// clients that do not know it).
+func (r *mutationResolver) roomSizingFromInput(ctx context.Context, mode *model.RoomSizingMode, margin, semesters *int) (model.RoomSizingMode, int, int, error) {
+	if mode != nil && margin != nil && semesters != nil {
+		return *mode, *margin, *semesters, nil
+	}
+	cfg, err := r.plexams.GenerationConfig(ctx)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if mode == nil {
+		mode = &cfg.RoomSizing
+	}
+	if margin == nil {
+		margin = &cfg.RoomSizingMargin
+	}
+	if semesters == nil {
+		semesters = &cfg.RoomSizingSemesters
+	}
+	return *mode, *margin, *semesters, nil
+}
+
+// examCurriculumFromInput maps the curriculum weight of the input; nil keeps the stored
+// value (older clients that do not know it).
+func (r *mutationResolver) examCurriculumFromInput(ctx context.Context, weight *float64) (float64, error) {
+	if weight != nil {
+		return *weight, nil
+	}
+	cfg, err := r.plexams.GenerationConfig(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return cfg.ExamCurriculum, nil
+}
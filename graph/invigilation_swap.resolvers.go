@@ -0,0 +1,44 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams"
+)
+
+// ProposeInvigilationSwap is the resolver for the proposeInvigilationSwap field.
+func (r *mutationResolver) ProposeInvigilationSwap(ctx context.Context, proposerID int, colleagueID int, give model.InvigilationSwapPositionInput, take *model.InvigilationSwapPositionInput, note *string) (*model.InvigilationSwap, error) {
+	return r.plexams.ProposeInvigilationSwap(ctx, proposerID, colleagueID, give, take, note)
+}
+
+// AcceptInvigilationSwap is the resolver for the acceptInvigilationSwap field.
+func (r *mutationResolver) AcceptInvigilationSwap(ctx context.Context, id string) (*model.InvigilationSwap, error) {
+	return r.plexams.AcceptInvigilationSwap(ctx, id)
+}
+
+// ApproveInvigilationSwap is the resolver for the approveInvigilationSwap field.
+func (r *mutationResolver) ApproveInvigilationSwap(ctx context.Context, id string) (*model.InvigilationSwap, error) {
+	return r.plexams.ApproveInvigilationSwap(ctx, id)
+}
+
+// RejectInvigilationSwap is the resolver for the rejectInvigilationSwap field.
+func (r *mutationResolver) RejectInvigilationSwap(ctx context.Context, id string, reason *string) (*model.InvigilationSwap, error) {
+	return r.plexams.RejectInvigilationSwap(ctx, id, reason)
+}
+
+// InvigilationSwaps is the resolver for the invigilationSwaps field.
+func (r *queryResolver) InvigilationSwaps(ctx context.Context, openOnly *bool) ([]*model.InvigilationSwap, error) {
+	return r.plexams.InvigilationSwaps(ctx, openOnly != nil && *openOnly)
+}
+
+// SendEmailInvigilationSwaps is the resolver for the sendEmailInvigilationSwaps field.
+func (r *subscriptionResolver) SendEmailInvigilationSwaps(ctx context.Context, run bool) (<-chan *model.LogLine, error) {
+	return r.runEmailOp(ctx, run, func(ctx context.Context, reporter plexams.Reporter) error {
+		return r.plexams.SendEmailInvigilationSwaps(ctx, run, reporter)
+	}), nil
+}
@@ -3,9 +3,12 @@ package model
 import "time"
 
 // BlockedRoom marks a room as not usable at one exam time (e.g. otherwise occupied).
-// The absolute Starttime is the persisted source of truth.
+// The absolute Starttime is the persisted source of truth. With Until set the block
+// covers the time range [Starttime, Until) instead of the slot at Starttime (an ad-hoc
+// reservation, see reserveFreeRoom).
 type BlockedRoom struct {
 	Starttime *time.Time `json:"starttime,omitempty" bson:"starttime,omitempty"`
 	Room      string     `json:"room" bson:"room"`
 	Reason    *string    `json:"reason,omitempty" bson:"reason,omitempty"`
+	Until     *time.Time `json:"until,omitempty" bson:"until,omitempty"`
 }
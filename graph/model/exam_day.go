@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// ExamDayEvent is one fact recorded on an exam day (check-in, dispatch, start, end,
+// absent students, incident). The exam-day dashboard is derived from the plan of the day
+// plus these events; the last event of a kind for the same room (and exam or
+// invigilator) wins.
+type ExamDayEvent struct {
+	ID              string           `json:"id" bson:"_id"`
+	Kind            ExamDayEventKind `json:"kind" bson:"kind"`
+	Starttime       time.Time        `json:"starttime" bson:"starttime"`
+	RoomName        *string          `json:"roomName,omitempty" bson:"roomname,omitempty"`
+	InvigilatorID   *int             `json:"invigilatorID,omitempty" bson:"invigilatorid,omitempty"`
+	InvigilatorName *string          `json:"invigilatorName,omitempty" bson:"invigilatorname,omitempty"`
+	Ancode          *int             `json:"ancode,omitempty" bson:"ancode,omitempty"`
+	Count           *int             `json:"count,omitempty" bson:"count,omitempty"`
+	At              time.Time        `json:"at" bson:"at"`
+	Note            *string          `json:"note,omitempty" bson:"note,omitempty"`
+	RecordedBy      *string          `json:"recordedBy,omitempty" bson:"recordedby,omitempty"`
+	CreatedAt       time.Time        `json:"createdAt" bson:"createdat"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// ExamProtocol is the protocol of one exam in one room as it came back after the exam.
+// Module, main examer and the registered students are kept with it, so the no-show
+// statistics of a past semester do not depend on its plan any more.
+type ExamProtocol struct {
+	ID         string             `json:"id" bson:"_id"`
+	Ancode     int                `json:"ancode" bson:"ancode"`
+	Module     string             `json:"module" bson:"module"`
+	MainExamer string             `json:"mainExamer" bson:"mainexamer"`
+	RoomName   string             `json:"roomName" bson:"roomname"`
+	Starttime  time.Time          `json:"starttime" bson:"starttime"`
+	Registered int                `json:"registered" bson:"registered"`
+	Present    int                `json:"present" bson:"present"`
+	Absent     int                `json:"absent" bson:"absent"`
+	Incidents  []string           `json:"incidents" bson:"incidents"`
+	ActualEnd  *time.Time         `json:"actualEnd,omitempty" bson:"actualend,omitempty"`
+	Note       *string            `json:"note,omitempty" bson:"note,omitempty"`
+	Source     ExamProtocolSource `json:"source" bson:"source"`
+	RecordedBy *string            `json:"recordedBy,omitempty" bson:"recordedby,omitempty"`
+	UpdatedAt  time.Time          `json:"updatedAt" bson:"updatedat"`
+}
@@ -9,13 +9,16 @@ type Invigilation struct {
 	// Starttime is the absolute start time of the invigilation's slot.
 	Starttime *time.Time `json:"-" bson:"starttime,omitempty"`
 	// Slot is derived from Starttime on read (day/slot + start time); not persisted.
-	Slot               *Slot   `json:"slot" bson:"-"`
-	RoomName           *string `json:"roomName,omitempty" bson:"roomname,omitempty"`
-	Duration           int     `json:"duration" bson:"duration"`
-	InvigilatorID      int     `json:"invigilatorID" bson:"invigilatorid"`
-	IsReserve          bool    `json:"isReserve" bson:"isreserve"`
-	IsSelfInvigilation bool    `json:"isSelfInvigilation" bson:"isselfinvigilation"`
-	PrePlanned         bool    `json:"prePlanned" bson:"preplanned"`
+	Slot     *Slot   `json:"slot" bson:"-"`
+	RoomName *string `json:"roomName,omitempty" bson:"roomname,omitempty"`
+	// Position orders several invigilators of one room (staffing rules): 0 is the
+	// lead invigilator, 1.. the additional ones. Not stored for 0.
+	Position           int  `json:"position" bson:"position,omitempty"`
+	Duration           int  `json:"duration" bson:"duration"`
+	InvigilatorID      int  `json:"invigilatorID" bson:"invigilatorid"`
+	IsReserve          bool `json:"isReserve" bson:"isreserve"`
+	IsSelfInvigilation bool `json:"isSelfInvigilation" bson:"isselfinvigilation"`
+	PrePlanned         bool `json:"prePlanned" bson:"preplanned"`
 }
 
 // PrePlannedInvigilation fixes an invigilator for a room (or the reserve) at one exam
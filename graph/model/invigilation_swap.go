@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// InvigilationSwap is a hand-over or swap of invigilations between two colleagues, from
+// the proposal to the approval (or rejection).
+type InvigilationSwap struct {
+	ID                    string                    `json:"id" bson:"_id"`
+	Kind                  InvigilationSwapKind      `json:"kind" bson:"kind"`
+	Status                InvigilationSwapStatus    `json:"status" bson:"status"`
+	ProposerID            int                       `json:"proposerID" bson:"proposerid"`
+	ProposerName          string                    `json:"proposerName" bson:"proposername"`
+	ColleagueID           int                       `json:"colleagueID" bson:"colleagueid"`
+	ColleagueName         string                    `json:"colleagueName" bson:"colleaguename"`
+	Give                  *InvigilationSwapPosition `json:"give" bson:"give"`
+	Take                  *InvigilationSwapPosition `json:"take,omitempty" bson:"take,omitempty"`
+	ProposerMinutesDelta  int                       `json:"proposerMinutesDelta" bson:"proposerminutesdelta"`
+	ColleagueMinutesDelta int                       `json:"colleagueMinutesDelta" bson:"colleagueminutesdelta"`
+	HardViolations        []string                  `json:"hardViolations" bson:"hardviolations"`
+	WithinThresholds      bool                      `json:"withinThresholds" bson:"withinthresholds"`
+	Note                  *string                   `json:"note,omitempty" bson:"note,omitempty"`
+	Reason                *string                   `json:"reason,omitempty" bson:"reason,omitempty"`
+	CreatedAt             time.Time                 `json:"createdAt" bson:"createdat"`
+	AcceptedAt            *time.Time                `json:"acceptedAt,omitempty" bson:"acceptedat,omitempty"`
+	DecidedAt             *time.Time                `json:"decidedAt,omitempty" bson:"decidedat,omitempty"`
+	AutoApproved          bool                      `json:"autoApproved" bson:"autoapproved"`
+	NotifiedAt            *time.Time                `json:"notifiedAt,omitempty" bson:"notifiedat,omitempty"`
+}
+
+// InvigilationSwapPosition is one invigilation taking part in a swap; RoomName is nil for
+// the reserve.
+type InvigilationSwapPosition struct {
+	Starttime time.Time `json:"starttime" bson:"starttime"`
+	RoomName  *string   `json:"roomName,omitempty" bson:"roomname,omitempty"`
+	Position  int       `json:"position" bson:"position"`
+	Minutes   int       `json:"minutes" bson:"minutes"`
+}
@@ -11,4 +11,6 @@ type InvigilatorConstraints struct {
 	IsNotInvigilator bool                      `json:"isNotInvigilator"`
 	ExcludedDates    []time.Time               `json:"excludedDates"`
 	TimeWindows      []*InvigilationTimeWindow `json:"timeWindows"`
+	// WeeklyTimeWindows repeat on every exam day with their weekday.
+	WeeklyTimeWindows []*WeeklyInvigilationTimeWindow `json:"weeklyTimeWindows"`
 }
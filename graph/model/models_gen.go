@@ -92,6 +92,18 @@ type AnCode struct {
 	ZpaAncode int `json:"zpaAncode"`
 }
 
+type AnnyBookingUsage struct {
+	Room          string    `json:"room"`
+	From          time.Time `json:"from"`
+	Until         time.Time `json:"until"`
+	BookedMinutes int       `json:"bookedMinutes"`
+	// Minutes with an exam in the room (the exams' windows incl. NTA extension, clipped to the booking).
+	UsedMinutes int `json:"usedMinutes"`
+	IdleMinutes int `json:"idleMinutes"`
+	Exams       int `json:"exams"`
+	MaxStudents int `json:"maxStudents"`
+}
+
 type AnnyConfig struct {
 	// names whose bookings are flagged as ours (mine=true).
 	PersonalizationNames []string `json:"personalizationNames"`
@@ -141,6 +153,42 @@ type BalanceReport struct {
 	MaxUnder        int  `json:"maxUnder"`
 }
 
+type Building struct {
+	// Prefix of the room names, e.g. R for R1.006.
+	Name   string `json:"name"`
+	Campus string `json:"campus"`
+}
+
+type Campus struct {
+	Name string `json:"name"`
+	// The campus of exams without location and of rooms in unknown buildings.
+	IsDefault bool `json:"isDefault"`
+}
+
+type CampusModel struct {
+	Campuses    []*Campus           `json:"campuses"`
+	Buildings   []*Building         `json:"buildings"`
+	TravelTimes []*CampusTravelTime `json:"travelTimes"`
+	// Rooms whose building/floor differ from their name.
+	RoomLocations []*RoomLocation `json:"roomLocations"`
+}
+
+type CampusTravelTime struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Travel time in minutes (both directions).
+	Minutes int `json:"minutes"`
+}
+
+type ConflictForecast struct {
+	Ancode1  int    `json:"ancode1"`
+	Ancode2  int    `json:"ancode2"`
+	Module1  string `json:"module1"`
+	Module2  string `json:"module2"`
+	Expected int    `json:"expected"`
+	Actual   *int   `json:"actual,omitempty"`
+}
+
 type ConflictPerProgram struct {
 	Program   string      `json:"program"`
 	Conflicts []*Conflict `json:"conflicts"`
@@ -153,8 +201,12 @@ type ConflictStudent struct {
 	Program string `json:"program"`
 	// the student's cohort/group, e.g. "IF4B".
 	Group string `json:"group"`
-	// true if this is (heuristically) a repeat for this student (repeater exam or the student's semester is above the exam's) — auto down-weighted and accepted by default.
+	// true if one of the exams is a repeat for this student (repeater exam; else the
+	// student's history across the earlier semesters; only without a history the heuristic
+	// "student's semester is above the exam's") — auto down-weighted and accepted by default.
 	AutoAccepted bool `json:"autoAccepted"`
+	// why an exam of the pair counts as a repeat for this student, one entry per repeat exam.
+	RepeatEvidence []*RepeatEvidence `json:"repeatEvidence"`
 	// explicit decision for this student, if any (overrides the automatic handling).
 	Decision *ConflictDecision `json:"decision,omitempty"`
 	// effective: penalty dropped for this student (explicit ACCEPT, or autoAccepted and not vetoed).
@@ -230,9 +282,13 @@ type ConstraintsInput struct {
 	Lab                *bool   `json:"lab,omitempty"`
 	Exahm              *bool   `json:"exahm,omitempty"`
 	Seb                *bool   `json:"seb,omitempty"`
-	KdpJiraURL         *string `json:"kdpJiraURL,omitempty"`
-	MaxStudents        *int    `json:"maxStudents,omitempty"`
-	AdditionalSeats    *int    `json:"additionalSeats,omitempty"`
+	// Room tags every room must have (see RoomConstraints.requiredTags).
+	RequiredTags    []string `json:"requiredTags,omitempty"`
+	KdpJiraURL      *string  `json:"kdpJiraURL,omitempty"`
+	MaxStudents     *int     `json:"maxStudents,omitempty"`
+	AdditionalSeats *int     `json:"additionalSeats,omitempty"`
+	// Expected attendance in percent (see RoomConstraints.expectedAttendance).
+	ExpectedAttendance *int `json:"expectedAttendance,omitempty"`
 	// Lead time (Vorlauf) in minutes before the exam; total that replaces the default 15.
 	PreExamMinutes *int `json:"preExamMinutes,omitempty"`
 	// Trailing time (Nachlauf) in minutes after the exam; total that replaces the default 15.
@@ -254,6 +310,21 @@ type CoverageReport struct {
 	Unfilled  int `json:"unfilled"`
 }
 
+// Two exams whose modules are required in the same semester of at least one program.
+type CurriculumConflict struct {
+	Ancode1 int    `json:"ancode1"`
+	Ancode2 int    `json:"ancode2"`
+	Module1 string `json:"module1"`
+	Module2 string `json:"module2"`
+	// The program semesters expecting both modules.
+	Placements []*CurriculumPlacement `json:"placements"`
+}
+
+type CurriculumPlacement struct {
+	Program  string `json:"program"`
+	Semester int    `json:"semester"`
+}
+
 type DistributionBucket struct {
 	Count        int `json:"count"`
 	Invigilators int `json:"invigilators"`
@@ -386,11 +457,81 @@ type ExamDay struct {
 	Date time.Time `json:"date"`
 }
 
+type ExamDayDashboard struct {
+	Date  time.Time      `json:"date"`
+	Slots []*ExamDaySlot `json:"slots"`
+	// All incidents of the day in time order.
+	Incidents []*ExamDayEvent `json:"incidents"`
+	// Students registered in the rooms of the day.
+	Students int `json:"students"`
+	// Students reported absent so far.
+	Absent      int       `json:"absent"`
+	GeneratedAt time.Time `json:"generatedAt"`
+}
+
+type ExamDayInvigilator struct {
+	InvigilatorID int    `json:"invigilatorID"`
+	Name          string `json:"name"`
+	// 0 = lead; reserves dispatched to the room follow the planned positions.
+	Position         int  `json:"position"`
+	SelfInvigilation bool `json:"selfInvigilation"`
+	// true for a reserve dispatched to the room.
+	Dispatched  bool       `json:"dispatched"`
+	CheckedInAt *time.Time `json:"checkedInAt,omitempty"`
+}
+
+type ExamDayReserve struct {
+	InvigilatorID int        `json:"invigilatorID"`
+	Name          string     `json:"name"`
+	CheckedInAt   *time.Time `json:"checkedInAt,omitempty"`
+	DispatchedTo  *string    `json:"dispatchedTo,omitempty"`
+	DispatchedAt  *time.Time `json:"dispatchedAt,omitempty"`
+}
+
+type ExamDayRoom struct {
+	RoomName     string                `json:"roomName"`
+	Status       ExamDayRoomStatus     `json:"status"`
+	Exams        []*ExamDayRoomExam    `json:"exams"`
+	Invigilators []*ExamDayInvigilator `json:"invigilators"`
+	// Start plus the longest duration in the room (NTA included).
+	PlannedEnd time.Time  `json:"plannedEnd"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	EndedAt    *time.Time `json:"endedAt,omitempty"`
+	// Absent students reported for the room (all its exams).
+	Absent    int `json:"absent"`
+	Incidents int `json:"incidents"`
+}
+
+type ExamDayRoomExam struct {
+	Ancode     int    `json:"ancode"`
+	Module     string `json:"module"`
+	MainExamer string `json:"mainExamer"`
+	Students   int    `json:"students"`
+	// null until reported.
+	Absent *int `json:"absent,omitempty"`
+}
+
+type ExamDaySlot struct {
+	Starttime time.Time         `json:"starttime"`
+	Rooms     []*ExamDayRoom    `json:"rooms"`
+	Reserves  []*ExamDayReserve `json:"reserves"`
+}
+
 type ExamDurationOverride struct {
 	Ancode   int `json:"ancode"`
 	Duration int `json:"duration"`
 }
 
+type ExamNameRanges struct {
+	Ancode     int              `json:"ancode"`
+	Module     string           `json:"module"`
+	MainExamer string           `json:"mainExamer"`
+	Starttime  time.Time        `json:"starttime"`
+	Ranges     []*RoomNameRange `json:"ranges"`
+	// The ranges do not overlap; false until rebalanceNameRanges ran for the exam.
+	Contiguous bool `json:"contiguous"`
+}
+
 // ExamPair is a pair of exams with display info (for canShareSlot lists/suggestions).
 type ExamPair struct {
 	Ancode1     int    `json:"ancode1"`
@@ -401,6 +542,26 @@ type ExamPair struct {
 	MainExamer2 string `json:"mainExamer2"`
 }
 
+type ExamPlacementExplanation struct {
+	Ancode     int    `json:"ancode"`
+	Module     string `json:"module"`
+	MainExamer string `json:"mainExamer"`
+	// current start time (null = not planned).
+	Starttime *time.Time `json:"starttime,omitempty"`
+	// all exams of the sameSlot group (incl. this one) — they always move together.
+	SameSlotAncodes []int `json:"sameSlotAncodes"`
+	// the generator never moves the exam (locked / external / not planned by me / phase-fixed).
+	Fixed bool `json:"fixed"`
+	// the constraints binding this exam, in plain text.
+	BindingConstraints []string `json:"bindingConstraints"`
+	// start times in the exam's allowed domain (0 = all start times allowed).
+	AllowedStarttimes int `json:"allowedStarttimes"`
+	// hard violations of the saved plan involving this exam (should be empty).
+	HardViolations []string `json:"hardViolations"`
+	// every start time of the exam period: feasible alternatives cheapest first, then the blocked ones chronologically.
+	Alternatives []*PlacementAlternative `json:"alternatives"`
+}
+
 // One exam I plan for an examer, for the planning info email (no slot/date).
 type ExamPlanningMailExam struct {
 	Ancode int    `json:"ancode"`
@@ -418,6 +579,17 @@ type ExamPlanningMailRecipient struct {
 	Exams []*ExamPlanningMailExam `json:"exams"`
 }
 
+type ExamProtocolInput struct {
+	Ancode   int    `json:"ancode"`
+	RoomName string `json:"roomName"`
+	// At least one of present and absent; the missing one is registered minus the other.
+	Present   *int       `json:"present,omitempty"`
+	Absent    *int       `json:"absent,omitempty"`
+	Incidents []string   `json:"incidents,omitempty"`
+	ActualEnd *time.Time `json:"actualEnd,omitempty"`
+	Note      *string    `json:"note,omitempty"`
+}
+
 // State of the EXaHM/SEB room phase (phase A) relative to phase B.
 type ExamRoomsPhaseState struct {
 	// planned EXaHM/SEB exams (have a plan entry — the set the room phase freezes).
@@ -489,6 +661,23 @@ type ExamScheduleDiagnostics struct {
 	SlotsOverThreshold int `json:"slotsOverThreshold"`
 	// Peak number of exams sharing a single start time.
 	MaxExamsAt int `json:"maxExamsAt"`
+	// Per study program breakdown of the spread (most students first) — shows whether any program is disadvantaged.
+	ByProgram []*ExamScheduleProgram `json:"byProgram"`
+}
+
+// Spread outcome of one study program's students in a generated (or current) exam schedule.
+type ExamScheduleProgram struct {
+	Program string `json:"program"`
+	// students of the program with at least two placed exams.
+	Students int `json:"students"`
+	// mean per-student proximity cost (the spread penalty the solver minimizes; lower = better).
+	MeanCost float64 `json:"meanCost"`
+	// 90th percentile of the per-student proximity cost.
+	P90Cost              float64 `json:"p90Cost"`
+	StudentsWithTooClose int     `json:"studentsWithTooClose"`
+	StudentsWithSameDay  int     `json:"studentsWithSameDay"`
+	// false when the program is too small to take part in the equity term (reported only).
+	InEquity bool `json:"inEquity"`
 }
 
 type ExamScheduleReport struct {
@@ -598,6 +787,19 @@ type FairnessDistribution struct {
 	Buckets []*DistributionBucket `json:"buckets"`
 }
 
+type FreeRoom struct {
+	Room   *Room          `json:"room"`
+	Status FreeRoomStatus `json:"status"`
+	// What covers a BOOKED room, e.g. "Anny-Buchung 08:00–12:00".
+	CoveredBy *string `json:"coveredBy,omitempty"`
+}
+
+type FreeRoomReservation struct {
+	Block *BlockedRoom `json:"block"`
+	// The new room request (building-management rooms not covered yet).
+	Request *RoomRequest `json:"request,omitempty"`
+}
+
 type GenerateAssembledExamsResult struct {
 	// the new state (dirty=false).
 	State *AssembledExamsState `json:"state"`
@@ -669,6 +871,10 @@ type GenerationConfig struct {
 	ExamHole float64 `json:"examHole"`
 	// 0 = tiered/grid-equivalent same-day cost; >0 = continuous falloff time constant (minutes) for finer start times.
 	ExamClosenessFalloffMin float64 `json:"examClosenessFalloffMin"`
+	// per-program equity: penalty per unit the worst-off program's mean spread cost lies above the mean of all students (programs with >= 5 students). 0 = off.
+	ExamEquity float64 `json:"examEquity"`
+	// curriculum: penalty per pair of required modules of the same program semester starting in the same slot (expected co-registration from the study plans). 0 = off.
+	ExamCurriculum float64 `json:"examCurriculum"`
 	// Pre-plan (SEB/EXaHM): usable fraction of a slot's booked Anny seats (1.0 = fill completely).
 	PreplanCapacityFactor float64 `json:"preplanCapacityFactor"`
 	// Room plan: whether/how the summer heat constraints apply (default AUTO by semester).
@@ -679,6 +885,8 @@ type GenerationConfig struct {
 	RoomBuffer float64 `json:"roomBuffer"`
 	// Room plan: penalty per extra room an exam is split across (keep an exam together).
 	RoomSplit float64 `json:"roomSplit"`
+	// Room plan: penalty per extra building a split exam spans (buildings from the campus model).
+	RoomSplitBuilding float64 `json:"roomSplitBuilding"`
 	// Room plan: penalty per distinct room used overall (compaction — request/open fewer rooms).
 	RoomCompaction float64 `json:"roomCompaction"`
 	// Room plan (summer): penalty per (floor × lateness × seat) in own rooms — later = lower floor.
@@ -687,6 +895,20 @@ type GenerationConfig struct {
 	RoomChurn float64 `json:"roomChurn"`
 	// Room plan (summer): clock hour up to which a slot start is 'cool' (lateness 0); later starts get lateness = start − baseline.
 	RoomHeatBaselineHour float64 `json:"roomHeatBaselineHour"`
+	// user-defined soft constraints for the exam schedule, the room plan and the invigilation plan (see SoftRule).
+	SoftRules []*SoftRule `json:"softRules"`
+	// Aufsichtenplanung: how many invigilators a room needs (default: one per room).
+	StaffingRules []*StaffingRule `json:"staffingRules"`
+	// Aufsichtentausch: approve an accepted swap without the planner if it breaks no hard constraint and stays within swapMaxMinutesDelta.
+	SwapAutoApprove bool `json:"swapAutoApprove"`
+	// Aufsichtentausch: largest change of a person's credited minutes an automatic approval allows (0 = equal minutes only).
+	SwapMaxMinutesDelta int `json:"swapMaxMinutesDelta"`
+	// Room plan: size the rooms for all registered students or for the expected attendance.
+	RoomSizing RoomSizingMode `json:"roomSizing"`
+	// Room plan (expected attendance): safety margin in percentage points on top of the expected attendance.
+	RoomSizingMargin int `json:"roomSizingMargin"`
+	// Room plan (expected attendance): how many previous semesters the no-show rates are taken from.
+	RoomSizingSemesters int `json:"roomSizingSemesters"`
 }
 
 type GenerationConfigInput struct {
@@ -722,6 +944,7 @@ type GenerationConfigInput struct {
 	ExamTbauFill            float64                       `json:"examTbauFill"`
 	ExamHole                float64                       `json:"examHole"`
 	ExamClosenessFalloffMin float64                       `json:"examClosenessFalloffMin"`
+	ExamEquity              float64                       `json:"examEquity"`
 	PreplanCapacityFactor   float64                       `json:"preplanCapacityFactor"`
 	RoomHeatMode            RoomHeatConstraintMode        `json:"roomHeatMode"`
 	RoomUnplaced            float64                       `json:"roomUnplaced"`
@@ -731,6 +954,22 @@ type GenerationConfigInput struct {
 	RoomHeatFloor           float64                       `json:"roomHeatFloor"`
 	RoomChurn               float64                       `json:"roomChurn"`
 	RoomHeatBaselineHour    float64                       `json:"roomHeatBaselineHour"`
+	// null keeps the stored rules (older clients).
+	SoftRules []*SoftRuleInput `json:"softRules,omitempty"`
+	// null keeps the stored staffing rules (older clients).
+	StaffingRules []*StaffingRuleInput `json:"staffingRules,omitempty"`
+	// null keeps the stored value (older clients).
+	SwapAutoApprove *bool `json:"swapAutoApprove,omitempty"`
+	// null keeps the stored value (older clients).
+	SwapMaxMinutesDelta *int `json:"swapMaxMinutesDelta,omitempty"`
+	// null keeps the stored value (older clients).
+	RoomSizing *RoomSizingMode `json:"roomSizing,omitempty"`
+	// null keeps the stored value (older clients).
+	RoomSizingMargin *int `json:"roomSizingMargin,omitempty"`
+	// null keeps the stored value (older clients).
+	RoomSizingSemesters *int `json:"roomSizingSemesters,omitempty"`
+	// null keeps the stored value (older clients).
+	ExamCurriculum *float64 `json:"examCurriculum,omitempty"`
 }
 
 type ImportJointResult struct {
@@ -772,22 +1011,32 @@ type InvigilationSlot struct {
 	RoomsWithInvigilators []*RoomWithInvigilator `json:"roomsWithInvigilators"`
 }
 
+type InvigilationSwapPositionInput struct {
+	Starttime time.Time `json:"starttime"`
+	RoomName  *string   `json:"roomName,omitempty"`
+	Position  *int      `json:"position,omitempty"`
+}
+
 // InvigilationTimeWindow restricts, for one calendar date, the times an
 // invigilator may invigilate. An assigned invigilation must start no earlier than
-// from (if set) and end no later than until (if set). The check is sub-slot
-// granular and NTA-aware, since the end time includes the room's (possibly
-// NTA-extended) duration.
+// from (if set) and end no later than until (if set). With unavailable = true the
+// window is blocked instead: no assigned invigilation may overlap from–until (an
+// open bound means the start or end of the day). The check is sub-slot granular
+// and NTA-aware, since the end time includes the room's (possibly NTA-extended)
+// duration.
 type InvigilationTimeWindow struct {
-	Date  time.Time  `json:"date"`
-	From  *time.Time `json:"from,omitempty"`
-	Until *time.Time `json:"until,omitempty"`
+	Date        time.Time  `json:"date"`
+	From        *time.Time `json:"from,omitempty"`
+	Until       *time.Time `json:"until,omitempty"`
+	Unavailable bool       `json:"unavailable"`
 }
 
-// date is the calendar day; from/until are clock times on that day (at least one of from/until must be set).
+// date is the calendar day; from/until are clock times on that day (at least one of from/until must be set). unavailable = true blocks the range instead of allowing only it.
 type InvigilationTimeWindowInput struct {
-	Date  time.Time  `json:"date"`
-	From  *time.Time `json:"from,omitempty"`
-	Until *time.Time `json:"until,omitempty"`
+	Date        time.Time  `json:"date"`
+	From        *time.Time `json:"from,omitempty"`
+	Until       *time.Time `json:"until,omitempty"`
+	Unavailable *bool      `json:"unavailable,omitempty"`
 }
 
 type InvigilationTodos struct {
@@ -812,6 +1061,8 @@ type InvigilatorConstraintsInput struct {
 	IsNotInvigilator bool                           `json:"isNotInvigilator"`
 	ExcludedDates    []*time.Time                   `json:"excludedDates"`
 	TimeWindows      []*InvigilationTimeWindowInput `json:"timeWindows"`
+	// null = no weekly windows.
+	WeeklyTimeWindows []*WeeklyInvigilationTimeWindowInput `json:"weeklyTimeWindows,omitempty"`
 }
 
 // InvigilatorOutlier: a person whose assigned minutes are furthest from target.
@@ -840,8 +1091,10 @@ type InvigilatorRequirements struct {
 	// fromZpa is false if the invigilator has not yet entered their requirements in
 	// the ZPA. In that case default requirements (full time, no contributions) are
 	// used and the invigilator still has to provide their real requirements.
-	FromZpa     bool                      `json:"fromZpa"`
-	TimeWindows []*InvigilationTimeWindow `json:"timeWindows"`
+	FromZpa bool `json:"fromZpa"`
+	// The date windows of the constraints plus the weekly windows expanded to the exam days.
+	TimeWindows       []*InvigilationTimeWindow       `json:"timeWindows"`
+	WeeklyTimeWindows []*WeeklyInvigilationTimeWindow `json:"weeklyTimeWindows"`
 }
 
 type InvigilatorTodos struct {
@@ -1041,6 +1294,34 @@ type NTAWithRegsByExamAndTeacher struct {
 	Exams   []*NTAWithRegsByExam `json:"exams,omitempty"`
 }
 
+type NoShowExam struct {
+	Ancode     int    `json:"ancode"`
+	Module     string `json:"module"`
+	MainExamer string `json:"mainExamer"`
+	Registered int    `json:"registered"`
+	Present    int    `json:"present"`
+	Absent     int    `json:"absent"`
+	// absent / (present + absent) in percent.
+	Rate float64 `json:"rate"`
+	// false if a planned room of the exam has no protocol yet.
+	Complete bool `json:"complete"`
+}
+
+type NoShowStatistics struct {
+	Workspace string `json:"workspace"`
+	// Exams with at least one protocol.
+	Exams int `json:"exams"`
+	// Rooms with a protocol.
+	Rooms      int `json:"rooms"`
+	Registered int `json:"registered"`
+	Present    int `json:"present"`
+	Absent     int `json:"absent"`
+	// absent / (present + absent) in percent.
+	Rate float64 `json:"rate"`
+	// Exams with a protocol, highest no-show rate first.
+	ByExam []*NoShowExam `json:"byExam"`
+}
+
 type NtaRoomAloneWaiver struct {
 	Mtknr  string `json:"mtknr"`
 	Ancode int    `json:"ancode"`
@@ -1076,6 +1357,33 @@ type OptimizerProgress struct {
 	Unfilled  int     `json:"unfilled"`
 }
 
+type PlacementAlternative struct {
+	Starttime time.Time `json:"starttime"`
+	// the exam's current start time.
+	Current  bool `json:"current"`
+	Feasible bool `json:"feasible"`
+	// change of the total solver cost if the exam moved here (positive = worse); 0 for the current and blocked start times.
+	CostDelta float64 `json:"costDelta"`
+	// why this start time is ruled out (empty when feasible).
+	Blockers []*PlacementBlocker `json:"blockers"`
+}
+
+type PlacementBlocker struct {
+	// name of the hard constraint (fixed | allowed-slots | student-clash | capacity).
+	Constraint string `json:"constraint"`
+	Message    string `json:"message"`
+	// the conflicting exams.
+	Ancodes []int `json:"ancodes"`
+	// the students whose registrations create the conflict.
+	Students []*PlacementStudent `json:"students"`
+}
+
+type PlacementStudent struct {
+	Mtknr   string `json:"mtknr"`
+	Name    string `json:"name"`
+	Program string `json:"program"`
+}
+
 type Planer struct {
 	Name  string `json:"name"`
 	Email string `json:"email"`
@@ -1139,13 +1447,14 @@ type PrePlannedRoom struct {
 }
 
 type PreplanExamInput struct {
-	ExamKind         string   `json:"examKind"`
-	ExamerID         int      `json:"examerID"`
-	Module           string   `json:"module"`
-	Programs         []string `json:"programs"`
-	ExpectedStudents int      `json:"expectedStudents"`
-	Duration         *int     `json:"duration,omitempty"`
-	Notes            *string  `json:"notes,omitempty"`
+	ExamKind string   `json:"examKind"`
+	ExamerID int      `json:"examerID"`
+	Module   string   `json:"module"`
+	Programs []string `json:"programs"`
+	// 0 = take the registrationForecast of the module (else of the examer).
+	ExpectedStudents int     `json:"expectedStudents"`
+	Duration         *int    `json:"duration,omitempty"`
+	Notes            *string `json:"notes,omitempty"`
 }
 
 // One graded finding of the pre-plan validation (same levels as ValidationFinding).
@@ -1283,6 +1592,48 @@ type RegWithProgram struct {
 	ZpaAncode     int    `json:"zpaAncode"`
 }
 
+type RegistrationForecast struct {
+	// The earlier workspaces the forecast is based on, oldest first.
+	Workspaces []string `json:"workspaces"`
+	// Overall registration trend per semester (1 = constant).
+	Growth float64 `json:"growth"`
+	// True once registrations are imported: actual and deviation are filled.
+	HasActual bool                        `json:"hasActual"`
+	Exams     []*RegistrationForecastExam `json:"exams"`
+	// Estimated conflicts (students registered for both exams), plus actual ones that were not forecast.
+	Conflicts []*ConflictForecast `json:"conflicts"`
+	Expected  int                 `json:"expected"`
+	Actual    *int                `json:"actual,omitempty"`
+}
+
+type RegistrationForecastExam struct {
+	Ancode     int                       `json:"ancode"`
+	Module     string                    `json:"module"`
+	MainExamer string                    `json:"mainExamer"`
+	Expected   int                       `json:"expected"`
+	Basis      RegistrationForecastBasis `json:"basis"`
+	// Trend per semester the last registrations were scaled with.
+	Growth  float64                     `json:"growth"`
+	History []*RegistrationHistoryPoint `json:"history"`
+	Actual  *int                        `json:"actual,omitempty"`
+	// actual − expected
+	Deviation *int `json:"deviation,omitempty"`
+}
+
+type RegistrationHistoryPoint struct {
+	Workspace  string `json:"workspace"`
+	Registered int    `json:"registered"`
+}
+
+// RepeatEvidence explains why an exam counts as a repeat for a student.
+type RepeatEvidence struct {
+	Ancode int          `json:"ancode"`
+	Module string       `json:"module"`
+	Source RepeatSource `json:"source"`
+	// the registrations of the same module in earlier semesters, oldest first.
+	Earlier []*StudentHistoryRecord `json:"earlier"`
+}
+
 // Anzahl der Nutzer je Rolle.
 type RoleCounts struct {
 	Admin  int `json:"admin"`
@@ -1307,8 +1658,10 @@ type Room struct {
 	SebSeats        *int `json:"sebSeats,omitempty"`
 	HmebSeats       *int `json:"hmebSeats,omitempty"`
 	Deactivated     bool `json:"deactivated"`
-	// Optional summer heat override (higher = hotter). Null = derive from the R-building floor in the name. Only used for own (non-booked) rooms.
+	// Optional summer heat override (higher = hotter). Null = derive from the room's floor (campus model: room location or name). Only used for own (non-booked) rooms.
 	Hitzewert *int `json:"hitzewert,omitempty"`
+	// Free-form feature tags (see RoomInput.tags).
+	Tags []string `json:"tags,omitempty"`
 }
 
 type RoomAndExam struct {
@@ -1322,10 +1675,18 @@ type RoomConstraints struct {
 	Lab              bool     `json:"lab"`
 	Exahm            bool     `json:"exahm"`
 	Seb              bool     `json:"seb"`
-	KdpJiraURL       *string  `json:"kdpJiraURL,omitempty"`
-	MaxStudents      *int     `json:"maxStudents,omitempty"`
+	// Room tags every room of the exam must have, "name" or "name:minimum" (e.g. pc,
+	// pc:25). The validation reports a room that seats more students than its quantity
+	// of a required tag.
+	RequiredTags []string `json:"requiredTags,omitempty"`
+	KdpJiraURL   *string  `json:"kdpJiraURL,omitempty"`
+	MaxStudents  *int     `json:"maxStudents,omitempty"`
 	// extra seats to reserve on top of the registered students (capacity buffer).
 	AdditionalSeats *int `json:"additionalSeats,omitempty"`
+	// Share (percent) of the registered students the rooms are sized for when the room plan
+	// sizes by expected attendance; replaces the prediction from previous semesters (null =
+	// predicted).
+	ExpectedAttendance *int `json:"expectedAttendance,omitempty"`
 	// Lead time (Vorlauf) in minutes the rooms must be free BEFORE this exam starts, as a
 	// total that REPLACES the default 15 min (null = default). Used for setup that exceeds the
 	// ordinary turnaround, e.g. an EXaHM exam in the T-building.
@@ -1336,6 +1697,12 @@ type RoomConstraints struct {
 	Comments        *string `json:"comments,omitempty"`
 }
 
+// All tags of a room: the built-in ones from its booleans plus its free-form tags.
+type RoomFeatures struct {
+	Room string   `json:"room"`
+	Tags []string `json:"tags"`
+}
+
 // One exam's use of a room in a slot.
 type RoomInSlotUsage struct {
 	Ancode       int    `json:"ancode"`
@@ -1357,8 +1724,92 @@ type RoomInput struct {
 	Seb             bool `json:"seb"`
 	SebSeats        *int `json:"sebSeats,omitempty"`
 	HmebSeats       *int `json:"hmebSeats,omitempty"`
-	// Optional summer heat override (higher = hotter). Null = derive from the R-building floor in the name. Only used for own (non-booked) rooms.
+	// Optional summer heat override (higher = hotter). Null = derive from the room's floor (campus model: room location or name). Only used for own (non-booked) rooms.
 	Hitzewert *int `json:"hitzewert,omitempty"`
+	// Free-form feature tags, "name" or "name:quantity" (e.g. pc:30, projector,
+	// wheelchair-access). The booleans above are the built-in tags exahm, seb, lab,
+	// handicap and socket and cannot be given here.
+	Tags []string `json:"tags,omitempty"`
+}
+
+type RoomKindShare struct {
+	RequestWith    RoomRequestType `json:"requestWith"`
+	Rooms          int             `json:"rooms"`
+	RoomSlots      int             `json:"roomSlots"`
+	SeatsAvailable int             `json:"seatsAvailable"`
+	SeatsUsed      int             `json:"seatsUsed"`
+	Utilization    float64         `json:"utilization"`
+	// Share of all seated students in percent.
+	Share float64 `json:"share"`
+}
+
+type RoomLayout struct {
+	Room        string `json:"room"`
+	Rows        int    `json:"rows"`
+	SeatsPerRow int    `json:"seatsPerRow"`
+	// Seat numbers an aisle follows (e.g. 6 = aisle between seat 6 and 7).
+	Aisles []int `json:"aisles"`
+	// Seats that cannot be used (broken, pillar, …).
+	Blocked []*SeatPosition `json:"blocked"`
+	// Seats reserved for NTAs or handicap needs (exempt from the spacing).
+	Reserved []*SeatPosition `json:"reserved"`
+	Spacing  SeatSpacing     `json:"spacing"`
+	// Usable seats (spacing and blocked seats applied, reserved seats included).
+	Capacity int `json:"capacity"`
+}
+
+type RoomLayoutInput struct {
+	Room        string               `json:"room"`
+	Rows        int                  `json:"rows"`
+	SeatsPerRow int                  `json:"seatsPerRow"`
+	Aisles      []int                `json:"aisles,omitempty"`
+	Blocked     []*SeatPositionInput `json:"blocked,omitempty"`
+	Reserved    []*SeatPositionInput `json:"reserved,omitempty"`
+	Spacing     SeatSpacing          `json:"spacing"`
+}
+
+type RoomLocation struct {
+	Room     string `json:"room"`
+	Building string `json:"building"`
+	Floor    int    `json:"floor"`
+}
+
+type RoomNameRange struct {
+	Room string `json:"room"`
+	// First letters of the range (A for the first room).
+	From string `json:"from"`
+	// Last letters of the range (Z for the last room).
+	To string `json:"to"`
+	// Students in the range.
+	Students int `json:"students"`
+	// NTA students in the room outside the range (informed individually).
+	Pinned int `json:"pinned"`
+}
+
+type RoomOutageExam struct {
+	Ancode       int       `json:"ancode"`
+	Module       string    `json:"module"`
+	MainExamer   string    `json:"mainExamer"`
+	MainExamerID int       `json:"mainExamerID"`
+	Starttime    time.Time `json:"starttime"`
+	RoomsBefore  []string  `json:"roomsBefore"`
+	RoomsAfter   []string  `json:"roomsAfter"`
+	// Students whose room changes.
+	MovedStudents int `json:"movedStudents"`
+	// Students left without a room.
+	UnplacedStudents int `json:"unplacedStudents"`
+}
+
+// An invigilation the outage changes: moved (fromRoom → toRoom), dropped (toRoom null,
+// the room is no longer used) or a newly used room that still needs an invigilator
+// (fromRoom and invigilatorID null). A room with several invigilators (staffing rules)
+// has one entry per invigilator; they move together.
+type RoomOutageInvigilation struct {
+	Starttime       time.Time `json:"starttime"`
+	FromRoom        *string   `json:"fromRoom,omitempty"`
+	ToRoom          *string   `json:"toRoom,omitempty"`
+	InvigilatorID   *int      `json:"invigilatorID,omitempty"`
+	InvigilatorName *string   `json:"invigilatorName,omitempty"`
 }
 
 // Structured outcome of a solver-based room-generation run (assignRoomsForExams), delivered once on the final RESULT line (also for dryRun).
@@ -1380,6 +1831,80 @@ type RoomPlanReport struct {
 	UnplacedExams []*UnplacedExam `json:"unplacedExams"`
 }
 
+// Where a room is, derived from the campus model.
+type RoomSite struct {
+	Room     string `json:"room"`
+	Campus   string `json:"campus"`
+	Building string `json:"building"`
+	Floor    int    `json:"floor"`
+	// Set by a room location instead of parsed from the name.
+	Override bool `json:"override"`
+}
+
+type RoomSlotUsage struct {
+	Room        string          `json:"room"`
+	RequestWith RoomRequestType `json:"requestWith"`
+	Starttime   time.Time       `json:"starttime"`
+	Seats       int             `json:"seats"`
+	Exams       int             `json:"exams"`
+	Students    int             `json:"students"`
+	Utilization float64         `json:"utilization"`
+}
+
+type RoomUsage struct {
+	Room           string          `json:"room"`
+	RequestWith    RoomRequestType `json:"requestWith"`
+	Seats          int             `json:"seats"`
+	SlotsUsed      int             `json:"slotsUsed"`
+	SeatsAvailable int             `json:"seatsAvailable"`
+	SeatsUsed      int             `json:"seatsUsed"`
+	Utilization    float64         `json:"utilization"`
+}
+
+type RoomUtilization struct {
+	Summary *RoomUtilizationSummary `json:"summary"`
+	// Per room, most used first.
+	Rooms []*RoomUsage `json:"rooms"`
+	// Per room and start time, chronological.
+	Cells []*RoomSlotUsage `json:"cells"`
+	// The busiest start times (most students), busiest first.
+	PeakSlots []*SlotUsage `json:"peakSlots"`
+	// Per Anny booking, chronological.
+	AnnyBookings []*AnnyBookingUsage `json:"annyBookings"`
+	// The same key figures for the compared workspace; null when there is none.
+	Previous *RoomUtilizationSummary `json:"previous,omitempty"`
+}
+
+type RoomUtilizationSummary struct {
+	// The workspace (database id) the figures are from.
+	Workspace string `json:"workspace"`
+	Exams     int    `json:"exams"`
+	// Start times with at least one planned room.
+	SlotsUsed int `json:"slotsUsed"`
+	RoomsUsed int `json:"roomsUsed"`
+	// Planned (room, start time) pairs.
+	RoomSlots int `json:"roomSlots"`
+	// Seats of the planned (room, start time) pairs.
+	SeatsAvailable int `json:"seatsAvailable"`
+	// Students seated.
+	SeatsUsed int `json:"seatsUsed"`
+	// seatsUsed / seatsAvailable in percent.
+	Utilization      float64 `json:"utilization"`
+	ReserveRoomSlots int     `json:"reserveRoomSlots"`
+	// Own rooms vs. rooms requested via Anny / the building management.
+	ByKind            []*RoomKindShare `json:"byKind"`
+	AnnyBookings      int              `json:"annyBookings"`
+	AnnyBookedMinutes int              `json:"annyBookedMinutes"`
+	// Booked minutes without an exam in the room.
+	AnnyIdleMinutes int `json:"annyIdleMinutes"`
+	// annyIdleMinutes / annyBookedMinutes in percent.
+	AnnyIdleShare float64 `json:"annyIdleShare"`
+	// Anny bookings without any exam.
+	AnnyUnusedBookings int        `json:"annyUnusedBookings"`
+	PeakStudents       int        `json:"peakStudents"`
+	PeakStarttime      *time.Time `json:"peakStarttime,omitempty"`
+}
+
 // A room allowed in a slot, with its free seats and the exams already using it.
 type RoomWithFreeSeats struct {
 	RoomName  string `json:"roomName"`
@@ -1400,7 +1925,12 @@ type RoomWithInvigilator struct {
 	MaxDuration  int            `json:"maxDuration"`
 	StudentCount int            `json:"studentCount"`
 	RoomAndExams []*RoomAndExam `json:"roomAndExams"`
-	Invigilator  *Teacher       `json:"invigilator,omitempty"`
+	// the (lead) invigilator, i.e. the first of invigilators.
+	Invigilator *Teacher `json:"invigilator,omitempty"`
+	// all invigilators of the room, ordered by position.
+	Invigilators []*Teacher `json:"invigilators"`
+	// invigilators the room needs according to the staffing rules.
+	InvigilatorsNeeded int `json:"invigilatorsNeeded"`
 	// true if the invigilation for this room in this slot is pre-planned (fixed).
 	PrePlanned bool `json:"prePlanned"`
 }
@@ -1437,6 +1967,80 @@ type SchedulerStatus struct {
 	AdminMailTime string `json:"adminMailTime"`
 }
 
+// One student's seat (row/seat 0 in SeatingPlan.unseated).
+type SeatAssignment struct {
+	Row    int    `json:"row"`
+	Seat   int    `json:"seat"`
+	Ancode int    `json:"ancode"`
+	Mtknr  string `json:"mtknr"`
+	Name   string `json:"name"`
+	Nta    bool   `json:"nta"`
+	// Seated on a reserved seat.
+	Reserved bool `json:"reserved"`
+}
+
+type SeatDemand struct {
+	Ancode     int    `json:"ancode"`
+	Module     string `json:"module"`
+	MainExamer string `json:"mainExamer"`
+	// Students in normal rooms (incl. additional seats; NTAs alone in a room excluded).
+	Registered int `json:"registered"`
+	// Predicted no-show rate in percent.
+	NoShowRate float64     `json:"noShowRate"`
+	Basis      NoShowBasis `json:"basis"`
+	// Recorded students the rate is based on.
+	BasedOnStudents int `json:"basedOnStudents"`
+	// roomConstraints.expectedAttendance, if set.
+	Override *int `json:"override,omitempty"`
+	// Share (percent) of the registered students the rooms are sized for: override, or 100 − rate + margin.
+	Attendance    int `json:"attendance"`
+	ExpectedSeats int `json:"expectedSeats"`
+}
+
+type SeatDemandPrediction struct {
+	// The sizing mode of the generation config; the prediction is only applied with EXPECTED_ATTENDANCE.
+	Mode RoomSizingMode `json:"mode"`
+	// Safety margin in percentage points.
+	Margin int `json:"margin"`
+	// The previous workspaces the protocols were taken from.
+	Workspaces []string      `json:"workspaces"`
+	Exams      []*SeatDemand `json:"exams"`
+	// Seats for all registered students (normal rooms).
+	Registered int `json:"registered"`
+	// Seats for the expected attendance.
+	Expected int `json:"expected"`
+}
+
+// A seat, 1-based: row 1 is the front row, seat 1 the leftmost seat.
+type SeatPosition struct {
+	Row  int `json:"row"`
+	Seat int `json:"seat"`
+}
+
+type SeatPositionInput struct {
+	Row  int `json:"row"`
+	Seat int `json:"seat"`
+}
+
+// The seats of all students planned into a room at one start time.
+type SeatingPlan struct {
+	Room        string          `json:"room"`
+	Starttime   time.Time       `json:"starttime"`
+	Ancodes     []int           `json:"ancodes"`
+	Rows        int             `json:"rows"`
+	SeatsPerRow int             `json:"seatsPerRow"`
+	Aisles      []int           `json:"aisles"`
+	Blocked     []*SeatPosition `json:"blocked"`
+	Reserved    []*SeatPosition `json:"reserved"`
+	// The room has no stored layout; a default layout (rows of ten) was used.
+	DefaultLayout bool              `json:"defaultLayout"`
+	Seats         []*SeatAssignment `json:"seats"`
+	// Students that did not fit the layout.
+	Unseated []*SeatAssignment `json:"unseated"`
+	// Neighbouring students (left or in front) of the same exam in a shared room.
+	MixingConflicts int `json:"mixingConflicts"`
+}
+
 type Semester struct {
 	// the database label (e.g. '2026 SS' or a clone '2026 SS-Test'); selects the database.
 	ID string `json:"id"`
@@ -1488,7 +2092,7 @@ type SemesterConfigInputData struct {
 	TimelagMin *int `json:"timelagMin,omitempty"`
 	// Two exams of a student closer than this (minutes, same day) are flagged as "too close" (null = default 120).
 	NotTooCloseMinutes *int `json:"notTooCloseMinutes,omitempty"`
-	// End-to-start travel buffer (minutes) a student needs between two exams at DIFFERENT campuses (null = default 120). Applied as a hard separation whenever the two exams' locations differ.
+	// End-to-start travel buffer (minutes) a student needs between two exams at DIFFERENT campuses (null = default 120). Applied as a hard separation between two different campuses without a travel time in the campus model.
 	CrossCampusGapMinutes *int `json:"crossCampusGapMinutes,omitempty"`
 	// Max students examined at the same start time (configurable per-time capacity for the Terminplan solver; null/0 = no limit).
 	MaxSeatsPerSlot *int `json:"maxSeatsPerSlot,omitempty"`
@@ -1512,6 +2116,15 @@ type ServerInfo struct {
 	MongoDatabase string `json:"mongoDatabase"`
 }
 
+type SlotUsage struct {
+	Starttime      time.Time `json:"starttime"`
+	Exams          int       `json:"exams"`
+	Rooms          int       `json:"rooms"`
+	SeatsAvailable int       `json:"seatsAvailable"`
+	Students       int       `json:"students"`
+	Utilization    float64   `json:"utilization"`
+}
+
 type SoftCostItem struct {
 	Name string  `json:"name"`
 	Cost float64 `json:"cost"`
@@ -1523,6 +2136,49 @@ type SoftCostReport struct {
 	Breakdown []*SoftCostItem `json:"breakdown"`
 }
 
+// A user-defined soft constraint for one of the generators (see SoftRuleTarget): whenever the
+// condition holds for a placement, the generator adds the penalty. Conditions use a small
+// expression language over the attributes of the target (see softRuleAttributes), e.g.
+//
+//	program == "IF" && time > "16:00"                (EXAMS)
+//	examer == "Braun" && building == "T"             (ROOMS)
+//	invigilator == "Braun" && reserve && hour >= 14  (INVIGILATIONS)
+//
+// with == != < <= > >=, in, !, &&, || and parentheses.
+type SoftRule struct {
+	// unique, stable key (listed as rule:<name> in the applied constraints).
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// the generator the rule applies to.
+	Target    SoftRuleTarget `json:"target"`
+	Condition string         `json:"condition"`
+	// penalty where the condition holds: per exam (EXAMS), per room an exam uses (ROOMS) or per
+	// invigilation (INVIGILATIONS); with perSeat per registered student (EXAMS) or per seat in
+	// the room (ROOMS). perSeat is not available for INVIGILATIONS.
+	Weight  float64 `json:"weight"`
+	PerSeat bool    `json:"perSeat"`
+	// disabled rules are kept but not applied.
+	Enabled bool `json:"enabled"`
+}
+
+type SoftRuleAttribute struct {
+	Name string `json:"name"`
+	// bool, number, string or string list.
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+type SoftRuleInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// default EXAMS.
+	Target    *SoftRuleTarget `json:"target,omitempty"`
+	Condition string          `json:"condition"`
+	Weight    float64         `json:"weight"`
+	PerSeat   bool            `json:"perSeat"`
+	Enabled   bool            `json:"enabled"`
+}
+
 type SpecialInterest struct {
 	Name string `json:"name"`
 	// output file name for the generated PDF.
@@ -1545,6 +2201,35 @@ type SpreadBucket struct {
 	Share float64 `json:"share"`
 }
 
+// A staffing rule for the invigilation planning: a room needs `invigilators` invigilators
+// at one exam time when all its conditions hold (room seats >= minSeats, students in the room
+// >= minStudents, an exam of the given type in the room, at least minNtas NTA students in
+// the room). Of all enabled matching rules the largest count wins; a room no rule matches
+// gets one invigilator. Every invigilator of a room is credited with the room's full
+// duration.
+type StaffingRule struct {
+	// unique, stable key.
+	Name        string           `json:"name"`
+	MinSeats    int              `json:"minSeats"`
+	MinStudents int              `json:"minStudents"`
+	ExamType    StaffingExamType `json:"examType"`
+	MinNtas     int              `json:"minNtas"`
+	// invigilators needed in the room (>= 1).
+	Invigilators int `json:"invigilators"`
+	// disabled rules are kept but not applied.
+	Enabled bool `json:"enabled"`
+}
+
+type StaffingRuleInput struct {
+	Name         string           `json:"name"`
+	MinSeats     int              `json:"minSeats"`
+	MinStudents  int              `json:"minStudents"`
+	ExamType     StaffingExamType `json:"examType"`
+	MinNtas      int              `json:"minNtas"`
+	Invigilators int              `json:"invigilators"`
+	Enabled      bool             `json:"enabled"`
+}
+
 type Starttime struct {
 	Start string `json:"start"`
 }
@@ -1569,6 +2254,16 @@ type StudentConflictDecision struct {
 	Decision ConflictDecision `json:"decision"`
 }
 
+// StudentHistorySummary is the result of rebuilding the student history.
+type StudentHistorySummary struct {
+	// the semester workspaces read, oldest first.
+	Workspaces []string `json:"workspaces"`
+	Students   int      `json:"students"`
+	Records    int      `json:"records"`
+	// records with imported attendance.
+	WithAttendance int `json:"withAttendance"`
+}
+
 type StudentRegsPerAncode struct {
 	Ancode     int                               `json:"ancode"`
 	PerProgram []*StudentRegsPerAncodeAndProgram `json:"perProgram"`
@@ -1662,6 +2357,28 @@ type ValidationReport struct {
 	Findings     []*ValidationFinding `json:"findings"`
 }
 
+// WeeklyInvigilationTimeWindow is an InvigilationTimeWindow repeated on every exam
+// day with the given weekday, e.g. "Di, until 12:00" (available only in the
+// morning) or "Do, from 14:00, unavailable" (blocked in the afternoon). A date
+// window of the same kind on that day replaces a weekly available window.
+type WeeklyInvigilationTimeWindow struct {
+	// Mo, Di, Mi, Do, Fr or Sa.
+	Weekday string `json:"weekday"`
+	// Clock time HH:MM.
+	From *string `json:"from,omitempty"`
+	// Clock time HH:MM.
+	Until       *string `json:"until,omitempty"`
+	Unavailable bool    `json:"unavailable"`
+}
+
+// weekday is Mo, Di, Mi, Do, Fr or Sa; from/until are clock times HH:MM (at least one must be set).
+type WeeklyInvigilationTimeWindowInput struct {
+	Weekday     string  `json:"weekday"`
+	From        *string `json:"from,omitempty"`
+	Until       *string `json:"until,omitempty"`
+	Unavailable *bool   `json:"unavailable,omitempty"`
+}
+
 type WorstStudent struct {
 	Mtknr     string `json:"mtknr"`
 	Name      string `json:"name"`
@@ -1761,59 +2478,61 @@ func (e ConflictDecision) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// LogLevel classifies a streamed LogLine. PROGRESS lines are throttled optimizer
-// snapshots and should be rendered in-place (like a spinner) instead of appended.
-// DONE marks the final line of a stream.
-type LogLevel string
+type ExamDayEventKind string
 
 const (
-	LogLevelInfo     LogLevel = "INFO"
-	LogLevelWarn     LogLevel = "WARN"
-	LogLevelError    LogLevel = "ERROR"
-	LogLevelProgress LogLevel = "PROGRESS"
-	LogLevelResult   LogLevel = "RESULT"
-	LogLevelDone     LogLevel = "DONE"
+	// An invigilator arrived in the room (roomName null: a reserve at the desk).
+	ExamDayEventKindCheckIn ExamDayEventKind = "CHECK_IN"
+	// A reserve was sent to a room.
+	ExamDayEventKindDispatch ExamDayEventKind = "DISPATCH"
+	// The exam in the room started.
+	ExamDayEventKindStart ExamDayEventKind = "START"
+	// The last student left the room.
+	ExamDayEventKindEnd ExamDayEventKind = "END"
+	// Absent students of one exam in the room.
+	ExamDayEventKindAbsent   ExamDayEventKind = "ABSENT"
+	ExamDayEventKindIncident ExamDayEventKind = "INCIDENT"
 )
 
-var AllLogLevel = []LogLevel{
-	LogLevelInfo,
-	LogLevelWarn,
-	LogLevelError,
-	LogLevelProgress,
-	LogLevelResult,
-	LogLevelDone,
+var AllExamDayEventKind = []ExamDayEventKind{
+	ExamDayEventKindCheckIn,
+	ExamDayEventKindDispatch,
+	ExamDayEventKindStart,
+	ExamDayEventKindEnd,
+	ExamDayEventKindAbsent,
+	ExamDayEventKindIncident,
 }
 
-func (e LogLevel) IsValid() bool {
+func (e ExamDayEventKind) IsValid() bool {
 	switch e {
-	case LogLevelInfo, LogLevelWarn, LogLevelError, LogLevelProgress, LogLevelResult, LogLevelDone:
+	case ExamDayEventKindCheckIn, ExamDayEventKindDispatch, ExamDayEventKindStart, ExamDayEventKindEnd, ExamDayEventKindAbsent, ExamDayEventKindIncident:
 		return true
 	}
 	return false
 }
 
-func (e LogLevel) String() string {
+func (e ExamDayEventKind) String() string {
 	return string(e)
 }
 
-func (e *LogLevel) UnmarshalGQL(v any) error {
+func (e *ExamDayEventKind) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = LogLevel(str)
+	*e = ExamDayEventKind(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid LogLevel", str)
+		return fmt.Errorf("%s is not a valid ExamDayEventKind", str)
 	}
 	return nil
 }
 
-func (e LogLevel) MarshalGQL(w io.Writer) {
+func (e ExamDayEventKind) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *LogLevel) UnmarshalJSON(b []byte) error {
+func (e *ExamDayEventKind) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1821,57 +2540,60 @@ func (e *LogLevel) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e LogLevel) MarshalJSON() ([]byte, error) {
+func (e ExamDayEventKind) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-// Areas that can be locked by a published gate.
-type PlanningGate string
+type ExamDayRoomStatus string
 
 const (
-	PlanningGateExams         PlanningGate = "EXAMS"
-	PlanningGateRooms         PlanningGate = "ROOMS"
-	PlanningGateInvigilations PlanningGate = "INVIGILATIONS"
+	// Not all invigilator positions of the room are checked in yet.
+	ExamDayRoomStatusWaiting ExamDayRoomStatus = "WAITING"
+	// All invigilators are there, the exam has not started.
+	ExamDayRoomStatusReady   ExamDayRoomStatus = "READY"
+	ExamDayRoomStatusRunning ExamDayRoomStatus = "RUNNING"
+	ExamDayRoomStatusEnded   ExamDayRoomStatus = "ENDED"
 )
 
-var AllPlanningGate = []PlanningGate{
-	PlanningGateExams,
-	PlanningGateRooms,
-	PlanningGateInvigilations,
+var AllExamDayRoomStatus = []ExamDayRoomStatus{
+	ExamDayRoomStatusWaiting,
+	ExamDayRoomStatusReady,
+	ExamDayRoomStatusRunning,
+	ExamDayRoomStatusEnded,
 }
 
-func (e PlanningGate) IsValid() bool {
+func (e ExamDayRoomStatus) IsValid() bool {
 	switch e {
-	case PlanningGateExams, PlanningGateRooms, PlanningGateInvigilations:
+	case ExamDayRoomStatusWaiting, ExamDayRoomStatusReady, ExamDayRoomStatusRunning, ExamDayRoomStatusEnded:
 		return true
 	}
 	return false
 }
 
-func (e PlanningGate) String() string {
+func (e ExamDayRoomStatus) String() string {
 	return string(e)
 }
 
-func (e *PlanningGate) UnmarshalGQL(v any) error {
+func (e *ExamDayRoomStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = PlanningGate(str)
+	*e = ExamDayRoomStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid PlanningGate", str)
+		return fmt.Errorf("%s is not a valid ExamDayRoomStatus", str)
 	}
 	return nil
 }
 
-func (e PlanningGate) MarshalGQL(w io.Writer) {
+func (e ExamDayRoomStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *PlanningGate) UnmarshalJSON(b []byte) error {
+func (e *ExamDayRoomStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1879,56 +2601,58 @@ func (e *PlanningGate) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e PlanningGate) MarshalJSON() ([]byte, error) {
+func (e ExamDayRoomStatus) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type PreplanRuleKind string
+type ExamProtocolSource string
 
 const (
-	// Must always hold; a plan violating it is invalid.
-	PreplanRuleKindHard PreplanRuleKind = "HARD"
-	// Optimization goal; the solver trades these off (weighted).
-	PreplanRuleKindSoft PreplanRuleKind = "SOFT"
+	ExamProtocolSourceManual ExamProtocolSource = "MANUAL"
+	// CSV/XLSX upload.
+	ExamProtocolSourceImport ExamProtocolSource = "IMPORT"
+	// Taken over from the exam-day records.
+	ExamProtocolSourceExamDay ExamProtocolSource = "EXAM_DAY"
 )
 
-var AllPreplanRuleKind = []PreplanRuleKind{
-	PreplanRuleKindHard,
-	PreplanRuleKindSoft,
+var AllExamProtocolSource = []ExamProtocolSource{
+	ExamProtocolSourceManual,
+	ExamProtocolSourceImport,
+	ExamProtocolSourceExamDay,
 }
 
-func (e PreplanRuleKind) IsValid() bool {
+func (e ExamProtocolSource) IsValid() bool {
 	switch e {
-	case PreplanRuleKindHard, PreplanRuleKindSoft:
+	case ExamProtocolSourceManual, ExamProtocolSourceImport, ExamProtocolSourceExamDay:
 		return true
 	}
 	return false
 }
 
-func (e PreplanRuleKind) String() string {
+func (e ExamProtocolSource) String() string {
 	return string(e)
 }
 
-func (e *PreplanRuleKind) UnmarshalGQL(v any) error {
+func (e *ExamProtocolSource) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = PreplanRuleKind(str)
+	*e = ExamProtocolSource(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid PreplanRuleKind", str)
+		return fmt.Errorf("%s is not a valid ExamProtocolSource", str)
 	}
 	return nil
 }
 
-func (e PreplanRuleKind) MarshalGQL(w io.Writer) {
+func (e ExamProtocolSource) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *PreplanRuleKind) UnmarshalJSON(b []byte) error {
+func (e *ExamProtocolSource) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1936,62 +2660,58 @@ func (e *PreplanRuleKind) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e PreplanRuleKind) MarshalJSON() ([]byte, error) {
+func (e ExamProtocolSource) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-// A role governs what a logged-in user may do. A user has exactly one role; the roles
-// form a hierarchy ADMIN ⊇ PLANER ⊇ VIEWER:
-// - VIEWER: read-only (queries + validations, no mutations/data-changing subscriptions).
-// - PLANER: full planning access (everything VIEWER can, plus all data-changing ops).
-// - ADMIN:  everything PLANER can, plus user administration (setUser/removeUser).
-// The enum is intentionally extensible for finer-grained roles later.
-type Role string
+// FREE = own room (no request needed); BOOKED = requested room covered by an approved
+// room request or one of our Anny bookings; REQUESTABLE = requested room, not covered yet.
+type FreeRoomStatus string
 
 const (
-	RoleAdmin  Role = "ADMIN"
-	RolePlaner Role = "PLANER"
-	RoleViewer Role = "VIEWER"
+	FreeRoomStatusFree        FreeRoomStatus = "FREE"
+	FreeRoomStatusBooked      FreeRoomStatus = "BOOKED"
+	FreeRoomStatusRequestable FreeRoomStatus = "REQUESTABLE"
 )
 
-var AllRole = []Role{
-	RoleAdmin,
-	RolePlaner,
-	RoleViewer,
+var AllFreeRoomStatus = []FreeRoomStatus{
+	FreeRoomStatusFree,
+	FreeRoomStatusBooked,
+	FreeRoomStatusRequestable,
 }
 
-func (e Role) IsValid() bool {
+func (e FreeRoomStatus) IsValid() bool {
 	switch e {
-	case RoleAdmin, RolePlaner, RoleViewer:
+	case FreeRoomStatusFree, FreeRoomStatusBooked, FreeRoomStatusRequestable:
 		return true
 	}
 	return false
 }
 
-func (e Role) String() string {
+func (e FreeRoomStatus) String() string {
 	return string(e)
 }
 
-func (e *Role) UnmarshalGQL(v any) error {
+func (e *FreeRoomStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = Role(str)
+	*e = FreeRoomStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid Role", str)
+		return fmt.Errorf("%s is not a valid FreeRoomStatus", str)
 	}
 	return nil
 }
 
-func (e Role) MarshalGQL(w io.Writer) {
+func (e FreeRoomStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *Role) UnmarshalJSON(b []byte) error {
+func (e *FreeRoomStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1999,59 +2719,56 @@ func (e *Role) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e Role) MarshalJSON() ([]byte, error) {
+func (e FreeRoomStatus) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-// When the room-plan generator applies the summer heat constraints (heat-floor soft + summer
-// cooldown hard). AUTO follows the semester (active only in summer/SS); SUMMER forces them on
-// (for testing); OFF disables them. Only own (non-booked) rooms are ever affected. Default AUTO.
-type RoomHeatConstraintMode string
+type InvigilationSwapKind string
 
 const (
-	RoomHeatConstraintModeAuto   RoomHeatConstraintMode = "AUTO"
-	RoomHeatConstraintModeSummer RoomHeatConstraintMode = "SUMMER"
-	RoomHeatConstraintModeOff    RoomHeatConstraintMode = "OFF"
+	// The proposer gives their invigilation to the colleague.
+	InvigilationSwapKindHandover InvigilationSwapKind = "HANDOVER"
+	// The proposer and the colleague trade one invigilation each.
+	InvigilationSwapKindSwap InvigilationSwapKind = "SWAP"
 )
 
-var AllRoomHeatConstraintMode = []RoomHeatConstraintMode{
-	RoomHeatConstraintModeAuto,
-	RoomHeatConstraintModeSummer,
-	RoomHeatConstraintModeOff,
+var AllInvigilationSwapKind = []InvigilationSwapKind{
+	InvigilationSwapKindHandover,
+	InvigilationSwapKindSwap,
 }
 
-func (e RoomHeatConstraintMode) IsValid() bool {
+func (e InvigilationSwapKind) IsValid() bool {
 	switch e {
-	case RoomHeatConstraintModeAuto, RoomHeatConstraintModeSummer, RoomHeatConstraintModeOff:
+	case InvigilationSwapKindHandover, InvigilationSwapKindSwap:
 		return true
 	}
 	return false
 }
 
-func (e RoomHeatConstraintMode) String() string {
+func (e InvigilationSwapKind) String() string {
 	return string(e)
 }
 
-func (e *RoomHeatConstraintMode) UnmarshalGQL(v any) error {
+func (e *InvigilationSwapKind) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = RoomHeatConstraintMode(str)
+	*e = InvigilationSwapKind(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid RoomHeatConstraintMode", str)
+		return fmt.Errorf("%s is not a valid InvigilationSwapKind", str)
 	}
 	return nil
 }
 
-func (e RoomHeatConstraintMode) MarshalGQL(w io.Writer) {
+func (e InvigilationSwapKind) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *RoomHeatConstraintMode) UnmarshalJSON(b []byte) error {
+func (e *InvigilationSwapKind) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -2059,59 +2776,61 @@ func (e *RoomHeatConstraintMode) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e RoomHeatConstraintMode) MarshalJSON() ([]byte, error) {
+func (e InvigilationSwapKind) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-// RoomRequestType says how a room that needs requesting must be requested:
-// NONE = no request needed, ANNY = via the Anny website (T-building), MANAGEMENT =
-// via the building management (Gebäudemanagement).
-type RoomRequestType string
+type InvigilationSwapStatus string
 
 const (
-	RoomRequestTypeNone       RoomRequestType = "NONE"
-	RoomRequestTypeAnny       RoomRequestType = "ANNY"
-	RoomRequestTypeManagement RoomRequestType = "MANAGEMENT"
+	// Waiting for the colleague.
+	InvigilationSwapStatusProposed InvigilationSwapStatus = "PROPOSED"
+	// The colleague agreed, waiting for the planner.
+	InvigilationSwapStatusAccepted InvigilationSwapStatus = "ACCEPTED"
+	// Applied to the plan.
+	InvigilationSwapStatusApproved InvigilationSwapStatus = "APPROVED"
+	InvigilationSwapStatusRejected InvigilationSwapStatus = "REJECTED"
 )
 
-var AllRoomRequestType = []RoomRequestType{
-	RoomRequestTypeNone,
-	RoomRequestTypeAnny,
-	RoomRequestTypeManagement,
+var AllInvigilationSwapStatus = []InvigilationSwapStatus{
+	InvigilationSwapStatusProposed,
+	InvigilationSwapStatusAccepted,
+	InvigilationSwapStatusApproved,
+	InvigilationSwapStatusRejected,
 }
 
-func (e RoomRequestType) IsValid() bool {
+func (e InvigilationSwapStatus) IsValid() bool {
 	switch e {
-	case RoomRequestTypeNone, RoomRequestTypeAnny, RoomRequestTypeManagement:
+	case InvigilationSwapStatusProposed, InvigilationSwapStatusAccepted, InvigilationSwapStatusApproved, InvigilationSwapStatusRejected:
 		return true
 	}
 	return false
 }
 
-func (e RoomRequestType) String() string {
+func (e InvigilationSwapStatus) String() string {
 	return string(e)
 }
 
-func (e *RoomRequestType) UnmarshalGQL(v any) error {
+func (e *InvigilationSwapStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = RoomRequestType(str)
+	*e = InvigilationSwapStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid RoomRequestType", str)
+		return fmt.Errorf("%s is not a valid InvigilationSwapStatus", str)
 	}
 	return nil
 }
 
-func (e RoomRequestType) MarshalGQL(w io.Writer) {
+func (e InvigilationSwapStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *RoomRequestType) UnmarshalJSON(b []byte) error {
+func (e *InvigilationSwapStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -2119,38 +2838,763 @@ func (e *RoomRequestType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e RoomRequestType) MarshalJSON() ([]byte, error) {
+func (e InvigilationSwapStatus) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-// How strictly the start-time window is enforced. HARD (default): a hard domain restriction —
-// a non-exempt exam is never placed outside its window; one that fits nowhere is left UNPLACED
-// with a clear reason (the rest of the plan is still written). SOFT: a strong penalty instead —
-// the exam may be placed outside the window (deliberate emergency deviation) but is reported as
-// a violation. EXaHM/SEB exams (booked T-building rooms) are exempt in both cases.
-type SlotTimeConstraintEnforcement string
+// LogLevel classifies a streamed LogLine. PROGRESS lines are throttled optimizer
+// snapshots and should be rendered in-place (like a spinner) instead of appended.
+// DONE marks the final line of a stream.
+type LogLevel string
 
 const (
-	SlotTimeConstraintEnforcementHard SlotTimeConstraintEnforcement = "HARD"
-	SlotTimeConstraintEnforcementSoft SlotTimeConstraintEnforcement = "SOFT"
+	LogLevelInfo     LogLevel = "INFO"
+	LogLevelWarn     LogLevel = "WARN"
+	LogLevelError    LogLevel = "ERROR"
+	LogLevelProgress LogLevel = "PROGRESS"
+	LogLevelResult   LogLevel = "RESULT"
+	LogLevelDone     LogLevel = "DONE"
 )
 
-var AllSlotTimeConstraintEnforcement = []SlotTimeConstraintEnforcement{
-	SlotTimeConstraintEnforcementHard,
-	SlotTimeConstraintEnforcementSoft,
+var AllLogLevel = []LogLevel{
+	LogLevelInfo,
+	LogLevelWarn,
+	LogLevelError,
+	LogLevelProgress,
+	LogLevelResult,
+	LogLevelDone,
 }
 
-func (e SlotTimeConstraintEnforcement) IsValid() bool {
+func (e LogLevel) IsValid() bool {
 	switch e {
-	case SlotTimeConstraintEnforcementHard, SlotTimeConstraintEnforcementSoft:
+	case LogLevelInfo, LogLevelWarn, LogLevelError, LogLevelProgress, LogLevelResult, LogLevelDone:
 		return true
 	}
 	return false
 }
 
-func (e SlotTimeConstraintEnforcement) String() string {
+func (e LogLevel) String() string {
+	return string(e)
+}
+
+func (e *LogLevel) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LogLevel(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LogLevel", str)
+	}
+	return nil
+}
+
+func (e LogLevel) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *LogLevel) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e LogLevel) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type NoShowBasis string
+
+const (
+	// The module has enough recorded students.
+	NoShowBasisModule NoShowBasis = "MODULE"
+	// The main examer has enough recorded students.
+	NoShowBasisExamer NoShowBasis = "EXAMER"
+	// All recorded exams.
+	NoShowBasisAll NoShowBasis = "ALL"
+	// No protocols in the previous semesters: everyone is expected.
+	NoShowBasisNone NoShowBasis = "NONE"
+)
+
+var AllNoShowBasis = []NoShowBasis{
+	NoShowBasisModule,
+	NoShowBasisExamer,
+	NoShowBasisAll,
+	NoShowBasisNone,
+}
+
+func (e NoShowBasis) IsValid() bool {
+	switch e {
+	case NoShowBasisModule, NoShowBasisExamer, NoShowBasisAll, NoShowBasisNone:
+		return true
+	}
+	return false
+}
+
+func (e NoShowBasis) String() string {
+	return string(e)
+}
+
+func (e *NoShowBasis) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NoShowBasis(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NoShowBasis", str)
+	}
+	return nil
+}
+
+func (e NoShowBasis) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *NoShowBasis) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e NoShowBasis) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Areas that can be locked by a published gate.
+type PlanningGate string
+
+const (
+	PlanningGateExams         PlanningGate = "EXAMS"
+	PlanningGateRooms         PlanningGate = "ROOMS"
+	PlanningGateInvigilations PlanningGate = "INVIGILATIONS"
+)
+
+var AllPlanningGate = []PlanningGate{
+	PlanningGateExams,
+	PlanningGateRooms,
+	PlanningGateInvigilations,
+}
+
+func (e PlanningGate) IsValid() bool {
+	switch e {
+	case PlanningGateExams, PlanningGateRooms, PlanningGateInvigilations:
+		return true
+	}
+	return false
+}
+
+func (e PlanningGate) String() string {
+	return string(e)
+}
+
+func (e *PlanningGate) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PlanningGate(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PlanningGate", str)
+	}
+	return nil
+}
+
+func (e PlanningGate) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PlanningGate) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PlanningGate) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PreplanRuleKind string
+
+const (
+	// Must always hold; a plan violating it is invalid.
+	PreplanRuleKindHard PreplanRuleKind = "HARD"
+	// Optimization goal; the solver trades these off (weighted).
+	PreplanRuleKindSoft PreplanRuleKind = "SOFT"
+)
+
+var AllPreplanRuleKind = []PreplanRuleKind{
+	PreplanRuleKindHard,
+	PreplanRuleKindSoft,
+}
+
+func (e PreplanRuleKind) IsValid() bool {
+	switch e {
+	case PreplanRuleKindHard, PreplanRuleKindSoft:
+		return true
+	}
+	return false
+}
+
+func (e PreplanRuleKind) String() string {
+	return string(e)
+}
+
+func (e *PreplanRuleKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PreplanRuleKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PreplanRuleKind", str)
+	}
+	return nil
+}
+
+func (e PreplanRuleKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PreplanRuleKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PreplanRuleKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type RegistrationForecastBasis string
+
+const (
+	// The module was offered in an earlier semester.
+	RegistrationForecastBasisModule RegistrationForecastBasis = "MODULE"
+	// Only the main examer had exams in an earlier semester (average per exam).
+	RegistrationForecastBasisExamer RegistrationForecastBasis = "EXAMER"
+	// Never seen before: no forecast.
+	RegistrationForecastBasisNone RegistrationForecastBasis = "NONE"
+)
+
+var AllRegistrationForecastBasis = []RegistrationForecastBasis{
+	RegistrationForecastBasisModule,
+	RegistrationForecastBasisExamer,
+	RegistrationForecastBasisNone,
+}
+
+func (e RegistrationForecastBasis) IsValid() bool {
+	switch e {
+	case RegistrationForecastBasisModule, RegistrationForecastBasisExamer, RegistrationForecastBasisNone:
+		return true
+	}
+	return false
+}
+
+func (e RegistrationForecastBasis) String() string {
+	return string(e)
+}
+
+func (e *RegistrationForecastBasis) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RegistrationForecastBasis(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RegistrationForecastBasis", str)
+	}
+	return nil
+}
+
+func (e RegistrationForecastBasis) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *RegistrationForecastBasis) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e RegistrationForecastBasis) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// RepeatSource is what a repeat decision is based on.
+type RepeatSource string
+
+const (
+	// the exam is a repeater exam
+	RepeatSourceExam RepeatSource = "EXAM"
+	// the student was registered for the module in an earlier semester
+	RepeatSourceHistory RepeatSource = "HISTORY"
+	// no history of the student: the study-group number is above the exam's
+	RepeatSourceHeuristic RepeatSource = "HEURISTIC"
+)
+
+var AllRepeatSource = []RepeatSource{
+	RepeatSourceExam,
+	RepeatSourceHistory,
+	RepeatSourceHeuristic,
+}
+
+func (e RepeatSource) IsValid() bool {
+	switch e {
+	case RepeatSourceExam, RepeatSourceHistory, RepeatSourceHeuristic:
+		return true
+	}
+	return false
+}
+
+func (e RepeatSource) String() string {
+	return string(e)
+}
+
+func (e *RepeatSource) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RepeatSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RepeatSource", str)
+	}
+	return nil
+}
+
+func (e RepeatSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *RepeatSource) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e RepeatSource) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// A role governs what a logged-in user may do. A user has exactly one role; the roles
+// form a hierarchy ADMIN ⊇ PLANER ⊇ VIEWER:
+// - VIEWER: read-only (queries + validations, no mutations/data-changing subscriptions).
+// - PLANER: full planning access (everything VIEWER can, plus all data-changing ops).
+// - ADMIN:  everything PLANER can, plus user administration (setUser/removeUser).
+// The enum is intentionally extensible for finer-grained roles later.
+type Role string
+
+const (
+	RoleAdmin  Role = "ADMIN"
+	RolePlaner Role = "PLANER"
+	RoleViewer Role = "VIEWER"
+)
+
+var AllRole = []Role{
+	RoleAdmin,
+	RolePlaner,
+	RoleViewer,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleAdmin, RolePlaner, RoleViewer:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *Role) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e Role) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type RoomChangeRole string
+
+const (
+	RoomChangeRoleExamer      RoomChangeRole = "EXAMER"
+	RoomChangeRoleInvigilator RoomChangeRole = "INVIGILATOR"
+)
+
+var AllRoomChangeRole = []RoomChangeRole{
+	RoomChangeRoleExamer,
+	RoomChangeRoleInvigilator,
+}
+
+func (e RoomChangeRole) IsValid() bool {
+	switch e {
+	case RoomChangeRoleExamer, RoomChangeRoleInvigilator:
+		return true
+	}
+	return false
+}
+
+func (e RoomChangeRole) String() string {
+	return string(e)
+}
+
+func (e *RoomChangeRole) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RoomChangeRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RoomChangeRole", str)
+	}
+	return nil
+}
+
+func (e RoomChangeRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *RoomChangeRole) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e RoomChangeRole) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// When the room-plan generator applies the summer heat constraints (heat-floor soft + summer
+// cooldown hard). AUTO follows the semester (active only in summer/SS); SUMMER forces them on
+// (for testing); OFF disables them. Only own (non-booked) rooms are ever affected. Default AUTO.
+type RoomHeatConstraintMode string
+
+const (
+	RoomHeatConstraintModeAuto   RoomHeatConstraintMode = "AUTO"
+	RoomHeatConstraintModeSummer RoomHeatConstraintMode = "SUMMER"
+	RoomHeatConstraintModeOff    RoomHeatConstraintMode = "OFF"
+)
+
+var AllRoomHeatConstraintMode = []RoomHeatConstraintMode{
+	RoomHeatConstraintModeAuto,
+	RoomHeatConstraintModeSummer,
+	RoomHeatConstraintModeOff,
+}
+
+func (e RoomHeatConstraintMode) IsValid() bool {
+	switch e {
+	case RoomHeatConstraintModeAuto, RoomHeatConstraintModeSummer, RoomHeatConstraintModeOff:
+		return true
+	}
+	return false
+}
+
+func (e RoomHeatConstraintMode) String() string {
+	return string(e)
+}
+
+func (e *RoomHeatConstraintMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RoomHeatConstraintMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RoomHeatConstraintMode", str)
+	}
+	return nil
+}
+
+func (e RoomHeatConstraintMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *RoomHeatConstraintMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e RoomHeatConstraintMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// RoomRequestType says how a room that needs requesting must be requested:
+// NONE = no request needed, ANNY = via the Anny website (T-building), MANAGEMENT =
+// via the building management (Gebäudemanagement).
+type RoomRequestType string
+
+const (
+	RoomRequestTypeNone       RoomRequestType = "NONE"
+	RoomRequestTypeAnny       RoomRequestType = "ANNY"
+	RoomRequestTypeManagement RoomRequestType = "MANAGEMENT"
+)
+
+var AllRoomRequestType = []RoomRequestType{
+	RoomRequestTypeNone,
+	RoomRequestTypeAnny,
+	RoomRequestTypeManagement,
+}
+
+func (e RoomRequestType) IsValid() bool {
+	switch e {
+	case RoomRequestTypeNone, RoomRequestTypeAnny, RoomRequestTypeManagement:
+		return true
+	}
+	return false
+}
+
+func (e RoomRequestType) String() string {
+	return string(e)
+}
+
+func (e *RoomRequestType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RoomRequestType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RoomRequestType", str)
+	}
+	return nil
+}
+
+func (e RoomRequestType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *RoomRequestType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e RoomRequestType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// What the room-plan generator sizes the rooms for. REGISTERED (default): a seat for every
+// registered student. EXPECTED_ATTENDANCE: only for the students expected to show up (the
+// no-show rate of previous semesters plus roomSizingMargin, see seatDemandPrediction), so a
+// room may get more registered students than it has seats.
+type RoomSizingMode string
+
+const (
+	RoomSizingModeRegistered         RoomSizingMode = "REGISTERED"
+	RoomSizingModeExpectedAttendance RoomSizingMode = "EXPECTED_ATTENDANCE"
+)
+
+var AllRoomSizingMode = []RoomSizingMode{
+	RoomSizingModeRegistered,
+	RoomSizingModeExpectedAttendance,
+}
+
+func (e RoomSizingMode) IsValid() bool {
+	switch e {
+	case RoomSizingModeRegistered, RoomSizingModeExpectedAttendance:
+		return true
+	}
+	return false
+}
+
+func (e RoomSizingMode) String() string {
+	return string(e)
+}
+
+func (e *RoomSizingMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RoomSizingMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RoomSizingMode", str)
+	}
+	return nil
+}
+
+func (e RoomSizingMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *RoomSizingMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e RoomSizingMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Exam-spacing pattern: which seats stay empty between students.
+type SeatSpacing string
+
+const (
+	SeatSpacingNone SeatSpacing = "NONE"
+	// seats 1, 3, 5, … of every row
+	SeatSpacingEveryOtherSeat SeatSpacing = "EVERY_OTHER_SEAT"
+	// rows 1, 3, 5, …
+	SeatSpacingEveryOtherRow SeatSpacing = "EVERY_OTHER_ROW"
+	// odd seats in odd rows, even seats in even rows
+	SeatSpacingCheckerboard SeatSpacing = "CHECKERBOARD"
+)
+
+var AllSeatSpacing = []SeatSpacing{
+	SeatSpacingNone,
+	SeatSpacingEveryOtherSeat,
+	SeatSpacingEveryOtherRow,
+	SeatSpacingCheckerboard,
+}
+
+func (e SeatSpacing) IsValid() bool {
+	switch e {
+	case SeatSpacingNone, SeatSpacingEveryOtherSeat, SeatSpacingEveryOtherRow, SeatSpacingCheckerboard:
+		return true
+	}
+	return false
+}
+
+func (e SeatSpacing) String() string {
+	return string(e)
+}
+
+func (e *SeatSpacing) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SeatSpacing(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SeatSpacing", str)
+	}
+	return nil
+}
+
+func (e SeatSpacing) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SeatSpacing) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SeatSpacing) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// How strictly the start-time window is enforced. HARD (default): a hard domain restriction —
+// a non-exempt exam is never placed outside its window; one that fits nowhere is left UNPLACED
+// with a clear reason (the rest of the plan is still written). SOFT: a strong penalty instead —
+// the exam may be placed outside the window (deliberate emergency deviation) but is reported as
+// a violation. EXaHM/SEB exams (booked T-building rooms) are exempt in both cases.
+type SlotTimeConstraintEnforcement string
+
+const (
+	SlotTimeConstraintEnforcementHard SlotTimeConstraintEnforcement = "HARD"
+	SlotTimeConstraintEnforcementSoft SlotTimeConstraintEnforcement = "SOFT"
+)
+
+var AllSlotTimeConstraintEnforcement = []SlotTimeConstraintEnforcement{
+	SlotTimeConstraintEnforcementHard,
+	SlotTimeConstraintEnforcementSoft,
+}
+
+func (e SlotTimeConstraintEnforcement) IsValid() bool {
+	switch e {
+	case SlotTimeConstraintEnforcementHard, SlotTimeConstraintEnforcementSoft:
+		return true
+	}
+	return false
+}
+
+func (e SlotTimeConstraintEnforcement) String() string {
 	return string(e)
 }
 
@@ -2249,6 +3693,187 @@ func (e SlotTimeConstraintMode) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// The generator a soft rule applies to. EXAMS: the exam schedule (an exam at a start time).
+// ROOMS: the room plan (an exam in a room). INVIGILATIONS: the invigilation plan (an
+// invigilator on an invigilation: room, NTA room or reserve).
+type SoftRuleTarget string
+
+const (
+	SoftRuleTargetExams         SoftRuleTarget = "EXAMS"
+	SoftRuleTargetRooms         SoftRuleTarget = "ROOMS"
+	SoftRuleTargetInvigilations SoftRuleTarget = "INVIGILATIONS"
+)
+
+var AllSoftRuleTarget = []SoftRuleTarget{
+	SoftRuleTargetExams,
+	SoftRuleTargetRooms,
+	SoftRuleTargetInvigilations,
+}
+
+func (e SoftRuleTarget) IsValid() bool {
+	switch e {
+	case SoftRuleTargetExams, SoftRuleTargetRooms, SoftRuleTargetInvigilations:
+		return true
+	}
+	return false
+}
+
+func (e SoftRuleTarget) String() string {
+	return string(e)
+}
+
+func (e *SoftRuleTarget) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SoftRuleTarget(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SoftRuleTarget", str)
+	}
+	return nil
+}
+
+func (e SoftRuleTarget) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SoftRuleTarget) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SoftRuleTarget) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SolverRunStatus string
+
+const (
+	SolverRunStatusRunning  SolverRunStatus = "RUNNING"
+	SolverRunStatusFinished SolverRunStatus = "FINISHED"
+	SolverRunStatusFailed   SolverRunStatus = "FAILED"
+	// Was RUNNING when the server stopped; can be resumed.
+	SolverRunStatusInterrupted SolverRunStatus = "INTERRUPTED"
+)
+
+var AllSolverRunStatus = []SolverRunStatus{
+	SolverRunStatusRunning,
+	SolverRunStatusFinished,
+	SolverRunStatusFailed,
+	SolverRunStatusInterrupted,
+}
+
+func (e SolverRunStatus) IsValid() bool {
+	switch e {
+	case SolverRunStatusRunning, SolverRunStatusFinished, SolverRunStatusFailed, SolverRunStatusInterrupted:
+		return true
+	}
+	return false
+}
+
+func (e SolverRunStatus) String() string {
+	return string(e)
+}
+
+func (e *SolverRunStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SolverRunStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SolverRunStatus", str)
+	}
+	return nil
+}
+
+func (e SolverRunStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SolverRunStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SolverRunStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// The exam type a staffing rule applies to, taken from the room constraints of the exams in
+// the room. ANY matches every room.
+type StaffingExamType string
+
+const (
+	StaffingExamTypeAny   StaffingExamType = "ANY"
+	StaffingExamTypeExahm StaffingExamType = "EXAHM"
+	StaffingExamTypeSeb   StaffingExamType = "SEB"
+	StaffingExamTypeLab   StaffingExamType = "LAB"
+)
+
+var AllStaffingExamType = []StaffingExamType{
+	StaffingExamTypeAny,
+	StaffingExamTypeExahm,
+	StaffingExamTypeSeb,
+	StaffingExamTypeLab,
+}
+
+func (e StaffingExamType) IsValid() bool {
+	switch e {
+	case StaffingExamTypeAny, StaffingExamTypeExahm, StaffingExamTypeSeb, StaffingExamTypeLab:
+		return true
+	}
+	return false
+}
+
+func (e StaffingExamType) String() string {
+	return string(e)
+}
+
+func (e *StaffingExamType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StaffingExamType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StaffingExamType", str)
+	}
+	return nil
+}
+
+func (e StaffingExamType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *StaffingExamType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e StaffingExamType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 // ValidationLevel classifies a single validation finding.
 type ValidationLevel string
 
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// RoomOutage is a room that becomes unavailable for a time range, with the repair of the
+// room plan for the exams that used it then. It is stored as a preview and applied on
+// confirmation; the repaired rows are kept with it (not exposed via GraphQL).
+type RoomOutage struct {
+	ID             string                    `json:"id" bson:"_id"`
+	Room           string                    `json:"room" bson:"room"`
+	From           time.Time                 `json:"from" bson:"from"`
+	Until          time.Time                 `json:"until" bson:"until"`
+	Reason         *string                   `json:"reason,omitempty" bson:"reason,omitempty"`
+	Starttimes     []time.Time               `json:"starttimes" bson:"starttimes"`
+	Exams          []*RoomOutageExam         `json:"exams" bson:"exams"`
+	Invigilations  []*RoomOutageInvigilation `json:"invigilations" bson:"invigilations"`
+	UnplacedSeats  int                       `json:"unplacedSeats" bson:"unplacedseats"`
+	HardViolations []string                  `json:"hardViolations" bson:"hardviolations"`
+	Notes          []string                  `json:"notes" bson:"notes"`
+	CreatedAt      time.Time                 `json:"createdAt" bson:"createdat"`
+	ConfirmedAt    *time.Time                `json:"confirmedAt,omitempty" bson:"confirmedat,omitempty"`
+
+	// PlannedRooms / Unplaced are the repaired rows of the affected exams, replacing
+	// theirs on confirmation; PrePlannedDropped are their pre-planned rooms in the
+	// blocked room. Fingerprint identifies the room plan the repair started from.
+	PlannedRooms      []*PlannedRoom    `json:"-" bson:"plannedrooms"`
+	Unplaced          []*UnplacedExam   `json:"-" bson:"unplaced"`
+	PrePlannedDropped []*PrePlannedRoom `json:"-" bson:"preplanneddropped"`
+	Fingerprint       string            `json:"-" bson:"fingerprint"`
+}
+
+// RoomChangeNotification is a queued email to one examiner or invigilator about the rooms
+// a confirmed room outage changed for them.
+type RoomChangeNotification struct {
+	ID          string         `json:"id" bson:"_id"`
+	OutageID    string         `json:"outageID" bson:"outageid"`
+	Room        string         `json:"room" bson:"room"`
+	Reason      *string        `json:"reason,omitempty" bson:"reason,omitempty"`
+	TeacherID   int            `json:"teacherID" bson:"teacherid"`
+	TeacherName string         `json:"teacherName" bson:"teachername"`
+	Role        RoomChangeRole `json:"role" bson:"role"`
+	Lines       []string       `json:"lines" bson:"lines"`
+	CreatedAt   time.Time      `json:"createdAt" bson:"createdat"`
+	SentAt      *time.Time     `json:"sentAt,omitempty" bson:"sentat,omitempty"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// SolverRun is a persisted (checkpointed) optimizer run (per semester, solver_runs).
+// The best assignment and the position of the random stream are stored with it, so
+// the run can be resumed after a server restart or continued with more iterations.
+// Assign and the rand state are internal and not exposed by the API.
+type SolverRun struct {
+	ID          string          `json:"id" bson:"_id"`
+	Kind        string          `json:"kind" bson:"kind"`
+	Status      SolverRunStatus `json:"status" bson:"status"`
+	DryRun      bool            `json:"dryRun" bson:"dryRun"`
+	Seed        int64           `json:"seed" bson:"seed"`
+	StartTemp   float64         `json:"startTemp" bson:"startTemp"`
+	EndTemp     float64         `json:"endTemp" bson:"endTemp"`
+	Iteration   int             `json:"iteration" bson:"iteration"`
+	Iterations  int             `json:"iterations" bson:"iterations"`
+	BestCost    float64         `json:"bestCost" bson:"bestCost"`
+	BestIter    int             `json:"bestIter" bson:"bestIter"`
+	Fingerprint string          `json:"fingerprint" bson:"fingerprint"`
+	StartedAt   time.Time       `json:"startedAt" bson:"startedAt"`
+	UpdatedAt   time.Time       `json:"updatedAt" bson:"updatedAt"`
+	Error       *string         `json:"error,omitempty" bson:"error,omitempty"`
+
+	Assign    []int `json:"-" bson:"assign"`
+	RandSeed  int64 `json:"-" bson:"randSeed"`
+	RandDraws int64 `json:"-" bson:"randDraws"`
+}
@@ -0,0 +1,25 @@
+package model
+
+// StudentHistory is the exam history of one student across the earlier semester
+// workspaces. The student is only known by a pseudonym (keyed hash of the Matrikelnummer),
+// so the global history holds no name or Matrikelnummer.
+type StudentHistory struct {
+	Pseudonym string                  `json:"pseudonym" bson:"pseudonym"`
+	Records   []*StudentHistoryRecord `json:"records" bson:"records"`
+}
+
+// StudentHistoryRecord is one registration of a student in an earlier semester. Sat is
+// only set when the attendance of that exam was imported.
+type StudentHistoryRecord struct {
+	Workspace string `json:"workspace" bson:"workspace"`
+	Ancode    int    `json:"ancode" bson:"ancode"`
+	Module    string `json:"module" bson:"module"`
+	Sat       *bool  `json:"sat,omitempty" bson:"sat,omitempty"`
+}
+
+// StudentAttendance records whether a registered student sat an exam of the semester.
+type StudentAttendance struct {
+	Mtknr   string `json:"mtknr" bson:"mtknr"`
+	Ancode  int    `json:"ancode" bson:"ancode"`
+	Present bool   `json:"present" bson:"present"`
+}
@@ -31,4 +31,16 @@ type StudyProgram struct {
 	// "MUC.DAI" | "MUC.HEALTH"). Set exactly for category "joint" programs; nil
 	// otherwise. Used to group joint programs for import/display.
 	JointFaculty *string `json:"jointFaculty,omitempty" bson:"jointFaculty,omitempty"`
+	// Curriculum is the study plan (module handbook) of the program, imported from a
+	// table (see plexams/curriculum). Required modules of the same semester are expected
+	// to be taken together.
+	Curriculum []*CurriculumModule `json:"curriculum,omitempty" bson:"curriculum,omitempty"`
+}
+
+// CurriculumModule is a module of a study plan in its planned semester.
+type CurriculumModule struct {
+	Semester int    `json:"semester" bson:"semester"`
+	Module   string `json:"module" bson:"module"`
+	// Required is true for a Pflichtmodul, false for a Wahlpflichtmodul.
+	Required bool `json:"required" bson:"required"`
 }
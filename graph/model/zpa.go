@@ -113,10 +113,13 @@ type ZPAExamPlan struct {
 type ZPAExamPlanRoom struct {
 	RoomName      string `json:"room_name"`
 	InvigilatorID int    `json:"invigilator_id"`
-	Duration      int    `json:"duration"`
-	IsReserve     bool   `json:"reserveRoom"`
-	StudentCount  int    `json:"numberStudents"`
-	IsHandicap    bool   `json:"handicapCompensation"`
+	// AdditionalInvigilatorIDs are the further invigilators of a room staffed by
+	// several (staffing rules); omitted for the usual single invigilator.
+	AdditionalInvigilatorIDs []int `json:"additional_invigilator_ids,omitempty"`
+	Duration                 int   `json:"duration"`
+	IsReserve                bool  `json:"reserveRoom"`
+	StudentCount             int   `json:"numberStudents"`
+	IsHandicap               bool  `json:"handicapCompensation"`
 }
 
 type ZPAStudent struct {
@@ -0,0 +1,16 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// RegistrationForecast is the resolver for the registrationForecast field.
+func (r *queryResolver) RegistrationForecast(ctx context.Context, semesters *int) (*model.RegistrationForecast, error) {
+	return r.plexams.RegistrationForecast(ctx, semesters)
+}
@@ -30,8 +30,8 @@ func (r *mutationResolver) BlockRoomAt(ctx context.Context, room string, startti
 }
 
 // UnblockRoomAt is the resolver for the unblockRoomAt field.
-func (r *mutationResolver) UnblockRoomAt(ctx context.Context, room string, starttime time.Time) (bool, error) {
-	return r.plexams.UnblockRoomForSlot(ctx, room, starttime)
+func (r *mutationResolver) UnblockRoomAt(ctx context.Context, room string, starttime time.Time, until *time.Time) (bool, error) {
+	return r.plexams.UnblockRoomForSlot(ctx, room, starttime, until)
 }
 
 // BlockRoomAtTimes is the resolver for the blockRoomAtTimes field.
@@ -57,7 +57,7 @@ func (r *mutationResolver) UnblockRoomAtTimes(ctx context.Context, room string,
 		if st == nil {
 			continue
 		}
-		if ok, err := r.plexams.UnblockRoomForSlot(ctx, room, *st); err != nil {
+		if ok, err := r.plexams.UnblockRoomForSlot(ctx, room, *st, nil); err != nil {
 			return removed, err
 		} else if ok {
 			removed++
@@ -156,7 +156,10 @@ func (r *queryResolver) UnplacedExams(ctx context.Context) ([]*model.UnplacedExa
 
 // RoomPlanConstraints is the resolver for the roomPlanConstraints field.
 func (r *queryResolver) RoomPlanConstraints(ctx context.Context) ([]*model.OptimizerConstraint, error) {
-	infos := r.plexams.RoomPlanConstraints()
+	infos, err := r.plexams.RoomPlanConstraints(ctx)
+	if err != nil {
+		return nil, err
+	}
 	out := make([]*model.OptimizerConstraint, 0, len(infos))
 	for _, i := range infos {
 		out = append(out, &model.OptimizerConstraint{
@@ -167,6 +170,11 @@ func (r *queryResolver) RoomPlanConstraints(ctx context.Context) ([]*model.Optim
 	return out, nil
 }
 
+// RoomFeatures is the resolver for the roomFeatures field.
+func (r *queryResolver) RoomFeatures(ctx context.Context) ([]*model.RoomFeatures, error) {
+	return r.plexams.RoomFeatures(ctx)
+}
+
 // Rooms is the resolver for the rooms field.
 func (r *roomsForSlotResolver) Rooms(ctx context.Context, obj *model.RoomsForSlot) ([]*model.Room, error) {
 	return r.plexams.RoomsFromRoomNames(ctx, obj.RoomNames)
@@ -0,0 +1,45 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams"
+)
+
+// PreviewRoomOutage is the resolver for the previewRoomOutage field.
+func (r *mutationResolver) PreviewRoomOutage(ctx context.Context, room string, from time.Time, until time.Time, reason *string) (*model.RoomOutage, error) {
+	return r.plexams.PreviewRoomOutage(ctx, room, from, until, reason)
+}
+
+// ConfirmRoomOutage is the resolver for the confirmRoomOutage field.
+func (r *mutationResolver) ConfirmRoomOutage(ctx context.Context, id string) (*model.RoomOutage, error) {
+	return r.plexams.ConfirmRoomOutage(ctx, id)
+}
+
+// DiscardRoomOutage is the resolver for the discardRoomOutage field.
+func (r *mutationResolver) DiscardRoomOutage(ctx context.Context, id string) (bool, error) {
+	return r.plexams.DiscardRoomOutage(ctx, id)
+}
+
+// RoomOutages is the resolver for the roomOutages field.
+func (r *queryResolver) RoomOutages(ctx context.Context) ([]*model.RoomOutage, error) {
+	return r.plexams.RoomOutages(ctx)
+}
+
+// RoomChangeNotifications is the resolver for the roomChangeNotifications field.
+func (r *queryResolver) RoomChangeNotifications(ctx context.Context, pendingOnly *bool) ([]*model.RoomChangeNotification, error) {
+	return r.plexams.RoomChangeNotifications(ctx, pendingOnly != nil && *pendingOnly)
+}
+
+// SendEmailRoomChanges is the resolver for the sendEmailRoomChanges field.
+func (r *subscriptionResolver) SendEmailRoomChanges(ctx context.Context, run bool) (<-chan *model.LogLine, error) {
+	return r.runEmailOp(ctx, run, func(ctx context.Context, reporter plexams.Reporter) error {
+		return r.plexams.SendEmailRoomChanges(ctx, run, reporter)
+	}), nil
+}
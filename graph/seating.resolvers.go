@@ -0,0 +1,47 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// SetRoomLayout is the resolver for the setRoomLayout field.
+func (r *mutationResolver) SetRoomLayout(ctx context.Context, input model.RoomLayoutInput) (*model.RoomLayout, error) {
+	return r.plexams.SetRoomLayout(ctx, input)
+}
+
+// RemoveRoomLayout is the resolver for the removeRoomLayout field.
+func (r *mutationResolver) RemoveRoomLayout(ctx context.Context, room string) (bool, error) {
+	return r.plexams.RemoveRoomLayout(ctx, room)
+}
+
+// RebalanceNameRanges is the resolver for the rebalanceNameRanges field.
+func (r *mutationResolver) RebalanceNameRanges(ctx context.Context, ancode *int) ([]*model.ExamNameRanges, error) {
+	return r.plexams.RebalanceNameRanges(ctx, ancode)
+}
+
+// RoomLayouts is the resolver for the roomLayouts field.
+func (r *queryResolver) RoomLayouts(ctx context.Context) ([]*model.RoomLayout, error) {
+	return r.plexams.RoomLayouts(ctx)
+}
+
+// SeatingPlan is the resolver for the seatingPlan field.
+func (r *queryResolver) SeatingPlan(ctx context.Context, room string, starttime time.Time) (*model.SeatingPlan, error) {
+	return r.plexams.SeatingPlan(ctx, room, starttime)
+}
+
+// SeatingPlansForExam is the resolver for the seatingPlansForExam field.
+func (r *queryResolver) SeatingPlansForExam(ctx context.Context, ancode int) ([]*model.SeatingPlan, error) {
+	return r.plexams.SeatingPlans(ctx, ancode)
+}
+
+// ExamNameRanges is the resolver for the examNameRanges field.
+func (r *queryResolver) ExamNameRanges(ctx context.Context, ancode *int) ([]*model.ExamNameRanges, error) {
+	return r.plexams.ExamNameRanges(ctx, ancode)
+}
@@ -155,9 +155,39 @@ func StartServer(plexams *plexams.Plexams, port string) {
 	// Generated documents (formerly the pdf/csv/ics CLI commands): draft plans and
 	// exports for the faculties/examers. Read-only, so no write gating.
 	router.Get("/download/pdf/{kind}", plexams.HTTPDownloadPDF)
+	router.Get("/download/pdf/placement/{ancode}", plexams.HTTPDownloadPlacementPDF)
+	router.Get("/download/pdf/exam-day/{date}", plexams.HTTPDownloadExamDayProtocolPDF)
 	router.Get("/download/csv/{kind}", plexams.HTTPDownloadCSVDraft)
 	router.Get("/download/ics/{program}", plexams.HTTPDownloadICS)
 
+	// Exact-solver exchange: a planning problem as LP/MiniZinc model, and the
+	// solution of the offline solver, validated and applied.
+	router.Get("/download/solver/{problem}/{format}", plexams.HTTPDownloadSolverModel)
+	router.Post("/upload/solver-solution/{problem}", plexams.HTTPUploadSolverSolution)
+
+	// Room-request exchange with the Gebäudemanagement: the requests as table (with an
+	// answer column) and ICS invites, and the annotated table back (?dryRun=true = diff).
+	router.Get("/download/room-requests/{format}", plexams.HTTPDownloadRoomRequests)
+	router.Post("/upload/room-request-answers", plexams.HTTPUploadRoomRequestAnswers)
+
+	// Invigilator availability from a teacher's calendar export (ICS / free-busy):
+	// busy time on exam days becomes unavailability (?dryRun=true = preview).
+	router.Post("/upload/invigilator-availability", plexams.HTTPUploadInvigilatorAvailability)
+
+	// Exam protocols after the exam: the table of all planned exam rooms (prefilled with
+	// the recorded protocols) and the filled-in table back (?dryRun=true = diff).
+	router.Get("/download/exam-protocols.csv", plexams.HTTPDownloadExamProtocolsTemplate)
+	router.Post("/upload/exam-protocols", plexams.HTTPUploadExamProtocols)
+
+	// Study plans (module handbooks) of the study programs, global master data: the
+	// table Studiengang;Fachsemester;Modul;Pflicht (?dryRun=true for a preview).
+	router.Get("/download/curriculum.csv", plexams.HTTPDownloadCurriculum)
+	router.Post("/upload/curriculum", plexams.HTTPUploadCurriculum)
+
+	// Attendance of the semester's exams per student (Ancode;Mtknr;anwesend, ?dryRun=true
+	// for a preview), read by later semesters into the pseudonymized student history.
+	router.Post("/upload/student-attendance", plexams.HTTPUploadStudentAttendance)
+
 	// Backup/restore: whole-semester clone (ZIP) and per-page datasets (JSON), so a
 	// semester can be dumped and re-uploaded into a fresh workspace for testing.
 	router.Get("/download/semester-dump.zip", plexams.HTTPDownloadSemesterDump)
@@ -0,0 +1,25 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// ResumeInvigilationRun is the resolver for the resumeInvigilationRun field.
+func (r *mutationResolver) ResumeInvigilationRun(ctx context.Context, id string, additionalIterations *int, dryRun bool) (*model.SolverRun, error) {
+	additional := 0
+	if additionalIterations != nil {
+		additional = *additionalIterations
+	}
+	return r.plexams.ResumeInvigilationRun(ctx, id, additional, dryRun)
+}
+
+// SolverRuns is the resolver for the solverRuns field.
+func (r *queryResolver) SolverRuns(ctx context.Context) ([]*model.SolverRun, error) {
+	return r.plexams.SolverRuns(ctx)
+}
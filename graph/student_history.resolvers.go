@@ -0,0 +1,16 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// RebuildStudentHistory is the resolver for the rebuildStudentHistory field.
+func (r *mutationResolver) RebuildStudentHistory(ctx context.Context, semesters *int) (*model.StudentHistorySummary, error) {
+	return r.plexams.RebuildStudentHistory(ctx, semesters)
+}
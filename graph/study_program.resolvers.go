@@ -42,3 +42,8 @@ func (r *mutationResolver) SeedStudyProgramsFromConfig(ctx context.Context) (int
 func (r *queryResolver) StudyPrograms(ctx context.Context) ([]*model.StudyProgram, error) {
 	return r.plexams.StudyPrograms(ctx)
 }
+
+// CurriculumConflicts is the resolver for the curriculumConflicts field.
+func (r *queryResolver) CurriculumConflicts(ctx context.Context) ([]*model.CurriculumConflict, error) {
+	return r.plexams.CurriculumConflicts(ctx)
+}
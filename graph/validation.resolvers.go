@@ -103,6 +103,11 @@ func (r *subscriptionResolver) ValidateSemesterTimes(ctx context.Context) (<-cha
 	return r.runValidation(ctx, "semester-times", r.plexams.ValidateSemesterTimes), nil
 }
 
+// ValidateCurriculum is the resolver for the validateCurriculum field.
+func (r *subscriptionResolver) ValidateCurriculum(ctx context.Context) (<-chan *model.LogLine, error) {
+	return r.runValidation(ctx, "curriculum", r.plexams.ValidateCurriculum), nil
+}
+
 // ValidateDBPlanEntries is the resolver for the validateDBPlanEntries field.
 func (r *subscriptionResolver) ValidateDBPlanEntries(ctx context.Context) (<-chan *model.LogLine, error) {
 	return r.runValidation(ctx, "db-plan-entries", r.plexams.ValidateDBPlanEntries), nil
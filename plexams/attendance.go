@@ -0,0 +1,169 @@
+package plexams
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/pdfgen"
+	"github.com/obcode/plexams.go/plexams/seatplan"
+)
+
+// Attendance lists: one sheet per exam and planned room (reserve rooms without students
+// are skipped) with the students in name order, their seat if the room has a seating plan
+// and an empty signature column. As one PDF (pdfExports "attendance-lists"), as a ZIP
+// with one PDF per examer ("attendance-lists-by-examer") and optionally attached to the
+// cover-page email.
+
+// examerSheets is the attendance sheets of one examer.
+type examerSheets struct {
+	examerID int
+	examer   string
+	sheets   []pdfgen.AttendanceSheet
+}
+
+// attendanceSheets builds the sheets of all exams (examerID 0) or of one examer, grouped
+// by examer (sorted by name) and per examer sorted by start time, ancode and room.
+func (p *Plexams) attendanceSheets(ctx context.Context, examerID int) ([]*examerSheets, error) {
+	exams, err := p.PlannedExams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	all, err := p.StudentRegsPerStudentPlanned(ctx)
+	if err != nil {
+		return nil, err
+	}
+	students := make(map[string]*model.Student, len(all))
+	for _, s := range all {
+		students[s.Mtknr] = s
+	}
+	plans, err := p.SeatingPlans(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	type seatKey struct {
+		room  string
+		start int64
+		mtknr string
+	}
+	seats := make(map[seatKey]string)
+	for _, plan := range plans {
+		for _, s := range plan.Seats {
+			seats[seatKey{plan.Room, plan.Starttime.Unix(), s.Mtknr}] = fmt.Sprintf("%d/%d", s.Row, s.Seat)
+		}
+	}
+
+	byExamer := make(map[int]*examerSheets)
+	for _, exam := range exams {
+		if exam.ZpaExam == nil || (examerID > 0 && exam.ZpaExam.MainExamerID != examerID) {
+			continue
+		}
+		for _, room := range exam.PlannedRooms {
+			if room.Starttime == nil || len(room.StudentsInRoom) == 0 {
+				continue
+			}
+			sheet := pdfgen.AttendanceSheet{
+				Ancode: exam.Ancode, Module: exam.ZpaExam.Module, MainExamer: exam.ZpaExam.MainExamer,
+				Room: room.RoomName, Starttime: *room.Starttime, Duration: room.Duration, Handicap: room.Handicap,
+				Students: make([]pdfgen.AttendanceStudent, 0, len(room.StudentsInRoom)),
+			}
+			for _, mtknr := range room.StudentsInRoom {
+				st := pdfgen.AttendanceStudent{Mtknr: mtknr, Seat: seats[seatKey{room.RoomName, room.Starttime.Unix(), mtknr}]}
+				if s, ok := students[mtknr]; ok {
+					st.Name, st.Program, st.NTA = s.Name, s.Program, s.Nta != nil
+				}
+				sheet.Students = append(sheet.Students, st)
+			}
+			sort.SliceStable(sheet.Students, func(i, j int) bool {
+				return seatplan.NameKey(sheet.Students[i].Name) < seatplan.NameKey(sheet.Students[j].Name)
+			})
+			es, ok := byExamer[exam.ZpaExam.MainExamerID]
+			if !ok {
+				es = &examerSheets{examerID: exam.ZpaExam.MainExamerID, examer: exam.ZpaExam.MainExamer}
+				byExamer[es.examerID] = es
+			}
+			es.sheets = append(es.sheets, sheet)
+		}
+	}
+
+	out := make([]*examerSheets, 0, len(byExamer))
+	for _, es := range byExamer {
+		sort.Slice(es.sheets, func(i, j int) bool {
+			a, b := es.sheets[i], es.sheets[j]
+			if !a.Starttime.Equal(b.Starttime) {
+				return a.Starttime.Before(b.Starttime)
+			}
+			if a.Ancode != b.Ancode {
+				return a.Ancode < b.Ancode
+			}
+			return a.Room < b.Room
+		})
+		out = append(out, es)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].examer < out[j].examer })
+	return out, nil
+}
+
+// AttendanceListsPDFBytes builds all attendance sheets as one PDF (sorted by start time).
+func (p *Plexams) AttendanceListsPDFBytes(ctx context.Context) ([]byte, error) {
+	byExamer, err := p.attendanceSheets(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	sheets := make([]pdfgen.AttendanceSheet, 0)
+	for _, es := range byExamer {
+		sheets = append(sheets, es.sheets...)
+	}
+	sort.SliceStable(sheets, func(i, j int) bool {
+		if !sheets[i].Starttime.Equal(sheets[j].Starttime) {
+			return sheets[i].Starttime.Before(sheets[j].Starttime)
+		}
+		return sheets[i].Room < sheets[j].Room
+	})
+	return marotoBytes(pdfgen.AttendanceSheets(p.semesterFull(), sheets))
+}
+
+// AttendanceListsZipBytes builds a ZIP with one attendance-list PDF per examer.
+func (p *Plexams) AttendanceListsZipBytes(ctx context.Context) ([]byte, error) {
+	byExamer, err := p.attendanceSheets(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, es := range byExamer {
+		data, err := marotoBytes(pdfgen.AttendanceSheets(p.semesterFull(), es.sheets))
+		if err != nil {
+			return nil, fmt.Errorf("attendance lists of %s: %w", es.examer, err)
+		}
+		f, err := zw.Create(attendanceFilename(es.examer))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// attendanceListsForExamer returns the examer's attendance lists as PDF, nil if the
+// examer has no planned rooms yet.
+func (p *Plexams) attendanceListsForExamer(ctx context.Context, examerID int) ([]byte, error) {
+	byExamer, err := p.attendanceSheets(ctx, examerID)
+	if err != nil || len(byExamer) == 0 {
+		return nil, err
+	}
+	return marotoBytes(pdfgen.AttendanceSheets(p.semesterFull(), byExamer[0].sheets))
+}
+
+func attendanceFilename(examer string) string {
+	return strings.NewReplacer(" ", "_", ",", "", "/", "-").Replace("Anwesenheitslisten_"+examer) + ".pdf"
+}
@@ -0,0 +1,213 @@
+// Package campus models where rooms are: campuses, the buildings on them and the floor
+// of a room, plus the travel times between campuses. It is DB-agnostic; the plexams
+// package builds a *Model from the stored campuses, buildings, room overrides and the
+// travel-time matrix and hands it to the solvers (examplan: travel gap between a
+// student's exams, roomplan: building and floor of a room, invigplan: travel between
+// two invigilations of one person).
+//
+// A room's building and floor are parsed from its name ("R1.006" → building R, floor
+// 1) unless an explicit room location overrides them; the building's campus is looked
+// up in the building list, falling back to the default campus. Exam locations
+// (Constraints.Location) name a campus; "" is the default campus.
+package campus
+
+import (
+	"strings"
+)
+
+// Campus is one site of the university.
+type Campus struct {
+	Name    string
+	Default bool // the campus of an exam without location and of unknown buildings
+}
+
+// Building is a building on a campus, identified by the prefix of its room names.
+type Building struct {
+	Name   string // "R", "T", …
+	Campus string
+}
+
+// RoomLocation overrides the building and floor parsed from a room's name.
+type RoomLocation struct {
+	Room     string
+	Building string
+	Floor    int
+}
+
+// Travel is the travel time between two campuses (symmetric).
+type Travel struct {
+	From, To string
+	Minutes  int
+}
+
+// Location is where a room is.
+type Location struct {
+	Campus   string
+	Building string
+	Floor    int
+}
+
+// Model answers "where is this room" and "how long from here to there".
+type Model struct {
+	defaultCampus string
+	campuses      map[string]string // folded name → name
+	buildings     map[string]string // building → campus
+	rooms         map[string]RoomLocation
+	travel        map[[2]string]int
+	fallback      int
+}
+
+// New builds the model. fallback is the travel time between two different campuses
+// without an entry in the matrix (the semester's crossCampusGapMinutes).
+func New(campuses []Campus, buildings []Building, rooms []RoomLocation, travel []Travel, fallback int) *Model {
+	m := &Model{
+		campuses:  make(map[string]string, len(campuses)),
+		buildings: make(map[string]string, len(buildings)),
+		rooms:     make(map[string]RoomLocation, len(rooms)),
+		travel:    make(map[[2]string]int, 2*len(travel)),
+		fallback:  fallback,
+	}
+	for _, c := range campuses {
+		m.campuses[fold(c.Name)] = c.Name
+		if c.Default && m.defaultCampus == "" {
+			m.defaultCampus = c.Name
+		}
+	}
+	if m.defaultCampus == "" && len(campuses) > 0 {
+		m.defaultCampus = campuses[0].Name // no default marked: the first one
+	}
+	for _, b := range buildings {
+		m.buildings[b.Name] = m.Campus(b.Campus)
+	}
+	for _, r := range rooms {
+		m.rooms[r.Room] = r
+	}
+	for _, t := range travel {
+		a, b := m.Campus(t.From), m.Campus(t.To)
+		m.travel[[2]string{a, b}] = t.Minutes
+		m.travel[[2]string{b, a}] = t.Minutes
+	}
+	return m
+}
+
+// DefaultCampus is the name of the default campus ("" without campuses).
+func (m *Model) DefaultCampus() string {
+	if m == nil {
+		return ""
+	}
+	return m.defaultCampus
+}
+
+// Campus normalizes an exam location to a campus name: "" is the default campus, a
+// known campus matches case-insensitively, anything else is returned unchanged.
+func (m *Model) Campus(location string) string {
+	if m == nil {
+		return location
+	}
+	if strings.TrimSpace(location) == "" {
+		return m.defaultCampus
+	}
+	if name, ok := m.campuses[fold(location)]; ok {
+		return name
+	}
+	return location
+}
+
+// Known reports whether a location names a known campus ("" always does).
+func (m *Model) Known(location string) bool {
+	if strings.TrimSpace(location) == "" {
+		return true
+	}
+	if m == nil {
+		return false
+	}
+	_, ok := m.campuses[fold(location)]
+	return ok
+}
+
+// TravelMinutes is the travel time between two locations: 0 on the same campus, the
+// matrix entry, or the fallback for two different campuses without an entry.
+func (m *Model) TravelMinutes(a, b string) int {
+	if m == nil {
+		return 0
+	}
+	ca, cb := m.Campus(a), m.Campus(b)
+	if ca == cb {
+		return 0
+	}
+	if minutes, ok := m.travel[[2]string{ca, cb}]; ok {
+		return minutes
+	}
+	return m.fallback
+}
+
+// Room returns the location of a room: the explicit override, else building and floor
+// parsed from the name; the campus comes from the building (default campus when the
+// building is unknown).
+func (m *Model) Room(name string) Location {
+	loc := Location{Building: BuildingFromName(name), Floor: FloorFromName(name)}
+	if m == nil {
+		return loc
+	}
+	if r, ok := m.rooms[name]; ok {
+		loc.Building, loc.Floor = r.Building, r.Floor
+	}
+	loc.Campus = m.defaultCampus
+	if c, ok := m.buildings[loc.Building]; ok {
+		loc.Campus = c
+	}
+	return loc
+}
+
+// BuildingFromName is the letter prefix of a room name followed by the floor digits,
+// e.g. "R1.006" → "R", "T3.021" → "T"; "" for names without that pattern ("Online").
+func BuildingFromName(name string) string {
+	b, _, ok := splitName(name)
+	if !ok {
+		return ""
+	}
+	return b
+}
+
+// FloorFromName is the floor of a room name of the form "<building><floor>.<room>",
+// e.g. "R2.007" → 2, "T3.021" → 3, "E-1.10" → -1; 0 for names without that pattern.
+func FloorFromName(name string) int {
+	_, floor, ok := splitName(name)
+	if !ok {
+		return 0
+	}
+	return floor
+}
+
+func splitName(name string) (building string, floor int, ok bool) {
+	i := 0
+	for i < len(name) && isLetter(name[i]) {
+		i++
+	}
+	if i == 0 {
+		return "", 0, false
+	}
+	building = strings.ToUpper(name[:i])
+	rest := name[i:]
+	sign := 1
+	if strings.HasPrefix(rest, "-") {
+		sign, rest = -1, rest[1:]
+	}
+	j := 0
+	for j < len(rest) && rest[j] >= '0' && rest[j] <= '9' {
+		floor = floor*10 + int(rest[j]-'0')
+		j++
+	}
+	if j == 0 || j >= len(rest) || rest[j] != '.' {
+		return "", 0, false
+	}
+	return building, sign * floor, true
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+func fold(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
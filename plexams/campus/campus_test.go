@@ -0,0 +1,77 @@
+package campus
+
+import "testing"
+
+func testModel() *Model {
+	return New(
+		[]Campus{{Name: "Lothstraße", Default: true}, {Name: "Pasing"}, {Name: "Karlstraße"}},
+		[]Building{{Name: "R", Campus: "Lothstraße"}, {Name: "T", Campus: "lothstraße"}, {Name: "P", Campus: "Pasing"}},
+		[]RoomLocation{{Room: "Aula", Building: "P", Floor: 1}},
+		[]Travel{{From: "Lothstraße", To: "Pasing", Minutes: 45}},
+		120,
+	)
+}
+
+func TestNameParsing(t *testing.T) {
+	for name, want := range map[string]struct {
+		building string
+		floor    int
+	}{
+		"R1.006": {"R", 1}, "r2.007": {"R", 2}, "T3.021": {"T", 3}, "E-1.10": {"E", -1},
+		"R12.001": {"R", 12}, "R0.011": {"R", 0}, "Online": {"", 0}, "ONLINE_1": {"", 0},
+		"R1": {"", 0}, "1.006": {"", 0}, "RX.001": {"", 0}, "": {"", 0},
+	} {
+		if got := BuildingFromName(name); got != want.building {
+			t.Errorf("BuildingFromName(%q) = %q, want %q", name, got, want.building)
+		}
+		if got := FloorFromName(name); got != want.floor {
+			t.Errorf("FloorFromName(%q) = %d, want %d", name, got, want.floor)
+		}
+	}
+}
+
+func TestRoom(t *testing.T) {
+	m := testModel()
+	for name, want := range map[string]Location{
+		"R1.006": {Campus: "Lothstraße", Building: "R", Floor: 1},
+		"T3.021": {Campus: "Lothstraße", Building: "T", Floor: 3},
+		"P0.100": {Campus: "Pasing", Building: "P", Floor: 0},
+		"Aula":   {Campus: "Pasing", Building: "P", Floor: 1},     // override
+		"X2.001": {Campus: "Lothstraße", Building: "X", Floor: 2}, // unknown building → default campus
+	} {
+		if got := m.Room(name); got != want {
+			t.Errorf("Room(%q) = %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+func TestTravelMinutes(t *testing.T) {
+	m := testModel()
+	for _, c := range []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "Lothstraße", 0}, // "" is the default campus
+		{"", "pasing", 45},
+		{"Pasing", "", 45}, // symmetric
+		{"Pasing", "Karlstraße", 120},
+		{"", "Garching", 120}, // unknown location: fallback
+	} {
+		if got := m.TravelMinutes(c.a, c.b); got != c.want {
+			t.Errorf("TravelMinutes(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+	if !m.Known("PASING") || !m.Known("") || m.Known("Garching") {
+		t.Errorf("Known: wrong result")
+	}
+
+	// without any campus data every differing location is the fallback apart
+	empty := New(nil, nil, nil, nil, 120)
+	if got := empty.TravelMinutes("", "Pasing"); got != 120 {
+		t.Errorf("empty model: TravelMinutes = %d, want 120", got)
+	}
+	if got := empty.TravelMinutes("Pasing", "Pasing"); got != 0 {
+		t.Errorf("empty model: same location = %d, want 0", got)
+	}
+}
@@ -0,0 +1,267 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/campus"
+)
+
+// Campus model: campuses, buildings, room locations and the travel-time matrix (all
+// global). campusModel turns them into the campus.Model the solvers and validations use.
+
+// campusModel builds the campus model from the DB. Two different campuses without a
+// travel time are crossCampusGapMinutes apart.
+func (p *Plexams) campusModel(ctx context.Context) (*campus.Model, error) {
+	campuses, err := p.dbClient.Campuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	buildings, err := p.dbClient.Buildings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	locations, err := p.dbClient.RoomLocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	travelTimes, err := p.dbClient.CampusTravelTimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cs := make([]campus.Campus, 0, len(campuses))
+	for _, c := range campuses {
+		cs = append(cs, campus.Campus{Name: c.Name, Default: c.IsDefault})
+	}
+	bs := make([]campus.Building, 0, len(buildings))
+	for _, b := range buildings {
+		bs = append(bs, campus.Building{Name: b.Name, Campus: b.Campus})
+	}
+	ls := make([]campus.RoomLocation, 0, len(locations))
+	for _, l := range locations {
+		ls = append(ls, campus.RoomLocation{Room: l.Room, Building: l.Building, Floor: l.Floor})
+	}
+	ts := make([]campus.Travel, 0, len(travelTimes))
+	for _, t := range travelTimes {
+		ts = append(ts, campus.Travel{From: t.From, To: t.To, Minutes: t.Minutes})
+	}
+	return campus.New(cs, bs, ls, ts, p.crossCampusGapMinutes()), nil
+}
+
+func (p *Plexams) CampusModel(ctx context.Context) (*model.CampusModel, error) {
+	campuses, err := p.dbClient.Campuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	buildings, err := p.dbClient.Buildings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	travelTimes, err := p.dbClient.CampusTravelTimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	locations, err := p.dbClient.RoomLocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &model.CampusModel{Campuses: campuses, Buildings: buildings, TravelTimes: travelTimes, RoomLocations: locations}, nil
+}
+
+// RoomSites returns campus, building and floor of every room.
+func (p *Plexams) RoomSites(ctx context.Context) ([]*model.RoomSite, error) {
+	m, err := p.campusModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := p.dbClient.Rooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	locations, err := p.dbClient.RoomLocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	override := make(map[string]bool, len(locations))
+	for _, l := range locations {
+		override[l.Room] = true
+	}
+	sites := make([]*model.RoomSite, 0, len(rooms))
+	for _, r := range rooms {
+		loc := m.Room(r.Name)
+		sites = append(sites, &model.RoomSite{
+			Room: r.Name, Campus: loc.Campus, Building: loc.Building, Floor: loc.Floor, Override: override[r.Name],
+		})
+	}
+	return sites, nil
+}
+
+// CampusTravelMinutes is the travel time between two exam locations.
+func (p *Plexams) CampusTravelMinutes(ctx context.Context, from, to string) (int, error) {
+	m, err := p.campusModel(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return m.TravelMinutes(from, to), nil
+}
+
+func (p *Plexams) SetCampus(ctx context.Context, name string, isDefault bool) (*model.Campus, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("a campus needs a name")
+	}
+	c := &model.Campus{Name: name, IsDefault: isDefault}
+	if err := p.dbClient.SetCampus(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// RemoveCampus removes a campus no building or travel time refers to.
+func (p *Plexams) RemoveCampus(ctx context.Context, name string) (bool, error) {
+	buildings, err := p.dbClient.Buildings(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, b := range buildings {
+		if b.Campus == name {
+			return false, fmt.Errorf("campus %s still has building %s", name, b.Name)
+		}
+	}
+	travelTimes, err := p.dbClient.CampusTravelTimes(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, t := range travelTimes {
+		if t.From == name || t.To == name {
+			return false, fmt.Errorf("campus %s still has a travel time to %s", name, otherCampus(t, name))
+		}
+	}
+	removed, err := p.dbClient.RemoveCampus(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	if !removed {
+		return false, fmt.Errorf("campus %s not found", name)
+	}
+	return true, nil
+}
+
+func otherCampus(t *model.CampusTravelTime, name string) string {
+	if t.From == name {
+		return t.To
+	}
+	return t.From
+}
+
+// knownCampus returns the stored name of a campus (case-insensitive match).
+func (p *Plexams) knownCampus(ctx context.Context, name string) (string, error) {
+	campuses, err := p.dbClient.Campuses(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range campuses {
+		if strings.EqualFold(c.Name, strings.TrimSpace(name)) {
+			return c.Name, nil
+		}
+	}
+	return "", fmt.Errorf("campus %s not found", name)
+}
+
+func (p *Plexams) SetBuilding(ctx context.Context, name, campusName string) (*model.Building, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "" {
+		return nil, fmt.Errorf("a building needs a name")
+	}
+	campusName, err := p.knownCampus(ctx, campusName)
+	if err != nil {
+		return nil, err
+	}
+	b := &model.Building{Name: name, Campus: campusName}
+	if err := p.dbClient.SetBuilding(ctx, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (p *Plexams) RemoveBuilding(ctx context.Context, name string) (bool, error) {
+	removed, err := p.dbClient.RemoveBuilding(ctx, strings.ToUpper(strings.TrimSpace(name)))
+	if err != nil {
+		return false, err
+	}
+	if !removed {
+		return false, fmt.Errorf("building %s not found", name)
+	}
+	return true, nil
+}
+
+func (p *Plexams) SetCampusTravelTime(ctx context.Context, from, to string, minutes int) (*model.CampusTravelTime, error) {
+	from, err := p.knownCampus(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	to, err = p.knownCampus(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	if from == to {
+		return nil, fmt.Errorf("travel time needs two different campuses")
+	}
+	if minutes < 0 {
+		return nil, fmt.Errorf("travel time must not be negative")
+	}
+	t := &model.CampusTravelTime{From: from, To: to, Minutes: minutes}
+	if err := p.dbClient.SetCampusTravelTime(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (p *Plexams) RemoveCampusTravelTime(ctx context.Context, from, to string) (bool, error) {
+	removed, err := p.dbClient.RemoveCampusTravelTime(ctx, from, to)
+	if err != nil {
+		return false, err
+	}
+	if !removed {
+		return false, fmt.Errorf("no travel time between %s and %s", from, to)
+	}
+	return true, nil
+}
+
+// SetRoomLocation overrides building and floor of an existing room; the building must
+// be known (its campus is the room's campus).
+func (p *Plexams) SetRoomLocation(ctx context.Context, room, building string, floor int) (*model.RoomLocation, error) {
+	if r, err := p.dbClient.RoomByName(ctx, room); err != nil || r == nil {
+		return nil, fmt.Errorf("room %s not found", room)
+	}
+	building = strings.ToUpper(strings.TrimSpace(building))
+	buildings, err := p.dbClient.Buildings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := false
+	for _, b := range buildings {
+		known = known || b.Name == building
+	}
+	if !known {
+		return nil, fmt.Errorf("building %s not found", building)
+	}
+	l := &model.RoomLocation{Room: room, Building: building, Floor: floor}
+	if err := p.dbClient.SetRoomLocation(ctx, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (p *Plexams) RemoveRoomLocation(ctx context.Context, room string) (bool, error) {
+	removed, err := p.dbClient.RemoveRoomLocation(ctx, room)
+	if err != nil {
+		return false, err
+	}
+	if !removed {
+		return false, fmt.Errorf("room %s has no location override", room)
+	}
+	return true, nil
+}
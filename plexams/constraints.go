@@ -2,9 +2,11 @@ package plexams
 
 import (
 	"context"
+	"fmt"
 	"sort"
 
 	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/roomcalc"
 	"github.com/rs/zerolog/log"
 )
 
@@ -108,7 +110,9 @@ func (p *Plexams) AddConstraints(ctx context.Context, ancode int, constraintsInp
 			constraintsInput.Lab != nil ||
 			constraintsInput.Seb != nil ||
 			constraintsInput.Exahm != nil ||
+			constraintsInput.RequiredTags != nil ||
 			constraintsInput.AdditionalSeats != nil ||
+			constraintsInput.ExpectedAttendance != nil ||
 			constraintsInput.PreExamMinutes != nil ||
 			constraintsInput.PostExamMinutes != nil ||
 			constraintsInput.AllowedRooms != nil {
@@ -136,6 +140,11 @@ func (p *Plexams) AddConstraints(ctx context.Context, ancode int, constraintsInp
 			if constraintsInput.Exahm != nil && *constraintsInput.Exahm {
 				constraints.RoomConstraints.Exahm = *constraintsInput.Exahm
 			}
+			requiredTags, err := roomcalc.NormalizeTags(constraintsInput.RequiredTags)
+			if err != nil {
+				return nil, fmt.Errorf("exam %d: %w", ancode, err)
+			}
+			constraints.RoomConstraints.RequiredTags = requiredTags
 			if constraintsInput.KdpJiraURL != nil && *constraintsInput.KdpJiraURL != "" {
 				constraints.RoomConstraints.KdpJiraURL = constraintsInput.KdpJiraURL
 			}
@@ -145,6 +154,12 @@ func (p *Plexams) AddConstraints(ctx context.Context, ancode int, constraintsInp
 			if constraintsInput.AdditionalSeats != nil && *constraintsInput.AdditionalSeats > 0 {
 				constraints.RoomConstraints.AdditionalSeats = constraintsInput.AdditionalSeats
 			}
+			if a := constraintsInput.ExpectedAttendance; a != nil {
+				if *a < 1 || *a > 100 {
+					return nil, fmt.Errorf("exam %d: expected attendance must be between 1 and 100 percent, got %d", ancode, *a)
+				}
+				constraints.RoomConstraints.ExpectedAttendance = a
+			}
 			// Vorlauf/Nachlauf: total minutes that replace the default 15; only kept when
 			// given as a positive value (0 = no override, stays nil = default 15).
 			if constraintsInput.PreExamMinutes != nil && *constraintsInput.PreExamMinutes > 0 {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	set "github.com/deckarep/golang-set/v2"
@@ -91,17 +92,19 @@ func (p *Plexams) CsvForLBARepeaterBytes(ctx context.Context) ([]byte, error) {
 		if hasStart {
 			invigs := set.NewSet[int]()
 			for _, room := range exam.PlannedRooms {
-				invigilator, err := p.invigilatorForRoomAtTime(ctx, room.RoomName, start)
+				roomInvigilators, err := p.invigilatorsForRoomAtTime(ctx, room.RoomName, start)
 				if err != nil {
 					log.Error().Err(err).Msg("cannot get invigilator")
 					return nil, err
 				}
-				if invigilator == nil || invigs.Contains(invigilator.ID) {
-					continue
+				for _, invigilator := range roomInvigilators {
+					if invigilator == nil || invigs.Contains(invigilator.ID) {
+						continue
+					}
+					invigilators += invigilator.Shortname + ", "
+					invigilatorEmails += invigilator.Email + ", "
+					invigs.Add(invigilator.ID)
 				}
-				invigilators += invigilator.Shortname + ", "
-				invigilatorEmails += invigilator.Email + ", "
-				invigs.Add(invigilator.ID)
 			}
 		}
 
@@ -126,7 +129,8 @@ func (p *Plexams) CsvForLBARepeaterBytes(ctx context.Context) ([]byte, error) {
 }
 
 // HTTPDownloadCSVDraft streams one of the human-readable draft CSVs as a download.
-// GET /download/csv/{kind}   (kind=draft needs ?program=<program>)
+// GET /download/csv/{kind}   (kind=draft needs ?program=<program>; kind=seating takes an
+// optional ?ancode=<ancode>)
 func (p *Plexams) HTTPDownloadCSVDraft(w http.ResponseWriter, r *http.Request) {
 	kind := chi.URLParam(r, "kind")
 
@@ -150,8 +154,24 @@ func (p *Plexams) HTTPDownloadCSVDraft(w http.ResponseWriter, r *http.Request) {
 	case "lba-repeater":
 		data, err = p.CsvForLBARepeaterBytes(r.Context())
 		filename = "Prüfungsplanung_LBA_Repeater_FK07.csv"
+	case "seating":
+		ancode := 0
+		if s := r.URL.Query().Get("ancode"); s != "" {
+			if ancode, err = strconv.Atoi(s); err != nil {
+				http.Error(w, "ancode must be a number", http.StatusBadRequest)
+				return
+			}
+		}
+		data, err = p.CsvSeatingBytes(r.Context(), ancode)
+		filename = "Sitzplätze.csv"
+		if ancode > 0 {
+			filename = fmt.Sprintf("Sitzplätze_%d.csv", ancode)
+		}
+	case "room-utilization":
+		data, err = p.CsvRoomUtilizationBytes(r.Context())
+		filename = "Raumauslastung.csv"
 	default:
-		http.Error(w, fmt.Sprintf("unknown csv kind %q (known: draft, exahm, lba-repeater)", kind), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("unknown csv kind %q (known: draft, exahm, lba-repeater, seating, room-utilization)", kind), http.StatusBadRequest)
 		return
 	}
 	if err != nil {
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/roomcalc"
 	"github.com/rs/zerolog/log"
 )
 
@@ -336,7 +337,7 @@ func (p *Plexams) csvConstraints() csvDataset {
 		"ancode", "notPlannedByMe", "notPlannedByMeInFK", "online", "location", "doNotPublish",
 		"excludeDays", "possibleDays", "fixedDay", "fixedTime", "sameSlot",
 		"allowedRooms", "placesWithSocket", "lab", "exahm", "seb", "kdpJiraURL",
-		"maxStudents", "additionalSeats", "comments",
+		"maxStudents", "additionalSeats", "comments", "requiredTags", "expectedAttendance",
 	}
 	return csvDataset{
 		Title:  "Constraints (inkl. notPlannedByMe)",
@@ -350,7 +351,7 @@ func (p *Plexams) csvConstraints() csvDataset {
 			rows := make([][]string, 0, len(cs))
 			for _, c := range cs {
 				rc := c.RoomConstraints
-				var allowed, socket, lab, exahm, seb, kdp, maxStud, addSeats, comments string
+				var allowed, socket, lab, exahm, seb, kdp, maxStud, addSeats, comments, tags, attendance string
 				if rc != nil {
 					allowed = strs2s(rc.AllowedRooms)
 					socket, lab, exahm, seb = b2s(rc.PlacesWithSocket), b2s(rc.Lab), b2s(rc.Exahm), b2s(rc.Seb)
@@ -358,13 +359,15 @@ func (p *Plexams) csvConstraints() csvDataset {
 					maxStud = intPtr2s(rc.MaxStudents)
 					addSeats = intPtr2s(rc.AdditionalSeats)
 					comments = strPtr2s(rc.Comments)
+					tags = strs2s(rc.RequiredTags)
+					attendance = intPtr2s(rc.ExpectedAttendance)
 				}
 				rows = append(rows, []string{
 					strconv.Itoa(c.Ancode), b2s(c.NotPlannedByMe), strPtr2s(c.NotPlannedByMeInFk), b2s(c.Online),
 					strPtr2s(c.Location), b2s(c.DoNotPublish),
 					dates2s(c.ExcludeDays), dates2s(c.PossibleDays), datePtr2s(c.FixedDay), dateTimePtr2s(c.FixedTime),
 					ints2s(c.SameSlot),
-					allowed, socket, lab, exahm, seb, kdp, maxStud, addSeats, comments,
+					allowed, socket, lab, exahm, seb, kdp, maxStud, addSeats, comments, tags, attendance,
 				})
 			}
 			return rows, nil
@@ -384,7 +387,8 @@ func (p *Plexams) csvConstraints() csvDataset {
 				sameSlot, err5 := s2ints(cell(row, 10))
 				maxStud, err6 := s2intPtr(cell(row, 17))
 				addSeats, err7 := s2intPtr(cell(row, 18))
-				if err := firstErr(err1, err2, err3, err4, err5, err6, err7); err != nil {
+				attendance, err8 := s2intPtr(cell(row, 21))
+				if err := firstErr(err1, err2, err3, err4, err5, err6, err7, err8); err != nil {
 					res.Skipped = append(res.Skipped, fmt.Sprintf("Zeile %d (ancode %d): %v", i+2, ancode, err))
 					continue
 				}
@@ -394,10 +398,16 @@ func (p *Plexams) csvConstraints() csvDataset {
 				socket, lab, exahm, seb := s2b(cell(row, 12)), s2b(cell(row, 13)), s2b(cell(row, 14)), s2b(cell(row, 15))
 				kdp := s2strPtr(cell(row, 16))
 				comments := s2strPtr(cell(row, 19))
-				if len(allowed) > 0 || socket || lab || exahm || seb || kdp != nil || maxStud != nil || addSeats != nil || comments != nil {
+				tags, err := roomcalc.NormalizeTags(s2strs(cell(row, 20)))
+				if err != nil {
+					res.Skipped = append(res.Skipped, fmt.Sprintf("Zeile %d (ancode %d): %v", i+2, ancode, err))
+					continue
+				}
+				if len(allowed) > 0 || socket || lab || exahm || seb || len(tags) > 0 || kdp != nil || maxStud != nil || addSeats != nil || comments != nil || attendance != nil {
 					rc = &model.RoomConstraints{
-						AllowedRooms: allowed, PlacesWithSocket: socket, Lab: lab, Exahm: exahm, Seb: seb,
+						AllowedRooms: allowed, PlacesWithSocket: socket, Lab: lab, Exahm: exahm, Seb: seb, RequiredTags: tags,
 						KdpJiraURL: kdp, MaxStudents: maxStud, AdditionalSeats: addSeats, Comments: comments,
+						ExpectedAttendance: attendance,
 					}
 				}
 
@@ -164,3 +164,78 @@ func ExahmRows(exams []*model.PlannedExam) []CsvExamEXaHM {
 	}
 	return rows
 }
+
+// SeatingRow is one seat of the examiners' seating list.
+type SeatingRow struct {
+	Ancode  int    `csv:"Ancode"`
+	Date    string `csv:"Termin"`
+	Room    string `csv:"Raum"`
+	Row     string `csv:"Reihe"`
+	Seat    string `csv:"Platz"`
+	Mtknr   string `csv:"Matrikelnummer"`
+	Name    string `csv:"Name"`
+	Comment string `csv:"Anmerkungen"`
+}
+
+// SeatingRows builds the rows of one seating plan (only ancode's students if ancode > 0),
+// seated students by seat, then the unseated ones without row and seat.
+func SeatingRows(plan *model.SeatingPlan, ancode int) []SeatingRow {
+	date := plan.Starttime.Local().Format("02.01.06, 15:04 Uhr")
+	rows := make([]SeatingRow, 0, len(plan.Seats)+len(plan.Unseated))
+	add := func(s *model.SeatAssignment, seated bool) {
+		if ancode > 0 && s.Ancode != ancode {
+			return
+		}
+		row := SeatingRow{Ancode: s.Ancode, Date: date, Room: plan.Room, Mtknr: s.Mtknr, Name: s.Name}
+		var comments []string
+		if seated {
+			row.Row, row.Seat = fmt.Sprint(s.Row), fmt.Sprint(s.Seat)
+		} else {
+			comments = append(comments, "kein Platz frei")
+		}
+		if s.Nta {
+			comments = append(comments, "NTA")
+		}
+		if s.Reserved {
+			comments = append(comments, "reservierter Platz")
+		}
+		row.Comment = strings.Join(comments, ", ")
+		rows = append(rows, row)
+	}
+	for _, s := range plan.Seats {
+		add(s, true)
+	}
+	for _, s := range plan.Unseated {
+		add(s, false)
+	}
+	return rows
+}
+
+// RoomUtilizationRow is one room at one start time of the room-utilization statistics.
+type RoomUtilizationRow struct {
+	Room        string `csv:"Raum"`
+	RequestWith string `csv:"Art"`
+	Seats       int    `csv:"Plätze"`
+	Date        string `csv:"Termin"`
+	Exams       int    `csv:"Prüfungen"`
+	Students    int    `csv:"Studierende"`
+	Utilization string `csv:"Auslastung"`
+}
+
+// RoomUtilizationRows builds one row per planned room and start time, in the given
+// (chronological) order.
+func RoomUtilizationRows(cells []*model.RoomSlotUsage) []RoomUtilizationRow {
+	rows := make([]RoomUtilizationRow, 0, len(cells))
+	for _, c := range cells {
+		rows = append(rows, RoomUtilizationRow{
+			Room:        c.Room,
+			RequestWith: c.RequestWith.String(),
+			Seats:       c.Seats,
+			Date:        c.Starttime.Local().Format("02.01.06, 15:04 Uhr"),
+			Exams:       c.Exams,
+			Students:    c.Students,
+			Utilization: fmt.Sprintf("%.1f %%", c.Utilization),
+		})
+	}
+	return rows
+}
@@ -83,3 +83,40 @@ func TestExahmRows(t *testing.T) {
 		t.Errorf("ExahmRows =\n%#v\nwant\n%#v", got, want)
 	}
 }
+
+func TestSeatingRows(t *testing.T) {
+	plan := &model.SeatingPlan{
+		Room: "R1.046", Starttime: fixedStart,
+		Seats: []*model.SeatAssignment{
+			{Row: 1, Seat: 1, Ancode: 7, Mtknr: "1", Name: "Abel", Nta: true, Reserved: true},
+			{Row: 1, Seat: 3, Ancode: 8, Mtknr: "2", Name: "Berg"},
+		},
+		Unseated: []*model.SeatAssignment{{Ancode: 7, Mtknr: "3", Name: "Cramer"}},
+	}
+	date := fixedStart.Local().Format("02.01.06, 15:04 Uhr")
+	got := SeatingRows(plan, 7)
+	want := []SeatingRow{
+		{Ancode: 7, Date: date, Room: "R1.046", Row: "1", Seat: "1", Mtknr: "1", Name: "Abel", Comment: "NTA, reservierter Platz"},
+		{Ancode: 7, Date: date, Room: "R1.046", Mtknr: "3", Name: "Cramer", Comment: "kein Platz frei"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SeatingRows =\n%#v\nwant\n%#v", got, want)
+	}
+	if n := len(SeatingRows(plan, 0)); n != 3 {
+		t.Errorf("SeatingRows(all) has %d rows, want 3", n)
+	}
+}
+
+func TestRoomUtilizationRows(t *testing.T) {
+	cells := []*model.RoomSlotUsage{
+		{Room: "R1.046", RequestWith: model.RoomRequestTypeAnny, Starttime: fixedStart, Seats: 60, Exams: 2, Students: 45, Utilization: 75},
+	}
+	got := RoomUtilizationRows(cells)
+	want := []RoomUtilizationRow{{
+		Room: "R1.046", RequestWith: "ANNY", Seats: 60, Date: fixedStart.Local().Format("02.01.06, 15:04 Uhr"),
+		Exams: 2, Students: 45, Utilization: "75.0 %",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RoomUtilizationRows =\n%#v\nwant\n%#v", got, want)
+	}
+}
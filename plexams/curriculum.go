@@ -0,0 +1,229 @@
+package plexams
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/curriculum"
+	"github.com/rs/zerolog/log"
+)
+
+// Curricula (see curriculum): the study plans of the study programs are imported as a
+// table into the global StudyProgram master data. Required modules of the same program
+// semester are expected to be taken together; the exam-schedule generator keeps them out
+// of the same slot (soft) and validateCurriculum warns if a plan puts them together.
+
+// curriculumConflictsOf returns the expected co-registrations between the exams from the
+// stored curricula.
+func (p *Plexams) curriculumConflictsOf(ctx context.Context, exams []curriculum.Exam) ([]curriculum.Conflict, error) {
+	programs, err := p.dbClient.StudyPrograms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return curriculum.Conflicts(programs, exams), nil
+}
+
+// zpaCurriculumConflicts returns the expected co-registrations between the ZPA exams to
+// plan.
+func (p *Plexams) zpaCurriculumConflicts(ctx context.Context) ([]curriculum.Conflict, error) {
+	zpaExams, err := p.GetZpaExamsToPlan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exams := make([]curriculum.Exam, 0, len(zpaExams))
+	for _, e := range zpaExams {
+		exams = append(exams, curriculum.Exam{Ancode: e.AnCode, Module: e.Module})
+	}
+	return p.curriculumConflictsOf(ctx, exams)
+}
+
+// CurriculumConflicts returns the expected co-registrations between the exams to plan.
+func (p *Plexams) CurriculumConflicts(ctx context.Context) ([]*model.CurriculumConflict, error) {
+	conflicts, err := p.zpaCurriculumConflicts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.CurriculumConflict, 0, len(conflicts))
+	for _, c := range conflicts {
+		cc := &model.CurriculumConflict{Ancode1: c.A, Ancode2: c.B, Module1: c.ModuleA, Module2: c.ModuleB,
+			Placements: make([]*model.CurriculumPlacement, 0, len(c.Placements))}
+		for _, pl := range c.Placements {
+			cc.Placements = append(cc.Placements, &model.CurriculumPlacement{Program: pl.Program, Semester: pl.Semester})
+		}
+		result = append(result, cc)
+	}
+	return result, nil
+}
+
+// ImportCurriculum reads a curriculum table. Every program in the file gets exactly the
+// modules of the file. dryRun (or any problem in the file) only returns the preview.
+func (p *Plexams) ImportCurriculum(ctx context.Context, data []byte, dryRun bool) (*curriculum.Import, error) {
+	entries, problems, err := curriculum.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	programs, err := p.dbClient.StudyPrograms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := curriculum.Diff(programs, entries, problems)
+	if dryRun || len(result.Problems) > 0 || result.Changed == 0 {
+		return result, nil
+	}
+	for _, prog := range result.Programs {
+		if err := p.dbClient.UpsertStudyProgram(ctx, prog); err != nil {
+			return nil, err
+		}
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// HTTPDownloadCurriculum streams the stored curricula of all study programs, the table
+// /upload/curriculum reads.
+// GET /download/curriculum.csv
+func (p *Plexams) HTTPDownloadCurriculum(w http.ResponseWriter, r *http.Request) {
+	programs, err := p.dbClient.StudyPrograms(r.Context())
+	if err != nil {
+		http.Error(w, "cannot get study programs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, err := curriculum.CSV(programs)
+	if err != nil {
+		http.Error(w, "cannot generate curriculum: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="Studienpläne.csv"`)
+	if _, err := w.Write(data); err != nil {
+		log.Error().Err(err).Msg("cannot write curriculum download")
+	}
+}
+
+// HTTPUploadCurriculum imports a curriculum table (multipart field "file", XLSX or CSV
+// with the columns Studiengang;Fachsemester;Modul;Pflicht). With ?dryRun=true it only
+// returns the preview; otherwise it stores the curricula unless the file has problems.
+// POST /upload/curriculum
+func (p *Plexams) HTTPUploadCurriculum(w http.ResponseWriter, r *http.Request) {
+	if !p.WritesAllowed() {
+		http.Error(w, "a validation or transfer/email is running, cannot upload now", http.StatusConflict)
+		return
+	}
+	if p.IsReadOnly() {
+		http.Error(w, "semester is read-only", http.StatusConflict)
+		return
+	}
+	if err := r.ParseMultipartForm(16 << 20); err != nil {
+		http.Error(w, "cannot parse upload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "missing file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close() //nolint:errcheck
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "cannot read file: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+	result, err := p.ImportCurriculum(r.Context(), data, dryRun)
+	if err != nil {
+		http.Error(w, "cannot import curriculum: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if result.Applied {
+		p.LogUpload(r.Context(), "uploadCurriculum", "file", header.Filename, "changes", result.Summary())
+	}
+	writeJSON(w, result)
+}
+
+// ValidateCurriculum warns when the plan puts exams of modules that are required in the
+// same program semester at the same start time: their students are expected to take
+// both. Pairs allowed to share a slot (sameSlot constraint, canShareSlot) are only info.
+func (p *Plexams) ValidateCurriculum(reporter Reporter) (*model.ValidationReport, error) {
+	ctx := context.Background()
+	v := newValidation(reporter, "curriculum", "prüfe Pflichtmodule laut Studienplan")
+
+	if ok, err := p.planGenerated(ctx); err != nil {
+		return nil, err
+	} else if !ok {
+		return v.skip(skipNoPlan), nil
+	}
+
+	v.step("berechne erwartete gemeinsame Anmeldungen")
+	conflicts, err := p.zpaCurriculumConflicts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) == 0 {
+		return v.skip("keine Pflichtmodule eines Fachsemesters unter den Prüfungen (Studienpläne importiert?)"), nil
+	}
+
+	planEntries, err := p.PlanEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	startOf := make(map[int]time.Time, len(planEntries))
+	for _, pe := range planEntries {
+		if pe.Starttime != nil {
+			startOf[pe.Ancode] = *pe.Starttime
+		}
+	}
+
+	allowed := make(map[[2]int]string)
+	cmap, err := p.ConstraintsMap(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot get constraints for curriculum validation")
+	}
+	for a, c := range cmap {
+		if c == nil {
+			continue
+		}
+		for _, other := range c.SameSlot {
+			allowed[[2]int{min(a, other), max(a, other)}] = "sameSlot-Constraint"
+		}
+	}
+	if pairs, err := p.dbClient.CanShareSlotPairs(ctx); err != nil {
+		log.Error().Err(err).Msg("cannot get canShareSlot pairs")
+	} else {
+		for _, pr := range pairs {
+			allowed[[2]int{min(pr[0], pr[1]), max(pr[0], pr[1])}] = "canShareSlot"
+		}
+	}
+
+	v.step("prüfe %d Paare von Pflichtmodulen", len(conflicts))
+	sort.Slice(conflicts, func(i, j int) bool {
+		si, sj := startOf[conflicts[i].A], startOf[conflicts[j].A]
+		if !si.Equal(sj) {
+			return si.Before(sj)
+		}
+		if conflicts[i].A != conflicts[j].A {
+			return conflicts[i].A < conflicts[j].A
+		}
+		return conflicts[i].B < conflicts[j].B
+	})
+	for _, c := range conflicts {
+		a, okA := startOf[c.A]
+		b, okB := startOf[c.B]
+		if !okA || !okB || !a.Equal(b) {
+			continue
+		}
+		r := ref{Ancode: ptr(c.A), RelatedAncodes: []int{c.B}, Starttime: ptr(a)}
+		if reason, ok := allowed[[2]int{c.A, c.B}]; ok {
+			v.infof(r, "Prüfungen %d (%s) und %d (%s) beginnen %s – Pflichtmodule im selben Fachsemester (%s), erlaubt (%s)",
+				c.A, c.ModuleA, c.B, c.ModuleB, fmtStart(&a), c, reason)
+			continue
+		}
+		v.warnf(r, "Prüfungen %d (%s) und %d (%s) beginnen %s – Pflichtmodule im selben Fachsemester (%s)",
+			c.A, c.ModuleA, c.B, c.ModuleB, fmtStart(&a), c)
+	}
+
+	return v.finish(), nil
+}
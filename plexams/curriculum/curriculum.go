@@ -0,0 +1,340 @@
+// Package curriculum handles the study plans (module handbooks) of the study programs:
+// which module is planned in which semester of a program, and whether it is required
+// (Pflicht) or elective (Wahlpflicht). Required modules of the same program semester are
+// expected to be taken together, so their exams should not run at the same time even
+// before any registration is known ("expected co-registration"). It is I/O-free; storing
+// the curricula in the study programs and logging stays in the plexams package.
+package curriculum
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/tableio"
+)
+
+// Column headers of the curriculum table.
+const (
+	ColProgram  = "Studiengang"
+	ColSemester = "Fachsemester"
+	ColModule   = "Modul"
+	ColRequired = "Pflicht"
+)
+
+// Header is the column order of the curriculum table.
+var Header = []string{ColProgram, ColSemester, ColModule, ColRequired}
+
+// aliases are the accepted (lower-case) header names per column.
+var aliases = map[string][]string{
+	ColProgram:  {"studiengang", "program", "programm", "kürzel"},
+	ColSemester: {"fachsemester", "semester", "sem"},
+	ColModule:   {"modul", "module", "modulname"},
+	ColRequired: {"pflicht", "required", "pflicht/wahlpflicht", "art", "typ"},
+}
+
+// Entry is one row of the table.
+type Entry struct {
+	Line     int
+	Program  string
+	Semester int
+	Module   string
+	Required bool
+}
+
+// Parse reads the table (XLSX or CSV with "," or ";"). Unreadable rows are returned as
+// problems (with the line number) instead of failing the whole file.
+func Parse(data []byte) (entries []Entry, problems []string, err error) {
+	rows, err := tableio.Read(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("file is empty")
+	}
+	col := make(map[string]int)
+	for i, h := range rows[0] {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\xEF\xBB\xBF")))
+		for name, names := range aliases {
+			for _, alias := range names {
+				if _, ok := col[name]; !ok && h == alias {
+					col[name] = i
+				}
+			}
+		}
+	}
+	for _, need := range Header {
+		if _, ok := col[need]; !ok {
+			return nil, nil, fmt.Errorf("column %q missing", need)
+		}
+	}
+	get := func(row []string, name string) string {
+		if i := col[name]; i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	for n, row := range rows[1:] {
+		line := n + 2
+		program, module := get(row, ColProgram), get(row, ColModule)
+		if program == "" && module == "" {
+			continue
+		}
+		if program == "" || module == "" {
+			problems = append(problems, fmt.Sprintf("line %d: %s and %s are needed", line, ColProgram, ColModule))
+			continue
+		}
+		semStr := get(row, ColSemester)
+		semester, err := strconv.Atoi(semStr)
+		if err != nil || semester < 1 {
+			problems = append(problems, fmt.Sprintf("line %d: cannot read %s %q", line, ColSemester, semStr))
+			continue
+		}
+		reqStr := get(row, ColRequired)
+		required, ok := ParseRequired(reqStr)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("line %d: cannot read %s %q (Pflicht/Wahlpflicht)", line, ColRequired, reqStr))
+			continue
+		}
+		entries = append(entries, Entry{Line: line, Program: program, Semester: semester, Module: module, Required: required})
+	}
+	return entries, problems, nil
+}
+
+// ParseRequired reads the required column: Pflicht/P/ja/x/true for a required module,
+// Wahlpflicht/WP/Wahl/nein/false for an elective one.
+func ParseRequired(s string) (required, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "pflicht", "p", "pf", "ja", "j", "x", "true", "1", "yes", "required":
+		return true, true
+	case "wahlpflicht", "wp", "wpf", "wahl", "w", "nein", "n", "false", "0", "no", "elective":
+		return false, true
+	}
+	return false, false
+}
+
+// CSV renders the curricula of the programs as CSV (UTF-8 with BOM, semicolons, so
+// German Excel opens it directly), sorted by program, semester and module. It is the
+// same table Parse reads.
+func CSV(programs []*model.StudyProgram) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\xEF\xBB\xBF")
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+	if err := w.Write(Header); err != nil {
+		return nil, err
+	}
+	sorted := append([]*model.StudyProgram(nil), programs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Shortname < sorted[j].Shortname })
+	for _, prog := range sorted {
+		for _, m := range sortedModules(prog.Curriculum) {
+			required := "Wahlpflicht"
+			if m.Required {
+				required = "Pflicht"
+			}
+			if err := w.Write([]string{prog.Shortname, strconv.Itoa(m.Semester), m.Module, required}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func sortedModules(modules []*model.CurriculumModule) []*model.CurriculumModule {
+	sorted := append([]*model.CurriculumModule(nil), modules...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Semester != sorted[j].Semester {
+			return sorted[i].Semester < sorted[j].Semester
+		}
+		return sorted[i].Module < sorted[j].Module
+	})
+	return sorted
+}
+
+// Import is the preview (and, once applied, the outcome) of a curriculum table. A
+// program in the file gets exactly the modules of the file; programs not in the file
+// keep their curriculum.
+type Import struct {
+	Applied bool `json:"applied"`
+	// Programs are the study programs of the file with their new curriculum.
+	Programs []*model.StudyProgram `json:"programs"`
+	Modules  int                   `json:"modules"`
+	Required int                   `json:"required"`
+	// Changed counts the programs whose curriculum differs from the stored one.
+	Changed int `json:"changed"`
+	// Problems are unreadable or unknown rows; an import with problems is not applied.
+	Problems []string `json:"problems"`
+}
+
+// Summary is a one-line description of the import for the mutation log.
+func (i *Import) Summary() string {
+	return fmt.Sprintf("%d programs (%d changed), %d modules (%d required)", len(i.Programs), i.Changed, i.Modules, i.Required)
+}
+
+// Diff checks the entries against the known study programs and builds their new
+// curricula.
+func Diff(programs []*model.StudyProgram, entries []Entry, problems []string) *Import {
+	byShortname := make(map[string]*model.StudyProgram, len(programs))
+	for _, prog := range programs {
+		byShortname[strings.ToUpper(prog.Shortname)] = prog
+	}
+	out := &Import{Programs: make([]*model.StudyProgram, 0), Problems: append(make([]string, 0), problems...)}
+	updated := make(map[string]*model.StudyProgram)
+	seen := make(map[string]int)
+	for _, e := range entries {
+		key := strings.ToUpper(e.Program)
+		prog, ok := byShortname[key]
+		if !ok {
+			out.Problems = append(out.Problems, fmt.Sprintf("line %d: unknown study program %q", e.Line, e.Program))
+			continue
+		}
+		dup := key + "|" + normalize(e.Module)
+		if prev, ok := seen[dup]; ok {
+			out.Problems = append(out.Problems, fmt.Sprintf("line %d: %s %s already in line %d", e.Line, prog.Shortname, e.Module, prev))
+			continue
+		}
+		seen[dup] = e.Line
+		u, ok := updated[key]
+		if !ok {
+			copied := *prog
+			copied.Curriculum = make([]*model.CurriculumModule, 0)
+			u = &copied
+			updated[key] = u
+			out.Programs = append(out.Programs, u)
+		}
+		u.Curriculum = append(u.Curriculum, &model.CurriculumModule{Semester: e.Semester, Module: e.Module, Required: e.Required})
+		out.Modules++
+		if e.Required {
+			out.Required++
+		}
+	}
+	sort.Slice(out.Programs, func(i, j int) bool { return out.Programs[i].Shortname < out.Programs[j].Shortname })
+	for _, u := range out.Programs {
+		u.Curriculum = sortedModules(u.Curriculum)
+		if !same(byShortname[strings.ToUpper(u.Shortname)].Curriculum, u.Curriculum) {
+			out.Changed++
+		}
+	}
+	return out
+}
+
+func same(a, b []*model.CurriculumModule) bool {
+	a, b = sortedModules(a), sortedModules(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if *a[i] != *b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize makes module names of the curriculum and of the exams comparable.
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// Exam is an exam to plan, matched to the curricula by its module name.
+type Exam struct {
+	Ancode int
+	Module string
+}
+
+// Placement is a program semester that has both modules of a conflict as required.
+type Placement struct {
+	Program  string
+	Semester int
+}
+
+// Conflict is an expected co-registration: the modules of both exams (A < B) are
+// required in the same semester of at least one program.
+type Conflict struct {
+	A, B             int
+	ModuleA, ModuleB string
+	Placements       []Placement
+}
+
+// Conflicts returns the expected co-registrations between the exams: every pair of exams
+// whose modules are required in the same program semester. Exams of the same module
+// (parallel exams) are not paired.
+func Conflicts(programs []*model.StudyProgram, exams []Exam) []Conflict {
+	byModule := make(map[string][]Exam)
+	for _, e := range exams {
+		if m := normalize(e.Module); m != "" {
+			byModule[m] = append(byModule[m], e)
+		}
+	}
+	byPair := make(map[[2]int]*Conflict)
+	for _, prog := range programs {
+		bySemester := make(map[int][]string)
+		for _, m := range prog.Curriculum {
+			module := normalize(m.Module)
+			if !m.Required || len(byModule[module]) == 0 {
+				continue
+			}
+			bySemester[m.Semester] = append(bySemester[m.Semester], module)
+		}
+		for semester, modules := range bySemester {
+			sort.Strings(modules)
+			for i := 0; i < len(modules); i++ {
+				for j := i + 1; j < len(modules); j++ {
+					if modules[i] == modules[j] {
+						continue
+					}
+					for _, a := range byModule[modules[i]] {
+						for _, b := range byModule[modules[j]] {
+							if a.Ancode == b.Ancode {
+								continue
+							}
+							x, y := a, b
+							if x.Ancode > y.Ancode {
+								x, y = y, x
+							}
+							key := [2]int{x.Ancode, y.Ancode}
+							c, ok := byPair[key]
+							if !ok {
+								c = &Conflict{A: x.Ancode, B: y.Ancode, ModuleA: x.Module, ModuleB: y.Module}
+								byPair[key] = c
+							}
+							c.Placements = append(c.Placements, Placement{Program: prog.Shortname, Semester: semester})
+						}
+					}
+				}
+			}
+		}
+	}
+	conflicts := make([]Conflict, 0, len(byPair))
+	for _, c := range byPair {
+		sort.Slice(c.Placements, func(i, j int) bool {
+			if c.Placements[i].Program != c.Placements[j].Program {
+				return c.Placements[i].Program < c.Placements[j].Program
+			}
+			return c.Placements[i].Semester < c.Placements[j].Semester
+		})
+		conflicts = append(conflicts, *c)
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].A != conflicts[j].A {
+			return conflicts[i].A < conflicts[j].A
+		}
+		return conflicts[i].B < conflicts[j].B
+	})
+	return conflicts
+}
+
+// String describes the program semesters, e.g. "IF 3, IB 3".
+func (c Conflict) String() string {
+	parts := make([]string, 0, len(c.Placements))
+	for _, pl := range c.Placements {
+		parts = append(parts, fmt.Sprintf("%s %d", pl.Program, pl.Semester))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,93 @@
+package curriculum
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+const sampleCSV = "\xEF\xBB\xBFStudiengang;Fachsemester;Modul;Pflicht\n" +
+	"IF;3;Datenbanken;Pflicht\n" +
+	"IF;3;Compilerbau;P\n" +
+	"IF;3;Web-Technologien;WP\n" +
+	"IB;3;datenbanken ;ja\n" +
+	"IB;3;Compilerbau;x\n" +
+	"IF;4;Software Engineering;Pflicht\n" +
+	"XY;1;Mathematik I;Pflicht\n" +
+	"IF;drei;Mathematik II;Pflicht\n" +
+	"IF;2;Mathematik II;vielleicht\n" +
+	";;;\n"
+
+func TestParseAndDiff(t *testing.T) {
+	entries, problems, err := Parse([]byte(sampleCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 7 || len(problems) != 2 {
+		t.Fatalf("got %d entries, problems %v", len(entries), problems)
+	}
+	programs := []*model.StudyProgram{
+		{Shortname: "IF", Curriculum: []*model.CurriculumModule{{Semester: 3, Module: "Datenbanken", Required: true}}},
+		{Shortname: "IB"},
+		{Shortname: "GN", Curriculum: []*model.CurriculumModule{{Semester: 1, Module: "Gestaltung", Required: true}}},
+	}
+	result := Diff(programs, entries, problems)
+	if len(result.Problems) != 3 || !strings.Contains(result.Problems[2], `unknown study program "XY"`) {
+		t.Errorf("problems = %v", result.Problems)
+	}
+	if len(result.Programs) != 2 || result.Programs[0].Shortname != "IB" || result.Programs[1].Shortname != "IF" {
+		t.Fatalf("programs = %+v", result.Programs)
+	}
+	if result.Modules != 6 || result.Required != 5 || result.Changed != 2 {
+		t.Errorf("summary = %s", result.Summary())
+	}
+	if len(programs[0].Curriculum) != 1 {
+		t.Errorf("stored program modified: %+v", programs[0].Curriculum)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	entries, _, err := Parse([]byte(sampleCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	programs := Diff([]*model.StudyProgram{{Shortname: "IF"}, {Shortname: "IB"}}, entries, nil).Programs
+	got := Conflicts(programs, []Exam{
+		{Ancode: 324, Module: "Datenbanken"},
+		{Ancode: 325, Module: "Datenbanken"}, // parallel exam of the same module
+		{Ancode: 101, Module: "Compilerbau"},
+		{Ancode: 150, Module: "Web-Technologien"}, // elective
+		{Ancode: 200, Module: "Software Engineering"},
+	})
+	both := []Placement{{Program: "IB", Semester: 3}, {Program: "IF", Semester: 3}}
+	want := []Conflict{
+		{A: 101, B: 324, ModuleA: "Compilerbau", ModuleB: "Datenbanken", Placements: both},
+		{A: 101, B: 325, ModuleA: "Compilerbau", ModuleB: "Datenbanken", Placements: both},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Conflicts =\n  %+v\nwant\n  %+v", got, want)
+	}
+	if s := got[0].String(); s != "IB 3, IF 3" {
+		t.Errorf("String = %q", s)
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	programs := []*model.StudyProgram{{Shortname: "IF", Curriculum: []*model.CurriculumModule{
+		{Semester: 4, Module: "Software Engineering", Required: true},
+		{Semester: 3, Module: "Web-Technologien"},
+	}}}
+	data, err := CSV(programs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, problems, err := Parse(data)
+	if err != nil || len(problems) > 0 {
+		t.Fatal(err, problems)
+	}
+	if result := Diff(programs, entries, nil); result.Changed != 0 || result.Modules != 2 {
+		t.Errorf("round trip: %s", result.Summary())
+	}
+}
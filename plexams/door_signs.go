@@ -0,0 +1,211 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/email"
+	"github.com/obcode/plexams.go/plexams/pdfgen"
+)
+
+// Door signs (Türschilder): one sign per room and exam day from the room plan. Reserve
+// rooms are not published, so a reserve use only shows up as "reserviert" — and only for
+// rooms that have to be requested or booked (rooms_for_slots lists them for the slot),
+// since the own rooms are usable in every slot anyway.
+
+// doorSignKey is a room on a day.
+type doorSignKey struct {
+	room string
+	day  string // 2006-01-02
+}
+
+// DoorSignsPDFBytes builds the door signs of all rooms and exam days.
+func (p *Plexams) DoorSignsPDFBytes(ctx context.Context) ([]byte, error) {
+	signs, err := p.doorSigns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return marotoBytes(pdfgen.DoorSigns(p.semesterFull(), signs))
+}
+
+func (p *Plexams) doorSigns(ctx context.Context) ([]pdfgen.DoorSign, error) {
+	exams, err := p.PlannedExams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := p.dbClient.Rooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	booked := make(map[string]bool, len(rooms))
+	for _, r := range rooms {
+		booked[r.Name] = r.NeedsRequest || r.RequestWith == model.RoomRequestTypeAnny
+	}
+	roomsForSlots, err := p.roomsForSlotsMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nameRanges, err := p.nameRangeLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type entry struct {
+		start time.Time
+		sign  pdfgen.DoorSignEntry
+	}
+	entries := make(map[doorSignKey][]entry)
+	// slots of the day: used by an exam or as reserve (true), or — for booked rooms —
+	// available in rooms_for_slots but unused (false)
+	used := make(map[doorSignKey]map[time.Time]bool)
+	days := make(map[doorSignKey]time.Time)
+	for _, exam := range exams {
+		byRoom := make(map[string][]*model.PlannedRoom)
+		order := make([]string, 0)
+		for _, pr := range exam.PlannedRooms {
+			if pr.Starttime == nil {
+				continue
+			}
+			if _, ok := byRoom[pr.RoomName]; !ok {
+				order = append(order, pr.RoomName)
+			}
+			byRoom[pr.RoomName] = append(byRoom[pr.RoomName], pr)
+		}
+		for _, room := range order {
+			prs := byRoom[room]
+			start := *prs[0].Starttime
+			k := doorSignKey{room, start.Format("2006-01-02")}
+			days[k] = start
+			if used[k] == nil {
+				used[k] = make(map[time.Time]bool)
+			}
+			used[k][start] = true
+			if doorSignReserveOnly(prs) {
+				continue
+			}
+			entries[k] = append(entries[k], entry{start, doorSignEntry(exam, prs, nameRanges[exam.Ancode][room])})
+		}
+	}
+
+	// booked rooms that stay free in a slot of a day they are used on
+	for k := range days {
+		if !booked[k.room] {
+			continue
+		}
+		for slot, names := range roomsForSlots {
+			if slot.Format("2006-01-02") != k.day || used[k][slot] {
+				continue
+			}
+			for _, name := range names {
+				if name == k.room {
+					used[k][slot] = false
+				}
+			}
+		}
+	}
+
+	keys := make([]doorSignKey, 0, len(days))
+	for k := range days {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].day != keys[j].day {
+			return keys[i].day < keys[j].day
+		}
+		return keys[i].room < keys[j].room
+	})
+	signs := make([]pdfgen.DoorSign, 0, len(keys))
+	for _, k := range keys {
+		es := entries[k]
+		sort.SliceStable(es, func(i, j int) bool { return es[i].start.Before(es[j].start) })
+		sign := pdfgen.DoorSign{Room: k.room, Day: email.DateDE(days[k])}
+		hasExam := make(map[time.Time]bool)
+		for _, e := range es {
+			sign.Entries = append(sign.Entries, e.sign)
+			hasExam[e.start] = true
+		}
+		slots := make([]time.Time, 0)
+		for slot := range used[k] {
+			if !hasExam[slot] {
+				slots = append(slots, slot)
+			}
+		}
+		sort.Slice(slots, func(i, j int) bool { return slots[i].Before(slots[j]) })
+		for _, slot := range slots {
+			if booked[k.room] {
+				sign.Reserved = append(sign.Reserved, slot.Local().Format("15:04")+" Uhr")
+			}
+		}
+		if len(sign.Entries) == 0 && len(sign.Reserved) == 0 {
+			continue
+		}
+		signs = append(signs, sign)
+	}
+	return signs, nil
+}
+
+// doorSignReserveOnly reports whether an exam uses the room only as reserve room.
+func doorSignReserveOnly(prs []*model.PlannedRoom) bool {
+	for _, pr := range prs {
+		if !pr.Reserve {
+			return false
+		}
+	}
+	return true
+}
+
+// doorSignEntry renders one exam in a room: the regular end (shortest non-NTA duration)
+// and the NTA end if an NTA in the room writes longer.
+func doorSignEntry(exam *model.PlannedExam, prs []*model.PlannedRoom, nameRange string) pdfgen.DoorSignEntry {
+	start := prs[0].Starttime.Local()
+	regular, longest := 0, 0
+	for _, pr := range prs {
+		if pr.Reserve {
+			continue
+		}
+		if !pr.Handicap && (regular == 0 || pr.Duration < regular) {
+			regular = pr.Duration
+		}
+		longest = max(longest, pr.Duration)
+	}
+	if regular == 0 {
+		regular = longest // NTA room only
+	}
+	timeLabel := fmt.Sprintf("%s – %s Uhr", start.Format("15:04"), start.Add(time.Duration(regular)*time.Minute).Format("15:04"))
+	if longest > regular {
+		timeLabel += fmt.Sprintf(" (NTA bis %s Uhr)", start.Add(time.Duration(longest)*time.Minute).Format("15:04"))
+	}
+
+	e := pdfgen.DoorSignEntry{Time: timeLabel, Exam: fmt.Sprintf("%d.", exam.Ancode)}
+	if exam.ZpaExam != nil {
+		e.Exam = fmt.Sprintf("%d. %s", exam.Ancode, exam.ZpaExam.Module)
+		e.Examer = exam.ZpaExam.MainExamer
+	}
+	if nameRange != "" {
+		e.Audience = "Nachnamen " + strings.ReplaceAll(nameRange, "–", " – ")
+	} else {
+		programs := make([]string, 0, len(exam.PrimussExams))
+		seen := make(map[string]bool)
+		for _, pe := range exam.PrimussExams {
+			if pe.Exam != nil && len(pe.StudentRegs) > 0 && !seen[pe.Exam.Program] {
+				seen[pe.Exam.Program] = true
+				programs = append(programs, pe.Exam.Program)
+			}
+		}
+		sort.Strings(programs)
+		e.Audience = "Studiengänge " + strings.Join(programs, ", ")
+	}
+	if exam.Constraints != nil && exam.Constraints.RoomConstraints != nil {
+		switch {
+		case exam.Constraints.RoomConstraints.Exahm:
+			e.Hint = "EXaHM-Prüfung am Rechner — bitte Hochschul-Login bereithalten."
+		case exam.Constraints.RoomConstraints.Seb:
+			e.Hint = "SEB-Prüfung (Safe Exam Browser) — eigenes Gerät mit installiertem SEB mitbringen."
+		}
+	}
+	return e
+}
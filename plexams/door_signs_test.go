@@ -0,0 +1,40 @@
+package plexams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+func TestDoorSignEntry(t *testing.T) {
+	start := time.Date(2026, 7, 11, 8, 30, 0, 0, time.Local)
+	exam := &model.PlannedExam{
+		Ancode:  7,
+		ZpaExam: &model.ZPAExam{Module: "Algebra", MainExamer: "Braun"},
+		PrimussExams: []*model.EnhancedPrimussExam{
+			{Exam: &model.PrimussExam{Program: "IF"}, StudentRegs: make([]*model.EnhancedStudentReg, 2)},
+			{Exam: &model.PrimussExam{Program: "IB"}, StudentRegs: make([]*model.EnhancedStudentReg, 1)},
+			{Exam: &model.PrimussExam{Program: "DC"}},
+		},
+		Constraints: &model.Constraints{RoomConstraints: &model.RoomConstraints{Seb: true}},
+	}
+	prs := []*model.PlannedRoom{
+		{Starttime: &start, RoomName: "R1.046", Duration: 90},
+		{Starttime: &start, RoomName: "R1.046", Duration: 113, Handicap: true},
+	}
+
+	e := doorSignEntry(exam, prs, "")
+	if e.Time != "08:30 – 10:00 Uhr (NTA bis 10:23 Uhr)" {
+		t.Errorf("Time = %q", e.Time)
+	}
+	if e.Exam != "7. Algebra" || e.Examer != "Braun" || e.Audience != "Studiengänge IB, IF" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if e.Hint == "" {
+		t.Error("SEB exam needs a hint")
+	}
+	if e := doorSignEntry(exam, prs, "A–Ka"); e.Audience != "Nachnamen A – Ka" {
+		t.Errorf("Audience = %q", e.Audience)
+	}
+}
@@ -45,12 +45,12 @@ type LbaRepeaterEmail struct {
 // BuildLbaRepeaterExams collects the repeat exams of LBAs (non-profs) that I planned, with
 // their time and invigilators, ordered chronologically. Pure over the already-fetched
 // exams: examer resolves an examer ID to its teacher (nil ⇒ skip the exam, e.g. lookup
-// error); only non-profs are kept. invigilatorForRoom resolves a room in a slot to its
-// invigilator (nil ⇒ skip that room). The exam's start time is its PlanEntry.Starttime.
+// error); only non-profs are kept. invigilatorsForRoom resolves a room in a slot to its
+// invigilators (empty ⇒ skip that room). The exam's start time is its PlanEntry.Starttime.
 func BuildLbaRepeaterExams(
 	plannedExams []*model.PlannedExam,
 	examer func(id int) *model.Teacher,
-	invigilatorForRoom func(room string, start time.Time) *model.Teacher,
+	invigilatorsForRoom func(room string, start time.Time) []*model.Teacher,
 ) []*LbaRepeaterExam {
 	result := make([]*LbaRepeaterExam, 0)
 	for _, exam := range plannedExams {
@@ -83,12 +83,13 @@ func BuildLbaRepeaterExams(
 				if room.RoomName == "ONLINE" {
 					continue
 				}
-				invigilator := invigilatorForRoom(room.RoomName, *exam.PlanEntry.Starttime)
-				if invigilator == nil || seen.Contains(invigilator.ID) {
-					continue
+				for _, invigilator := range invigilatorsForRoom(room.RoomName, *exam.PlanEntry.Starttime) {
+					if invigilator == nil || seen.Contains(invigilator.ID) {
+						continue
+					}
+					seen.Add(invigilator.ID)
+					invigilators = append(invigilators, LbaPerson{Name: invigilator.Shortname, Email: invigilator.Email})
 				}
-				seen.Add(invigilator.ID)
-				invigilators = append(invigilators, LbaPerson{Name: invigilator.Shortname, Email: invigilator.Email})
 			}
 		}
 
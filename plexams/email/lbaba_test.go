@@ -38,12 +38,12 @@ func TestBuildLbaRepeaterExams(t *testing.T) {
 		5: {ID: 5, IsProf: false, Email: "lba5@hm.edu"},
 	}
 	examer := func(id int) *model.Teacher { return teachers[id] } // id 4 -> nil (lookup fail)
-	invigs := map[string]*model.Teacher{
-		"R1": {ID: 10, Shortname: "Inv10", Email: "i10@hm.edu"},
-		"R2": {ID: 10, Shortname: "Inv10", Email: "i10@hm.edu"}, // same ID -> deduped
-		"R3": {ID: 20, Shortname: "Inv20", Email: "i20@hm.edu"},
+	invigs := map[string][]*model.Teacher{
+		"R1": {{ID: 10, Shortname: "Inv10", Email: "i10@hm.edu"}},
+		"R2": {{ID: 10, Shortname: "Inv10", Email: "i10@hm.edu"}},                                                    // same ID -> deduped
+		"R3": {{ID: 20, Shortname: "Inv20", Email: "i20@hm.edu"}, {ID: 21, Shortname: "Inv21", Email: "i21@hm.edu"}}, // two invigilators
 	}
-	invigilatorForRoom := func(room string, _ time.Time) *model.Teacher { return invigs[room] }
+	invigilatorsForRoom := func(room string, _ time.Time) []*model.Teacher { return invigs[room] }
 
 	prog := func(name string, regs int) *model.EnhancedPrimussExam {
 		return &model.EnhancedPrimussExam{Exam: &model.PrimussExam{Program: name}, StudentRegs: make([]*model.EnhancedStudentReg, regs)}
@@ -69,7 +69,7 @@ func TestBuildLbaRepeaterExams(t *testing.T) {
 		repeaterExam(5, "LBA Five", "MG", false, nil, nil, nil),
 	}
 
-	got := BuildLbaRepeaterExams(exams, examer, invigilatorForRoom)
+	got := BuildLbaRepeaterExams(exams, examer, invigilatorsForRoom)
 
 	// kept: G (unplanned, zero time first), B (08:00), A (10:00)
 	if len(got) != 3 {
@@ -84,6 +84,11 @@ func TestBuildLbaRepeaterExams(t *testing.T) {
 		t.Errorf("G = %+v", got[0])
 	}
 
+	// B: both invigilators of the room
+	if len(got[1].Invigilators) != 2 || got[1].Invigilators[1].Name != "Inv21" {
+		t.Errorf("B invigilators = %+v (want 2)", got[1].Invigilators)
+	}
+
 	// A: examer email, date/time, deduped invigilators, sorted programs (count>0 only)
 	a := got[2]
 	if a.Examer.Email != "lba1@hm.edu" || a.Date != "Mo, 06.07.2026" || a.Time != "10:00" {
@@ -28,6 +28,7 @@ type PublishedRoomsExam struct {
 // PublishedRoomsRoom is one room of an exam with its seat blocks and co-usage.
 type PublishedRoomsRoom struct {
 	RoomName    string
+	NameRange   string                  // "A–Ka" if the exam is split by last name, else ""
 	Allocations []string                // seat blocks of this room (non-NTA first, then by duration)
 	SharedWith  []*PublishedRoomsShared // other exams using the same room in the same slot
 }
@@ -41,7 +41,9 @@ func (p *Plexams) coverPagePDF(ctx context.Context, examerID int) (data []byte,
 	return data, "coverPages.dir", nil
 }
 
-func (p *Plexams) SendCoverPagesMails(ctx context.Context, run bool, reporter Reporter) error {
+// SendCoverPagesMails sends the cover-page email to every examer with exams planned by
+// me; withAttendance also attaches the examer's attendance lists.
+func (p *Plexams) SendCoverPagesMails(ctx context.Context, run, withAttendance bool, reporter Reporter) error {
 	if err := p.emailSendAllowed(ctx, condCoverPagesSent, run); err != nil {
 		return err
 	}
@@ -52,7 +54,7 @@ func (p *Plexams) SendCoverPagesMails(ctx context.Context, run bool, reporter Re
 
 	sent := 0
 	for _, teacher := range teachers {
-		if err := p.SendCoverPageMail(ctx, teacher.ID, run, reporter); err != nil {
+		if err := p.SendCoverPageMail(ctx, teacher.ID, run, withAttendance, reporter); err != nil {
 			log.Error().Err(err).Int("examerID", teacher.ID).Msg("cannot send cover page mail")
 		} else {
 			sent++
@@ -66,7 +68,9 @@ func (p *Plexams) SendCoverPagesMails(ctx context.Context, run bool, reporter Re
 	return nil
 }
 
-func (p *Plexams) SendCoverPageMail(ctx context.Context, examerID int, run bool, reporter Reporter) error {
+// SendCoverPageMail sends the cover-page email to one examer; withAttendance also
+// attaches the examer's attendance lists (skipped with a warning if there are none).
+func (p *Plexams) SendCoverPageMail(ctx context.Context, examerID int, run, withAttendance bool, reporter Reporter) error {
 	teacher, err := p.GetTeacher(ctx, examerID)
 	if err != nil {
 		log.Debug().Err(err).Msg("cannot get teacher by ID")
@@ -81,6 +85,28 @@ func (p *Plexams) SendCoverPageMail(ctx context.Context, examerID int, run bool,
 		return err
 	}
 
+	attachments := []*mailAttachment{{
+		Filename:    strings.ReplaceAll(fmt.Sprintf("%s_Deckblaetter_Pruefungen_%s.pdf", p.semester, teacher.Fullname), " ", "_"),
+		ContentType: "application/pdf",
+		Content:     pdfData,
+	}}
+	if withAttendance {
+		lists, err := p.attendanceListsForExamer(ctx, examerID)
+		switch {
+		case err != nil:
+			reporter.StopProgressFail(fmt.Sprintf("%s: cannot build attendance lists: %v", teacher.Fullname, err))
+			return err
+		case lists == nil:
+			reporter.Warnf("%s: no planned rooms, no attendance lists attached", teacher.Fullname)
+		default:
+			attachments = append(attachments, &mailAttachment{
+				Filename:    strings.ReplaceAll(fmt.Sprintf("%s_Anwesenheitslisten_%s.pdf", p.semester, teacher.Fullname), " ", "_"),
+				ContentType: "application/pdf",
+				Content:     lists,
+			})
+		}
+	}
+
 	coverMailData := &CoverMailData{
 		PlanerName:    p.planer.Name,
 		Teacher:       teacher,
@@ -101,11 +127,7 @@ func (p *Plexams) SendCoverPageMail(ctx context.Context, examerID int, run bool,
 		subject,
 		text,
 		html,
-		[]*mailAttachment{{
-			Filename:    strings.ReplaceAll(fmt.Sprintf("%s_Deckblaetter_Pruefungen_%s.pdf", p.semester, teacher.Fullname), " ", "_"),
-			ContentType: "application/pdf",
-			Content:     pdfData,
-		}},
+		attachments,
 		false,
 	)
 	if err != nil {
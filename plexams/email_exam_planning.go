@@ -142,6 +142,9 @@ func constraintsText(c *model.Constraints) string {
 		if rc.PlacesWithSocket {
 			parts = append(parts, "Steckdosen")
 		}
+		if len(rc.RequiredTags) > 0 {
+			parts = append(parts, "Raummerkmale: "+strings.Join(rc.RequiredTags, ", "))
+		}
 		if len(rc.AllowedRooms) > 0 {
 			parts = append(parts, "nur Räume: "+strings.Join(rc.AllowedRooms, ", "))
 		}
@@ -150,6 +150,18 @@ func TestSimpleEmailsGolden(t *testing.T) {
 			&secretariatInvigEmail{SemesterName: "2026 SS", PlanerName: "Test Planer"}},
 		{"invigilationMissingEmail", "invigilationMissingEmail.md.tmpl", true,
 			&InvigilationMissingMailData{Teacher: teacher, Semester: "2026 SS", PlanerName: "Test Planer", Minutes: 180}},
+		{"roomChangeEmail", "roomChangeEmail.md.tmpl", true,
+			&RoomChangeMailData{Teacher: teacher, Room: "R1.046", Reason: "Wasserschaden", PlanerName: "Test Planer",
+				Lines: []string{"324. Mathe, Mo, 13.07.2026 10:30 Uhr: jetzt R1.049, R1.051 (bisher R1.046)"}}},
+		{"roomChangeEmailInvigilator", "roomChangeEmail.md.tmpl", true,
+			&RoomChangeMailData{Teacher: teacher, Room: "R1.046", Invigilator: true, PlanerName: "Test Planer",
+				Lines: []string{"Mo, 13.07.2026 10:30 Uhr: Aufsicht in R1.049 statt R1.046"}}},
+		{"invigilationSwapEmail", "invigilationSwapEmail.md.tmpl", true,
+			&InvigilationSwapMailData{Teacher: teacher, PlanerName: "Test Planer",
+				Lines: []string{
+					"Mo, 13.07.2026 10:30 Uhr: Sie geben die Aufsicht in R1.049 an Prof. Dr. Max Muster ab",
+					"Di, 14.07.2026 08:30 Uhr: Sie übernehmen die Reserveaufsicht von Prof. Dr. Max Muster",
+				}}},
 	}
 	for _, c := range cases {
 		text, html, err := email.New(nil, renderFuncs(), jiraURL).Render(c.tmpl, c.jira, c.data)
@@ -288,14 +300,14 @@ func TestPublishedEmailsGolden(t *testing.T) {
 	stats := &InvigilationsEmail{
 		NoOfInvigilators: 10, InvigilationInRooms: 1200, ReserveInvigilation: 600,
 		OtherContributions: 120, TodoPerInvigilator: 900, MaxDeviation: 30, MinDeviation: 20,
-		PlanerName: "Test Planer", Teacher: &model.Teacher{Fullname: "Prof. Test"},
+		SharedRooms: 2, PlanerName: "Test Planer", Teacher: &model.Teacher{Fullname: "Prof. Test"},
 	}
 	rooms := &email.PublishedRoomsEmail{
 		Teacher: &model.Teacher{Shortname: "tst"}, PlanerName: "Test Planer",
 		Exams: []*email.PublishedRoomsExam{{
 			Ancode: 111, Module: "Mathe", Date: "Mo, 06.07.2026", Time: "08:30",
 			Rooms: []*email.PublishedRoomsRoom{{
-				RoomName: "R1.100", Allocations: []string{"20 Stud., 90 min"},
+				RoomName: "R1.100", NameRange: "A–Ka", Allocations: []string{"20 Stud., 90 min"},
 				SharedWith: []*email.PublishedRoomsShared{{ExamHeader: "222. Physik (Prof. B)", Allocations: []string{"5 Stud., 90 min", "1 Stud., 120 min, NTA: 25%"}}},
 			}},
 		}},
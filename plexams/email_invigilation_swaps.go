@@ -0,0 +1,136 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/email"
+	"github.com/rs/zerolog/log"
+)
+
+type InvigilationSwapMailData struct {
+	Teacher    *model.Teacher
+	Lines      []string
+	PlanerName string
+}
+
+// SendEmailInvigilationSwaps sends the two invigilators of every approved, not yet
+// notified swap their part of the swap and their updated plan as ICS. A swap is only
+// marked as notified on a real run and if both mails went out.
+func (p *Plexams) SendEmailInvigilationSwaps(ctx context.Context, run bool, reporter Reporter) error {
+	reporter.Step("collecting approved invigilation swaps")
+	swaps, err := p.dbClient.InvigilationSwaps(ctx, false)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot get invigilation swaps")
+		return err
+	}
+	pending := make([]*model.InvigilationSwap, 0)
+	for _, swap := range swaps {
+		if swap.Status == model.InvigilationSwapStatusApproved && swap.NotifiedAt == nil {
+			pending = append(pending, swap)
+		}
+	}
+	if len(pending) == 0 {
+		reporter.StopProgress("no approved invigilation swaps to announce")
+		return nil
+	}
+
+	sent := 0
+	for _, swap := range pending {
+		ok := true
+		for _, teacherID := range []int{swap.ProposerID, swap.ColleagueID} {
+			if err := p.sendEmailInvigilationSwap(ctx, swap, teacherID, run, reporter); err != nil {
+				log.Error().Err(err).Str("swap", swap.ID).Int("teacher", teacherID).
+					Msg("cannot send invigilation-swap email")
+				ok = false
+			}
+		}
+		if !ok {
+			continue
+		}
+		sent++
+		if run {
+			now := time.Now()
+			swap.NotifiedAt = &now
+			if err := p.dbClient.SaveInvigilationSwap(ctx, swap); err != nil {
+				return err
+			}
+		}
+	}
+
+	reporter.StopProgress(fmt.Sprintf("announced %d of %d swaps", sent, len(pending)))
+	return nil
+}
+
+func (p *Plexams) sendEmailInvigilationSwap(ctx context.Context, swap *model.InvigilationSwap, teacherID int, run bool, reporter Reporter) error {
+	teacher, err := p.GetTeacher(ctx, teacherID)
+	if err != nil {
+		return err
+	}
+	if teacher == nil {
+		return fmt.Errorf("teacher %d not found", teacherID)
+	}
+
+	reporter.Step(fmt.Sprintf("sending invigilation-swap email to %s", teacher.Fullname))
+
+	mailData := &InvigilationSwapMailData{
+		Teacher:    teacher,
+		Lines:      invigilationSwapLines(swap, teacherID),
+		PlanerName: p.planer.Name,
+	}
+	text, html, err := p.mailRenderer().Render("invigilationSwapEmail.md.tmpl", true, mailData)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("[Prüfungsplanung %s] Aufsichtentausch", p.semester)
+
+	var attachments []*mailAttachment
+	if icsData, err := p.InvigilatorICS(ctx, teacherID); err != nil {
+		reporter.Warnf("%s: cannot build ICS: %v", teacher.Fullname, err)
+	} else {
+		attachments = append(attachments, &mailAttachment{
+			Filename:    strings.ReplaceAll(fmt.Sprintf("%s_Aufsichtenplan_%s.ics", p.semester, teacher.Fullname), " ", "_"),
+			ContentType: "text/calendar; charset=utf-8",
+			Content:     icsData,
+		})
+	}
+
+	if err := p.sendMail(run, []string{teacher.Email}, nil, subject, text, html, attachments, true); err != nil {
+		reporter.Warnf("error while sending email to %s: %v", teacher.Fullname, err)
+		return err
+	}
+
+	reporter.Printf("  ✓ sent to %s %s", teacher.Fullname, p.recipientInfo(run, teacher.Email))
+	return nil
+}
+
+// invigilationSwapLines describes the swap from one invigilator's point of view, e.g.
+// "Mo, 13.07.2026 10:30 Uhr: Sie übernehmen die Aufsicht in R1.049 von Prof. Dr. X".
+func invigilationSwapLines(swap *model.InvigilationSwap, teacherID int) []string {
+	line := func(sp *model.InvigilationSwapPosition, takeOver bool, other string) string {
+		what := "die Reserveaufsicht"
+		if sp.RoomName != nil {
+			what = "die Aufsicht in " + *sp.RoomName
+		}
+		when := fmt.Sprintf("%s %s Uhr", email.DateDE(sp.Starttime), email.TimeDE(sp.Starttime))
+		if takeOver {
+			return fmt.Sprintf("%s: Sie übernehmen %s von %s", when, what, other)
+		}
+		return fmt.Sprintf("%s: Sie geben %s an %s ab", when, what, other)
+	}
+
+	isProposer := teacherID == swap.ProposerID
+	other := swap.ProposerName
+	if isProposer {
+		other = swap.ColleagueName
+	}
+	lines := []string{line(swap.Give, !isProposer, other)}
+	if swap.Take != nil {
+		lines = append(lines, line(swap.Take, isProposer, other))
+	}
+	return lines
+}
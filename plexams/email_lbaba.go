@@ -24,14 +24,14 @@ func (p *Plexams) buildLbaRepeaterExams(ctx context.Context) ([]*email.LbaRepeat
 		}
 		return t
 	}
-	invigilatorForRoom := func(room string, start time.Time) *model.Teacher {
-		inv, err := p.GetInvigilatorForRoom(ctx, room, start)
+	invigilatorsForRoom := func(room string, start time.Time) []*model.Teacher {
+		invs, err := p.GetInvigilatorsAt(ctx, room, start)
 		if err != nil {
 			return nil
 		}
-		return inv
+		return invs
 	}
-	return email.BuildLbaRepeaterExams(plannedExams, examer, invigilatorForRoom), nil
+	return email.BuildLbaRepeaterExams(plannedExams, examer, invigilatorsForRoom), nil
 }
 
 // SendEmailLbaRepeaters sends the Lehrbeauftragten-Beauftragte:r (emails.lbaba)
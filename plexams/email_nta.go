@@ -120,9 +120,9 @@ func (p *Plexams) SendHandicapsMailToStudentRoomAlone(ctx context.Context, run b
 type NTAEmailExamAndRoom struct {
 	Exam        *model.PlannedExam
 	Room        *model.PlannedRoom
-	Invigilator *model.Teacher
-	Date        string // e.g. "Mo, 13.07.2026"
-	Time        string // e.g. "08:30"
+	Invigilator *model.Teacher // the lead invigilator; all invigilators of the room are in Cc
+	Date        string         // e.g. "Mo, 13.07.2026"
+	Time        string         // e.g. "08:30"
 	// Waiver is set when the student deliberately gave up their room-alone right
 	// for this exam (the stored reason); empty otherwise.
 	Waiver string
@@ -203,16 +203,19 @@ func (p *Plexams) SendHandicapsMailsNTAPlanned(ctx context.Context, run bool, re
 				log.Error().Int("ancode", exam.Ancode).Str("mtknr", nta.Mtknr).Msg("exam not planned")
 				continue
 			}
-			invigilator, err := p.invigilatorForRoomAtTime(ctx, room.RoomName, start)
-			if err != nil || invigilator == nil {
+			invigilators, err := p.invigilatorsForRoomAtTime(ctx, room.RoomName, start)
+			if err != nil || len(invigilators) == 0 {
 				log.Error().Err(err).Int("ancode", exam.Ancode).Str("room", room.RoomName).
 					Time("starttime", start).
 					Msg("cannot get invigilator")
 				continue
 			}
+			invigilator := invigilators[0]
 			log.Debug().Str("mtknr", nta.Mtknr).Str("name", nta.Name).Str("room", room.RoomName).Str("invigilator", invigilator.Fullname).
 				Msg("found info")
-			ccSet.Add(invigilator.Email)
+			for _, inv := range invigilators {
+				ccSet.Add(inv.Email)
+			}
 			examsWithRoom = append(examsWithRoom, NTAEmailExamAndRoom{
 				Exam:        exam,
 				Room:        room,
@@ -59,8 +59,10 @@ type InvigilationsEmail struct {
 	TodoPerInvigilator  int
 	MaxDeviation        int
 	MinDeviation        int
-	PlanerName          string
-	Teacher             *model.Teacher
+	// SharedRooms counts the rooms (per exam time) staffed by several invigilators.
+	SharedRooms int
+	PlanerName  string
+	Teacher     *model.Teacher
 }
 
 // invigilationImagePNG returns the personal invigilation-plan PNG for an
@@ -181,9 +183,14 @@ func (p *Plexams) SendEmailPublishedInvigilations(ctx context.Context, run bool,
 		return err
 	}
 	assigned := set.NewSet[int]()
+	shared := set.NewSet[string]()
 	for _, inv := range invigilations {
 		assigned.Add(inv.InvigilatorID)
+		if inv.Position > 0 && inv.RoomName != nil && inv.Starttime != nil {
+			shared.Add(fmt.Sprintf("%d/%s", inv.Starttime.Unix(), *inv.RoomName))
+		}
 	}
+	stats.SharedRooms = shared.Cardinality()
 	missing := assigned.Difference(withCalendar).ToSlice()
 	sort.Ints(missing)
 	for _, id := range missing {
@@ -55,6 +55,11 @@ func (p *Plexams) SendEmailPublishedRooms(ctx context.Context, run bool, reporte
 		return s
 	}
 
+	nameRanges, err := p.nameRangeLabels(ctx)
+	if err != nil {
+		return err
+	}
+
 	subject := fmt.Sprintf("[Prüfungsplanung %s] Ihre Prüfungsräume", p.semester)
 
 	sent := 0
@@ -75,6 +80,9 @@ func (p *Plexams) SendEmailPublishedRooms(ctx context.Context, run bool, reporte
 		exams := make([]*email.PublishedRoomsExam, 0, len(plannedExams))
 		for _, exam := range plannedExams {
 			if e := email.BuildPublishedRoomsExam(exam, examsInSlot, examerShort); e != nil {
+				for _, room := range e.Rooms {
+					room.NameRange = nameRanges[e.Ancode][room.RoomName]
+				}
 				exams = append(exams, e)
 			}
 		}
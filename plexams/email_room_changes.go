@@ -0,0 +1,91 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/rs/zerolog/log"
+)
+
+type RoomChangeMailData struct {
+	Teacher     *model.Teacher
+	Room        string
+	Reason      string
+	Lines       []string
+	Invigilator bool
+	PlanerName  string
+}
+
+// SendEmailRoomChanges sends the queued room-change notifications of confirmed room
+// outages, one email per examiner or invigilator and outage. A notification is only
+// marked as sent on a real run.
+func (p *Plexams) SendEmailRoomChanges(ctx context.Context, run bool, reporter Reporter) error {
+	reporter.Step("collecting pending room-change notifications")
+	notifications, err := p.dbClient.RoomChangeNotifications(ctx, true)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot get room-change notifications")
+		return err
+	}
+	if len(notifications) == 0 {
+		reporter.StopProgress("no pending room-change notifications")
+		return nil
+	}
+
+	sent := 0
+	for _, n := range notifications {
+		if err := p.sendEmailRoomChange(ctx, n, run, reporter); err != nil {
+			log.Error().Err(err).Int("teacher", n.TeacherID).Str("room", n.Room).
+				Msg("cannot send room-change email")
+			continue
+		}
+		sent++
+		if run {
+			if err := p.dbClient.SetRoomChangeNotificationSent(ctx, n.ID, time.Now()); err != nil {
+				return err
+			}
+		}
+	}
+
+	reporter.StopProgress(fmt.Sprintf("sent %d of %d emails", sent, len(notifications)))
+	return nil
+}
+
+func (p *Plexams) sendEmailRoomChange(ctx context.Context, n *model.RoomChangeNotification, run bool, reporter Reporter) error {
+	teacher, err := p.GetTeacher(ctx, n.TeacherID)
+	if err != nil {
+		return err
+	}
+	if teacher == nil {
+		return fmt.Errorf("teacher %d not found", n.TeacherID)
+	}
+
+	reporter.Step(fmt.Sprintf("sending room-change email to %s", teacher.Fullname))
+
+	mailData := &RoomChangeMailData{
+		Teacher:     teacher,
+		Room:        n.Room,
+		Lines:       n.Lines,
+		Invigilator: n.Role == model.RoomChangeRoleInvigilator,
+		PlanerName:  p.planer.Name,
+	}
+	if n.Reason != nil {
+		mailData.Reason = *n.Reason
+	}
+
+	text, html, err := p.mailRenderer().Render("roomChangeEmail.md.tmpl", true, mailData)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("[Prüfungsplanung %s] Raumänderung", p.semester)
+
+	if err := p.sendMail(run, []string{teacher.Email}, nil, subject, text, html, nil, true); err != nil {
+		reporter.Warnf("error while sending email to %s: %v", teacher.Fullname, err)
+		return err
+	}
+
+	reporter.Printf("  ✓ sent to %s (%d changes) %s", teacher.Fullname, len(n.Lines), p.recipientInfo(run, teacher.Email))
+	return nil
+}
@@ -3,14 +3,16 @@ package plexams
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/obcode/plexams.go/plexams/email"
 )
 
 // SendEmailRoomRequests sends the request for building-management rooms to the
 // Gebäudemanagement. It lists all active room requests grouped by room and then
-// by day, with their (buffered) time ranges. run == false is a dry run that only
-// mails the dry-run recipient.
+// by day, with their (buffered) time ranges, and attaches them as XLSX (to be
+// returned with the answers, see ImportRoomRequestAnswers) and as ICS invites. run ==
+// false is a dry run that only mails the dry-run recipient.
 func (p *Plexams) SendEmailRoomRequests(ctx context.Context, run bool, reporter Reporter) error {
 	if err := p.emailSendAllowed(ctx, condRoomRequestsSent, run); err != nil {
 		return err
@@ -41,7 +43,23 @@ func (p *Plexams) SendEmailRoomRequests(ctx context.Context, run bool, reporter
 
 	subject := fmt.Sprintf("[Prüfungsplanung %s] Raumanfrage für die Prüfungsplanung", p.semester)
 
-	if err := p.sendMail(run, []string{p.semesterConfig.Emails.RoomManagement}, nil, subject, text, html, nil, false); err != nil {
+	// the same requests machine-readable: the table to return with the answers and an
+	// invite per room and time
+	table, err := p.RoomRequestsXLSXBytes(ctx)
+	if err != nil {
+		return err
+	}
+	invites, err := p.roomRequestInvites(ctx)
+	if err != nil {
+		return err
+	}
+	attachments := append([]*mailAttachment{{
+		Filename:    fmt.Sprintf("Raumanfragen_%s.xlsx", strings.ReplaceAll(p.semester, " ", "_")),
+		ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		Content:     table,
+	}}, invites...)
+
+	if err := p.sendMail(run, []string{p.semesterConfig.Emails.RoomManagement}, nil, subject, text, html, attachments, false); err != nil {
 		return err
 	}
 	if run {
@@ -391,6 +391,21 @@ var emailTemplateCatalog = map[string]emailTemplateInfo{
 		},
 	},
 
+	"invigilationSwapEmail.md.tmpl": {
+		Description: "An eine Aufsicht: ein genehmigter Aufsichtentausch mit einer Kollegin/einem Kollegen, aktualisierter Plan als ICS im Anhang.",
+		Jira:        true,
+		Variables: []emailTemplateVar{
+			v("{{ .Teacher.Fullname }}", "Voller Name der Aufsicht.", "Prof. Dr. Erika Mustermann"),
+			v("{{ range .Lines }}", "Schleife über die getauschten Aufsichten (je eine Zeile).", "1 Aufsicht"),
+			v("{{ .PlanerName }}", "Name der/des Planenden (Unterschrift).", samplePlanerName),
+		},
+		Sample: map[string]any{
+			"Teacher":    map[string]any{"Fullname": "Prof. Dr. Erika Mustermann"},
+			"Lines":      []string{"Mo, 13.07.2026 10:30 Uhr: Sie geben die Aufsicht in R1.049 an Prof. Dr. Max Muster ab"},
+			"PlanerName": samplePlanerName,
+		},
+	},
+
 	"invigilationsSecretariatEmail.md.tmpl": {
 		Description: "An das Sekretariat: die Prüfungsplanung ist abgeschlossen und im ZPA hinterlegt, der Plan kann ausgehängt werden.",
 		Jira:        false,
@@ -529,6 +544,7 @@ var emailTemplateCatalog = map[string]emailTemplateInfo{
 			v("{{ .TodoPerInvigilator }}", "100%-Deputat in Minuten.", "1200"),
 			v("{{ .MaxDeviation }}", "Minuten „zu wenig“ (Spanne).", "30"),
 			v("{{ .MinDeviation }}", "Minuten „zu viel“ (Spanne).", "45"),
+			v("{{ .SharedRooms }}", "Räume (je Prüfungszeit) mit mehreren Aufsichten; 0 = Absatz entfällt.", "2"),
 			v("{{ .PlanerName }}", "Name der/des Planenden (Unterschrift).", samplePlanerName),
 		},
 		Sample: map[string]any{
@@ -540,6 +556,7 @@ var emailTemplateCatalog = map[string]emailTemplateInfo{
 			"TodoPerInvigilator":  1200,
 			"MaxDeviation":        30,
 			"MinDeviation":        45,
+			"SharedRooms":         2,
 			"PlanerName":          samplePlanerName,
 		},
 	},
@@ -556,6 +573,7 @@ var emailTemplateCatalog = map[string]emailTemplateInfo{
 			v("{{ .Time }}", "Uhrzeit.", "10:30"),
 			v("{{ range .Rooms }}", "Schleife über die Räume einer Prüfung.", "1 Raum"),
 			v("{{ .RoomName }}", "Raumname.", "R1.234"),
+			v("{{ .NameRange }}", "Nachnamen-Bereich des Raums bei auf mehrere Räume verteilten Prüfungen (sonst leer).", "A–Ka"),
 			v("{{ .Allocations }}", "Liste der Belegungszeilen (Studierende, Reserve, NTA).", "20 Studierende; +2 Reserve"),
 			v("{{ range .SharedWith }}", "Schleife über mitnutzende Prüfungen.", "1 Prüfung"),
 			v("{{ .ExamHeader }}", "Überschrift der mitnutzenden Prüfung.", "1250. Software Engineering"),
@@ -570,6 +588,7 @@ var emailTemplateCatalog = map[string]emailTemplateInfo{
 					"Rooms": []any{
 						map[string]any{
 							"RoomName":    "R1.234",
+							"NameRange":   "A–Ka",
 							"Allocations": []string{"20 Studierende", "+2 Reserve"},
 							"SharedWith": []any{
 								map[string]any{"ExamHeader": "1250. Software Engineering (Prof. Kollege)", "Allocations": []string{"10 Studierende"}},
@@ -581,6 +600,27 @@ var emailTemplateCatalog = map[string]emailTemplateInfo{
 		},
 	},
 
+	"roomChangeEmail.md.tmpl": {
+		Description: "An eine:n Prüfende:n oder eine Aufsicht: geänderte Räume/Aufsichten, weil ein Raum kurzfristig ausfällt.",
+		Jira:        true,
+		Variables: []emailTemplateVar{
+			v("{{ .Teacher.Fullname }}", "Voller Name der/des Empfangenden.", "Prof. Dr. Erika Mustermann"),
+			v("{{ .Room }}", "Der ausgefallene Raum.", "R1.046"),
+			v("{{ .Reason }}", "Grund des Ausfalls (optional).", "Wasserschaden"),
+			v("{{ .Invigilator }}", "true, wenn die Mail an eine Aufsicht geht.", "false"),
+			v("{{ range .Lines }}", "Schleife über die Änderungen (je eine Zeile).", "1 Änderung"),
+			v("{{ .PlanerName }}", "Name der/des Planenden (Unterschrift).", samplePlanerName),
+		},
+		Sample: map[string]any{
+			"Teacher":     map[string]any{"Fullname": "Prof. Dr. Erika Mustermann"},
+			"Room":        "R1.046",
+			"Reason":      "Wasserschaden",
+			"Invigilator": false,
+			"Lines":       []string{"324. Datenbanken, Mo, 13.07.2026 10:30 Uhr: jetzt R1.049, R1.051 (bisher R1.046)"},
+			"PlanerName":  samplePlanerName,
+		},
+	},
+
 	"roomRequestEmail.md.tmpl": {
 		Description: "An das Gebäudemanagement: Raumanforderung mit Tagen und Zeiten (inkl. Vor-/Nachlauf).",
 		Jira:        false,
@@ -212,18 +212,7 @@ func cumulativeOverloads(exams []cumExam, intervals []bookedRoomInterval, stopAt
 	var out []cumOverload
 	for i := 0; i+1 < len(times); i++ {
 		mid := times[i].Add(times[i+1].Sub(times[i]) / 2)
-		exahmSeats, totalSeats := 0, 0
-		for _, iv := range intervals {
-			if iv.from.After(mid) || !iv.until.After(mid) {
-				continue
-			}
-			if iv.exahm {
-				exahmSeats += iv.seats
-				totalSeats += iv.seats
-			} else if iv.seb {
-				totalSeats += iv.sebSeats
-			}
-		}
+		exahmSeats, totalSeats := bookedSeatsAt(intervals, mid)
 		exahmDem, totalDem := 0, 0
 		var ids []int
 		for _, e := range exams {
@@ -251,6 +240,23 @@ func cumulativeOverloads(exams []cumExam, intervals []bookedRoomInterval, stopAt
 	return out
 }
 
+// bookedSeatsAt returns the booked EXaHM seats and the booked seats of any kind (EXaHM
+// plus SEB) of the rooms whose booking covers the instant t.
+func bookedSeatsAt(intervals []bookedRoomInterval, t time.Time) (exahmSeats, totalSeats int) {
+	for _, iv := range intervals {
+		if iv.from.After(t) || !iv.until.After(t) {
+			continue
+		}
+		if iv.exahm {
+			exahmSeats += iv.seats
+			totalSeats += iv.seats
+		} else if iv.seb {
+			totalSeats += iv.sebSeats
+		}
+	}
+	return exahmSeats, totalSeats
+}
+
 // exahmWindowSeats returns how many booked seats are usable for an exam placed at start:
 // the sum of seats of booked rooms of the required kind whose Anny window fully covers the
 // exam window [start-pre, start+dur+post]. Rooms booked too short (not covering the whole
@@ -297,6 +297,10 @@ func (p *Plexams) conflictsFromSlots(ctx context.Context, slotByAncode map[int]*
 	// with the solver and the validation). constraints is also reused below for locations/foreign.
 	constraints, _ := p.ConstraintsMap(ctx)
 	locOf := func(ancode int) string { return locationOf(constraints[ancode]) }
+	sites, err := p.campusModel(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	type studInfo struct{ mtknr, name, program, group string }
 	type agg struct {
@@ -319,7 +323,7 @@ func (p *Plexams) conflictsFromSlots(ctx context.Context, slotByAncode map[int]*
 		for i := 0; i < len(placed); i++ {
 			for j := i + 1; j < len(placed); j++ {
 				si, sj := slotByAncode[placed[i]], slotByAncode[placed[j]]
-				gap := effectiveGapMinutes(examGap, p.crossCampusGapMinutes(), locOf(placed[i]), locOf(placed[j]))
+				gap := effectiveGapMinutes(examGap, p.travelMinutes(sites, locOf(placed[i]), locOf(placed[j])), locOf(placed[i]), locOf(placed[j]))
 				rank, label := conflictcalc.TimeProximity(
 					si.Starttime, endOf(placed[i], si),
 					sj.Starttime, endOf(placed[j], sj),
@@ -361,6 +365,7 @@ func (p *Plexams) conflictsFromSlots(ctx context.Context, slotByAncode map[int]*
 	// them is spurious: treat like canShareSlot (auto-accepted, info only).
 	ssRoot := p.sameSlotGroups(ctx)
 	info := p.examInfoMap(ctx)
+	repeats := p.repeatDecider(ctx)
 	foreign := func(ancode int) bool {
 		if ancode >= externalAncodeBase {
 			return true
@@ -382,9 +387,15 @@ func (p *Plexams) conflictsFromSlots(ctx context.Context, slotByAncode map[int]*
 		}
 		affected := make([]*model.ConflictStudent, 0, len(a.students))
 		for _, s := range a.students {
-			studSem := repeatcalc.SemesterOf(s.group)
-			autoAccepted := shareable || repeatcalc.RepeatForStudent(studSem, i0.repeater, i0.minSem) || repeatcalc.RepeatForStudent(studSem, i1.repeater, i1.minSem)
-			cs := &model.ConflictStudent{Mtknr: s.mtknr, Name: s.name, Program: s.program, Group: s.group, AutoAccepted: autoAccepted}
+			evidence := make([]*model.RepeatEvidence, 0, 2)
+			for k, ei := range []examInfo{i0, i1} {
+				if d := repeats.decide(s.mtknr, s.group, ei.module, ei.repeater, ei.minSem); d.Repeat {
+					evidence = append(evidence, repeatEvidence(key[k], ei.module, d))
+				}
+			}
+			autoAccepted := shareable || len(evidence) > 0
+			cs := &model.ConflictStudent{Mtknr: s.mtknr, Name: s.name, Program: s.program, Group: s.group, AutoAccepted: autoAccepted,
+				RepeatEvidence: evidence}
 			if d, ok := decs[s.mtknr]; ok {
 				dd := d
 				cs.Decision = &dd
@@ -0,0 +1,582 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/pdfgen"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Exam-day operations (see graph/exam_day.graphqls): what happens on an exam day is
+// recorded as events (check-ins, dispatched reserves, start and end of a room, absent
+// students, incidents). The dashboard is derived from the plan of the day plus these
+// events, so a wrongly recorded event is simply removed again.
+
+// examDayWatchers are the live dashboard subscriptions of this process, by semester and
+// date. A watcher is only signalled; it rebuilds the dashboard itself, so a burst of
+// events is coalesced into one update.
+var examDayWatchers = struct {
+	sync.Mutex
+	signals map[string]map[chan struct{}]bool
+}{signals: make(map[string]map[chan struct{}]bool)}
+
+func addExamDayWatcher(key string) chan struct{} {
+	examDayWatchers.Lock()
+	defer examDayWatchers.Unlock()
+	signal := make(chan struct{}, 1)
+	if examDayWatchers.signals[key] == nil {
+		examDayWatchers.signals[key] = make(map[chan struct{}]bool)
+	}
+	examDayWatchers.signals[key][signal] = true
+	return signal
+}
+
+func removeExamDayWatcher(key string, signal chan struct{}) {
+	examDayWatchers.Lock()
+	defer examDayWatchers.Unlock()
+	delete(examDayWatchers.signals[key], signal)
+	if len(examDayWatchers.signals[key]) == 0 {
+		delete(examDayWatchers.signals, key)
+	}
+}
+
+func notifyExamDayWatchers(key string) {
+	examDayWatchers.Lock()
+	defer examDayWatchers.Unlock()
+	for signal := range examDayWatchers.signals[key] {
+		select {
+		case signal <- struct{}{}:
+		default: // an update is already pending
+		}
+	}
+}
+
+func (p *Plexams) examDayKey(date time.Time) string {
+	return p.semester + "|" + date.In(time.Local).Format("2006-01-02")
+}
+
+// examDayRange is the local calendar day of date.
+func examDayRange(date time.Time) (time.Time, time.Time) {
+	y, m, d := date.In(time.Local).Date()
+	from := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	return from, from.AddDate(0, 0, 1)
+}
+
+// ExamDayEvents returns the events recorded on the day of date in the order they happened.
+func (p *Plexams) ExamDayEvents(ctx context.Context, date time.Time) ([]*model.ExamDayEvent, error) {
+	from, until := examDayRange(date)
+	return p.dbClient.ExamDayEvents(ctx, from, until)
+}
+
+// ExamDayDashboard builds the operations dashboard of the day of date.
+func (p *Plexams) ExamDayDashboard(ctx context.Context, date time.Time) (*model.ExamDayDashboard, error) {
+	from, until := examDayRange(date)
+	inDay := func(t *time.Time) bool { return t != nil && !t.Before(from) && t.Before(until) }
+
+	allRooms, err := p.dbClient.PlannedRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rooms := make([]*model.PlannedRoom, 0)
+	for _, pr := range allRooms {
+		if inDay(pr.Starttime) {
+			rooms = append(rooms, pr)
+		}
+	}
+	allInvigilations, err := p.dbClient.GetAllInvigilations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	invigilations := make([]*model.Invigilation, 0)
+	for _, inv := range allInvigilations {
+		if inDay(inv.Starttime) {
+			invigilations = append(invigilations, inv)
+		}
+	}
+	events, err := p.dbClient.ExamDayEvents(ctx, from, until)
+	if err != nil {
+		return nil, err
+	}
+
+	exams := make(map[int]*model.ZPAExam)
+	for _, pr := range rooms {
+		if _, ok := exams[pr.Ancode]; ok {
+			continue
+		}
+		zpaExam, err := p.dbClient.GetZpaExamByAncode(ctx, pr.Ancode)
+		if err != nil {
+			log.Debug().Err(err).Int("ancode", pr.Ancode).Msg("no zpa exam for planned room")
+		}
+		exams[pr.Ancode] = zpaExam
+	}
+	names := make(map[int]string)
+	addName := func(id int) {
+		if _, ok := names[id]; ok {
+			return
+		}
+		names[id] = fmt.Sprintf("#%d", id)
+		if teacher, err := p.GetTeacher(ctx, id); err == nil && teacher != nil {
+			names[id] = teacher.Fullname
+		}
+	}
+	for _, inv := range invigilations {
+		addName(inv.InvigilatorID)
+	}
+
+	return buildExamDayDashboard(from, rooms, invigilations, events, exams, names, time.Now()), nil
+}
+
+// buildExamDayDashboard derives the dashboard from the planned rooms and invigilations of
+// the day and its events (in the order they happened; the last one of a kind wins).
+func buildExamDayDashboard(day time.Time, rooms []*model.PlannedRoom, invigilations []*model.Invigilation,
+	events []*model.ExamDayEvent, exams map[int]*model.ZPAExam, names map[int]string, now time.Time,
+) *model.ExamDayDashboard {
+	type roomKey struct {
+		start int64
+		room  string
+	}
+	type personKey struct {
+		start int64
+		room  string // "" for the reserve desk
+		id    int
+	}
+	type examKey struct {
+		start  int64
+		room   string
+		ancode int
+	}
+
+	slots := make(map[int64]*model.ExamDaySlot)
+	slot := func(start time.Time) *model.ExamDaySlot {
+		s, ok := slots[start.Unix()]
+		if !ok {
+			s = &model.ExamDaySlot{Starttime: start, Rooms: make([]*model.ExamDayRoom, 0), Reserves: make([]*model.ExamDayReserve, 0)}
+			slots[start.Unix()] = s
+		}
+		return s
+	}
+	roomByKey := make(map[roomKey]*model.ExamDayRoom)
+	room := func(start time.Time, name string) *model.ExamDayRoom {
+		k := roomKey{start.Unix(), name}
+		r, ok := roomByKey[k]
+		if !ok {
+			r = &model.ExamDayRoom{
+				RoomName: name, Status: model.ExamDayRoomStatusWaiting, PlannedEnd: start,
+				Exams: make([]*model.ExamDayRoomExam, 0), Invigilators: make([]*model.ExamDayInvigilator, 0),
+			}
+			roomByKey[k] = r
+			s := slot(start)
+			s.Rooms = append(s.Rooms, r)
+		}
+		return r
+	}
+	extendEnd := func(r *model.ExamDayRoom, start time.Time, minutes int) {
+		if end := start.Add(time.Duration(minutes) * time.Minute); end.After(r.PlannedEnd) {
+			r.PlannedEnd = end
+		}
+	}
+	name := func(id int) string {
+		if n, ok := names[id]; ok {
+			return n
+		}
+		return fmt.Sprintf("#%d", id)
+	}
+
+	examByKey := make(map[examKey]*model.ExamDayRoomExam)
+	for _, pr := range rooms {
+		r := room(*pr.Starttime, pr.RoomName)
+		extendEnd(r, *pr.Starttime, pr.Duration)
+		if pr.Reserve && len(pr.StudentsInRoom) == 0 {
+			continue
+		}
+		k := examKey{pr.Starttime.Unix(), pr.RoomName, pr.Ancode}
+		e, ok := examByKey[k]
+		if !ok {
+			e = &model.ExamDayRoomExam{Ancode: pr.Ancode}
+			if zpaExam := exams[pr.Ancode]; zpaExam != nil {
+				e.Module, e.MainExamer = zpaExam.Module, zpaExam.MainExamer
+			}
+			examByKey[k] = e
+			r.Exams = append(r.Exams, e)
+		}
+		e.Students += len(pr.StudentsInRoom)
+	}
+
+	reserveByKey := make(map[personKey]*model.ExamDayReserve)
+	for _, inv := range invigilations {
+		if inv.IsReserve || inv.RoomName == nil {
+			s := slot(*inv.Starttime)
+			res := &model.ExamDayReserve{InvigilatorID: inv.InvigilatorID, Name: name(inv.InvigilatorID)}
+			reserveByKey[personKey{inv.Starttime.Unix(), "", inv.InvigilatorID}] = res
+			s.Reserves = append(s.Reserves, res)
+			continue
+		}
+		r := room(*inv.Starttime, *inv.RoomName)
+		extendEnd(r, *inv.Starttime, inv.Duration)
+		r.Invigilators = append(r.Invigilators, &model.ExamDayInvigilator{
+			InvigilatorID: inv.InvigilatorID, Name: name(inv.InvigilatorID),
+			Position: inv.Position, SelfInvigilation: inv.IsSelfInvigilation,
+		})
+	}
+
+	checkIns := make(map[personKey]time.Time)
+	absent := make(map[examKey]int)
+	incidents := make([]*model.ExamDayEvent, 0)
+	for _, ev := range events {
+		start := ev.Starttime.Unix()
+		roomName := ""
+		if ev.RoomName != nil {
+			roomName = *ev.RoomName
+		}
+		switch ev.Kind {
+		case model.ExamDayEventKindCheckIn:
+			if ev.InvigilatorID != nil {
+				checkIns[personKey{start, roomName, *ev.InvigilatorID}] = ev.At
+			}
+		case model.ExamDayEventKindDispatch:
+			if ev.InvigilatorID == nil || ev.RoomName == nil {
+				continue
+			}
+			if res := reserveByKey[personKey{start, "", *ev.InvigilatorID}]; res != nil {
+				at := ev.At
+				res.DispatchedTo, res.DispatchedAt = ev.RoomName, &at
+			}
+		case model.ExamDayEventKindStart, model.ExamDayEventKindEnd:
+			r := roomByKey[roomKey{start, roomName}]
+			if r == nil {
+				continue
+			}
+			at := ev.At
+			if ev.Kind == model.ExamDayEventKindStart {
+				r.StartedAt = &at
+			} else {
+				r.EndedAt = &at
+			}
+		case model.ExamDayEventKindAbsent:
+			if ev.Ancode != nil && ev.Count != nil {
+				absent[examKey{start, roomName, *ev.Ancode}] = *ev.Count
+			}
+		case model.ExamDayEventKindIncident:
+			incidents = append(incidents, ev)
+			if r := roomByKey[roomKey{start, roomName}]; r != nil {
+				r.Incidents++
+			}
+		}
+	}
+
+	for k, res := range reserveByKey {
+		if at, ok := checkIns[k]; ok {
+			res.CheckedInAt = &at
+		}
+		if res.DispatchedTo == nil {
+			continue
+		}
+		r := roomByKey[roomKey{k.start, *res.DispatchedTo}]
+		if r == nil {
+			continue
+		}
+		r.Invigilators = append(r.Invigilators, &model.ExamDayInvigilator{
+			InvigilatorID: res.InvigilatorID, Name: res.Name, Position: 1 << 16, Dispatched: true,
+			CheckedInAt: res.CheckedInAt,
+		})
+	}
+
+	dashboard := &model.ExamDayDashboard{
+		Date: day, Slots: make([]*model.ExamDaySlot, 0, len(slots)), Incidents: incidents, GeneratedAt: now,
+	}
+	for rk, r := range roomByKey {
+		sort.SliceStable(r.Invigilators, func(i, j int) bool {
+			a, b := r.Invigilators[i], r.Invigilators[j]
+			if a.Position != b.Position {
+				return a.Position < b.Position
+			}
+			return a.Name < b.Name
+		})
+		planned, present := 0, 0
+		for i, inv := range r.Invigilators {
+			if at, ok := checkIns[personKey{rk.start, rk.room, inv.InvigilatorID}]; ok {
+				inv.CheckedInAt = &at
+			}
+			if inv.Dispatched {
+				inv.Position = i
+			} else {
+				planned++
+			}
+			if inv.CheckedInAt != nil {
+				present++
+			}
+		}
+		sort.Slice(r.Exams, func(i, j int) bool { return r.Exams[i].Ancode < r.Exams[j].Ancode })
+		for _, e := range r.Exams {
+			if n, ok := absent[examKey{rk.start, rk.room, e.Ancode}]; ok {
+				n := n
+				e.Absent = &n
+				r.Absent += n
+				dashboard.Absent += n
+			}
+			dashboard.Students += e.Students
+		}
+		switch {
+		case r.EndedAt != nil:
+			r.Status = model.ExamDayRoomStatusEnded
+		case r.StartedAt != nil:
+			r.Status = model.ExamDayRoomStatusRunning
+		case present > 0 && present >= planned:
+			r.Status = model.ExamDayRoomStatusReady
+		}
+	}
+	for _, s := range slots {
+		sort.Slice(s.Rooms, func(i, j int) bool { return s.Rooms[i].RoomName < s.Rooms[j].RoomName })
+		sort.Slice(s.Reserves, func(i, j int) bool { return s.Reserves[i].Name < s.Reserves[j].Name })
+		dashboard.Slots = append(dashboard.Slots, s)
+	}
+	sort.Slice(dashboard.Slots, func(i, j int) bool { return dashboard.Slots[i].Starttime.Before(dashboard.Slots[j].Starttime) })
+	return dashboard
+}
+
+// validateExamDayEvent checks an event against the dashboard of its day: the exam time,
+// the room, the invigilator and the exam must be part of the plan.
+func validateExamDayEvent(d *model.ExamDayDashboard, ev *model.ExamDayEvent) error {
+	when := ev.Starttime.In(time.Local).Format("02.01.06 15:04")
+	var s *model.ExamDaySlot
+	for _, candidate := range d.Slots {
+		if candidate.Starttime.Equal(ev.Starttime) {
+			s = candidate
+		}
+	}
+	if s == nil {
+		return fmt.Errorf("no exam planned at %s", when)
+	}
+	var r *model.ExamDayRoom
+	if ev.RoomName != nil {
+		for _, candidate := range s.Rooms {
+			if candidate.RoomName == *ev.RoomName {
+				r = candidate
+			}
+		}
+		if r == nil {
+			return fmt.Errorf("room %s is not planned at %s", *ev.RoomName, when)
+		}
+	}
+	reserve := func() *model.ExamDayReserve {
+		for _, res := range s.Reserves {
+			if ev.InvigilatorID != nil && res.InvigilatorID == *ev.InvigilatorID {
+				return res
+			}
+		}
+		return nil
+	}
+	needRoom := func() error {
+		if r == nil {
+			return fmt.Errorf("%s needs a room", strings.ToLower(string(ev.Kind)))
+		}
+		return nil
+	}
+
+	switch ev.Kind {
+	case model.ExamDayEventKindCheckIn:
+		if ev.InvigilatorID == nil {
+			return fmt.Errorf("check-in needs an invigilator")
+		}
+		if r == nil {
+			if reserve() == nil {
+				return fmt.Errorf("invigilator %d is no reserve at %s", *ev.InvigilatorID, when)
+			}
+			return nil
+		}
+		for _, inv := range r.Invigilators {
+			if inv.InvigilatorID == *ev.InvigilatorID {
+				return nil
+			}
+		}
+		return fmt.Errorf("invigilator %d is not planned in %s at %s (dispatch a reserve first)", *ev.InvigilatorID, *ev.RoomName, when)
+	case model.ExamDayEventKindDispatch:
+		if err := needRoom(); err != nil {
+			return err
+		}
+		if reserve() == nil {
+			return fmt.Errorf("invigilator %d is no reserve at %s", *ev.InvigilatorID, when)
+		}
+	case model.ExamDayEventKindStart:
+		return needRoom()
+	case model.ExamDayEventKindEnd:
+		if err := needRoom(); err != nil {
+			return err
+		}
+		if r.StartedAt != nil && ev.At.Before(*r.StartedAt) {
+			return fmt.Errorf("the end of %s is before its start", r.RoomName)
+		}
+	case model.ExamDayEventKindAbsent:
+		if err := needRoom(); err != nil {
+			return err
+		}
+		for _, e := range r.Exams {
+			if ev.Ancode != nil && e.Ancode == *ev.Ancode {
+				if ev.Count == nil || *ev.Count < 0 || *ev.Count > e.Students {
+					return fmt.Errorf("absent students of %d in %s must be between 0 and %d", e.Ancode, r.RoomName, e.Students)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("exam %d is not planned in %s at %s", *ev.Ancode, r.RoomName, when)
+	case model.ExamDayEventKindIncident:
+		if ev.Note == nil || strings.TrimSpace(*ev.Note) == "" {
+			return fmt.Errorf("an incident needs a description")
+		}
+		if ev.Ancode == nil {
+			return nil
+		}
+		for _, candidate := range s.Rooms {
+			if r != nil && candidate != r {
+				continue
+			}
+			for _, e := range candidate.Exams {
+				if e.Ancode == *ev.Ancode {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("exam %d is not planned there at %s", *ev.Ancode, when)
+	}
+	return nil
+}
+
+// recordExamDayEvent validates and stores an event and updates the live dashboards. at
+// defaults to now.
+func (p *Plexams) recordExamDayEvent(ctx context.Context, ev *model.ExamDayEvent, at *time.Time) (*model.ExamDayEvent, error) {
+	now := time.Now()
+	ev.At = now
+	if at != nil {
+		ev.At = *at
+	}
+	dashboard, err := p.ExamDayDashboard(ctx, ev.Starttime)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateExamDayEvent(dashboard, ev); err != nil {
+		return nil, err
+	}
+	ev.ID = primitive.NewObjectID().Hex()
+	ev.CreatedAt = now
+	ev.RecordedBy = p.OperatorID()
+	if ev.InvigilatorID != nil {
+		if teacher, err := p.GetTeacher(ctx, *ev.InvigilatorID); err == nil && teacher != nil {
+			ev.InvigilatorName = &teacher.Fullname
+		}
+	}
+	if err := p.dbClient.AddExamDayEvent(ctx, ev); err != nil {
+		return nil, err
+	}
+	notifyExamDayWatchers(p.examDayKey(ev.Starttime))
+	return ev, nil
+}
+
+// CheckInInvigilator records an invigilator arriving in a room or, with roomName nil, at
+// the desk as reserve.
+func (p *Plexams) CheckInInvigilator(ctx context.Context, starttime time.Time, roomName *string, invigilatorID int, at *time.Time) (*model.ExamDayEvent, error) {
+	return p.recordExamDayEvent(ctx, &model.ExamDayEvent{
+		Kind: model.ExamDayEventKindCheckIn, Starttime: starttime, RoomName: roomName, InvigilatorID: &invigilatorID,
+	}, at)
+}
+
+// DispatchReserve sends a reserve of the exam time to a room.
+func (p *Plexams) DispatchReserve(ctx context.Context, starttime time.Time, invigilatorID int, roomName string, note *string) (*model.ExamDayEvent, error) {
+	return p.recordExamDayEvent(ctx, &model.ExamDayEvent{
+		Kind: model.ExamDayEventKindDispatch, Starttime: starttime, RoomName: &roomName, InvigilatorID: &invigilatorID, Note: note,
+	}, nil)
+}
+
+// RecordExamStart records the actual start of the exam(s) in a room.
+func (p *Plexams) RecordExamStart(ctx context.Context, starttime time.Time, roomName string, at *time.Time) (*model.ExamDayEvent, error) {
+	return p.recordExamDayEvent(ctx, &model.ExamDayEvent{
+		Kind: model.ExamDayEventKindStart, Starttime: starttime, RoomName: &roomName,
+	}, at)
+}
+
+// RecordExamEnd records the actual end of the exam(s) in a room.
+func (p *Plexams) RecordExamEnd(ctx context.Context, starttime time.Time, roomName string, at *time.Time) (*model.ExamDayEvent, error) {
+	return p.recordExamDayEvent(ctx, &model.ExamDayEvent{
+		Kind: model.ExamDayEventKindEnd, Starttime: starttime, RoomName: &roomName,
+	}, at)
+}
+
+// RecordAbsentStudents records how many students of an exam registered in a room did not
+// show up.
+func (p *Plexams) RecordAbsentStudents(ctx context.Context, starttime time.Time, roomName string, ancode, count int) (*model.ExamDayEvent, error) {
+	return p.recordExamDayEvent(ctx, &model.ExamDayEvent{
+		Kind: model.ExamDayEventKindAbsent, Starttime: starttime, RoomName: &roomName, Ancode: &ancode, Count: &count,
+	}, nil)
+}
+
+// RecordExamDayIncident records an incident, optionally bound to a room and an exam.
+func (p *Plexams) RecordExamDayIncident(ctx context.Context, starttime time.Time, roomName *string, ancode *int, note string, at *time.Time) (*model.ExamDayEvent, error) {
+	return p.recordExamDayEvent(ctx, &model.ExamDayEvent{
+		Kind: model.ExamDayEventKindIncident, Starttime: starttime, RoomName: roomName, Ancode: ancode, Note: &note,
+	}, at)
+}
+
+// RemoveExamDayEvent removes a wrongly recorded event.
+func (p *Plexams) RemoveExamDayEvent(ctx context.Context, id string) (bool, error) {
+	ev, err := p.dbClient.RemoveExamDayEvent(ctx, id)
+	if err != nil || ev == nil {
+		return false, err
+	}
+	notifyExamDayWatchers(p.examDayKey(ev.Starttime))
+	return true, nil
+}
+
+// WatchExamDayDashboard streams the dashboard of the day of date: at once and again after
+// every recorded or removed event of that day, until ctx is done.
+func (p *Plexams) WatchExamDayDashboard(ctx context.Context, date time.Time) (<-chan *model.ExamDayDashboard, error) {
+	first, err := p.ExamDayDashboard(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	key := p.examDayKey(date)
+	signal := addExamDayWatcher(key)
+	ch := make(chan *model.ExamDayDashboard, 1)
+	ch <- first
+
+	go func() {
+		defer close(ch)
+		defer removeExamDayWatcher(key, signal)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signal:
+				dashboard, err := p.ExamDayDashboard(ctx, date)
+				if err != nil {
+					log.Error().Err(err).Str("day", key).Msg("cannot build exam-day dashboard")
+					continue
+				}
+				select {
+				case ch <- dashboard:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// ExamDayProtocolPDFBytes builds the end-of-day protocol of the day of date as PDF bytes.
+func (p *Plexams) ExamDayProtocolPDFBytes(ctx context.Context, date time.Time) ([]byte, error) {
+	dashboard, err := p.ExamDayDashboard(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	events, err := p.ExamDayEvents(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	return marotoBytes(pdfgen.ExamDayProtocol(p.semesterFull(), dashboard, events))
+}
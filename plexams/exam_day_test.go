@@ -0,0 +1,102 @@
+package plexams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+func examDayFixture() (time.Time, []*model.PlannedRoom, []*model.Invigilation, map[int]*model.ZPAExam, map[int]string) {
+	start := time.Date(2026, 7, 13, 10, 30, 0, 0, time.Local)
+	r1, r2 := "R1.049", "R1.046"
+	rooms := []*model.PlannedRoom{
+		{Starttime: &start, RoomName: r1, Ancode: 324, Duration: 90, StudentsInRoom: []string{"1", "2", "3"}},
+		{Starttime: &start, RoomName: r1, Ancode: 325, Duration: 60, StudentsInRoom: []string{"4"}},
+		{Starttime: &start, RoomName: r2, Ancode: 324, Duration: 120, StudentsInRoom: []string{"5"}, Handicap: true},
+	}
+	invigilations := []*model.Invigilation{
+		{Starttime: &start, RoomName: &r1, InvigilatorID: 1, Duration: 90},
+		{Starttime: &start, RoomName: &r1, InvigilatorID: 2, Duration: 90, Position: 1},
+		{Starttime: &start, RoomName: &r2, InvigilatorID: 3, Duration: 120},
+		{Starttime: &start, IsReserve: true, InvigilatorID: 4, Duration: 120},
+	}
+	exams := map[int]*model.ZPAExam{324: {AnCode: 324, Module: "Datenbanken", MainExamer: "Prof. Dr. Erika Mustermann"}}
+	names := map[int]string{1: "A", 2: "B", 3: "C", 4: "D"}
+	return start, rooms, invigilations, exams, names
+}
+
+func TestBuildExamDayDashboard(t *testing.T) {
+	start, rooms, invigilations, exams, names := examDayFixture()
+	r1, r2 := "R1.049", "R1.046"
+	one, two, three, four := 1, 2, 3, 4
+	ancode, count := 324, 1
+	at := func(min int) time.Time { return start.Add(time.Duration(min) * time.Minute) }
+	events := []*model.ExamDayEvent{
+		{Kind: model.ExamDayEventKindCheckIn, Starttime: start, RoomName: &r1, InvigilatorID: &one, At: at(-20)},
+		{Kind: model.ExamDayEventKindCheckIn, Starttime: start, InvigilatorID: &four, At: at(-15)},
+		{Kind: model.ExamDayEventKindDispatch, Starttime: start, RoomName: &r1, InvigilatorID: &four, At: at(-10)},
+		{Kind: model.ExamDayEventKindCheckIn, Starttime: start, RoomName: &r2, InvigilatorID: &three, At: at(-5)},
+		{Kind: model.ExamDayEventKindStart, Starttime: start, RoomName: &r1, At: at(0)},
+		{Kind: model.ExamDayEventKindAbsent, Starttime: start, RoomName: &r1, Ancode: &ancode, Count: &two},
+		{Kind: model.ExamDayEventKindAbsent, Starttime: start, RoomName: &r1, Ancode: &ancode, Count: &count},
+		{Kind: model.ExamDayEventKindIncident, Starttime: start, RoomName: &r1, At: at(30)},
+		{Kind: model.ExamDayEventKindEnd, Starttime: start, RoomName: &r1, At: at(95)},
+	}
+
+	d := buildExamDayDashboard(start, rooms, invigilations, events, exams, names, start)
+	if len(d.Slots) != 1 || len(d.Slots[0].Rooms) != 2 {
+		t.Fatalf("want one slot with two rooms, got %+v", d.Slots)
+	}
+	if d.Students != 5 || d.Absent != 1 || len(d.Incidents) != 1 {
+		t.Errorf("students/absent/incidents = %d/%d/%d, want 5/1/1 (last absent count wins)", d.Students, d.Absent, len(d.Incidents))
+	}
+
+	big, small := d.Slots[0].Rooms[1], d.Slots[0].Rooms[0] // sorted by name: R1.046, R1.049
+	if big.RoomName != r1 || big.Status != model.ExamDayRoomStatusEnded || big.Incidents != 1 {
+		t.Errorf("R1.049 = %+v", big)
+	}
+	if !big.PlannedEnd.Equal(at(90)) || big.Exams[0].Module != "Datenbanken" || big.Exams[0].Students != 3 {
+		t.Errorf("R1.049 planned end/exams = %v %+v", big.PlannedEnd, big.Exams[0])
+	}
+	if n := len(big.Invigilators); n != 3 || !big.Invigilators[2].Dispatched || big.Invigilators[2].CheckedInAt == nil {
+		t.Errorf("R1.049 invigilators: want the two planned and the dispatched reserve last, got %+v", big.Invigilators)
+	}
+	if small.Status != model.ExamDayRoomStatusReady || !small.PlannedEnd.Equal(at(120)) {
+		t.Errorf("R1.046 = %+v, want READY until 12:30", small)
+	}
+	res := d.Slots[0].Reserves[0]
+	if res.DispatchedTo == nil || *res.DispatchedTo != r1 || res.CheckedInAt == nil {
+		t.Errorf("reserve = %+v", res)
+	}
+}
+
+func TestValidateExamDayEvent(t *testing.T) {
+	start, rooms, invigilations, exams, names := examDayFixture()
+	d := buildExamDayDashboard(start, rooms, invigilations, nil, exams, names, start)
+	r1, r2, unknown := "R1.049", "R1.046", "R0.001"
+	one, three, four, ancode, tooMany, note := 1, 3, 4, 324, 4, "Feueralarm"
+
+	cases := []struct {
+		name string
+		ev   *model.ExamDayEvent
+		ok   bool
+	}{
+		{"planned check-in", &model.ExamDayEvent{Kind: model.ExamDayEventKindCheckIn, Starttime: start, RoomName: &r1, InvigilatorID: &one}, true},
+		{"check-in in the wrong room", &model.ExamDayEvent{Kind: model.ExamDayEventKindCheckIn, Starttime: start, RoomName: &r1, InvigilatorID: &three}, false},
+		{"reserve at the desk", &model.ExamDayEvent{Kind: model.ExamDayEventKindCheckIn, Starttime: start, InvigilatorID: &four}, true},
+		{"no reserve at the desk", &model.ExamDayEvent{Kind: model.ExamDayEventKindCheckIn, Starttime: start, InvigilatorID: &one}, false},
+		{"dispatch a reserve", &model.ExamDayEvent{Kind: model.ExamDayEventKindDispatch, Starttime: start, RoomName: &r2, InvigilatorID: &four}, true},
+		{"dispatch a non-reserve", &model.ExamDayEvent{Kind: model.ExamDayEventKindDispatch, Starttime: start, RoomName: &r2, InvigilatorID: &one}, false},
+		{"unknown room", &model.ExamDayEvent{Kind: model.ExamDayEventKindStart, Starttime: start, RoomName: &unknown}, false},
+		{"unknown exam time", &model.ExamDayEvent{Kind: model.ExamDayEventKindStart, Starttime: start.Add(time.Hour), RoomName: &r1}, false},
+		{"too many absent", &model.ExamDayEvent{Kind: model.ExamDayEventKindAbsent, Starttime: start, RoomName: &r1, Ancode: &ancode, Count: &tooMany}, false},
+		{"incident without text", &model.ExamDayEvent{Kind: model.ExamDayEventKindIncident, Starttime: start}, false},
+		{"incident of an exam", &model.ExamDayEvent{Kind: model.ExamDayEventKindIncident, Starttime: start, Ancode: &ancode, Note: &note}, true},
+	}
+	for _, c := range cases {
+		if err := validateExamDayEvent(d, c.ev); (err == nil) != c.ok {
+			t.Errorf("%s: err = %v, want ok = %v", c.name, err, c.ok)
+		}
+	}
+}
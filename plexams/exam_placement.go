@@ -0,0 +1,188 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/examplan"
+	"github.com/obcode/plexams.go/plexams/pdfgen"
+	"github.com/rs/zerolog/log"
+)
+
+// ExamPlacementExplanation explains the placement of one exam in the saved plan: it builds
+// the exam-schedule problem exactly as the generator would (ratings applied), loads the
+// saved plan into it unchanged (examplan.FromPlan) and asks the solver state, for every
+// other start time, what rules it out or what moving there would cost. The binding
+// constraints are rendered from the exam's constraints and plan entry. Used to answer
+// "why is my exam on Saturday?" support tickets.
+func (p *Plexams) ExamPlacementExplanation(ctx context.Context, ancode int) (*model.ExamPlacementExplanation, error) {
+	exam, err := p.AssembledExam(ctx, ancode)
+	if err != nil || exam == nil {
+		return nil, fmt.Errorf("exam %d does not exist", ancode)
+	}
+	prob, buildInfo, err := p.buildExamPlanProblem(ctx, true, false, false)
+	if err != nil {
+		return nil, err
+	}
+	u := prob.UnitOf(ancode)
+	if u < 0 {
+		return nil, fmt.Errorf("exam %d is not part of the exam schedule (no registrations, or fixed without a time)", ancode)
+	}
+	st := examplan.FromPlan(prob)
+	ex := st.Explain(u)
+
+	constraints, err := p.ConstraintsMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	planEntries, err := p.PlanEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pe *model.PlanEntry
+	for _, e := range planEntries {
+		if e.Ancode == ancode {
+			pe = e
+			break
+		}
+	}
+	students := make(map[string]*model.Student)
+	if regs, err := p.StudentRegsPerStudentPlanned(ctx); err == nil {
+		for _, s := range regs {
+			students[s.Mtknr] = s
+		}
+	} else {
+		log.Error().Err(err).Msg("cannot get students for placement explanation")
+	}
+
+	unit := prob.Units[u]
+	out := &model.ExamPlacementExplanation{
+		Ancode:             ancode,
+		SameSlotAncodes:    unit.Ancodes,
+		Fixed:              unit.Fixed,
+		BindingConstraints: bindingConstraints(constraints[ancode], pe, unit, len(prob.Slots), buildInfo.unplaceableReason[ancode]),
+		AllowedStarttimes:  ex.Allowed,
+		HardViolations:     []string{},
+	}
+	if exam.ZpaExam != nil {
+		out.Module = exam.ZpaExam.Module
+		out.MainExamer = exam.ZpaExam.MainExamer
+	}
+	if ex.Slot >= 0 {
+		start := prob.Slots[ex.Slot].Start
+		out.Starttime = &start
+	}
+	for _, v := range prob.Registry().HardViolations(st) {
+		if slices.Contains(v.Refs, ancode) {
+			out.HardViolations = append(out.HardViolations, fmt.Sprintf("%s: %s %v", v.Constraint, v.Message, v.Refs))
+		}
+	}
+	for _, o := range ex.Options {
+		alt := &model.PlacementAlternative{
+			Starttime: prob.Slots[o.Slot].Start,
+			Current:   o.Current,
+			Feasible:  o.Feasible,
+			CostDelta: o.CostDelta,
+			Blockers:  make([]*model.PlacementBlocker, 0, len(o.Blockers)),
+		}
+		for _, b := range o.Blockers {
+			blocker := &model.PlacementBlocker{
+				Constraint: b.Constraint,
+				Message:    b.Message,
+				Ancodes:    b.Ancodes,
+				Students:   make([]*model.PlacementStudent, 0, len(b.Students)),
+			}
+			if blocker.Ancodes == nil {
+				blocker.Ancodes = []int{}
+			}
+			for _, mtknr := range b.Students {
+				ps := &model.PlacementStudent{Mtknr: mtknr}
+				if s := students[mtknr]; s != nil {
+					ps.Name = s.Name
+					ps.Program = s.Program
+				}
+				blocker.Students = append(blocker.Students, ps)
+			}
+			alt.Blockers = append(alt.Blockers, blocker)
+		}
+		out.Alternatives = append(out.Alternatives, alt)
+	}
+	return out, nil
+}
+
+// bindingConstraints renders the constraints that restrict an exam's placement as plain
+// (German) text lines for the explanation and its PDF.
+func bindingConstraints(c *model.Constraints, pe *model.PlanEntry, unit examplan.Unit, nSlots int, unplaceable string) []string {
+	lines := make([]string, 0)
+	if pe != nil && pe.Locked {
+		lines = append(lines, "manuell gesperrt (Termin bleibt fest)")
+	}
+	if pe != nil && pe.PhaseFixed {
+		lines = append(lines, "in der EXaHM/SEB-Raumphase fixiert")
+	}
+	if unit.Foreign {
+		lines = append(lines, "extern / von einer anderen Fakultät geplant")
+	}
+	if c != nil {
+		if c.FixedTime != nil {
+			lines = append(lines, "feste Zeit: "+c.FixedTime.Local().Format("02.01.06 15:04"))
+		}
+		if c.FixedDay != nil {
+			lines = append(lines, "fester Tag: "+c.FixedDay.Local().Format("02.01.06"))
+		}
+		if len(c.PossibleDays) > 0 {
+			lines = append(lines, "mögliche Tage: "+formatDays(c.PossibleDays))
+		}
+		if len(c.ExcludeDays) > 0 {
+			lines = append(lines, "ausgeschlossene Tage: "+formatDays(c.ExcludeDays))
+		}
+		if c.Location != nil && *c.Location != "" {
+			lines = append(lines, fmt.Sprintf("Standort %s (Reisezeit zu Prüfungen an anderen Standorten)", *c.Location))
+		}
+	}
+	if len(unit.Ancodes) > 1 {
+		lines = append(lines, fmt.Sprintf("gleicher Slot (sameSlot) mit %v", unit.Ancodes))
+	}
+	if unit.Exahm {
+		lines = append(lines, "EXaHM: nur Startzeiten mit genügend gebuchten EXaHM-Plätzen, deren Buchung das Prüfungsfenster abdeckt")
+	}
+	if unit.Seb {
+		lines = append(lines, "SEB: bevorzugt Startzeiten mit gebuchten SEB-Plätzen")
+	}
+	if unplaceable != "" {
+		lines = append(lines, "nicht planbar: "+unplaceable)
+	}
+	if n := len(unit.Allowed); n > 0 {
+		allowed := n
+		if n == 1 && unit.Allowed[0] < 0 {
+			allowed = 0
+		}
+		lines = append(lines, fmt.Sprintf("%d von %d Startzeiten zulässig", allowed, nSlots))
+	}
+	return lines
+}
+
+func formatDays(days []*time.Time) string {
+	out := make([]string, 0, len(days))
+	for _, d := range days {
+		if d != nil {
+			out = append(out, d.Local().Format("02.01."))
+		}
+	}
+	sort.Strings(out)
+	return strings.Join(out, ", ")
+}
+
+// ExamPlacementPDFBytes renders the placement explanation of one exam as a PDF.
+func (p *Plexams) ExamPlacementPDFBytes(ctx context.Context, ancode int) ([]byte, error) {
+	ex, err := p.ExamPlacementExplanation(ctx, ancode)
+	if err != nil {
+		return nil, err
+	}
+	return marotoBytes(pdfgen.PlacementExplanation(p.semesterFull(), ex))
+}
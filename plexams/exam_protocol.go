@@ -0,0 +1,318 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/examprotocol"
+	"github.com/obcode/plexams.go/plexams/pdfgen"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Exam protocols (see examprotocol): one record per exam and room after the exam, entered
+// one by one, in bulk as table or taken over from the exam-day records, and the no-show
+// statistics built from them.
+
+// ExamProtocols returns the recorded protocols, of one exam or all.
+func (p *Plexams) ExamProtocols(ctx context.Context, ancode *int) ([]*model.ExamProtocol, error) {
+	return p.dbClient.ExamProtocols(ctx, ancode)
+}
+
+// plannedExamRooms returns the exam rooms a protocol can be recorded for: the planned
+// rooms with students, one per exam and room (the students of an exam in the same room
+// summed up), with module and main examer from ZPA.
+func (p *Plexams) plannedExamRooms(ctx context.Context) ([]examprotocol.Planned, error) {
+	rooms, err := p.dbClient.PlannedRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exams := make(map[int]*model.ZPAExam)
+	byKey := make(map[string]*examprotocol.Planned)
+	order := make([]string, 0)
+	for _, pr := range rooms {
+		if pr.Starttime == nil || len(pr.StudentsInRoom) == 0 {
+			continue
+		}
+		key := examprotocol.Key(pr.Ancode, pr.RoomName)
+		if pl, ok := byKey[key]; ok {
+			pl.Registered += len(pr.StudentsInRoom)
+			if pr.Starttime.Before(pl.Start) {
+				pl.Start = *pr.Starttime
+			}
+			continue
+		}
+		zpaExam, ok := exams[pr.Ancode]
+		if !ok {
+			zpaExam, err = p.dbClient.GetZpaExamByAncode(ctx, pr.Ancode)
+			if err != nil {
+				log.Debug().Err(err).Int("ancode", pr.Ancode).Msg("no zpa exam for planned room")
+			}
+			exams[pr.Ancode] = zpaExam
+		}
+		pl := &examprotocol.Planned{Ancode: pr.Ancode, Room: pr.RoomName, Start: *pr.Starttime, Registered: len(pr.StudentsInRoom)}
+		if zpaExam != nil {
+			pl.Module, pl.MainExamer = zpaExam.Module, zpaExam.MainExamer
+		}
+		byKey[key] = pl
+		order = append(order, key)
+	}
+	planned := make([]examprotocol.Planned, 0, len(order))
+	for _, key := range order {
+		planned = append(planned, *byKey[key])
+	}
+	return planned, nil
+}
+
+// saveExamProtocol stores a built protocol and records it in the mutation log.
+func (p *Plexams) saveExamProtocol(ctx context.Context, name, logType string, protocol *model.ExamProtocol) error {
+	protocol.ID = primitive.NewObjectID().Hex()
+	protocol.RecordedBy = p.OperatorID()
+	protocol.UpdatedAt = time.Now()
+	if err := p.dbClient.SaveExamProtocol(ctx, protocol); err != nil {
+		return err
+	}
+	p.LogMutation(ctx, &model.MutationLogEntry{
+		Time: time.Now(),
+		Name: name,
+		Type: logType,
+		User: p.OperatorID(),
+		Args: []*model.MutationLogArg{
+			{Key: "ancode", Value: strconv.Itoa(protocol.Ancode)},
+			{Key: "room", Value: protocol.RoomName},
+			{Key: "present", Value: strconv.Itoa(protocol.Present)},
+			{Key: "absent", Value: strconv.Itoa(protocol.Absent)},
+		},
+		Ancodes: []int{protocol.Ancode},
+	})
+	return nil
+}
+
+// SetExamProtocol records (or replaces) the protocol of an exam in a planned room.
+func (p *Plexams) SetExamProtocol(ctx context.Context, input model.ExamProtocolInput) (*model.ExamProtocol, error) {
+	planned, err := p.plannedExamRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key := examprotocol.Key(input.Ancode, input.RoomName)
+	for _, pl := range planned {
+		if examprotocol.Key(pl.Ancode, pl.Room) != key {
+			continue
+		}
+		protocol, err := examprotocol.Build(pl, input.Present, input.Absent, input.Incidents, input.ActualEnd, input.Note)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.saveExamProtocol(ctx, "setExamProtocol", "mutation", protocol); err != nil {
+			return nil, err
+		}
+		return protocol, nil
+	}
+	return nil, fmt.Errorf("exam %d is not planned in room %s", input.Ancode, input.RoomName)
+}
+
+// RemoveExamProtocol removes the protocol of an exam in a room.
+func (p *Plexams) RemoveExamProtocol(ctx context.Context, ancode int, roomName string) (bool, error) {
+	return p.dbClient.RemoveExamProtocol(ctx, ancode, roomName)
+}
+
+// TakeOverExamDayProtocols creates protocols from the exam-day records of the day of
+// date: for every exam and room with a reported absent count and no protocol yet, with
+// the recorded end of the room and the notes of its incidents concerning the exam.
+func (p *Plexams) TakeOverExamDayProtocols(ctx context.Context, date time.Time) ([]*model.ExamProtocol, error) {
+	dashboard, err := p.ExamDayDashboard(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := p.dbClient.ExamProtocols(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	recorded := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		recorded[examprotocol.Key(e.Ancode, e.RoomName)] = true
+	}
+
+	created := make([]*model.ExamProtocol, 0)
+	for _, slot := range dashboard.Slots {
+		for _, room := range slot.Rooms {
+			for _, exam := range room.Exams {
+				if exam.Absent == nil || recorded[examprotocol.Key(exam.Ancode, room.RoomName)] {
+					continue
+				}
+				incidents := make([]string, 0)
+				for _, ev := range dashboard.Incidents {
+					if ev.RoomName == nil || *ev.RoomName != room.RoomName || !ev.Starttime.Equal(slot.Starttime) ||
+						(ev.Ancode != nil && *ev.Ancode != exam.Ancode) || ev.Note == nil {
+						continue
+					}
+					incidents = append(incidents, *ev.Note)
+				}
+				pl := examprotocol.Planned{
+					Ancode: exam.Ancode, Module: exam.Module, MainExamer: exam.MainExamer,
+					Room: room.RoomName, Start: slot.Starttime, Registered: exam.Students,
+				}
+				protocol, err := examprotocol.Build(pl, nil, exam.Absent, incidents, room.EndedAt, nil)
+				if err != nil {
+					return nil, err
+				}
+				protocol.Source = model.ExamProtocolSourceExamDay
+				if err := p.saveExamProtocol(ctx, "takeOverExamDayProtocol", "mutation", protocol); err != nil {
+					return nil, err
+				}
+				created = append(created, protocol)
+			}
+		}
+	}
+	return created, nil
+}
+
+// NoShowStatistics computes the no-show statistics from the protocols of a workspace
+// (nil = the current one).
+func (p *Plexams) NoShowStatistics(ctx context.Context, workspace *string) (*model.NoShowStatistics, error) {
+	database := p.dbClient.DatabaseName()
+	if workspace != nil && strings.TrimSpace(*workspace) != "" && strings.TrimSpace(*workspace) != database {
+		database = strings.TrimSpace(*workspace)
+		if !p.dbClient.DatabaseHasConfig(ctx, database) {
+			return nil, fmt.Errorf("workspace %s not found", database)
+		}
+	}
+	protocols, err := p.dbClient.ExamProtocolsForDatabase(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := p.dbClient.PlannedRoomsForDatabase(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	roomsPerExam := make(map[int]int)
+	seen := make(map[string]bool)
+	for _, pr := range rooms {
+		key := examprotocol.Key(pr.Ancode, pr.RoomName)
+		if pr.Starttime == nil || len(pr.StudentsInRoom) == 0 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		roomsPerExam[pr.Ancode]++
+	}
+	return examprotocol.Statistics(database, protocols, roomsPerExam), nil
+}
+
+// ExamProtocolsCSVBytes builds the protocol table of all planned exam rooms, prefilled
+// with the recorded protocols.
+func (p *Plexams) ExamProtocolsCSVBytes(ctx context.Context) ([]byte, error) {
+	planned, err := p.plannedExamRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := p.dbClient.ExamProtocols(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return examprotocol.CSV(planned, existing)
+}
+
+// ImportExamProtocols reads the filled-in protocol table. dryRun (or any problem in the
+// file) only returns the diff preview. Every stored protocol is recorded in the mutation
+// log.
+func (p *Plexams) ImportExamProtocols(ctx context.Context, data []byte, dryRun bool) (*examprotocol.Import, error) {
+	entries, empty, problems, err := examprotocol.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	planned, err := p.plannedExamRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := p.dbClient.ExamProtocols(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	result := examprotocol.Diff(planned, existing, entries, empty, problems)
+	if dryRun || len(result.Problems) > 0 || len(result.Protocols) == 0 {
+		return result, nil
+	}
+	for _, protocol := range result.Protocols {
+		if err := p.saveExamProtocol(ctx, "importExamProtocol", "upload", protocol); err != nil {
+			return nil, err
+		}
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// ExamProtocolsPDFBytes builds the PDF of all recorded protocols with the no-show
+// statistics.
+func (p *Plexams) ExamProtocolsPDFBytes(ctx context.Context) ([]byte, error) {
+	protocols, err := p.dbClient.ExamProtocols(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := p.NoShowStatistics(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return marotoBytes(pdfgen.ExamProtocols(p.semesterFull(), protocols, stat))
+}
+
+// HTTPDownloadExamProtocolsTemplate streams the protocol table for bulk entry.
+// GET /download/exam-protocols.csv
+func (p *Plexams) HTTPDownloadExamProtocolsTemplate(w http.ResponseWriter, r *http.Request) {
+	data, err := p.ExamProtocolsCSVBytes(r.Context())
+	if err != nil {
+		http.Error(w, "cannot generate exam protocols: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	filename := fmt.Sprintf("%s_Prüfungsprotokolle.csv", strings.ReplaceAll(p.semester, " ", "_"))
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if _, err := w.Write(data); err != nil {
+		log.Error().Err(err).Msg("cannot write exam protocols download")
+	}
+}
+
+// HTTPUploadExamProtocols imports the filled-in protocol table (multipart field "file",
+// XLSX or CSV). With ?dryRun=true it only returns the diff preview; otherwise it stores
+// the protocols unless the file has problems.
+// POST /upload/exam-protocols
+func (p *Plexams) HTTPUploadExamProtocols(w http.ResponseWriter, r *http.Request) {
+	if !p.WritesAllowed() {
+		http.Error(w, "a validation or transfer/email is running, cannot upload now", http.StatusConflict)
+		return
+	}
+	if p.IsReadOnly() {
+		http.Error(w, "semester is read-only", http.StatusConflict)
+		return
+	}
+	if err := r.ParseMultipartForm(16 << 20); err != nil {
+		http.Error(w, "cannot parse upload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "missing file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close() //nolint:errcheck
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "cannot read file: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+	result, err := p.ImportExamProtocols(r.Context(), data, dryRun)
+	if err != nil {
+		http.Error(w, "cannot import exam protocols: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if result.Applied {
+		p.LogUpload(r.Context(), "uploadExamProtocols", "file", header.Filename, "changes", result.Summary())
+	}
+	writeJSON(w, result)
+}
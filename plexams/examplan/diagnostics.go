@@ -1,5 +1,10 @@
 package examplan
 
+import (
+	"math"
+	"sort"
+)
+
 // Diagnostics is a human-readable quality report of a solved schedule: how close
 // students' exams ended up, the worst-off student, and per-slot load. It is used to
 // judge and calibrate a run (the raw cost alone is not interpretable).
@@ -26,6 +31,25 @@ type Diagnostics struct {
 	SlotsOverThreshold int
 	MaxExamsPerSlot    int
 	InteriorHoles      int // empty slots between occupied ones on the same day (bad for invigilation)
+
+	// Programs is the per-program breakdown (students with a known program), most
+	// students first — the evidence that no program is systematically disadvantaged.
+	Programs []ProgramDiagnostics
+}
+
+// ProgramDiagnostics is the spread outcome of one study program's students.
+type ProgramDiagnostics struct {
+	Program  string
+	Students int // students of the program with at least two placed exams
+	// MeanPenalty / P90Penalty are the mean and 90th percentile of the students' spread
+	// penalty P_s (the per-student proximity cost the solver minimizes; lower = better).
+	MeanPenalty          float64
+	P90Penalty           float64
+	StudentsWithAdjacent int
+	StudentsWithSameDay  int
+	// InEquity: the program is large enough (equityMinStudents) to take part in the equity
+	// term; smaller programs are reported but not steered.
+	InEquity bool
 }
 
 // bucket classifies a placed pair by temporal proximity; returns an index into the
@@ -55,6 +79,11 @@ func (st *State) Diagnostics() Diagnostics {
 	p := st.P
 	var d Diagnostics
 
+	type progAgg struct {
+		penalties         []float64
+		adjacent, sameDay int
+	}
+	progs := make(map[string]*progAgg)
 	for si := range p.Students {
 		s := &p.Students[si]
 		hasAdj, hasSameDay := false, false
@@ -92,6 +121,20 @@ func (st *State) Diagnostics() Diagnostics {
 		}
 		if counted {
 			d.Students++
+			if s.Program != "" {
+				pa := progs[s.Program]
+				if pa == nil {
+					pa = &progAgg{}
+					progs[s.Program] = pa
+				}
+				pa.penalties = append(pa.penalties, ps)
+				if hasAdj {
+					pa.adjacent++
+				}
+				if hasSameDay {
+					pa.sameDay++
+				}
+			}
 		}
 		if hasAdj {
 			d.StudentsWithAdjacent++
@@ -129,5 +172,53 @@ func (st *State) Diagnostics() Diagnostics {
 	for di := range p.days {
 		d.InteriorHoles += st.dayHoleCount(di)
 	}
+
+	inEquity := make(map[string]bool, len(p.programs))
+	for _, prog := range p.programs {
+		inEquity[prog] = true
+	}
+	for prog, pa := range progs {
+		d.Programs = append(d.Programs, ProgramDiagnostics{
+			Program:              prog,
+			Students:             len(pa.penalties),
+			MeanPenalty:          mean(pa.penalties),
+			P90Penalty:           percentile(pa.penalties, 0.9),
+			StudentsWithAdjacent: pa.adjacent,
+			StudentsWithSameDay:  pa.sameDay,
+			InEquity:             inEquity[prog],
+		})
+	}
+	sort.Slice(d.Programs, func(i, j int) bool {
+		if d.Programs[i].Students != d.Programs[j].Students {
+			return d.Programs[i].Students > d.Programs[j].Students
+		}
+		return d.Programs[i].Program < d.Programs[j].Program
+	})
 	return d
 }
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// percentile returns the q-quantile (0..1) of xs by the nearest-rank method.
+func percentile(xs []float64, q float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(xs))
+	copy(sorted, xs)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
@@ -1,8 +1,10 @@
 package examplan
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
+	"strings"
 	"testing"
 	"time"
 
@@ -360,8 +362,15 @@ func fullCost(st *State) float64 {
 	h, _ := holeCost(st)
 	f, _ := tbauFillCost(st)
 	td, _ := timeOfDayCost(st)
+	e, _ := equityCost(st)
 	u, _ := unplacedCost(st)
-	return s + a + l + h + f + td + u
+	c, _ := curriculumCost(st)
+	var r float64
+	for i := range st.P.rules {
+		c, _ := ruleC{i: i, r: &st.P.rules[i]}.Cost(st)
+		r += c
+	}
+	return s + a + l + h + f + td + e + u + c + r
 }
 
 func TestIncrementalMatchesFull(t *testing.T) {
@@ -385,6 +394,36 @@ func TestIncrementalMatchesFull(t *testing.T) {
 	}
 }
 
+func TestCurriculumKeepsRequiredModulesApart(t *testing.T) {
+	// two small exams of the same examer (attract: same slot) that are required in the
+	// same program semester: the curriculum pair outweighs the attract pull.
+	units := []Unit{
+		{ID: 1, Ancodes: []int{1}, Seats: 3, Fixed: true, FixedSlot: 0},
+		{ID: 2, Ancodes: []int{2}, Seats: 3},
+	}
+	w := DefaultWeights()
+	w.SlotLoad = 0
+	p := NewProblem(testSlots(), units, nil, []AttractPair{{A: 0, B: 1, Weight: 1}}, w)
+	p.SetCurriculumPairs([]CurriculumPair{{A: 0, B: 1, Weight: 1}})
+
+	st, _ := Solve(p, fastOpts(), false)
+	if st.SlotOf[1] == 0 {
+		t.Errorf("curriculum pair placed in the same slot: %v", st.SlotOf)
+	}
+
+	undo := st.moveUnit(1, 0)
+	if c, vs := curriculumCost(st); c != w.Curriculum || len(vs) != 1 {
+		t.Errorf("curriculum cost = %v (%d violations), want %v", c, len(vs), w.Curriculum)
+	}
+	if diff := st.Cost() - fullCost(st); diff > 1e-6 || diff < -1e-6 {
+		t.Errorf("incremental cost %.4f != full recompute %.4f", st.Cost(), fullCost(st))
+	}
+	undo()
+	if c, _ := curriculumCost(st); c != 0 || st.curricTotal != 0 {
+		t.Errorf("after undo: curriculum cost %v, total %v", c, st.curricTotal)
+	}
+}
+
 func TestAcceptedWeightZeroStillHardSameSlot(t *testing.T) {
 	units := []Unit{{ID: 1, Ancodes: []int{1}, Seats: 10}, {ID: 2, Ancodes: []int{2}, Seats: 10}}
 	// per-student acceptance is modelled as weight 0: no proximity penalty, but the
@@ -751,3 +790,125 @@ func TestCrossCampusSameDayPenalty(t *testing.T) {
 			DefaultWeights().CrossCampus, cross, same)
 	}
 }
+
+// equityStudents builds n students of program prog, each with a conflict between units a
+// and b.
+func equityStudents(prog string, n, a, b int) []Student {
+	out := make([]Student, n)
+	for i := range out {
+		out[i] = Student{ID: fmt.Sprintf("%s-%d", prog, i), Program: prog, Pairs: []Pair{{A: a, B: b, Weight: 1}}}
+	}
+	return out
+}
+
+func TestEquityPenalizesWorstProgram(t *testing.T) {
+	units := []Unit{{ID: 1, Seats: 10}, {ID: 2, Seats: 10}, {ID: 3, Seats: 10}, {ID: 4, Seats: 10}}
+	students := append(equityStudents("IF", 20, 0, 1), equityStudents("GS", 5, 2, 3)...)
+	p := NewProblem(testSlots(), units, students, nil, DefaultWeights())
+	st := newState(p)
+	st.setPhysical(0, 0) // IF: Mon 08:30 / Tue 08:30 — well spread
+	st.setPhysical(1, 2)
+	st.setPhysical(2, 0) // GS: Mon 08:30 / Mon 11:30 — directly consecutive
+	st.setPhysical(3, 1)
+	st.initCost()
+	if st.equityTotal <= 0 {
+		t.Fatalf("the consecutive GS exams should make GS the worst-off program, equity %.2f", st.equityTotal)
+	}
+	_, vs := equityCost(st)
+	if len(vs) != 1 || !strings.Contains(vs[0].Message, "GS") {
+		t.Errorf("expected one violation naming GS, got %+v", vs)
+	}
+}
+
+func TestEquityIgnoresSmallPrograms(t *testing.T) {
+	units := []Unit{{ID: 1, Seats: 10}, {ID: 2, Seats: 10}, {ID: 3, Seats: 10}, {ID: 4, Seats: 10}}
+	students := append(equityStudents("IF", 20, 0, 1), equityStudents("GS", equityMinStudents-1, 2, 3)...)
+	p := NewProblem(testSlots(), units, students, nil, DefaultWeights())
+	st := newState(p)
+	st.setPhysical(0, 0)
+	st.setPhysical(1, 2)
+	st.setPhysical(2, 0)
+	st.setPhysical(3, 1)
+	st.initCost()
+	if st.equityTotal != 0 {
+		t.Errorf("a program below equityMinStudents must not steer the solver, equity %.2f", st.equityTotal)
+	}
+	d := st.Diagnostics()
+	if len(d.Programs) != 2 {
+		t.Fatalf("expected both programs in the breakdown, got %+v", d.Programs)
+	}
+	if d.Programs[0].Program != "IF" || !d.Programs[0].InEquity || d.Programs[1].InEquity {
+		t.Errorf("breakdown should list IF (counted) before GS (not counted), got %+v", d.Programs)
+	}
+	if d.Programs[1].StudentsWithAdjacent != equityMinStudents-1 || d.Programs[1].MeanPenalty <= d.Programs[0].MeanPenalty {
+		t.Errorf("GS should show the consecutive exams and the higher mean, got %+v", d.Programs[1])
+	}
+}
+
+// TestEquityIncrementalMatchesFull exercises the incremental per-program bookkeeping
+// (moveUnit / undo) against a from-scratch recompute over many random moves.
+func TestEquityIncrementalMatchesFull(t *testing.T) {
+	units := []Unit{{ID: 1, Seats: 10}, {ID: 2, Seats: 10}, {ID: 3, Seats: 10}, {ID: 4, Seats: 10}}
+	students := append(equityStudents("IF", 8, 0, 1), equityStudents("GS", 6, 1, 2)...)
+	students = append(students, equityStudents("DC", 5, 2, 3)...)
+	p := NewProblem(testSlots(), units, students, nil, DefaultWeights())
+	st := construct(p)
+	rng := rand.New(rand.NewSource(7))
+	for i := 0; i < 3000; i++ {
+		undo := st.Propose(rng)
+		if undo == nil {
+			continue
+		}
+		want, _ := equityCost(st)
+		if diff := st.equityTotal - want; diff > 1e-6 || diff < -1e-6 {
+			t.Fatalf("equityTotal %.4f != recompute %.4f after move %d; slots=%v", st.equityTotal, want, i, st.SlotOf)
+		}
+		if i%3 == 0 {
+			undo()
+			want2, _ := equityCost(st)
+			if diff := st.equityTotal - want2; diff > 1e-6 || diff < -1e-6 {
+				t.Fatalf("equityTotal %.4f != recompute %.4f after undo %d", st.equityTotal, want2, i)
+			}
+		}
+	}
+}
+
+func TestUserRuleSteersPlacementAndIsDescribed(t *testing.T) {
+	units := []Unit{{ID: 1, Ancodes: []int{1}, Seats: 10}, {ID: 2, Ancodes: []int{2}, Seats: 10}, {ID: 3, Ancodes: []int{3}, Seats: 10}}
+	students := []Student{{ID: "a", Pairs: []Pair{{A: 0, B: 1, Weight: 1}, {A: 1, B: 2, Weight: 1}}}}
+	p := NewProblem(testSlots(), units, students, nil, DefaultWeights())
+	// unit 0 is only welcome in the last slot
+	cost := make([][]float64, len(units))
+	cost[0] = make([]float64, len(p.Slots))
+	for s := 0; s < len(p.Slots)-1; s++ {
+		cost[0][s] = 1e5
+	}
+	p.SetRules([]Rule{{Name: "late", Description: "ancode == 1 && time < \"14:00\"", Weight: 1e5, Cost: cost}})
+
+	st, _ := Solve(p, fastOpts(), false)
+	if st.SlotOf[0] != len(p.Slots)-1 {
+		t.Errorf("rule must push unit 0 into the last slot, got %d", st.SlotOf[0])
+	}
+	if got, want := st.Cost(), fullCost(st); got-want > 1e-6 || want-got > 1e-6 {
+		t.Errorf("incremental cost %.4f != full %.4f", got, want)
+	}
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < 500; i++ {
+		if undo := st.Propose(rng); undo != nil && i%2 == 0 {
+			undo()
+		}
+		if got, want := st.Cost(), fullCost(st); got-want > 1e-6 || want-got > 1e-6 {
+			t.Fatalf("incremental cost %.4f != full %.4f after move %d", got, want, i)
+		}
+	}
+
+	var found bool
+	for _, info := range p.Registry().Describe() {
+		if info.Name == "rule:late" {
+			found = info.Kind == optimize.KindSoft && info.Weight == 1e5
+		}
+	}
+	if !found {
+		t.Error("Describe must list the user rule as a soft constraint")
+	}
+}
@@ -0,0 +1,170 @@
+package examplan
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Blocker is one reason a unit cannot move into a given slot. Constraint is the Name of
+// the hard constraint (see Registry) that rules the slot out; Ancodes are the conflicting
+// partner exams and Students the students whose registrations create that conflict.
+type Blocker struct {
+	Constraint string
+	Message    string
+	Ancodes    []int
+	Students   []string
+}
+
+// SlotOption is the outcome of moving a unit into one slot of the exam period: either
+// the hard constraints blocking it, or (when feasible) the change of the total soft cost
+// such a move would cause (positive = worse than the current placement).
+type SlotOption struct {
+	Slot      int
+	Current   bool
+	Feasible  bool
+	Blockers  []Blocker
+	CostDelta float64
+}
+
+// Explanation lists, for one unit, every slot of the exam period with its blockers or cost
+// difference: feasible alternatives cheapest first, then the blocked slots in
+// chronological order. Allowed is the size of the unit's slot domain (0 = all slots).
+type Explanation struct {
+	Unit    int
+	Slot    int
+	Allowed int
+	Options []SlotOption
+}
+
+// FromPlan builds the State of an existing assignment without optimizing it: fixed units
+// at their FixedSlot, movable units at their StartSlot (or unplaced). No feasibility
+// filtering is applied — the saved plan is to be explained, not repaired — so the state
+// may carry hard violations, which Registry().HardViolations then reports.
+func FromPlan(p *Problem) *State {
+	st := newState(p)
+	for _, u := range p.movable {
+		if s := p.Units[u].StartSlot; s >= 0 && s < len(p.Slots) {
+			st.setPhysical(u, s)
+		}
+	}
+	st.initCost()
+	return st
+}
+
+// Explain reports why unit u sits where it is: for every other slot either the hard
+// constraints ruling it out (fixed placement, slot domain, a student's overlapping exam,
+// seat/EXaHM capacity) or the soft-cost difference of moving there. The state is left
+// unchanged.
+func (st *State) Explain(u int) Explanation {
+	p := st.P
+	cur := st.SlotOf[u]
+	ex := Explanation{Unit: u, Slot: cur, Allowed: len(p.Units[u].Allowed)}
+
+	// students sharing a pair with u, per conflict partner (deterministic order)
+	studentsByPartner := make(map[int][]string)
+	for _, si := range p.unitStudents[u] {
+		for _, pr := range p.Students[si].Pairs {
+			switch u {
+			case pr.A:
+				studentsByPartner[pr.B] = append(studentsByPartner[pr.B], p.Students[si].ID)
+			case pr.B:
+				studentsByPartner[pr.A] = append(studentsByPartner[pr.A], p.Students[si].ID)
+			}
+		}
+	}
+	for v := range studentsByPartner {
+		sort.Strings(studentsByPartner[v])
+	}
+
+	base := st.Cost()
+	for s := range p.Slots {
+		opt := SlotOption{Slot: s, Current: s == cur}
+		if s == cur {
+			opt.Feasible = true
+			ex.Options = append(ex.Options, opt)
+			continue
+		}
+		opt.Blockers = st.blockers(u, s, studentsByPartner)
+		if len(opt.Blockers) == 0 {
+			opt.Feasible = true
+			undo := st.moveUnit(u, s)
+			opt.CostDelta = st.Cost() - base
+			undo()
+		}
+		ex.Options = append(ex.Options, opt)
+	}
+
+	sort.SliceStable(ex.Options, func(i, j int) bool {
+		a, b := ex.Options[i], ex.Options[j]
+		if a.Feasible != b.Feasible {
+			return a.Feasible
+		}
+		if a.Feasible && a.CostDelta != b.CostDelta {
+			return a.CostDelta < b.CostDelta
+		}
+		return p.Slots[a.Slot].Start.Before(p.Slots[b.Slot].Start)
+	})
+	return ex
+}
+
+// blockers collects every hard reason unit u may not move into slot s — the same checks
+// as feasible, but exhaustive and with the involved exams and students attached.
+func (st *State) blockers(u, s int, studentsByPartner map[int][]string) []Blocker {
+	p := st.P
+	unit := &p.Units[u]
+	var bs []Blocker
+	if unit.Fixed {
+		bs = append(bs, Blocker{Constraint: "fixed", Message: "Prüfung ist fest eingeplant (gesperrt / extern / fixiert)"})
+	}
+	if !p.allows(u, s) {
+		bs = append(bs, Blocker{Constraint: "allowed-slots", Message: "Slot nicht zulässig (Constraints, gesperrte Tage, gemeinsame Studiengänge, Zeitfenster, EXaHM-Buchung)"})
+	}
+	for _, v := range p.hardConfSorted[u] {
+		if sv := st.SlotOf[v]; sv >= 0 && p.overlaps(u, s, v, sv) {
+			bs = append(bs, Blocker{Constraint: "student-clash", Ancodes: p.Units[v].Ancodes, Students: studentsByPartner[v],
+				Message: fmt.Sprintf("%d Studierende haben zeitlich überlappend Prüfung %d", len(studentsByPartner[v]), p.Units[v].ID)})
+		}
+	}
+	seats := unit.Seats
+	if cap := p.Slots[s].Seats; cap > 0 && st.slotSeats[s] > 0 && st.slotSeats[s]+seats > cap {
+		bs = append(bs, Blocker{Constraint: "capacity", Ancodes: st.unitsInSlot(s),
+			Message: fmt.Sprintf("Slot-Kapazität: %d + %d Plätze > %d", st.slotSeats[s], seats, cap)})
+	}
+	if unit.Exahm {
+		if used := st.slotExahm[s] + st.slotExahmOverrun[s]; used+seats > p.Slots[s].ExahmSeats {
+			bs = append(bs, Blocker{Constraint: "capacity",
+				Message: fmt.Sprintf("EXaHM-Kapazität: %d belegt + %d > %d gebuchte Plätze", used, seats, p.Slots[s].ExahmSeats)})
+		}
+		for _, t := range p.overrunTargets(u, s) {
+			if used := st.slotExahm[t] + st.slotExahmOverrun[t]; used+seats > p.Slots[t].ExahmSeats {
+				bs = append(bs, Blocker{Constraint: "capacity",
+					Message: fmt.Sprintf("EXaHM-Kapazität im Folgeslot (verlängerter Nachlauf): %d belegt + %d > %d gebuchte Plätze", used, seats, p.Slots[t].ExahmSeats)})
+			}
+		}
+	}
+	return bs
+}
+
+// unitsInSlot returns the ancodes of the seat-consuming units currently placed in slot s.
+func (st *State) unitsInSlot(s int) []int {
+	var out []int
+	for u := range st.P.Units {
+		if st.SlotOf[u] == s && st.P.Units[u].Seats > 0 {
+			out = append(out, st.P.Units[u].Ancodes...)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+// UnitOf returns the index of the unit holding ancode, or -1.
+func (p *Problem) UnitOf(ancode int) int {
+	for u := range p.Units {
+		for _, a := range p.Units[u].Ancodes {
+			if a == ancode {
+				return u
+			}
+		}
+	}
+	return -1
+}
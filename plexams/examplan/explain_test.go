@@ -0,0 +1,63 @@
+package examplan
+
+import "testing"
+
+func TestExplainListsBlockersAndCostDeltas(t *testing.T) {
+	units := []Unit{
+		{ID: 1, Ancodes: []int{1}, Seats: 10, StartSlot: 0},
+		{ID: 2, Ancodes: []int{2}, Seats: 10, StartSlot: 2},
+		{ID: 3, Ancodes: []int{3}, Seats: 10, Allowed: []int{0, 2}, StartSlot: -1},
+	}
+	students := []Student{
+		{ID: "s1", Pairs: []Pair{{A: 0, B: 1, Weight: 1}}},
+		{ID: "s2", Pairs: []Pair{{A: 0, B: 1, Weight: 1}}},
+	}
+	p := NewProblem(testSlots(), units, students, nil, DefaultWeights())
+	st := FromPlan(p)
+	if st.SlotOf[0] != 0 || st.SlotOf[1] != 2 || st.SlotOf[2] != -1 {
+		t.Fatalf("FromPlan must keep the saved assignment, got %v", st.SlotOf)
+	}
+	before := st.Cost()
+
+	ex := st.Explain(0)
+	if ex.Slot != 0 || len(ex.Options) != len(p.Slots) {
+		t.Fatalf("expected one option per slot for the placed unit, got %+v", ex)
+	}
+	if st.Cost() != before || st.SlotOf[0] != 0 {
+		t.Errorf("Explain must leave the state unchanged")
+	}
+	var clash *SlotOption
+	for i := range ex.Options {
+		if ex.Options[i].Slot == 2 {
+			clash = &ex.Options[i]
+		}
+	}
+	if clash == nil || clash.Feasible || len(clash.Blockers) != 1 || clash.Blockers[0].Constraint != "student-clash" {
+		t.Fatalf("slot of the conflict partner must be blocked by a student clash, got %+v", clash)
+	}
+	if got := clash.Blockers[0].Students; len(got) != 2 || got[0] != "s1" || got[1] != "s2" {
+		t.Errorf("both students should be named as the reason, got %v", got)
+	}
+	// feasible alternatives first, cheapest first; the current slot has delta 0
+	for i := 1; i < len(ex.Options); i++ {
+		a, b := ex.Options[i-1], ex.Options[i]
+		if !a.Feasible && b.Feasible {
+			t.Errorf("feasible options must precede blocked ones: %+v", ex.Options)
+		}
+		if a.Feasible && b.Feasible && a.CostDelta > b.CostDelta {
+			t.Errorf("feasible options must be sorted by cost delta: %+v", ex.Options)
+		}
+	}
+
+	ex3 := st.Explain(2)
+	for _, o := range ex3.Options {
+		if o.Slot == 1 || o.Slot == 3 {
+			if o.Feasible || o.Blockers[0].Constraint != "allowed-slots" {
+				t.Errorf("slot %d is outside the domain and must be blocked, got %+v", o.Slot, o)
+			}
+		}
+	}
+	if p.UnitOf(3) != 2 || p.UnitOf(99) != -1 {
+		t.Errorf("UnitOf: got %d / %d", p.UnitOf(3), p.UnitOf(99))
+	}
+}
@@ -31,12 +31,16 @@ type State struct {
 	slotExahmOverrun []int
 
 	pS            []float64
+	progSum       []float64 // per equity program: sum of its students' P_s
 	spreadTotal   float64
 	attractTotal  float64
 	slotLoadTotal float64
 	tbauFillTotal float64
 	holeTotal     float64
 	timeTotal     float64
+	equityTotal   float64
+	ruleTotal     float64 // user-defined soft rules (Problem.rules)
+	curricTotal   float64 // curriculum pairs in the same slot (Problem.curriculum)
 	nUnplaced     int
 }
 
@@ -50,6 +54,7 @@ func newState(p *Problem) *State {
 		slotSeb:          make([]int, len(p.Slots)),
 		slotExahmOverrun: make([]int, len(p.Slots)),
 		pS:               make([]float64, len(p.Students)),
+		progSum:          make([]float64, len(p.programs)),
 	}
 	for i := range st.SlotOf {
 		st.SlotOf[i] = -1
@@ -122,11 +127,18 @@ func (st *State) seatUnitsInSlot(s int) int {
 func (st *State) initCost() {
 	p := st.P
 	st.spreadTotal = 0
+	for g := range st.progSum {
+		st.progSum[g] = 0
+	}
 	for si := range p.Students {
 		ps := st.studentPenalty(si)
 		st.pS[si] = ps
 		st.spreadTotal += ps + p.W.WorstCase*ps*ps
+		if g := p.studentProg[si]; g >= 0 {
+			st.progSum[g] += ps
+		}
 	}
+	st.equityTotal = p.equityPenalty(st.progSum)
 	st.attractTotal = 0
 	for _, ap := range p.Attract {
 		a, b := st.SlotOf[ap.A], st.SlotOf[ap.B]
@@ -134,6 +146,10 @@ func (st *State) initCost() {
 			st.attractTotal += ap.Weight * p.farness(a, b)
 		}
 	}
+	st.curricTotal = 0
+	for _, cp := range p.curriculum {
+		st.curricTotal += p.curriculumPenalty(cp.Weight, st.SlotOf[cp.A], st.SlotOf[cp.B])
+	}
 	st.slotLoadTotal = 0
 	st.tbauFillTotal = 0
 	for s := range p.Slots {
@@ -145,9 +161,11 @@ func (st *State) initCost() {
 		st.holeTotal += p.W.Hole * float64(st.dayHoleCount(d))
 	}
 	st.timeTotal = 0
+	st.ruleTotal = 0
 	for u := range p.Units {
 		if s := st.SlotOf[u]; s >= 0 {
 			st.timeTotal += p.timePenalty(u, s)
+			st.ruleTotal += p.rulePenalty(u, s)
 		}
 	}
 	st.nUnplaced = 0
@@ -235,6 +253,19 @@ func (st *State) attractOfUnit(u int) float64 {
 	return c
 }
 
+// curriculumOfUnit is the curriculum cost contributed by unit u's curriculum pairs.
+func (st *State) curriculumOfUnit(u int) float64 {
+	p := st.P
+	if st.SlotOf[u] < 0 || p.unitCurriculum == nil {
+		return 0
+	}
+	var c float64
+	for _, cr := range p.unitCurriculum[u] {
+		c += p.curriculumPenalty(cr.weight, st.SlotOf[u], st.SlotOf[cr.other])
+	}
+	return c
+}
+
 // moveUnit moves unit u to newSlot (!= its current slot), maintaining all cost totals
 // incrementally, and returns an undo closure. The caller guarantees hard-feasibility.
 func (st *State) moveUnit(u, newSlot int) func() {
@@ -248,14 +279,19 @@ func (st *State) moveUnit(u, newSlot int) func() {
 	}
 	savedSpread := st.spreadTotal
 	savedAttract := st.attractTotal
+	savedCurric := st.curricTotal
 	savedLoad := st.slotLoadTotal
 	savedFill := st.tbauFillTotal
 	savedHole := st.holeTotal
 	savedTime := st.timeTotal
+	savedRule := st.ruleTotal
+	savedEquity := st.equityTotal
+	savedProgSum := cpF(st.progSum)
 	savedUnplaced := st.nUnplaced
 
 	// start-time avoidance delta: depends only on the moved unit's slot (and its seats)
 	st.timeTotal += p.timePenalty(u, newSlot) - p.timePenalty(u, old)
+	st.ruleTotal += p.rulePenalty(u, newSlot) - p.rulePenalty(u, old)
 
 	// slot-load + T-building-fill deltas over the (at most two) touched slots
 	loadBefore, fillBefore := 0.0, 0.0
@@ -268,6 +304,7 @@ func (st *State) moveUnit(u, newSlot int) func() {
 		fillBefore += p.tbauPenalty(newSlot, st.slotExahm[newSlot], st.slotSeb[newSlot])
 	}
 	oldAttractU := st.attractOfUnit(u)
+	oldCurricU := st.curriculumOfUnit(u)
 	// interior-hole delta over the (at most two) days whose occupancy this move changes
 	dOld, dNew := -1, -1
 	if old >= 0 {
@@ -293,6 +330,7 @@ func (st *State) moveUnit(u, newSlot int) func() {
 	st.tbauFillTotal += fillAfter - fillBefore
 	st.holeTotal += st.holeOfDays(dOld, dNew) - holeBefore
 	st.attractTotal += st.attractOfUnit(u) - oldAttractU
+	st.curricTotal += st.curriculumOfUnit(u) - oldCurricU
 	if old < 0 && newSlot >= 0 {
 		st.nUnplaced--
 	} else if old >= 0 && newSlot < 0 {
@@ -301,6 +339,7 @@ func (st *State) moveUnit(u, newSlot int) func() {
 	for _, s := range affected {
 		st.recomputeStudentSpread(s)
 	}
+	st.equityTotal = p.equityPenalty(st.progSum)
 
 	return func() {
 		st.setPhysical(u, old)
@@ -309,20 +348,28 @@ func (st *State) moveUnit(u, newSlot int) func() {
 		}
 		st.spreadTotal = savedSpread
 		st.attractTotal = savedAttract
+		st.curricTotal = savedCurric
 		st.slotLoadTotal = savedLoad
 		st.tbauFillTotal = savedFill
 		st.holeTotal = savedHole
 		st.timeTotal = savedTime
+		st.ruleTotal = savedRule
+		st.equityTotal = savedEquity
+		copy(st.progSum, savedProgSum)
 		st.nUnplaced = savedUnplaced
 	}
 }
 
-// recomputeStudentSpread recomputes P_s for one student and updates spreadTotal.
+// recomputeStudentSpread recomputes P_s for one student and updates spreadTotal and the
+// student's per-program sum (the equity total itself is refreshed once per move).
 func (st *State) recomputeStudentSpread(si int) {
 	p := st.P
 	newPS := st.studentPenalty(si)
 	old := st.pS[si]
 	st.spreadTotal += (newPS + p.W.WorstCase*newPS*newPS) - (old + p.W.WorstCase*old*old)
+	if g := p.studentProg[si]; g >= 0 {
+		st.progSum[g] += newPS - old
+	}
 	st.pS[si] = newPS
 }
 
@@ -469,13 +516,13 @@ func (st *State) Propose(rng *rand.Rand) func() {
 
 // Cost is the maintained total soft objective (O(1)).
 func (st *State) Cost() float64 {
-	return st.spreadTotal + st.attractTotal + st.slotLoadTotal + st.tbauFillTotal + st.holeTotal + st.timeTotal + st.P.W.Unplaced*float64(st.nUnplaced)
+	return st.spreadTotal + st.attractTotal + st.slotLoadTotal + st.tbauFillTotal + st.holeTotal + st.timeTotal + st.equityTotal + st.ruleTotal + st.curricTotal + st.P.W.Unplaced*float64(st.nUnplaced)
 }
 
 func (st *State) Snapshot() any {
 	return snapshot{
 		slotOf: cp(st.SlotOf), slotSeats: cp(st.slotSeats), slotOwn: cp(st.slotOwn), slotExahm: cp(st.slotExahm), slotSeb: cp(st.slotSeb), slotExahmOverrun: cp(st.slotExahmOverrun),
-		pS: cpF(st.pS), spread: st.spreadTotal, attract: st.attractTotal, load: st.slotLoadTotal, fill: st.tbauFillTotal, hole: st.holeTotal, time: st.timeTotal, nUnplaced: st.nUnplaced,
+		pS: cpF(st.pS), progSum: cpF(st.progSum), spread: st.spreadTotal, attract: st.attractTotal, load: st.slotLoadTotal, fill: st.tbauFillTotal, hole: st.holeTotal, time: st.timeTotal, equity: st.equityTotal, rule: st.ruleTotal, curric: st.curricTotal, nUnplaced: st.nUnplaced,
 	}
 }
 
@@ -488,19 +535,23 @@ func (st *State) Restore(a any) {
 	copy(st.slotSeb, sn.slotSeb)
 	copy(st.slotExahmOverrun, sn.slotExahmOverrun)
 	copy(st.pS, sn.pS)
+	copy(st.progSum, sn.progSum)
 	st.spreadTotal = sn.spread
 	st.attractTotal = sn.attract
 	st.slotLoadTotal = sn.load
 	st.tbauFillTotal = sn.fill
 	st.holeTotal = sn.hole
 	st.timeTotal = sn.time
+	st.equityTotal = sn.equity
+	st.ruleTotal = sn.rule
+	st.curricTotal = sn.curric
 	st.nUnplaced = sn.nUnplaced
 }
 
 type snapshot struct {
 	slotOf, slotSeats, slotOwn, slotExahm, slotSeb, slotExahmOverrun []int
-	pS                                                               []float64
-	spread, attract, load, fill, hole, time                          float64
+	pS, progSum                                                      []float64
+	spread, attract, load, fill, hole, time, equity, rule, curric    float64
 	nUnplaced                                                        int
 }
 
@@ -564,6 +615,23 @@ func attractCost(st *State) (float64, []optimize.Violation) {
 	return total, nil
 }
 
+func curriculumCost(st *State) (float64, []optimize.Violation) {
+	p := st.P
+	var total float64
+	var vs []optimize.Violation
+	for _, cp := range p.curriculum {
+		pen := p.curriculumPenalty(cp.Weight, st.SlotOf[cp.A], st.SlotOf[cp.B])
+		if pen == 0 {
+			continue
+		}
+		total += pen
+		refs := append(append([]int{}, p.Units[cp.A].Ancodes...), p.Units[cp.B].Ancodes...)
+		vs = append(vs, optimize.Violation{Constraint: "curriculum", Penalty: pen, Refs: refs,
+			Message: "Pflichtmodule desselben Fachsemesters im selben Slot"})
+	}
+	return total, vs
+}
+
 func slotLoadCost(st *State) (float64, []optimize.Violation) {
 	p := st.P
 	var total float64
@@ -677,6 +745,30 @@ func timeOfDayCost(st *State) (float64, []optimize.Violation) {
 	return total, vs
 }
 
+// equityCost recomputes the per-program equity penalty from scratch and reports the
+// worst-off program when it lies above the population mean.
+func equityCost(st *State) (float64, []optimize.Violation) {
+	p := st.P
+	progSum := make([]float64, len(p.programs))
+	for si := range p.Students {
+		if g := p.studentProg[si]; g >= 0 {
+			progSum[g] += st.studentPenalty(si)
+		}
+	}
+	total := p.equityPenalty(progSum)
+	if total == 0 {
+		return 0, nil
+	}
+	worst, worstMean := 0, -1.0
+	for g, sum := range progSum {
+		if mean := sum / float64(p.progSize[g]); mean > worstMean {
+			worst, worstMean = g, mean
+		}
+	}
+	return total, []optimize.Violation{{Constraint: "program-equity", Penalty: total,
+		Message: fmt.Sprintf("Studiengang %s schlechter verteilt als der Durchschnitt (Ø %.0f)", p.programs[worst], worstMean)}}
+}
+
 func unplacedCost(st *State) (float64, []optimize.Violation) {
 	n := st.unplacedCount()
 	var vs []optimize.Violation
@@ -7,7 +7,8 @@
 // Hard constraints: fixed placements stay put, a student never has two exams in the
 // same slot (unless the pair is declared shareable), per-slot EXaHM and total seat
 // capacities. Soft objective (weighted): the spread (sum over students + a convex
-// worst-case term so no single student gets a bad schedule), "attract" pairs that
+// worst-case term so no single student gets a bad schedule), a per-program equity term
+// so no study program is systematically worse off, "attract" pairs that
 // should sit together (parallel sections / small exams of the same examer), and a
 // slot-load term keeping the seats/rooms per slot moderate.
 package examplan
@@ -79,9 +80,12 @@ type Pair struct {
 }
 
 // Student is one student's conflicting unit pairs, used for the spread objective.
+// Program (empty = unknown) groups students for the per-program equity term and the
+// per-program diagnostics.
 type Student struct {
-	ID    string
-	Pairs []Pair
+	ID      string
+	Program string
+	Pairs   []Pair
 }
 
 // AttractPair are two units that should sit close (ideally same slot): parallel
@@ -126,8 +130,21 @@ type Weights struct {
 	//        Meaningful only with finer/free start times; the value is a calibration knob.
 	// Across-day pairs always fall off with the real time gap (DayFactor·24/h) in both modes.
 	ClosenessFalloffMin float64
+	// Equity penalizes the worst-off study program: Equity · (highest per-program mean P_s −
+	// mean P_s over all counted students). The summed spread lets the big programs dominate
+	// the objective; this term keeps a small program from being systematically sacrificed.
+	// Only programs with at least equityMinStudents students take part. 0 = off.
+	Equity float64
+	// Curriculum penalizes, per curriculum pair (see SetCurriculumPairs), two required
+	// modules of the same program semester starting in the same slot: students are expected
+	// to take both, even if their registrations are not known yet. 0 = off.
+	Curriculum float64
 }
 
+// equityMinStudents is the number of students with conflicts a program needs to take part
+// in the equity term: below it the program mean is too noisy to steer the solver with.
+const equityMinStudents = 5
+
 // DefaultWeights returns the calibrated weights (tuned against real data, Test26SS,
 // 2026-07-02: Adjacent/SameDay high enough to push directly-consecutive to 0 and
 // same-day exams down markedly, with a mild worst-case term protecting the least
@@ -153,6 +170,13 @@ func DefaultWeights() Weights {
 		// switch the same-day spread cost to a continuous time-gap falloff for finer start
 		// times (a calibration knob, tuned per semester against real data).
 		ClosenessFalloffMin: 0,
+		// Equity: a program whose students average 100 more P_s than the population costs
+		// about two directly-consecutive pairs — a tie-breaker pulling the worst-off program
+		// towards the mean, never a reason to create new same-day pairs elsewhere.
+		Equity: 50,
+		// Curriculum: below Adjacent (2500), so an expected co-registration never outweighs
+		// a real student's directly-consecutive pair, but far above the tie-breakers.
+		Curriculum: 1000,
 	}
 }
 
@@ -287,6 +311,9 @@ type Problem struct {
 	timeMode        TimeWindowMode
 	timeEarliestMin int
 	timeLatestMin   int
+	// rules are the user-defined soft constraints (GenerationConfig), evaluated by the
+	// caller into per-(unit, slot) penalties. Set via SetRules; nil = none.
+	rules []Rule
 
 	// derived
 	movable        []int
@@ -308,7 +335,11 @@ type Problem struct {
 	overrun      []map[int][]int
 	unitStudents [][]int        // unit -> student indices that have a pair with it
 	unitAttract  [][]attractRef // unit -> its attract partners
-	targetLoad   float64        // ideal seats per slot = total seats / number of slots
+	// curriculum pairs (expected co-registrations from the study plans), set via
+	// SetCurriculumPairs; unitCurriculum is the per-unit view like unitAttract.
+	curriculum     []CurriculumPair
+	unitCurriculum [][]attractRef
+	targetLoad     float64 // ideal seats per slot = total seats / number of slots
 	// day grouping for the interior-hole and same-day penalties, derived purely from each
 	// slot's absolute Start time (no slot-ordinal input): days[d] is the slot indices of
 	// one calendar day, sorted chronologically; dayOfSlot maps a slot index back to its day
@@ -318,6 +349,14 @@ type Problem struct {
 	days       [][]int
 	dayOfSlot  []int
 	slotDayPos []int
+	// equity grouping: studentProg[si] is the index into programs of student si's program,
+	// or -1 when the student takes no part in the equity term (unknown program, or one with
+	// fewer than equityMinStudents students); progSize[g] counts the members of programs[g]
+	// and equityStudents all counted students.
+	programs       []string
+	progSize       []int
+	studentProg    []int
+	equityStudents int
 }
 
 type attractRef struct {
@@ -405,6 +444,32 @@ func NewProblem(slots []Slot, units []Unit, students []Student, attract []Attrac
 		p.days = append(p.days, slots)
 	}
 
+	// equity grouping: programs with enough students, in name order (deterministic).
+	byProg := make(map[string][]int)
+	for si := range p.Students {
+		if prog := p.Students[si].Program; prog != "" {
+			byProg[prog] = append(byProg[prog], si)
+		}
+	}
+	p.studentProg = make([]int, len(p.Students))
+	for si := range p.studentProg {
+		p.studentProg[si] = -1
+	}
+	for prog, members := range byProg {
+		if len(members) >= equityMinStudents {
+			p.programs = append(p.programs, prog)
+		}
+	}
+	sort.Strings(p.programs)
+	p.progSize = make([]int, len(p.programs))
+	for g, prog := range p.programs {
+		for _, si := range byProg[prog] {
+			p.studentProg[si] = g
+		}
+		p.progSize[g] = len(byProg[prog])
+		p.equityStudents += len(byProg[prog])
+	}
+
 	// deterministic (sorted) view of hardConf: iterating a Go map has random order, so
 	// summing floats over it (constructive addedCost) would make runs non-reproducible.
 	p.hardConfSorted = make([][]int, len(p.Units))
@@ -445,6 +510,72 @@ func (p *Problem) SetHardSeparations(sep map[[2]int]int) {
 	}
 }
 
+// CurriculumPair are two units whose modules are required in the same semester of a study
+// program (see plexams/curriculum): they should not start in the same slot. Weight scales
+// W.Curriculum per pair (e.g. by the number of programs expecting the pair).
+type CurriculumPair struct {
+	A, B   int
+	Weight float64
+}
+
+// SetCurriculumPairs installs the curriculum pairs. Pairs of a unit with itself or with
+// out-of-range units are ignored. Call before Solve.
+func (p *Problem) SetCurriculumPairs(pairs []CurriculumPair) {
+	p.curriculum = make([]CurriculumPair, 0, len(pairs))
+	p.unitCurriculum = make([][]attractRef, len(p.Units))
+	for _, cp := range pairs {
+		if cp.A == cp.B || cp.A < 0 || cp.B < 0 || cp.A >= len(p.Units) || cp.B >= len(p.Units) {
+			continue
+		}
+		p.curriculum = append(p.curriculum, cp)
+		p.unitCurriculum[cp.A] = append(p.unitCurriculum[cp.A], attractRef{cp.B, cp.Weight})
+		p.unitCurriculum[cp.B] = append(p.unitCurriculum[cp.B], attractRef{cp.A, cp.Weight})
+	}
+}
+
+// curriculumPenalty is the penalty of a curriculum pair placed in slots a and b.
+func (p *Problem) curriculumPenalty(weight float64, a, b int) float64 {
+	if a < 0 || a != b {
+		return 0
+	}
+	return p.W.Curriculum * weight
+}
+
+// Rule is a user-defined soft constraint (a planner's condition such as "exams of program
+// X not after 16:00", see plexams/softrule). The solver knows nothing about the condition:
+// the caller evaluates it for every unit and slot, and Cost[u][s] is the (already
+// weighted) penalty of placing unit u into slot s. A nil row means the rule never applies
+// to u; fixed units should get nil rows (they cannot move anyway).
+type Rule struct {
+	Name        string
+	Title       string
+	Description string
+	Weight      float64
+	Cost        [][]float64
+}
+
+// SetRules installs the user-defined soft constraints. Call before Solve.
+func (p *Problem) SetRules(rules []Rule) {
+	p.rules = rules
+}
+
+// cost is the penalty of rule r for unit u in slot s (0 when unplaced).
+func (r *Rule) cost(u, s int) float64 {
+	if s < 0 || u >= len(r.Cost) || s >= len(r.Cost[u]) {
+		return 0
+	}
+	return r.Cost[u][s]
+}
+
+// rulePenalty is the summed penalty of all user rules for unit u in slot s.
+func (p *Problem) rulePenalty(u, s int) float64 {
+	var c float64
+	for i := range p.rules {
+		c += p.rules[i].cost(u, s)
+	}
+	return c
+}
+
 // SetOverrunTargets installs, per (unit, slot), the later slots the unit's extended
 // Nachlauf keeps its EXaHM rooms occupied into (see the overrun field). targets keyed by
 // [2]int{unit, slot}. Only EXaHM units with an extended Nachlauf have entries; all others
@@ -503,6 +634,28 @@ func (p *Problem) overlaps(u, su, v, sv int) bool {
 	return -d < p.sepMinutes(v, u)
 }
 
+// equityPenalty is the per-program equity penalty for the given per-program sums of P_s:
+// Equity times how far the worst program's mean lies above the mean over all counted
+// students. 0 when the term is off or fewer than two programs take part (nothing to
+// compare against).
+func (p *Problem) equityPenalty(progSum []float64) float64 {
+	if p.W.Equity == 0 || len(p.programs) < 2 {
+		return 0
+	}
+	total, worst := 0.0, 0.0
+	for g, sum := range progSum {
+		total += sum
+		if mean := sum / float64(p.progSize[g]); mean > worst {
+			worst = mean
+		}
+	}
+	gap := worst - total/float64(p.equityStudents)
+	if gap <= 0 {
+		return 0
+	}
+	return p.W.Equity * gap
+}
+
 // loadPenalty is the even-distribution penalty for a slot holding `seats` seats: the
 // squared deviation from the ideal load (total seats / number of slots), so both empty
 // and very full slots are penalized and the solver spreads exams evenly over the slots.
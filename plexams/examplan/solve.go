@@ -195,6 +195,11 @@ func addedCost(st *State, u, s int) float64 {
 			c += ap.Weight * p.farness(s, so)
 		}
 	}
+	if p.unitCurriculum != nil {
+		for _, cr := range p.unitCurriculum[u] {
+			c += p.curriculumPenalty(cr.weight, s, st.SlotOf[cr.other])
+		}
+	}
 	seats := p.Units[u].Seats
 	over := st.slotSeats[s] + seats - p.W.LoadThreshold
 	if over > 0 {
@@ -212,6 +217,7 @@ func addedCost(st *State, u, s int) float64 {
 		}
 	}
 	c += p.timePenalty(u, s)
+	c += p.rulePenalty(u, s)
 	return c
 }
 
@@ -232,13 +238,17 @@ func marginalOverflow(before, add, cap int) int {
 // Registry returns the self-describing hard/soft constraints for reporting and the
 // read-only "which constraints are applied" view.
 func (p *Problem) Registry() optimize.Registry[*State] {
+	soft := []optimize.SoftConstraint[*State]{
+		spreadC{p.W}, equityC{p.W}, attractC{p.W}, curriculumC{p.W}, slotLoadC{p.W}, holeC{p.W}, tbauFillC{p.W}, overflowC{p.W}, timeOfDayC{p.W}, placementC{p.W},
+	}
+	for i := range p.rules {
+		soft = append(soft, ruleC{i: i, r: &p.rules[i]})
+	}
 	return optimize.Registry[*State]{
 		Hard: []optimize.HardConstraint[*State]{
 			fixedC{}, allowedC{}, sameStudentC{}, capacityC{},
 		},
-		Soft: []optimize.SoftConstraint[*State]{
-			spreadC{p.W}, attractC{p.W}, slotLoadC{p.W}, holeC{p.W}, tbauFillC{p.W}, overflowC{p.W}, timeOfDayC{p.W}, placementC{p.W},
-		},
+		Soft: soft,
 	}
 }
 
@@ -323,6 +333,14 @@ func (c spreadC) Info() optimize.Info {
 }
 func (spreadC) Cost(st *State) (float64, []optimize.Violation) { return spreadCost(st) }
 
+type equityC struct{ w Weights }
+
+func (c equityC) Info() optimize.Info {
+	return optimize.Info{Name: "program-equity", Title: "Fairness zwischen Studiengängen", Kind: optimize.KindSoft, Weight: c.w.Equity, Tier: 11,
+		Description: "Kein Studiengang soll systematisch schlechter verteilt sein: bestraft wird, wie weit die mittlere Spreizungs-Strafe des schlechtesten Studiengangs über dem Mittel aller Studierenden liegt (nur Studiengänge mit mindestens 5 Studierenden mit Konflikten)."}
+}
+func (equityC) Cost(st *State) (float64, []optimize.Violation) { return equityCost(st) }
+
 type attractC struct{ w Weights }
 
 func (c attractC) Info() optimize.Info {
@@ -331,6 +349,14 @@ func (c attractC) Info() optimize.Info {
 }
 func (attractC) Cost(st *State) (float64, []optimize.Violation) { return attractCost(st) }
 
+type curriculumC struct{ w Weights }
+
+func (c curriculumC) Info() optimize.Info {
+	return optimize.Info{Name: "curriculum", Title: "Pflichtmodule eines Semesters nicht gleichzeitig", Kind: optimize.KindSoft, Weight: c.w.Curriculum, Tier: 21,
+		Description: "Laut Studienplan im selben Fachsemester vorgesehene Pflichtmodule eines Studiengangs sollen nicht im selben Slot liegen, auch wenn (noch) keine Anmeldungen dafür vorliegen."}
+}
+func (curriculumC) Cost(st *State) (float64, []optimize.Violation) { return curriculumCost(st) }
+
 type slotLoadC struct{ w Weights }
 
 func (c slotLoadC) Info() optimize.Info {
@@ -379,6 +405,33 @@ func (c placementC) Info() optimize.Info {
 }
 func (placementC) Cost(st *State) (float64, []optimize.Violation) { return unplacedCost(st) }
 
+// ruleC is one user-defined soft rule (Problem.rules), listed under "rule:<name>".
+type ruleC struct {
+	i int
+	r *Rule
+}
+
+func (c ruleC) Info() optimize.Info {
+	title := c.r.Title
+	if title == "" {
+		title = c.r.Name
+	}
+	return optimize.Info{Name: "rule:" + c.r.Name, Title: "Eigene Regel: " + title, Kind: optimize.KindSoft, Weight: c.r.Weight, Tier: 40 + c.i,
+		Description: c.r.Description}
+}
+func (c ruleC) Cost(st *State) (float64, []optimize.Violation) {
+	var total float64
+	var vs []optimize.Violation
+	for u := range st.P.Units {
+		if pen := c.r.cost(u, st.SlotOf[u]); pen > 0 {
+			total += pen
+			vs = append(vs, optimize.Violation{Constraint: "rule:" + c.r.Name, Penalty: pen, Refs: st.P.Units[u].Ancodes,
+				Message: "Regel „" + c.r.Name + "“ verletzt"})
+		}
+	}
+	return total, vs
+}
+
 // UnplacedAncodes returns the ancodes of units left without a slot (for reporting).
 func (st *State) UnplacedAncodes() []int {
 	var out []int
@@ -7,9 +7,12 @@ import (
 	"time"
 
 	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/campus"
 	"github.com/obcode/plexams.go/plexams/conflictcalc"
+	"github.com/obcode/plexams.go/plexams/curriculum"
 	"github.com/obcode/plexams.go/plexams/examplan"
 	"github.com/obcode/plexams.go/plexams/optimize"
+	"github.com/obcode/plexams.go/plexams/regforecast"
 	"github.com/obcode/plexams.go/plexams/repeatcalc"
 	"github.com/rs/zerolog/log"
 )
@@ -28,7 +31,8 @@ const defaultExamGapMinutes = 30
 // two of their exams held at DIFFERENT campuses (e.g. Pasing ↔ Lothstraße) when the semester
 // config leaves crossCampusGapMinutes unset: the travel time makes a tighter placement
 // impossible even though neither exam's time window overlaps. It is a HARD separation (like
-// the same-campus examGap, just larger) applied whenever the two exams' locations differ.
+// the same-campus examGap, just larger) applied between two different campuses that have no
+// travel time in the campus model.
 const defaultCrossCampusGapMinutes = 120
 
 // effectiveGapMinutes is the required end-to-start buffer between two of a student's exams
@@ -44,6 +48,19 @@ func effectiveGapMinutes(examGap, crossCampusGap int, locA, locB string) int {
 	return examGap
 }
 
+// travelMinutes is the crossCampusGap between two exam locations: the campus model's
+// travel time (0 on the same campus, the matrix entry, else crossCampusGapMinutes).
+// Without a campus model (nil) two differing locations are crossCampusGapMinutes apart.
+func (p *Plexams) travelMinutes(sites *campus.Model, locA, locB string) int {
+	if sites == nil {
+		if locA == locB {
+			return 0
+		}
+		return p.crossCampusGapMinutes()
+	}
+	return sites.TravelMinutes(locA, locB)
+}
+
 // ExamScheduleResult summarizes a Terminplan generation run.
 type ExamScheduleResult struct {
 	Units             int
@@ -100,12 +117,19 @@ type examPlanBuildInfo struct {
 // buildExamPlanProblem assembles the exam-schedule optimization problem from the
 // current data: assembled exams to plan (movable), fixed obstacles (locked / external
 // / not-planned-by-me), per-student conflict pairs, EXaHM slot capacities and the
-// attract pairs (parallel sections / small same-examer exams).
-func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPhase bool) (*examplan.Problem, *examPlanBuildInfo, error) {
+// attract pairs (parallel sections / small same-examer exams). With useForecast, our exams
+// without registrations are sized by their registration forecast and the estimated
+// conflicts between them enter as forecast students.
+func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPhase, useForecast bool) (*examplan.Problem, *examPlanBuildInfo, error) {
 	sc := p.semesterConfig
 	if sc == nil {
 		return nil, nil, fmt.Errorf("no semester config loaded")
 	}
+	// campus model: travel time between the campuses of two exams (location-aware gap)
+	sites, err := p.campusModel(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// --- slots ---
 	slotStarts := make([]time.Time, 0, len(sc.Slots))
@@ -189,6 +213,28 @@ func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPh
 		}
 	}
 
+	regsOf := func(e *model.AssembledExam) int { return e.StudentRegsCount }
+	var forecastConflicts []regforecast.Conflict
+	if useForecast {
+		targets := make([]regforecast.Target, 0)
+		for _, e := range assembled {
+			if e.StudentRegsCount == 0 && e.ZpaExam != nil {
+				targets = append(targets, regforecast.Target{ID: e.Ancode, Module: e.ZpaExam.Module, MainExamer: e.ZpaExam.MainExamer})
+			}
+		}
+		expected, conflicts, err := p.forecastFor(ctx, targets)
+		if err != nil {
+			return nil, nil, err
+		}
+		forecastConflicts = conflicts
+		regsOf = func(e *model.AssembledExam) int {
+			if e.StudentRegsCount == 0 {
+				return expected[e.Ancode]
+			}
+			return e.StudentRegsCount
+		}
+	}
+
 	type exRec struct {
 		e          *model.AssembledExam
 		fixedSlot  int // -1 if movable
@@ -207,7 +253,7 @@ func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPh
 		// no registrations → no exam: our own 0-registration exams are not planned
 		// (dynamic — reincluded automatically once registrations appear). Foreign exams
 		// keep 0 of our regs on purpose and stay as time obstacles.
-		if e.StudentRegsCount == 0 && !foreign {
+		if regsOf(e) == 0 && !foreign {
 			noRegsSkipped = append(noRegsSkipped, e.Ancode)
 			continue
 		}
@@ -236,7 +282,7 @@ func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPh
 		// to the FIXED conflicting exams (placedFixed): those cannot move, so they genuinely
 		// constrain this exam. Movable-vs-movable overlaps are handled by the hard separations
 		// installed below, not by pre-restricting the domain to a warm-start position.
-		allowedSlots := p.allowedSlotsFor(e, placedFixed, gapMin)
+		allowedSlots := p.allowedSlotsFor(e, placedFixed, gapMin, sites)
 		idxs := make([]int, 0, len(allowedSlots))
 		for _, s := range allowedSlots {
 			if idx, ok := idxByStart[s.Starttime]; ok {
@@ -314,7 +360,7 @@ func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPh
 		for _, a := range members {
 			e := rec[a].e
 			u.Ancodes = append(u.Ancodes, a)
-			u.Seats += e.StudentRegsCount
+			u.Seats += regsOf(e)
 			if rec[a].exahm {
 				u.Exahm = true
 			}
@@ -372,7 +418,7 @@ func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPh
 		}
 		idx := len(units)
 		units = append(units, examplan.Unit{
-			ID: a, Ancodes: []int{a}, Seats: r.e.StudentRegsCount, Exahm: r.exahm, Seb: r.seb,
+			ID: a, Ancodes: []int{a}, Seats: regsOf(r.e), Exahm: r.exahm, Seb: r.seb,
 			Examer: r.e.ZpaExam.MainExamerID, Module: r.e.ZpaExam.Module, Program: firstProgram(r.e),
 			Fixed: true, FixedSlot: r.fixedSlot, Foreign: r.foreign, Location: locationOf(constraints[a]),
 			StartSlot: -1,
@@ -535,6 +581,8 @@ func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPh
 	// the window is inert there.
 	timeSpec := p.slotTimeSpec(ctx, slots)
 	w.TimeOfDay = timeSpec.weight
+	// repeats: by the student history, else the study-group heuristic
+	repeats := p.repeatDecider(ctx)
 	students := make([]examplan.Student, 0, len(studentsRaw))
 	for _, s := range studentsRaw {
 		seen := make(map[int]bool)
@@ -549,7 +597,10 @@ func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPh
 			continue
 		}
 		sort.Ints(list)
-		studSem := repeatcalc.SemesterOf(s.Group)
+		repeatOf := make(map[int]bool, len(list))
+		for _, u := range list {
+			repeatOf[u] = repeats.decide(s.Mtknr, s.Group, units[u].Module, unitRepeater[u], unitSemester[u]).Repeat
+		}
 		var pairs []examplan.Pair
 		for i := 0; i < len(list); i++ {
 			for j := i + 1; j < len(list); j++ {
@@ -561,8 +612,7 @@ func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPh
 				if canShare[up] {
 					continue // declared shareable: drop hard + soft entirely
 				}
-				isRepeat := repeatcalc.RepeatForStudent(studSem, unitRepeater[a], unitSemester[a]) ||
-					repeatcalc.RepeatForStudent(studSem, unitRepeater[b], unitSemester[b])
+				isRepeat := repeatOf[a] || repeatOf[b]
 				weight := 1.0
 				switch dec := decisions[s.Mtknr][up]; {
 				case dec == model.ConflictDecisionAccept:
@@ -572,11 +622,24 @@ func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPh
 				case isRepeat:
 					weight = w.RepeatFactor // auto down-weight for (likely) repeats
 				}
-				pairs = append(pairs, examplan.Pair{A: a, B: b, Weight: weight, CrossLoc: units[a].Location != units[b].Location})
+				pairs = append(pairs, examplan.Pair{A: a, B: b, Weight: weight, CrossLoc: p.travelMinutes(sites, units[a].Location, units[b].Location) > 0})
 			}
 		}
 		if len(pairs) > 0 {
-			students = append(students, examplan.Student{ID: s.Mtknr, Pairs: pairs})
+			students = append(students, examplan.Student{ID: s.Mtknr, Program: s.Program, Pairs: pairs})
+		}
+	}
+	// forecast conflicts between exams without registrations: one forecast student per
+	// estimated shared student
+	for _, c := range forecastConflicts {
+		a, okA := unitOf[c.A]
+		b, okB := unitOf[c.B]
+		if !okA || !okB || a == b || canShare[[2]int{min(a, b), max(a, b)}] {
+			continue
+		}
+		pair := examplan.Pair{A: min(a, b), B: max(a, b), Weight: 1, CrossLoc: p.travelMinutes(sites, units[a].Location, units[b].Location) > 0}
+		for k := 0; k < c.Students; k++ {
+			students = append(students, examplan.Student{ID: fmt.Sprintf("forecast-%d-%d-%d", c.A, c.B, k), Pairs: []examplan.Pair{pair}})
 		}
 	}
 	// deterministic order (the DB/query order is not guaranteed) so a re-run with the
@@ -647,7 +710,7 @@ func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPh
 	hardSep := make(map[[2]int]int)
 	for _, s := range students {
 		for _, pr := range s.Pairs {
-			pairGap := effectiveGapMinutes(gapMin, p.crossCampusGapMinutes(), units[pr.A].Location, units[pr.B].Location)
+			pairGap := effectiveGapMinutes(gapMin, p.travelMinutes(sites, units[pr.A].Location, units[pr.B].Location), units[pr.A].Location, units[pr.B].Location)
 			if sep := occFor(pr.A, s.ID) + pairGap; sep > hardSep[[2]int{pr.A, pr.B}] {
 				hardSep[[2]int{pr.A, pr.B}] = sep
 			}
@@ -830,6 +893,50 @@ func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPh
 	prob.SetTimeWindow(timeSpec.mode, timeSpec.earliestMin, timeSpec.latestMin)
 	prob.SetHardSeparations(hardSep)
 	prob.SetOverrunTargets(overrun)
+	// expected co-registrations from the curricula (soft): required modules of the same
+	// program semester should not start in the same slot
+	curricExams := make([]curriculum.Exam, 0, len(assembled))
+	for _, e := range assembled {
+		if e.ZpaExam != nil {
+			curricExams = append(curricExams, curriculum.Exam{Ancode: e.Ancode, Module: e.ZpaExam.Module})
+		}
+	}
+	curricConflicts, err := p.curriculumConflictsOf(ctx, curricExams)
+	if err != nil {
+		return nil, nil, err
+	}
+	curricWeight := make(map[[2]int]float64)
+	for _, c := range curricConflicts {
+		a, okA := unitOf[c.A]
+		b, okB := unitOf[c.B]
+		key := [2]int{min(a, b), max(a, b)}
+		if !okA || !okB || a == b || canShare[key] {
+			continue
+		}
+		curricWeight[key] = max(curricWeight[key], float64(len(c.Placements)))
+	}
+	curricPairs := make([]examplan.CurriculumPair, 0, len(curricWeight))
+	for key, weight := range curricWeight {
+		curricPairs = append(curricPairs, examplan.CurriculumPair{A: key[0], B: key[1], Weight: weight})
+	}
+	sort.Slice(curricPairs, func(i, j int) bool {
+		if curricPairs[i].A != curricPairs[j].A {
+			return curricPairs[i].A < curricPairs[j].A
+		}
+		return curricPairs[i].B < curricPairs[j].B
+	})
+	prob.SetCurriculumPairs(curricPairs)
+	// user-defined soft rules (validated on save; a rule that no longer compiles is skipped
+	// with an error in the log rather than failing the whole generation)
+	if rules, err := compileSoftRules(genCfg.SoftRules); err != nil {
+		log.Error().Err(err).Msg("user-defined soft rules ignored")
+	} else if len(rules) > 0 {
+		examByAncode := make(map[int]*model.AssembledExam, len(assembled))
+		for _, e := range assembled {
+			examByAncode[e.Ancode] = e
+		}
+		prob.SetRules(examPlanRules(rules, units, slots, examByAncode, constraints))
+	}
 	return prob, &examPlanBuildInfo{exahmNtaAncodes: exahmWithNTA, unplaceableReason: unplaceableReason}, nil
 }
 
@@ -838,29 +945,32 @@ func (p *Plexams) buildExamPlanProblem(ctx context.Context, applyRatings, roomPh
 // non-fixed plan entries (locked / external / not-planned-by-me stay untouched) and
 // removes stale entries of any exam that ended up unplaced. It refuses to write when
 // there are hard violations.
-func (p *Plexams) GenerateExamSchedule(ctx context.Context, dryRun bool, seed int64, iterations int, ignoreRatings, keepAssigned bool, reporter Reporter) (*ExamScheduleResult, error) {
-	return p.runExamGeneration(ctx, false, dryRun, seed, iterations, ignoreRatings, keepAssigned, reporter, condExamScheduleGenerated)
+func (p *Plexams) GenerateExamSchedule(ctx context.Context, dryRun bool, seed int64, iterations int, ignoreRatings, keepAssigned, useForecast bool, reporter Reporter) (*ExamScheduleResult, error) {
+	return p.runExamGeneration(ctx, false, dryRun, seed, iterations, ignoreRatings, keepAssigned, useForecast, reporter, condExamScheduleGenerated)
 }
 
 // GenerateExamRoomsPhase runs phase A: it schedules only the EXaHM/SEB exams into the
 // booked T-building slots (maximizing room usage), leaving everything else for phase B.
 func (p *Plexams) GenerateExamRoomsPhase(ctx context.Context, dryRun bool, seed int64, iterations int, reporter Reporter) (*ExamScheduleResult, error) {
-	return p.runExamGeneration(ctx, true, dryRun, seed, iterations, false, false, reporter, condExahmSebPlanned)
+	return p.runExamGeneration(ctx, true, dryRun, seed, iterations, false, false, false, reporter, condExahmSebPlanned)
 }
 
-func (p *Plexams) runExamGeneration(ctx context.Context, roomPhase, dryRun bool, seed int64, iterations int, ignoreRatings, keepAssigned bool, reporter Reporter, doneCond string) (*ExamScheduleResult, error) {
+func (p *Plexams) runExamGeneration(ctx context.Context, roomPhase, dryRun bool, seed int64, iterations int, ignoreRatings, keepAssigned, useForecast bool, reporter Reporter, doneCond string) (*ExamScheduleResult, error) {
 	if ignoreRatings {
 		reporter.Println("Konflikt-Bewertungen werden für diesen Lauf ignoriert")
 	}
 	if keepAssigned {
 		reporter.Println("Warm-Start: bestehender Plan wird als Ausgangspunkt behalten")
 	}
+	if useForecast {
+		reporter.Println("Entwurf mit Anmeldeprognose: Prüfungen ohne Anmeldungen werden mit prognostizierten Anmeldungen und Konflikten geplant")
+	}
 	if roomPhase {
 		reporter.Step("EXaHM/SEB-Raumphase wird aufgebaut …")
 	} else {
 		reporter.Step("Terminplan-Problem wird aufgebaut …")
 	}
-	prob, buildInfo, err := p.buildExamPlanProblem(ctx, !ignoreRatings, roomPhase)
+	prob, buildInfo, err := p.buildExamPlanProblem(ctx, !ignoreRatings, roomPhase, useForecast)
 	if err != nil {
 		reporter.StopProgressFail("Aufbau fehlgeschlagen: " + err.Error())
 		return nil, err
@@ -1188,10 +1298,19 @@ func sameCalendarDay(a, b time.Time) bool {
 }
 
 // ExamScheduleConstraints returns the read-only description of the hard/soft
-// constraints the exam-schedule generator applies.
-func (p *Plexams) ExamScheduleConstraints() []optimize.Info {
+// constraints the exam-schedule generator applies, including the enabled user rules.
+func (p *Plexams) ExamScheduleConstraints(ctx context.Context) ([]optimize.Info, error) {
 	prob := &examplan.Problem{W: examplan.DefaultWeights()}
-	return prob.Registry().Describe()
+	cfg, err := p.GenerationConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := compileSoftRules(cfg.SoftRules)
+	if err != nil {
+		return nil, err
+	}
+	prob.SetRules(examPlanRules(rules, nil, nil, nil, nil))
+	return prob.Registry().Describe(), nil
 }
 
 func locationOf(c *model.Constraints) string {
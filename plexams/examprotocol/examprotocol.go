@@ -0,0 +1,369 @@
+// Package examprotocol handles the protocols that come back after an exam, one per exam
+// and room: building a checked protocol from what was entered, the table for bulk entry
+// (prefilled with the planned rooms) and its import with a diff against the stored
+// protocols, and the no-show statistics. It is I/O-free; storing and logging stays in
+// the plexams package.
+//
+// A row of the table is identified by Ancode + Raum (an exam uses a room only once), so
+// rows may be reordered, filtered or dropped.
+package examprotocol
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/tableio"
+)
+
+// Column headers of the protocol table.
+const (
+	ColAncode     = "Ancode"
+	ColModule     = "Prüfung"
+	ColExamer     = "Prüfende"
+	ColRoom       = "Raum"
+	ColStart      = "Prüfungsbeginn"
+	ColRegistered = "angemeldet"
+	ColPresent    = "anwesend"
+	ColAbsent     = "abwesend"
+	ColEnd        = "Ende"
+	ColIncidents  = "Vorkommnisse"
+	ColNote       = "Bemerkung"
+)
+
+// Header is the column order of the protocol table.
+var Header = []string{ColAncode, ColModule, ColExamer, ColRoom, ColStart, ColRegistered, ColPresent, ColAbsent, ColEnd, ColIncidents, ColNote}
+
+// Planned is an exam in a planned room, the unit a protocol is recorded for.
+type Planned struct {
+	Ancode     int
+	Module     string
+	MainExamer string
+	Room       string
+	Start      time.Time
+	Registered int
+}
+
+// Key identifies an exam in a room.
+func Key(ancode int, room string) string {
+	return fmt.Sprintf("%d@%s", ancode, strings.ToUpper(strings.ReplaceAll(room, " ", "")))
+}
+
+// Build checks what was entered for a planned exam room and returns the protocol
+// (source MANUAL). At least one of present and absent is needed; the missing one is
+// registered minus the other. The actual end must be after the start.
+func Build(pl Planned, present, absent *int, incidents []string, actualEnd *time.Time, note *string) (*model.ExamProtocol, error) {
+	switch {
+	case present == nil && absent == nil:
+		return nil, fmt.Errorf("%d in %s: present or absent students needed", pl.Ancode, pl.Room)
+	case present == nil:
+		p := max(pl.Registered-*absent, 0)
+		present = &p
+	case absent == nil:
+		a := max(pl.Registered-*present, 0)
+		absent = &a
+	}
+	if *present < 0 || *absent < 0 {
+		return nil, fmt.Errorf("%d in %s: negative number of students", pl.Ancode, pl.Room)
+	}
+	if actualEnd != nil && !actualEnd.After(pl.Start) {
+		return nil, fmt.Errorf("%d in %s: the end %s is not after the start", pl.Ancode, pl.Room, actualEnd.Local().Format("15:04"))
+	}
+	cleaned := make([]string, 0, len(incidents))
+	for _, i := range incidents {
+		if i = strings.TrimSpace(i); i != "" {
+			cleaned = append(cleaned, i)
+		}
+	}
+	if note != nil && strings.TrimSpace(*note) == "" {
+		note = nil
+	}
+	return &model.ExamProtocol{
+		Ancode: pl.Ancode, Module: pl.Module, MainExamer: pl.MainExamer, RoomName: pl.Room, Starttime: pl.Start,
+		Registered: pl.Registered, Present: *present, Absent: *absent, Incidents: cleaned,
+		ActualEnd: actualEnd, Note: note, Source: model.ExamProtocolSourceManual,
+	}, nil
+}
+
+// Rows builds the table rows (without header), one per planned exam room sorted by
+// start, ancode and room, prefilled with the stored protocols.
+func Rows(planned []Planned, existing []*model.ExamProtocol) [][]string {
+	byKey := make(map[string]*model.ExamProtocol, len(existing))
+	for _, p := range existing {
+		byKey[Key(p.Ancode, p.RoomName)] = p
+	}
+	sorted := slices.Clone(planned)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		if a.Ancode != b.Ancode {
+			return a.Ancode < b.Ancode
+		}
+		return a.Room < b.Room
+	})
+	rows := make([][]string, 0, len(sorted))
+	for _, pl := range sorted {
+		row := []string{
+			strconv.Itoa(pl.Ancode), pl.Module, pl.MainExamer, pl.Room, pl.Start.Local().Format("02.01.2006 15:04"),
+			strconv.Itoa(pl.Registered), "", "", "", "", "",
+		}
+		if p := byKey[Key(pl.Ancode, pl.Room)]; p != nil {
+			row[6], row[7] = strconv.Itoa(p.Present), strconv.Itoa(p.Absent)
+			if p.ActualEnd != nil {
+				row[8] = p.ActualEnd.Local().Format("15:04")
+			}
+			row[9] = strings.Join(p.Incidents, " | ")
+			if p.Note != nil {
+				row[10] = *p.Note
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// CSV renders the protocol table as CSV (UTF-8 with BOM, semicolons, so German Excel
+// opens it directly).
+func CSV(planned []Planned, existing []*model.ExamProtocol) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\xEF\xBB\xBF")
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+	if err := w.Write(Header); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(Rows(planned, existing)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Entry is one filled-in row of the table.
+type Entry struct {
+	Line      int
+	Ancode    int
+	Room      string
+	Present   *int
+	Absent    *int
+	End       string // "15:04", the date is the exam's
+	Incidents []string
+	Note      string
+}
+
+// Parse reads the filled-in table (XLSX or CSV with "," or ";"). Rows without present
+// and absent students are not filled in yet and only counted; unreadable rows are
+// returned as problems (with the line number) instead of failing the whole file.
+func Parse(data []byte) (entries []Entry, empty int, problems []string, err error) {
+	rows, err := tableio.Read(data)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if len(rows) == 0 {
+		return nil, 0, nil, fmt.Errorf("file is empty")
+	}
+	col := make(map[string]int)
+	for i, h := range rows[0] {
+		col[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\xEF\xBB\xBF")))] = i
+	}
+	for _, need := range []string{ColAncode, ColRoom} {
+		if _, ok := col[strings.ToLower(need)]; !ok {
+			return nil, 0, nil, fmt.Errorf("column %q missing", need)
+		}
+	}
+	get := func(row []string, name string) string {
+		i, ok := col[strings.ToLower(name)]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+	number := func(line int, row []string, name string) (*int, bool) {
+		s := get(row, name)
+		if s == "" {
+			return nil, true
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: cannot read %s %q", line, name, s))
+			return nil, false
+		}
+		return &n, true
+	}
+
+	for n, row := range rows[1:] {
+		line := n + 2
+		ancodeStr, room := get(row, ColAncode), get(row, ColRoom)
+		if ancodeStr == "" && room == "" {
+			continue
+		}
+		present, okP := number(line, row, ColPresent)
+		absent, okA := number(line, row, ColAbsent)
+		if !okP || !okA {
+			continue
+		}
+		if present == nil && absent == nil {
+			empty++
+			continue
+		}
+		ancode, err := strconv.Atoi(ancodeStr)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: cannot read %s %q", line, ColAncode, ancodeStr))
+			continue
+		}
+		incidents := make([]string, 0)
+		for _, i := range strings.FieldsFunc(get(row, ColIncidents), func(r rune) bool { return r == '|' || r == '\n' }) {
+			if i = strings.TrimSpace(i); i != "" {
+				incidents = append(incidents, i)
+			}
+		}
+		entries = append(entries, Entry{
+			Line: line, Ancode: ancode, Room: room, Present: present, Absent: absent,
+			End: get(row, ColEnd), Incidents: incidents, Note: get(row, ColNote),
+		})
+	}
+	return entries, empty, problems, nil
+}
+
+// Import is the diff preview (and, once applied, the outcome) of a filled-in table.
+type Import struct {
+	Applied bool `json:"applied"`
+	// Protocols are the new and changed protocols (source IMPORT).
+	Protocols []*model.ExamProtocol `json:"protocols"`
+	New       int                   `json:"new"`
+	Changed   int                   `json:"changed"`
+	Unchanged int                   `json:"unchanged"`
+	// Empty rows are not filled in yet.
+	Empty int `json:"empty"`
+	// Problems are unreadable or unknown rows; an import with problems is not applied.
+	Problems []string `json:"problems"`
+}
+
+// Summary is a one-line description of the diff for the mutation log.
+func (i *Import) Summary() string {
+	return fmt.Sprintf("%d new, %d changed, %d unchanged", i.New, i.Changed, i.Unchanged)
+}
+
+// Diff checks the entries against the planned exam rooms and compares them with the
+// stored protocols.
+func Diff(planned []Planned, existing []*model.ExamProtocol, entries []Entry, empty int, problems []string) *Import {
+	plannedByKey := make(map[string]Planned, len(planned))
+	for _, pl := range planned {
+		plannedByKey[Key(pl.Ancode, pl.Room)] = pl
+	}
+	existingByKey := make(map[string]*model.ExamProtocol, len(existing))
+	for _, p := range existing {
+		existingByKey[Key(p.Ancode, p.RoomName)] = p
+	}
+
+	out := &Import{Protocols: make([]*model.ExamProtocol, 0), Empty: empty, Problems: append(make([]string, 0), problems...)}
+	seen := make(map[string]int)
+	for _, e := range entries {
+		k := Key(e.Ancode, e.Room)
+		pl, ok := plannedByKey[k]
+		if !ok {
+			out.Problems = append(out.Problems, fmt.Sprintf("line %d: exam %d is not planned in %s", e.Line, e.Ancode, e.Room))
+			continue
+		}
+		if prev, ok := seen[k]; ok {
+			out.Problems = append(out.Problems, fmt.Sprintf("line %d: %d in %s already in line %d", e.Line, e.Ancode, e.Room, prev))
+			continue
+		}
+		seen[k] = e.Line
+
+		var end *time.Time
+		if e.End != "" {
+			clock, err := time.Parse("15:04", strings.ReplaceAll(e.End, ".", ":"))
+			if err != nil {
+				out.Problems = append(out.Problems, fmt.Sprintf("line %d: cannot read %s %q (15:04)", e.Line, ColEnd, e.End))
+				continue
+			}
+			start := pl.Start.Local()
+			t := time.Date(start.Year(), start.Month(), start.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+			end = &t
+		}
+		var note *string
+		if e.Note != "" {
+			note = &e.Note
+		}
+		protocol, err := Build(pl, e.Present, e.Absent, e.Incidents, end, note)
+		if err != nil {
+			out.Problems = append(out.Problems, fmt.Sprintf("line %d: %v", e.Line, err))
+			continue
+		}
+		protocol.Source = model.ExamProtocolSourceImport
+
+		before := existingByKey[k]
+		switch {
+		case before == nil:
+			out.New++
+		case Same(before, protocol):
+			out.Unchanged++
+			continue
+		default:
+			out.Changed++
+		}
+		out.Protocols = append(out.Protocols, protocol)
+	}
+	return out
+}
+
+// Same reports whether two protocols of the same exam room record the same.
+func Same(a, b *model.ExamProtocol) bool {
+	sameTime := (a.ActualEnd == nil) == (b.ActualEnd == nil) && (a.ActualEnd == nil || a.ActualEnd.Equal(*b.ActualEnd))
+	sameNote := (a.Note == nil) == (b.Note == nil) && (a.Note == nil || *a.Note == *b.Note)
+	return a.Present == b.Present && a.Absent == b.Absent && sameTime && sameNote && slices.Equal(a.Incidents, b.Incidents)
+}
+
+// Statistics computes the no-show statistics of the protocols. roomsPerExam is the
+// number of planned rooms with students per exam, to tell complete exams.
+func Statistics(workspace string, protocols []*model.ExamProtocol, roomsPerExam map[int]int) *model.NoShowStatistics {
+	stat := &model.NoShowStatistics{Workspace: workspace, ByExam: make([]*model.NoShowExam, 0)}
+	byAncode := make(map[int]*model.NoShowExam)
+	rooms := make(map[int]int)
+	for _, p := range protocols {
+		e, ok := byAncode[p.Ancode]
+		if !ok {
+			e = &model.NoShowExam{Ancode: p.Ancode, Module: p.Module, MainExamer: p.MainExamer}
+			byAncode[p.Ancode] = e
+			stat.ByExam = append(stat.ByExam, e)
+		}
+		e.Registered += p.Registered
+		e.Present += p.Present
+		e.Absent += p.Absent
+		rooms[p.Ancode]++
+		stat.Rooms++
+		stat.Registered += p.Registered
+		stat.Present += p.Present
+		stat.Absent += p.Absent
+	}
+	for _, e := range stat.ByExam {
+		e.Rate = Rate(e.Present, e.Absent)
+		e.Complete = rooms[e.Ancode] >= roomsPerExam[e.Ancode]
+	}
+	stat.Exams = len(stat.ByExam)
+	stat.Rate = Rate(stat.Present, stat.Absent)
+	sort.Slice(stat.ByExam, func(i, j int) bool {
+		if stat.ByExam[i].Rate != stat.ByExam[j].Rate {
+			return stat.ByExam[i].Rate > stat.ByExam[j].Rate
+		}
+		return stat.ByExam[i].Ancode < stat.ByExam[j].Ancode
+	})
+	return stat
+}
+
+// Rate is absent / (present + absent) in percent, rounded to one decimal.
+func Rate(present, absent int) float64 {
+	if present+absent == 0 {
+		return 0
+	}
+	return math.Round(float64(absent)*1000/float64(present+absent)) / 10
+}
@@ -0,0 +1,119 @@
+package examprotocol
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+var start = time.Date(2026, 7, 13, 10, 30, 0, 0, time.Local)
+
+func samplePlanned() []Planned {
+	return []Planned{
+		{Ancode: 324, Module: "Datenbanken", MainExamer: "Mustermann", Room: "R1.049", Start: start, Registered: 30},
+		{Ancode: 324, Module: "Datenbanken", MainExamer: "Mustermann", Room: "R1.046", Start: start, Registered: 20},
+		{Ancode: 101, Module: "Mathematik I", MainExamer: "Muster", Room: "R3.016", Start: start.Add(-2 * time.Hour), Registered: 40},
+	}
+}
+
+func intp(i int) *int { return &i }
+
+func TestBuild(t *testing.T) {
+	pl := samplePlanned()[0]
+	p, err := Build(pl, nil, intp(4), []string{" Feueralarm ", ""}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Present != 26 || p.Absent != 4 || len(p.Incidents) != 1 || p.Incidents[0] != "Feueralarm" {
+		t.Errorf("got present %d, absent %d, incidents %v", p.Present, p.Absent, p.Incidents)
+	}
+	if p.Source != model.ExamProtocolSourceManual {
+		t.Errorf("source = %s", p.Source)
+	}
+
+	early := start.Add(-time.Minute)
+	for name, err := range map[string]error{
+		"nothing":  func() error { _, err := Build(pl, nil, nil, nil, nil, nil); return err }(),
+		"negative": func() error { _, err := Build(pl, intp(-1), intp(3), nil, nil, nil); return err }(),
+		"end":      func() error { _, err := Build(pl, intp(30), nil, nil, &early, nil); return err }(),
+	} {
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRows(t *testing.T) {
+	end := start.Add(95 * time.Minute)
+	note := "Klausur 5 Minuten später ausgeteilt"
+	existing := []*model.ExamProtocol{{Ancode: 324, RoomName: "R1.049", Present: 27, Absent: 3, ActualEnd: &end, Note: &note, Incidents: []string{"a", "b"}}}
+	rows := Rows(samplePlanned(), existing)
+	if len(rows) != 3 || rows[0][0] != "101" || rows[1][3] != "R1.046" {
+		t.Fatalf("unexpected order: %v", rows)
+	}
+	want := []string{"324", "Datenbanken", "Mustermann", "R1.049", "13.07.2026 10:30", "30", "27", "3", "12:05", "a | b", note}
+	if strings.Join(rows[2], ";") != strings.Join(want, ";") {
+		t.Errorf("rows[2] = %v, want %v", rows[2], want)
+	}
+}
+
+func TestCSVImport(t *testing.T) {
+	planned := samplePlanned()
+	data, err := CSV(planned, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines", len(lines))
+	}
+	// fill in two rows (one with only the absent count) and add an exam not planned
+	lines[2] = strings.Replace(lines[2], ";20;;;;;", ";20;18;;12.10;Handy | Toilette;", 1)
+	lines[3] = strings.Replace(lines[3], ";30;;;;;", ";30;;5;;;", 1)
+	lines = append(lines, "999;X;Y;R0.001;13.07.2026 10:30;1;1;0;;;")
+	entries, empty, problems, err := Parse([]byte(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || empty != 1 || len(problems) != 0 {
+		t.Fatalf("entries %d, empty %d, problems %v", len(entries), empty, problems)
+	}
+
+	result := Diff(planned, nil, entries, empty, problems)
+	if len(result.Problems) != 1 || !strings.Contains(result.Problems[0], "999") {
+		t.Errorf("problems = %v", result.Problems)
+	}
+	if result.New != 2 || len(result.Protocols) != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	p := result.Protocols[0]
+	if p.RoomName != "R1.046" || p.Present != 18 || p.Absent != 2 || p.ActualEnd == nil ||
+		!p.ActualEnd.Equal(start.Add(100*time.Minute)) || len(p.Incidents) != 2 || p.Source != model.ExamProtocolSourceImport {
+		t.Errorf("protocol = %+v", p)
+	}
+
+	// importing the same again changes nothing
+	again := Diff(planned, result.Protocols, entries[:2], 0, nil)
+	if again.Unchanged != 2 || len(again.Protocols) != 0 {
+		t.Errorf("second import = %+v", again)
+	}
+}
+
+func TestStatistics(t *testing.T) {
+	protocols := []*model.ExamProtocol{
+		{Ancode: 324, RoomName: "R1.049", Registered: 30, Present: 27, Absent: 3},
+		{Ancode: 101, RoomName: "R3.016", Registered: 40, Present: 30, Absent: 10},
+	}
+	stat := Statistics("2026-SS", protocols, map[int]int{324: 2, 101: 1})
+	if stat.Exams != 2 || stat.Rooms != 2 || stat.Absent != 13 || stat.Rate != 18.6 {
+		t.Errorf("stat = %+v", stat)
+	}
+	if stat.ByExam[0].Ancode != 101 || stat.ByExam[0].Rate != 25 || !stat.ByExam[0].Complete {
+		t.Errorf("first exam = %+v", stat.ByExam[0])
+	}
+	if stat.ByExam[1].Complete {
+		t.Error("324 has a room without protocol and is not complete")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,32 +23,53 @@ func planEntryStart(pe *model.PlanEntry) (time.Time, bool) {
 	return *pe.Starttime, true
 }
 
-// invigilatorForRoomAtTime resolves the invigilator on duty in a room (or the
+// invigilatorForRoomAtTime resolves the (lead) invigilator on duty in a room (or the
 // reserve, roomName == "reserve") at the given absolute start time by matching the
 // persisted invigilation Starttime. It replaces the slot-based
 // GetInvigilatorForRoom / GetInvigilatorInSlot lookups. Returns (nil, nil) when no
 // invigilation covers that room at that time.
 func (p *Plexams) invigilatorForRoomAtTime(ctx context.Context, roomName string, start time.Time) (*model.Teacher, error) {
+	invigilators, err := p.invigilatorsForRoomAtTime(ctx, roomName, start)
+	if err != nil || len(invigilators) == 0 {
+		return nil, err
+	}
+	return invigilators[0], nil
+}
+
+// invigilatorsForRoomAtTime resolves all invigilators on duty in a room (or the
+// reserve) at the given absolute start time, ordered by position (lead first). A room
+// has more than one when the staffing rules ask for it.
+func (p *Plexams) invigilatorsForRoomAtTime(ctx context.Context, roomName string, start time.Time) ([]*model.Teacher, error) {
 	invigilations, err := p.dbClient.GetAllInvigilations(ctx)
 	if err != nil {
 		log.Error().Err(err).Msg("cannot get invigilations")
 		return nil, err
 	}
+	matching := make([]*model.Invigilation, 0, 1)
 	for _, inv := range invigilations {
 		if inv.Starttime == nil || !inv.Starttime.Equal(start) {
 			continue
 		}
 		if roomName == "reserve" {
 			if inv.RoomName == nil && inv.IsReserve {
-				return p.GetTeacher(ctx, inv.InvigilatorID)
+				matching = append(matching, inv)
 			}
 			continue
 		}
 		if inv.RoomName != nil && *inv.RoomName == roomName {
-			return p.GetTeacher(ctx, inv.InvigilatorID)
+			matching = append(matching, inv)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool { return matching[i].Position < matching[j].Position })
+	teachers := make([]*model.Teacher, 0, len(matching))
+	for _, inv := range matching {
+		teacher, err := p.GetTeacher(ctx, inv.InvigilatorID)
+		if err != nil {
+			return nil, err
 		}
+		teachers = append(teachers, teacher)
 	}
-	return nil, nil
+	return teachers, nil
 }
 
 type ExportNtas struct {
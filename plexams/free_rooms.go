@@ -0,0 +1,169 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/anny"
+	"github.com/obcode/plexams.go/plexams/preplancalc"
+	"github.com/obcode/plexams.go/plexams/roomcalc"
+)
+
+// FreeRooms finds rooms free in [from, until) for an ad-hoc use besides the exam plan
+// (make-up exam, Einsicht), see roomcalc.FreeRooms.
+func (p *Plexams) FreeRooms(ctx context.Context, from, until time.Time, seats int, tags []string) ([]*model.FreeRoom, error) {
+	if !until.After(from) {
+		return nil, fmt.Errorf("until must be after from")
+	}
+	required, err := roomcalc.ParseRequiredTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := p.dbClient.Rooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taken, covers, err := p.roomOccupancies(ctx, rooms)
+	if err != nil {
+		return nil, err
+	}
+	return roomcalc.FreeRooms(rooms, from, until, seats, required, taken, covers), nil
+}
+
+// ReserveFreeRoom reserves a free room for [from, until) with a time-range block; a
+// building-management room not covered yet also gets a room request.
+func (p *Plexams) ReserveFreeRoom(ctx context.Context, room string, from, until time.Time, reason *string) (*model.FreeRoomReservation, error) {
+	if !until.After(from) {
+		return nil, fmt.Errorf("until must be after from")
+	}
+	rooms, err := p.dbClient.Rooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var dbRoom *model.Room
+	for _, r := range rooms {
+		if r.Name == room {
+			dbRoom = r
+		}
+	}
+	if dbRoom == nil {
+		return nil, fmt.Errorf("room %s not found", room)
+	}
+	taken, covers, err := p.roomOccupancies(ctx, rooms)
+	if err != nil {
+		return nil, err
+	}
+	free := roomcalc.FreeRooms([]*model.Room{dbRoom}, from, until, 0, nil, taken, covers)
+	if len(free) == 0 {
+		if dbRoom.Deactivated {
+			return nil, fmt.Errorf("room %s is deactivated", room)
+		}
+		reasons := make([]string, 0)
+		for _, c := range roomcalc.Conflicts(room, taken, from, until) {
+			reasons = append(reasons, c.Reason)
+		}
+		return nil, fmt.Errorf("room %s is not free from %s to %s: %s",
+			room, from.Format("02.01. 15:04"), until.Format("15:04"), strings.Join(reasons, ", "))
+	}
+
+	reservation := &model.FreeRoomReservation{}
+	if free[0].Status == model.FreeRoomStatusRequestable {
+		if dbRoom.RequestWith == model.RoomRequestTypeAnny {
+			return nil, fmt.Errorf("room %s is not booked in Anny from %s to %s; book it there and re-import the bookings",
+				room, from.Format("02.01. 15:04"), until.Format("15:04"))
+		}
+		if reservation.Request, err = p.AddRoomRequest(ctx, room, from, from, until); err != nil {
+			return nil, err
+		}
+	}
+	block := &model.BlockedRoom{Starttime: &from, Until: &until, Room: room, Reason: reason}
+	if err := p.dbClient.BlockRoomForSlot(ctx, block); err != nil {
+		return nil, err
+	}
+	reservation.Block = block
+	return reservation, nil
+}
+
+// roomOccupancies collects when the rooms are taken — planned exams incl. their
+// Vor-/Nachlauf, room blocks and Anny bookings of others — and when requested rooms
+// are covered by an approved room request or one of our Anny bookings.
+func (p *Plexams) roomOccupancies(ctx context.Context, rooms []*model.Room) (taken, covers []roomcalc.Occupancy, err error) {
+	planned, err := p.dbClient.PlannedRooms(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	constraints, err := p.ConstraintsMap(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, pr := range planned {
+		if pr.Starttime == nil {
+			continue
+		}
+		pre, post := occupancyBuffers(constraints[pr.Ancode])
+		taken = append(taken, roomcalc.Occupancy{
+			Room:   pr.RoomName,
+			From:   pr.Starttime.Add(-pre),
+			Until:  pr.Starttime.Add(time.Duration(pr.Duration)*time.Minute + post),
+			Reason: fmt.Sprintf("Prüfung %d um %s", pr.Ancode, pr.Starttime.Format("02.01. 15:04")),
+		})
+	}
+
+	blocks, err := p.dbClient.BlockedRooms(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	slotBlock := slotBlockDuration(p.semesterConfig.Starttimes)
+	for _, b := range blocks {
+		if b.Starttime == nil {
+			continue
+		}
+		until := b.Starttime.Add(slotBlock)
+		if b.Until != nil {
+			until = *b.Until
+		}
+		reason := "gesperrt ab " + b.Starttime.Format("02.01. 15:04")
+		if b.Reason != nil && *b.Reason != "" {
+			reason += " (" + *b.Reason + ")"
+		}
+		taken = append(taken, roomcalc.Occupancy{Room: b.Room, From: *b.Starttime, Until: until, Reason: reason})
+	}
+
+	requests, err := p.dbClient.RoomRequests(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, r := range requests {
+		if r.Approved && r.Active {
+			covers = append(covers, roomcalc.Occupancy{Room: r.Room, From: r.From, Until: r.Until,
+				Reason: fmt.Sprintf("Raumanfrage %s–%s", r.From.Format("02.01. 15:04"), r.Until.Format("15:04"))})
+		}
+	}
+
+	bookings, err := p.dbClient.AllAnnyBookings(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := p.anny.PersonalizationNames(ctx)
+	roomName := make(map[string]string, len(rooms))
+	for _, r := range rooms {
+		roomName[preplancalc.NormRoomName(r.Name)] = r.Name
+	}
+	for _, b := range bookings {
+		room, ok := roomName[preplancalc.NormRoomName(b.Room)]
+		if b.Room == "" || !ok || b.CanceledAt != nil {
+			continue
+		}
+		span := fmt.Sprintf("%s–%s", b.StartDate.Format("02.01. 15:04"), b.EndDate.Format("15:04"))
+		switch {
+		case !anny.MatchesAnyPersonalization(b.PersonalizationName, names):
+			taken = append(taken, roomcalc.Occupancy{Room: room, From: b.StartDate, Until: b.EndDate, Reason: "fremde Anny-Buchung " + span})
+		case anny.IsApprovedStatus(b.Status) && !b.IsBlocker:
+			covers = append(covers, roomcalc.Occupancy{Room: room, From: b.StartDate, Until: b.EndDate, Reason: "Anny-Buchung " + span})
+		}
+	}
+	return taken, covers, nil
+}
@@ -0,0 +1,23 @@
+package plexams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+func TestBlockedSlots(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2026, 7, 20, h, m, 0, 0, time.Local) }
+	slots := map[time.Time]bool{at(8, 30): true, at(10, 30): true, at(12, 30): true}
+	from, until := at(10, 0), at(11, 0)
+	got := blockedSlots(&model.BlockedRoom{Room: "A", Starttime: &from, Until: &until}, slots, 2*time.Hour)
+	if len(got) != 2 {
+		t.Fatalf("got %v, want the 08:30 and 10:30 slots", got)
+	}
+	for _, s := range got {
+		if s.Equal(at(12, 30)) {
+			t.Errorf("12:30 slot must not be blocked")
+		}
+	}
+}
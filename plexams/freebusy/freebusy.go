@@ -0,0 +1,363 @@
+// Package freebusy turns a teacher's calendar export (ICS with events, e.g. from
+// Exchange/Outlook, or a VFREEBUSY export) into busy blocks and those into invigilator
+// unavailability on the exam days: whole excluded days and unavailable time windows.
+// It is I/O-free; loading and storing the invigilator constraints stays in the plexams
+// package.
+//
+// Only the busy time is used: summaries, locations and attendees of the events are
+// never read, so a full calendar can be uploaded without exposing its content.
+package freebusy
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	ical "github.com/arran4/golang-ical"
+)
+
+// Busy is one busy interval [Start, End).
+type Busy struct {
+	Start time.Time
+	End   time.Time
+}
+
+// maxOccurrences bounds the expansion of one recurring event.
+const maxOccurrences = 5000
+
+// Parse reads an ICS file and returns its busy intervals overlapping [from, until).
+// Events marked free (TRANSP:TRANSPARENT, X-MICROSOFT-CDO-BUSYSTATUS:FREE), cancelled
+// events and FREEBUSY periods of type FREE are ignored; tentative ones count as busy.
+// Times without a usable TZID are read in loc (Outlook writes Windows zone names like
+// "W. Europe Standard Time"). Daily and weekly recurrences are expanded; other
+// recurrences only contribute their first occurrence and are reported as warnings.
+// Unreadable events are returned as problems instead of failing the whole file.
+func Parse(data []byte, loc *time.Location, from, until time.Time) (busy []Busy, warnings, problems []string, err error) {
+	cal, err := ical.ParseCalendar(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot read calendar: %w", err)
+	}
+	warnings, problems = make([]string, 0), make([]string, 0)
+	add := func(b Busy) {
+		if b.End.After(from) && b.Start.Before(until) && b.End.After(b.Start) {
+			busy = append(busy, b)
+		}
+	}
+
+	events := cal.Events()
+	// RECURRENCE-ID overrides replace single occurrences of a recurring event; the
+	// override itself is read as an ordinary event.
+	overridden := make(map[string]bool)
+	for _, e := range events {
+		if p := e.GetProperty(ical.ComponentPropertyRecurrenceId); p != nil {
+			if t, _, err := parseTime(p, loc); err == nil {
+				overridden[e.Id()+"@"+t.UTC().Format(time.RFC3339)] = true
+			}
+		}
+	}
+
+	for i, e := range events {
+		if !eventIsBusy(&e.ComponentBase) {
+			continue
+		}
+		start, allDay, err := timeProp(&e.ComponentBase, ical.ComponentPropertyDtStart, loc)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("event %d: %v", i+1, err))
+			continue
+		}
+		end, err := eventEnd(&e.ComponentBase, start, allDay, loc)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("event %d: %v", i+1, err))
+			continue
+		}
+		length := end.Sub(start)
+
+		rruleProp := e.GetProperty(ical.ComponentPropertyRrule)
+		if rruleProp == nil || e.HasProperty(ical.ComponentPropertyRecurrenceId) {
+			add(Busy{Start: start, End: end})
+			continue
+		}
+		rule, err := parseRRule(rruleProp.Value, loc)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("event %d: %v, only the first occurrence is used", i+1, err))
+			add(Busy{Start: start, End: end})
+			continue
+		}
+		excluded := make(map[string]bool)
+		for _, p := range e.GetProperties(ical.ComponentPropertyExdate) {
+			for _, v := range strings.Split(p.Value, ",") {
+				q := *p
+				q.Value = v
+				if t, _, err := parseTime(&q, loc); err == nil {
+					excluded[t.UTC().Format(time.RFC3339)] = true
+				}
+			}
+		}
+		for _, occ := range rule.occurrences(start, until) {
+			k := occ.UTC().Format(time.RFC3339)
+			if excluded[k] || overridden[e.Id()+"@"+k] {
+				continue
+			}
+			add(Busy{Start: occ, End: occ.Add(length)})
+		}
+	}
+
+	for i, fb := range cal.Busys() {
+		for _, p := range fb.GetProperties(ical.ComponentPropertyFreebusy) {
+			if t := p.ICalParameters["FBTYPE"]; len(t) > 0 && strings.EqualFold(t[0], "FREE") {
+				continue
+			}
+			for _, period := range strings.Split(p.Value, ",") {
+				b, err := parsePeriod(period, loc)
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("free/busy %d: %v", i+1, err))
+					continue
+				}
+				add(b)
+			}
+		}
+	}
+
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+	return busy, warnings, problems, nil
+}
+
+func eventIsBusy(e *ical.ComponentBase) bool {
+	if p := e.GetProperty(ical.ComponentPropertyTransp); p != nil && strings.EqualFold(p.Value, "TRANSPARENT") {
+		return false
+	}
+	if p := e.GetProperty(ical.ComponentPropertyStatus); p != nil && strings.EqualFold(p.Value, "CANCELLED") {
+		return false
+	}
+	if p := e.GetProperty(ical.ComponentProperty("X-MICROSOFT-CDO-BUSYSTATUS")); p != nil && strings.EqualFold(p.Value, "FREE") {
+		return false
+	}
+	return true
+}
+
+func timeProp(e *ical.ComponentBase, prop ical.ComponentProperty, loc *time.Location) (time.Time, bool, error) {
+	p := e.GetProperty(prop)
+	if p == nil {
+		return time.Time{}, false, fmt.Errorf("missing %s", prop)
+	}
+	return parseTime(p, loc)
+}
+
+// eventEnd is DTEND, DTSTART + DURATION or, without both, the end of the day for an
+// all-day event and the start otherwise (RFC 5545, 3.6.1).
+func eventEnd(e *ical.ComponentBase, start time.Time, allDay bool, loc *time.Location) (time.Time, error) {
+	if e.HasProperty(ical.ComponentPropertyDtEnd) {
+		end, _, err := timeProp(e, ical.ComponentPropertyDtEnd, loc)
+		return end, err
+	}
+	if p := e.GetProperty(ical.ComponentProperty(ical.PropertyDuration)); p != nil {
+		d, err := parseDuration(p.Value)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return start.Add(d), nil
+	}
+	if allDay {
+		return start.AddDate(0, 0, 1), nil
+	}
+	return start, nil
+}
+
+// parseTime reads a DATE or DATE-TIME value; allDay is true for a DATE.
+func parseTime(p *ical.IANAProperty, loc *time.Location) (t time.Time, allDay bool, err error) {
+	v := strings.TrimSpace(p.Value)
+	switch {
+	case len(v) == 8:
+		t, err = time.ParseInLocation("20060102", v, loc)
+		allDay = true
+	case strings.HasSuffix(v, "Z"):
+		t, err = time.Parse("20060102T150405Z", v)
+	default:
+		l := loc
+		if tz := p.ICalParameters["TZID"]; len(tz) > 0 {
+			if zone, zerr := time.LoadLocation(strings.Trim(tz[0], `"`)); zerr == nil {
+				l = zone
+			}
+		}
+		t, err = time.ParseInLocation("20060102T150405", v, l)
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("cannot read time %q", v)
+	}
+	return t, allDay, nil
+}
+
+// parsePeriod reads a FREEBUSY period: start/end or start/duration.
+func parsePeriod(s string, loc *time.Location) (Busy, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "/", 2)
+	if len(parts) != 2 {
+		return Busy{}, fmt.Errorf("cannot read period %q", s)
+	}
+	start, _, err := parseTime(&ical.IANAProperty{BaseProperty: ical.BaseProperty{Value: parts[0]}}, loc)
+	if err != nil {
+		return Busy{}, err
+	}
+	if strings.HasPrefix(parts[1], "P") {
+		d, err := parseDuration(parts[1])
+		if err != nil {
+			return Busy{}, err
+		}
+		return Busy{Start: start, End: start.Add(d)}, nil
+	}
+	end, _, err := parseTime(&ical.IANAProperty{BaseProperty: ical.BaseProperty{Value: parts[1]}}, loc)
+	if err != nil {
+		return Busy{}, err
+	}
+	return Busy{Start: start, End: end}, nil
+}
+
+// parseDuration reads an RFC 5545 duration like PT1H30M, P1D or P2W (no sign).
+func parseDuration(s string) (time.Duration, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(s), "+")
+	if !strings.HasPrefix(v, "P") {
+		return 0, fmt.Errorf("cannot read duration %q", s)
+	}
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, r := range v[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+		case r == 'T':
+			inTime = true
+		default:
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("cannot read duration %q", s)
+			}
+			num = ""
+			switch {
+			case r == 'W':
+				d += time.Duration(n) * 7 * 24 * time.Hour
+			case r == 'D':
+				d += time.Duration(n) * 24 * time.Hour
+			case r == 'H' && inTime:
+				d += time.Duration(n) * time.Hour
+			case r == 'M' && inTime:
+				d += time.Duration(n) * time.Minute
+			case r == 'S' && inTime:
+				d += time.Duration(n) * time.Second
+			default:
+				return 0, fmt.Errorf("cannot read duration %q", s)
+			}
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("cannot read duration %q", s)
+	}
+	return d, nil
+}
+
+// rrule is the supported subset of a recurrence rule: DAILY or WEEKLY with INTERVAL,
+// COUNT, UNTIL and (weekly) BYDAY.
+type rrule struct {
+	freq     string
+	interval int
+	count    int
+	until    time.Time
+	byDay    []time.Weekday
+}
+
+var icalWeekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+func parseRRule(s string, loc *time.Location) (rrule, error) {
+	r := rrule{interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, val := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+		switch key {
+		case "FREQ":
+			r.freq = val
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return r, fmt.Errorf("recurrence with INTERVAL=%s not supported", val)
+			}
+			r.interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return r, fmt.Errorf("recurrence with COUNT=%s not supported", val)
+			}
+			r.count = n
+		case "UNTIL":
+			t, _, err := parseTime(&ical.IANAProperty{BaseProperty: ical.BaseProperty{Value: val}}, loc)
+			if err != nil {
+				return r, fmt.Errorf("recurrence with UNTIL=%s not supported", val)
+			}
+			r.until = t
+		case "BYDAY":
+			for _, d := range strings.Split(val, ",") {
+				wd, ok := icalWeekdays[d]
+				if !ok {
+					return r, fmt.Errorf("recurrence with BYDAY=%s not supported", val)
+				}
+				r.byDay = append(r.byDay, wd)
+			}
+		case "WKST":
+		default:
+			return r, fmt.Errorf("recurrence with %s not supported", key)
+		}
+	}
+	if r.freq != "DAILY" && r.freq != "WEEKLY" {
+		return r, fmt.Errorf("recurrence FREQ=%s not supported", r.freq)
+	}
+	return r, nil
+}
+
+// occurrences returns the start times of the recurrence (the first is start itself)
+// before limit.
+func (r rrule) occurrences(start, limit time.Time) []time.Time {
+	out := make([]time.Time, 0)
+	n := 0
+	emit := func(t time.Time) bool {
+		if (!r.until.IsZero() && t.After(r.until)) || !t.Before(limit) || (r.count > 0 && n >= r.count) || n >= maxOccurrences {
+			return false
+		}
+		n++
+		out = append(out, t)
+		return true
+	}
+
+	if r.freq == "DAILY" {
+		for i := 0; emit(start.AddDate(0, 0, i*r.interval)); i++ {
+		}
+		return out
+	}
+
+	days := r.byDay
+	if len(days) == 0 {
+		days = []time.Weekday{start.Weekday()}
+	}
+	// weeks start on Monday (WKST default)
+	offset := (int(start.Weekday()) + 6) % 7
+	weekStart := start.AddDate(0, 0, -offset)
+	sorted := append([]time.Weekday{}, days...)
+	sort.Slice(sorted, func(i, j int) bool { return (int(sorted[i])+6)%7 < (int(sorted[j])+6)%7 })
+	for week := 0; ; week += r.interval {
+		for _, wd := range sorted {
+			t := weekStart.AddDate(0, 0, week*7+(int(wd)+6)%7)
+			if t.Before(start) {
+				continue
+			}
+			if !emit(t) {
+				return out
+			}
+		}
+	}
+}
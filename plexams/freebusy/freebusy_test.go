@@ -0,0 +1,148 @@
+package freebusy
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+var berlin, _ = time.LoadLocation("Europe/Berlin")
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, time.July, day, hour, minute, 0, 0, berlin)
+}
+
+const outlookICS = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:Microsoft Exchange Server 2010
+BEGIN:VEVENT
+UID:weekly
+SUMMARY:Fakultätsrat
+DTSTART;TZID=W. Europe Standard Time:20260630T090000
+DTEND;TZID=W. Europe Standard Time:20260630T113000
+RRULE:FREQ=WEEKLY;COUNT=3;BYDAY=TU
+EXDATE;TZID=W. Europe Standard Time:20260707T090000
+END:VEVENT
+BEGIN:VEVENT
+UID:conference
+SUMMARY:Konferenz
+DTSTART;VALUE=DATE:20260709
+DTEND;VALUE=DATE:20260710
+END:VEVENT
+BEGIN:VEVENT
+UID:free
+DTSTART:20260708T080000Z
+DTEND:20260708T100000Z
+TRANSP:TRANSPARENT
+END:VEVENT
+BEGIN:VEVENT
+UID:afternoon
+DTSTART:20260708T120000Z
+DURATION:PT2H
+END:VEVENT
+BEGIN:VEVENT
+UID:monthly
+DTSTART:20260713T060000Z
+DTEND:20260713T070000Z
+RRULE:FREQ=MONTHLY
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestParse(t *testing.T) {
+	ics := strings.ReplaceAll(outlookICS, "\n", "\r\n")
+	busy, warnings, problems, err := Parse([]byte(ics), berlin, at(6, 0, 0), at(18, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("unexpected problems %v", problems)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("want a warning for the monthly recurrence, got %v", warnings)
+	}
+	// 30.06. lies before the period, 07.07. is excluded: only 14.07. of the series
+	want := []Busy{
+		{Start: at(8, 14, 0), End: at(8, 16, 0)},
+		{Start: at(9, 0, 0), End: at(10, 0, 0)},
+		{Start: at(13, 8, 0), End: at(13, 9, 0)},
+		{Start: at(14, 9, 0), End: at(14, 11, 30)},
+	}
+	if len(busy) != len(want) {
+		t.Fatalf("got %d busy intervals, want %d: %v", len(busy), len(want), busy)
+	}
+	for i := range want {
+		if !busy[i].Start.Equal(want[i].Start) || !busy[i].End.Equal(want[i].End) {
+			t.Errorf("busy[%d] = %v-%v, want %v-%v", i, busy[i].Start, busy[i].End, want[i].Start, want[i].End)
+		}
+	}
+}
+
+func TestParseFreeBusy(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VFREEBUSY\r\n" +
+		"FREEBUSY;FBTYPE=BUSY:20260707T070000Z/20260707T090000Z,20260708T120000Z/PT1H\r\n" +
+		"FREEBUSY;FBTYPE=FREE:20260709T070000Z/20260709T090000Z\r\n" +
+		"END:VFREEBUSY\r\nEND:VCALENDAR\r\n"
+	busy, _, problems, err := Parse([]byte(ics), berlin, at(6, 0, 0), at(18, 0, 0))
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("err %v, problems %v", err, problems)
+	}
+	if len(busy) != 2 || !busy[0].Start.Equal(at(7, 9, 0)) || !busy[1].End.Equal(at(8, 15, 0)) {
+		t.Errorf("unexpected busy intervals %v", busy)
+	}
+}
+
+func TestBlocksAndMerge(t *testing.T) {
+	days := []time.Time{at(7, 0, 0), at(8, 0, 0), at(9, 0, 0)}
+	busy := []Busy{
+		{Start: at(6, 18, 0), End: at(7, 9, 0)},   // runs into the morning of 07.07.
+		{Start: at(7, 14, 0), End: at(7, 15, 0)},  // touching the next one
+		{Start: at(7, 15, 0), End: at(7, 16, 30)}, // -> 14:00-16:30
+		{Start: at(9, 0, 0), End: at(10, 0, 0)},   // whole 09.07.
+		{Start: at(20, 8, 0), End: at(20, 9, 0)},  // not an exam day
+	}
+	blocks := Blocks(busy, days, berlin)
+	if len(blocks) != 3 {
+		t.Fatalf("got %d blocks, want 3: %v", len(blocks), blocks)
+	}
+	if blocks[0].From != nil || !blocks[0].Until.Equal(at(7, 9, 0)) {
+		t.Errorf("morning block = %+v", blocks[0])
+	}
+	if !blocks[1].From.Equal(at(7, 14, 0)) || !blocks[1].Until.Equal(at(7, 16, 30)) {
+		t.Errorf("afternoon block = %+v", blocks[1])
+	}
+	if !blocks[2].WholeDay() {
+		t.Errorf("09.07. must be a whole day, got %+v", blocks[2])
+	}
+
+	from, until := at(8, 10, 0), at(8, 12, 0)
+	existing := &model.InvigilatorConstraints{
+		TeacherID:     42,
+		ExcludedDates: []time.Time{at(9, 0, 0)},
+		TimeWindows: []*model.InvigilationTimeWindow{
+			{Date: at(8, 0, 0), From: &from, Until: &until, Unavailable: true},
+			{Date: at(8, 0, 0), From: &from}, // available window, never touched
+		},
+	}
+
+	merged, result := Merge(existing, blocks, false, berlin)
+	if len(result.Added) != 2 || len(result.Removed) != 0 || result.Unchanged != 1 {
+		t.Errorf("add-only diff = %s", result.Summary())
+	}
+	if len(merged.ExcludedDates) != 1 || len(merged.TimeWindows) != 4 {
+		t.Errorf("add-only merge: %d dates, %d windows", len(merged.ExcludedDates), len(merged.TimeWindows))
+	}
+
+	merged, result = Merge(existing, blocks, true, berlin)
+	if len(result.Added) != 2 || len(result.Removed) != 1 || result.Unchanged != 1 {
+		t.Errorf("replace diff = %s", result.Summary())
+	}
+	if len(merged.TimeWindows) != 3 || merged.TimeWindows[0].Unavailable {
+		t.Errorf("replace must drop the old unavailable window and keep the available one: %+v", merged.TimeWindows)
+	}
+	if len(existing.TimeWindows) != 2 {
+		t.Error("merge must not modify the stored constraints")
+	}
+}
@@ -0,0 +1,182 @@
+package freebusy
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// Block is the unavailability on one exam day: the whole day (From and Until nil) or
+// From–Until, with nil for a busy time running over the start or end of the day.
+type Block struct {
+	Date  time.Time  `json:"date"`
+	From  *time.Time `json:"from,omitempty"`
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// WholeDay reports whether the block covers the whole day.
+func (b Block) WholeDay() bool { return b.From == nil && b.Until == nil }
+
+func (b Block) key(loc *time.Location) string {
+	k := b.Date.In(loc).Format("2006-01-02")
+	if b.From != nil {
+		k += " " + b.From.In(loc).Format("15:04")
+	} else {
+		k += " -"
+	}
+	if b.Until != nil {
+		k += " " + b.Until.In(loc).Format("15:04")
+	} else {
+		k += " -"
+	}
+	return k
+}
+
+// Blocks clips the busy intervals to the exam days and merges overlapping or touching
+// ones, one block per resulting interval. Busy time on other days is dropped.
+func Blocks(busy []Busy, days []time.Time, loc *time.Location) []Block {
+	out := make([]Block, 0)
+	for _, day := range days {
+		y, m, d := day.In(loc).Date()
+		dayStart := time.Date(y, m, d, 0, 0, 0, 0, loc)
+		dayEnd := dayStart.AddDate(0, 0, 1)
+
+		clipped := make([]Busy, 0)
+		for _, b := range busy {
+			if !b.End.After(dayStart) || !b.Start.Before(dayEnd) {
+				continue
+			}
+			c := b
+			if c.Start.Before(dayStart) {
+				c.Start = dayStart
+			}
+			if c.End.After(dayEnd) {
+				c.End = dayEnd
+			}
+			clipped = append(clipped, c)
+		}
+		sort.Slice(clipped, func(i, j int) bool { return clipped[i].Start.Before(clipped[j].Start) })
+
+		merged := make([]Busy, 0, len(clipped))
+		for _, c := range clipped {
+			if n := len(merged); n > 0 && !c.Start.After(merged[n-1].End) {
+				if c.End.After(merged[n-1].End) {
+					merged[n-1].End = c.End
+				}
+				continue
+			}
+			merged = append(merged, c)
+		}
+
+		for _, mb := range merged {
+			block := Block{Date: dayStart}
+			if mb.Start.After(dayStart) {
+				from := mb.Start.In(loc)
+				block.From = &from
+			}
+			if mb.End.Before(dayEnd) {
+				until := mb.End.In(loc)
+				block.Until = &until
+			}
+			out = append(out, block)
+		}
+	}
+	return out
+}
+
+// Import is the preview (and, once applied, the outcome) of an availability import for
+// one teacher.
+type Import struct {
+	TeacherID int  `json:"teacherID"`
+	Applied   bool `json:"applied"`
+	// Replace: the existing excluded days and unavailable windows not in the file are
+	// removed; otherwise the file only adds.
+	Replace   bool    `json:"replace"`
+	Busy      int     `json:"busy"` // busy intervals of the file in the exam period
+	Added     []Block `json:"added"`
+	Removed   []Block `json:"removed"`
+	Unchanged int     `json:"unchanged"`
+	// Warnings are events read only partly (e.g. an unsupported recurrence).
+	Warnings []string `json:"warnings"`
+	// Problems are unreadable events; an import with problems is not applied.
+	Problems []string `json:"problems"`
+}
+
+// Merge applies the blocks to a copy of the constraints: whole-day blocks become
+// excluded dates, the others unavailable time windows. Available and weekly windows and
+// isNotInvigilator are never touched. It returns the merged constraints and the diff.
+func Merge(c *model.InvigilatorConstraints, blocks []Block, replace bool, loc *time.Location) (*model.InvigilatorConstraints, *Import) {
+	result := &Import{
+		TeacherID: c.TeacherID,
+		Replace:   replace,
+		Added:     make([]Block, 0),
+		Removed:   make([]Block, 0),
+		Warnings:  make([]string, 0),
+		Problems:  make([]string, 0),
+	}
+	merged := &model.InvigilatorConstraints{
+		TeacherID:         c.TeacherID,
+		IsNotInvigilator:  c.IsNotInvigilator,
+		ExcludedDates:     make([]time.Time, 0, len(c.ExcludedDates)),
+		TimeWindows:       make([]*model.InvigilationTimeWindow, 0, len(c.TimeWindows)),
+		WeeklyTimeWindows: c.WeeklyTimeWindows,
+	}
+
+	wanted := make(map[string]bool, len(blocks))
+	for _, b := range blocks {
+		wanted[b.key(loc)] = true
+	}
+	existing := make(map[string]bool)
+	keep := func(b Block) bool {
+		k := b.key(loc)
+		existing[k] = true
+		if wanted[k] {
+			result.Unchanged++
+			return true
+		}
+		if replace {
+			result.Removed = append(result.Removed, b)
+			return false
+		}
+		return true
+	}
+
+	for _, d := range c.ExcludedDates {
+		if keep(Block{Date: d}) {
+			merged.ExcludedDates = append(merged.ExcludedDates, d)
+		}
+	}
+	for _, w := range c.TimeWindows {
+		if w == nil {
+			continue
+		}
+		if w.Unavailable && !keep(Block{Date: w.Date, From: w.From, Until: w.Until}) {
+			continue
+		}
+		merged.TimeWindows = append(merged.TimeWindows, w)
+	}
+
+	for _, b := range blocks {
+		k := b.key(loc)
+		if existing[k] {
+			continue
+		}
+		existing[k] = true
+		result.Added = append(result.Added, b)
+		if b.WholeDay() {
+			merged.ExcludedDates = append(merged.ExcludedDates, b.Date)
+		} else {
+			merged.TimeWindows = append(merged.TimeWindows, &model.InvigilationTimeWindow{
+				Date: b.Date, From: b.From, Until: b.Until, Unavailable: true,
+			})
+		}
+	}
+	return merged, result
+}
+
+// Summary is a one-line description of the diff for the mutation log.
+func (i *Import) Summary() string {
+	return fmt.Sprintf("%d added, %d removed, %d unchanged", len(i.Added), len(i.Removed), i.Unchanged)
+}
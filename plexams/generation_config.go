@@ -2,9 +2,11 @@ package plexams
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/obcode/plexams.go/graph/model"
 	"github.com/obcode/plexams.go/plexams/examplan"
+	"github.com/obcode/plexams.go/plexams/invigcalc"
 	"github.com/obcode/plexams.go/plexams/invigplan"
 	"github.com/obcode/plexams.go/plexams/roomplan"
 	"github.com/spf13/viper"
@@ -14,14 +16,26 @@ import (
 // falls back to the package defaults, seeded from the config file (viper) for
 // backwards compatibility.
 func (p *Plexams) GenerationConfig(ctx context.Context) (*model.GenerationConfig, error) {
-	cfg, err := p.dbClient.GetGenerationConfig(ctx)
+	cfg, stored, err := p.dbClient.GetGenerationConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
 	if cfg != nil {
-		fillSlotTimeDefaults(cfg)   // backfill fields absent from an older stored config
-		fillExamWeightDefaults(cfg) // backfill the examplan/preplan solver weights
-		fillRoomWeightDefaults(cfg) // backfill the roomplan solver weights + heat mode
+		fillSlotTimeDefaults(cfg)           // backfill fields absent from an older stored config
+		fillExamWeightDefaults(cfg, stored) // backfill the examplan/preplan solver weights
+		fillRoomWeightDefaults(cfg)         // backfill the roomplan solver weights + heat mode
+		fillRoomSizingDefaults(cfg)         // backfill the room sizing mode
+		if cfg.SoftRules == nil {
+			cfg.SoftRules = []*model.SoftRule{}
+		}
+		for _, r := range cfg.SoftRules {
+			if r != nil {
+				r.Target = softRuleTarget(r) // rules stored before they had a target
+			}
+		}
+		if cfg.StaffingRules == nil {
+			cfg.StaffingRules = []*model.StaffingRule{}
+		}
 		return cfg, nil
 	}
 	return defaultGenerationConfig(), nil
@@ -42,16 +56,31 @@ func fillRoomWeightDefaults(cfg *model.GenerationConfig) {
 		cfg.RoomChurn = w.Churn
 		cfg.RoomHeatBaselineHour = w.HeatBaselineHour
 	}
+	if cfg.RoomSplitBuilding == 0 {
+		cfg.RoomSplitBuilding = roomplan.DefaultWeights().SplitBuilding
+	}
 	if cfg.RoomHeatMode == "" {
 		cfg.RoomHeatMode = model.RoomHeatConstraintModeAuto
 	}
 }
 
+// fillRoomSizingDefaults seeds the room sizing for a stored config that predates it: an
+// empty mode means margin and semesters are missing as well.
+func fillRoomSizingDefaults(cfg *model.GenerationConfig) {
+	if cfg.RoomSizing == "" {
+		cfg.RoomSizing = model.RoomSizingModeRegistered
+		cfg.RoomSizingMargin = defaultRoomSizingMargin
+		cfg.RoomSizingSemesters = defaultRoomSizingSemesters
+	}
+}
+
 // fillExamWeightDefaults seeds the Terminplan (examplan) solver weights and the pre-plan
 // capacity factor for a stored config that predates them (all zero → treated as "unset").
 // ExamAdjacent is the sentinel: its tuned default is far from 0, so a stored 0 means the
 // whole exam-weight group is missing and gets backfilled from the tuned defaults.
-func fillExamWeightDefaults(cfg *model.GenerationConfig) {
+// Weights added later, where 0 means "off", are backfilled only when their key is missing
+// from the stored document (stored).
+func fillExamWeightDefaults(cfg *model.GenerationConfig, stored map[string]bool) {
 	if cfg.ExamAdjacent == 0 {
 		w := examplan.DefaultWeights()
 		cfg.ExamAdjacent = w.Adjacent
@@ -68,6 +97,12 @@ func fillExamWeightDefaults(cfg *model.GenerationConfig) {
 		cfg.ExamHole = w.Hole
 		cfg.ExamClosenessFalloffMin = w.ClosenessFalloffMin
 	}
+	if !stored["examequity"] {
+		cfg.ExamEquity = examplan.DefaultWeights().Equity
+	}
+	if !stored["examcurriculum"] {
+		cfg.ExamCurriculum = examplan.DefaultWeights().Curriculum
+	}
 	if cfg.PreplanCapacityFactor == 0 {
 		cfg.PreplanCapacityFactor = preplanCapacityFactor
 	}
@@ -93,6 +128,8 @@ func examScheduleWeights(cfg *model.GenerationConfig) examplan.Weights {
 	w.TbauFill = cfg.ExamTbauFill
 	w.Hole = cfg.ExamHole
 	w.ClosenessFalloffMin = cfg.ExamClosenessFalloffMin
+	w.Equity = cfg.ExamEquity
+	w.Curriculum = cfg.ExamCurriculum
 	return w
 }
 
@@ -120,8 +157,31 @@ func fillSlotTimeDefaults(cfg *model.GenerationConfig) {
 	}
 }
 
-// SetGenerationConfig stores the global generation config.
+// SetGenerationConfig stores the global generation config. The user-defined soft rules
+// are compiled first, so a rule with a syntax or type error is rejected on save instead of
+// being silently skipped by the next generation; the staffing rules are checked likewise.
 func (p *Plexams) SetGenerationConfig(ctx context.Context, cfg *model.GenerationConfig) (*model.GenerationConfig, error) {
+	if cfg.SoftRules == nil {
+		cfg.SoftRules = []*model.SoftRule{}
+	}
+	if cfg.StaffingRules == nil {
+		cfg.StaffingRules = []*model.StaffingRule{}
+	}
+	if _, err := compileSoftRules(cfg.SoftRules); err != nil {
+		return nil, err
+	}
+	if err := invigcalc.CheckStaffingRules(cfg.StaffingRules); err != nil {
+		return nil, err
+	}
+	if cfg.SwapMaxMinutesDelta < 0 {
+		return nil, fmt.Errorf("swapMaxMinutesDelta must not be negative, got %d", cfg.SwapMaxMinutesDelta)
+	}
+	if cfg.RoomSizingMargin < 0 || cfg.RoomSizingMargin > 100 {
+		return nil, fmt.Errorf("roomSizingMargin must be between 0 and 100, got %d", cfg.RoomSizingMargin)
+	}
+	if cfg.RoomSizingSemesters < 1 {
+		return nil, fmt.Errorf("roomSizingSemesters must be at least 1, got %d", cfg.RoomSizingSemesters)
+	}
 	if err := p.dbClient.SetGenerationConfig(ctx, cfg); err != nil {
 		return nil, err
 	}
@@ -154,9 +214,13 @@ func defaultGenerationConfig() *model.GenerationConfig {
 		SlotTimeGradientWeight: defaultSlotTimeGradientWeight,
 		SlotTimeWinterEarliest: defaultSlotTimeWinterEarliest,
 		SlotTimeSummerLatest:   defaultSlotTimeSummerLatest,
+		SoftRules:              []*model.SoftRule{},
+		StaffingRules:          []*model.StaffingRule{},
+		SwapMaxMinutesDelta:    defaultSwapMaxMinutesDelta,
 	}
-	fillExamWeightDefaults(cfg) // seed the examplan/preplan solver weights from the tuned defaults
-	fillRoomWeightDefaults(cfg) // seed the roomplan solver weights + heat mode from the defaults
+	fillExamWeightDefaults(cfg, nil) // seed the examplan/preplan solver weights from the tuned defaults
+	fillRoomWeightDefaults(cfg)      // seed the roomplan solver weights + heat mode from the defaults
+	fillRoomSizingDefaults(cfg)      // sized by registrations unless switched on
 
 	// legacy seed from the config file
 	if viper.IsSet("invigilation.optimizer.iterations") {
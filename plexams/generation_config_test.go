@@ -0,0 +1,52 @@
+package plexams
+
+import (
+	"testing"
+
+	"github.com/obcode/plexams.go/db"
+	"github.com/obcode/plexams.go/graph/model"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// loadStoredConfig decodes a stored generation config document the way GenerationConfig
+// does and backfills the exam weights.
+func loadStoredConfig(t *testing.T, doc bson.M) *model.GenerationConfig {
+	t.Helper()
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, stored, err := db.DecodeGenerationConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fillExamWeightDefaults(cfg, stored)
+	return cfg
+}
+
+func TestFillExamWeightDefaultsEquity(t *testing.T) {
+	// stored before the equity weight existed: tuned exam weights, no equity key
+	cfg := loadStoredConfig(t, bson.M{"examadjacent": 2000.0, "examsameday": 80.0})
+	if cfg.ExamAdjacent != 2000 || cfg.ExamEquity != 50 {
+		t.Errorf("old config: adjacent %v, equity %v (want 2000, 50)", cfg.ExamAdjacent, cfg.ExamEquity)
+	}
+	// switched off on purpose
+	cfg = loadStoredConfig(t, bson.M{"examadjacent": 2000.0, "examequity": 0.0})
+	if cfg.ExamEquity != 0 {
+		t.Errorf("stored 0 (off) became %v", cfg.ExamEquity)
+	}
+	if cfg := defaultGenerationConfig(); cfg.ExamEquity != 50 {
+		t.Errorf("default equity %v", cfg.ExamEquity)
+	}
+}
+
+func TestFillExamWeightDefaultsCurriculum(t *testing.T) {
+	cfg := loadStoredConfig(t, bson.M{"examadjacent": 2000.0, "examequity": 50.0})
+	if cfg.ExamCurriculum != 1000 {
+		t.Errorf("old config: curriculum %v (want 1000)", cfg.ExamCurriculum)
+	}
+	cfg = loadStoredConfig(t, bson.M{"examadjacent": 2000.0, "examcurriculum": 0.0})
+	if cfg.ExamCurriculum != 0 {
+		t.Errorf("stored 0 (off) became %v", cfg.ExamCurriculum)
+	}
+}
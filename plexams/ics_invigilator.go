@@ -43,12 +43,20 @@ func (p *Plexams) InvigilatorICS(ctx context.Context, invigilatorID int) ([]byte
 		examsAtCache[key] = plannedByMe
 		return plannedByMe
 	}
-	invigName := func(room string, start time.Time) string {
-		t, err := p.invigilatorAt(ctx, room, start)
-		if err != nil || t == nil {
-			return "—"
+	// invigNames lists the shortnames of all invigilators of a room (several when the
+	// staffing rules ask for it), leaving out exceptID (0: nobody).
+	invigNames := func(room string, start time.Time, exceptID int) []string {
+		teachers, err := p.invigilatorsForRoomAtTime(ctx, room, start)
+		if err != nil {
+			return nil
+		}
+		names := make([]string, 0, len(teachers))
+		for _, t := range teachers {
+			if t != nil && t.ID != exceptID {
+				names = append(names, t.Shortname)
+			}
 		}
-		return t.Shortname
+		return names
 	}
 
 	uid := 0
@@ -68,11 +76,18 @@ func (p *Plexams) InvigilatorICS(ctx context.Context, invigilatorID int) ([]byte
 		var b strings.Builder
 		fmt.Fprintf(&b, "%s (%d min, %d Stud.)", room.RoomName, room.Duration, len(room.StudentsInRoom))
 		if withInvigilator {
-			name := "—"
+			var names []string
 			if room.Starttime != nil {
-				name = invigName(room.RoomName, *room.Starttime)
+				names = invigNames(room.RoomName, *room.Starttime, 0)
+			}
+			switch len(names) {
+			case 0:
+				b.WriteString(", Aufsicht: —")
+			case 1:
+				fmt.Fprintf(&b, ", Aufsicht: %s", names[0])
+			default:
+				fmt.Fprintf(&b, ", Aufsichten: %s", strings.Join(names, ", "))
 			}
-			fmt.Fprintf(&b, ", Aufsicht: %s", name)
 		}
 		if nta := roomNTA(exam, room); nta != nil {
 			alone := ""
@@ -179,6 +194,9 @@ func (p *Plexams) InvigilatorICS(ctx context.Context, invigilatorID int) ([]byte
 
 			summary := fmt.Sprintf("Aufsicht: %s", room)
 			var b strings.Builder
+			if others := invigNames(room, start, invigilatorID); len(others) > 0 {
+				fmt.Fprintf(&b, "Weitere Aufsicht in Ihrem Raum: %s\n", strings.Join(others, ", "))
+			}
 			if ownExam != nil {
 				summary = fmt.Sprintf("Aufsicht: %s — %s", room, examHeader(ownExam))
 				// NTA in this room
@@ -239,6 +257,9 @@ func (p *Plexams) InvigilatorICS(ctx context.Context, invigilatorID int) ([]byte
 					rendered = true
 				}
 			}
+			if others := invigNames(selfRoom, start, invigilatorID); len(others) > 0 {
+				fmt.Fprintf(&b, "\nWeitere Aufsicht in Ihrem Raum: %s", strings.Join(others, ", "))
+			}
 			if !rendered {
 				for _, line := range examRooms(exam, false, "") {
 					b.WriteString("\n" + line)
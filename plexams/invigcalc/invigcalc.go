@@ -2,7 +2,8 @@
 // invigilation minutes to cover and each invigilator's factor and already-credited
 // contributions, it derives the per-invigilator target minutes (factor-weighted, with
 // largest-remainder rounding so the targets sum exactly to the work) and the per-invigilator
-// todo summary. These are pure functions over graph/model types with no I/O, split out of
+// todo summary. It also decides how many invigilators a room needs (staffing rules, see
+// InvigilatorsNeeded). These are pure functions over graph/model types with no I/O, split out of
 // the plexams package so the correctness-critical math is isolated and unit-tested.
 package invigcalc
 
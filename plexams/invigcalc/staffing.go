@@ -0,0 +1,76 @@
+package invigcalc
+
+import (
+	"fmt"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// StaffingRoom describes one room at one exam time as far as the staffing rules need it.
+type StaffingRoom struct {
+	Seats    int // seats of the room (rooms collection)
+	Students int // students planned into the room
+	NTAs     int // NTA students in the room
+	Exahm    bool
+	Seb      bool
+	Lab      bool
+}
+
+// InvigilatorsNeeded returns how many invigilators the room needs: the largest count of
+// all enabled rules whose conditions hold, at least 1.
+func InvigilatorsNeeded(rules []*model.StaffingRule, room StaffingRoom) int {
+	needed := 1
+	for _, rule := range rules {
+		if rule == nil || !rule.Enabled || !staffingRuleMatches(rule, room) {
+			continue
+		}
+		if rule.Invigilators > needed {
+			needed = rule.Invigilators
+		}
+	}
+	return needed
+}
+
+func staffingRuleMatches(rule *model.StaffingRule, room StaffingRoom) bool {
+	if room.Seats < rule.MinSeats || room.Students < rule.MinStudents || room.NTAs < rule.MinNtas {
+		return false
+	}
+	switch rule.ExamType {
+	case model.StaffingExamTypeExahm:
+		return room.Exahm
+	case model.StaffingExamTypeSeb:
+		return room.Seb
+	case model.StaffingExamTypeLab:
+		return room.Lab
+	default:
+		return true
+	}
+}
+
+// CheckStaffingRules rejects rules that cannot be applied sensibly: empty or duplicate
+// names, negative thresholds and fewer than one invigilator.
+func CheckStaffingRules(rules []*model.StaffingRule) error {
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		if rule.Name == "" {
+			return fmt.Errorf("staffing rule without name")
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("duplicate staffing rule %q", rule.Name)
+		}
+		seen[rule.Name] = true
+		if rule.MinSeats < 0 || rule.MinStudents < 0 || rule.MinNtas < 0 {
+			return fmt.Errorf("staffing rule %q: thresholds must not be negative", rule.Name)
+		}
+		if rule.Invigilators < 1 {
+			return fmt.Errorf("staffing rule %q: needs at least one invigilator, got %d", rule.Name, rule.Invigilators)
+		}
+		if !rule.ExamType.IsValid() {
+			return fmt.Errorf("staffing rule %q: unknown exam type %q", rule.Name, rule.ExamType)
+		}
+	}
+	return nil
+}
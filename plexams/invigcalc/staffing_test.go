@@ -0,0 +1,54 @@
+package invigcalc
+
+import (
+	"testing"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+func TestInvigilatorsNeeded(t *testing.T) {
+	rules := []*model.StaffingRule{
+		{Name: "hoersaal", MinSeats: 100, MinStudents: 60, ExamType: model.StaffingExamTypeAny, Invigilators: 2, Enabled: true},
+		{Name: "exahm", MinStudents: 20, ExamType: model.StaffingExamTypeExahm, Invigilators: 2, Enabled: true},
+		{Name: "viele-ntas", MinNtas: 3, ExamType: model.StaffingExamTypeAny, Invigilators: 3, Enabled: true},
+		{Name: "aus", ExamType: model.StaffingExamTypeAny, Invigilators: 5, Enabled: false},
+	}
+	tests := []struct {
+		name string
+		room StaffingRoom
+		want int
+	}{
+		{"small room", StaffingRoom{Seats: 40, Students: 30}, 1},
+		{"large hall, few students", StaffingRoom{Seats: 200, Students: 25}, 1},
+		{"large hall, full", StaffingRoom{Seats: 200, Students: 120}, 2},
+		{"exahm lab", StaffingRoom{Seats: 30, Students: 28, Exahm: true}, 2},
+		{"lab, not exahm", StaffingRoom{Seats: 30, Students: 28, Lab: true}, 1},
+		{"largest count wins", StaffingRoom{Seats: 200, Students: 120, NTAs: 3}, 3},
+	}
+	for _, tt := range tests {
+		if got := InvigilatorsNeeded(rules, tt.room); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+	if got := InvigilatorsNeeded(nil, StaffingRoom{Seats: 500, Students: 400}); got != 1 {
+		t.Errorf("without rules got %d, want 1", got)
+	}
+}
+
+func TestCheckStaffingRules(t *testing.T) {
+	ok := &model.StaffingRule{Name: "a", ExamType: model.StaffingExamTypeAny, Invigilators: 2}
+	if err := CheckStaffingRules([]*model.StaffingRule{ok}); err != nil {
+		t.Errorf("valid rule rejected: %v", err)
+	}
+	for _, bad := range [][]*model.StaffingRule{
+		{ok, ok},
+		{{Name: "", ExamType: model.StaffingExamTypeAny, Invigilators: 2}},
+		{{Name: "b", ExamType: model.StaffingExamTypeAny, Invigilators: 0}},
+		{{Name: "c", ExamType: model.StaffingExamTypeAny, MinSeats: -1, Invigilators: 2}},
+		{{Name: "d", ExamType: "ORAL", Invigilators: 2}},
+	} {
+		if err := CheckStaffingRules(bad); err == nil {
+			t.Errorf("rules %+v must be rejected", bad)
+		}
+	}
+}
@@ -9,12 +9,18 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// GetInvigilatorAt returns the invigilator assigned to a room (or "reserve") at the
-// given absolute start time.
+// GetInvigilatorAt returns the (lead) invigilator assigned to a room (or "reserve") at
+// the given absolute start time.
 func (p *Plexams) GetInvigilatorAt(ctx context.Context, roomname string, starttime time.Time) (*model.Teacher, error) {
 	return p.dbClient.GetInvigilatorAt(ctx, roomname, starttime)
 }
 
+// GetInvigilatorsAt returns all invigilators of a room (or "reserve") at the given
+// absolute start time, the lead invigilator first.
+func (p *Plexams) GetInvigilatorsAt(ctx context.Context, roomname string, starttime time.Time) ([]*model.Teacher, error) {
+	return p.dbClient.GetInvigilatorsAt(ctx, roomname, starttime)
+}
+
 func (p *Plexams) AddInvigilation(ctx context.Context, room string, starttime time.Time, invigilatorID int) error {
 	invigilator, err := p.GetInvigilator(ctx, invigilatorID)
 	if err != nil {
@@ -45,7 +51,7 @@ func (p *Plexams) AddInvigilation(ctx context.Context, room string, starttime ti
 	}
 
 	// add to DB
-	return p.dbClient.AddInvigilationAt(context.Background(), room, starttime, invigilatorID)
+	return p.dbClient.AddInvigilationAt(context.Background(), room, starttime, 0, invigilatorID)
 }
 
 // PreAddInvigilation fixes an invigilator for a room (roomName != nil) or the
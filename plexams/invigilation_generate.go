@@ -3,12 +3,14 @@ package plexams
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"time"
 
 	"github.com/logrusorgru/aurora"
 	"github.com/obcode/plexams.go/db"
 	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/campus"
 	"github.com/obcode/plexams.go/plexams/invigplan"
 	"github.com/rs/zerolog/log"
 )
@@ -37,6 +39,22 @@ func (p *Plexams) AssignInvigilations(ctx context.Context, dryRun bool, opts inv
 	if err := p.generationAllowed(ctx, model.PlanningGateInvigilations); err != nil {
 		return nil, err
 	}
+	problem, err := p.prepareInvigilationProblem(ctx, reporter)
+	if err != nil {
+		return nil, err
+	}
+	run := newInvigilationRun(problem, dryRun, opts)
+	if err := p.dbClient.SaveSolverRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("cannot save solver run: %w", err)
+	}
+	activateSolverRun(run.ID)
+	defer deactivateSolverRun(run.ID)
+	return p.runInvigilationOptimizer(ctx, problem, run, dryRun, opts, reporter)
+}
+
+// prepareInvigilationProblem refreshes the self-invigilations and the todos and
+// builds the planning problem from the DB.
+func (p *Plexams) prepareInvigilationProblem(ctx context.Context, reporter Reporter) (*invigplan.Problem, error) {
 	reporter.Println("refreshing self-invigilations and todos ...")
 	if err := p.PrepareSelfInvigilation(); err != nil {
 		return nil, fmt.Errorf("cannot prepare self invigilations: %w", err)
@@ -44,37 +62,64 @@ func (p *Plexams) AssignInvigilations(ctx context.Context, dryRun bool, opts inv
 	if _, err := p.PrepareInvigilationTodos(ctx); err != nil {
 		return nil, fmt.Errorf("cannot prepare invigilation todos: %w", err)
 	}
+	return p.buildInvigilationProblem(ctx, false)
+}
 
-	problem, err := p.buildInvigilationProblem(ctx, false)
-	if err != nil {
-		return nil, err
+// runInvigilationOptimizer optimizes the problem, checkpointing into run, and (unless
+// dryRun) writes the result. opts.Resume continues a checkpointed run. The caller marks
+// the run active; the run is FINISHED once the plan is written, FAILED on an error.
+func (p *Plexams) runInvigilationOptimizer(ctx context.Context, problem *invigplan.Problem, run *model.SolverRun,
+	dryRun bool, opts invigplan.Options, reporter Reporter,
+) (*model.InvigilationReport, error) {
+	if opts.Resume != nil {
+		reporter.Printf("resuming run %s at iteration %d (up to %d iterations, seed %d) ...\n",
+			run.ID, opts.Resume.Iteration, opts.Iterations, opts.Seed)
+	} else {
+		reporter.Printf("optimizing (up to %d iterations, seed %d) ...\n", opts.Iterations, opts.Seed)
 	}
-
-	reporter.Printf("optimizing (up to %d iterations, seed %d) ...\n", opts.Iterations, opts.Seed)
 	opts.ProgressEvery = max(1, opts.Iterations/200)
 	opts.OnProgress = reporter.Progress
+	opts.CheckpointEvery = max(1, opts.Iterations/solverRunCheckpoints)
+	opts.Checkpoint = func(cp invigplan.Checkpoint) { p.checkpointSolverRun(ctx, run, cp) }
 
-	best, result := invigplan.Optimize(problem, invigplan.DefaultRegistry(), opts)
+	rules, err := p.invigilationSoftRules(ctx, problem)
+	if err != nil {
+		return nil, p.failSolverRun(ctx, run, err)
+	}
+	best, result := invigplan.Optimize(problem, invigplan.DefaultRegistry(rules...), opts)
 	reporter.StopProgress(aurora.Sprintf(aurora.Green("optimization done")))
 
 	report := printInvigilationReport(reporter, problem, best, result, opts)
 
 	if dryRun {
+		run.Status = model.SolverRunStatusFinished
+		p.saveSolverRun(ctx, run)
 		reporter.Println("dry run: nothing written")
 		return report, nil
 	}
 
 	if !p.WritesAllowed() {
-		return report, fmt.Errorf("writes are blocked while a validation is running")
+		return report, p.failSolverRun(ctx, run, fmt.Errorf("writes are blocked while a validation is running"))
 	}
 
 	if result.Unfilled > 0 {
 		reporter.Warnf("writing plan with open positions: %d open", result.Unfilled)
 		log.Warn().Int("open", result.Unfilled).Msg("writing plan with open positions")
 	}
+	if err := p.saveInvigilationPlan(ctx, problem, best, reporter); err != nil {
+		return report, p.failSolverRun(ctx, run, err)
+	}
+	run.Status = model.SolverRunStatusFinished
+	p.saveSolverRun(ctx, run)
+	reporter.Println("... done")
+	return report, nil
+}
 
+// saveInvigilationPlan writes the non-self invigilations of plan to invigilations_other,
+// replacing the previous content, and recalculates the todos.
+func (p *Plexams) saveInvigilationPlan(ctx context.Context, problem *invigplan.Problem, plan *invigplan.Plan, reporter Reporter) error {
 	toSave := make([]interface{}, 0, len(problem.Positions))
-	for posIdx, invigID := range best.Assign {
+	for posIdx, invigID := range plan.Assign {
 		if invigID == invigplan.Unassigned {
 			continue
 		}
@@ -94,6 +139,7 @@ func (p *Plexams) AssignInvigilations(ctx context.Context, dryRun bool, opts inv
 			// Starttime is the source of truth; day/slot are derived on read.
 			Starttime: &start,
 			RoomName:  roomName,
+			Position:  pos.Seat,
 			// Duration is the actual time block (longest invigilation in the
 			// slot), not the credited minutes. For a reserve pos.Minutes is the
 			// credited 60 min while pos.Block holds the slot's max duration; the
@@ -108,17 +154,16 @@ func (p *Plexams) AssignInvigilations(ctx context.Context, dryRun bool, opts inv
 
 	otherCtx := context.WithValue(ctx, db.CollectionName("collectionName"), "invigilations_other")
 	if err := p.dbClient.DropAndSave(otherCtx, toSave); err != nil {
-		return report, fmt.Errorf("cannot save generated invigilations: %w", err)
+		return fmt.Errorf("cannot save generated invigilations: %w", err)
 	}
 	reporter.Printf("wrote %d invigilations to invigilations_other\n", len(toSave))
 
 	reporter.Println("recalculating todos ...")
 	if _, err := p.PrepareInvigilationTodos(ctx); err != nil {
-		return report, fmt.Errorf("cannot recalculate todos: %w", err)
+		return fmt.Errorf("cannot recalculate todos: %w", err)
 	}
 	p.markCondition(ctx, condInvigilationsAssigned)
-	reporter.Println("... done")
-	return report, nil
+	return nil
 }
 
 // printInvigilationReport prints a readable, colored report of the optimizer
@@ -317,6 +362,8 @@ func (p *Plexams) ShowInvigilationProblem(ctx context.Context) error {
 	fmt.Printf("Invigilation problem\n")
 	fmt.Printf("  positions:          %4d  (%d rooms, %d NTA rooms, %d reserves)\n",
 		s.Positions, s.Rooms, s.NTARooms, s.Reserves)
+	fmt.Printf("  additional seats:   %4d  (second, third, ... invigilator of a room, staffing rules)\n",
+		s.AdditionalSeats)
 	fmt.Printf("  fixed:              %4d  (%d self-invigilations + pre-planned)\n",
 		s.FixedPositions, s.SelfPositions)
 	fmt.Printf("  open to optimize:   %4d\n", s.Positions-s.FixedPositions)
@@ -328,8 +375,9 @@ func (p *Plexams) ShowInvigilationProblem(ctx context.Context) error {
 
 // buildInvigilationProblem assembles the static snapshot the invigilation
 // optimizer works on. It reads everything from the DB once:
-//   - positions to fill: every planned room per slot (one invigilator each) plus
-//     one reserve per slot that has exams,
+//   - positions to fill: every planned room per slot (one position per
+//     invigilator the staffing rules ask for, default one) plus one reserve per
+//     slot that has exams,
 //   - invigilators with their target minutes and availability (from the cached
 //     todos),
 //   - the fixed assignments: self-invigilations and pre-planned invigilations,
@@ -414,14 +462,35 @@ func (p *Plexams) buildInvigilationProblem(ctx context.Context, includeExcluded
 		ownExamDays[examerID][dateOrdinal(start)] = true
 	}
 
+	// campus of every room position, for the travel time between two invigilations
+	sites, err := p.campusModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	campusOf := func(room string) string {
+		if sites.DefaultCampus() == "" {
+			return ""
+		}
+		if loc := sites.Room(room); loc.Building != "" {
+			return loc.Campus
+		}
+		return ""
+	}
+
 	positions := make([]invigplan.Position, 0)
 	fixed := make(map[int]int)
 
 	// posIndexByRoomAt and reserveAt let pre-planned invigilations find "their"
-	// position again after the sweep, keyed on the slot's absolute start time.
-	posIndexByRoomAt := make(map[posKey]int)
+	// position again after the sweep, keyed on the slot's absolute start time. A room
+	// with several invigilators lists its non-self seats first, in order.
+	posIndexByRoomAt := make(map[posKey][]int)
 	reserveAt := make(map[int64]int)
 
+	staffing, err := p.newRoomStaffing(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, slot := range p.semesterConfig.Slots {
 		start := slot.Starttime
 		rooms, err := p.PlannedRoomsInSlot(ctx, start)
@@ -494,22 +563,37 @@ func (p *Plexams) buildInvigilationProblem(ctx context.Context, includeExcluded
 		}
 		sort.Strings(roomNames)
 
+		// one position per invigilator the room needs (staffing rules); a
+		// self-invigilation takes the first seat, every further seat is credited
+		// with the room's full duration.
+		needed := staffing.needed(rooms)
 		for _, name := range roomNames {
 			info := roomMap[name]
-			pos := invigplan.Position{
-				Room:    name,
-				IsNTA:   info.isNTA,
-				Minutes: info.maxDuration,
-				Block:   info.maxDuration,
-				Start:   start,
+			key := posKey{start.Unix(), name}
+			selfIdx := -1
+			for seat := 0; seat < needed[name]; seat++ {
+				pos := invigplan.Position{
+					Room:    name,
+					Seat:    seat,
+					IsNTA:   info.isNTA,
+					Minutes: info.maxDuration,
+					Block:   info.maxDuration,
+					Start:   start,
+					Campus:  campusOf(name),
+				}
+				if examerID, ok := selfByPosition[key]; ok && seat == 0 {
+					pos.IsSelf = true
+					pos.Minutes = 0
+					fixed[len(positions)] = examerID
+					selfIdx = len(positions)
+				} else {
+					posIndexByRoomAt[key] = append(posIndexByRoomAt[key], len(positions))
+				}
+				positions = append(positions, pos)
 			}
-			if examerID, ok := selfByPosition[posKey{start.Unix(), name}]; ok {
-				pos.IsSelf = true
-				pos.Minutes = 0
-				fixed[len(positions)] = examerID
+			if selfIdx >= 0 { // a pre-planning may still override the self-invigilation
+				posIndexByRoomAt[key] = append(posIndexByRoomAt[key], selfIdx)
 			}
-			posIndexByRoomAt[posKey{start.Unix(), name}] = len(positions)
-			positions = append(positions, pos)
 		}
 
 		// one reserve per slot with exams.
@@ -530,7 +614,11 @@ func (p *Plexams) buildInvigilationProblem(ctx context.Context, includeExcluded
 			if pp.RoomName == nil {
 				posIdx, ok = reserveAt[pp.Starttime.Unix()]
 			} else {
-				posIdx, ok = posIndexByRoomAt[posKey{pp.Starttime.Unix(), *pp.RoomName}]
+				key := posKey{pp.Starttime.Unix(), *pp.RoomName}
+				if free := posIndexByRoomAt[key]; len(free) > 0 {
+					posIdx, ok = free[0], true
+					posIndexByRoomAt[key] = free[1:]
+				}
 			}
 		}
 		if !ok {
@@ -598,19 +686,7 @@ func (p *Plexams) buildInvigilationProblem(ctx context.Context, includeExcluded
 					gi.ExcludedDays[dateOrdinal(p.semesterConfig.Days[dayNum-1].Date)] = true
 				}
 			}
-			for _, w := range inv.Requirements.TimeWindows {
-				if w == nil {
-					continue
-				}
-				dtw := invigplan.DayTimeWindow{Date: w.Date}
-				if w.From != nil {
-					dtw.From = *w.From
-				}
-				if w.Until != nil {
-					dtw.Until = *w.Until
-				}
-				gi.TimeWindows = append(gi.TimeWindows, dtw)
-			}
+			gi.TimeWindows = append(gi.TimeWindows, dayTimeWindows(inv.Requirements.TimeWindows)...)
 		}
 		invigilators = append(invigilators, gi)
 	}
@@ -627,6 +703,7 @@ func (p *Plexams) buildInvigilationProblem(ctx context.Context, includeExcluded
 		ToleranceMin: cfg.ToleranceMin,
 		MaxSpanHours: cfg.MaxSpanHours,
 		Weights:      optimizerWeights(cfg),
+		Travel:       invigilationTravel(positions, sites),
 	}
 	problem.Prepare()
 
@@ -641,6 +718,35 @@ func (p *Plexams) buildInvigilationProblem(ctx context.Context, includeExcluded
 	return problem, nil
 }
 
+// invigilationSoftRules evaluates the user-defined invigilation rules of the generation
+// config for problem, to be registered with invigplan.DefaultRegistry. Rules that no longer
+// compile are skipped with an error in the log, like in the other generators.
+func (p *Plexams) invigilationSoftRules(ctx context.Context, problem *invigplan.Problem) ([]invigplan.Rule, error) {
+	cfg, err := p.GenerationConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := compileSoftRules(cfg.SoftRules)
+	if err != nil {
+		log.Error().Err(err).Msg("user-defined soft rules ignored")
+		return nil, nil
+	}
+	if !slices.ContainsFunc(rules, func(cr compiledSoftRule) bool { return cr.target == model.SoftRuleTargetInvigilations }) {
+		return nil, nil
+	}
+	todos, err := p.GetInvigilationTodos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	invigilators := make(map[int]*model.Invigilator, len(todos.Invigilators))
+	for _, inv := range todos.Invigilators {
+		if inv.Teacher != nil {
+			invigilators[inv.Teacher.ID] = inv
+		}
+	}
+	return invigilationRules(rules, problem, invigilators), nil
+}
+
 // dateOrdinal maps a time to a calendar-date ordinal (y*10000 + month*100 +
 // day), matching invigplan's internal date keying so day-scoped inputs
 // (ExcludedDays, own-exam days) line up with the position start dates.
@@ -648,3 +754,27 @@ func dateOrdinal(t time.Time) int {
 	y, m, d := t.Date()
 	return y*10000 + int(m)*100 + d
 }
+
+// invigilationTravel is the travel-time matrix between the campuses of the positions.
+func invigilationTravel(positions []invigplan.Position, sites *campus.Model) map[[2]string]int {
+	campuses := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, pos := range positions {
+		if pos.Campus != "" && !seen[pos.Campus] {
+			seen[pos.Campus] = true
+			campuses = append(campuses, pos.Campus)
+		}
+	}
+	if len(campuses) < 2 {
+		return nil
+	}
+	travel := make(map[[2]string]int)
+	for _, a := range campuses {
+		for _, b := range campuses {
+			if a != b {
+				travel[[2]string{a, b}] = sites.TravelMinutes(a, b)
+			}
+		}
+	}
+	return travel
+}
@@ -0,0 +1,86 @@
+package plexams
+
+import (
+	"context"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/invigcalc"
+)
+
+// roomStaffing applies the staffing rules of the generation config to the planned rooms
+// of one exam time. It loads the rooms and exam constraints once, so a caller walking
+// all slots builds it once and asks per slot.
+type roomStaffing struct {
+	rules       []*model.StaffingRule
+	seats       map[string]int
+	constraints map[int]*model.RoomConstraints
+}
+
+// newRoomStaffing loads the staffing rules and, if any rule is enabled, the room seats
+// and the room constraints of the exams.
+func (p *Plexams) newRoomStaffing(ctx context.Context) (*roomStaffing, error) {
+	cfg, err := p.GenerationConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s := &roomStaffing{}
+	for _, rule := range cfg.StaffingRules {
+		if rule != nil && rule.Enabled {
+			s.rules = append(s.rules, rule)
+		}
+	}
+	if len(s.rules) == 0 {
+		return s, nil
+	}
+
+	rooms, err := p.dbClient.Rooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.seats = make(map[string]int, len(rooms))
+	for _, room := range rooms {
+		s.seats[room.Name] = room.Seats
+	}
+	constraints, err := p.dbClient.GetConstraints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.constraints = make(map[int]*model.RoomConstraints, len(constraints))
+	for _, c := range constraints {
+		if c != nil && c.RoomConstraints != nil {
+			s.constraints[c.Ancode] = c.RoomConstraints
+		}
+	}
+	return s, nil
+}
+
+// needed returns the invigilators each room needs, keyed by room name, for the planned
+// rooms of one exam time. Without enabled rules every room needs one.
+func (s *roomStaffing) needed(rooms []*model.PlannedRoom) map[string]int {
+	byRoom := make(map[string]*invigcalc.StaffingRoom)
+	ntas := make(map[string]map[string]bool)
+	for _, room := range rooms {
+		sr, ok := byRoom[room.RoomName]
+		if !ok {
+			sr = &invigcalc.StaffingRoom{Seats: s.seats[room.RoomName]}
+			byRoom[room.RoomName] = sr
+			ntas[room.RoomName] = make(map[string]bool)
+		}
+		sr.Students += len(room.StudentsInRoom)
+		if room.NtaMtknr != nil {
+			ntas[room.RoomName][*room.NtaMtknr] = true
+		}
+		if c := s.constraints[room.Ancode]; c != nil {
+			sr.Exahm = sr.Exahm || c.Exahm
+			sr.Seb = sr.Seb || c.Seb
+			sr.Lab = sr.Lab || c.Lab
+		}
+	}
+
+	needed := make(map[string]int, len(byRoom))
+	for name, sr := range byRoom {
+		sr.NTAs = len(ntas[name])
+		needed[name] = invigcalc.InvigilatorsNeeded(s.rules, *sr)
+	}
+	return needed
+}
@@ -0,0 +1,330 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/email"
+	"github.com/obcode/plexams.go/plexams/invigplan"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Invigilation swaps between colleagues (see graph/invigilation_swap.graphqls): every step
+// re-evaluates the swap on the persisted plan with the invigplan hard constraints, so a
+// swap proposed on Monday is not approved on Friday after the plan changed underneath.
+
+// defaultSwapMaxMinutesDelta is the default auto-approve threshold: a swap may shift up to
+// half an hour of credited minutes between the two colleagues.
+const defaultSwapMaxMinutesDelta = 30
+
+// InvigilationSwaps returns the swaps of the semester, newest first.
+func (p *Plexams) InvigilationSwaps(ctx context.Context, openOnly bool) ([]*model.InvigilationSwap, error) {
+	return p.dbClient.InvigilationSwaps(ctx, openOnly)
+}
+
+// ProposeInvigilationSwap records a hand-over (take == nil) or swap proposed by an
+// invigilator. A proposal that would break a hard constraint is refused right away.
+func (p *Plexams) ProposeInvigilationSwap(ctx context.Context, proposerID, colleagueID int,
+	give model.InvigilationSwapPositionInput, take *model.InvigilationSwapPositionInput, note *string,
+) (*model.InvigilationSwap, error) {
+	if proposerID == colleagueID {
+		return nil, fmt.Errorf("cannot swap an invigilation with oneself")
+	}
+	swap := &model.InvigilationSwap{
+		ID:          primitive.NewObjectID().Hex(),
+		Kind:        model.InvigilationSwapKindHandover,
+		Status:      model.InvigilationSwapStatusProposed,
+		ProposerID:  proposerID,
+		ColleagueID: colleagueID,
+		Give:        swapPosition(give),
+		Note:        note,
+		CreatedAt:   time.Now(),
+	}
+	if take != nil {
+		swap.Kind = model.InvigilationSwapKindSwap
+		swap.Take = swapPosition(*take)
+	}
+	for _, t := range []struct {
+		id   int
+		name *string
+	}{{proposerID, &swap.ProposerName}, {colleagueID, &swap.ColleagueName}} {
+		teacher, err := p.GetTeacher(ctx, t.id)
+		if err != nil {
+			return nil, err
+		}
+		if teacher == nil {
+			return nil, fmt.Errorf("teacher %d not found", t.id)
+		}
+		*t.name = teacher.Fullname
+	}
+
+	if err := p.evaluateInvigilationSwap(ctx, swap); err != nil {
+		return nil, err
+	}
+	if len(swap.HardViolations) > 0 {
+		return nil, fmt.Errorf("swap breaks hard constraints: %s", swap.HardViolations[0])
+	}
+	if err := p.dbClient.SaveInvigilationSwap(ctx, swap); err != nil {
+		return nil, err
+	}
+	return swap, nil
+}
+
+// AcceptInvigilationSwap records the colleague's consent. If the generation config allows
+// automatic approval and the swap is within the thresholds, it is applied at once.
+func (p *Plexams) AcceptInvigilationSwap(ctx context.Context, id string) (*model.InvigilationSwap, error) {
+	swap, err := p.openInvigilationSwap(ctx, id, model.InvigilationSwapStatusProposed)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.evaluateInvigilationSwap(ctx, swap); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	swap.Status = model.InvigilationSwapStatusAccepted
+	swap.AcceptedAt = &now
+
+	cfg, err := p.GenerationConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SwapAutoApprove && swap.WithinThresholds && len(swap.HardViolations) == 0 {
+		swap.AutoApproved = true
+		return swap, p.applyInvigilationSwap(ctx, swap)
+	}
+	if err := p.dbClient.SaveInvigilationSwap(ctx, swap); err != nil {
+		return nil, err
+	}
+	return swap, nil
+}
+
+// ApproveInvigilationSwap applies an accepted swap by hand. The planner may approve beyond
+// the minute thresholds, never against a hard constraint.
+func (p *Plexams) ApproveInvigilationSwap(ctx context.Context, id string) (*model.InvigilationSwap, error) {
+	swap, err := p.openInvigilationSwap(ctx, id, model.InvigilationSwapStatusAccepted)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.evaluateInvigilationSwap(ctx, swap); err != nil {
+		return nil, err
+	}
+	if len(swap.HardViolations) > 0 {
+		if err := p.dbClient.SaveInvigilationSwap(ctx, swap); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("swap breaks hard constraints: %s", swap.HardViolations[0])
+	}
+	return swap, p.applyInvigilationSwap(ctx, swap)
+}
+
+// RejectInvigilationSwap closes an open swap without changing the plan.
+func (p *Plexams) RejectInvigilationSwap(ctx context.Context, id string, reason *string) (*model.InvigilationSwap, error) {
+	swap, err := p.openInvigilationSwap(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	swap.Status = model.InvigilationSwapStatusRejected
+	swap.DecidedAt = &now
+	swap.Reason = reason
+	if err := p.dbClient.SaveInvigilationSwap(ctx, swap); err != nil {
+		return nil, err
+	}
+	return swap, nil
+}
+
+// openInvigilationSwap loads a swap and checks its status (any open status if none given).
+func (p *Plexams) openInvigilationSwap(ctx context.Context, id string, status ...model.InvigilationSwapStatus) (*model.InvigilationSwap, error) {
+	swap, err := p.dbClient.InvigilationSwap(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if swap == nil {
+		return nil, fmt.Errorf("invigilation swap %s not found", id)
+	}
+	if len(status) == 0 {
+		status = []model.InvigilationSwapStatus{model.InvigilationSwapStatusProposed, model.InvigilationSwapStatusAccepted}
+	}
+	for _, s := range status {
+		if swap.Status == s {
+			return swap, nil
+		}
+	}
+	return nil, fmt.Errorf("invigilation swap %s is %s", id, swap.Status)
+}
+
+// evaluateInvigilationSwap maps the swap onto the persisted plan and fills in the minutes,
+// the minute deltas, the thresholds check and the hard violations the swap would add. It
+// fails if a position does not exist (any more) or is not held by the expected person.
+func (p *Plexams) evaluateInvigilationSwap(ctx context.Context, swap *model.InvigilationSwap) error {
+	problem, err := p.buildInvigilationProblem(ctx, true)
+	if err != nil {
+		return err
+	}
+	// the persisted plan is what the swap changes, not the generator's fixed seeds
+	problem.Fixed = map[int]int{}
+	plan, index, err := p.persistedInvigilationPlan(ctx, problem, nil)
+	if err != nil {
+		return err
+	}
+	prePlanned, err := p.PrePlannedInvigilations(ctx)
+	if err != nil {
+		return err
+	}
+
+	giveIdx, err := swapPositionIndex(problem, plan, index, prePlanned, swap.Give, swap.ProposerID)
+	if err != nil {
+		return err
+	}
+	takeIdx := -1
+	if swap.Take != nil {
+		if takeIdx, err = swapPositionIndex(problem, plan, index, prePlanned, swap.Take, swap.ColleagueID); err != nil {
+			return err
+		}
+	}
+	for _, id := range []int{swap.ProposerID, swap.ColleagueID} {
+		if problem.Invigilator(id) == nil {
+			return fmt.Errorf("teacher %d does no invigilation this semester", id)
+		}
+	}
+
+	swap.HardViolations = swapHardViolations(problem, plan, giveIdx, takeIdx, swap.ProposerID, swap.ColleagueID)
+	swap.Give.Minutes = problem.Positions[giveIdx].Minutes
+	takeMinutes := 0
+	if swap.Take != nil {
+		swap.Take.Minutes = problem.Positions[takeIdx].Minutes
+		takeMinutes = swap.Take.Minutes
+	}
+	swap.ProposerMinutesDelta = takeMinutes - swap.Give.Minutes
+	swap.ColleagueMinutesDelta = -swap.ProposerMinutesDelta
+
+	cfg, err := p.GenerationConfig(ctx)
+	if err != nil {
+		return err
+	}
+	delta := swap.ProposerMinutesDelta
+	if delta < 0 {
+		delta = -delta
+	}
+	swap.WithinThresholds = delta <= cfg.SwapMaxMinutesDelta
+	return nil
+}
+
+// swapPositionIndex finds the problem position of a swap position and checks that the
+// expected invigilator holds it and that it may change hands.
+func swapPositionIndex(problem *invigplan.Problem, plan *invigplan.Plan, index map[string]int,
+	prePlanned []*model.PrePlannedInvigilation, sp *model.InvigilationSwapPosition, holderID int,
+) (int, error) {
+	room, where := "", "reserve"
+	if sp.RoomName != nil {
+		room, where = *sp.RoomName, *sp.RoomName
+	}
+	when := sp.Starttime.Format("02.01. 15:04")
+	idx, ok := index[positionKey(sp.Starttime, sp.RoomName == nil, room, sp.Position)]
+	if !ok {
+		return 0, fmt.Errorf("no invigilation position for %s at %s", where, when)
+	}
+	if problem.Positions[idx].IsSelf {
+		return 0, fmt.Errorf("%s at %s is a self-invigilation and cannot be handed over", where, when)
+	}
+	if holder := plan.Assign[idx]; holder != holderID {
+		return 0, fmt.Errorf("%s at %s is not invigilated by %d", where, when, holderID)
+	}
+	for _, pp := range prePlanned {
+		if pp.InvigilatorID == holderID && pp.Starttime != nil && pp.Starttime.Equal(sp.Starttime) &&
+			pp.IsReserve == (sp.RoomName == nil) && (pp.IsReserve || (pp.RoomName != nil && *pp.RoomName == room)) {
+			return 0, fmt.Errorf("%s at %s is pre-planned for %d, remove the pre-planning first", where, when, holderID)
+		}
+	}
+	return idx, nil
+}
+
+// swapHardViolations returns the hard violations the swap adds to the plan (the plan is
+// changed in place). Violations the plan already had are not the swap's fault.
+func swapHardViolations(problem *invigplan.Problem, plan *invigplan.Plan, giveIdx, takeIdx, proposerID, colleagueID int) []string {
+	reg := invigplan.DefaultRegistry()
+	before := make(map[string]bool)
+	for _, v := range reg.HardViolations(problem, plan) {
+		before[v.Constraint+"|"+v.Message] = true
+	}
+	plan.Set(giveIdx, colleagueID)
+	if takeIdx >= 0 {
+		plan.Set(takeIdx, proposerID)
+	}
+	added := make([]string, 0)
+	for _, v := range reg.HardViolations(problem, plan) {
+		if !before[v.Constraint+"|"+v.Message] {
+			added = append(added, fmt.Sprintf("[%s] %s", v.Constraint, v.Message))
+		}
+	}
+	return added
+}
+
+// applyInvigilationSwap writes the swap into the invigilation plan, marks it approved and
+// records it in the mutation log.
+func (p *Plexams) applyInvigilationSwap(ctx context.Context, swap *model.InvigilationSwap) error {
+	if err := p.dbClient.AddInvigilationAt(ctx, swapRoom(swap.Give), swap.Give.Starttime, swap.Give.Position, swap.ColleagueID); err != nil {
+		return err
+	}
+	if swap.Take != nil {
+		if err := p.dbClient.AddInvigilationAt(ctx, swapRoom(swap.Take), swap.Take.Starttime, swap.Take.Position, swap.ProposerID); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	swap.Status = model.InvigilationSwapStatusApproved
+	swap.DecidedAt = &now
+	if err := p.dbClient.SaveInvigilationSwap(ctx, swap); err != nil {
+		return err
+	}
+
+	args := []*model.MutationLogArg{
+		{Key: "id", Value: swap.ID},
+		{Key: "kind", Value: string(swap.Kind)},
+		{Key: "proposerID", Value: strconv.Itoa(swap.ProposerID)},
+		{Key: "colleagueID", Value: strconv.Itoa(swap.ColleagueID)},
+		{Key: "give", Value: swapPositionLabel(swap.Give)},
+		{Key: "autoApproved", Value: strconv.FormatBool(swap.AutoApproved)},
+	}
+	if swap.Take != nil {
+		args = append(args, &model.MutationLogArg{Key: "take", Value: swapPositionLabel(swap.Take)})
+	}
+	p.LogMutation(ctx, &model.MutationLogEntry{
+		Time:    now,
+		Name:    "applyInvigilationSwap",
+		Type:    "mutation",
+		User:    p.OperatorID(),
+		Args:    args,
+		Ancodes: []int{},
+	})
+	return nil
+}
+
+func swapPosition(in model.InvigilationSwapPositionInput) *model.InvigilationSwapPosition {
+	sp := &model.InvigilationSwapPosition{Starttime: in.Starttime, RoomName: in.RoomName}
+	if in.Position != nil {
+		sp.Position = *in.Position
+	}
+	return sp
+}
+
+// swapRoom is the room argument of AddInvigilationAt ("reserve" for the reserve).
+func swapRoom(sp *model.InvigilationSwapPosition) string {
+	if sp.RoomName == nil {
+		return "reserve"
+	}
+	return *sp.RoomName
+}
+
+// swapPositionLabel renders a swap position in German, e.g. "Mo, 13.07.2026 10:30 Uhr,
+// Aufsicht in R1.049 (90 Minuten)".
+func swapPositionLabel(sp *model.InvigilationSwapPosition) string {
+	what := "Reserveaufsicht"
+	if sp.RoomName != nil {
+		what = "Aufsicht in " + *sp.RoomName
+	}
+	return fmt.Sprintf("%s %s Uhr, %s (%d Minuten)", email.DateDE(sp.Starttime), email.TimeDE(sp.Starttime), what, sp.Minutes)
+}
@@ -0,0 +1,75 @@
+package plexams
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/invigplan"
+)
+
+func TestSwapHardViolations(t *testing.T) {
+	start := time.Date(2026, 7, 13, 10, 30, 0, 0, time.Local)
+	problem := &invigplan.Problem{
+		Positions: []invigplan.Position{
+			{Room: "R1.049", Minutes: 90, Block: 90, Start: start},
+			{Room: "R1.046", Minutes: 90, Block: 90, Start: start},
+		},
+		Invigilators: []invigplan.Invigilator{
+			{ID: 1, TargetMinutes: 90},
+			{ID: 2, TargetMinutes: 90},
+			{ID: 3, TargetMinutes: 90, OwnExamSlots: map[int64]bool{start.Unix(): true}},
+		},
+		Fixed: map[int]int{},
+	}
+	problem.Prepare()
+
+	newPlan := func() *invigplan.Plan {
+		plan := invigplan.NewPlan(problem)
+		plan.Set(0, 1)
+		plan.Set(1, 2)
+		return plan
+	}
+
+	// a swap of two rooms in the same slot breaks nothing
+	if v := swapHardViolations(problem, newPlan(), 0, 1, 1, 2); len(v) != 0 {
+		t.Errorf("swap in the same slot: got violations %v", v)
+	}
+	// handing over to someone with an own exam at that time is refused
+	if v := swapHardViolations(problem, newPlan(), 0, -1, 1, 3); len(v) == 0 {
+		t.Error("hand-over to a colleague with an own exam: want a violation")
+	}
+	// handing over to someone who already invigilates in that slot is refused
+	if v := swapHardViolations(problem, newPlan(), 0, -1, 1, 2); len(v) == 0 {
+		t.Error("hand-over to a colleague busy in the same slot: want a violation")
+	}
+}
+
+func TestInvigilationSwapLines(t *testing.T) {
+	room := "R1.049"
+	swap := &model.InvigilationSwap{
+		Kind:          model.InvigilationSwapKindSwap,
+		ProposerID:    1,
+		ProposerName:  "Prof. Dr. Erika Mustermann",
+		ColleagueID:   2,
+		ColleagueName: "Prof. Dr. Max Muster",
+		Give:          &model.InvigilationSwapPosition{Starttime: time.Date(2026, 7, 13, 10, 30, 0, 0, time.Local), RoomName: &room},
+		Take:          &model.InvigilationSwapPosition{Starttime: time.Date(2026, 7, 14, 8, 30, 0, 0, time.Local)},
+	}
+
+	proposer := invigilationSwapLines(swap, 1)
+	want := []string{
+		"Mo, 13.07.2026 10:30 Uhr: Sie geben die Aufsicht in R1.049 an Prof. Dr. Max Muster ab",
+		"Di, 14.07.2026 08:30 Uhr: Sie übernehmen die Reserveaufsicht von Prof. Dr. Max Muster",
+	}
+	if strings.Join(proposer, "\n") != strings.Join(want, "\n") {
+		t.Errorf("proposer lines = %q, want %q", proposer, want)
+	}
+
+	colleague := invigilationSwapLines(swap, 2)
+	if len(colleague) != 2 || !strings.Contains(colleague[0], "Sie übernehmen die Aufsicht in R1.049 von Prof. Dr. Erika Mustermann") ||
+		!strings.Contains(colleague[1], "Sie geben die Reserveaufsicht an Prof. Dr. Erika Mustermann ab") {
+		t.Errorf("colleague lines = %q", colleague)
+	}
+}
@@ -0,0 +1,134 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/freebusy"
+)
+
+// ImportInvigilatorAvailability reads a teacher's calendar export (ICS with events or
+// free/busy) and turns the busy time on the exam days into excluded days and
+// unavailable time windows of their invigilator constraints (see freebusy). With
+// replace, the excluded days and unavailable windows missing in the file are removed
+// (re-import after the calendar changed); otherwise the file only adds. dryRun (or any
+// problem in the file) only returns the preview.
+func (p *Plexams) ImportInvigilatorAvailability(ctx context.Context, teacherID int, data []byte, replace, dryRun bool) (*freebusy.Import, error) {
+	teachers, err := p.getInvigilators(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := false
+	for _, t := range teachers {
+		if t.ID == teacherID {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("teacher %d is not in the invigilator pool", teacherID)
+	}
+	if len(p.semesterConfig.Days) == 0 {
+		return nil, fmt.Errorf("no exam days configured")
+	}
+
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	days := make([]time.Time, 0, len(p.semesterConfig.Days))
+	for _, day := range p.semesterConfig.Days {
+		days = append(days, day.Date)
+	}
+	y, m, d := days[0].In(loc).Date()
+	from := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	y, m, d = days[len(days)-1].In(loc).Date()
+	until := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+
+	busy, warnings, problems, err := freebusy.Parse(data, loc, from, until)
+	if err != nil {
+		return nil, err
+	}
+
+	constraints, err := p.dbClient.InvigilatorConstraintsForTeacher(ctx, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	if constraints == nil {
+		constraints = &model.InvigilatorConstraints{TeacherID: teacherID}
+	}
+	merged, result := freebusy.Merge(constraints, freebusy.Blocks(busy, days, loc), replace, loc)
+	result.Busy = len(busy)
+	result.Warnings = append(result.Warnings, warnings...)
+	result.Problems = append(result.Problems, problems...)
+	if dryRun || len(result.Problems) > 0 || len(result.Added)+len(result.Removed) == 0 {
+		return result, nil
+	}
+
+	if err := p.dbClient.UpsertInvigilatorConstraints(ctx, merged); err != nil {
+		return nil, err
+	}
+	p.LogMutation(ctx, &model.MutationLogEntry{
+		Time: time.Now(),
+		Name: "importInvigilatorAvailability",
+		Type: "upload",
+		User: p.OperatorID(),
+		Args: []*model.MutationLogArg{
+			{Key: "teacherID", Value: strconv.Itoa(teacherID)},
+			{Key: "replace", Value: strconv.FormatBool(replace)},
+			{Key: "changes", Value: result.Summary()},
+		},
+		Ancodes: []int{},
+	})
+	p.rebuildInvigilationTodosBestEffort(ctx)
+	result.Applied = true
+	return result, nil
+}
+
+// HTTPUploadInvigilatorAvailability imports a teacher's calendar export (multipart
+// fields "teacherID" and "file", ICS). With ?dryRun=true it only returns the preview,
+// ?replace=true replaces the previously entered unavailability.
+// POST /upload/invigilator-availability
+func (p *Plexams) HTTPUploadInvigilatorAvailability(w http.ResponseWriter, r *http.Request) {
+	if !p.WritesAllowed() {
+		http.Error(w, "a validation or transfer/email is running, cannot upload now", http.StatusConflict)
+		return
+	}
+	if p.IsReadOnly() {
+		http.Error(w, "semester is read-only", http.StatusConflict)
+		return
+	}
+	if err := r.ParseMultipartForm(16 << 20); err != nil {
+		http.Error(w, "cannot parse upload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	teacherID, err := strconv.Atoi(r.FormValue("teacherID"))
+	if err != nil {
+		http.Error(w, "missing or invalid teacherID", http.StatusBadRequest)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "missing file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close() //nolint:errcheck
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "cannot read file: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+	replace := r.URL.Query().Get("replace") == "true"
+	result, err := p.ImportInvigilatorAvailability(r.Context(), teacherID, data, replace, dryRun)
+	if err != nil {
+		http.Error(w, "cannot import availability: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if result.Applied {
+		p.LogUpload(r.Context(), "uploadInvigilatorAvailability", "teacherID", strconv.Itoa(teacherID), "file", header.Filename, "changes", result.Summary())
+	}
+	writeJSON(w, result)
+}
@@ -209,7 +209,8 @@ func (p *Plexams) notInvigilating(ctx context.Context) (func(teacherID int) bool
 
 // SetInvigilatorConstraints creates or replaces the whole constraints record of
 // one invigilator. Empty records (no exclusion, no dates, no windows) are stored
-// too, so the GUI can keep an explicit "no constraints" entry.
+// too, so the GUI can keep an explicit "no constraints" entry. Weekly windows are
+// stored as entered and expanded to the exam days when the invigilator is built.
 func (p *Plexams) SetInvigilatorConstraints(ctx context.Context, input model.InvigilatorConstraintsInput) (*model.InvigilatorConstraints, error) {
 	timeWindows := make([]*model.InvigilationTimeWindow, 0, len(input.TimeWindows))
 	for _, w := range input.TimeWindows {
@@ -222,7 +223,24 @@ func (p *Plexams) SetInvigilatorConstraints(ctx context.Context, input model.Inv
 		if w.From != nil && w.Until != nil && !w.Until.After(*w.From) {
 			return nil, fmt.Errorf("time window on %s: until must be after from", w.Date.Format("02.01.2006"))
 		}
-		timeWindows = append(timeWindows, &model.InvigilationTimeWindow{Date: w.Date, From: w.From, Until: w.Until})
+		timeWindows = append(timeWindows, &model.InvigilationTimeWindow{
+			Date:        w.Date,
+			From:        w.From,
+			Until:       w.Until,
+			Unavailable: w.Unavailable != nil && *w.Unavailable,
+		})
+	}
+
+	weeklyTimeWindows := make([]*model.WeeklyInvigilationTimeWindow, 0, len(input.WeeklyTimeWindows))
+	for _, w := range input.WeeklyTimeWindows {
+		if w == nil {
+			continue
+		}
+		window, err := weeklyTimeWindowFromInput(w)
+		if err != nil {
+			return nil, err
+		}
+		weeklyTimeWindows = append(weeklyTimeWindows, window)
 	}
 
 	excludedDates := make([]time.Time, 0, len(input.ExcludedDates))
@@ -233,10 +251,11 @@ func (p *Plexams) SetInvigilatorConstraints(ctx context.Context, input model.Inv
 	}
 
 	constraints := &model.InvigilatorConstraints{
-		TeacherID:        input.TeacherID,
-		IsNotInvigilator: input.IsNotInvigilator,
-		ExcludedDates:    excludedDates,
-		TimeWindows:      timeWindows,
+		TeacherID:         input.TeacherID,
+		IsNotInvigilator:  input.IsNotInvigilator,
+		ExcludedDates:     excludedDates,
+		TimeWindows:       timeWindows,
+		WeeklyTimeWindows: weeklyTimeWindows,
 	}
 	if err := p.dbClient.UpsertInvigilatorConstraints(ctx, constraints); err != nil {
 		return nil, err
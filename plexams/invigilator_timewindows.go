@@ -0,0 +1,166 @@
+package plexams
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/invigplan"
+)
+
+// weekdayDE parses a German two-letter weekday ("Mo" … "So", case-insensitive).
+func weekdayDE(s string) (time.Weekday, bool) {
+	s = strings.TrimSpace(s)
+	for i, name := range weekdaysDE {
+		if strings.EqualFold(s, name) {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
+
+// clockMinutes parses an optional "HH:MM" clock time into minutes since midnight; ok is
+// false for nil or blank.
+func clockMinutes(s *string) (minutes int, ok bool, err error) {
+	if s == nil || strings.TrimSpace(*s) == "" {
+		return 0, false, nil
+	}
+	t, err := time.Parse("15:04", strings.TrimSpace(*s))
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid clock time %q (expected HH:MM)", *s)
+	}
+	return t.Hour()*60 + t.Minute(), true, nil
+}
+
+// weeklyTimeWindowFromInput validates one weekly window and normalizes it (weekday as
+// "Mo", times as "HH:MM", blank bounds as nil).
+func weeklyTimeWindowFromInput(w *model.WeeklyInvigilationTimeWindowInput) (*model.WeeklyInvigilationTimeWindow, error) {
+	weekday, ok := weekdayDE(w.Weekday)
+	if !ok {
+		return nil, fmt.Errorf("weekly time window: unknown weekday %q (expected Mo, Di, Mi, Do, Fr or Sa)", w.Weekday)
+	}
+	from, hasFrom, err := clockMinutes(w.From)
+	if err != nil {
+		return nil, fmt.Errorf("weekly time window on %s: %w", weekdaysDE[weekday], err)
+	}
+	until, hasUntil, err := clockMinutes(w.Until)
+	if err != nil {
+		return nil, fmt.Errorf("weekly time window on %s: %w", weekdaysDE[weekday], err)
+	}
+	if !hasFrom && !hasUntil {
+		return nil, fmt.Errorf("weekly time window on %s: at least one of from/until must be set", weekdaysDE[weekday])
+	}
+	if hasFrom && hasUntil && until <= from {
+		return nil, fmt.Errorf("weekly time window on %s: until must be after from", weekdaysDE[weekday])
+	}
+
+	window := &model.WeeklyInvigilationTimeWindow{
+		Weekday:     weekdaysDE[weekday],
+		Unavailable: w.Unavailable != nil && *w.Unavailable,
+	}
+	if hasFrom {
+		s := fmt.Sprintf("%02d:%02d", from/60, from%60)
+		window.From = &s
+	}
+	if hasUntil {
+		s := fmt.Sprintf("%02d:%02d", until/60, until%60)
+		window.Until = &s
+	}
+	return window, nil
+}
+
+// expandWeeklyTimeWindows adds the weekly windows to the date windows for every exam day
+// with a matching weekday. A weekly available window is skipped on a day that already
+// has a date-specific available window (the date window is the more specific statement);
+// unavailable windows always add up.
+func expandWeeklyTimeWindows(days []time.Time, dated []*model.InvigilationTimeWindow,
+	weekly []*model.WeeklyInvigilationTimeWindow, loc *time.Location,
+) []*model.InvigilationTimeWindow {
+	if len(weekly) == 0 {
+		return dated
+	}
+	hasAvailable := make(map[string]bool, len(dated))
+	for _, w := range dated {
+		if w != nil && !w.Unavailable {
+			hasAvailable[w.Date.In(loc).Format("2006-01-02")] = true
+		}
+	}
+
+	windows := append([]*model.InvigilationTimeWindow{}, dated...)
+	for _, day := range days {
+		y, m, d := day.In(loc).Date()
+		for _, w := range weekly {
+			if w == nil || w.Weekday != weekdaysDE[day.In(loc).Weekday()] {
+				continue
+			}
+			if !w.Unavailable && hasAvailable[day.In(loc).Format("2006-01-02")] {
+				continue
+			}
+			window := &model.InvigilationTimeWindow{
+				Date:        time.Date(y, m, d, 0, 0, 0, 0, loc),
+				Unavailable: w.Unavailable,
+			}
+			if from, ok, err := clockMinutes(w.From); err == nil && ok {
+				t := time.Date(y, m, d, from/60, from%60, 0, 0, loc)
+				window.From = &t
+			}
+			if until, ok, err := clockMinutes(w.Until); err == nil && ok {
+				t := time.Date(y, m, d, until/60, until%60, 0, 0, loc)
+				window.Until = &t
+			}
+			windows = append(windows, window)
+		}
+	}
+	return windows
+}
+
+// dayTimeWindows converts the invigilator's date windows into the invigplan windows.
+func dayTimeWindows(windows []*model.InvigilationTimeWindow) []invigplan.DayTimeWindow {
+	result := make([]invigplan.DayTimeWindow, 0, len(windows))
+	for _, w := range windows {
+		if w == nil {
+			continue
+		}
+		dtw := invigplan.DayTimeWindow{Date: w.Date, Unavailable: w.Unavailable}
+		if w.From != nil {
+			dtw.From = *w.From
+		}
+		if w.Until != nil {
+			dtw.Until = *w.Until
+		}
+		result = append(result, dtw)
+	}
+	return result
+}
+
+// unavailableAllDay reports whether the unavailable windows block the whole day: one
+// without bounds, or together overlapping every slot of the day. slots holds one position
+// per slot with exams (from the slot start over its shortest exam), so the check agrees
+// with invigplan's AllowsTime. Such a day counts like an excluded day.
+func unavailableAllDay(date time.Time, slots []invigplan.Position, windows []*model.InvigilationTimeWindow) bool {
+	unavailable := &invigplan.Invigilator{}
+	for _, w := range dayTimeWindows(windows) {
+		if !w.Unavailable || !sameDay(w.Date.Local(), date.Local()) {
+			continue
+		}
+		if w.From.IsZero() && w.Until.IsZero() {
+			return true
+		}
+		unavailable.TimeWindows = append(unavailable.TimeWindows, w)
+	}
+	if len(unavailable.TimeWindows) == 0 {
+		return false
+	}
+	onDay := 0
+	for _, pos := range slots {
+		if !sameDay(pos.Start.Local(), date.Local()) {
+			continue
+		}
+		onDay++
+		if unavailable.AllowsTime(pos) {
+			return false
+		}
+	}
+	return onDay > 0
+}
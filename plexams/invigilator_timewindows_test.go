@@ -0,0 +1,127 @@
+package plexams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/invigplan"
+)
+
+func TestWeeklyTimeWindowFromInput(t *testing.T) {
+	until := "12:00"
+	w, err := weeklyTimeWindowFromInput(&model.WeeklyInvigilationTimeWindowInput{Weekday: "di", Until: &until})
+	if err != nil {
+		t.Fatalf("valid window rejected: %v", err)
+	}
+	if w.Weekday != "Di" || w.From != nil || *w.Until != "12:00" || w.Unavailable {
+		t.Errorf("unexpected window %+v", w)
+	}
+
+	from, early, bad := "14:00", "08:00", "25:00"
+	for _, in := range []*model.WeeklyInvigilationTimeWindowInput{
+		{Weekday: "Tu", Until: &until},
+		{Weekday: "Mo"},
+		{Weekday: "Mo", From: &bad},
+		{Weekday: "Mo", From: &from, Until: &early},
+	} {
+		if _, err := weeklyTimeWindowFromInput(in); err == nil {
+			t.Errorf("window %+v must be rejected", in)
+		}
+	}
+}
+
+func TestExpandWeeklyTimeWindows(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	tue1 := time.Date(2026, time.July, 7, 0, 0, 0, 0, loc)
+	wed := time.Date(2026, time.July, 8, 0, 0, 0, 0, loc)
+	tue2 := time.Date(2026, time.July, 14, 0, 0, 0, 0, loc)
+	noon, afternoon := "12:00", "14:00"
+	yes := true
+
+	morning, err := weeklyTimeWindowFromInput(&model.WeeklyInvigilationTimeWindowInput{Weekday: "Di", Until: &noon})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocked, err := weeklyTimeWindowFromInput(&model.WeeklyInvigilationTimeWindowInput{Weekday: "Di", From: &afternoon, Unavailable: &yes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, time.July, 14, 10, 0, 0, 0, loc)
+	dated := []*model.InvigilationTimeWindow{{Date: tue2, From: &from}}
+
+	got := expandWeeklyTimeWindows([]time.Time{tue1, wed, tue2}, dated,
+		[]*model.WeeklyInvigilationTimeWindow{morning, blocked}, loc)
+
+	// dated window + both weekly windows on 07.07. + only the unavailable one on 14.07.
+	if len(got) != 4 {
+		t.Fatalf("got %d windows, want 4: %+v", len(got), got)
+	}
+	if w := got[1]; !w.Date.Equal(tue1) || w.From != nil || !w.Until.Equal(time.Date(2026, time.July, 7, 12, 0, 0, 0, loc)) {
+		t.Errorf("weekly morning window expanded wrong: %+v", w)
+	}
+	if w := got[3]; !w.Date.Equal(tue2) || !w.Unavailable || !w.From.Equal(time.Date(2026, time.July, 14, 14, 0, 0, 0, loc)) {
+		t.Errorf("weekly unavailable window expanded wrong: %+v", w)
+	}
+}
+
+func TestUnavailableAllDay(t *testing.T) {
+	at := func(d, h, m int) time.Time { return time.Date(2026, 7, d, h, m, 0, 0, time.Local) }
+	ptr := func(t time.Time) *time.Time { return &t }
+	day := at(13, 0, 0) // Monday
+	// 08:30, 10:30 and 14:30 with 90-minute exams, Tuesday 08:30
+	slots := []invigplan.Position{
+		{Start: at(13, 8, 30), Block: 90}, {Start: at(13, 10, 30), Block: 90},
+		{Start: at(13, 14, 30), Block: 90}, {Start: at(14, 8, 30), Block: 90},
+	}
+
+	cases := []struct {
+		name    string
+		windows []*model.InvigilationTimeWindow
+		want    bool
+	}{
+		{"none", nil, false},
+		{"available only", []*model.InvigilationTimeWindow{{Date: day, Until: ptr(at(13, 12, 0))}}, false},
+		{"other day", []*model.InvigilationTimeWindow{{Date: at(14, 0, 0), Unavailable: true}}, false},
+		{"covers all slots", []*model.InvigilationTimeWindow{{Date: day, From: ptr(at(13, 7, 0)), Unavailable: true}}, true},
+		{"covers some slots", []*model.InvigilationTimeWindow{{Date: day, Until: ptr(at(13, 12, 0)), Unavailable: true}}, false},
+		{"two windows together", []*model.InvigilationTimeWindow{
+			{Date: day, Until: ptr(at(13, 12, 0)), Unavailable: true},
+			{Date: day, From: ptr(at(13, 13, 0)), Unavailable: true},
+		}, true},
+		// starts after the first slot has begun: still overlaps its exam
+		{"begins mid-slot", []*model.InvigilationTimeWindow{{Date: day, From: ptr(at(13, 9, 0)), Unavailable: true}}, true},
+		// ends before the last slot starts, but inside the 10:30 exam
+		{"ends mid-slot", []*model.InvigilationTimeWindow{{Date: day, Until: ptr(at(13, 11, 0)), Unavailable: true}}, false},
+		{"ends mid-slot, afternoon blocked", []*model.InvigilationTimeWindow{
+			{Date: day, Until: ptr(at(13, 11, 0)), Unavailable: true},
+			{Date: day, From: ptr(at(13, 15, 59)), Unavailable: true},
+		}, true},
+	}
+	for _, c := range cases {
+		if got := unavailableAllDay(day, slots, c.windows); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	// a date window without bounds blocks the day even without slots
+	if !unavailableAllDay(day, nil, []*model.InvigilationTimeWindow{{Date: day, Unavailable: true}}) {
+		t.Error("window without bounds does not block the day")
+	}
+
+	// a weekly unavailable window from the first slot on excludes the day in
+	// InvigilatorsForDay
+	from := "08:00"
+	windows := expandWeeklyTimeWindows([]time.Time{day, at(14, 0, 0)}, nil,
+		[]*model.WeeklyInvigilationTimeWindow{{Weekday: "Mo", From: &from, Unavailable: true}}, time.Local)
+	inv := &model.Invigilator{
+		Requirements: &model.InvigilatorRequirements{TimeWindows: windows},
+		Todos:        &model.InvigilatorTodos{},
+	}
+	if want, can := dayOkForInvigilator(1, day, slots, inv); want || can {
+		t.Errorf("Monday: want %v, can %v", want, can)
+	}
+	if want, can := dayOkForInvigilator(2, at(14, 0, 0), slots, inv); want || !can {
+		t.Errorf("Tuesday: want %v, can %v", want, can)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/obcode/plexams.go/db"
 	"github.com/obcode/plexams.go/graph/model"
 	"github.com/obcode/plexams.go/plexams/invigcalc"
+	"github.com/obcode/plexams.go/plexams/invigplan"
 	"github.com/obcode/plexams.go/zpa"
 	"github.com/rs/zerolog/log"
 )
@@ -115,24 +116,6 @@ func (p *Plexams) invigilationsAt(ctx context.Context, roomname string, start ti
 	return result, nil
 }
 
-// invigilatorAt returns the invigilator (teacher) assigned to a room at start,
-// mirroring db.GetInvigilatorForRoom but keyed by absolute time.
-func (p *Plexams) invigilatorAt(ctx context.Context, roomname string, start time.Time) (*model.Teacher, error) {
-	all, err := p.dbClient.GetAllInvigilations(ctx)
-	if err != nil {
-		return nil, err
-	}
-	for _, inv := range all {
-		if inv.Starttime == nil || !inv.Starttime.Equal(start) {
-			continue
-		}
-		if inv.RoomName != nil && *inv.RoomName == roomname {
-			return p.dbClient.GetTeacher(ctx, inv.InvigilatorID)
-		}
-	}
-	return nil, nil
-}
-
 func (p *Plexams) InvigilatorsWithReq(ctx context.Context) ([]*model.Invigilator, error) {
 	teachers, err := p.getInvigilators(ctx)
 	if err != nil {
@@ -215,14 +198,22 @@ func (p *Plexams) buildInvigilator(ctx context.Context, teacher *model.Teacher,
 
 	// Additional per-invigilator constraints come from the DB (managed via the
 	// GUI), merged on top of the ZPA requirements.
+	// Weekly windows are expanded to the exam days, so everything downstream only
+	// sees date windows.
+	loc, _ := time.LoadLocation("Europe/Berlin")
 	var timeWindows []*model.InvigilationTimeWindow
+	var weeklyTimeWindows []*model.WeeklyInvigilationTimeWindow
 	if constraints != nil {
-		timeWindows = constraints.TimeWindows
+		days := make([]time.Time, 0, len(p.semesterConfig.Days))
+		for _, day := range p.semesterConfig.Days {
+			days = append(days, day.Date)
+		}
+		timeWindows = expandWeeklyTimeWindows(days, constraints.TimeWindows, constraints.WeeklyTimeWindows, loc)
+		weeklyTimeWindows = constraints.WeeklyTimeWindows
 	}
 
 	// ExcludedDates: the (≤3) whole days from the ZPA (stored as "02.01.06"
 	// strings) plus the additional ones from the DB constraints.
-	loc, _ := time.LoadLocation("Europe/Berlin")
 	excludedDates := make([]*time.Time, 0, len(reqs.ExcludedDates))
 	for _, day := range reqs.ExcludedDates {
 		t, err := time.ParseInLocation("02.01.06", day, loc)
@@ -307,6 +298,7 @@ func (p *Plexams) buildInvigilator(ctx context.Context, teacher *model.Teacher,
 			Factor:                 factor,
 			FromZpa:                fromZPA,
 			TimeWindows:            timeWindows,
+			WeeklyTimeWindows:      weeklyTimeWindows,
 		},
 	}, nil
 }
@@ -358,6 +350,12 @@ func (p *Plexams) PrepareInvigilationTodos(ctx context.Context) (*model.Invigila
 
 	todos := model.InvigilationTodos{}
 
+	staffing, err := p.newRoomStaffing(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot get staffing rules")
+		return nil, err
+	}
+
 	for _, slot := range p.semesterConfig.Slots {
 		roomsInSlot, err := p.plannedRoomsAt(ctx, slot.Starttime)
 		if err != nil {
@@ -367,27 +365,31 @@ func (p *Plexams) PrepareInvigilationTodos(ctx context.Context) (*model.Invigila
 			if len(roomsInSlot) == 0 {
 				continue
 			}
+			// every invigilator of a room (staffing rules) does the room's full
+			// duration; a self-invigilation covers one of them.
+			needed := staffing.needed(roomsInSlot)
 			roomMap := make(map[string]int)
-		OUTER:
 			for _, room := range roomsInSlot {
+				maxDuration, ok := roomMap[room.RoomName]
+				if !ok || maxDuration < room.Duration {
+					roomMap[room.RoomName] = room.Duration
+				}
+			}
+
+			for name, maxDuration := range roomMap {
+				seats := needed[name]
 				for _, selfInvigilation := range selfInvigilations {
 					if selfInvigilation.Starttime != nil &&
 						selfInvigilation.Starttime.Equal(slot.Starttime) &&
 						selfInvigilation.RoomName != nil &&
-						*selfInvigilation.RoomName == room.RoomName {
-						log.Debug().Time("start", slot.Starttime).Str("room", room.RoomName).
+						*selfInvigilation.RoomName == name {
+						log.Debug().Time("start", slot.Starttime).Str("room", name).
 							Msg("found self invigilation")
-						continue OUTER
+						seats--
+						break
 					}
 				}
-				maxDuration, ok := roomMap[room.RoomName]
-				if !ok || maxDuration < room.Duration {
-					roomMap[room.RoomName] = room.Duration
-				}
-			}
-
-			for _, maxDuration := range roomMap {
-				todos.SumExamRooms += maxDuration
+				todos.SumExamRooms += seats * maxDuration
 			}
 			todos.SumReserve += 60 // FIXME: Maybe some other time? half of max duration in slot?
 		}
@@ -481,6 +483,27 @@ func (p *Plexams) AddInvigilatorsToInvigilationTodos(ctx context.Context, todos
 
 func (p *Plexams) InvigilatorsForDay(ctx context.Context, date time.Time) (*model.InvigilatorsForDay, error) {
 	day := p.dayNumberForDate(date)
+	// one position per slot with exams, over its shortest exam: an unavailable window
+	// overlapping it overlaps every position of the slot
+	slots := make([]invigplan.Position, 0)
+	for _, slot := range p.semesterConfig.Slots {
+		if !sameDay(slot.Starttime.Local(), date.Local()) {
+			continue
+		}
+		rooms, err := p.PlannedRoomsInSlot(ctx, slot.Starttime)
+		if err != nil {
+			return nil, err
+		}
+		shortest := 0
+		for _, room := range rooms {
+			if room.Duration > 0 && (shortest == 0 || room.Duration < shortest) {
+				shortest = room.Duration
+			}
+		}
+		if shortest > 0 {
+			slots = append(slots, invigplan.Position{Start: slot.Starttime, Block: shortest})
+		}
+	}
 	invigilationTodos, err := p.dbClient.GetInvigilationTodos(ctx)
 	if err != nil {
 		log.Error().Err(err).Msg("cannot get invigilation todos")
@@ -491,7 +514,7 @@ func (p *Plexams) InvigilatorsForDay(ctx context.Context, date time.Time) (*mode
 	can := make([]*model.Invigilator, 0)
 
 	for _, invigilator := range invigilationTodos.Invigilators {
-		wantDay, canDay := dayOkForInvigilator(day, invigilator)
+		wantDay, canDay := dayOkForInvigilator(day, date, slots, invigilator)
 		if wantDay {
 			want = append(want, invigilator)
 		} else if canDay {
@@ -505,7 +528,10 @@ func (p *Plexams) InvigilatorsForDay(ctx context.Context, date time.Time) (*mode
 	}, nil
 }
 
-func dayOkForInvigilator(day int, invigilator *model.Invigilator) (wantDay, canDay bool) {
+// dayOkForInvigilator reports whether the invigilator wants (own exam or invigilation
+// that day) or can invigilate on the day. An unavailable window blocking the whole day
+// (see unavailableAllDay) excludes it like an excluded day.
+func dayOkForInvigilator(day int, date time.Time, slots []invigplan.Position, invigilator *model.Invigilator) (wantDay, canDay bool) {
 	// day in exlude days?
 	if invigilator.Requirements != nil {
 		for _, excludedDay := range invigilator.Requirements.ExcludedDays {
@@ -513,6 +539,9 @@ func dayOkForInvigilator(day int, invigilator *model.Invigilator) (wantDay, canD
 				return false, false
 			}
 		}
+		if unavailableAllDay(date, slots, invigilator.Requirements.TimeWindows) {
+			return false, false
+		}
 		for _, examDay := range invigilator.Requirements.ExamDays {
 			if day == examDay {
 				return true, true
@@ -658,7 +687,7 @@ func (p *Plexams) MakeSelfInvigilations(ctx context.Context) ([]*model.Invigilat
 	return invigilations, nil
 }
 
-// GetInvigilatorForRoom returns the invigilator assigned to a room at the given
+// GetInvigilatorForRoom returns the (lead) invigilator assigned to a room at the given
 // absolute start time (nil when none is assigned).
 func (p *Plexams) GetInvigilatorForRoom(ctx context.Context, name string, start time.Time) (*model.Teacher, error) {
 	return p.dbClient.GetInvigilatorAt(ctx, name, start)
@@ -724,12 +753,23 @@ func (p *Plexams) RoomsWithInvigilationsForSlot(ctx context.Context, starttime t
 	}
 	sort.Strings(keys)
 
+	staffing, err := p.newRoomStaffing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	needed := staffing.needed(rooms)
+
 	for _, name := range keys {
 		roomsForExam := roomMap[name]
-		invigilator, err := p.GetInvigilatorForRoom(ctx, name, starttime)
+		invigilators, err := p.dbClient.GetInvigilatorsAt(ctx, name, starttime)
 		if err != nil {
 			log.Error().Err(err).Time("starttime", starttime).Str("room", name).
 				Msg("cannot get invigilator for rooms in slot")
+			invigilators = []*model.Teacher{}
+		}
+		var invigilator *model.Teacher
+		if len(invigilators) > 0 {
+			invigilator = invigilators[0]
 		}
 
 		roomAndExams := make([]*model.RoomAndExam, 0)
@@ -753,12 +793,14 @@ func (p *Plexams) RoomsWithInvigilationsForSlot(ctx context.Context, starttime t
 		}
 
 		slot.RoomsWithInvigilators = append(slot.RoomsWithInvigilators, &model.RoomWithInvigilator{
-			Name:         name,
-			MaxDuration:  maxDuration,
-			StudentCount: studentCount,
-			RoomAndExams: roomAndExams,
-			Invigilator:  invigilator,
-			PrePlanned:   prePlannedRooms[name],
+			Name:               name,
+			MaxDuration:        maxDuration,
+			StudentCount:       studentCount,
+			RoomAndExams:       roomAndExams,
+			Invigilator:        invigilator,
+			Invigilators:       invigilators,
+			PrePlanned:         prePlannedRooms[name],
+			InvigilatorsNeeded: needed[name],
 		})
 	}
 	return slot, nil
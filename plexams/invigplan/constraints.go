@@ -37,9 +37,11 @@ type Registry struct {
 	Soft []SoftConstraint
 }
 
-// DefaultRegistry returns the constraints in their intended order.
-func DefaultRegistry() *Registry {
-	return &Registry{
+// DefaultRegistry returns the constraints in their intended order, followed by one soft
+// constraint per user-defined rule (see Rule). The rules must be evaluated for the Problem
+// the registry is used with.
+func DefaultRegistry(rules ...Rule) *Registry {
+	reg := &Registry{
 		Hard: []HardConstraint{
 			availabilityHard{},
 			timeWindowHard{},
@@ -57,6 +59,10 @@ func DefaultRegistry() *Registry {
 			daySpanSoft{},
 		},
 	}
+	for i := range rules {
+		reg.Soft = append(reg.Soft, ruleSoft{rule: &rules[i]})
+	}
+	return reg
 }
 
 // Allows reports whether assigning posIdx to invigID violates no hard
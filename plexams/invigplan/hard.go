@@ -41,7 +41,8 @@ func (c availabilityHard) Check(p *Problem, plan *Plan) []Violation {
 }
 
 // timeWindowHard: a person may only take an invigilation that fits the
-// per-date time windows they configured (semester.yaml timeWindows). The check
+// per-date time windows of their constraints (weekly windows arrive expanded to
+// dates) and overlaps none of their unavailable windows. The check
 // uses the position's real start and end time, so an invigilation running long
 // because of an NTA extension is rejected if it would finish after the allowed
 // "until" – even when a normal-length invigilation in the same slot still fits.
@@ -159,7 +160,8 @@ func (c oneInvigilationPerSlotHard) Check(p *Problem, plan *Plan) []Violation {
 }
 
 // timeGapHard: two invigilations of the same person need at least TimelagMin
-// minutes between the end of the earlier and the start of the later one.
+// minutes between the end of the earlier and the start of the later one, plus the
+// travel time when the two rooms are on different campuses.
 type timeGapHard struct{}
 
 func (timeGapHard) Name() string { return "time-gap" }
@@ -172,7 +174,7 @@ func (timeGapHard) Allows(p *Problem, plan *Plan, posIdx, invigID int) bool {
 			continue
 		}
 		op := p.Positions[other]
-		if !gapOK(cand, op, lag) {
+		if !p.gapOK(cand, op, lag) {
 			return false
 		}
 	}
@@ -186,13 +188,13 @@ func (c timeGapHard) Check(p *Problem, plan *Plan) []Violation {
 		for i := 0; i < len(positions); i++ {
 			for j := i + 1; j < len(positions); j++ {
 				a, b := p.Positions[positions[i]], p.Positions[positions[j]]
-				if !gapOK(a, b, lag) {
+				if !p.gapOK(a, b, lag) {
 					vs = append(vs, Violation{
 						Constraint:    c.Name(),
 						InvigilatorID: invigID,
 						Start:         a.Start,
 						Message: fmt.Sprintf("invigilator %d: less than %d min between %s and %s",
-							invigID, p.TimelagMin, a.Start.Format("02.01. 15:04"), b.Start.Format("02.01. 15:04")),
+							invigID, p.TimelagMin+p.travelMinutes(a, b), a.Start.Format("02.01. 15:04"), b.Start.Format("02.01. 15:04")),
 					})
 				}
 			}
@@ -201,9 +203,10 @@ func (c timeGapHard) Check(p *Problem, plan *Plan) []Violation {
 	return vs
 }
 
-// gapOK reports whether two positions are far enough apart in time. Positions on
-// different calendar days never conflict.
-func gapOK(a, b Position, lag time.Duration) bool {
+// gapOK reports whether two positions are far enough apart in time: lag plus the
+// travel time between their campuses. Positions on different calendar days never
+// conflict.
+func (p *Problem) gapOK(a, b Position, lag time.Duration) bool {
 	if dateKey(a.Start) != dateKey(b.Start) {
 		return true
 	}
@@ -211,5 +214,15 @@ func gapOK(a, b Position, lag time.Duration) bool {
 	if later.Start.Before(earlier.Start) {
 		earlier, later = b, a
 	}
+	lag += time.Duration(p.travelMinutes(a, b)) * time.Minute
 	return !later.Start.Before(earlier.End().Add(lag))
 }
+
+// travelMinutes is the travel time between the campuses of two positions (0 on the
+// same campus or when one of them has none).
+func (p *Problem) travelMinutes(a, b Position) int {
+	if a.Campus == "" || b.Campus == "" || a.Campus == b.Campus {
+		return 0
+	}
+	return p.Travel[[2]string{a.Campus, b.Campus}]
+}
@@ -200,6 +200,44 @@ func TestTimeWindowHard(t *testing.T) {
 	}
 }
 
+func TestUnavailableTimeWindowHard(t *testing.T) {
+	pos := []Position{
+		{Room: "R1", Minutes: 90, Block: 90, Start: start(8, 0)},   // 08:00-09:30
+		{Room: "R1", Minutes: 90, Block: 90, Start: start(10, 30)}, // 10:30-12:00
+		{Room: "R1", Minutes: 90, Block: 90, Start: start(14, 0)},  // 14:00-15:30
+	}
+	p := &Problem{
+		Positions:    pos,
+		Invigilators: []Invigilator{{ID: 1, TargetMinutes: 300}},
+		Fixed:        map[int]int{},
+	}
+	// blocked 09:30-14:00: touching the bounds is fine, overlapping is not
+	p.Invigilators[0].TimeWindows = []DayTimeWindow{
+		{Date: start(0, 0), From: start(9, 30), Until: start(14, 0), Unavailable: true},
+	}
+	p.Prepare()
+	plan := NewPlan(p)
+
+	c := timeWindowHard{}
+	for posIdx, want := range []bool{true, false, true} {
+		if got := c.Allows(p, plan, posIdx, 1); got != want {
+			t.Errorf("position %d: allowed = %t, want %t", posIdx, got, want)
+		}
+	}
+
+	// an unavailable range also cuts into an available window of the same date
+	p.Invigilators[0].TimeWindows = []DayTimeWindow{
+		{Date: start(0, 0), From: start(8, 0)},
+		{Date: start(0, 0), Until: start(11, 0), Unavailable: true},
+	}
+	p.Prepare()
+	for posIdx, want := range []bool{false, false, true} {
+		if got := c.Allows(p, plan, posIdx, 1); got != want {
+			t.Errorf("combined windows, position %d: allowed = %t, want %t", posIdx, got, want)
+		}
+	}
+}
+
 func TestOwnExamHard(t *testing.T) {
 	p := newTestProblem()
 	p.Invigilators[0].OwnExamSlots = map[int64]bool{start(8, 0).Unix(): true}
@@ -229,6 +267,33 @@ func TestOnePerSlotHard(t *testing.T) {
 	}
 }
 
+func TestTwoSeatRoomNeedsTwoPeople(t *testing.T) {
+	// a large hall with two seats (staffing rules) next to a small room and the reserve
+	pos := []Position{
+		{Room: "R0.001", Seat: 0, Minutes: 90, Block: 90, Start: start(8, 0)},
+		{Room: "R0.001", Seat: 1, Minutes: 90, Block: 90, Start: start(8, 0)},
+		{Room: "R1", Minutes: 90, Block: 90, Start: start(8, 0)},
+		{IsReserve: true, Minutes: 60, Block: 90, Start: start(8, 0)},
+	}
+	p := &Problem{
+		Positions: pos,
+		Invigilators: []Invigilator{
+			{ID: 1, TargetMinutes: 90}, {ID: 2, TargetMinutes: 90},
+			{ID: 3, TargetMinutes: 90}, {ID: 4, TargetMinutes: 60},
+		},
+		Fixed: map[int]int{},
+	}
+	p.Prepare()
+
+	best, result := Optimize(p, DefaultRegistry(), DefaultOptions())
+	if result.Unfilled != 0 || best.Assign[0] == best.Assign[1] {
+		t.Fatalf("both seats must be filled by different people: %v", best.Assign)
+	}
+	if st := p.Stats(); st.AdditionalSeats != 1 || st.SumPositionMinutes != 330 {
+		t.Errorf("stats = %+v, want 1 additional seat credited with the full 90 min", st)
+	}
+}
+
 func TestTimeGapHard(t *testing.T) {
 	p := newTestProblem()
 	plan := NewPlan(p)
@@ -245,6 +310,31 @@ func TestTimeGapHard(t *testing.T) {
 	}
 }
 
+func TestTimeGapHardTravel(t *testing.T) {
+	p := newTestProblem()
+	p.TimelagMin = 10
+	p.Positions[0].Campus = "Lothstraße"
+	p.Positions[3].Campus = "Pasing"
+	p.Travel = map[[2]string]int{{"Lothstraße", "Pasing"}: 30, {"Pasing", "Lothstraße"}: 30}
+	plan := NewPlan(p)
+	plan.Set(0, 1) // 08:00-09:30 at Lothstraße
+
+	c := timeGapHard{}
+	// 09:45 at Pasing: 15 min < 10 min lag + 30 min travel.
+	if c.Allows(p, plan, 3, 1) {
+		t.Error("15 min are not enough to travel to another campus")
+	}
+	plan.Set(3, 1)
+	if vs := c.Check(p, plan); len(vs) != 1 {
+		t.Errorf("expected one time-gap violation, got %v", vs)
+	}
+	// the reserve has no campus: no travel time
+	plan.Clear(3)
+	if !c.Allows(p, plan, 4, 1) {
+		t.Error("the reserve needs no travel time")
+	}
+}
+
 func TestMinuteBalanceSoft(t *testing.T) {
 	p := newTestProblem()
 	plan := NewPlan(p)
@@ -325,6 +415,36 @@ func TestDistributionSoftEqualizes(t *testing.T) {
 	}
 }
 
+func TestRuleSoftInDefaultRegistry(t *testing.T) {
+	p := newTestProblem()
+	// "no reserve for invigilator 1": 500 per reserve position taken by 1
+	rule := Rule{Name: "no-reserve-1", Cost: make([]map[int]float64, len(p.Positions))}
+	rule.Cost[2] = map[int]float64{1: 500}
+	rule.Cost[4] = map[int]float64{1: 500}
+	reg := DefaultRegistry(rule)
+
+	plan := NewPlan(p)
+	plan.Set(2, 1)
+	plan.Set(4, 2)
+	_, byConstraint, vs := reg.Cost(p, plan)
+	if got := byConstraint["rule:no-reserve-1"]; got != 500 {
+		t.Errorf("expected the rule to cost 500, got %g (%v)", got, byConstraint)
+	}
+	found := false
+	for _, v := range vs {
+		if v.Constraint == "rule:no-reserve-1" && v.InvigilatorID == 1 && v.Start.Equal(start(8, 0)) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a rule violation for invigilator 1 at 08:00, got %v", vs)
+	}
+
+	if _, without, _ := DefaultRegistry().Cost(p, plan); len(without) != len(byConstraint)-1 {
+		t.Errorf("a registry without rules must list one constraint less, got %v", without)
+	}
+}
+
 func TestRegistryAllowsCombinesHardConstraints(t *testing.T) {
 	p := newTestProblem()
 	p.Invigilators[0].ExcludedDays = map[int]bool{dateOrd(start(0, 0)): true}
@@ -523,3 +643,36 @@ func TestMinuteBalancePenalizesOverMore(t *testing.T) {
 		t.Fatalf("over-target (%g) should cost more than under-target (%g)", over, under)
 	}
 }
+
+func TestOptimizeCheckpointAndResume(t *testing.T) {
+	p := buildGridProblem(2, 2, 2, 6)
+	reg := DefaultRegistry()
+	opts := DefaultOptions()
+	opts.Iterations = 20_000
+	opts.StopOnBalance = false
+	opts.CheckpointEvery = 5_000
+	var cps []Checkpoint
+	opts.Checkpoint = func(cp Checkpoint) { cps = append(cps, cp) }
+	_, full := Optimize(p, reg, opts)
+	if len(cps) != 4 || cps[3].Iteration != 20_000 || cps[3].BestCost != full.Cost {
+		t.Fatalf("expected 3 periodic + 1 final checkpoint matching the result, got %+v", cps)
+	}
+
+	opts.Checkpoint = nil
+	opts.Resume = &cps[1]
+	best, res := Optimize(p, reg, opts)
+	if hv := reg.HardViolations(p, best); len(hv) != 0 {
+		t.Fatalf("resumed plan must stay hard-feasible, got %v", hv)
+	}
+	if res.Cost > cps[1].BestCost {
+		t.Errorf("resumed run must not end worse than its checkpoint: %v > %v", res.Cost, cps[1].BestCost)
+	}
+
+	if _, err := PlanFromAssign(p, cps[1].Assign[:3]); err == nil {
+		t.Error("an assignment of another problem must be rejected")
+	}
+	other := buildGridProblem(2, 2, 2, 7)
+	if p.Fingerprint() == other.Fingerprint() || p.Fingerprint() != buildGridProblem(2, 2, 2, 6).Fingerprint() {
+		t.Error("fingerprint must identify the problem content")
+	}
+}
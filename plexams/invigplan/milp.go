@@ -0,0 +1,334 @@
+package invigplan
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/obcode/plexams.go/plexams/milp"
+)
+
+// MILP is the invigilation problem (or a part of it) as a mixed-integer linear program
+// for an external exact solver. x[pos][invig] is 1 when the invigilator takes the
+// position; only pairs allowed by availability, time windows and own exams get a
+// variable, and one-per-slot plus the time gap become pairwise/clique constraints.
+//
+// The soft objective is the DefaultRegistry's, linearized exactly: coverage, the minute
+// balance (centering inside the band and the dominant beyond-tolerance term), max days,
+// own-exam days, the reserve/NTA distribution (squares as tangent cuts at the
+// integer points) and the user-defined rules. The day span is not modelled; the imported
+// plan is scored with the full registry anyway.
+type MILP struct {
+	Model *milp.Model
+
+	prob *Problem
+	base *Plan
+	free []bool
+	x    map[[2]int]int // (position, invigilator ID) → variable
+}
+
+// BuildMILP builds the program for the positions free selects (nil = all). The other
+// positions keep their invigilator from base (nil = only the fixed positions), so a
+// single day can be solved against the rest of the plan. Fixed positions are never free.
+// rules are the user-defined rules of the registry the plan is scored with.
+func BuildMILP(p *Problem, base *Plan, free func(posIdx int) bool, rules ...Rule) *MILP {
+	if base == nil {
+		base = NewPlan(p)
+	}
+	m := &MILP{
+		Model: milp.New("plexams invigilation plan"),
+		prob:  p,
+		base:  base,
+		free:  make([]bool, len(p.Positions)),
+		x:     make(map[[2]int]int),
+	}
+	m.Model.Comments = []string{
+		"x_<position>_<invigilator> = 1: the invigilator takes the position.",
+		"Not modelled: day span (max hours per day).",
+	}
+	for pos := range p.Positions {
+		_, fixed := p.Fixed[pos]
+		m.free[pos] = !fixed && (free == nil || free(pos))
+	}
+
+	lag := time.Duration(p.TimelagMin) * time.Minute
+	for pos := range p.Positions {
+		if !m.free[pos] {
+			if base.Assign[pos] == Unassigned {
+				m.Model.Offset += p.Weights.Coverage
+			}
+			for r := range rules {
+				m.Model.Offset += rules[r].cost(pos, base.Assign[pos])
+			}
+			continue
+		}
+		var terms []milp.Term
+		for i := range p.Invigilators {
+			in := &p.Invigilators[i]
+			if !m.allowed(pos, in, lag) {
+				continue
+			}
+			v := m.Model.Binary(fmt.Sprintf("x_%d_%d", pos, in.ID))
+			m.x[[2]int{pos, in.ID}] = v
+			terms = append(terms, milp.T(v, 1))
+			coef := -p.Weights.Coverage
+			for r := range rules {
+				coef += rules[r].cost(pos, in.ID)
+			}
+			m.Model.Minimize(v, coef)
+		}
+		m.Model.Add(fmt.Sprintf("pos_%d", pos), milp.LE, 1, terms...)
+		m.Model.Offset += p.Weights.Coverage
+	}
+
+	for i := range p.Invigilators {
+		in := &p.Invigilators[i]
+		cand := m.candidates(in.ID)
+		m.addConflicts(in.ID, cand, lag)
+		m.addMinutes(in, cand)
+		m.addDays(in, cand)
+		m.addDistribution(in, cand, KindReserve)
+		m.addDistribution(in, cand, KindNTA)
+	}
+	return m
+}
+
+// allowed reports whether the invigilator may take the free position given the hard
+// constraints that do not depend on the other free positions.
+func (m *MILP) allowed(pos int, in *Invigilator, lag time.Duration) bool {
+	position := m.prob.Positions[pos]
+	if !in.Available(position) || !in.AllowsTime(position) || in.OwnExamSlots[position.SlotKey()] {
+		return false
+	}
+	for _, other := range m.base.Positions(in.ID) {
+		if m.free[other] {
+			continue
+		}
+		op := m.prob.Positions[other]
+		if op.Start.Equal(position.Start) || !m.prob.gapOK(position, op, lag) {
+			return false
+		}
+	}
+	return true
+}
+
+// candidates returns the free positions the invigilator has a variable for, sorted.
+func (m *MILP) candidates(invigID int) []int {
+	var out []int
+	for k := range m.x {
+		if k[1] == invigID {
+			out = append(out, k[0])
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+// constant returns the non-free positions of the invigilator.
+func (m *MILP) constant(invigID int) []int {
+	var out []int
+	for _, pos := range m.base.Positions(invigID) {
+		if !m.free[pos] {
+			out = append(out, pos)
+		}
+	}
+	return out
+}
+
+// addConflicts: at most one position per start time (a clique) and no two positions
+// closer than the time gap.
+func (m *MILP) addConflicts(invigID int, cand []int, lag time.Duration) {
+	p := m.prob
+	byStart := make(map[int64][]milp.Term)
+	var starts []int64
+	for _, pos := range cand {
+		k := p.Positions[pos].SlotKey()
+		if _, ok := byStart[k]; !ok {
+			starts = append(starts, k)
+		}
+		byStart[k] = append(byStart[k], milp.T(m.x[[2]int{pos, invigID}], 1))
+	}
+	sort.Slice(starts, func(a, b int) bool { return starts[a] < starts[b] })
+	for _, k := range starts {
+		if len(byStart[k]) > 1 {
+			m.Model.Add(fmt.Sprintf("slot_%d_%d", invigID, k), milp.LE, 1, byStart[k]...)
+		}
+	}
+	for a := 0; a < len(cand); a++ {
+		for b := a + 1; b < len(cand); b++ {
+			pa, pb := p.Positions[cand[a]], p.Positions[cand[b]]
+			if pa.Start.Equal(pb.Start) || p.gapOK(pa, pb, lag) {
+				continue
+			}
+			m.Model.Add(fmt.Sprintf("gap_%d_%d_%d", invigID, cand[a], cand[b]), milp.LE, 1,
+				milp.T(m.x[[2]int{cand[a], invigID}], 1), milp.T(m.x[[2]int{cand[b], invigID}], 1))
+		}
+	}
+}
+
+// addMinutes linearizes minuteBalanceSoft: doing − target = dp − dm + over − under with
+// dp, dm ∈ [0, tolerance]. Since BeyondTolerance dominates the centering weight, an
+// optimal solution fills dp/dm up to the tolerance before it uses over/under.
+func (m *MILP) addMinutes(in *Invigilator, cand []int) {
+	p := m.prob
+	doing := 0
+	for _, pos := range m.constant(in.ID) {
+		doing += p.Positions[pos].Minutes
+	}
+	tol := float64(p.ToleranceMin)
+	scale := float64(max(in.TargetMinutes, p.ToleranceMin))
+	dp := m.Model.Continuous(fmt.Sprintf("dp_%d", in.ID), 0, tol)
+	dm := m.Model.Continuous(fmt.Sprintf("dm_%d", in.ID), 0, tol)
+	over := m.Model.NonNegative(fmt.Sprintf("over_%d", in.ID))
+	under := m.Model.NonNegative(fmt.Sprintf("under_%d", in.ID))
+	terms := []milp.Term{milp.T(dp, -1), milp.T(dm, 1), milp.T(over, -1), milp.T(under, 1)}
+	for _, pos := range cand {
+		if minutes := p.Positions[pos].Minutes; minutes != 0 {
+			terms = append(terms, milp.T(m.x[[2]int{pos, in.ID}], float64(minutes)))
+		}
+	}
+	m.Model.Add(fmt.Sprintf("minutes_%d", in.ID), milp.EQ, float64(in.TargetMinutes-doing), terms...)
+	m.Model.Minimize(dp, p.Weights.MinuteBalance*p.Weights.OverTargetFactor/scale)
+	m.Model.Minimize(dm, p.Weights.MinuteBalance/scale)
+	m.Model.Minimize(over, p.Weights.BeyondTolerance)
+	m.Model.Minimize(under, p.Weights.BeyondTolerance)
+}
+
+// addDays models the presence days for maxDaysSoft and preferOwnExamDaysSoft: a binary
+// per day the invigilator could newly be present on.
+func (m *MILP) addDays(in *Invigilator, cand []int) {
+	p := m.prob
+	present := make(map[int]bool) // days with a constant invigilation or a (non-excluded) own exam
+	invigDays := make(map[int]bool)
+	for _, pos := range m.constant(in.ID) {
+		d := dateKey(p.Positions[pos].Start)
+		present[d] = true
+		invigDays[d] = true
+	}
+	examDays := 0
+	for d := range in.OwnExamDays {
+		if !in.ExcludedDays[d] {
+			present[d] = true
+			examDays++
+		}
+	}
+	if len(in.OwnExamDays) > 0 {
+		for d := range invigDays {
+			if !in.OwnExamDays[d] {
+				m.Model.Offset += p.Weights.PreferExamDays
+			}
+		}
+	}
+
+	byDay := make(map[int][]int)
+	var days []int
+	for _, pos := range cand {
+		d := dateKey(p.Positions[pos].Start)
+		if invigDays[d] {
+			continue // already an invigilation day: no extra cost either way
+		}
+		if _, ok := byDay[d]; !ok {
+			days = append(days, d)
+		}
+		byDay[d] = append(byDay[d], pos)
+	}
+	sort.Ints(days)
+	var newDays []milp.Term
+	for _, d := range days {
+		prefer := len(in.OwnExamDays) > 0 && !in.OwnExamDays[d]
+		if !prefer && present[d] {
+			continue // an own exam day: neither a new presence day nor a preference cost
+		}
+		y := m.Model.Binary(fmt.Sprintf("day_%d_%d", in.ID, d))
+		for _, pos := range byDay[d] {
+			m.Model.Add(fmt.Sprintf("onday_%d_%d", in.ID, pos), milp.LE, 0, milp.T(m.x[[2]int{pos, in.ID}], 1), milp.T(y, -1))
+		}
+		if prefer {
+			m.Model.Minimize(y, p.Weights.PreferExamDays)
+		}
+		if !present[d] {
+			newDays = append(newDays, milp.T(y, 1))
+		}
+	}
+
+	limit := max(maxAttendanceDays, examDays)
+	excess0 := len(present) - limit // excess without any new day
+	if excess0+len(newDays) <= 0 {
+		return
+	}
+	if len(newDays) == 0 {
+		m.Model.Offset += p.Weights.MaxDays * float64(excess0*excess0)
+		return
+	}
+	// excess ≥ present + Σ newDays − limit; cost ≥ MaxDays · excess² via the tangents
+	// through consecutive integer points, exact at integer excess.
+	excess := m.Model.NonNegative(fmt.Sprintf("excess_%d", in.ID))
+	m.Model.Add(fmt.Sprintf("excessdef_%d", in.ID), milp.GE, float64(excess0), append([]milp.Term{milp.T(excess, 1)}, negate(newDays)...)...)
+	cost := m.Model.NonNegative(fmt.Sprintf("maxdays_%d", in.ID))
+	for k := max(0, excess0); k < excess0+len(newDays); k++ {
+		m.Model.Add(fmt.Sprintf("maxdays_%d_%d", in.ID, k), milp.GE, -p.Weights.MaxDays*float64(k*(k+1)),
+			milp.T(cost, 1), milp.T(excess, -p.Weights.MaxDays*float64(2*k+1)))
+	}
+	m.Model.Minimize(cost, 1)
+}
+
+// addDistribution linearizes distributionSoft for one kind: Distribution · count².
+func (m *MILP) addDistribution(in *Invigilator, cand []int, kind Kind) {
+	p := m.prob
+	c0 := 0
+	for _, pos := range m.constant(in.ID) {
+		if p.Positions[pos].Kind() == kind {
+			c0++
+		}
+	}
+	var terms []milp.Term
+	for _, pos := range cand {
+		if p.Positions[pos].Kind() == kind {
+			terms = append(terms, milp.T(m.x[[2]int{pos, in.ID}], 1))
+		}
+	}
+	if len(terms) == 0 {
+		m.Model.Offset += p.Weights.Distribution * float64(c0*c0)
+		return
+	}
+	cost := m.Model.NonNegative(fmt.Sprintf("dist_%s_%d", kind, in.ID))
+	for k := c0; k < c0+len(terms); k++ {
+		cut := []milp.Term{milp.T(cost, 1)}
+		for _, t := range terms {
+			cut = append(cut, milp.T(t.Var, -p.Weights.Distribution*float64(2*k+1)))
+		}
+		rhs := p.Weights.Distribution * (float64(2*k+1)*float64(c0) - float64(k*(k+1)))
+		m.Model.Add(fmt.Sprintf("dist_%s_%d_%d", kind, in.ID, k), milp.GE, rhs, cut...)
+	}
+	m.Model.Minimize(cost, 1)
+}
+
+// Plan maps a solution back to a plan: the free positions as the solver assigned them,
+// all others as in base. The caller validates it with Registry.HardViolations.
+func (m *MILP) Plan(sol milp.Solution) (*Plan, error) {
+	plan := NewPlan(m.prob)
+	for pos, invigID := range m.base.Assign {
+		if !m.free[pos] && invigID != Unassigned {
+			plan.Set(pos, invigID)
+		}
+	}
+	for k, v := range m.x {
+		if !sol.On(v) {
+			continue
+		}
+		pos, invigID := k[0], k[1]
+		if plan.Assign[pos] != Unassigned {
+			return nil, fmt.Errorf("position %d is assigned to both invigilator %d and %d", pos, plan.Assign[pos], invigID)
+		}
+		plan.Set(pos, invigID)
+	}
+	return plan, nil
+}
+
+func negate(terms []milp.Term) []milp.Term {
+	out := make([]milp.Term, len(terms))
+	for i, t := range terms {
+		out[i] = milp.T(t.Var, -t.Coef)
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package invigplan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obcode/plexams.go/plexams/milp"
+)
+
+func TestMILPVariablesFollowHardConstraints(t *testing.T) {
+	p := newTestProblem()
+	p.Invigilators[0].OwnExamSlots = map[int64]bool{start(8, 0).Unix(): true}
+	p.Prepare()
+	m := BuildMILP(p, nil, nil)
+
+	if _, ok := m.Model.Lookup("x_0_1"); ok {
+		t.Error("invigilator 1 has an own exam at 08:00 and must not get a variable there")
+	}
+	if _, ok := m.Model.Lookup("x_0_2"); !ok {
+		t.Error("invigilator 2 should get a variable for position 0")
+	}
+	found := false
+	for _, c := range m.Model.Constraints {
+		if strings.HasPrefix(c.Name, "gap_2_") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected time-gap constraints for invigilator 2 (15 min gap < 20 min lag)")
+	}
+}
+
+func TestMILPPlanRoundtrip(t *testing.T) {
+	p := newTestProblem()
+	m := BuildMILP(p, nil, nil)
+
+	sol, err := milp.ReadSolution(strings.NewReader("x_0_2 1\nx_3_1 1\nx_4_2 0\n"), m.Model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := m.Plan(sol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Assign[0] != 2 || plan.Assign[3] != 1 || plan.Assign[4] != Unassigned {
+		t.Fatalf("unexpected plan %v", plan.Assign)
+	}
+	if hard := DefaultRegistry().HardViolations(p, plan); len(hard) != 0 {
+		t.Fatalf("expected no hard violations, got %v", hard)
+	}
+
+	dup, err := milp.ReadSolution(strings.NewReader("x_0_1 1\nx_0_2 1\n"), m.Model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Plan(dup); err == nil {
+		t.Error("a position assigned twice must be rejected")
+	}
+}
+
+func TestMILPKeepsNonFreePositions(t *testing.T) {
+	p := newTestProblem()
+	base := NewPlan(p)
+	base.Set(0, 2) // 08:00, kept
+
+	// only the 09:45 slot is free
+	m := BuildMILP(p, base, func(pos int) bool { return pos >= 3 })
+	if _, ok := m.Model.Lookup("x_0_1"); ok {
+		t.Error("positions outside the free set must not get variables")
+	}
+	if _, ok := m.Model.Lookup("x_3_2"); ok {
+		t.Error("invigilator 2 keeps 08:00 and cannot take 09:45 (time gap)")
+	}
+
+	sol, err := milp.ReadSolution(strings.NewReader("x_3_1 1\n"), m.Model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := m.Plan(sol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Assign[0] != 2 || plan.Assign[3] != 1 {
+		t.Fatalf("unexpected plan %v", plan.Assign)
+	}
+}
+
+func TestMILPRuleCosts(t *testing.T) {
+	p := newTestProblem()
+	rule := Rule{Name: "r", Cost: make([]map[int]float64, len(p.Positions))}
+	rule.Cost[0] = map[int]float64{2: 700}
+	m := BuildMILP(p, nil, nil, rule)
+
+	plain, ok := m.Model.Lookup("x_0_1")
+	if !ok {
+		t.Fatal("expected a variable for invigilator 1 at position 0")
+	}
+	ruled, _ := m.Model.Lookup("x_0_2")
+	coef := func(v int) float64 {
+		var c float64
+		for _, t := range m.Model.Objective {
+			if t.Var == v {
+				c += t.Coef
+			}
+		}
+		return c
+	}
+	if got := coef(ruled) - coef(plain); got != 700 {
+		t.Errorf("the rule should add 700 to x_0_2, got %g", got)
+	}
+}
@@ -9,7 +9,12 @@
 // adding a constraint and registering it – nothing else changes.
 package invigplan
 
-import "time"
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+)
 
 // Unassigned marks a position that currently has no invigilator.
 const Unassigned = -1
@@ -37,15 +42,19 @@ func (k Kind) String() string {
 // Position is one invigilation that has to be filled: a room in a slot, or the
 // reserve of a slot. Self-invigilations are modelled as positions too, but are
 // always part of Problem.Fixed and therefore never moved by the optimizer.
+// A room staffed by several invigilators (see the staffing rules) has one
+// position per invigilator, distinguished by Seat.
 type Position struct {
 	Room      string // "" for the reserve
+	Seat      int    // 0 for the (lead) invigilator of a room, 1.. for additional ones
 	IsReserve bool
 	IsNTA     bool
 	IsSelf    bool // self-invigilation (examiner supervising their own exam)
 
 	// Minutes counts toward the invigilator's minute contingent. It is 0 for
-	// self-invigilations, the exam duration for a normal room and (per the
-	// existing model) 60 for a reserve.
+	// self-invigilations, the exam duration for a normal room (for every seat of
+	// a room with several invigilators) and (per the existing model) 60 for a
+	// reserve.
 	Minutes int
 
 	// Block is the real time the position occupies, used for the time-gap and
@@ -54,6 +63,10 @@ type Position struct {
 	Block int
 
 	Start time.Time
+
+	// Campus is where the room is ("" for the reserve and rooms without a campus: no
+	// travel time to or from them).
+	Campus string
 }
 
 // End returns the time the position is over (Start + Block).
@@ -121,10 +134,15 @@ type Invigilator struct {
 // duration. Several windows may share a date – then the position only has to fit
 // into one of them (see AllowsTime), e.g. 08:00–11:00 and 14:00–open to keep
 // 11:00–14:00 free.
+//
+// An Unavailable window is the opposite: the position must not overlap From–Until
+// (zero = start / end of the day). Unavailable windows are AND-combined with each
+// other and with the available ones.
 type DayTimeWindow struct {
-	Date  time.Time // calendar date the window applies to
-	From  time.Time // earliest allowed start; zero = no lower bound
-	Until time.Time // latest allowed end; zero = no upper bound
+	Date        time.Time // calendar date the window applies to
+	From        time.Time // earliest allowed start; zero = no lower bound
+	Until       time.Time // latest allowed end; zero = no upper bound
+	Unavailable bool
 }
 
 // fits reports whether the position lies completely inside this window.
@@ -138,23 +156,39 @@ func (w DayTimeWindow) fits(pos Position) bool {
 	return true
 }
 
+// overlaps reports whether the position shares any time with this window.
+func (w DayTimeWindow) overlaps(pos Position) bool {
+	if !w.From.IsZero() && !pos.End().After(w.From) {
+		return false
+	}
+	if !w.Until.IsZero() && !pos.Start.Before(w.Until) {
+		return false
+	}
+	return true
+}
+
 // AllowsTime reports whether the position fits the person's time windows. Only
-// windows on the position's calendar date are considered; if there are any, the
-// position must fit into at least one of them (windows on the same date are
+// windows on the position's calendar date are considered. The position must not
+// overlap any unavailable window; if there are available windows, it must fit
+// into at least one of them (available windows on the same date are
 // OR-combined). With no window for that date (or no windows at all) the position
 // is allowed.
 func (in *Invigilator) AllowsTime(pos Position) bool {
-	hasWindowForDate := false
+	hasWindowForDate, fitsWindow := false, false
 	for _, w := range in.TimeWindows {
 		if !sameDate(w.Date, pos.Start) {
 			continue
 		}
-		hasWindowForDate = true
-		if w.fits(pos) {
-			return true
+		if w.Unavailable {
+			if w.overlaps(pos) {
+				return false
+			}
+			continue
 		}
+		hasWindowForDate = true
+		fitsWindow = fitsWindow || w.fits(pos)
 	}
-	return !hasWindowForDate
+	return !hasWindowForDate || fitsWindow
 }
 
 // sameDate reports whether a and b fall on the same calendar day.
@@ -195,6 +229,10 @@ type Problem struct {
 	MaxSpanHours float64 // max hours from first start to last end per day (default 8)
 	Weights      Weights
 
+	// Travel holds the travel minutes between two campuses (both directions), added to
+	// TimelagMin between two invigilations on different campuses. nil: no travel times.
+	Travel map[[2]string]int
+
 	byID map[int]*Invigilator
 }
 
@@ -216,6 +254,31 @@ func (p *Problem) Prepare() {
 	}
 }
 
+// Fingerprint identifies the problem's content (positions, invigilators with their
+// restrictions, fixed assignments). A stored plan or checkpoint only fits a problem
+// with the same fingerprint: otherwise the position indices may mean something else or
+// the stored assignment may no longer be hard-feasible.
+func (p *Problem) Fingerprint() string {
+	h := fnv.New64a()
+	for _, pos := range p.Positions {
+		fmt.Fprintf(h, "%s|%d|%t|%t|%t|%d|%d|%d|%s;", pos.Room, pos.Seat, pos.IsReserve, pos.IsNTA, pos.IsSelf, pos.Minutes, pos.Block, pos.Start.Unix(), pos.Campus)
+	}
+	fmt.Fprintf(h, "%v;", p.Travel)
+	for _, inv := range p.Invigilators {
+		fmt.Fprintf(h, "%d|%d|%v|%v|%v|%v|%v|%v;", inv.ID, inv.TargetMinutes, inv.ExcludedDays, inv.ExcludedSlots,
+			inv.OwnExamSlots, inv.OwnExamDays, inv.OwnExams, inv.TimeWindows)
+	}
+	fixed := make([]int, 0, len(p.Fixed))
+	for pos := range p.Fixed {
+		fixed = append(fixed, pos)
+	}
+	sort.Ints(fixed)
+	for _, pos := range fixed {
+		fmt.Fprintf(h, "%d=%d;", pos, p.Fixed[pos])
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
 // Invigilator returns the invigilator with the given id, or nil.
 func (p *Problem) Invigilator(id int) *Invigilator {
 	return p.byID[id]
@@ -22,6 +22,13 @@ type Options struct {
 	StopOnBalance   bool
 	StagnationLimit int
 
+	// Checkpoint, if set, receives the best plan and the run position every
+	// CheckpointEvery iterations and once at the end, so the run can be persisted.
+	// Resume continues such a run instead of starting from the greedy plan.
+	Checkpoint      func(Checkpoint)
+	CheckpointEvery int
+	Resume          *Checkpoint
+
 	// OnProgress, if set, is called every ProgressEvery iterations with a
 	// snapshot of the current best plan. It is throttled on purpose: calling it
 	// per iteration would dominate the runtime with terminal I/O.
@@ -63,6 +70,17 @@ type Result struct {
 	StoppedEarly     bool
 }
 
+// Checkpoint is the persistable state of an optimizer run (see optimize.Checkpoint):
+// the best assignment so far (position index → invigilator ID) and where the run stands.
+type Checkpoint struct {
+	Iteration int
+	Total     int
+	BestCost  float64
+	BestIter  int
+	Assign    []int
+	Rand      optimize.RandState
+}
+
 // change records a single (position -> invigilator) reassignment so a move can
 // be undone after a rejected annealing step.
 type change struct {
@@ -75,9 +93,25 @@ type change struct {
 // annealing. Every intermediate plan stays hard-feasible (moves are only
 // applied when the registry allows them), so the result satisfies all hard
 // constraints; the soft constraints are traded off via the cost function.
+//
+// With opts.Resume the run continues from the checkpointed plan and random stream (the
+// caller guarantees the checkpoint belongs to this problem, see Problem.Fingerprint); a
+// checkpoint whose assignment does not fit the problem falls back to a fresh run.
 func Optimize(p *Problem, reg *Registry, opts Options) (*Plan, Result) {
-	rng := rand.New(rand.NewSource(opts.Seed)) //nolint:gosec // not security relevant
-	plan := Greedy(p, reg, rng)
+	rng, src := optimize.NewRand(optimize.RandState{Seed: opts.Seed})
+	var plan *Plan
+	var resume *optimize.Checkpoint
+	if opts.Resume != nil {
+		if pl, err := PlanFromAssign(p, opts.Resume.Assign); err == nil {
+			plan = pl
+			rng, src = optimize.NewRand(opts.Resume.Rand)
+			resume = &optimize.Checkpoint{Iteration: opts.Resume.Iteration, Total: opts.Resume.Total,
+				BestCost: opts.Resume.BestCost, BestIter: opts.Resume.BestIter, Best: plan, Rand: opts.Resume.Rand}
+		}
+	}
+	if plan == nil {
+		plan = Greedy(p, reg, rng)
+	}
 
 	movable := movablePositions(p)
 	result := Result{Iterations: opts.Iterations}
@@ -91,9 +125,21 @@ func Optimize(p *Problem, reg *Registry, opts Options) (*Plan, Result) {
 			StartTemp:         opts.StartTemp,
 			EndTemp:           opts.EndTemp,
 			Rng:               rng,
+			RandSource:        src,
 			StopWhenConverged: opts.StopOnBalance,
 			StagnationLimit:   opts.StagnationLimit,
 			ProgressEvery:     opts.ProgressEvery,
+			Resume:            resume,
+			CheckpointEvery:   opts.CheckpointEvery,
+		}
+		if opts.Checkpoint != nil {
+			oopts.Checkpoint = func(cp optimize.Checkpoint) {
+				best := cp.Best.(*Plan)
+				assign := make([]int, len(best.Assign))
+				copy(assign, best.Assign)
+				opts.Checkpoint(Checkpoint{Iteration: cp.Iteration, Total: cp.Total, BestCost: cp.BestCost,
+					BestIter: cp.BestIter, Assign: assign, Rand: cp.Rand})
+			}
 		}
 		if opts.OnProgress != nil && opts.ProgressEvery > 0 {
 			oopts.OnProgress = func(pr optimize.Progress) {
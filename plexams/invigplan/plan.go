@@ -1,6 +1,9 @@
 package invigplan
 
-import "sort"
+import (
+	"fmt"
+	"sort"
+)
 
 // Plan is the mutable assignment the optimizer searches over. Assign maps a
 // position index to an invigilator id (or Unassigned); byInvig is the inverse
@@ -27,6 +30,26 @@ func NewPlan(prob *Problem) *Plan {
 	return pl
 }
 
+// PlanFromAssign rebuilds a plan from a stored assignment (position index → invigilator
+// ID, Unassigned for open), e.g. a checkpoint. Fixed positions keep the problem's fixed
+// invigilator. The assignment must belong to this problem (see Problem.Fingerprint).
+func PlanFromAssign(prob *Problem, assign []int) (*Plan, error) {
+	if len(assign) != len(prob.Positions) {
+		return nil, fmt.Errorf("assignment has %d positions, problem has %d", len(assign), len(prob.Positions))
+	}
+	pl := NewPlan(prob)
+	for posIdx, invigID := range assign {
+		if invigID == Unassigned || pl.IsFixed(posIdx) {
+			continue
+		}
+		if prob.Invigilator(invigID) == nil {
+			return nil, fmt.Errorf("assignment references unknown invigilator %d", invigID)
+		}
+		pl.Set(posIdx, invigID)
+	}
+	return pl, nil
+}
+
 // IsFixed reports whether a position is locked (pre-planned or self).
 func (pl *Plan) IsFixed(posIdx int) bool {
 	_, ok := pl.prob.Fixed[posIdx]
@@ -249,6 +249,57 @@ func (c daySpanSoft) Cost(p *Problem, plan *Plan) (float64, []Violation) {
 	return penalty, vs
 }
 
+// Rule is a user-defined soft constraint (a planner's condition such as "Braun not as
+// reserve in the afternoon", see plexams/softrule). The optimizer knows nothing about the
+// condition: the caller evaluates it for every position and invigilator of one Problem,
+// and Cost[posIdx][invigID] is the (already weighted) penalty of the invigilator taking
+// the position. A nil map means the rule never applies to the position; fixed positions
+// should get nil maps (they cannot move anyway).
+type Rule struct {
+	Name string
+	Cost []map[int]float64
+}
+
+// cost is the penalty of the rule for invigID taking posIdx (0 when unassigned).
+func (r *Rule) cost(posIdx, invigID int) float64 {
+	if invigID == Unassigned || posIdx >= len(r.Cost) {
+		return 0
+	}
+	return r.Cost[posIdx][invigID]
+}
+
+// ruleSoft is one user-defined rule, listed under "rule:<name>".
+type ruleSoft struct {
+	rule *Rule
+}
+
+func (c ruleSoft) Name() string { return "rule:" + c.rule.Name }
+
+func (c ruleSoft) Cost(p *Problem, plan *Plan) (float64, []Violation) {
+	var penalty float64
+	var vs []Violation
+	for posIdx, invigID := range plan.Assign {
+		pen := c.rule.cost(posIdx, invigID)
+		if pen == 0 {
+			continue
+		}
+		pos := p.Positions[posIdx]
+		room := pos.Room
+		if pos.IsReserve {
+			room = "reserve"
+		}
+		penalty += pen
+		vs = append(vs, Violation{
+			Constraint:    c.Name(),
+			InvigilatorID: invigID,
+			Start:         pos.Start,
+			Penalty:       pen,
+			Message:       fmt.Sprintf("rule %q: invigilator %d in %s at %s", c.rule.Name, invigID, room, pos.Start.Format("02.01. 15:04")),
+		})
+	}
+	return penalty, vs
+}
+
 func abs(n int) int {
 	if n < 0 {
 		return -n
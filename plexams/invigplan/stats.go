@@ -74,6 +74,7 @@ type Stats struct {
 	NTARooms           int
 	Reserves           int
 	SelfPositions      int
+	AdditionalSeats    int // positions of second, third, ... invigilators of a room
 	FixedPositions     int
 	Invigilators       int
 	SumPositionMinutes int // minutes that have to be covered (self counts 0)
@@ -93,6 +94,9 @@ func (p *Problem) Stats() Stats {
 		if pos.IsSelf {
 			s.SelfPositions++
 		}
+		if pos.Seat > 0 {
+			s.AdditionalSeats++
+		}
 		switch pos.Kind() {
 		case KindReserve:
 			s.Reserves++
@@ -0,0 +1,86 @@
+package milp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// lpTermsPerLine keeps LP lines well below the 255-character limit of some readers.
+const lpTermsPerLine = 8
+
+// WriteLP writes the model in the CPLEX LP format.
+func (m *Model) WriteLP(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "\\ %s\n", m.Name)
+	for _, c := range m.Comments {
+		fmt.Fprintf(bw, "\\ %s\n", c)
+	}
+	fmt.Fprintf(bw, "\\ objective offset (not included below): %s\n", lpNumber(m.Offset))
+
+	bw.WriteString("Minimize\n obj:")
+	if len(m.Objective) == 0 && len(m.Vars) > 0 {
+		fmt.Fprintf(bw, " 0 %s", m.Vars[0].Name)
+	}
+	m.writeLPTerms(bw, m.Objective)
+	bw.WriteString("\nSubject To\n")
+	for i, c := range m.Constraints {
+		name := c.Name
+		if name == "" {
+			name = fmt.Sprintf("c%d", i)
+		}
+		fmt.Fprintf(bw, " %s:", name)
+		m.writeLPTerms(bw, c.Terms)
+		fmt.Fprintf(bw, " %s %s\n", c.Sense, lpNumber(c.RHS))
+	}
+
+	bw.WriteString("Bounds\n")
+	for _, v := range m.Vars {
+		if v.Type == Binary {
+			continue
+		}
+		if math.IsInf(v.Upper, 1) {
+			fmt.Fprintf(bw, " %s >= %s\n", v.Name, lpNumber(v.Lower))
+		} else {
+			fmt.Fprintf(bw, " %s <= %s <= %s\n", lpNumber(v.Lower), v.Name, lpNumber(v.Upper))
+		}
+	}
+	m.writeLPSection(bw, "General", Integer)
+	m.writeLPSection(bw, "Binary", Binary)
+	bw.WriteString("End\n")
+	return bw.Flush()
+}
+
+func (m *Model) writeLPTerms(bw *bufio.Writer, terms []Term) {
+	for i, t := range terms {
+		if i > 0 && i%lpTermsPerLine == 0 {
+			bw.WriteString("\n   ")
+		}
+		sign := "+"
+		coef := t.Coef
+		if coef < 0 {
+			sign, coef = "-", -coef
+		}
+		fmt.Fprintf(bw, " %s %s %s", sign, lpNumber(coef), m.Vars[t.Var].Name)
+	}
+}
+
+func (m *Model) writeLPSection(bw *bufio.Writer, title string, typ VarType) {
+	n := 0
+	for _, v := range m.Vars {
+		if v.Type != typ {
+			continue
+		}
+		if n == 0 {
+			bw.WriteString(title + "\n")
+		}
+		fmt.Fprintf(bw, " %s\n", v.Name)
+		n++
+	}
+}
+
+func lpNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,137 @@
+// Package milp describes a mixed-integer linear program independently of a solver and
+// writes it in the exchange formats open-source exact solvers read: the CPLEX LP format
+// (CBC, HiGHS, GLPK, SCIP) and a MiniZinc model (with a MIP backend such as CBC or
+// HiGHS). ReadSolution reads the variable values back from a solver's solution file.
+//
+// The schedule generators (preplan, roomplan, invigplan) map their problems onto a
+// Model for small subproblems that should be solved to proven optimality offline; the
+// solution is mapped back and validated against the generator's own hard constraints
+// before it is applied. Solving itself is not part of plexams.
+package milp
+
+import (
+	"fmt"
+	"math"
+)
+
+// VarType is the domain of a variable.
+type VarType int
+
+const (
+	Binary VarType = iota
+	Integer
+	Continuous
+)
+
+// Var is a decision variable. Names must be valid identifiers in both formats (a
+// letter followed by letters, digits or underscores) and unique within the model.
+type Var struct {
+	Name         string
+	Type         VarType
+	Lower, Upper float64 // Upper may be math.Inf(1)
+}
+
+// Term is coef · variable.
+type Term struct {
+	Var  int
+	Coef float64
+}
+
+// Sense is the relation of a constraint.
+type Sense int
+
+const (
+	LE Sense = iota
+	GE
+	EQ
+)
+
+func (s Sense) String() string {
+	switch s {
+	case GE:
+		return ">="
+	case EQ:
+		return "="
+	default:
+		return "<="
+	}
+}
+
+// Constraint is Σ terms (sense) RHS.
+type Constraint struct {
+	Name  string
+	Terms []Term
+	Sense Sense
+	RHS   float64
+}
+
+// Model is a minimization problem: Σ Objective + Offset subject to Constraints.
+type Model struct {
+	Name        string
+	Comments    []string // written as a header; e.g. what the model covers and leaves out
+	Vars        []Var
+	Constraints []Constraint
+	Objective   []Term
+	Offset      float64 // constant part of the objective (not written as a term)
+
+	byName map[string]int
+}
+
+// New returns an empty model.
+func New(name string) *Model {
+	return &Model{Name: name, byName: make(map[string]int)}
+}
+
+func (m *Model) addVar(v Var) int {
+	if _, dup := m.byName[v.Name]; dup {
+		panic(fmt.Sprintf("milp: variable %q defined twice", v.Name))
+	}
+	m.byName[v.Name] = len(m.Vars)
+	m.Vars = append(m.Vars, v)
+	return len(m.Vars) - 1
+}
+
+// Binary adds a 0/1 variable and returns its index.
+func (m *Model) Binary(name string) int {
+	return m.addVar(Var{Name: name, Type: Binary, Lower: 0, Upper: 1})
+}
+
+// Integer adds an integer variable in [lo, hi].
+func (m *Model) Integer(name string, lo, hi float64) int {
+	return m.addVar(Var{Name: name, Type: Integer, Lower: lo, Upper: hi})
+}
+
+// Continuous adds a real variable in [lo, hi] (hi may be math.Inf(1)).
+func (m *Model) Continuous(name string, lo, hi float64) int {
+	return m.addVar(Var{Name: name, Type: Continuous, Lower: lo, Upper: hi})
+}
+
+// NonNegative adds an unbounded real variable ≥ 0, typically an auxiliary cost variable.
+func (m *Model) NonNegative(name string) int {
+	return m.Continuous(name, 0, math.Inf(1))
+}
+
+// Lookup returns the index of the named variable.
+func (m *Model) Lookup(name string) (int, bool) {
+	i, ok := m.byName[name]
+	return i, ok
+}
+
+// Add adds a constraint. A constraint without terms is dropped: the builders only
+// produce those when every involved decision is fixed.
+func (m *Model) Add(name string, sense Sense, rhs float64, terms ...Term) {
+	if len(terms) == 0 {
+		return
+	}
+	m.Constraints = append(m.Constraints, Constraint{Name: name, Terms: terms, Sense: sense, RHS: rhs})
+}
+
+// Minimize adds coef · v to the objective.
+func (m *Model) Minimize(v int, coef float64) {
+	if coef != 0 {
+		m.Objective = append(m.Objective, Term{Var: v, Coef: coef})
+	}
+}
+
+// T is a shorthand for a Term.
+func T(v int, coef float64) Term { return Term{Var: v, Coef: coef} }
@@ -0,0 +1,96 @@
+package milp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testModel() (*Model, int, int, int) {
+	m := New("test")
+	x := m.Binary("x_1")
+	y := m.Integer("y_1", 0, 5)
+	c := m.NonNegative("c_1")
+	m.Add("cap", LE, 4, T(x, 3), T(y, 1))
+	m.Add("cost", GE, -1.5, T(c, 1), T(y, -0.5))
+	m.Add("empty", LE, 1) // no terms: dropped
+	m.Minimize(x, -10)
+	m.Minimize(c, 2)
+	m.Offset = 10
+	return m, x, y, c
+}
+
+func TestWriteLP(t *testing.T) {
+	m, _, _, _ := testModel()
+	var buf bytes.Buffer
+	if err := m.WriteLP(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"Minimize\n obj: - 10 x_1 + 2 c_1\n",
+		" cap: + 3 x_1 + 1 y_1 <= 4\n",
+		" cost: + 1 c_1 - 0.5 y_1 >= -1.5\n",
+		" 0 <= y_1 <= 5\n",
+		" c_1 >= 0\n",
+		"General\n y_1\n",
+		"Binary\n x_1\n",
+		"End\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("LP output misses %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "empty") {
+		t.Error("a constraint without terms must be dropped")
+	}
+}
+
+func TestWriteMiniZinc(t *testing.T) {
+	m, _, _, _ := testModel()
+	var buf bytes.Buffer
+	if err := m.WriteMiniZinc(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"var 0..1: x_1;\n",
+		"var float: c_1;\nconstraint c_1 >= 0.0;\n",
+		"constraint 3*x_1 + 1*y_1 <= 4;\n",
+		"constraint 1.0*c_1 + (-0.5)*int2float(y_1) >= (-1.5);\n",
+		"solve minimize (-10.0)*int2float(x_1) + 2.0*c_1;\n",
+		"\"y_1 \\(y_1)\\n\",\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("MiniZinc output misses %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestReadSolution(t *testing.T) {
+	m, x, y, c := testModel()
+	cbc := "Optimal - objective value -7.00000000\n      0 x_1                  1                    -10\n      2 c_1                  1.5                  2\n"
+	sol, err := ReadSolution(strings.NewReader(cbc), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sol.On(x) || sol.Int(y) != 0 || sol.Value(c) != 1.5 {
+		t.Errorf("CBC: got x=%v y=%d c=%v", sol.Value(x), sol.Int(y), sol.Value(c))
+	}
+
+	mzn := "x_1 0\ny_1 2\nc_1 0.0\n----------\nx_1 1\ny_1 1\nc_1 0.0\n----------\n==========\n"
+	sol, err = ReadSolution(strings.NewReader(mzn), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sol.On(x) || sol.Int(y) != 1 {
+		t.Errorf("MiniZinc: the last solution must win, got x=%v y=%d", sol.Value(x), sol.Int(y))
+	}
+
+	if _, err := ReadSolution(strings.NewReader("Infeasible - objective value 0\n"), m); err == nil {
+		t.Error("an infeasible solution file must be rejected")
+	}
+	if _, err := ReadSolution(strings.NewReader("nothing here\n"), m); err == nil {
+		t.Error("a file without any known variable must be rejected")
+	}
+}
@@ -0,0 +1,105 @@
+package milp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WriteMiniZinc writes the model as a MiniZinc model. Constraints with only integer
+// variables and integral coefficients stay integer; all others are written over
+// floats, so the model needs a MIP backend (e.g. `minizinc --solver cbc`). The output
+// item prints one "name value" line per variable, which ReadSolution understands.
+func (m *Model) WriteMiniZinc(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%% %s\n", m.Name)
+	for _, c := range m.Comments {
+		fmt.Fprintf(bw, "%% %s\n", c)
+	}
+	fmt.Fprintf(bw, "%% objective offset (not included below): %s\n\n", lpNumber(m.Offset))
+
+	for _, v := range m.Vars {
+		switch {
+		case v.Type == Continuous && math.IsInf(v.Upper, 1):
+			fmt.Fprintf(bw, "var float: %s;\nconstraint %s >= %s;\n", v.Name, v.Name, mznFloat(v.Lower))
+		case v.Type == Continuous:
+			fmt.Fprintf(bw, "var %s..%s: %s;\n", mznFloat(v.Lower), mznFloat(v.Upper), v.Name)
+		default:
+			fmt.Fprintf(bw, "var %d..%d: %s;\n", int64(v.Lower), int64(v.Upper), v.Name)
+		}
+	}
+	bw.WriteString("\n")
+	for _, c := range m.Constraints {
+		integral := m.integral(c.Terms) && c.RHS == math.Trunc(c.RHS)
+		fmt.Fprintf(bw, "constraint %s %s %s;\n", m.mznSum(c.Terms, integral), c.Sense, mznNumber(c.RHS, integral))
+	}
+
+	obj := "0"
+	if len(m.Objective) > 0 {
+		obj = m.mznSum(m.Objective, m.integral(m.Objective))
+	}
+	fmt.Fprintf(bw, "\nsolve minimize %s;\n\noutput [\n", obj)
+	for i, v := range m.Vars {
+		sep := ","
+		if i == len(m.Vars)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(bw, "  \"%s \\(%s)\\n\"%s\n", v.Name, v.Name, sep)
+	}
+	bw.WriteString("];\n")
+	return bw.Flush()
+}
+
+// integral reports whether the terms only involve integer variables with integral
+// coefficients.
+func (m *Model) integral(terms []Term) bool {
+	for _, t := range terms {
+		if m.Vars[t.Var].Type == Continuous || t.Coef != math.Trunc(t.Coef) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Model) mznSum(terms []Term, integral bool) string {
+	var sb strings.Builder
+	for i, t := range terms {
+		if i > 0 {
+			sb.WriteString(" + ")
+		}
+		v := m.Vars[t.Var]
+		name := v.Name
+		if !integral && v.Type != Continuous {
+			name = "int2float(" + name + ")"
+		}
+		fmt.Fprintf(&sb, "%s*%s", mznNumber(t.Coef, integral), name)
+	}
+	return sb.String()
+}
+
+func mznNumber(f float64, integral bool) string {
+	if integral {
+		s := strconv.FormatInt(int64(f), 10)
+		if f < 0 {
+			return "(" + s + ")"
+		}
+		return s
+	}
+	s := mznFloat(f)
+	if f < 0 {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// mznFloat formats a float literal MiniZinc accepts (always with a decimal point).
+func mznFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
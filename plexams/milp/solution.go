@@ -0,0 +1,73 @@
+package milp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Solution holds the variable values read from a solver's solution file. Variables
+// missing from the file are 0 (CBC, for example, only lists non-zero values).
+type Solution struct {
+	m      *Model
+	values map[int]float64
+}
+
+// Value returns the value of variable v.
+func (s Solution) Value(v int) float64 { return s.values[v] }
+
+// On reports whether a binary variable is set.
+func (s Solution) On(v int) bool { return s.values[v] > 0.5 }
+
+// Int returns the value of an integer variable, rounded.
+func (s Solution) Int(v int) int {
+	f := s.values[v]
+	if f < 0 {
+		return int(f - 0.5)
+	}
+	return int(f + 0.5)
+}
+
+// ReadSolution reads the values of m's variables from a solution file. It accepts
+// every format that puts a variable name followed by its value on a line, which
+// covers the solution files of CBC (`solu`), HiGHS (`--solution_file`) and SCIP and
+// the output of the MiniZinc models written by WriteMiniZinc. Later values win, so for MiniZinc the
+// last (best) solution is taken. A file reporting an infeasible model is an error.
+func ReadSolution(r io.Reader, m *Model) (Solution, error) {
+	sol := Solution{m: m, values: make(map[int]float64)}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	found := 0
+	for sc.Scan() {
+		line := sc.Text()
+		lower := strings.ToLower(line)
+		if strings.Contains(lower, "infeasible") || strings.Contains(lower, "unsatisfiable") {
+			return sol, fmt.Errorf("the solution file reports an infeasible model: %s", strings.TrimSpace(line))
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == '=' || r == ':' || r == ','
+		})
+		for i := 0; i+1 < len(fields); i++ {
+			v, ok := m.byName[fields[i]]
+			if !ok {
+				continue
+			}
+			f, err := strconv.ParseFloat(fields[i+1], 64)
+			if err != nil {
+				continue
+			}
+			sol.values[v] = f
+			found++
+			break
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return sol, err
+	}
+	if found == 0 {
+		return sol, fmt.Errorf("no values for the model's variables found in the solution file")
+	}
+	return sol, nil
+}
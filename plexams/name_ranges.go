@@ -0,0 +1,227 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/pdfgen"
+	"github.com/obcode/plexams.go/plexams/seatplan"
+)
+
+// Name ranges of split exams (see seatplan.Ranges): the rooms of an exam in its slot
+// without the reserve and NTA (handicap) rooms. NTAs planned into a regular room are
+// pinned to it. RebalanceNameRanges rewrites the planned rooms; the seating plans follow
+// automatically since they are derived from them.
+
+// nameRangeExam is one split exam with its planned-room entries per room.
+type nameRangeExam struct {
+	ancode  int
+	start   time.Time
+	rooms   []string                        // sorted
+	entries map[string][]*model.PlannedRoom // room → entries of the exam in the room
+}
+
+func (e *nameRangeExam) splits(students map[string]*model.Student) []seatplan.Split {
+	splits := make([]seatplan.Split, 0, len(e.rooms))
+	for _, room := range e.rooms {
+		sp := seatplan.Split{Room: room}
+		for _, pr := range e.entries[room] {
+			for _, mtknr := range pr.StudentsInRoom {
+				sp.Students = append(sp.Students, seatStudent(mtknr, students))
+			}
+		}
+		splits = append(splits, sp)
+	}
+	return splits
+}
+
+func seatStudent(mtknr string, students map[string]*model.Student) seatplan.Student {
+	st := seatplan.Student{Mtknr: mtknr}
+	if s, ok := students[mtknr]; ok {
+		st.Name, st.NTA = s.Name, s.Nta != nil
+	}
+	return st
+}
+
+func pinnedNTA(s seatplan.Student) bool { return s.NTA }
+
+// nameRangeExams returns the split exams (all, or only ancode if ancode > 0) sorted by
+// start time and ancode, with the planned rooms they were built from and the students.
+func (p *Plexams) nameRangeExams(ctx context.Context, ancode int) ([]*nameRangeExam, []*model.PlannedRoom, map[string]*model.Student, error) {
+	planned, err := p.dbClient.PlannedRooms(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	all, err := p.StudentRegsPerStudentPlanned(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	students := make(map[string]*model.Student, len(all))
+	for _, s := range all {
+		students[s.Mtknr] = s
+	}
+
+	type key struct {
+		ancode int
+		start  int64
+	}
+	byKey := make(map[key]*nameRangeExam)
+	for _, pr := range planned {
+		if pr.Reserve || pr.Handicap || pr.Starttime == nil || len(pr.StudentsInRoom) == 0 {
+			continue
+		}
+		if ancode > 0 && pr.Ancode != ancode {
+			continue
+		}
+		k := key{pr.Ancode, pr.Starttime.Unix()}
+		e, ok := byKey[k]
+		if !ok {
+			e = &nameRangeExam{ancode: pr.Ancode, start: *pr.Starttime, entries: make(map[string][]*model.PlannedRoom)}
+			byKey[k] = e
+		}
+		if _, ok := e.entries[pr.RoomName]; !ok {
+			e.rooms = append(e.rooms, pr.RoomName)
+		}
+		e.entries[pr.RoomName] = append(e.entries[pr.RoomName], pr)
+	}
+	exams := make([]*nameRangeExam, 0, len(byKey))
+	for _, e := range byKey {
+		if len(e.rooms) < 2 {
+			continue
+		}
+		sort.Strings(e.rooms)
+		exams = append(exams, e)
+	}
+	sort.Slice(exams, func(i, j int) bool {
+		if !exams[i].start.Equal(exams[j].start) {
+			return exams[i].start.Before(exams[j].start)
+		}
+		return exams[i].ancode < exams[j].ancode
+	})
+	return exams, planned, students, nil
+}
+
+func (p *Plexams) examNameRanges(ctx context.Context, e *nameRangeExam, splits []seatplan.Split) *model.ExamNameRanges {
+	ranges, contiguous := seatplan.Ranges(splits, pinnedNTA)
+	out := &model.ExamNameRanges{
+		Ancode:     e.ancode,
+		Starttime:  e.start,
+		Ranges:     make([]*model.RoomNameRange, 0, len(ranges)),
+		Contiguous: contiguous,
+	}
+	if exam, err := p.dbClient.GetZpaExamByAncode(ctx, e.ancode); err == nil && exam != nil {
+		out.Module, out.MainExamer = exam.Module, exam.MainExamer
+	}
+	for _, r := range ranges {
+		out.Ranges = append(out.Ranges, &model.RoomNameRange{
+			Room: r.Room, From: r.From, To: r.To, Students: r.Count, Pinned: r.Pinned,
+		})
+	}
+	return out
+}
+
+// ExamNameRanges returns the name ranges of all split exams (or only ancode if given).
+func (p *Plexams) ExamNameRanges(ctx context.Context, ancode *int) ([]*model.ExamNameRanges, error) {
+	exams, _, students, err := p.nameRangeExams(ctx, intOrZero(ancode))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*model.ExamNameRanges, 0, len(exams))
+	for _, e := range exams {
+		out = append(out, p.examNameRanges(ctx, e, e.splits(students)))
+	}
+	return out, nil
+}
+
+// RebalanceNameRanges redistributes the students of the split exams (or only ancode)
+// over their rooms by name and saves the planned rooms. Every planned-room entry keeps
+// its number of students and its NTAs.
+func (p *Plexams) RebalanceNameRanges(ctx context.Context, ancode *int) ([]*model.ExamNameRanges, error) {
+	if err := p.generationAllowed(ctx, model.PlanningGateRooms); err != nil {
+		return nil, err
+	}
+	exams, planned, students, err := p.nameRangeExams(ctx, intOrZero(ancode))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*model.ExamNameRanges, 0, len(exams))
+	for _, e := range exams {
+		balanced := seatplan.Rebalance(e.splits(students), pinnedNTA)
+		for _, sp := range balanced {
+			free := make([]string, 0, len(sp.Students))
+			for _, s := range sp.Students {
+				if !pinnedNTA(s) {
+					free = append(free, s.Mtknr)
+				}
+			}
+			for _, pr := range e.entries[sp.Room] {
+				keep := make([]string, 0, len(pr.StudentsInRoom))
+				for _, mtknr := range pr.StudentsInRoom {
+					if pinnedNTA(seatStudent(mtknr, students)) {
+						keep = append(keep, mtknr)
+					}
+				}
+				n := len(pr.StudentsInRoom) - len(keep)
+				pr.StudentsInRoom = append(keep, free[:n]...)
+				free = free[n:]
+			}
+		}
+		out = append(out, p.examNameRanges(ctx, e, e.splits(students)))
+	}
+	if len(exams) > 0 {
+		if err := p.dbClient.ReplacePlannedRooms(ctx, planned); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func intOrZero(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+// NameRangeNoticesPDFBytes builds the door notices (one landscape page per room and
+// start time with the ranges of the split exams in the room).
+func (p *Plexams) NameRangeNoticesPDFBytes(ctx context.Context) ([]byte, error) {
+	exams, err := p.ExamNameRanges(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return marotoBytes(pdfgen.NameRangeNotices(p.semesterFull(), exams))
+}
+
+// NameRangeOverviewPDFBytes builds the overview of all split exams with their ranges.
+func (p *Plexams) NameRangeOverviewPDFBytes(ctx context.Context) ([]byte, error) {
+	exams, err := p.ExamNameRanges(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return marotoBytes(pdfgen.NameRangeOverview(p.semesterFull(), exams))
+}
+
+// nameRangeLabels maps ancode → room → "A–Ka" for the published-rooms email.
+func (p *Plexams) nameRangeLabels(ctx context.Context) (map[int]map[string]string, error) {
+	exams, err := p.ExamNameRanges(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[int]map[string]string, len(exams))
+	for _, e := range exams {
+		if !e.Contiguous {
+			continue
+		}
+		labels[e.Ancode] = make(map[string]string, len(e.Ranges))
+		for _, r := range e.Ranges {
+			if r.From != "" {
+				labels[e.Ancode][r.Room] = fmt.Sprintf("%s–%s", r.From, r.To)
+			}
+		}
+	}
+	return labels, nil
+}
@@ -0,0 +1,113 @@
+// Package noshow predicts how many registered students of an exam will actually show up,
+// from the exam protocols of previous semesters (see examprotocol): the no-show rate of
+// the same module, else of the same main examer, else of all exams. It is I/O-free;
+// loading the protocols of the previous workspaces stays in the plexams package.
+package noshow
+
+import (
+	"math"
+	"strings"
+)
+
+// MinStudents is the number of recorded students (present + absent) a module or examer
+// needs before its own rate is used instead of the next, broader one.
+const MinStudents = 20
+
+// Basis is what a prediction is based on.
+type Basis string
+
+const (
+	BasisModule Basis = "MODULE"
+	BasisExamer Basis = "EXAMER"
+	BasisAll    Basis = "ALL"
+	BasisNone   Basis = "NONE" // no history at all: everyone is expected
+)
+
+// Record is one recorded exam room of a previous semester.
+type Record struct {
+	Module     string
+	MainExamer string
+	Present    int
+	Absent     int
+}
+
+type count struct{ present, absent int }
+
+func (c count) students() int { return c.present + c.absent }
+
+// rate is absent / (present + absent) in percent, rounded to one decimal.
+func (c count) rate() float64 {
+	if c.students() == 0 {
+		return 0
+	}
+	return math.Round(float64(c.absent)*1000/float64(c.students())) / 10
+}
+
+// History holds the recorded students per module and main examer.
+type History struct {
+	modules map[string]count
+	examers map[string]count
+	all     count
+}
+
+// NewHistory aggregates the records.
+func NewHistory(records []Record) *History {
+	h := &History{modules: make(map[string]count), examers: make(map[string]count)}
+	for _, r := range records {
+		add := func(m map[string]count, key string) {
+			if key == "" {
+				return
+			}
+			c := m[key]
+			c.present += r.Present
+			c.absent += r.Absent
+			m[key] = c
+		}
+		add(h.modules, normalize(r.Module))
+		add(h.examers, normalize(r.MainExamer))
+		h.all.present += r.Present
+		h.all.absent += r.Absent
+	}
+	return h
+}
+
+// normalize makes module and examer names comparable across semesters.
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// Prediction is the expected no-show rate of an exam.
+type Prediction struct {
+	// Rate is the no-show rate in percent.
+	Rate  float64
+	Basis Basis
+	// Students is the number of recorded students the rate is based on.
+	Students int
+}
+
+// Predict returns the no-show rate of the module if it has at least MinStudents
+// recorded students, else that of the main examer, else that of all exams.
+func (h *History) Predict(module, mainExamer string) Prediction {
+	if c, ok := h.modules[normalize(module)]; ok && c.students() >= MinStudents {
+		return Prediction{Rate: c.rate(), Basis: BasisModule, Students: c.students()}
+	}
+	if c, ok := h.examers[normalize(mainExamer)]; ok && c.students() >= MinStudents {
+		return Prediction{Rate: c.rate(), Basis: BasisExamer, Students: c.students()}
+	}
+	if h.all.students() > 0 {
+		return Prediction{Rate: h.all.rate(), Basis: BasisAll, Students: h.all.students()}
+	}
+	return Prediction{Basis: BasisNone}
+}
+
+// Attendance is the share (percent) of the registered students a room is sized for:
+// 100 minus the no-show rate plus the safety margin (percentage points), within 1..100.
+func Attendance(rate float64, margin int) int {
+	return min(max(int(math.Ceil(100-rate))+margin, 1), 100)
+}
+
+// ExpectedSeats is the number of seats the registered students need at the given
+// attendance (percent), rounded up.
+func ExpectedSeats(registered, attendance int) int {
+	return (registered*attendance + 99) / 100
+}
@@ -0,0 +1,48 @@
+package noshow
+
+import "testing"
+
+func TestPredict(t *testing.T) {
+	h := NewHistory([]Record{
+		{Module: "Datenbanken", MainExamer: "Mustermann", Present: 27, Absent: 3},
+		{Module: "Datenbanken ", MainExamer: "Mustermann", Present: 15, Absent: 5},
+		{Module: "Compilerbau", MainExamer: "Mustermann", Present: 8, Absent: 2},
+		{Module: "Mathematik I", MainExamer: "Muster", Present: 10, Absent: 5},
+	})
+	tests := []struct {
+		module, examer string
+		want           Prediction
+	}{
+		{"datenbanken", "Neu", Prediction{Rate: 16, Basis: BasisModule, Students: 50}},
+		{"Compilerbau", "Mustermann", Prediction{Rate: 16.7, Basis: BasisExamer, Students: 60}},
+		{"Mathematik I", "Muster", Prediction{Rate: 20, Basis: BasisAll, Students: 75}},
+	}
+	for _, tt := range tests {
+		if got := h.Predict(tt.module, tt.examer); got != tt.want {
+			t.Errorf("Predict(%q, %q) = %+v, want %+v", tt.module, tt.examer, got, tt.want)
+		}
+	}
+	if got := NewHistory(nil).Predict("x", "y"); got.Basis != BasisNone || got.Rate != 0 {
+		t.Errorf("empty history: %+v", got)
+	}
+}
+
+func TestAttendance(t *testing.T) {
+	tests := []struct {
+		rate   float64
+		margin int
+		want   int
+	}{
+		{16.7, 5, 89},
+		{0, 5, 100},
+		{99.5, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := Attendance(tt.rate, tt.margin); got != tt.want {
+			t.Errorf("Attendance(%v, %d) = %d, want %d", tt.rate, tt.margin, got, tt.want)
+		}
+	}
+	if got := ExpectedSeats(45, 89); got != 41 {
+		t.Errorf("ExpectedSeats(45, 89) = %d, want 41", got)
+	}
+}
@@ -43,6 +43,19 @@ type Options struct {
 	// (so the result is identical to an inline loop that used the same rng) — used by the
 	// invigilation solver, whose greedy start draws from the same stream.
 	Rng *rand.Rand
+	// RandSource is the counting source behind a caller-supplied Rng (see NewRand). It is
+	// only needed for checkpoints; without Rng, Anneal creates its own counting source.
+	RandSource *Source
+
+	// Checkpoint, if set, is called every CheckpointEvery iterations and once at the end
+	// with the best state and the position of the run, so a caller can persist it. Best
+	// is the Model's snapshot and must not be modified.
+	Checkpoint      func(Checkpoint)
+	CheckpointEvery int
+	// Resume continues a checkpointed run: the Model is restored to Resume.Best and the
+	// loop starts at Resume.Iteration. Without Rng, the random stream continues at
+	// Resume.Rand; with Rng, the caller must have positioned it (NewRand(Resume.Rand)).
+	Resume *Checkpoint
 
 	// OnProgress, if set, is called every ProgressEvery iterations with a snapshot
 	// of the current best. It is throttled on purpose: per-iteration calls would
@@ -111,17 +124,25 @@ type Detailer interface {
 // Model restored to the best state found. The Model is responsible for starting from
 // (and only ever moving through) hard-feasible states.
 func Anneal(m Model, opts Options) Result {
-	rng := opts.Rng
+	rng, src := opts.Rng, opts.RandSource
 	if rng == nil {
-		rng = rand.New(rand.NewSource(opts.Seed)) //nolint:gosec // deterministic, not security relevant
+		state := RandState{Seed: opts.Seed}
+		if opts.Resume != nil {
+			state = opts.Resume.Rand
+		}
+		rng, src = NewRand(state)
 	}
 	conv, hasConv := m.(Converger)
 	det, hasDet := m.(Detailer)
 
+	start, bestIter := 0, 0
+	if opts.Resume != nil {
+		m.Restore(opts.Resume.Best)
+		start, bestIter = opts.Resume.Iteration, opts.Resume.BestIter
+	}
 	cost := m.Cost()
 	best := m.Snapshot()
 	bestCost := cost
-	bestIter := 0
 	bestConverged := false
 	if hasConv {
 		bestConverged = conv.Converged()
@@ -132,9 +153,20 @@ func Anneal(m Model, opts Options) Result {
 	}
 
 	progress := opts.OnProgress != nil && opts.ProgressEvery > 0
+	checkpoint := func(it int) {
+		cp := Checkpoint{Iteration: it, Total: opts.Iterations, BestCost: bestCost, BestIter: bestIter, Best: best}
+		if src != nil {
+			cp.Rand = src.State()
+		}
+		opts.Checkpoint(cp)
+	}
+	checkpoints := opts.Checkpoint != nil && opts.CheckpointEvery > 0
 	result := Result{Iterations: opts.Iterations}
 
-	for it := 0; it < opts.Iterations; it++ {
+	for it := start; it < opts.Iterations; it++ {
+		if checkpoints && it > start && it%opts.CheckpointEvery == 0 {
+			checkpoint(it)
+		}
 		if progress && it%opts.ProgressEvery == 0 {
 			opts.OnProgress(Progress{Iteration: it, Total: opts.Iterations, BestCost: bestCost, Detail: bestDetail})
 		}
@@ -173,6 +205,9 @@ func Anneal(m Model, opts Options) Result {
 		}
 	}
 
+	if opts.Checkpoint != nil {
+		checkpoint(result.Iterations)
+	}
 	m.Restore(best)
 	result.Cost = bestCost
 	return result
@@ -163,3 +163,75 @@ func TestRegistryDescribeAndCost(t *testing.T) {
 		t.Errorf("feasible start should have no hard violations, got %d", len(vs))
 	}
 }
+
+func TestNewRandContinuesStream(t *testing.T) {
+	ref := rand.New(rand.NewSource(42))
+	rng, src := NewRand(RandState{Seed: 42})
+	for i := 0; i < 100; i++ {
+		if a, b := ref.Intn(1000), rng.Intn(1000); a != b {
+			t.Fatalf("draw %d: counting source diverges from math/rand (%d != %d)", i, a, b)
+		}
+	}
+	want := make([]float64, 10)
+	for i := range want {
+		want[i] = ref.Float64()
+	}
+	resumed, _ := NewRand(src.State())
+	for i := range want {
+		if got := resumed.Float64(); got != want[i] {
+			t.Fatalf("resumed stream diverges at %d: %v != %v", i, got, want[i])
+		}
+	}
+}
+
+// counting wraps the toy to count proposals.
+type counting struct {
+	*toy
+	proposals int
+}
+
+func (c *counting) Propose(rng *rand.Rand) func() {
+	c.proposals++
+	return c.toy.Propose(rng)
+}
+
+func TestAnnealCheckpointAndResume(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Iterations = 10_000
+	opts.StartTemp = 100
+	opts.EndTemp = 0.01
+	opts.StopWhenConverged = false
+	opts.CheckpointEvery = 1_000
+
+	var cps []Checkpoint
+	opts.Checkpoint = func(cp Checkpoint) { cps = append(cps, cp) }
+	full := Anneal(newToy(), opts)
+	if len(cps) != 10 || cps[0].Iteration != 1_000 || cps[9].Iteration != 10_000 {
+		t.Fatalf("expected checkpoints every 1000 iterations plus a final one, got %d", len(cps))
+	}
+	if cps[9].BestCost != full.Cost {
+		t.Errorf("final checkpoint must carry the result cost: %v != %v", cps[9].BestCost, full.Cost)
+	}
+
+	// "restart": a fresh model resumes from the 5000-iteration checkpoint
+	cp := cps[4]
+	m := &counting{toy: newToy()}
+	opts.Checkpoint = nil
+	opts.Resume = &cp
+	res := Anneal(m, opts)
+	if m.proposals != opts.Iterations-cp.Iteration {
+		t.Errorf("resumed run must only do the remaining %d iterations, did %d", opts.Iterations-cp.Iteration, m.proposals)
+	}
+	if res.Cost > cp.BestCost {
+		t.Errorf("resumed run must not end worse than its checkpoint: %v > %v", res.Cost, cp.BestCost)
+	}
+
+	// continue a finished run with more iterations
+	final := cps[9]
+	m = &counting{toy: newToy()}
+	opts.Resume = &final
+	opts.Iterations = 15_000
+	if res := Anneal(m, opts); m.proposals != 5_000 || res.Cost > final.BestCost {
+		t.Errorf("continuing must run the 5000 extra iterations without getting worse: %d, %v", m.proposals, res.Cost)
+	}
+}
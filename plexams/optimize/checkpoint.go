@@ -0,0 +1,69 @@
+package optimize
+
+import "math/rand"
+
+// RandState identifies a position in a seeded random stream: the seed and the number of
+// values drawn from the source so far. Unlike a *rand.Rand it can be stored, and
+// NewRand(state) recreates a generator that continues exactly where the stored one was.
+type RandState struct {
+	Seed  int64
+	Draws uint64
+}
+
+// Source is a math/rand source that counts its draws so its position can be
+// checkpointed. Every rand.Rand method used by the generators (Intn, Float64, …) draws
+// whole values from the source, so seed + draw count fully determines the stream.
+type Source struct {
+	seed  int64
+	draws uint64
+	src   rand.Source64
+}
+
+func (s *Source) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *Source) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
+}
+
+func (s *Source) Seed(seed int64) {
+	s.seed, s.draws = seed, 0
+	s.src.Seed(seed)
+}
+
+// State returns the current position of the stream.
+func (s *Source) State() RandState {
+	return RandState{Seed: s.seed, Draws: s.draws}
+}
+
+// NewRand returns a generator positioned at state (a fresh stream for a zero Draws)
+// together with its counting source. For Draws == 0 it yields exactly the stream of
+// rand.New(rand.NewSource(state.Seed)).
+func NewRand(state RandState) (*rand.Rand, *Source) {
+	src := &Source{seed: state.Seed, src: rand.NewSource(state.Seed).(rand.Source64)} //nolint:gosec // deterministic, not security relevant
+	for src.draws < state.Draws {
+		src.Uint64()
+	}
+	return rand.New(src), src //nolint:gosec // deterministic, not security relevant
+}
+
+// Checkpoint is the persistable progress of an Anneal run: the best state seen (as
+// returned by the Model's Snapshot), the iteration reached and the position of the
+// random stream. Passing it back as Options.Resume (with the Model restored from Best
+// and Rng recreated via NewRand(Rand)) continues the run after a restart; a larger
+// Options.Iterations continues it beyond its original length.
+//
+// Resuming restarts from the best state, not from the (discarded) current one, so a
+// resumed run is not bit-identical to an uninterrupted one — it continues the same
+// cooling schedule from the best plan found so far.
+type Checkpoint struct {
+	Iteration int
+	Total     int
+	BestCost  float64
+	BestIter  int
+	Best      any
+	Rand      RandState
+}
@@ -54,6 +54,15 @@ func (p *Plexams) spreadStatisticsMaroto(ctx context.Context) (pdf.Maroto, error
 	return pdfgen.SpreadStatistics(p.semesterFull(), stat), nil
 }
 
+func (p *Plexams) roomUtilizationMaroto(ctx context.Context) (pdf.Maroto, error) {
+	stat, err := p.RoomUtilization(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot compute room utilization")
+		return nil, err
+	}
+	return pdfgen.RoomUtilization(p.semesterFull(), stat), nil
+}
+
 func (p *Plexams) constraintsMaroto(ctx context.Context) (pdf.Maroto, error) {
 	examsWithConstraints, err := p.ZpaExamsToPlanWithConstraints(ctx)
 	if err != nil {
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/johnfercher/maroto/pkg/pdf"
@@ -58,6 +60,15 @@ func (p *Plexams) SpreadStatisticsPDFBytes(ctx context.Context) ([]byte, error)
 	return marotoBytes(m)
 }
 
+// RoomUtilizationPDFBytes builds the room-utilization statistics PDF as bytes.
+func (p *Plexams) RoomUtilizationPDFBytes(ctx context.Context) ([]byte, error) {
+	m, err := p.roomUtilizationMaroto(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return marotoBytes(m)
+}
+
 // pdfExport describes one downloadable document, its default download filename and
 // content type, and how to build its bytes.
 type pdfExport struct {
@@ -70,17 +81,25 @@ type pdfExport struct {
 // kind strings the old `pdf` CLI command used.
 func (p *Plexams) pdfExports() map[string]pdfExport {
 	return map[string]pdfExport{
-		"exams-to-plan":     {filename: "PrüfungenImPrüfungszeitraum.pdf", build: p.GenerateExamsToPlanPDFBytes},
-		"same-module-name":  {filename: "PrüfungenMitGleichenModulnamen.pdf", build: p.SameModulNamesBytes},
-		"constraints":       {filename: "Constraints.pdf", build: p.ConstraintsPDFBytes},
-		"spread-statistics": {filename: "Prüfungsverteilung-Statistik.pdf", build: p.SpreadStatisticsPDFBytes},
-		"draft-muc.dai":     {filename: "draft-muc.dai.pdf", build: func(ctx context.Context) ([]byte, error) { return marotoBytes(p.draftMucDaiMaroto(ctx)) }},
-		"draft-fk08":        {filename: "draft-fk08.pdf", build: func(ctx context.Context) ([]byte, error) { return marotoBytes(p.draftFk08Maroto(ctx)) }},
-		"draft-fk10":        {filename: "draft-fk10.pdf", build: func(ctx context.Context) ([]byte, error) { return marotoBytes(p.draftFk10Maroto(ctx)) }},
-		"draft-exahm":       {filename: "draft-exahm.pdf", build: func(ctx context.Context) ([]byte, error) { return marotoBytes(p.draftExahmMaroto(ctx)) }},
-		"draft-fs":          {filename: "draft-fs.pdf", build: p.DraftFSBytes},
-		"draft-lba-rep":     {filename: "draft-lba-rep.pdf", build: p.DraftLbaRepBytes},
-		"draft-si":          {filename: "draft-si.zip", contentType: "application/zip", build: p.DraftSIZipBytes},
+		"exams-to-plan":              {filename: "PrüfungenImPrüfungszeitraum.pdf", build: p.GenerateExamsToPlanPDFBytes},
+		"same-module-name":           {filename: "PrüfungenMitGleichenModulnamen.pdf", build: p.SameModulNamesBytes},
+		"constraints":                {filename: "Constraints.pdf", build: p.ConstraintsPDFBytes},
+		"spread-statistics":          {filename: "Prüfungsverteilung-Statistik.pdf", build: p.SpreadStatisticsPDFBytes},
+		"room-utilization":           {filename: "Raumauslastung.pdf", build: p.RoomUtilizationPDFBytes},
+		"exam-protocols":             {filename: "Prüfungsprotokolle.pdf", build: p.ExamProtocolsPDFBytes},
+		"draft-muc.dai":              {filename: "draft-muc.dai.pdf", build: func(ctx context.Context) ([]byte, error) { return marotoBytes(p.draftMucDaiMaroto(ctx)) }},
+		"draft-fk08":                 {filename: "draft-fk08.pdf", build: func(ctx context.Context) ([]byte, error) { return marotoBytes(p.draftFk08Maroto(ctx)) }},
+		"draft-fk10":                 {filename: "draft-fk10.pdf", build: func(ctx context.Context) ([]byte, error) { return marotoBytes(p.draftFk10Maroto(ctx)) }},
+		"draft-exahm":                {filename: "draft-exahm.pdf", build: func(ctx context.Context) ([]byte, error) { return marotoBytes(p.draftExahmMaroto(ctx)) }},
+		"draft-fs":                   {filename: "draft-fs.pdf", build: p.DraftFSBytes},
+		"draft-lba-rep":              {filename: "draft-lba-rep.pdf", build: p.DraftLbaRepBytes},
+		"draft-si":                   {filename: "draft-si.zip", contentType: "application/zip", build: p.DraftSIZipBytes},
+		"attendance-lists":           {filename: "Anwesenheitslisten.pdf", build: p.AttendanceListsPDFBytes},
+		"attendance-lists-by-examer": {filename: "Anwesenheitslisten.zip", contentType: "application/zip", build: p.AttendanceListsZipBytes},
+		"door-signs":                 {filename: "Türschilder.pdf", build: p.DoorSignsPDFBytes},
+		"seating-charts":             {filename: "Sitzpläne.zip", contentType: "application/zip", build: p.SeatingChartsZipBytes},
+		"name-range-notices":         {filename: "Raumaufteilung-Aushänge.pdf", build: p.NameRangeNoticesPDFBytes},
+		"name-range-overview":        {filename: "Raumaufteilung-Übersicht.pdf", build: p.NameRangeOverviewPDFBytes},
 	}
 }
 
@@ -120,3 +139,45 @@ func (p *Plexams) HTTPDownloadPDF(w http.ResponseWriter, r *http.Request) {
 		log.Error().Err(err).Str("kind", kind).Msg("cannot write pdf download")
 	}
 }
+
+// HTTPDownloadPlacementPDF streams the placement explanation of one exam as a PDF.
+// GET /download/pdf/placement/{ancode}
+func (p *Plexams) HTTPDownloadPlacementPDF(w http.ResponseWriter, r *http.Request) {
+	ancode, err := strconv.Atoi(chi.URLParam(r, "ancode"))
+	if err != nil {
+		http.Error(w, "ancode must be a number", http.StatusBadRequest)
+		return
+	}
+	data, err := p.ExamPlacementPDFBytes(r.Context(), ancode)
+	if err != nil {
+		http.Error(w, "cannot generate pdf: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	filename := fmt.Sprintf("%s_Terminbegründung_%d.pdf", strings.ReplaceAll(p.semester, " ", "_"), ancode)
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if _, err := w.Write(data); err != nil {
+		log.Error().Err(err).Int("ancode", ancode).Msg("cannot write placement pdf download")
+	}
+}
+
+// HTTPDownloadExamDayProtocolPDF streams the end-of-day protocol of an exam day.
+// GET /download/pdf/exam-day/{date} (date as 2006-01-02)
+func (p *Plexams) HTTPDownloadExamDayProtocolPDF(w http.ResponseWriter, r *http.Request) {
+	date, err := time.ParseInLocation("2006-01-02", chi.URLParam(r, "date"), time.Local)
+	if err != nil {
+		http.Error(w, "date must be given as 2006-01-02", http.StatusBadRequest)
+		return
+	}
+	data, err := p.ExamDayProtocolPDFBytes(r.Context(), date)
+	if err != nil {
+		http.Error(w, "cannot generate pdf: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	filename := fmt.Sprintf("%s_Prüfungstag_%s.pdf", strings.ReplaceAll(p.semester, " ", "_"), date.Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if _, err := w.Write(data); err != nil {
+		log.Error().Err(err).Str("date", date.Format("2006-01-02")).Msg("cannot write exam-day protocol download")
+	}
+}
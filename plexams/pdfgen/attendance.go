@@ -0,0 +1,83 @@
+package pdfgen
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/johnfercher/maroto/pkg/color"
+	"github.com/johnfercher/maroto/pkg/consts"
+	"github.com/johnfercher/maroto/pkg/pdf"
+	"github.com/johnfercher/maroto/pkg/props"
+)
+
+// AttendanceSheet is the attendance list of one exam in one planned room.
+type AttendanceSheet struct {
+	Ancode     int
+	Module     string
+	MainExamer string
+	Room       string
+	Starttime  time.Time
+	Duration   int  // minutes, the room's (NTA rooms are longer)
+	Handicap   bool // NTA room
+	Students   []AttendanceStudent
+}
+
+// AttendanceStudent is one line of an attendance sheet.
+type AttendanceStudent struct {
+	Name    string
+	Mtknr   string
+	Program string
+	Seat    string // "Reihe/Platz" from the seating plan, "" without one
+	NTA     bool   // marked only; the compensation itself is not printed
+}
+
+// AttendanceSheets renders the attendance lists (portrait), one sheet per exam and room
+// starting on a new page, with an empty signature column.
+func AttendanceSheets(semesterFull string, sheets []AttendanceSheet) pdf.Maroto {
+	m := pdf.NewMaroto(consts.Portrait, consts.A4)
+	m.SetPageMargins(10, 15, 10)
+	footer(m)
+	gray := color.Color{Red: 211, Green: 211, Blue: 211}
+
+	if len(sheets) == 0 {
+		centeredRow(m, 10, 3, consts.Bold, fmt.Sprintf("Keine Anwesenheitslisten — %s", semesterFull))
+		return m
+	}
+	for i, s := range sheets {
+		if i > 0 {
+			m.AddPage()
+		}
+		centeredRow(m, 10, 3, consts.Bold, fmt.Sprintf("Anwesenheitsliste %d. %s (%s)", s.Ancode, s.Module, s.MainExamer))
+		room := fmt.Sprintf("Raum %s", s.Room)
+		if s.Handicap {
+			room += " (NTA-Raum)"
+		}
+		centeredRow(m, 8, 1, consts.Normal, fmt.Sprintf("%s — %s Uhr, %d Minuten — %s",
+			room, s.Starttime.Local().Format("02.01.06, 15:04"), s.Duration, semesterFull))
+		m.TableList([]string{"Nr.", "Name", "Matrikelnr.", "Studiengang", "Platz", "NTA", "Unterschrift"},
+			AttendanceRows(s.Students), props.TableList{
+				HeaderProp:           props.TableListContent{Size: 9, GridSizes: []uint{1, 3, 2, 1, 1, 1, 3}},
+				ContentProp:          props.TableListContent{Size: 9, GridSizes: []uint{1, 3, 2, 1, 1, 1, 3}},
+				Align:                consts.Left,
+				AlternatedBackground: &gray,
+				HeaderContentSpace:   2,
+				Line:                 true,
+			})
+		centeredRow(m, 12, 6, consts.Italic, fmt.Sprintf(
+			"%d Studierende eingeplant. Nicht eingeplante Studierende bitte handschriftlich ergänzen.", len(s.Students)))
+	}
+	return m
+}
+
+// AttendanceRows builds the table rows of a sheet; the signature column stays empty.
+func AttendanceRows(students []AttendanceStudent) [][]string {
+	rows := make([][]string, 0, len(students))
+	for i, s := range students {
+		nta := ""
+		if s.NTA {
+			nta = "NTA"
+		}
+		rows = append(rows, []string{fmt.Sprint(i + 1), s.Name, s.Mtknr, s.Program, s.Seat, nta, ""})
+	}
+	return rows
+}
@@ -0,0 +1,32 @@
+package pdfgen
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAttendanceSheets(t *testing.T) {
+	students := []AttendanceStudent{
+		{Name: "Abel, Anna", Mtknr: "1", Program: "IF", Seat: "1/1"},
+		{Name: "Berg, Bert", Mtknr: "2", Program: "IB", NTA: true},
+	}
+	want := [][]string{
+		{"1", "Abel, Anna", "1", "IF", "1/1", "", ""},
+		{"2", "Berg, Bert", "2", "IB", "", "NTA", ""},
+	}
+	if got := AttendanceRows(students); !reflect.DeepEqual(got, want) {
+		t.Errorf("AttendanceRows =\n%v\nwant\n%v", got, want)
+	}
+
+	sheets := []AttendanceSheet{
+		{Ancode: 7, Module: "Algebra", MainExamer: "Braun", Room: "R1.046", Duration: 90,
+			Starttime: time.Date(2026, 7, 11, 8, 30, 0, 0, time.Local), Students: students},
+		{Ancode: 7, Module: "Algebra", MainExamer: "Braun", Room: "R1.010", Duration: 120, Handicap: true,
+			Starttime: time.Date(2026, 7, 11, 8, 30, 0, 0, time.Local), Students: students[1:]},
+	}
+	buf, err := AttendanceSheets("Sommersemester 2026", sheets).Output()
+	if err != nil || buf.Len() == 0 {
+		t.Fatalf("AttendanceSheets: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package pdfgen
+
+import (
+	"fmt"
+
+	"github.com/johnfercher/maroto/pkg/consts"
+	"github.com/johnfercher/maroto/pkg/pdf"
+	"github.com/johnfercher/maroto/pkg/props"
+)
+
+// DoorSign is the door sign of one room on one exam day.
+type DoorSign struct {
+	Room     string
+	Day      string // "Sa, 11.07.2026"
+	Entries  []DoorSignEntry
+	Reserved []string // times the room is booked for exams but stays free (reserve)
+}
+
+// DoorSignEntry is one exam in the room.
+type DoorSignEntry struct {
+	Time     string // "08:30 – 10:00 Uhr (NTA bis 10:30 Uhr)"
+	Exam     string // "123. Modul"
+	Examer   string
+	Audience string // "Nachnamen A – Ka" or the programs
+	Hint     string // EXaHM/SEB hint, "" for paper exams
+}
+
+// DoorSigns renders one A4 door sign (landscape) per room and day: the room in large
+// print, then every exam with time, examer, name range or programs and the EXaHM/SEB
+// hint.
+func DoorSigns(semesterFull string, signs []DoorSign) pdf.Maroto {
+	m := pdf.NewMaroto(consts.Landscape, consts.A4)
+	m.SetPageMargins(10, 15, 10)
+	footer(m)
+
+	if len(signs) == 0 {
+		centeredRow(m, 10, 3, consts.Bold, fmt.Sprintf("Keine Prüfungsräume geplant — %s", semesterFull))
+		return m
+	}
+	for i, s := range signs {
+		if i > 0 {
+			m.AddPage()
+		}
+		m.Row(22, func() {
+			m.Col(12, func() {
+				m.Text("Prüfungsraum "+s.Room, props.Text{Top: 4, Size: 28, Style: consts.Bold, Align: consts.Center})
+			})
+		})
+		centeredRow(m, 12, 2, consts.Bold, fmt.Sprintf("%s — Prüfungen %s", s.Day, semesterFull))
+		for _, e := range s.Entries {
+			m.Row(12, func() {
+				m.Col(4, func() {
+					m.Text(e.Time, props.Text{Top: 4, Size: 13, Style: consts.Bold})
+				})
+				m.Col(8, func() {
+					m.Text(e.Exam, props.Text{Top: 4, Size: 13, Style: consts.Bold})
+				})
+			})
+			m.Row(14, func() {
+				m.Col(4, func() {
+					m.Text(e.Examer, props.Text{Size: 11})
+				})
+				m.Col(8, func() {
+					m.Text(e.Audience, props.Text{Size: 11})
+					if e.Hint != "" {
+						m.Text(e.Hint, props.Text{Top: 5, Size: 10, Style: consts.Italic})
+					}
+				})
+			})
+		}
+		for _, r := range s.Reserved {
+			m.Row(10, func() {
+				m.Col(4, func() {
+					m.Text(r, props.Text{Top: 3, Size: 11})
+				})
+				m.Col(8, func() {
+					m.Text("für Prüfungen reserviert — bitte freihalten", props.Text{Top: 3, Size: 11, Style: consts.Italic})
+				})
+			})
+		}
+		centeredRow(m, 14, 8, consts.Italic, "Bitte während der Prüfungen Ruhe auf dem Flur. Zutritt nur für Prüfungsteilnehmende.")
+	}
+	return m
+}
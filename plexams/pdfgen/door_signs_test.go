@@ -0,0 +1,15 @@
+package pdfgen
+
+import "testing"
+
+func TestDoorSignsRender(t *testing.T) {
+	signs := []DoorSign{{
+		Room: "R1.046", Day: "Sa, 11.07.2026",
+		Entries:  []DoorSignEntry{{Time: "08:30 – 10:00 Uhr", Exam: "7. Algebra", Examer: "Braun", Audience: "Nachnamen A – Ka", Hint: "EXaHM"}},
+		Reserved: []string{"12:00 Uhr"},
+	}}
+	buf, err := DoorSigns("Sommersemester 2026", signs).Output()
+	if err != nil || buf.Len() == 0 {
+		t.Fatalf("DoorSigns: %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package pdfgen
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/johnfercher/maroto/pkg/color"
+	"github.com/johnfercher/maroto/pkg/consts"
+	"github.com/johnfercher/maroto/pkg/pdf"
+	"github.com/johnfercher/maroto/pkg/props"
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// ExamDayProtocol renders the end-of-day protocol of an exam day (portrait): per exam
+// time the rooms with their exams, invigilators (with check-in), actual start and end and
+// absent students, the reserves, then the incidents and the complete event log.
+func ExamDayProtocol(semesterFull string, d *model.ExamDayDashboard, events []*model.ExamDayEvent) pdf.Maroto {
+	m := pdf.NewMaroto(consts.Portrait, consts.A4)
+	m.SetPageMargins(10, 15, 10)
+	footer(m)
+	gray := color.Color{Red: 211, Green: 211, Blue: 211}
+	table := func(header []string, rows [][]string, grid []uint) {
+		m.TableList(header, rows, props.TableList{
+			HeaderProp:           props.TableListContent{Size: 8, GridSizes: grid},
+			ContentProp:          props.TableListContent{Size: 8, GridSizes: grid},
+			Align:                consts.Left,
+			AlternatedBackground: &gray,
+			HeaderContentSpace:   1,
+			Line:                 false,
+		})
+	}
+
+	day := d.Date.Local()
+	centeredRow(m, 10, 3, consts.Bold, "Protokoll des Prüfungstags "+dayReplacer.Replace(day.Format("Mon, 02.01.2006")))
+	centeredRow(m, 8, 1, consts.Normal, fmt.Sprintf("%s — %d Studierende angemeldet, %d abwesend gemeldet, %d Vorkommnisse — erstellt %s Uhr",
+		semesterFull, d.Students, d.Absent, len(d.Incidents), d.GeneratedAt.Local().Format("02.01.06, 15:04")))
+
+	if len(d.Slots) == 0 {
+		centeredRow(m, 10, 3, consts.Normal, "An diesem Tag sind keine Prüfungen geplant.")
+		return m
+	}
+	for _, s := range d.Slots {
+		sectionRow(m, fmt.Sprintf("Prüfungen ab %s Uhr", s.Starttime.Local().Format("15:04")))
+		table([]string{"Raum", "Prüfungen", "Aufsichten", "Beginn", "Ende (geplant)", "abwesend"},
+			ExamDayRoomRows(s.Rooms), []uint{1, 4, 3, 1, 2, 1})
+		if len(s.Reserves) > 0 {
+			table([]string{"Reserve", "da", "geschickt in", "um"}, ExamDayReserveRows(s.Reserves), []uint{5, 2, 3, 2})
+		}
+	}
+
+	if len(d.Incidents) > 0 {
+		sectionRow(m, "Vorkommnisse")
+		table([]string{"Zeit", "Raum", "Prüfung", "Beschreibung", "erfasst von"}, ExamDayIncidentRows(d.Incidents), []uint{1, 1, 1, 6, 3})
+	}
+	if len(events) > 0 {
+		sectionRow(m, "Alle Ereignisse")
+		table([]string{"Zeit", "Termin", "Raum", "Ereignis", "erfasst von"}, ExamDayEventRows(events), []uint{1, 1, 1, 6, 3})
+	}
+	return m
+}
+
+func clock(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format("15:04")
+}
+
+// ExamDayRoomRows builds one row per room of an exam time.
+func ExamDayRoomRows(rooms []*model.ExamDayRoom) [][]string {
+	rows := make([][]string, 0, len(rooms))
+	for _, r := range rooms {
+		exams := make([]string, 0, len(r.Exams))
+		for _, e := range r.Exams {
+			exams = append(exams, fmt.Sprintf("%d. %s (%d)", e.Ancode, e.Module, e.Students))
+		}
+		invigilators := make([]string, 0, len(r.Invigilators))
+		for _, inv := range r.Invigilators {
+			line := inv.Name
+			if inv.Dispatched {
+				line += " (Reserve)"
+			}
+			if inv.CheckedInAt != nil {
+				line += " " + clock(inv.CheckedInAt)
+			} else {
+				line += " fehlt"
+			}
+			invigilators = append(invigilators, line)
+		}
+		absent := "-"
+		for _, e := range r.Exams {
+			if e.Absent != nil {
+				absent = fmt.Sprintf("%d", r.Absent)
+			}
+		}
+		rows = append(rows, []string{
+			r.RoomName, strings.Join(exams, ", "), strings.Join(invigilators, ", "),
+			clock(r.StartedAt), fmt.Sprintf("%s (%s)", clock(r.EndedAt), clock(&r.PlannedEnd)), absent,
+		})
+	}
+	return rows
+}
+
+// ExamDayReserveRows builds one row per reserve of an exam time.
+func ExamDayReserveRows(reserves []*model.ExamDayReserve) [][]string {
+	rows := make([][]string, 0, len(reserves))
+	for _, res := range reserves {
+		to := "-"
+		if res.DispatchedTo != nil {
+			to = *res.DispatchedTo
+		}
+		rows = append(rows, []string{res.Name, clock(res.CheckedInAt), to, clock(res.DispatchedAt)})
+	}
+	return rows
+}
+
+// ExamDayIncidentRows builds one row per incident.
+func ExamDayIncidentRows(incidents []*model.ExamDayEvent) [][]string {
+	rows := make([][]string, 0, len(incidents))
+	for _, ev := range incidents {
+		exam := "-"
+		if ev.Ancode != nil {
+			exam = fmt.Sprintf("%d", *ev.Ancode)
+		}
+		rows = append(rows, []string{clock(&ev.At), orDash(ev.RoomName), exam, orDash(ev.Note), orDash(ev.RecordedBy)})
+	}
+	return rows
+}
+
+// ExamDayEventRows builds one row per recorded event, described in German.
+func ExamDayEventRows(events []*model.ExamDayEvent) [][]string {
+	rows := make([][]string, 0, len(events))
+	for _, ev := range events {
+		rows = append(rows, []string{
+			clock(&ev.At), clock(&ev.Starttime), orDash(ev.RoomName), ExamDayEventText(ev), orDash(ev.RecordedBy),
+		})
+	}
+	return rows
+}
+
+// ExamDayEventText describes an event in German, e.g. "Max Muster ist da".
+func ExamDayEventText(ev *model.ExamDayEvent) string {
+	who := "?"
+	if ev.InvigilatorName != nil {
+		who = *ev.InvigilatorName
+	} else if ev.InvigilatorID != nil {
+		who = fmt.Sprintf("#%d", *ev.InvigilatorID)
+	}
+	var text string
+	switch ev.Kind {
+	case model.ExamDayEventKindCheckIn:
+		text = who + " ist da"
+		if ev.RoomName == nil {
+			text += " (Reserve)"
+		}
+	case model.ExamDayEventKindDispatch:
+		text = fmt.Sprintf("Reserve %s geschickt nach %s", who, orDash(ev.RoomName))
+	case model.ExamDayEventKindStart:
+		text = "Prüfungsbeginn"
+	case model.ExamDayEventKindEnd:
+		text = "Prüfungsende"
+	case model.ExamDayEventKindAbsent:
+		if ev.Ancode != nil && ev.Count != nil {
+			text = fmt.Sprintf("%d abwesend in Prüfung %d", *ev.Count, *ev.Ancode)
+		}
+	case model.ExamDayEventKindIncident:
+		text = "Vorkommnis"
+		if ev.Ancode != nil {
+			text += fmt.Sprintf(" (Prüfung %d)", *ev.Ancode)
+		}
+	}
+	if ev.Note != nil && *ev.Note != "" {
+		text += ": " + *ev.Note
+	}
+	return text
+}
+
+func orDash(s *string) string {
+	if s == nil || *s == "" {
+		return "-"
+	}
+	return *s
+}
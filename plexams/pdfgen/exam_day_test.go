@@ -0,0 +1,70 @@
+package pdfgen
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+func TestExamDayProtocolRenders(t *testing.T) {
+	start := time.Date(2026, 7, 13, 10, 30, 0, 0, time.Local)
+	room, note, by := "R1.049", "Feueralarm, 10 Minuten Unterbrechung", "planer@hm.edu"
+	absent := 2
+	incident := &model.ExamDayEvent{Kind: model.ExamDayEventKindIncident, Starttime: start, RoomName: &room, At: start.Add(40 * time.Minute), Note: &note, RecordedBy: &by}
+	d := &model.ExamDayDashboard{
+		Date: start, Students: 30, Absent: 2, GeneratedAt: start.Add(8 * time.Hour),
+		Incidents: []*model.ExamDayEvent{incident},
+		Slots: []*model.ExamDaySlot{{
+			Starttime: start,
+			Rooms: []*model.ExamDayRoom{{
+				RoomName: room, Status: model.ExamDayRoomStatusEnded, PlannedEnd: start.Add(90 * time.Minute),
+				Exams:        []*model.ExamDayRoomExam{{Ancode: 324, Module: "Datenbanken", Students: 30, Absent: &absent}},
+				Invigilators: []*model.ExamDayInvigilator{{InvigilatorID: 1, Name: "Prof. Dr. Erika Mustermann"}},
+				Absent:       2, Incidents: 1,
+			}},
+			Reserves: []*model.ExamDayReserve{{InvigilatorID: 2, Name: "Max Muster"}},
+		}},
+	}
+	for _, dashboard := range []*model.ExamDayDashboard{d, {Date: start}} {
+		buf, err := ExamDayProtocol("Sommersemester 2026", dashboard, []*model.ExamDayEvent{incident}).Output()
+		if err != nil {
+			t.Fatalf("Output() error: %v", err)
+		}
+		if buf.Len() == 0 {
+			t.Fatal("rendered PDF is empty")
+		}
+	}
+
+	rows := ExamDayRoomRows(d.Slots[0].Rooms)
+	if got := rows[0][2]; got != "Prof. Dr. Erika Mustermann fehlt" {
+		t.Errorf("invigilator column = %q", got)
+	}
+	if got := ExamDayEventText(incident); !strings.HasPrefix(got, "Vorkommnis: Feueralarm") {
+		t.Errorf("event text = %q", got)
+	}
+}
+
+func TestExamProtocolsRenders(t *testing.T) {
+	start := time.Date(2026, 7, 13, 10, 30, 0, 0, time.Local)
+	note := "Klausur später ausgeteilt"
+	protocols := []*model.ExamProtocol{{
+		Ancode: 324, Module: "Datenbanken", RoomName: "R1.049", Starttime: start,
+		Registered: 30, Present: 27, Absent: 3, Incidents: []string{"Feueralarm"}, Note: &note,
+	}}
+	stat := &model.NoShowStatistics{Exams: 1, Rooms: 1, Registered: 30, Present: 27, Absent: 3, Rate: 10,
+		ByExam: []*model.NoShowExam{{Ancode: 324, Module: "Datenbanken", Registered: 30, Present: 27, Absent: 3, Rate: 10, Complete: true}}}
+	for _, ps := range [][]*model.ExamProtocol{protocols, nil} {
+		buf, err := ExamProtocols("Sommersemester 2026", ps, stat).Output()
+		if err != nil {
+			t.Fatalf("Output() error: %v", err)
+		}
+		if buf.Len() == 0 {
+			t.Fatal("rendered PDF is empty")
+		}
+	}
+	if got := ExamProtocolRows(protocols)[0][7]; got != "Feueralarm; Klausur später ausgeteilt" {
+		t.Errorf("remarks column = %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+package pdfgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/johnfercher/maroto/pkg/color"
+	"github.com/johnfercher/maroto/pkg/consts"
+	"github.com/johnfercher/maroto/pkg/pdf"
+	"github.com/johnfercher/maroto/pkg/props"
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// ExamProtocols renders the recorded exam protocols (landscape): the no-show summary, one
+// row per exam and room in time order, then the exams by no-show rate.
+func ExamProtocols(semesterFull string, protocols []*model.ExamProtocol, stat *model.NoShowStatistics) pdf.Maroto {
+	m := pdf.NewMaroto(consts.Landscape, consts.A4)
+	m.SetPageMargins(10, 15, 10)
+	footer(m)
+	gray := color.Color{Red: 211, Green: 211, Blue: 211}
+	table := func(header []string, rows [][]string, grid []uint) {
+		m.TableList(header, rows, props.TableList{
+			HeaderProp:           props.TableListContent{Size: 8, GridSizes: grid},
+			ContentProp:          props.TableListContent{Size: 8, GridSizes: grid},
+			Align:                consts.Left,
+			AlternatedBackground: &gray,
+			HeaderContentSpace:   1,
+			Line:                 false,
+		})
+	}
+
+	centeredRow(m, 10, 3, consts.Bold, "Prüfungsprotokolle "+semesterFull)
+	centeredRow(m, 8, 1, consts.Normal, fmt.Sprintf("%d Prüfungen in %d Räumen — %d angemeldet, %d anwesend, %d abwesend (%.1f %%)",
+		stat.Exams, stat.Rooms, stat.Registered, stat.Present, stat.Absent, stat.Rate))
+
+	if len(protocols) == 0 {
+		centeredRow(m, 10, 3, consts.Normal, "Es sind noch keine Protokolle erfasst.")
+		return m
+	}
+	sectionRow(m, "Protokolle")
+	table([]string{"Beginn", "Prüfung", "Raum", "angem.", "anw.", "abw.", "Ende", "Vorkommnisse / Bemerkung"},
+		ExamProtocolRows(protocols), []uint{1, 3, 1, 1, 1, 1, 1, 3})
+	sectionRow(m, "Nicht erschienen je Prüfung")
+	table([]string{"Prüfung", "Prüfende", "angemeldet", "anwesend", "abwesend", "Quote", "vollständig"},
+		NoShowExamRows(stat.ByExam), []uint{4, 3, 1, 1, 1, 1, 1})
+	return m
+}
+
+// ExamProtocolRows builds one row per protocol.
+func ExamProtocolRows(protocols []*model.ExamProtocol) [][]string {
+	rows := make([][]string, 0, len(protocols))
+	for _, p := range protocols {
+		remarks := append([]string{}, p.Incidents...)
+		if p.Note != nil && *p.Note != "" {
+			remarks = append(remarks, *p.Note)
+		}
+		text := strings.Join(remarks, "; ")
+		if text == "" {
+			text = "-"
+		}
+		rows = append(rows, []string{
+			p.Starttime.Local().Format("02.01. 15:04"), fmt.Sprintf("%d. %s", p.Ancode, p.Module), p.RoomName,
+			fmt.Sprintf("%d", p.Registered), fmt.Sprintf("%d", p.Present), fmt.Sprintf("%d", p.Absent),
+			clock(p.ActualEnd), text,
+		})
+	}
+	return rows
+}
+
+// NoShowExamRows builds one row per exam of the no-show statistics.
+func NoShowExamRows(exams []*model.NoShowExam) [][]string {
+	rows := make([][]string, 0, len(exams))
+	for _, e := range exams {
+		complete := "ja"
+		if !e.Complete {
+			complete = "nein"
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("%d. %s", e.Ancode, e.Module), e.MainExamer,
+			fmt.Sprintf("%d", e.Registered), fmt.Sprintf("%d", e.Present), fmt.Sprintf("%d", e.Absent),
+			fmt.Sprintf("%.1f %%", e.Rate), complete,
+		})
+	}
+	return rows
+}
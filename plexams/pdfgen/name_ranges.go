@@ -0,0 +1,140 @@
+package pdfgen
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/johnfercher/maroto/pkg/color"
+	"github.com/johnfercher/maroto/pkg/consts"
+	"github.com/johnfercher/maroto/pkg/pdf"
+	"github.com/johnfercher/maroto/pkg/props"
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// RoomNotice is one door notice: the split exams in a room at a start time with the
+// room's name range of each.
+type RoomNotice struct {
+	Room      string
+	Starttime string
+	Exams     []RoomNoticeExam
+}
+
+// RoomNoticeExam is one exam on a door notice.
+type RoomNoticeExam struct {
+	Header string // "123. Modul (Prüfer)"
+	Range  string // "A – Ka"
+}
+
+// RoomNotices groups the ranges of the split exams by room and start time (sorted by
+// start time, then room); rooms holding only NTAs of an exam get no entry.
+func RoomNotices(exams []*model.ExamNameRanges) []RoomNotice {
+	type key struct {
+		start int64
+		room  string
+	}
+	byKey := make(map[key]*RoomNotice)
+	keys := make([]key, 0)
+	for _, e := range exams {
+		for _, r := range e.Ranges {
+			if r.From == "" {
+				continue
+			}
+			k := key{e.Starttime.Unix(), r.Room}
+			n, ok := byKey[k]
+			if !ok {
+				n = &RoomNotice{Room: r.Room, Starttime: e.Starttime.Local().Format("02.01.06, 15:04 Uhr")}
+				byKey[k] = n
+				keys = append(keys, k)
+			}
+			n.Exams = append(n.Exams, RoomNoticeExam{
+				Header: fmt.Sprintf("%d. %s (%s)", e.Ancode, e.Module, e.MainExamer),
+				Range:  fmt.Sprintf("%s – %s", r.From, r.To),
+			})
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].start != keys[j].start {
+			return keys[i].start < keys[j].start
+		}
+		return keys[i].room < keys[j].room
+	})
+	notices := make([]RoomNotice, 0, len(keys))
+	for _, k := range keys {
+		notices = append(notices, *byKey[k])
+	}
+	return notices
+}
+
+// NameRangeNotices renders the door notices of the split exams (landscape, one page per
+// room and start time): the room, the exams and, in large print, the name range.
+func NameRangeNotices(semesterFull string, exams []*model.ExamNameRanges) pdf.Maroto {
+	m := pdf.NewMaroto(consts.Landscape, consts.A4)
+	m.SetPageMargins(10, 15, 10)
+	footer(m)
+
+	notices := RoomNotices(exams)
+	if len(notices) == 0 {
+		centeredRow(m, 10, 3, consts.Bold, fmt.Sprintf("Keine auf mehrere Räume verteilten Prüfungen — %s", semesterFull))
+		return m
+	}
+	for i, n := range notices {
+		if i > 0 {
+			m.AddPage()
+		}
+		m.Row(25, func() {
+			m.Col(12, func() {
+				m.Text(fmt.Sprintf("Raum %s", n.Room), props.Text{Top: 5, Size: 28, Style: consts.Bold, Align: consts.Center})
+			})
+		})
+		centeredRow(m, 10, 2, consts.Normal, fmt.Sprintf("Prüfungen am %s — %s", n.Starttime, semesterFull))
+		for _, e := range n.Exams {
+			centeredRow(m, 14, 6, consts.Bold, e.Header)
+			m.Row(30, func() {
+				m.Col(12, func() {
+					m.Text("Nachnamen "+e.Range, props.Text{Top: 6, Size: 40, Style: consts.Bold, Align: consts.Center})
+				})
+			})
+		}
+		centeredRow(m, 12, 6, consts.Italic,
+			"Studierende mit Nachteilsausgleich (NTA) wurden persönlich über ihren Raum informiert.")
+	}
+	return m
+}
+
+// NameRangeOverview renders the overview of the split exams (portrait): per exam its
+// rooms with name range, number of students and NTAs outside the range.
+func NameRangeOverview(semesterFull string, exams []*model.ExamNameRanges) pdf.Maroto {
+	m := pdf.NewMaroto(consts.Portrait, consts.A4)
+	m.SetPageMargins(10, 15, 10)
+	footer(m)
+	gray := color.Color{Red: 211, Green: 211, Blue: 211}
+
+	centeredRow(m, 10, 3, consts.Bold, fmt.Sprintf("Raumaufteilung nach Nachnamen — %s", semesterFull))
+	for _, e := range exams {
+		sectionRow(m, fmt.Sprintf("%d. %s (%s), %s Uhr", e.Ancode, e.Module, e.MainExamer, e.Starttime.Local().Format("02.01.06, 15:04")))
+		m.TableList([]string{"Raum", "Nachnamen", "Studierende", "NTA (gesondert)"}, nameRangeRows(e), props.TableList{
+			HeaderProp:           props.TableListContent{Size: 9, GridSizes: []uint{3, 3, 3, 3}},
+			ContentProp:          props.TableListContent{Size: 9, GridSizes: []uint{3, 3, 3, 3}},
+			Align:                consts.Left,
+			AlternatedBackground: &gray,
+			HeaderContentSpace:   1,
+			Line:                 false,
+		})
+		if !e.Contiguous {
+			centeredRow(m, 8, 1, consts.Italic, "Achtung: die Bereiche überschneiden sich, die Aufteilung wurde noch nicht neu sortiert.")
+		}
+	}
+	return m
+}
+
+func nameRangeRows(e *model.ExamNameRanges) [][]string {
+	rows := make([][]string, 0, len(e.Ranges))
+	for _, r := range e.Ranges {
+		names := "nur NTA"
+		if r.From != "" {
+			names = fmt.Sprintf("%s – %s", r.From, r.To)
+		}
+		rows = append(rows, []string{r.Room, names, fmt.Sprint(r.Students), fmt.Sprint(r.Pinned)})
+	}
+	return rows
+}
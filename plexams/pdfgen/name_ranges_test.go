@@ -0,0 +1,42 @@
+package pdfgen
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+func TestNameRangeNotices(t *testing.T) {
+	start := time.Date(2026, 7, 11, 8, 30, 0, 0, time.Local)
+	exams := []*model.ExamNameRanges{
+		{Ancode: 7, Module: "Algebra", MainExamer: "Braun", Starttime: start, Contiguous: true, Ranges: []*model.RoomNameRange{
+			{Room: "R1.046", From: "A", To: "Ka", Students: 30},
+			{Room: "R1.006", From: "Ke", To: "Z", Students: 28, Pinned: 1},
+		}},
+		{Ancode: 9, Module: "Physik", MainExamer: "Zorn", Starttime: start, Contiguous: true, Ranges: []*model.RoomNameRange{
+			{Room: "R1.046", From: "A", To: "M", Students: 10},
+			{Room: "R2.010", Pinned: 1},
+			{Room: "R2.012", From: "N", To: "Z", Students: 10},
+		}},
+	}
+	got := RoomNotices(exams)
+	want := []RoomNotice{
+		{Room: "R1.006", Starttime: "11.07.26, 08:30 Uhr", Exams: []RoomNoticeExam{{Header: "7. Algebra (Braun)", Range: "Ke – Z"}}},
+		{Room: "R1.046", Starttime: "11.07.26, 08:30 Uhr", Exams: []RoomNoticeExam{
+			{Header: "7. Algebra (Braun)", Range: "A – Ka"}, {Header: "9. Physik (Zorn)", Range: "A – M"},
+		}},
+		{Room: "R2.012", Starttime: "11.07.26, 08:30 Uhr", Exams: []RoomNoticeExam{{Header: "9. Physik (Zorn)", Range: "N – Z"}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RoomNotices =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if buf, err := NameRangeNotices("Sommersemester 2026", exams).Output(); err != nil || buf.Len() == 0 {
+		t.Errorf("NameRangeNotices: %v", err)
+	}
+	if buf, err := NameRangeOverview("Sommersemester 2026", exams).Output(); err != nil || buf.Len() == 0 {
+		t.Errorf("NameRangeOverview: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package pdfgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/johnfercher/maroto/pkg/color"
+	"github.com/johnfercher/maroto/pkg/consts"
+	"github.com/johnfercher/maroto/pkg/pdf"
+	"github.com/johnfercher/maroto/pkg/props"
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// placementMaxStudents caps the student names listed per blocker; the rest is counted.
+const placementMaxStudents = 5
+
+// PlacementExplanation renders the "why is this exam where it is" report of one exam
+// (portrait): its binding constraints, then every start time of the exam period with
+// either the cost difference of moving there or what rules it out. Meant to be handed
+// to the examer or the Prüfungsamt, so blocking students are named.
+func PlacementExplanation(semesterFull string, ex *model.ExamPlacementExplanation) pdf.Maroto {
+	m := pdf.NewMaroto(consts.Portrait, consts.A4)
+	m.SetPageMargins(10, 15, 10)
+	footer(m)
+	gray := color.Color{Red: 211, Green: 211, Blue: 211}
+
+	centeredRow(m, 10, 3, consts.Bold,
+		fmt.Sprintf("Terminbegründung %d. %s (%s) — %s", ex.Ancode, ex.Module, ex.MainExamer, semesterFull))
+	current := "nicht geplant"
+	if ex.Starttime != nil {
+		current = ex.Starttime.Local().Format("02.01.06 15:04") + " Uhr"
+	}
+	centeredRow(m, 8, 1, consts.Normal, "Aktueller Termin: "+current)
+
+	sectionRow(m, "Bindende Bedingungen")
+	m.TableList([]string{"Bedingung"}, placementConstraintRows(ex), props.TableList{
+		HeaderProp:           props.TableListContent{Size: 9, GridSizes: []uint{12}},
+		ContentProp:          props.TableListContent{Size: 9, GridSizes: []uint{12}},
+		Align:                consts.Left,
+		AlternatedBackground: &gray,
+		HeaderContentSpace:   1,
+		Line:                 false,
+	})
+	if len(ex.HardViolations) > 0 {
+		centeredRow(m, 8, 2, consts.Italic,
+			"Achtung, der gespeicherte Plan verletzt harte Bedingungen: "+strings.Join(ex.HardViolations, "; "))
+	}
+
+	sectionRow(m, "Alternative Termine (zulässige zuerst, günstigste zuerst)")
+	m.TableList([]string{"Termin", "Status", "Δ Kosten", "Begründung"}, placementAlternativeRows(ex.Alternatives), props.TableList{
+		HeaderProp:           props.TableListContent{Size: 8, GridSizes: []uint{2, 2, 1, 7}},
+		ContentProp:          props.TableListContent{Size: 8, GridSizes: []uint{2, 2, 1, 7}},
+		Align:                consts.Left,
+		AlternatedBackground: &gray,
+		HeaderContentSpace:   1,
+		Line:                 false,
+	})
+	centeredRow(m, 8, 2, consts.Italic,
+		"Δ Kosten: Änderung der Zielfunktion des Generators bei Verschiebung nur dieser Prüfung (positiv = schlechter).")
+
+	return m
+}
+
+func placementConstraintRows(ex *model.ExamPlacementExplanation) [][]string {
+	rows := make([][]string, 0, len(ex.BindingConstraints)+1)
+	for _, c := range ex.BindingConstraints {
+		rows = append(rows, []string{c})
+	}
+	if len(rows) == 0 {
+		rows = append(rows, []string{"keine — der Termin ergibt sich nur aus der Optimierung"})
+	}
+	return rows
+}
+
+func placementAlternativeRows(alts []*model.PlacementAlternative) [][]string {
+	rows := make([][]string, 0, len(alts))
+	for _, a := range alts {
+		status, delta := "blockiert", ""
+		switch {
+		case a.Current:
+			status, delta = "aktuell", "0"
+		case a.Feasible:
+			status, delta = "möglich", fmt.Sprintf("%+.1f", a.CostDelta)
+		}
+		reasons := make([]string, 0, len(a.Blockers))
+		for _, b := range a.Blockers {
+			reasons = append(reasons, placementBlockerText(b))
+		}
+		rows = append(rows, []string{a.Starttime.Local().Format("02.01. 15:04"), status, delta, strings.Join(reasons, "; ")})
+	}
+	return rows
+}
+
+// placementBlockerText renders one blocker with the first few students by name.
+func placementBlockerText(b *model.PlacementBlocker) string {
+	text := b.Message
+	if len(b.Students) == 0 {
+		return text
+	}
+	names := make([]string, 0, placementMaxStudents)
+	for i, s := range b.Students {
+		if i == placementMaxStudents {
+			names = append(names, fmt.Sprintf("+%d weitere", len(b.Students)-placementMaxStudents))
+			break
+		}
+		name := s.Name
+		if name == "" {
+			name = s.Mtknr
+		}
+		if s.Program != "" {
+			name += " (" + s.Program + ")"
+		}
+		names = append(names, name)
+	}
+	return text + ": " + strings.Join(names, ", ")
+}
@@ -0,0 +1,45 @@
+package pdfgen
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+func TestPlacementExplanationRenders(t *testing.T) {
+	start := time.Date(2026, 7, 11, 8, 30, 0, 0, time.Local)
+	students := make([]*model.PlacementStudent, 0, 7)
+	for i := range 7 {
+		students = append(students, &model.PlacementStudent{Mtknr: string(rune('a' + i)), Name: "Name", Program: "IF"})
+	}
+	ex := &model.ExamPlacementExplanation{
+		Ancode: 42, Module: "Algorithmen", MainExamer: "Braun", Starttime: &start,
+		SameSlotAncodes:    []int{42},
+		BindingConstraints: []string{"fester Tag: 11.07.26"},
+		AllowedStarttimes:  4,
+		Alternatives: []*model.PlacementAlternative{
+			{Starttime: start, Current: true, Feasible: true},
+			{Starttime: start.Add(2 * time.Hour), Feasible: true, CostDelta: 12.5},
+			{Starttime: start.Add(24 * time.Hour), Blockers: []*model.PlacementBlocker{
+				{Constraint: "student-clash", Message: "7 Studierende haben zeitlich überlappend Prüfung 7", Ancodes: []int{7}, Students: students},
+			}},
+		},
+	}
+	buf, err := PlacementExplanation("Sommersemester 2026", ex).Output()
+	if err != nil {
+		t.Fatalf("Output() error: %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Fatal("empty PDF")
+	}
+
+	rows := placementAlternativeRows(ex.Alternatives)
+	if rows[0][1] != "aktuell" || rows[1][2] != "+12.5" || rows[2][1] != "blockiert" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+	if !strings.HasSuffix(rows[2][3], "+2 weitere") {
+		t.Errorf("student list must be capped, got %q", rows[2][3])
+	}
+}
@@ -0,0 +1,164 @@
+package pdfgen
+
+import (
+	"fmt"
+
+	"github.com/johnfercher/maroto/pkg/color"
+	"github.com/johnfercher/maroto/pkg/consts"
+	"github.com/johnfercher/maroto/pkg/pdf"
+	"github.com/johnfercher/maroto/pkg/props"
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// RoomUtilization renders the room-utilization statistics PDF (portrait) for the room
+// requests to the Gebäudemanagement: key figures (next to the compared semester when
+// there is one), own vs. requested rooms, per room, the peak start times and the Anny
+// bookings with their idle minutes.
+func RoomUtilization(semesterFull string, stat *model.RoomUtilization) pdf.Maroto {
+	m := pdf.NewMaroto(consts.Portrait, consts.A4)
+	m.SetPageMargins(10, 15, 10)
+	footer(m)
+	gray := color.Color{Red: 211, Green: 211, Blue: 211}
+	table := func(header []string, rows [][]string, grid []uint) {
+		m.TableList(header, rows, props.TableList{
+			HeaderProp:           props.TableListContent{Size: 8, GridSizes: grid},
+			ContentProp:          props.TableListContent{Size: 8, GridSizes: grid},
+			Align:                consts.Left,
+			AlternatedBackground: &gray,
+			HeaderContentSpace:   1,
+			Line:                 false,
+		})
+	}
+
+	centeredRow(m, 10, 3, consts.Bold, fmt.Sprintf("Raumauslastung im Prüfungszeitraum — %s", semesterFull))
+	centeredRow(m, 8, 1, consts.Normal,
+		"Ein Raum zählt zu jedem Termin, an dem er geplant ist, mit allen Plätzen — egal wie viele Prüfungen ihn teilen.")
+
+	sectionRow(m, "Kennzahlen")
+	if stat.Previous != nil {
+		table([]string{"Kennzahl", stat.Summary.Workspace, stat.Previous.Workspace},
+			roomUtilizationKeyFigureRows(stat.Summary, stat.Previous), []uint{6, 3, 3})
+		centeredRow(m, 8, 1, consts.Italic, "Vorsemester mit den heutigen Platzzahlen der Räume gerechnet.")
+	} else {
+		table([]string{"Kennzahl", "Wert"}, roomUtilizationKeyFigureRows(stat.Summary, nil), []uint{9, 3})
+	}
+
+	sectionRow(m, "Eigene vs. angefragte Räume")
+	table([]string{"Raumart", "Räume", "Belegungen", "Plätze", "belegt", "Auslastung", "Anteil Stud."},
+		roomKindRows(stat.Summary.ByKind), []uint{3, 1, 2, 2, 1, 1, 2})
+
+	sectionRow(m, "Räume")
+	table([]string{"Raum", "Art", "Plätze", "Termine", "Plätze ges.", "belegt", "Auslastung"},
+		roomUsageRows(stat.Rooms), []uint{2, 2, 1, 2, 2, 1, 2})
+
+	sectionRow(m, "Spitzenzeiten")
+	table([]string{"Termin", "Prüfungen", "Räume", "Plätze", "Studierende", "Auslastung"},
+		slotUsageRows(stat.PeakSlots), []uint{4, 2, 1, 2, 2, 1})
+
+	if len(stat.AnnyBookings) > 0 {
+		sectionRow(m, "Anny-Buchungen")
+		table([]string{"Raum", "von", "bis", "gebucht", "genutzt", "ungenutzt", "Prüf.", "max. Stud."},
+			annyBookingUsageRows(stat.AnnyBookings), []uint{2, 2, 2, 1, 1, 2, 1, 1})
+	}
+
+	return m
+}
+
+func roomUtilizationKeyFigureRows(s, prev *model.RoomUtilizationSummary) [][]string {
+	peak := func(s *model.RoomUtilizationSummary) string {
+		if s.PeakStarttime == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%d (%s)", s.PeakStudents, s.PeakStarttime.Local().Format("02.01. 15:04"))
+	}
+	figures := []struct {
+		label string
+		value func(*model.RoomUtilizationSummary) string
+	}{
+		{"Prüfungen", func(s *model.RoomUtilizationSummary) string { return fmt.Sprintf("%d", s.Exams) }},
+		{"Termine mit Räumen", func(s *model.RoomUtilizationSummary) string { return fmt.Sprintf("%d", s.SlotsUsed) }},
+		{"genutzte Räume", func(s *model.RoomUtilizationSummary) string { return fmt.Sprintf("%d", s.RoomsUsed) }},
+		{"Raumbelegungen (Raum × Termin)", func(s *model.RoomUtilizationSummary) string { return fmt.Sprintf("%d", s.RoomSlots) }},
+		{"Plätze verfügbar / belegt", func(s *model.RoomUtilizationSummary) string {
+			return fmt.Sprintf("%d / %d", s.SeatsAvailable, s.SeatsUsed)
+		}},
+		{"Auslastung der Plätze", func(s *model.RoomUtilizationSummary) string { return pct(s.Utilization) }},
+		{"Reserveräume (Raum × Termin)", func(s *model.RoomUtilizationSummary) string { return fmt.Sprintf("%d", s.ReserveRoomSlots) }},
+		{"Spitze: Studierende gleichzeitig", peak},
+		{"Anny-Buchungen (davon ohne Prüfung)", func(s *model.RoomUtilizationSummary) string {
+			return fmt.Sprintf("%d (%d)", s.AnnyBookings, s.AnnyUnusedBookings)
+		}},
+		{"Anny gebucht / ungenutzt (Stunden)", func(s *model.RoomUtilizationSummary) string {
+			return fmt.Sprintf("%s / %s", hours(s.AnnyBookedMinutes), hours(s.AnnyIdleMinutes))
+		}},
+		{"Anny ungenutzt", func(s *model.RoomUtilizationSummary) string { return pct(s.AnnyIdleShare) }},
+	}
+	rows := make([][]string, 0, len(figures))
+	for _, f := range figures {
+		row := []string{f.label, f.value(s)}
+		if prev != nil {
+			row = append(row, f.value(prev))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func hours(minutes int) string { return fmt.Sprintf("%.1f", float64(minutes)/60) }
+
+// roomKindLabel names a RoomRequestType for the statistics.
+func roomKindLabel(k model.RoomRequestType) string {
+	switch k {
+	case model.RoomRequestTypeAnny:
+		return "Anny"
+	case model.RoomRequestTypeManagement:
+		return "Gebäudemanagement"
+	default:
+		return "eigener Raum"
+	}
+}
+
+func roomKindRows(kinds []*model.RoomKindShare) [][]string {
+	rows := make([][]string, 0, len(kinds))
+	for _, k := range kinds {
+		rows = append(rows, []string{
+			roomKindLabel(k.RequestWith), fmt.Sprintf("%d", k.Rooms), fmt.Sprintf("%d", k.RoomSlots),
+			fmt.Sprintf("%d", k.SeatsAvailable), fmt.Sprintf("%d", k.SeatsUsed), pct(k.Utilization), pct(k.Share),
+		})
+	}
+	return rows
+}
+
+func roomUsageRows(rooms []*model.RoomUsage) [][]string {
+	rows := make([][]string, 0, len(rooms))
+	for _, r := range rooms {
+		rows = append(rows, []string{
+			r.Room, roomKindLabel(r.RequestWith), fmt.Sprintf("%d", r.Seats), fmt.Sprintf("%d", r.SlotsUsed),
+			fmt.Sprintf("%d", r.SeatsAvailable), fmt.Sprintf("%d", r.SeatsUsed), pct(r.Utilization),
+		})
+	}
+	return rows
+}
+
+func slotUsageRows(slots []*model.SlotUsage) [][]string {
+	rows := make([][]string, 0, len(slots))
+	for _, s := range slots {
+		rows = append(rows, []string{
+			FormatTermin(s.Starttime.Local()), fmt.Sprintf("%d", s.Exams), fmt.Sprintf("%d", s.Rooms),
+			fmt.Sprintf("%d", s.SeatsAvailable), fmt.Sprintf("%d", s.Students), pct(s.Utilization),
+		})
+	}
+	return rows
+}
+
+func annyBookingUsageRows(bookings []*model.AnnyBookingUsage) [][]string {
+	rows := make([][]string, 0, len(bookings))
+	for _, b := range bookings {
+		rows = append(rows, []string{
+			b.Room, b.From.Local().Format("02.01. 15:04"), b.Until.Local().Format("02.01. 15:04"),
+			hours(b.BookedMinutes), hours(b.UsedMinutes), hours(b.IdleMinutes),
+			fmt.Sprintf("%d", b.Exams), fmt.Sprintf("%d", b.MaxStudents),
+		})
+	}
+	return rows
+}
@@ -0,0 +1,67 @@
+package pdfgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+func sampleRoomUtilization() *model.RoomUtilization {
+	start := time.Date(2026, 7, 13, 8, 30, 0, 0, time.Local)
+	summary := func(ws string) *model.RoomUtilizationSummary {
+		return &model.RoomUtilizationSummary{
+			Workspace: ws, Exams: 120, SlotsUsed: 30, RoomsUsed: 12, RoomSlots: 180,
+			SeatsAvailable: 9000, SeatsUsed: 5400, Utilization: 60, ReserveRoomSlots: 20,
+			ByKind: []*model.RoomKindShare{
+				{RequestWith: model.RoomRequestTypeNone, Rooms: 8, RoomSlots: 150, SeatsAvailable: 7000, SeatsUsed: 4500, Utilization: 64.3, Share: 83.3},
+				{RequestWith: model.RoomRequestTypeAnny, Rooms: 3, RoomSlots: 25, SeatsAvailable: 1500, SeatsUsed: 700, Utilization: 46.7, Share: 13},
+				{RequestWith: model.RoomRequestTypeManagement, Rooms: 1, RoomSlots: 5, SeatsAvailable: 500, SeatsUsed: 200, Utilization: 40, Share: 3.7},
+			},
+			AnnyBookings: 10, AnnyBookedMinutes: 2400, AnnyIdleMinutes: 600, AnnyIdleShare: 25, AnnyUnusedBookings: 1,
+			PeakStudents: 400, PeakStarttime: &start,
+		}
+	}
+	return &model.RoomUtilization{
+		Summary: summary("2026-SS"),
+		Rooms: []*model.RoomUsage{
+			{Room: "R1.046", RequestWith: model.RoomRequestTypeAnny, Seats: 60, SlotsUsed: 10, SeatsAvailable: 600, SeatsUsed: 400, Utilization: 66.7},
+		},
+		Cells: []*model.RoomSlotUsage{
+			{Room: "R1.046", RequestWith: model.RoomRequestTypeAnny, Starttime: start, Seats: 60, Exams: 2, Students: 50, Utilization: 83.3},
+		},
+		PeakSlots: []*model.SlotUsage{{Starttime: start, Exams: 8, Rooms: 10, SeatsAvailable: 600, Students: 400, Utilization: 66.7}},
+		AnnyBookings: []*model.AnnyBookingUsage{
+			{Room: "R1.046", From: start, Until: start.Add(4 * time.Hour), BookedMinutes: 240, UsedMinutes: 150, IdleMinutes: 90, Exams: 2, MaxStudents: 50},
+		},
+		Previous: summary("2025-WS"),
+	}
+}
+
+func TestRoomUtilizationRenders(t *testing.T) {
+	for _, stat := range []*model.RoomUtilization{
+		sampleRoomUtilization(),
+		{Summary: &model.RoomUtilizationSummary{Workspace: "2026-SS"}}, // nothing planned yet, no previous
+	} {
+		buf, err := RoomUtilization("Sommersemester 2026", stat).Output()
+		if err != nil {
+			t.Fatalf("Output() error: %v", err)
+		}
+		if buf.Len() == 0 {
+			t.Fatal("rendered PDF is empty")
+		}
+	}
+}
+
+func TestRoomUtilizationKeyFigureRows(t *testing.T) {
+	stat := sampleRoomUtilization()
+	rows := roomUtilizationKeyFigureRows(stat.Summary, stat.Previous)
+	for _, r := range rows {
+		if len(r) != 3 {
+			t.Fatalf("row %v: want 3 columns with the previous semester", r)
+		}
+	}
+	if rows := roomUtilizationKeyFigureRows(stat.Summary, nil); len(rows[0]) != 2 {
+		t.Errorf("want 2 columns without a previous semester")
+	}
+}
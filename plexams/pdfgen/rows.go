@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/obcode/plexams.go/graph/model"
@@ -132,6 +133,9 @@ func ConstraintsRows(exams []*model.ZPAExamWithConstraints, sameSlotExam map[int
 			if c.RoomConstraints.PlacesWithSocket {
 				contents = append(contents, []string{"", "", "", "", "- Steckdosen an den Sitzplätzen"})
 			}
+			if len(c.RoomConstraints.RequiredTags) > 0 {
+				contents = append(contents, []string{"", "", "", "", "- Raummerkmale: " + strings.Join(c.RoomConstraints.RequiredTags, ", ")})
+			}
 		}
 		contents = append(contents, []string{"", "", "", ""})
 	}
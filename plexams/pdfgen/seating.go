@@ -0,0 +1,148 @@
+package pdfgen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/johnfercher/maroto/pkg/color"
+	"github.com/johnfercher/maroto/pkg/consts"
+	"github.com/johnfercher/maroto/pkg/pdf"
+	"github.com/johnfercher/maroto/pkg/props"
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// SeatingChart renders the seating chart of one room at one start time (portrait): the
+// room as a monospace grid seen from the front (row 1 first, one letter per exam), the
+// legend and the seat list by position. modules maps an ancode to its label.
+func SeatingChart(semesterFull string, plan *model.SeatingPlan, modules map[int]string) pdf.Maroto {
+	m := pdf.NewMaroto(consts.Portrait, consts.A4)
+	m.SetPageMargins(10, 15, 10)
+	footer(m)
+	gray := color.Color{Red: 211, Green: 211, Blue: 211}
+
+	centeredRow(m, 10, 3, consts.Bold,
+		fmt.Sprintf("Sitzplan %s, %s Uhr — %s", plan.Room, plan.Starttime.Local().Format("02.01.06, 15:04"), semesterFull))
+	if plan.DefaultLayout {
+		centeredRow(m, 8, 1, consts.Italic, "Für den Raum ist kein Sitzplan-Layout hinterlegt, Standard-Layout (Reihen à 10 Plätze).")
+	}
+
+	letters := seatingLetters(plan.Ancodes)
+	sectionRow(m, "Raum (vorne = Tafel)")
+	size := 9.0
+	if plan.SeatsPerRow > 24 {
+		size = 6
+	}
+	for _, line := range SeatingGridLines(plan, letters) {
+		m.Row(size*0.5, func() {
+			m.Col(12, func() {
+				m.Text(line, props.Text{Family: consts.Courier, Size: size, Align: consts.Left})
+			})
+		})
+	}
+	legend := make([]string, 0, len(plan.Ancodes))
+	for _, ancode := range plan.Ancodes {
+		legend = append(legend, fmt.Sprintf("%s = %s", letters[ancode], modules[ancode]))
+	}
+	centeredRow(m, 8, 2, consts.Italic,
+		strings.Join(legend, "; ")+"; * = NTA, r = reservierter Platz, x = gesperrt, . = frei, | = Gang")
+	if plan.MixingConflicts > 0 {
+		centeredRow(m, 8, 1, consts.Italic,
+			fmt.Sprintf("%d Nachbarschaften (links/vorne) derselben Prüfung ließen sich nicht vermeiden.", plan.MixingConflicts))
+	}
+
+	sectionRow(m, "Sitzplätze")
+	m.TableList([]string{"Reihe", "Platz", "Prüfung", "Name", "Matrikelnr.", ""}, seatingListRows(plan, letters), props.TableList{
+		HeaderProp:           props.TableListContent{Size: 9, GridSizes: []uint{1, 1, 1, 5, 2, 2}},
+		ContentProp:          props.TableListContent{Size: 8, GridSizes: []uint{1, 1, 1, 5, 2, 2}},
+		Align:                consts.Left,
+		AlternatedBackground: &gray,
+		HeaderContentSpace:   1,
+		Line:                 false,
+	})
+	return m
+}
+
+// seatingLetters assigns A, B, C, … to the (sorted) ancodes of a room.
+func seatingLetters(ancodes []int) map[int]string {
+	sorted := append([]int(nil), ancodes...)
+	sort.Ints(sorted)
+	letters := make(map[int]string, len(sorted))
+	for i, a := range sorted {
+		letters[a] = string(rune('A' + i%26))
+	}
+	return letters
+}
+
+// SeatingGridLines renders the room grid, one line per row: three characters per seat
+// (exam letter, "*" for NTA), " x " blocked, " r " reserved and free, " . " free, and
+// " | " after a seat an aisle follows.
+func SeatingGridLines(plan *model.SeatingPlan, letters map[int]string) []string {
+	type pos struct{ row, seat int }
+	cells := make(map[pos]string, len(plan.Seats))
+	for _, s := range plan.Seats {
+		mark := " "
+		if s.Nta {
+			mark = "*"
+		}
+		cells[pos{s.Row, s.Seat}] = " " + letters[s.Ancode] + mark
+	}
+	for _, b := range plan.Blocked {
+		cells[pos{b.Row, b.Seat}] = " x "
+	}
+	for _, r := range plan.Reserved {
+		if _, ok := cells[pos{r.Row, r.Seat}]; !ok {
+			cells[pos{r.Row, r.Seat}] = " r "
+		}
+	}
+	aisle := make(map[int]bool, len(plan.Aisles))
+	for _, a := range plan.Aisles {
+		aisle[a] = true
+	}
+
+	lines := make([]string, 0, plan.Rows+1)
+	var header strings.Builder
+	header.WriteString("     ")
+	for s := 1; s <= plan.SeatsPerRow; s++ {
+		fmt.Fprintf(&header, "%3d", s)
+		if aisle[s] {
+			header.WriteString("   ")
+		}
+	}
+	lines = append(lines, header.String())
+	for r := 1; r <= plan.Rows; r++ {
+		var line strings.Builder
+		fmt.Fprintf(&line, "R%3d ", r)
+		for s := 1; s <= plan.SeatsPerRow; s++ {
+			cell, ok := cells[pos{r, s}]
+			if !ok {
+				cell = " . "
+			}
+			line.WriteString(cell)
+			if aisle[s] {
+				line.WriteString(" | ")
+			}
+		}
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+func seatingListRows(plan *model.SeatingPlan, letters map[int]string) [][]string {
+	rows := make([][]string, 0, len(plan.Seats)+len(plan.Unseated))
+	for _, s := range plan.Seats {
+		note := ""
+		switch {
+		case s.Nta && s.Reserved:
+			note = "NTA, reserviert"
+		case s.Nta:
+			note = "NTA"
+		}
+		rows = append(rows, []string{fmt.Sprint(s.Row), fmt.Sprint(s.Seat),
+			fmt.Sprintf("%s (%d)", letters[s.Ancode], s.Ancode), s.Name, s.Mtknr, note})
+	}
+	for _, s := range plan.Unseated {
+		rows = append(rows, []string{"–", "–", fmt.Sprintf("%s (%d)", letters[s.Ancode], s.Ancode), s.Name, s.Mtknr, "kein Platz frei"})
+	}
+	return rows
+}
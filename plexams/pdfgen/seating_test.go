@@ -0,0 +1,41 @@
+package pdfgen
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+func TestSeatingChartRenders(t *testing.T) {
+	plan := &model.SeatingPlan{
+		Room: "R1.046", Starttime: time.Date(2026, 7, 11, 8, 30, 0, 0, time.Local),
+		Ancodes: []int{7, 9}, Rows: 2, SeatsPerRow: 4, Aisles: []int{2},
+		Blocked:  []*model.SeatPosition{{Row: 2, Seat: 4}},
+		Reserved: []*model.SeatPosition{{Row: 1, Seat: 1}, {Row: 2, Seat: 1}},
+		Seats: []*model.SeatAssignment{
+			{Row: 1, Seat: 1, Ancode: 9, Mtknr: "1", Name: "Abel", Nta: true, Reserved: true},
+			{Row: 1, Seat: 2, Ancode: 7, Mtknr: "2", Name: "Berg"},
+			{Row: 2, Seat: 2, Ancode: 9, Mtknr: "3", Name: "Cramer"},
+		},
+		Unseated: []*model.SeatAssignment{{Ancode: 7, Mtknr: "4", Name: "Dorn"}},
+	}
+	buf, err := SeatingChart("Sommersemester 2026", plan, map[int]string{7: "7. Algebra (Braun)", 9: "9. Physik (Zorn)"}).Output()
+	if err != nil {
+		t.Fatalf("Output() error: %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Fatal("empty PDF")
+	}
+
+	got := SeatingGridLines(plan, seatingLetters(plan.Ancodes))
+	want := []string{
+		"       1  2     3  4",
+		"R  1  B* A  |  .  . ",
+		"R  2  r  B  |  .  x ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SeatingGridLines =\n%q\nwant\n%q", got, want)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	set "github.com/deckarep/golang-set/v2"
 	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/campus"
 	"github.com/obcode/plexams.go/plexams/conflictcalc"
 	"github.com/rs/zerolog/log"
 )
@@ -153,7 +154,11 @@ func (p *Plexams) AllowedSlots(ctx context.Context, ancode int) ([]*model.Slot,
 	if err != nil {
 		return nil, err
 	}
-	return p.allowedSlotsFor(exam, placed, p.examGapMinutes()), nil
+	sites, err := p.campusModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return p.allowedSlotsFor(exam, placed, p.examGapMinutes(), sites), nil
 }
 
 // allowedSlotsFor computes the grid slots into which exam may be placed without a HARD
@@ -162,7 +167,7 @@ func (p *Plexams) AllowedSlots(ctx context.Context, ancode int) ([]*model.Slot,
 // the exam's time window overlap a conflicting exam's window (location-aware travel
 // buffer) — the time-based generalisation of the former "same slot" exclusion. It does no
 // I/O, so a caller placing many exams (the schedule generator) can build `placed` once.
-func (p *Plexams) allowedSlotsFor(exam *model.AssembledExam, placed map[int]placedExamInfo, examGap int) []*model.Slot {
+func (p *Plexams) allowedSlotsFor(exam *model.AssembledExam, placed map[int]placedExamInfo, examGap int, sites *campus.Model) []*model.Slot {
 	allSlots := p.semesterConfig.Slots
 
 	if exam.Constraints != nil && exam.Constraints.FixedTime != nil {
@@ -186,7 +191,7 @@ func (p *Plexams) allowedSlotsFor(exam *model.AssembledExam, placed map[int]plac
 		}
 	}
 
-	excluded := p.overlapSlots(exam, placed, examGap)
+	excluded := p.overlapSlots(exam, placed, examGap, sites)
 	for _, slot := range p.semesterConfig.ForbiddenSlots {
 		excluded.Add(*slot)
 	}
@@ -225,7 +230,11 @@ func (p *Plexams) AwkwardSlots(ctx context.Context, ancode int) ([]*model.Slot,
 		log.Error().Err(err).Int("ancode", exam.Ancode).Msg("cannot get placed exam infos")
 		return nil, err
 	}
-	slotsWithConflicts := p.overlapSlots(exam, placed, p.examGapMinutes())
+	sites, err := p.campusModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slotsWithConflicts := p.overlapSlots(exam, placed, p.examGapMinutes(), sites)
 
 	awkwardSlots := make([]*model.Slot, 0)
 	for _, slot := range slotsWithConflicts.ToSlice() {
@@ -261,7 +270,7 @@ func (p *Plexams) AwkwardSlots(ctx context.Context, ancode int) ([]*model.Slot,
 // count: the caller passes every placed exam (manual placement / GUI) or only the
 // time-fixed ones (the generator, which lets its own hard separations govern the movable
 // exams that can still move).
-func (p *Plexams) overlapSlots(exam *model.AssembledExam, placed map[int]placedExamInfo, examGap int) set.Set[model.Slot] {
+func (p *Plexams) overlapSlots(exam *model.AssembledExam, placed map[int]placedExamInfo, examGap int, sites *campus.Model) set.Set[model.Slot] {
 	slotSet := set.NewSet[model.Slot]()
 	if exam == nil {
 		return slotSet
@@ -273,7 +282,7 @@ func (p *Plexams) overlapSlots(exam *model.AssembledExam, placed map[int]placedE
 		if !ok {
 			continue // conflicting exam not placed at an absolute time → no window to avoid
 		}
-		gap := effectiveGapMinutes(examGap, p.crossCampusGapMinutes(), examLoc, c.location)
+		gap := effectiveGapMinutes(examGap, p.travelMinutes(sites, examLoc, c.location), examLoc, c.location)
 		cEnd := c.start.Add(time.Duration(c.duration) * time.Minute)
 		for _, slot := range p.semesterConfig.Slots {
 			examEnd := slot.Starttime.Add(examDur)
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/campus"
 )
 
 func TestEffectiveGapMinutes(t *testing.T) {
@@ -89,7 +90,7 @@ func TestAllowedSlotsForExcludesOffGridOverlap(t *testing.T) {
 		338: {start: time.Date(2026, 7, 6, 11, 0, 0, 0, time.Local), duration: 60, location: "", fixed: true},
 	}
 
-	got := allowedHHMM(p.allowedSlotsFor(exam, placed, p.examGapMinutes()))
+	got := allowedHHMM(p.allowedSlotsFor(exam, placed, p.examGapMinutes(), nil))
 	want := []string{"08:30", "12:30"} // 10:30 (10:30–12:00) overlaps 11:00–12:00 → excluded
 	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
 		t.Errorf("off-grid overlap: allowed = %v, want %v", got, want)
@@ -112,7 +113,7 @@ func TestAllowedSlotsForCrossCampusWiderBuffer(t *testing.T) {
 		900: {start: time.Date(2026, 7, 6, 10, 30, 0, 0, time.Local), duration: 90, location: "Campus Pasing", fixed: true},
 	}
 
-	got := allowedHHMM(p.allowedSlotsFor(exam, placed, p.examGapMinutes()))
+	got := allowedHHMM(p.allowedSlotsFor(exam, placed, p.examGapMinutes(), nil))
 	// 08:30 (ends 10:00, gap 30 < 120), 10:30 (overlap), 12:30 (conflict ends 12:00, gap 30
 	// < 120) all excluded; only 14:30 (gap 150 > 120) survives.
 	want := []string{"14:30"}
@@ -126,7 +127,35 @@ func TestOverlapSlotsSkipsUnplacedConflict(t *testing.T) {
 	slots := slotsOnDay([2]int{8, 30}, [2]int{10, 30})
 	p := newPlexamsWithSlots(slots, 30)
 	exam := &model.AssembledExam{Ancode: 424, MaxDuration: 90, Conflicts: []*model.ZPAConflict{{Ancode: 338}}}
-	if excl := p.overlapSlots(exam, map[int]placedExamInfo{}, 30); excl.Cardinality() != 0 {
+	if excl := p.overlapSlots(exam, map[int]placedExamInfo{}, 30, nil); excl.Cardinality() != 0 {
 		t.Errorf("unplaced conflict must exclude no slot, got %d", excl.Cardinality())
 	}
 }
+
+// With a campus model the travel-time matrix replaces the flat cross-campus buffer: 60 min
+// from the main campus to Pasing lets 13:30 through, which the 120-min default would block.
+func TestAllowedSlotsForCampusTravelMatrix(t *testing.T) {
+	slots := slotsOnDay([2]int{8, 30}, [2]int{10, 30}, [2]int{12, 30}, [2]int{13, 30})
+	p := newPlexamsWithSlots(slots, 30)
+	sites := campus.New(
+		[]campus.Campus{{Name: "Lothstraße", Default: true}, {Name: "Campus Pasing"}}, nil, nil,
+		[]campus.Travel{{From: "Lothstraße", To: "Campus Pasing", Minutes: 60}},
+		p.crossCampusGapMinutes(),
+	)
+
+	exam := &model.AssembledExam{Ancode: 424, MaxDuration: 90, Conflicts: []*model.ZPAConflict{{Ancode: 900}}}
+	placed := map[int]placedExamInfo{
+		900: {start: time.Date(2026, 7, 6, 10, 30, 0, 0, time.Local), duration: 90, location: "Campus Pasing", fixed: true},
+	}
+
+	if got := allowedHHMM(p.allowedSlotsFor(exam, placed, p.examGapMinutes(), sites)); len(got) != 1 || got[0] != "13:30" {
+		t.Errorf("travel matrix: allowed = %v, want [13:30]", got)
+	}
+	if got := allowedHHMM(p.allowedSlotsFor(exam, placed, p.examGapMinutes(), nil)); len(got) != 0 {
+		t.Errorf("default buffer: allowed = %v, want none", got)
+	}
+	// "" is the default campus: no travel between it and Lothstraße
+	if got := p.travelMinutes(sites, "", "lothstraße"); got != 0 {
+		t.Errorf("travelMinutes(default, Lothstraße) = %d, want 0", got)
+	}
+}
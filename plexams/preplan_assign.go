@@ -8,6 +8,8 @@ import (
 
 	"github.com/obcode/plexams.go/graph/model"
 	"github.com/obcode/plexams.go/plexams/preplancalc"
+	"github.com/obcode/plexams.go/plexams/regforecast"
+	"github.com/rs/zerolog/log"
 )
 
 // preplanCapacityFactor is the usable fraction of a slot's booked Anny seats. 1.0 =
@@ -366,12 +368,48 @@ func kindBookingFindings(key time.Time, kind string, needed, available int, room
 // (with keepAssigned, currently-slotted non-fixed exams are kept too). When no Anny
 // rooms are booked anywhere, nothing is assigned.
 func (p *Plexams) GeneratePreplanAssignment(ctx context.Context, keepAssigned bool) (*model.PreplanValidation, error) {
+	inst, err := p.buildPreplanInstance(ctx, keepAssigned)
+	if err != nil {
+		return nil, err
+	}
+	if inst == nil {
+		return skippedPreplanValidation(), nil
+	}
+	assign := solvePreplan(inst.units, inst.slots, inst.fixedUsed, inst.fixedProgs, inst.exahmIntervals)
+	return p.applyPreplanAssignment(ctx, inst, assign)
+}
+
+// preplanInstance is the pre-plan assignment problem built from the current data: the
+// candidate slots with their pinned occupancy and the units still to distribute, plus
+// what applyPreplanAssignment needs to persist and validate the result.
+type preplanInstance struct {
+	preExams             []*model.PreplanExam
+	regularSlots         []*model.Slot
+	exahmRooms, sebRooms []preplancalc.RoomCapacity
+	rBauSebThreshold     int
+	booked               map[time.Time]*slotBooking
+	exahmIntervals       []bookedRoomInterval
+	blockDur             time.Duration
+
+	slots      []*preplanSlot
+	fixedUsed  []int
+	fixedProgs []map[string]bool
+	units      []*preplanUnit
+
+	// per pre-exam: the slot of a pinned exam and whether it stays fixed
+	finalSlot  []*preplanSlot
+	finalFixed []bool
+}
+
+// buildPreplanInstance builds the pre-plan problem (see GeneratePreplanAssignment). It
+// returns nil when there are no pre-exams.
+func (p *Plexams) buildPreplanInstance(ctx context.Context, keepAssigned bool) (*preplanInstance, error) {
 	preExams, err := p.dbClient.PreplanExams(ctx)
 	if err != nil {
 		return nil, err
 	}
 	if len(preExams) == 0 {
-		return skippedPreplanValidation(), nil
+		return nil, nil
 	}
 	// candidate slots = ALL regular exam slots (not only the MUC.DAI slots): the
 	// pre-exams go wherever we have booked Anny rooms, and those bookings sit on the
@@ -499,7 +537,6 @@ func (p *Plexams) GeneratePreplanAssignment(ctx context.Context, keepAssigned bo
 	}
 
 	solveUnits := make([]*preplanUnit, 0, len(groupOrder))
-	solveMembers := make([][]int, 0, len(groupOrder)) // members per solve unit, by solve index
 
 	for _, r := range groupOrder {
 		members := groupMembers[r]
@@ -659,13 +696,12 @@ func (p *Plexams) GeneratePreplanAssignment(ctx context.Context, keepAssigned bo
 			allowedSlots: allowedSlots, rBauOverflow: rBauOverflow,
 			dur: uDur, occPre: uOccPre, occPost: uOccPost,
 		})
-		solveMembers = append(solveMembers, members)
 	}
 
 	// explicit "nicht gleichzeitig" pairs (PreplanExam.NotSameSlot) → strong conflicts
 	unitOfExam := make(map[int]int, len(preExams))
-	for ui, members := range solveMembers {
-		for _, mi := range members {
+	for ui, u := range solveUnits {
+		for _, mi := range u.members {
 			unitOfExam[preExams[mi].ID] = ui
 		}
 	}
@@ -713,13 +749,51 @@ func (p *Plexams) GeneratePreplanAssignment(ctx context.Context, keepAssigned bo
 		}
 	}
 
-	assign := solvePreplan(solveUnits, slots, fixedUsed, fixedProgs, exahmIntervals)
-	for u, members := range solveMembers {
+	// pairs with common students in earlier semesters (registration forecast) → spread
+	// like a shared study program, unless explicitly marked as shareable
+	targets := make([]regforecast.Target, 0, len(preExams))
+	for _, pe := range preExams {
+		targets = append(targets, regforecast.Target{ID: pe.ID, Module: pe.Module, MainExamer: pe.ExamerName})
+	}
+	if _, conflicts, err := p.forecastFor(ctx, targets); err != nil {
+		log.Warn().Err(err).Msg("cannot forecast conflicts of the pre-exams")
+	} else {
+		for _, c := range conflicts {
+			ui, okA := unitOfExam[c.A]
+			uj, okB := unitOfExam[c.B]
+			if !okA || !okB || ui == uj || solveUnits[ui].compatible[uj] || solveUnits[ui].conflicts[uj] >= preplanProgramConflictWeight {
+				continue
+			}
+			if solveUnits[ui].conflicts == nil {
+				solveUnits[ui].conflicts = map[int]int{}
+			}
+			if solveUnits[uj].conflicts == nil {
+				solveUnits[uj].conflicts = map[int]int{}
+			}
+			solveUnits[ui].conflicts[uj] = preplanProgramConflictWeight
+			solveUnits[uj].conflicts[ui] = preplanProgramConflictWeight
+		}
+	}
+
+	return &preplanInstance{
+		preExams: preExams, regularSlots: regularSlots, exahmRooms: exahmRooms, sebRooms: sebRooms,
+		rBauSebThreshold: rBauSebThreshold, booked: booked, exahmIntervals: exahmIntervals, blockDur: blockDur,
+		slots: slots, fixedUsed: fixedUsed, fixedProgs: fixedProgs, units: solveUnits,
+		finalSlot: finalSlot, finalFixed: finalFixed,
+	}, nil
+}
+
+// applyPreplanAssignment persists an assignment (per unit the slot index, -1 = none) and
+// validates the result.
+func (p *Plexams) applyPreplanAssignment(ctx context.Context, inst *preplanInstance, assign []int) (*model.PreplanValidation, error) {
+	preExams, slots, regularSlots, booked := inst.preExams, inst.slots, inst.regularSlots, inst.booked
+	finalSlot, finalFixed := inst.finalSlot, inst.finalFixed
+	for u, unit := range inst.units {
 		var ps *preplanSlot
 		if assign[u] >= 0 {
 			ps = slots[assign[u]]
 		}
-		for _, i := range members {
+		for _, i := range unit.members {
 			finalSlot[i] = ps
 			finalFixed[i] = false
 		}
@@ -751,7 +825,7 @@ func (p *Plexams) GeneratePreplanAssignment(ctx context.Context, keepAssigned bo
 	}
 	// validatePreplan reports the small-SEB R-building notes and the genuinely-unplaced
 	// must-place exams (threshold-aware), so no extra messages are added here.
-	result := validatePreplan(preExams, exahmRooms, sebRooms, bookedAfter, rBauSebThreshold, exahmIntervals, blockDur)
+	result := validatePreplan(preExams, inst.exahmRooms, inst.sebRooms, bookedAfter, inst.rBauSebThreshold, inst.exahmIntervals, inst.blockDur)
 	// pre-planning goal: surface which booked Anny slots are now unused and can be cancelled.
 	usedStarts := make(map[time.Time]bool)
 	for _, pe := range preExams {
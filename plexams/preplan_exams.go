@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/regforecast"
 	"github.com/rs/zerolog/log"
 )
 
@@ -266,13 +267,24 @@ func (p *Plexams) preplanExamFromInput(ctx context.Context, input *model.Preplan
 		notes = strings.TrimSpace(*input.Notes)
 	}
 
+	// no estimate given: take the registration forecast from the earlier semesters
+	expectedStudents := input.ExpectedStudents
+	if expectedStudents == 0 {
+		target := regforecast.Target{Module: input.Module, MainExamer: examerName}
+		if expected, _, err := p.forecastFor(ctx, []regforecast.Target{target}); err != nil {
+			log.Warn().Err(err).Str("module", input.Module).Msg("cannot forecast the registrations of the pre-exam")
+		} else {
+			expectedStudents = expected[target.ID]
+		}
+	}
+
 	return &model.PreplanExam{
 		ExamKind:         input.ExamKind,
 		ExamerID:         input.ExamerID,
 		ExamerName:       examerName,
 		Module:           strings.TrimSpace(input.Module),
 		Programs:         programs,
-		ExpectedStudents: input.ExpectedStudents,
+		ExpectedStudents: expectedStudents,
 		Duration:         input.Duration,
 		Notes:            notes,
 	}, nil
@@ -0,0 +1,220 @@
+package plexams
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/obcode/plexams.go/plexams/milp"
+)
+
+// preplanMILP is the pre-plan assignment (see solvePreplan) as a mixed-integer linear
+// program for an external exact solver. x_<unit>_<slot> = 1 puts the unit into the slot.
+// Hard: one slot per unit, the allowed slots, the slot capacity and the cross-slot booked
+// seats over time (cumulativeOverloads, per elementary time interval). Soft: the drop
+// cost, one preplanSlotOpenCost per used slot and the proximity of conflicting units (a
+// pair variable per same-day slot pair) and of pinned exams sharing a program.
+type preplanMILP struct {
+	model *milp.Model
+	inst  *preplanInstance
+	x     map[[2]int]int // (unit, slot) → variable
+}
+
+func buildPreplanMILP(inst *preplanInstance) *preplanMILP {
+	units, slots := inst.units, inst.slots
+	addProgramConflicts(units)
+	m := &preplanMILP{model: milp.New("plexams pre-plan"), inst: inst, x: make(map[[2]int]int)}
+	m.model.Comments = []string{"x_<unit>_<slot> = 1: the unit (exams sharing a slot) is placed in the booked slot."}
+
+	bySlot := make([][]milp.Term, len(slots))
+	for u, unit := range units {
+		var terms []milp.Term
+		for s, slot := range slots {
+			if unit.allowedSlots != nil && !unit.allowedSlots[s] {
+				continue
+			}
+			if inst.fixedUsed[s]+unit.seats > slot.capacity {
+				continue
+			}
+			v := m.model.Binary(fmt.Sprintf("x_%d_%d", u, s))
+			m.x[[2]int{u, s}] = v
+			terms = append(terms, milp.T(v, 1))
+			bySlot[s] = append(bySlot[s], milp.T(v, float64(unit.seats)))
+			cost := -float64(unit.dropCost)
+			for f := range slots {
+				if len(inst.fixedProgs[f]) > 0 && shareProgramSet(unit.programs, inst.fixedProgs[f]) {
+					cost += float64(proximityPenalty(slot, slots[f], preplanProgramConflictWeight))
+				}
+			}
+			m.model.Minimize(v, cost)
+		}
+		m.model.Add(fmt.Sprintf("unit_%d", u), milp.LE, 1, terms...)
+		m.model.Offset += float64(unit.dropCost)
+	}
+
+	for s, slot := range slots {
+		m.model.Add(fmt.Sprintf("cap_%d", s), milp.LE, float64(slot.capacity-inst.fixedUsed[s]), bySlot[s]...)
+		if inst.fixedUsed[s] > 0 {
+			m.model.Offset += preplanSlotOpenCost // already open
+			continue
+		}
+		if len(bySlot[s]) == 0 {
+			continue
+		}
+		open := m.model.Binary(fmt.Sprintf("open_%d", s))
+		for _, t := range bySlot[s] {
+			m.model.Add(fmt.Sprintf("opendef_%d_%d", s, t.Var), milp.LE, 0, milp.T(t.Var, 1), milp.T(open, -1))
+		}
+		m.model.Minimize(open, preplanSlotOpenCost)
+	}
+
+	m.addProximity()
+	m.addCumulative()
+	return m
+}
+
+// addProximity charges proximityPenalty for every conflicting pair placed on the same day
+// (w ≥ x_u_s + x_v_t − 1).
+func (m *preplanMILP) addProximity() {
+	units, slots := m.inst.units, m.inst.slots
+	for u := range units {
+		partners := make([]int, 0, len(units[u].conflicts))
+		for v := range units[u].conflicts {
+			if v > u {
+				partners = append(partners, v)
+			}
+		}
+		sort.Ints(partners)
+		for _, v := range partners {
+			for s := range slots {
+				xu, ok := m.x[[2]int{u, s}]
+				if !ok {
+					continue
+				}
+				for t := range slots {
+					xv, ok := m.x[[2]int{v, t}]
+					if !ok || !sameDay(slots[s].start, slots[t].start) {
+						continue
+					}
+					w := m.model.NonNegative(fmt.Sprintf("near_%d_%d_%d_%d", u, v, s, t))
+					m.model.Add(fmt.Sprintf("neardef_%d_%d_%d_%d", u, v, s, t), milp.GE, -1,
+						milp.T(w, 1), milp.T(xu, -1), milp.T(xv, -1))
+					m.model.Minimize(w, float64(proximityPenalty(slots[s], slots[t], units[u].conflicts[v])))
+				}
+			}
+		}
+	}
+}
+
+// addCumulative bounds the simultaneously occupied EXaHM and total seats by the booked
+// seats in every elementary interval between two window boundaries, like
+// cumulativeOverloads. Pinned exams are not counted, as in solvePreplan.
+func (m *preplanMILP) addCumulative() {
+	intervals := m.inst.exahmIntervals
+	if len(intervals) == 0 {
+		return
+	}
+	type window struct {
+		v  int
+		ce cumExam
+	}
+	keys := make([][2]int, 0, len(m.x))
+	for k := range m.x {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(a, b int) bool { return m.x[keys[a]] < m.x[keys[b]] })
+	windows := make([]window, 0, len(keys))
+	seen := make(map[int64]time.Time)
+	for _, k := range keys {
+		ce := m.inst.units[k[0]].cumExamAt(k[0], m.inst.slots[k[1]].start)
+		windows = append(windows, window{m.x[k], ce})
+		seen[ce.from.UnixNano()] = ce.from
+		seen[ce.to.UnixNano()] = ce.to
+	}
+	for _, iv := range intervals {
+		seen[iv.from.UnixNano()] = iv.from
+		seen[iv.until.UnixNano()] = iv.until
+	}
+	times := make([]time.Time, 0, len(seen))
+	for _, t := range seen {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	for i := 0; i+1 < len(times); i++ {
+		mid := times[i].Add(times[i+1].Sub(times[i]) / 2)
+		var exahm, total []milp.Term
+		for _, w := range windows {
+			if w.ce.from.After(mid) || !w.ce.to.After(mid) {
+				continue
+			}
+			total = append(total, milp.T(w.v, float64(w.ce.seats)))
+			if w.ce.exahm {
+				exahm = append(exahm, milp.T(w.v, float64(w.ce.seats)))
+			}
+		}
+		if len(total) == 0 {
+			continue
+		}
+		exahmSeats, totalSeats := bookedSeatsAt(intervals, mid)
+		m.model.Add(fmt.Sprintf("exahm_%d", i), milp.LE, float64(exahmSeats), exahm...)
+		m.model.Add(fmt.Sprintf("seats_%d", i), milp.LE, float64(totalSeats), total...)
+	}
+}
+
+// assignment maps a solution back to the per-unit slot index (-1 = none) and checks the
+// hard constraints the annealer enforces; the returned violations are empty when the
+// assignment may be applied.
+func (m *preplanMILP) assignment(sol milp.Solution) ([]int, []string) {
+	units, slots := m.inst.units, m.inst.slots
+	assign := make([]int, len(units))
+	for u := range assign {
+		assign[u] = -1
+	}
+	var violations []string
+	keys := make([][2]int, 0, len(m.x))
+	for k := range m.x {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(a, b int) bool { return m.x[keys[a]] < m.x[keys[b]] })
+	for _, k := range keys {
+		if !sol.On(m.x[k]) {
+			continue
+		}
+		u, s := k[0], k[1]
+		if assign[u] >= 0 {
+			violations = append(violations, fmt.Sprintf("unit %d is placed in %s and %s", units[u].minID, slotLabelDE(slots[assign[u]].start), slotLabelDE(slots[s].start)))
+			continue
+		}
+		assign[u] = s
+	}
+
+	used := append([]int(nil), m.inst.fixedUsed...)
+	var exams []cumExam
+	for u, s := range assign {
+		if s < 0 {
+			continue
+		}
+		if units[u].allowedSlots != nil && !units[u].allowedSlots[s] {
+			violations = append(violations, fmt.Sprintf("unit %d is not allowed in %s", units[u].minID, slotLabelDE(slots[s].start)))
+		}
+		used[s] += units[u].seats
+		exams = append(exams, units[u].cumExamAt(u, slots[s].start))
+	}
+	for s, slot := range slots {
+		if used[s] > slot.capacity {
+			violations = append(violations, fmt.Sprintf("%s: %d seats, capacity %d", slotLabelDE(slot.start), used[s], slot.capacity))
+		}
+	}
+	for _, o := range cumulativeOverloads(exams, m.inst.exahmIntervals, false) {
+		violations = append(violations, fmt.Sprintf("%s–%s: %d seats occupied, %d booked",
+			o.from.Format("02.01. 15:04"), o.to.Format("15:04"), o.demand, o.seats))
+	}
+	return assign, violations
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
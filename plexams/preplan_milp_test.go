@@ -0,0 +1,37 @@
+package plexams
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obcode/plexams.go/plexams/milp"
+)
+
+func TestPreplanMILPRoundtrip(t *testing.T) {
+	units := []*preplanUnit{unit(1, 60, false, "IF"), unit(2, 50, false, "IF"), unit(3, 30, true, "DC")}
+	slots := []*preplanSlot{{start: at(1, 1), capacity: 80}, {start: at(2, 1), capacity: 80}}
+	used, progs := emptyFixed(len(slots))
+	inst := &preplanInstance{units: units, slots: slots, fixedUsed: used, fixedProgs: progs}
+	m := buildPreplanMILP(inst)
+
+	sol, err := milp.ReadSolution(strings.NewReader("x_0_0 1\nx_1_1 1\nx_2_1 1\n"), m.model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assign, violations := m.assignment(sol)
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations, got %v", violations)
+	}
+	if assign[0] != 0 || assign[1] != 1 || assign[2] != 1 {
+		t.Fatalf("unexpected assignment %v", assign)
+	}
+
+	// 60 + 30 seats do not fit a slot of 80
+	over, err := milp.ReadSolution(strings.NewReader("x_0_0 1\nx_2_0 1\n"), m.model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, violations := m.assignment(over); len(violations) == 0 {
+		t.Error("an over-capacity solution must be reported")
+	}
+}
@@ -98,6 +98,14 @@ type preplanUnit struct {
 	rBauOverflow int
 }
 
+// cumExamAt is unit id's occupancy when placed at start, for cumulativeOverloads.
+func (u *preplanUnit) cumExamAt(id int, start time.Time) cumExam {
+	return cumExam{
+		id: id, seats: u.seats, exahm: u.hasExahm,
+		from: start.Add(-u.occPre), to: start.Add(u.dur + u.occPost),
+	}
+}
+
 // proximityPenalty is the soft cost of placing two conflicting units in slots a and b:
 // p0 when at the same start time, scaled down with the time gap on the same calendar
 // day, and 0 once they are on different days (different days are always "far enough").
@@ -138,13 +146,7 @@ func solvePreplan(units []*preplanUnit, slots []*preplanSlot, fixedUsed []int, f
 	// booking at every instant. Rooms may be shared (aggregate seats), but a long exam whose
 	// setup/teardown reaches into a neighbouring slot competes for seats there — this is the
 	// hard cross-slot capacity constraint (see cumulativeOverloads).
-	cumExamOf := func(u, s int) cumExam {
-		st := slots[s].start
-		return cumExam{
-			id: u, seats: units[u].seats, exahm: units[u].hasExahm,
-			from: st.Add(-units[u].occPre), to: st.Add(units[u].dur + units[u].occPost),
-		}
-	}
+	cumExamOf := func(u, s int) cumExam { return units[u].cumExamAt(u, slots[s].start) }
 	cumFeasible := func(a []int, extraU, extraS int) bool {
 		if len(intervals) == 0 {
 			return true // no bookings loaded → fall back to the aggregate seat bound only
@@ -161,24 +163,7 @@ func solvePreplan(units []*preplanUnit, slots []*preplanSlot, fixedUsed []int, f
 		return len(cumulativeOverloads(exams, intervals, true)) == 0
 	}
 
-	// units sharing a study program conflict softly; merge with any explicit conflicts
-	// already set by the caller (those use the stronger explicit weight and win).
-	for i := range units {
-		if units[i].conflicts == nil {
-			units[i].conflicts = map[int]int{}
-		}
-	}
-	for i := 0; i < n; i++ {
-		for j := i + 1; j < n; j++ {
-			if units[i].compatible[j] {
-				continue // explicitly allowed to share a slot / be adjacent
-			}
-			if shareProgram(units[i], units[j]) && units[i].conflicts[j] < preplanProgramConflictWeight {
-				units[i].conflicts[j] = preplanProgramConflictWeight
-				units[j].conflicts[i] = preplanProgramConflictWeight
-			}
-		}
-	}
+	addProgramConflicts(units)
 
 	// seats used + non-fixed occupants per slot for an assignment
 	occupancy := func(a []int) (used []int, occ [][]int) {
@@ -288,6 +273,28 @@ func solvePreplan(units []*preplanUnit, slots []*preplanSlot, fixedUsed []int, f
 	return model.assign
 }
 
+// addProgramConflicts lets units sharing a study program conflict softly, merged with any
+// explicit conflicts already set by the caller (those use the stronger explicit weight and
+// win).
+func addProgramConflicts(units []*preplanUnit) {
+	for i := range units {
+		if units[i].conflicts == nil {
+			units[i].conflicts = map[int]int{}
+		}
+	}
+	for i := 0; i < len(units); i++ {
+		for j := i + 1; j < len(units); j++ {
+			if units[i].compatible[j] {
+				continue // explicitly allowed to share a slot / be adjacent
+			}
+			if shareProgram(units[i], units[j]) && units[i].conflicts[j] < preplanProgramConflictWeight {
+				units[i].conflicts[j] = preplanProgramConflictWeight
+				units[j].conflicts[i] = preplanProgramConflictWeight
+			}
+		}
+	}
+}
+
 // preplanModel adapts the pre-plan assignment to the generic optimize.Model interface:
 // the state is the per-unit slot assignment, Cost is the pre-plan soft/drop cost, and a
 // Propose is one proposeMove (relocate-with-ejection or swap), undone by restoring the
@@ -0,0 +1,275 @@
+// Package regforecast predicts the registrations of an exam before the Primuss data of
+// the semester is there, from the registrations of earlier semesters: the same module
+// (with its own growth trend), else the same main examer, scaled by the overall trend.
+// It also estimates how many students will take two exams together (pairwise
+// conflicts), from the students registered for both modules in an earlier semester. It
+// is I/O-free; loading the earlier workspaces stays in the plexams package.
+package regforecast
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Basis is what a forecast is based on.
+type Basis string
+
+const (
+	BasisModule Basis = "MODULE"
+	BasisExamer Basis = "EXAMER"
+	BasisNone   Basis = "NONE" // never seen before: no forecast
+)
+
+// minGrowth and maxGrowth bound the trend per semester, so a single outlier (a module
+// offered once as a repeat exam only) does not explode or wipe out a forecast.
+const (
+	minGrowth = 0.5
+	maxGrowth = 2.0
+)
+
+// Exam is one exam of an earlier semester with its registrations.
+type Exam struct {
+	Ancode     int
+	Module     string
+	MainExamer string
+	Registered int
+}
+
+// Semester is the data of one earlier semester workspace.
+type Semester struct {
+	Workspace string
+	Exams     []Exam
+	// Students holds, per student, the ancodes the student was registered for.
+	Students [][]int
+}
+
+// Point is the registrations of a module (or the per-exam average of an examer) in one
+// earlier semester.
+type Point struct {
+	Workspace  string
+	Registered int
+}
+
+type semester struct {
+	workspace string
+	modules   map[string]int
+	examers   map[string][2]int // registrations, exams
+	shared    map[[2]string]int // students registered for both modules
+	total     int
+}
+
+// History holds the earlier semesters, oldest first.
+type History struct {
+	semesters []*semester
+	growth    float64
+}
+
+// NewHistory aggregates the semesters, which must be given oldest first.
+func NewHistory(semesters []Semester) *History {
+	h := &History{semesters: make([]*semester, 0, len(semesters))}
+	totals := make([]Point, 0, len(semesters))
+	for _, in := range semesters {
+		s := &semester{
+			workspace: in.Workspace,
+			modules:   make(map[string]int),
+			examers:   make(map[string][2]int),
+			shared:    make(map[[2]string]int),
+		}
+		moduleOf := make(map[int]string, len(in.Exams))
+		for _, e := range in.Exams {
+			module := normalize(e.Module)
+			if module == "" {
+				continue
+			}
+			moduleOf[e.Ancode] = module
+			s.modules[module] += e.Registered
+			if examer := normalize(e.MainExamer); examer != "" {
+				c := s.examers[examer]
+				s.examers[examer] = [2]int{c[0] + e.Registered, c[1] + 1}
+			}
+			s.total += e.Registered
+		}
+		for _, ancodes := range in.Students {
+			modules := make([]string, 0, len(ancodes))
+			seen := make(map[string]bool, len(ancodes))
+			for _, a := range ancodes {
+				if m, ok := moduleOf[a]; ok && !seen[m] {
+					seen[m] = true
+					modules = append(modules, m)
+				}
+			}
+			sort.Strings(modules)
+			for i := 0; i < len(modules); i++ {
+				for j := i + 1; j < len(modules); j++ {
+					s.shared[[2]string{modules[i], modules[j]}]++
+				}
+			}
+		}
+		h.semesters = append(h.semesters, s)
+		if s.total > 0 {
+			totals = append(totals, Point{Workspace: s.workspace, Registered: s.total})
+		}
+	}
+	h.growth = growth(totals, 1)
+	return h
+}
+
+// normalize makes module and examer names comparable across semesters.
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// growth is the average factor per step between the first and the last point (geometric
+// mean), within minGrowth..maxGrowth; fallback if there are fewer than two points.
+func growth(points []Point, fallback float64) float64 {
+	if len(points) < 2 || points[0].Registered == 0 {
+		return fallback
+	}
+	first, last := float64(points[0].Registered), float64(points[len(points)-1].Registered)
+	g := math.Pow(last/first, 1/float64(len(points)-1))
+	return math.Round(min(max(g, minGrowth), maxGrowth)*100) / 100
+}
+
+// Growth is the overall registration trend per semester (1 = constant).
+func (h *History) Growth() float64 { return h.growth }
+
+// Workspaces returns the workspaces of the history, oldest first.
+func (h *History) Workspaces() []string {
+	ws := make([]string, 0, len(h.semesters))
+	for _, s := range h.semesters {
+		ws = append(ws, s.workspace)
+	}
+	return ws
+}
+
+// Forecast is the predicted registrations of one exam.
+type Forecast struct {
+	Expected int
+	Basis    Basis
+	// Growth is the trend per semester the last registrations were scaled with.
+	Growth float64
+	// History is the registrations the forecast is based on, oldest first.
+	History []Point
+}
+
+// Forecast predicts the registrations of an exam: the module's last registrations times
+// its own trend (the overall trend if it was offered only once), else the average
+// registrations of the main examer's exams in their last semester times the overall
+// trend.
+func (h *History) Forecast(module, mainExamer string) Forecast {
+	if points := h.modulePoints(normalize(module)); len(points) > 0 {
+		g := growth(points, h.growth)
+		return Forecast{Expected: scale(points[len(points)-1].Registered, g), Basis: BasisModule, Growth: g, History: points}
+	}
+	examer := normalize(mainExamer)
+	points := make([]Point, 0)
+	for _, s := range h.semesters {
+		if c, ok := s.examers[examer]; ok && examer != "" {
+			points = append(points, Point{Workspace: s.workspace, Registered: c[0] / c[1]})
+		}
+	}
+	if len(points) > 0 {
+		return Forecast{Expected: scale(points[len(points)-1].Registered, h.growth), Basis: BasisExamer, Growth: h.growth, History: points}
+	}
+	return Forecast{Basis: BasisNone, Growth: 1, History: points}
+}
+
+func (h *History) modulePoints(module string) []Point {
+	points := make([]Point, 0)
+	if module == "" {
+		return points
+	}
+	for _, s := range h.semesters {
+		if n, ok := s.modules[module]; ok {
+			points = append(points, Point{Workspace: s.workspace, Registered: n})
+		}
+	}
+	return points
+}
+
+func scale(n int, g float64) int {
+	return int(math.Round(float64(n) * g))
+}
+
+// Target is an exam of the semester to forecast, identified by the caller's id (an
+// ancode, or a pre-exam id).
+type Target struct {
+	ID         int
+	Module     string
+	MainExamer string
+}
+
+// Conflict is the estimated number of students taking both exams (A < B).
+type Conflict struct {
+	A, B     int
+	Students int
+}
+
+// Conflicts estimates the pairwise conflicts between the targets: the students
+// registered for both modules in the last semester both were offered, scaled like the
+// smaller of the two forecasts (relative to that semester). Targets of the same module
+// (parallel exams) are not paired; pairs estimated at less than one student are dropped.
+func (h *History) Conflicts(targets []Target) []Conflict {
+	byModule := make(map[string][]int)
+	expected := make(map[string]int)
+	for _, t := range targets {
+		module := normalize(t.Module)
+		if module == "" {
+			continue
+		}
+		if _, ok := expected[module]; !ok {
+			expected[module] = h.Forecast(module, "").Expected
+		}
+		byModule[module] = append(byModule[module], t.ID)
+	}
+	modules := make([]string, 0, len(byModule))
+	for m := range byModule {
+		modules = append(modules, m)
+	}
+	sort.Strings(modules)
+
+	conflicts := make([]Conflict, 0)
+	for i := 0; i < len(modules); i++ {
+		for j := i + 1; j < len(modules); j++ {
+			a, b := modules[i], modules[j]
+			students := h.sharedStudents(a, b, expected[a], expected[b])
+			if students < 1 {
+				continue
+			}
+			for _, ida := range byModule[a] {
+				for _, idb := range byModule[b] {
+					if ida == idb {
+						continue
+					}
+					conflicts = append(conflicts, Conflict{A: min(ida, idb), B: max(ida, idb), Students: students})
+				}
+			}
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].A != conflicts[j].A {
+			return conflicts[i].A < conflicts[j].A
+		}
+		return conflicts[i].B < conflicts[j].B
+	})
+	return conflicts
+}
+
+// sharedStudents scales the students of the last semester with both modules (a < b).
+func (h *History) sharedStudents(a, b string, expectedA, expectedB int) int {
+	for k := len(h.semesters) - 1; k >= 0; k-- {
+		s := h.semesters[k]
+		ra, okA := s.modules[a]
+		rb, okB := s.modules[b]
+		if !okA || !okB {
+			continue
+		}
+		factor := 1.0
+		if ra > 0 && rb > 0 {
+			factor = min(float64(expectedA)/float64(ra), float64(expectedB)/float64(rb))
+		}
+		return scale(s.shared[[2]string{a, b}], factor)
+	}
+	return 0
+}
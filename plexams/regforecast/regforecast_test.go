@@ -0,0 +1,74 @@
+package regforecast
+
+import (
+	"reflect"
+	"testing"
+)
+
+func sampleHistory() *History {
+	return NewHistory([]Semester{
+		{
+			Workspace: "2025-SS",
+			Exams: []Exam{
+				{Ancode: 1, Module: "Datenbanken", MainExamer: "Mustermann", Registered: 100},
+				{Ancode: 2, Module: "Compilerbau", MainExamer: "Muster", Registered: 40},
+			},
+			Students: [][]int{{1, 2}, {1, 2}, {1}},
+		},
+		{
+			Workspace: "2025-WS",
+			Exams: []Exam{
+				{Ancode: 7, Module: "Mathematik I", MainExamer: "Muster", Registered: 60},
+			},
+		},
+		{
+			Workspace: "2026-SS",
+			Exams: []Exam{
+				{Ancode: 11, Module: "Datenbanken ", MainExamer: "Mustermann", Registered: 120},
+				{Ancode: 12, Module: "Compilerbau", MainExamer: "Muster", Registered: 40},
+				{Ancode: 13, Module: "Software Engineering", MainExamer: "Neumann", Registered: 80},
+			},
+			Students: [][]int{{11, 12}, {11, 12}, {11, 12}, {11, 12}, {11, 13}},
+		},
+	})
+}
+
+func TestForecast(t *testing.T) {
+	h := sampleHistory()
+	// total: 140 -> 60 -> 240 = factor 1.31 per semester
+	if g := h.Growth(); g != 1.31 {
+		t.Errorf("growth = %v", g)
+	}
+	tests := []struct {
+		module, examer string
+		expected       int
+		basis          Basis
+	}{
+		{"datenbanken", "", 144, BasisModule},          // 100 -> 120: 1.2 per step
+		{"Compilerbau", "Muster", 40, BasisModule},     // constant
+		{"Software Engineering", "", 105, BasisModule}, // once: overall trend
+		{"Neues Modul", "Muster", 52, BasisExamer},     // 40 per exam in 2026-SS times 1.31
+		{"Neues Modul", "Unbekannt", 0, BasisNone},
+	}
+	for _, tt := range tests {
+		f := h.Forecast(tt.module, tt.examer)
+		if f.Expected != tt.expected || f.Basis != tt.basis {
+			t.Errorf("Forecast(%q, %q) = %+v, want %d %s", tt.module, tt.examer, f, tt.expected, tt.basis)
+		}
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	h := sampleHistory()
+	got := h.Conflicts([]Target{
+		{ID: 324, Module: "Datenbanken"},
+		{ID: 325, Module: "Datenbanken"}, // parallel exam of the same module
+		{ID: 101, Module: "Compilerbau"},
+		{ID: 200, Module: "Mathematik I"},
+	})
+	// 4 shared students in 2026-SS, scaled by min(144/120, 40/40) = 1
+	want := []Conflict{{A: 101, B: 324, Students: 4}, {A: 101, B: 325, Students: 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Conflicts = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,187 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/regforecast"
+)
+
+// Registration forecast (see regforecast): before the Primuss data is imported, the
+// registrations and pairwise conflicts of the exams are predicted from the earlier
+// semester workspaces, so the pre-planning and an early exam plan draft can run.
+
+const defaultForecastSemesters = 4
+
+// registrationHistory collects the assembled exams and student registrations of the
+// previous semesters (up to semesters back; workspaces that do not exist are skipped),
+// oldest first.
+func (p *Plexams) registrationHistory(ctx context.Context, semesters int) (*regforecast.History, error) {
+	history := make([]regforecast.Semester, 0, semesters)
+	for _, db := range p.previousWorkspaces(ctx, semesters) {
+		exams, err := p.dbClient.AssembledExamsForDatabase(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		students, err := p.dbClient.StudentRegsPerStudentPlannedForDatabase(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		s := regforecast.Semester{Workspace: db, Exams: make([]regforecast.Exam, 0, len(exams)), Students: make([][]int, 0, len(students))}
+		for _, e := range exams {
+			if e.ZpaExam == nil {
+				continue
+			}
+			s.Exams = append(s.Exams, regforecast.Exam{Ancode: e.Ancode, Module: e.ZpaExam.Module, MainExamer: e.ZpaExam.MainExamer, Registered: e.StudentRegsCount})
+		}
+		for _, st := range students {
+			s.Students = append(s.Students, st.ZpaAncodes)
+		}
+		history = append(history, s)
+	}
+	// collected newest first
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+	return regforecast.NewHistory(history), nil
+}
+
+// forecastFor predicts the registrations (by target id) and the pairwise conflicts of
+// the targets from the default number of previous semesters.
+func (p *Plexams) forecastFor(ctx context.Context, targets []regforecast.Target) (map[int]int, []regforecast.Conflict, error) {
+	history, err := p.registrationHistory(ctx, defaultForecastSemesters)
+	if err != nil {
+		return nil, nil, err
+	}
+	expected := make(map[int]int, len(targets))
+	for _, t := range targets {
+		expected[t.ID] = history.Forecast(t.Module, t.MainExamer).Expected
+	}
+	return expected, history.Conflicts(targets), nil
+}
+
+// RegistrationForecast forecasts the registrations and conflicts of the exams to plan
+// and, once registrations are imported, compares them with the actual ones.
+func (p *Plexams) RegistrationForecast(ctx context.Context, semesters *int) (*model.RegistrationForecast, error) {
+	n := defaultForecastSemesters
+	if semesters != nil {
+		if *semesters < 1 {
+			return nil, fmt.Errorf("semesters must be at least 1, got %d", *semesters)
+		}
+		n = *semesters
+	}
+	history, err := p.registrationHistory(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	exams, err := p.GetZpaExamsToPlan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assembled, err := p.dbClient.GetAssembledExams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	actual := make(map[int]int, len(assembled))
+	total := 0
+	for _, e := range assembled {
+		actual[e.Ancode] = e.StudentRegsCount
+		total += e.StudentRegsCount
+	}
+
+	result := &model.RegistrationForecast{
+		Workspaces: history.Workspaces(),
+		Growth:     history.Growth(),
+		HasActual:  total > 0,
+		Exams:      make([]*model.RegistrationForecastExam, 0, len(exams)),
+		Conflicts:  make([]*model.ConflictForecast, 0),
+	}
+	if result.HasActual {
+		result.Actual = &total
+	}
+	targets := make([]regforecast.Target, 0, len(exams))
+	moduleOf := make(map[int]string, len(exams))
+	for _, e := range exams {
+		f := history.Forecast(e.Module, e.MainExamer)
+		fe := &model.RegistrationForecastExam{
+			Ancode: e.AnCode, Module: e.Module, MainExamer: e.MainExamer, Expected: f.Expected,
+			Basis: model.RegistrationForecastBasis(f.Basis), Growth: f.Growth,
+			History: make([]*model.RegistrationHistoryPoint, 0, len(f.History)),
+		}
+		for _, pt := range f.History {
+			fe.History = append(fe.History, &model.RegistrationHistoryPoint{Workspace: pt.Workspace, Registered: pt.Registered})
+		}
+		if result.HasActual {
+			a, d := actual[e.AnCode], actual[e.AnCode]-f.Expected
+			fe.Actual, fe.Deviation = &a, &d
+		}
+		result.Exams = append(result.Exams, fe)
+		result.Expected += f.Expected
+		targets = append(targets, regforecast.Target{ID: e.AnCode, Module: e.Module, MainExamer: e.MainExamer})
+		moduleOf[e.AnCode] = e.Module
+	}
+	sort.Slice(result.Exams, func(i, j int) bool { return result.Exams[i].Ancode < result.Exams[j].Ancode })
+
+	var actualPairs map[[2]int]int
+	if result.HasActual {
+		if actualPairs, err = p.actualConflictPairs(ctx, moduleOf); err != nil {
+			return nil, err
+		}
+	}
+	forecast := make(map[[2]int]bool)
+	for _, c := range history.Conflicts(targets) {
+		key := [2]int{c.A, c.B}
+		forecast[key] = true
+		cf := &model.ConflictForecast{Ancode1: c.A, Ancode2: c.B, Module1: moduleOf[c.A], Module2: moduleOf[c.B], Expected: c.Students}
+		if result.HasActual {
+			a := actualPairs[key]
+			cf.Actual = &a
+		}
+		result.Conflicts = append(result.Conflicts, cf)
+	}
+	for key, a := range actualPairs {
+		if forecast[key] {
+			continue
+		}
+		result.Conflicts = append(result.Conflicts, &model.ConflictForecast{
+			Ancode1: key[0], Ancode2: key[1], Module1: moduleOf[key[0]], Module2: moduleOf[key[1]], Actual: &a,
+		})
+	}
+	sort.Slice(result.Conflicts, func(i, j int) bool {
+		a, b := result.Conflicts[i], result.Conflicts[j]
+		if a.Ancode1 != b.Ancode1 {
+			return a.Ancode1 < b.Ancode1
+		}
+		return a.Ancode2 < b.Ancode2
+	})
+	return result, nil
+}
+
+// actualConflictPairs counts the students registered for both exams, per pair of the
+// given ancodes (smaller ancode first).
+func (p *Plexams) actualConflictPairs(ctx context.Context, ancodes map[int]string) (map[[2]int]int, error) {
+	students, err := p.StudentRegsPerStudentPlanned(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make(map[[2]int]int)
+	for _, s := range students {
+		regs := make([]int, 0, len(s.ZpaAncodes))
+		for _, a := range s.ZpaAncodes {
+			if _, ok := ancodes[a]; ok {
+				regs = append(regs, a)
+			}
+		}
+		sort.Ints(regs)
+		for i := 0; i < len(regs); i++ {
+			for j := i + 1; j < len(regs); j++ {
+				if regs[i] != regs[j] {
+					pairs[[2]int{regs[i], regs[j]}]++
+				}
+			}
+		}
+	}
+	return pairs, nil
+}
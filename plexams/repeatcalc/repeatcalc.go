@@ -1,6 +1,7 @@
 // Package repeatcalc holds the pure study-group-semester repeat heuristic: reading the
 // semester number out of a study-group code and deciding whether an exam is (likely) a
-// repeat for a student. It drives the conflict auto-accept (a repeat conflict is only
+// repeat for a student. It is the fallback of studenthistory for students without a
+// history, which drives the conflict auto-accept (a repeat conflict is only
 // informational) and the plan-generation down-weighting. All functions are I/O-free;
 // the heuristic is not fully reliable (study-group numbers are the only signal), so it
 // only down-weights, never hard-excludes.
@@ -29,3 +29,13 @@ func roomBuffers(constraints *model.Constraints) (pre, post time.Duration) {
 	}
 	return pre, post
 }
+
+// occupancyBuffers returns the lead and trailing time a planned exam occupies its room:
+// the EXaHM/SEB buffers for such exams, roomBuffers otherwise. Finding free rooms and
+// checking time-range blocks use it alike.
+func occupancyBuffers(constraints *model.Constraints) (pre, post time.Duration) {
+	if constraints != nil && constraints.RoomConstraints != nil && (constraints.RoomConstraints.Exahm || constraints.RoomConstraints.Seb) {
+		return exahmRoomBuffers(constraints)
+	}
+	return roomBuffers(constraints)
+}
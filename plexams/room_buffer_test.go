@@ -46,3 +46,13 @@ func TestRoomBuffers(t *testing.T) {
 		})
 	}
 }
+
+func TestOccupancyBuffers(t *testing.T) {
+	if pre, post := occupancyBuffers(nil); pre != roomRequestBuffer || post != roomRequestBuffer {
+		t.Errorf("plain exam: (%v, %v), want the room buffers", pre, post)
+	}
+	exahm := &model.Constraints{RoomConstraints: &model.RoomConstraints{Exahm: true}}
+	if pre, post := occupancyBuffers(exahm); pre != exahmDefaultBuffer || post != exahmDefaultBuffer {
+		t.Errorf("EXaHM exam: (%v, %v), want the EXaHM buffers", pre, post)
+	}
+}
@@ -0,0 +1,669 @@
+package plexams
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/email"
+	"github.com/obcode/plexams.go/plexams/optimize"
+	"github.com/obcode/plexams.go/plexams/roomplan"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Room outages: a room that becomes unavailable after the room plan is done. The preview
+// repairs the room plan of only the affected exams (every other exam is pinned to its
+// saved rooms, the affected exams keep as many students where they are as possible) and
+// is stored; the confirmation applies it, moves the invigilations and queues the
+// notifications for sendEmailRoomChanges.
+
+// repairChurnWeight is the minimum churn weight of a repair: moving a student who could
+// stay costs more than splitting an exam across one more room.
+const repairChurnWeight = 200
+
+// PreviewRoomOutage repairs the room plan for room being unavailable from..until and
+// stores the result as an unconfirmed outage. Nothing else is changed.
+func (p *Plexams) PreviewRoomOutage(ctx context.Context, room string, from, until time.Time, reason *string) (*model.RoomOutage, error) {
+	if !until.After(from) {
+		return nil, fmt.Errorf("the end of a room outage must be after its start")
+	}
+	if r, err := p.dbClient.RoomByName(ctx, room); err != nil || r == nil {
+		return nil, fmt.Errorf("room %s not found", room)
+	}
+	base, err := p.buildRoomPlanProblem(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roomIdx := -1
+	for i := range base.Rooms {
+		if base.Rooms[i].Name == room {
+			roomIdx = i
+		}
+	}
+	planned, err := p.dbClient.PlannedRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slotList := outageSlots(base.Slots, planned, room, from, until)
+	if len(slotList) == 0 {
+		return nil, fmt.Errorf("no exam time between %s and %s", from.Format("02.01.06 15:04"), until.Format("02.01.06 15:04"))
+	}
+	slots := make(map[int]bool, len(slotList))
+	starttimes := make([]time.Time, 0, len(slotList))
+	for _, s := range slotList {
+		slots[s] = true
+		starttimes = append(starttimes, base.Slots[s].Start)
+	}
+
+	prev := previousRooms(base, planned)
+	affected := affectedExams(base, prev, roomIdx, slots)
+	prob := repairProblem(base, prev, roomIdx, slots, affected)
+
+	opts := optimize.DefaultOptions()
+	opts.Seed = 1
+	opts.StrictImprove = true
+	st, _ := roomplan.Solve(prob, opts, true)
+
+	affectedAncodes := make(map[int]bool, len(affected))
+	for e := range affected {
+		affectedAncodes[prob.Exams[e].Ancode] = true
+	}
+	hard := make([]string, 0)
+	for _, v := range prob.Registry().HardViolations(st) {
+		if refersTo(v.Refs, affectedAncodes) {
+			hard = append(hard, fmt.Sprintf("%s: %s %v", v.Constraint, v.Message, v.Refs))
+		}
+	}
+	assignments := make([]roomplan.RoomAssignment, 0)
+	for _, a := range st.Assignments() {
+		if affectedAncodes[a.Ancode] {
+			assignments = append(assignments, a)
+		}
+	}
+
+	outage := &model.RoomOutage{
+		ID:             primitive.NewObjectID().Hex(),
+		Room:           room,
+		From:           from,
+		Until:          until,
+		Reason:         reason,
+		Starttimes:     starttimes,
+		Exams:          make([]*model.RoomOutageExam, 0),
+		Invigilations:  make([]*model.RoomOutageInvigilation, 0),
+		UnplacedSeats:  st.UnplacedCount(),
+		HardViolations: hard,
+		Notes:          make([]string, 0),
+		CreatedAt:      time.Now(),
+		PlannedRooms:   p.assignmentsToPlannedRooms(assignments, roomInfoMap(prob), prePlannedSet(ctx, p)),
+		Unplaced:       groupUnplaced(st.Unplaced()),
+		Fingerprint:    roomPlanFingerprint(planned),
+	}
+
+	inOutage := func(pr *model.PlannedRoom) bool {
+		return pr.RoomName == room && pr.Starttime != nil && containsTime(starttimes, *pr.Starttime)
+	}
+	after := make([]*model.PlannedRoom, 0, len(planned))
+	for _, pr := range planned {
+		if pr.Reserve && inOutage(pr) {
+			outage.Notes = append(outage.Notes, fmt.Sprintf("Reserveraum %s für Prüfung %d entfällt.", room, pr.Ancode))
+		}
+		if !keepOnOutage(pr, affectedAncodes, inOutage) {
+			continue
+		}
+		after = append(after, pr)
+	}
+	after = append(after, outage.PlannedRooms...)
+
+	if err := p.describeRoomOutage(ctx, outage, planned, after, affectedAncodes); err != nil {
+		return nil, err
+	}
+
+	prePlanned, err := p.dbClient.PrePlannedRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, pp := range prePlanned {
+		if pp.RoomName == room && affectedAncodes[pp.Ancode] {
+			outage.PrePlannedDropped = append(outage.PrePlannedDropped, pp)
+			outage.Notes = append(outage.Notes, fmt.Sprintf("Vorplanung von Raum %s für Prüfung %d wird entfernt.", room, pp.Ancode))
+		}
+	}
+	if outage.UnplacedSeats > 0 {
+		outage.Notes = append(outage.Notes, fmt.Sprintf("%d Plätze konnten nicht untergebracht werden.", outage.UnplacedSeats))
+	}
+
+	if err := p.dbClient.SaveRoomOutage(ctx, outage); err != nil {
+		return nil, err
+	}
+	return outage, nil
+}
+
+// describeRoomOutage fills the per-exam summary and the invigilation changes of an outage
+// from the room plan before and after the repair.
+func (p *Plexams) describeRoomOutage(ctx context.Context, outage *model.RoomOutage, before, after []*model.PlannedRoom, affected map[int]bool) error {
+	oldRoom := make(map[[2]string]string)
+	roomsBefore := make(map[int][]string)
+	starts := make(map[int]time.Time)
+	for _, pr := range before {
+		if pr.Reserve || !affected[pr.Ancode] {
+			continue
+		}
+		roomsBefore[pr.Ancode] = appendUnique(roomsBefore[pr.Ancode], pr.RoomName)
+		if pr.Starttime != nil {
+			starts[pr.Ancode] = *pr.Starttime
+		}
+		for _, m := range pr.StudentsInRoom {
+			oldRoom[[2]string{fmt.Sprint(pr.Ancode), m}] = pr.RoomName
+		}
+	}
+	roomsAfter := make(map[int][]string)
+	moved := make(map[int]int)
+	for _, pr := range outage.PlannedRooms {
+		roomsAfter[pr.Ancode] = appendUnique(roomsAfter[pr.Ancode], pr.RoomName)
+		for _, m := range pr.StudentsInRoom {
+			if oldRoom[[2]string{fmt.Sprint(pr.Ancode), m}] != pr.RoomName {
+				moved[pr.Ancode]++
+			}
+		}
+	}
+	unplaced := make(map[int]int)
+	for _, u := range outage.Unplaced {
+		unplaced[u.Ancode] += len(u.Mtknrs)
+	}
+
+	ancodes := make([]int, 0, len(affected))
+	for ancode := range affected {
+		ancodes = append(ancodes, ancode)
+	}
+	sort.Slice(ancodes, func(i, j int) bool {
+		if !starts[ancodes[i]].Equal(starts[ancodes[j]]) {
+			return starts[ancodes[i]].Before(starts[ancodes[j]])
+		}
+		return ancodes[i] < ancodes[j]
+	})
+	for _, ancode := range ancodes {
+		e := &model.RoomOutageExam{
+			Ancode: ancode, Starttime: starts[ancode],
+			RoomsBefore: sortedStrings(roomsBefore[ancode]), RoomsAfter: sortedStrings(roomsAfter[ancode]),
+			MovedStudents: moved[ancode], UnplacedStudents: unplaced[ancode],
+		}
+		if zpaExam, err := p.dbClient.GetZpaExamByAncode(ctx, ancode); err == nil && zpaExam != nil {
+			e.Module, e.MainExamer, e.MainExamerID = zpaExam.Module, zpaExam.MainExamer, zpaExam.MainExamerID
+		}
+		outage.Exams = append(outage.Exams, e)
+	}
+
+	invigilations, err := p.dbClient.GetAllInvigilations(ctx)
+	if err != nil {
+		return err
+	}
+	for _, start := range outage.Starttimes {
+		used, now := roomsAt(before, start), roomsAt(after, start)
+		// all invigilators of a room (staffing rules may ask for several): moving or
+		// removing the invigilation of a room affects every position there
+		invigilators := make(map[string][]int)
+		for _, room := range used {
+			invs, err := p.dbClient.GetInvigilationsAt(ctx, room, start)
+			if err != nil {
+				return err
+			}
+			for _, inv := range invs {
+				if !slices.Contains(invigilators[room], inv.InvigilatorID) {
+					invigilators[room] = append(invigilators[room], inv.InvigilatorID)
+				}
+			}
+		}
+		changes := invigilationChanges(start, difference(used, now), difference(now, used), invigilators, len(invigilations) > 0)
+		for _, c := range changes {
+			if c.InvigilatorID != nil {
+				if teacher, err := p.GetTeacher(ctx, *c.InvigilatorID); err == nil && teacher != nil {
+					name := teacher.Fullname
+					c.InvigilatorName = &name
+				}
+			}
+			if c.FromRoom == nil {
+				outage.Notes = append(outage.Notes, fmt.Sprintf("Raum %s braucht am %s um %s Uhr noch eine Aufsicht.",
+					*c.ToRoom, email.DateDE(start), email.TimeDE(start)))
+			}
+		}
+		outage.Invigilations = append(outage.Invigilations, changes...)
+	}
+	return nil
+}
+
+// ConfirmRoomOutage applies a previewed outage: blocks the room at the affected times,
+// writes the repaired rooms, moves (or drops) the invigilations and queues the
+// notifications for the affected examiners and invigilators.
+func (p *Plexams) ConfirmRoomOutage(ctx context.Context, id string) (*model.RoomOutage, error) {
+	outage, err := p.dbClient.RoomOutage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if outage == nil {
+		return nil, fmt.Errorf("room outage %s not found", id)
+	}
+	if outage.ConfirmedAt != nil {
+		return nil, fmt.Errorf("room outage %s is already confirmed", id)
+	}
+	if len(outage.HardViolations) > 0 {
+		return nil, fmt.Errorf("room outage %s has %d hard violations", id, len(outage.HardViolations))
+	}
+	planned, err := p.dbClient.PlannedRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if roomPlanFingerprint(planned) != outage.Fingerprint {
+		return nil, fmt.Errorf("the room plan changed since the preview; preview the outage again")
+	}
+
+	for _, start := range outage.Starttimes {
+		if _, err := p.BlockRoomForSlot(ctx, outage.Room, start, outage.Reason); err != nil {
+			return nil, err
+		}
+	}
+
+	affected := make(map[int]bool, len(outage.Exams))
+	for _, e := range outage.Exams {
+		affected[e.Ancode] = true
+	}
+	inOutage := func(pr *model.PlannedRoom) bool {
+		return pr.RoomName == outage.Room && pr.Starttime != nil && containsTime(outage.Starttimes, *pr.Starttime)
+	}
+	rows := make([]*model.PlannedRoom, 0, len(planned)+len(outage.PlannedRooms))
+	for _, pr := range planned {
+		if keepOnOutage(pr, affected, inOutage) {
+			rows = append(rows, pr)
+		}
+	}
+	rows = append(rows, outage.PlannedRooms...)
+	if err := p.dbClient.ReplacePlannedRooms(ctx, rows); err != nil {
+		return nil, err
+	}
+
+	unplaced, err := p.dbClient.UnplacedExams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keptUnplaced := make([]*model.UnplacedExam, 0, len(unplaced)+len(outage.Unplaced))
+	for _, u := range unplaced {
+		if !affected[u.Ancode] {
+			keptUnplaced = append(keptUnplaced, u)
+		}
+	}
+	if err := p.dbClient.ReplaceUnplacedExams(ctx, append(keptUnplaced, outage.Unplaced...)); err != nil {
+		return nil, err
+	}
+
+	for _, pp := range outage.PrePlannedDropped {
+		if _, err := p.dbClient.RemovePrePlannedRoomFromExam(ctx, pp.Ancode, pp.RoomName, pp.Mtknr); err != nil {
+			return nil, err
+		}
+	}
+
+	done := make(map[string]bool) // the db calls act on every position of a room
+	for _, c := range outage.Invigilations {
+		if c.FromRoom == nil {
+			continue
+		}
+		key := *c.FromRoom + "@" + startKey(c.Starttime)
+		if done[key] {
+			continue
+		}
+		done[key] = true
+		switch {
+		case c.ToRoom != nil:
+			_, err = p.dbClient.MoveInvigilationAt(ctx, c.Starttime, *c.FromRoom, *c.ToRoom)
+		case c.FromRoom != nil:
+			_, err = p.dbClient.RemoveInvigilationAt(ctx, c.Starttime, *c.FromRoom)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	if err := p.dbClient.AddRoomChangeNotifications(ctx, roomChangeNotifications(outage, now)); err != nil {
+		return nil, err
+	}
+	outage.ConfirmedAt = &now
+	if err := p.dbClient.SaveRoomOutage(ctx, outage); err != nil {
+		return nil, err
+	}
+	log.Info().Str("room", outage.Room).Int("exams", len(outage.Exams)).Msg("room outage confirmed")
+	return outage, nil
+}
+
+// DiscardRoomOutage removes a preview that was not confirmed.
+func (p *Plexams) DiscardRoomOutage(ctx context.Context, id string) (bool, error) {
+	outage, err := p.dbClient.RoomOutage(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if outage == nil {
+		return false, fmt.Errorf("room outage %s not found", id)
+	}
+	if outage.ConfirmedAt != nil {
+		return false, fmt.Errorf("room outage %s is already confirmed", id)
+	}
+	return p.dbClient.RemoveRoomOutage(ctx, id)
+}
+
+func (p *Plexams) RoomOutages(ctx context.Context) ([]*model.RoomOutage, error) {
+	return p.dbClient.RoomOutages(ctx)
+}
+
+func (p *Plexams) RoomChangeNotifications(ctx context.Context, pendingOnly bool) ([]*model.RoomChangeNotification, error) {
+	return p.dbClient.RoomChangeNotifications(ctx, pendingOnly)
+}
+
+// roomChangeNotifications builds one notification per examiner and per invigilator
+// affected by a confirmed outage.
+func roomChangeNotifications(outage *model.RoomOutage, now time.Time) []*model.RoomChangeNotification {
+	type key struct {
+		teacher int
+		role    model.RoomChangeRole
+	}
+	byKey := make(map[key]*model.RoomChangeNotification)
+	var order []key
+	add := func(k key, name, line string) {
+		n := byKey[k]
+		if n == nil {
+			n = &model.RoomChangeNotification{
+				ID: primitive.NewObjectID().Hex(), OutageID: outage.ID, Room: outage.Room, Reason: outage.Reason,
+				TeacherID: k.teacher, TeacherName: name, Role: k.role, Lines: []string{}, CreatedAt: now,
+			}
+			byKey[k] = n
+			order = append(order, k)
+		}
+		n.Lines = append(n.Lines, line)
+	}
+
+	for _, e := range outage.Exams {
+		if e.MainExamerID == 0 {
+			continue
+		}
+		line := fmt.Sprintf("%d. %s, %s %s Uhr: jetzt %s (bisher %s)", e.Ancode, e.Module,
+			email.DateDE(e.Starttime), email.TimeDE(e.Starttime), orNone(e.RoomsAfter), orNone(e.RoomsBefore))
+		if e.UnplacedStudents > 0 {
+			line += fmt.Sprintf(", %d Studierende noch ohne Raum", e.UnplacedStudents)
+		}
+		add(key{e.MainExamerID, model.RoomChangeRoleExamer}, e.MainExamer, line)
+	}
+	for _, c := range outage.Invigilations {
+		if c.InvigilatorID == nil || c.FromRoom == nil {
+			continue
+		}
+		name := ""
+		if c.InvigilatorName != nil {
+			name = *c.InvigilatorName
+		}
+		when := fmt.Sprintf("%s %s Uhr", email.DateDE(c.Starttime), email.TimeDE(c.Starttime))
+		line := fmt.Sprintf("%s: Ihre Aufsicht in %s entfällt", when, *c.FromRoom)
+		if c.ToRoom != nil {
+			line = fmt.Sprintf("%s: Aufsicht in %s statt %s", when, *c.ToRoom, *c.FromRoom)
+		}
+		add(key{*c.InvigilatorID, model.RoomChangeRoleInvigilator}, name, line)
+	}
+
+	out := make([]*model.RoomChangeNotification, 0, len(order))
+	for _, k := range order {
+		out = append(out, byKey[k])
+	}
+	return out
+}
+
+// outageSlots returns the slots in which room is unavailable for an outage from..until:
+// every slot starting in the range, and an earlier slot whose use of the room (its
+// longest planned exam there) still runs at from.
+func outageSlots(slots []roomplan.Slot, planned []*model.PlannedRoom, room string, from, until time.Time) []int {
+	var out []int
+	for i, s := range slots {
+		if !s.Start.Before(until) {
+			continue
+		}
+		if !s.Start.Before(from) {
+			out = append(out, i)
+			continue
+		}
+		maxDuration := 0
+		for _, pr := range planned {
+			if pr.RoomName == room && pr.Starttime != nil && pr.Starttime.Equal(s.Start) && pr.Duration > maxDuration {
+				maxDuration = pr.Duration
+			}
+		}
+		if s.Start.Add(time.Duration(maxDuration) * time.Minute).After(from) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// previousRooms maps every seat of the problem to its room in the saved plan (-1 = none).
+// Seats without a student (additional seats) are not recorded in the plan.
+func previousRooms(prob *roomplan.Problem, planned []*model.PlannedRoom) []int {
+	roomIdx := make(map[string]int, len(prob.Rooms))
+	for i := range prob.Rooms {
+		roomIdx[prob.Rooms[i].Name] = i
+	}
+	type key struct {
+		ancode int
+		mtknr  string
+	}
+	roomOf := make(map[key]int)
+	for _, pr := range planned {
+		ri, ok := roomIdx[pr.RoomName]
+		if pr.Reserve || !ok {
+			continue
+		}
+		for _, m := range pr.StudentsInRoom {
+			roomOf[key{pr.Ancode, m}] = ri
+		}
+	}
+	prev := make([]int, len(prob.Seats))
+	for i, s := range prob.Seats {
+		prev[i] = -1
+		if s.Mtknr == "" {
+			continue
+		}
+		if ri, ok := roomOf[key{prob.Exams[s.Exam].Ancode, s.Mtknr}]; ok {
+			prev[i] = ri
+		}
+	}
+	return prev
+}
+
+// affectedExams returns the exams with a seat in room during one of the outage slots.
+func affectedExams(prob *roomplan.Problem, prev []int, room int, slots map[int]bool) map[int]bool {
+	affected := make(map[int]bool)
+	if room < 0 {
+		return affected
+	}
+	for i, s := range prob.Seats {
+		if !slots[prob.Exams[s.Exam].Slot] {
+			continue
+		}
+		if prev[i] == room || s.Fixed && s.FixedRoom == room {
+			affected[s.Exam] = true
+		}
+	}
+	return affected
+}
+
+// repairProblem derives the repair problem from the full one: room is no longer allowed in
+// the outage slots, every seat of an unaffected exam is fixed to its saved room (its seats
+// without one are left out), the affected exams keep their pre-planned rooms elsewhere and
+// start from their saved rooms with a churn cost for every student who moves.
+func repairProblem(base *roomplan.Problem, prev []int, room int, slots map[int]bool, affected map[int]bool) *roomplan.Problem {
+	exams := make([]roomplan.Exam, len(base.Exams))
+	for e, ex := range base.Exams {
+		if slots[ex.Slot] {
+			ex.AllowedNormal = withoutRoom(ex.AllowedNormal, room)
+			ex.AllowedAlone = withoutRoom(ex.AllowedAlone, room)
+		}
+		exams[e] = ex
+	}
+	seats := make([]roomplan.Seat, 0, len(base.Seats))
+	prevOut := make([]int, 0, len(base.Seats))
+	for i, s := range base.Seats {
+		switch {
+		case affected[s.Exam]:
+			if s.Fixed && s.FixedRoom == room && slots[base.Exams[s.Exam].Slot] {
+				s.Fixed = false
+			}
+		case prev[i] >= 0:
+			s.Fixed, s.FixedRoom = true, prev[i]
+		case !s.Fixed:
+			continue
+		}
+		seats = append(seats, s)
+		prevOut = append(prevOut, prev[i])
+	}
+	w := base.W
+	if w.Churn < repairChurnWeight {
+		w.Churn = repairChurnWeight
+	}
+	prob := roomplan.NewProblem(base.Slots, base.Rooms, exams, seats, w)
+	prob.Summer = base.Summer
+	prob.TimelagMin = base.TimelagMin
+	prob.PrevRoom = prevOut
+	return prob
+}
+
+// invigilationChanges pairs, for one start time, the rooms an outage frees with the rooms
+// it newly uses: the invigilators of a freed room move together to a new room (both in
+// name order), one change per invigilator; a freed room left over loses its
+// invigilations, a new room left over needs an invigilator (only reported once
+// invigilations are planned at all).
+func invigilationChanges(start time.Time, freed, added []string, invigilators map[string][]int, invigilationsPlanned bool) []*model.RoomOutageInvigilation {
+	freed, added = sortedStrings(freed), sortedStrings(added)
+	out := make([]*model.RoomOutageInvigilation, 0)
+	k := 0
+	for _, room := range freed {
+		ids := invigilators[room]
+		if len(ids) == 0 {
+			continue
+		}
+		var toRoom *string
+		if k < len(added) {
+			to := added[k]
+			toRoom = &to
+			k++
+		}
+		for _, id := range ids {
+			fromRoom, invID := room, id
+			out = append(out, &model.RoomOutageInvigilation{Starttime: start, FromRoom: &fromRoom, ToRoom: toRoom, InvigilatorID: &invID})
+		}
+	}
+	if invigilationsPlanned {
+		for ; k < len(added); k++ {
+			toRoom := added[k]
+			out = append(out, &model.RoomOutageInvigilation{Starttime: start, ToRoom: &toRoom})
+		}
+	}
+	return out
+}
+
+// roomPlanFingerprint identifies the saved room plan, so a preview is only applied to the
+// plan it was computed from.
+func roomPlanFingerprint(planned []*model.PlannedRoom) string {
+	lines := make([]string, 0, len(planned))
+	for _, pr := range planned {
+		start := ""
+		if pr.Starttime != nil {
+			start = pr.Starttime.UTC().Format(time.RFC3339)
+		}
+		students := append([]string(nil), pr.StudentsInRoom...)
+		sort.Strings(students)
+		lines = append(lines, fmt.Sprintf("%s|%s|%d|%t|%s", start, pr.RoomName, pr.Ancode, pr.Reserve, strings.Join(students, ",")))
+	}
+	sort.Strings(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// keepOnOutage reports whether a saved planned room stays when an outage is applied:
+// the affected exams' rooms are replaced, reserve rooms in the blocked room are dropped.
+func keepOnOutage(pr *model.PlannedRoom, affected map[int]bool, inOutage func(*model.PlannedRoom) bool) bool {
+	if pr.Reserve {
+		return !inOutage(pr)
+	}
+	return !affected[pr.Ancode]
+}
+
+func roomsAt(planned []*model.PlannedRoom, start time.Time) []string {
+	var rooms []string
+	for _, pr := range planned {
+		if !pr.Reserve && pr.Starttime != nil && pr.Starttime.Equal(start) {
+			rooms = appendUnique(rooms, pr.RoomName)
+		}
+	}
+	return rooms
+}
+
+func refersTo(refs []int, ancodes map[int]bool) bool {
+	if len(refs) == 0 {
+		return true
+	}
+	for _, r := range refs {
+		if ancodes[r] {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutRoom(rooms []int, room int) []int {
+	out := make([]int, 0, len(rooms))
+	for _, r := range rooms {
+		if r != room {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func containsTime(times []time.Time, t time.Time) bool {
+	for _, x := range times {
+		if x.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+func difference(a, b []string) []string {
+	out := make([]string, 0)
+	for _, x := range a {
+		if !slices.Contains(b, x) {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+func sortedStrings(list []string) []string {
+	out := append([]string{}, list...)
+	sort.Strings(out)
+	return out
+}
+
+func orNone(rooms []string) string {
+	if len(rooms) == 0 {
+		return "kein Raum"
+	}
+	return strings.Join(rooms, ", ")
+}
@@ -0,0 +1,221 @@
+package plexams
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/optimize"
+	"github.com/obcode/plexams.go/plexams/roomplan"
+)
+
+// outageFixture: two slots on one day, three rooms; exam 1 (slot 0) sits in A and B,
+// exam 2 (slot 0) in C, exam 3 (slot 1) in A.
+func outageFixture() (*roomplan.Problem, []*model.PlannedRoom) {
+	s0 := time.Date(2026, 7, 13, 8, 30, 0, 0, time.Local)
+	s1 := time.Date(2026, 7, 13, 10, 30, 0, 0, time.Local)
+	rooms := []roomplan.Room{{Name: "A", Seats: 2}, {Name: "B", Seats: 2}, {Name: "C", Seats: 4}}
+	all := []int{0, 1, 2}
+	exams := []roomplan.Exam{
+		{Ancode: 1, Slot: 0, Duration: 90, NormalCount: 3, AllowedNormal: all, AllowedAlone: all},
+		{Ancode: 2, Slot: 0, Duration: 60, NormalCount: 1, AllowedNormal: all, AllowedAlone: all},
+		{Ancode: 3, Slot: 1, Duration: 60, NormalCount: 1, AllowedNormal: all, AllowedAlone: all},
+	}
+	seats := []roomplan.Seat{
+		{Exam: 0, Mtknr: "a"}, {Exam: 0, Mtknr: "b"}, {Exam: 0, Mtknr: "c"},
+		{Exam: 1, Mtknr: "d"}, {Exam: 1}, // additional seat
+		{Exam: 2, Mtknr: "e"},
+	}
+	prob := roomplan.NewProblem([]roomplan.Slot{{Start: s0}, {Start: s1}}, rooms, exams, seats, roomplan.DefaultWeights())
+	planned := []*model.PlannedRoom{
+		{Starttime: &s0, RoomName: "A", Ancode: 1, Duration: 90, StudentsInRoom: []string{"a", "b"}},
+		{Starttime: &s0, RoomName: "B", Ancode: 1, Duration: 90, StudentsInRoom: []string{"c"}},
+		{Starttime: &s0, RoomName: "C", Ancode: 2, Duration: 60, StudentsInRoom: []string{"d"}},
+		{Starttime: &s0, RoomName: "A", Ancode: 2, Reserve: true, StudentsInRoom: []string{}},
+		{Starttime: &s1, RoomName: "A", Ancode: 3, Duration: 60, StudentsInRoom: []string{"e"}},
+	}
+	return prob, planned
+}
+
+func TestOutageSlots(t *testing.T) {
+	prob, planned := outageFixture()
+	s0 := prob.Slots[0].Start
+	for _, c := range []struct {
+		room        string
+		from, until time.Time
+		want        []int
+	}{
+		{"A", s0, s0.Add(time.Minute), []int{0}},
+		{"A", s0.Add(80 * time.Minute), s0.Add(2 * time.Hour), []int{0}},    // exam in A still running
+		{"C", s0.Add(80 * time.Minute), s0.Add(2 * time.Hour), nil},         // exam in C already over
+		{"A", s0.Add(80 * time.Minute), s0.Add(3 * time.Hour), []int{0, 1}}, // both
+		{"A", s0.Add(-time.Hour), s0, nil},
+	} {
+		got := outageSlots(prob.Slots, planned, c.room, c.from, c.until)
+		if len(got) != len(c.want) {
+			t.Errorf("outageSlots(%s, %s–%s) = %v, want %v", c.room, c.from.Format("15:04"), c.until.Format("15:04"), got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("outageSlots(%s, %s–%s) = %v, want %v", c.room, c.from.Format("15:04"), c.until.Format("15:04"), got, c.want)
+			}
+		}
+	}
+}
+
+func TestRepairProblem(t *testing.T) {
+	prob, planned := outageFixture()
+	prev := previousRooms(prob, planned)
+	want := []int{0, 0, 1, 2, -1, 0}
+	for i := range want {
+		if prev[i] != want[i] {
+			t.Fatalf("previousRooms = %v, want %v", prev, want)
+		}
+	}
+
+	slots := map[int]bool{0: true}
+	affected := affectedExams(prob, prev, 0, slots)
+	if len(affected) != 1 || !affected[0] {
+		t.Fatalf("affectedExams = %v, want only exam 0", affected)
+	}
+
+	repair := repairProblem(prob, prev, 0, slots, affected)
+	// the additional seat of the unaffected exam 2 has no saved room and is left out
+	if len(repair.Seats) != 5 {
+		t.Fatalf("repair has %d seats, want 5", len(repair.Seats))
+	}
+	for _, r := range repair.Exams[0].AllowedNormal {
+		if r == 0 {
+			t.Errorf("room A still allowed for exam 1 in the outage slot")
+		}
+	}
+	if len(repair.Exams[2].AllowedNormal) != 3 {
+		t.Errorf("room A must stay allowed outside the outage slots")
+	}
+	for i, s := range repair.Seats {
+		if s.Exam != 0 && (!s.Fixed || s.FixedRoom != repair.PrevRoom[i]) {
+			t.Errorf("seat %d of unaffected exam %d not fixed to its saved room", i, s.Exam)
+		}
+		if s.Exam == 0 && s.Fixed {
+			t.Errorf("seat %d of the affected exam is fixed", i)
+		}
+	}
+	if repair.W.Churn < repairChurnWeight {
+		t.Errorf("churn weight %v below %v", repair.W.Churn, repairChurnWeight)
+	}
+
+	opts := optimize.DefaultOptions()
+	opts.Seed = 1
+	opts.Iterations = 2000
+	st, _ := roomplan.Solve(repair, opts, true)
+	if st.UnplacedCount() != 0 {
+		t.Fatalf("%d seats unplaced", st.UnplacedCount())
+	}
+	for _, a := range st.Assignments() {
+		switch {
+		case a.Ancode == 1 && a.Room == "A":
+			t.Errorf("exam 1 still in the blocked room")
+		case a.Ancode == 1 && a.Room == "B" && !slices.Contains(a.Mtknrs, "c"):
+			t.Errorf("exam 1: student c moved out of B (%v)", a.Mtknrs)
+		case a.Ancode == 3 && a.Room != "A":
+			t.Errorf("exam 3 moved to %s although outside the outage", a.Room)
+		}
+	}
+}
+
+func TestInvigilationChanges(t *testing.T) {
+	start := time.Date(2026, 7, 13, 8, 30, 0, 0, time.Local)
+	changes := invigilationChanges(start, []string{"B", "A", "X"}, []string{"D"}, map[string][]int{"A": {7}, "B": {8}}, true)
+	if len(changes) != 2 {
+		t.Fatalf("got %d changes, want 2", len(changes))
+	}
+	if *changes[0].FromRoom != "A" || changes[0].ToRoom == nil || *changes[0].ToRoom != "D" || *changes[0].InvigilatorID != 7 {
+		t.Errorf("first change: want A → D by 7")
+	}
+	if *changes[1].FromRoom != "B" || changes[1].ToRoom != nil {
+		t.Errorf("second change: want B dropped")
+	}
+
+	changes = invigilationChanges(start, nil, []string{"D"}, nil, true)
+	if len(changes) != 1 || changes[0].FromRoom != nil || *changes[0].ToRoom != "D" {
+		t.Errorf("a new room must be reported as needing an invigilator")
+	}
+	if changes = invigilationChanges(start, nil, []string{"D"}, nil, false); len(changes) != 0 {
+		t.Errorf("no invigilator needed before invigilations are planned")
+	}
+	// a staffing rule put two invigilators into A: both move to D, both are notified
+	changes = invigilationChanges(start, []string{"A"}, []string{"D"}, map[string][]int{"A": {7, 9}}, true)
+	if len(changes) != 2 {
+		t.Fatalf("staffed room: got %d changes, want 2", len(changes))
+	}
+	for i, want := range []int{7, 9} {
+		c := changes[i]
+		if *c.FromRoom != "A" || c.ToRoom == nil || *c.ToRoom != "D" || *c.InvigilatorID != want {
+			t.Errorf("staffed room, change %d: want A → D by %d", i, want)
+		}
+	}
+	ns := roomChangeNotifications(&model.RoomOutage{ID: "o2", Room: "A", Invigilations: changes}, start)
+	if len(ns) != 2 || ns[0].TeacherID != 7 || ns[1].TeacherID != 9 {
+		t.Errorf("staffed room: want a notification for 7 and 9, got %+v", ns)
+	}
+
+	// dropped without a new room: every invigilator of the room loses the invigilation
+	changes = invigilationChanges(start, []string{"A"}, nil, map[string][]int{"A": {7, 9}}, true)
+	if len(changes) != 2 || changes[0].ToRoom != nil || changes[1].ToRoom != nil {
+		t.Errorf("staffed room dropped: want two removals, got %d changes", len(changes))
+	}
+}
+
+func TestRoomChangeNotifications(t *testing.T) {
+	start := time.Date(2026, 7, 13, 8, 30, 0, 0, time.Local)
+	a, d := "A", "D"
+	inv := 7
+	name := "Dr. Aufsicht"
+	outage := &model.RoomOutage{
+		ID: "o1", Room: "A",
+		Exams: []*model.RoomOutageExam{
+			{Ancode: 1, Module: "Mathe", MainExamer: "Prof. Test", MainExamerID: 3, Starttime: start,
+				RoomsBefore: []string{"A", "B"}, RoomsAfter: []string{"B", "D"}},
+			{Ancode: 2, Module: "Physik", MainExamer: "Prof. Test", MainExamerID: 3, Starttime: start,
+				RoomsBefore: []string{"A"}, RoomsAfter: []string{}, UnplacedStudents: 4},
+		},
+		Invigilations: []*model.RoomOutageInvigilation{
+			{Starttime: start, FromRoom: &a, ToRoom: &d, InvigilatorID: &inv, InvigilatorName: &name},
+			{Starttime: start, ToRoom: &d},
+		},
+	}
+	ns := roomChangeNotifications(outage, start)
+	if len(ns) != 2 {
+		t.Fatalf("got %d notifications, want 2", len(ns))
+	}
+	if ns[0].TeacherID != 3 || ns[0].Role != model.RoomChangeRoleExamer || len(ns[0].Lines) != 2 {
+		t.Errorf("examer notification: %+v", ns[0])
+	}
+	if want := "1. Mathe, Mo, 13.07.2026 08:30 Uhr: jetzt B, D (bisher A, B)"; ns[0].Lines[0] != want {
+		t.Errorf("line = %q, want %q", ns[0].Lines[0], want)
+	}
+	if want := "2. Physik, Mo, 13.07.2026 08:30 Uhr: jetzt kein Raum (bisher A), 4 Studierende noch ohne Raum"; ns[0].Lines[1] != want {
+		t.Errorf("line = %q, want %q", ns[0].Lines[1], want)
+	}
+	if ns[1].TeacherID != 7 || ns[1].Role != model.RoomChangeRoleInvigilator || ns[1].Lines[0] != "Mo, 13.07.2026 08:30 Uhr: Aufsicht in D statt A" {
+		t.Errorf("invigilator notification: %+v", ns[1])
+	}
+}
+
+func TestRoomPlanFingerprint(t *testing.T) {
+	_, planned := outageFixture()
+	fp := roomPlanFingerprint(planned)
+	reversed := make([]*model.PlannedRoom, len(planned))
+	for i, pr := range planned {
+		reversed[len(planned)-1-i] = pr
+	}
+	if roomPlanFingerprint(reversed) != fp {
+		t.Errorf("fingerprint depends on the order of the rows")
+	}
+	planned[0].StudentsInRoom = []string{"a"}
+	if roomPlanFingerprint(planned) == fp {
+		t.Errorf("fingerprint ignores a moved student")
+	}
+}
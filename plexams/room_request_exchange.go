@@ -0,0 +1,218 @@
+package plexams
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/roomexchange"
+	"github.com/rs/zerolog/log"
+)
+
+// Room-request exchange with the Gebäudemanagement (see roomexchange): the active
+// requests go out as a table with a "Genehmigt" column plus ICS invites, the annotated
+// table comes back as an upload that approves/rejects the requests in bulk.
+
+// RoomRequestsXLSXBytes builds the exchange table as XLSX.
+func (p *Plexams) RoomRequestsXLSXBytes(ctx context.Context) ([]byte, error) {
+	requests, err := p.dbClient.RoomRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return roomexchange.XLSX(requests)
+}
+
+// RoomRequestsCSVBytes builds the exchange table as CSV.
+func (p *Plexams) RoomRequestsCSVBytes(ctx context.Context) ([]byte, error) {
+	requests, err := p.dbClient.RoomRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return roomexchange.CSV(requests)
+}
+
+// roomRequestInvites returns the ICS invites of the active requests, one file per room,
+// sorted by filename.
+func (p *Plexams) roomRequestInvites(ctx context.Context) ([]*mailAttachment, error) {
+	requests, err := p.dbClient.RoomRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	organizer := ""
+	if p.planer != nil {
+		organizer = p.planer.Email
+	}
+	attendee := ""
+	if p.semesterConfig != nil && p.semesterConfig.Emails != nil {
+		attendee = p.semesterConfig.Emails.RoomManagement
+	}
+	files := roomexchange.ICS(p.semester, organizer, attendee, requests, time.Now())
+	out := make([]*mailAttachment, 0, len(files))
+	for room, data := range files {
+		out = append(out, &mailAttachment{
+			Filename:    fmt.Sprintf("Raumanfrage_%s.ics", strings.ReplaceAll(room, " ", "")),
+			ContentType: "text/calendar; charset=utf-8; method=REQUEST",
+			Content:     data,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Filename < out[j].Filename })
+	return out, nil
+}
+
+// RoomRequestInvitesZipBytes builds a ZIP with one ICS file per room.
+func (p *Plexams) RoomRequestInvitesZipBytes(ctx context.Context) ([]byte, error) {
+	invites, err := p.roomRequestInvites(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, inv := range invites {
+		f, err := zw.Create(inv.Filename)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(inv.Content); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportRoomRequestAnswers reads the returned, annotated table and approves/rejects the
+// requests it answers. dryRun (or any problem in the file) only returns the diff
+// preview. Every applied change is recorded in the mutation log.
+func (p *Plexams) ImportRoomRequestAnswers(ctx context.Context, data []byte, dryRun bool) (*roomexchange.Import, error) {
+	answers, unanswered, problems, err := roomexchange.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := p.dbClient.RoomRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := roomexchange.Diff(requests, answers, unanswered, problems)
+	if dryRun || len(result.Problems) > 0 || len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	for _, c := range result.Changes {
+		if c.Approved != c.ApprovedBefore {
+			if _, err := p.dbClient.SetRoomRequestApproved(ctx, c.Room, c.Starttime, c.Approved); err != nil {
+				return nil, err
+			}
+		}
+		if c.Active != c.ActiveBefore {
+			if _, err := p.dbClient.SetRoomRequestActive(ctx, c.Room, c.Starttime, c.Active); err != nil {
+				return nil, err
+			}
+		}
+		p.LogMutation(ctx, &model.MutationLogEntry{
+			Time: time.Now(),
+			Name: "importRoomRequestAnswer",
+			Type: "upload",
+			User: p.OperatorID(),
+			Args: []*model.MutationLogArg{
+				{Key: "room", Value: c.Room},
+				{Key: "starttime", Value: c.Starttime.Format(time.RFC3339)},
+				{Key: "approved", Value: strconv.FormatBool(c.Approved)},
+				{Key: "active", Value: strconv.FormatBool(c.Active)},
+				{Key: "note", Value: c.Note},
+			},
+			Ancodes: []int{},
+		})
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// HTTPDownloadRoomRequests streams the room-request exchange files.
+// GET /download/room-requests/{format}   (format = xlsx, csv or ics (ZIP, one file per room))
+func (p *Plexams) HTTPDownloadRoomRequests(w http.ResponseWriter, r *http.Request) {
+	format := chi.URLParam(r, "format")
+	var (
+		data        []byte
+		err         error
+		filename    string
+		contentType string
+	)
+	switch format {
+	case "xlsx":
+		data, err = p.RoomRequestsXLSXBytes(r.Context())
+		filename = "Raumanfragen.xlsx"
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case "csv":
+		data, err = p.RoomRequestsCSVBytes(r.Context())
+		filename = "Raumanfragen.csv"
+		contentType = "text/csv; charset=utf-8"
+	case "ics":
+		data, err = p.RoomRequestInvitesZipBytes(r.Context())
+		filename = "Raumanfragen-Termine.zip"
+		contentType = "application/zip"
+	default:
+		http.Error(w, fmt.Sprintf("unknown format %q (known: xlsx, csv, ics)", format), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "cannot generate room requests: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fullName := fmt.Sprintf("%s_%s", strings.ReplaceAll(p.semester, " ", "_"), filename)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fullName))
+	if _, err := w.Write(data); err != nil {
+		log.Error().Err(err).Str("format", format).Msg("cannot write room requests download")
+	}
+}
+
+// HTTPUploadRoomRequestAnswers imports the table returned by the Gebäudemanagement
+// (multipart field "file", XLSX or CSV). With ?dryRun=true it only returns the diff
+// preview; otherwise it applies the changes unless the file has problems.
+// POST /upload/room-request-answers
+func (p *Plexams) HTTPUploadRoomRequestAnswers(w http.ResponseWriter, r *http.Request) {
+	if !p.WritesAllowed() {
+		http.Error(w, "a validation or transfer/email is running, cannot upload now", http.StatusConflict)
+		return
+	}
+	if p.IsReadOnly() {
+		http.Error(w, "semester is read-only", http.StatusConflict)
+		return
+	}
+	if err := r.ParseMultipartForm(16 << 20); err != nil {
+		http.Error(w, "cannot parse upload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "missing file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close() //nolint:errcheck
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "cannot read file: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+	result, err := p.ImportRoomRequestAnswers(r.Context(), data, dryRun)
+	if err != nil {
+		http.Error(w, "cannot import room request answers: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if result.Applied {
+		p.LogUpload(r.Context(), "uploadRoomRequestAnswers", "file", header.Filename, "changes", strconv.Itoa(len(result.Changes)))
+	}
+	writeJSON(w, result)
+}
@@ -117,14 +117,14 @@ func pickRequestRooms(mgmt []*model.Room, usedInSlot map[string]bool, studs int)
 
 // needsRequestRoom reports whether an exam is a candidate for a building-
 // management request room: planned by me and without specific room constraints
-// (exahm/lab/seb/places-with-socket are handled elsewhere).
+// (exahm/lab/seb/places-with-socket and required tags are handled elsewhere).
 func needsRequestRoom(exam *model.PlannedExam) bool {
 	if exam.Constraints != nil && exam.Constraints.NotPlannedByMe {
 		return false
 	}
 	if exam.Constraints != nil && exam.Constraints.RoomConstraints != nil {
 		rc := exam.Constraints.RoomConstraints
-		if rc.Exahm || rc.Lab || rc.Seb || rc.PlacesWithSocket {
+		if rc.Exahm || rc.Lab || rc.Seb || rc.PlacesWithSocket || len(rc.RequiredTags) > 0 {
 			return false
 		}
 	}
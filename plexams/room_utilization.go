@@ -0,0 +1,157 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jszwec/csvutil"
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/anny"
+	"github.com/obcode/plexams.go/plexams/csvgen"
+	"github.com/obcode/plexams.go/plexams/preplancalc"
+	"github.com/obcode/plexams.go/plexams/roomstats"
+	"github.com/rs/zerolog/log"
+)
+
+// RoomUtilization measures the planned rooms of the current workspace (see roomstats)
+// and, when available, of the compared workspace: compareWith, or else the previous
+// semester's workspace if it exists.
+func (p *Plexams) RoomUtilization(ctx context.Context, compareWith *string) (*model.RoomUtilization, error) {
+	rooms, err := p.roomStatsRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := p.dbClient.DatabaseName()
+	stat, err := p.roomUtilizationFor(ctx, current, rooms)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := ""
+	if compareWith != nil && strings.TrimSpace(*compareWith) != "" {
+		previous = strings.TrimSpace(*compareWith)
+		if !p.dbClient.DatabaseHasConfig(ctx, previous) {
+			return nil, fmt.Errorf("workspace %s not found", previous)
+		}
+	} else if workspaces := p.previousWorkspaces(ctx, 1); len(workspaces) > 0 {
+		previous = workspaces[0]
+	}
+	if previous != "" && previous != current {
+		prev, err := p.roomUtilizationFor(ctx, previous, rooms)
+		if err != nil {
+			return nil, err
+		}
+		stat.Previous = prev.Summary
+	}
+	return stat, nil
+}
+
+func (p *Plexams) roomUtilizationFor(ctx context.Context, database string, rooms map[string]roomstats.Room) (*model.RoomUtilization, error) {
+	planned, err := p.dbClient.PlannedRoomsForDatabase(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	uses := make([]roomstats.Use, 0, len(planned))
+	for _, pr := range planned {
+		if pr.Starttime == nil {
+			continue
+		}
+		uses = append(uses, roomstats.Use{
+			Room: pr.RoomName, Start: *pr.Starttime, Duration: pr.Duration, Ancode: pr.Ancode,
+			Students: len(pr.StudentsInRoom), Reserve: pr.Reserve,
+		})
+	}
+
+	bookings, err := p.dbClient.AnnyBookingsForDatabase(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	names := p.anny.PersonalizationNames(ctx)
+	annyRoom := make(map[string]string)
+	for name, r := range rooms {
+		if r.RequestWith == model.RoomRequestTypeAnny {
+			annyRoom[preplancalc.NormRoomName(name)] = name
+		}
+	}
+	booked := make([]roomstats.Booking, 0, len(bookings))
+	for _, b := range bookings {
+		if b.Room == "" || b.CanceledAt != nil || b.IsBlocker ||
+			!anny.MatchesAnyPersonalization(b.PersonalizationName, names) || !anny.IsApprovedStatus(b.Status) {
+			continue
+		}
+		room, ok := annyRoom[preplancalc.NormRoomName(b.Room)]
+		if !ok {
+			continue
+		}
+		booked = append(booked, roomstats.Booking{Room: room, From: b.StartDate, Until: b.EndDate})
+	}
+
+	return roomstats.Compute(database, rooms, uses, booked), nil
+}
+
+// roomStatsRooms returns seats and request kind of all rooms. Rooms are global, so
+// a compared semester is measured with today's seat numbers.
+func (p *Plexams) roomStatsRooms(ctx context.Context) (map[string]roomstats.Room, error) {
+	rooms, err := p.dbClient.Rooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]roomstats.Room, len(rooms))
+	for _, r := range rooms {
+		out[r.Name] = roomstats.Room{Seats: r.Seats, RequestWith: r.RequestWith}
+	}
+	return out, nil
+}
+
+// previousSemester returns the semester before "2026 SS" ("2025 WS") or "2026 WS"
+// ("2026 SS"); "" when the name does not parse.
+func previousSemester(semester string) string {
+	parts := strings.Fields(strings.Replace(semester, "-", " ", 1))
+	if len(parts) != 2 {
+		return ""
+	}
+	year, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return ""
+	}
+	switch parts[1] {
+	case "SS":
+		return fmt.Sprintf("%d WS", year-1)
+	case "WS":
+		return fmt.Sprintf("%d SS", year)
+	}
+	return ""
+}
+
+// previousWorkspaces returns the database names of the previous semesters (up to n back,
+// newest first); workspaces that do not exist are skipped.
+func (p *Plexams) previousWorkspaces(ctx context.Context, n int) []string {
+	workspaces := make([]string, 0, n)
+	semester := p.semester
+	for k := 0; k < n; k++ {
+		if semester = previousSemester(semester); semester == "" {
+			break
+		}
+		if db := strings.Replace(semester, " ", "-", 1); p.dbClient.DatabaseHasConfig(ctx, db) {
+			workspaces = append(workspaces, db)
+		}
+	}
+	return workspaces
+}
+
+// CsvRoomUtilizationBytes builds the per room and start time CSV of the room
+// utilization.
+func (p *Plexams) CsvRoomUtilizationBytes(ctx context.Context) ([]byte, error) {
+	stat, err := p.RoomUtilization(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	b, err := csvutil.Marshal(csvgen.RoomUtilizationRows(stat.Cells))
+	if err != nil {
+		log.Error().Err(err).Msg("error when marshaling to csv")
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,101 @@
+package roomcalc
+
+import (
+	"sort"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// Occupancy is a time range [From, Until) of a room: taken (by an exam incl. its
+// buffers, a block, a foreign booking) or covered (by our approved request/booking).
+type Occupancy struct {
+	Room        string
+	From, Until time.Time
+	Reason      string
+}
+
+func (o Occupancy) overlaps(from, until time.Time) bool {
+	return o.From.Before(until) && o.Until.After(from)
+}
+
+// Conflicts returns the occupancies of room that overlap [from, until), earliest first.
+func Conflicts(room string, taken []Occupancy, from, until time.Time) []Occupancy {
+	var out []Occupancy
+	for _, o := range taken {
+		if o.Room == room && o.overlaps(from, until) {
+			out = append(out, o)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].From.Before(out[j].From) })
+	return out
+}
+
+// Covering returns the covers of room that together span all of [from, until)
+// (back-to-back ones count as one), or nil when there is a gap.
+func Covering(room string, covers []Occupancy, from, until time.Time) []Occupancy {
+	own := Conflicts(room, covers, from, until)
+	reached := from
+	var used []Occupancy
+	for _, c := range own {
+		if c.From.After(reached) {
+			return nil
+		}
+		if c.Until.After(reached) {
+			reached = c.Until
+			used = append(used, c)
+		}
+		if !reached.Before(until) {
+			return used
+		}
+	}
+	return nil
+}
+
+// FreeRooms returns the active rooms with at least seats seats and all required tags
+// that are not taken in [from, until). Own rooms are FREE; rooms that need a request
+// are BOOKED when covers span the range, else REQUESTABLE. Sorted by status, then
+// smallest room first, then by name.
+func FreeRooms(rooms []*model.Room, from, until time.Time, seats int, required Tags, taken, covers []Occupancy) []*model.FreeRoom {
+	out := make([]*model.FreeRoom, 0)
+	for _, r := range rooms {
+		if r.Deactivated || r.Seats < seats || len(RoomTags(r).Missing(required)) > 0 {
+			continue
+		}
+		if len(Conflicts(r.Name, taken, from, until)) > 0 {
+			continue
+		}
+		free := &model.FreeRoom{Room: r, Status: model.FreeRoomStatusFree}
+		if r.RequestWith != model.RoomRequestTypeNone {
+			free.Status = model.FreeRoomStatusRequestable
+			if cover := Covering(r.Name, covers, from, until); cover != nil {
+				free.Status = model.FreeRoomStatusBooked
+				reason := cover[0].Reason
+				for _, c := range cover[1:] {
+					reason += ", " + c.Reason
+				}
+				free.CoveredBy = &reason
+			}
+		}
+		out = append(out, free)
+	}
+	rank := map[model.FreeRoomStatus]int{
+		model.FreeRoomStatusFree: 0, model.FreeRoomStatusBooked: 1, model.FreeRoomStatusRequestable: 2,
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if rank[out[i].Status] != rank[out[j].Status] {
+			return rank[out[i].Status] < rank[out[j].Status]
+		}
+		if out[i].Room.Seats != out[j].Room.Seats {
+			return out[i].Room.Seats < out[j].Room.Seats
+		}
+		return out[i].Room.Name < out[j].Room.Name
+	})
+	return out
+}
+
+// ParseRequiredTags parses required tags given as "name" / "name:minimum" (built-in
+// tags like exahm or lab included).
+func ParseRequiredTags(tags []string) (Tags, error) {
+	return parseTags(tags)
+}
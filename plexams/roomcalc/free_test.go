@@ -0,0 +1,73 @@
+package roomcalc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+func TestFreeRooms(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2026, 7, 20, h, m, 0, 0, time.Local) }
+	rooms := []*model.Room{
+		{Name: "A", Seats: 30, RequestWith: model.RoomRequestTypeNone},
+		{Name: "B", Seats: 20, RequestWith: model.RoomRequestTypeNone, Tags: []string{"pc:20"}},
+		{Name: "C", Seats: 60, RequestWith: model.RoomRequestTypeManagement},
+		{Name: "D", Seats: 40, RequestWith: model.RoomRequestTypeAnny},
+		{Name: "E", Seats: 50, RequestWith: model.RoomRequestTypeNone, Deactivated: true},
+		{Name: "F", Seats: 10, RequestWith: model.RoomRequestTypeNone},
+	}
+	taken := []Occupancy{
+		{Room: "A", From: at(8, 15), Until: at(10, 15), Reason: "Prüfung 1"}, // ends before
+		{Room: "F", From: at(11, 45), Until: at(13, 0), Reason: "gesperrt"},  // overlaps the end
+	}
+	covers := []Occupancy{
+		{Room: "D", From: at(8, 0), Until: at(11, 0), Reason: "Anny-Buchung 08:00–11:00"},
+		{Room: "D", From: at(11, 0), Until: at(14, 0), Reason: "Anny-Buchung 11:00–14:00"},
+	}
+
+	got := FreeRooms(rooms, at(10, 15), at(12, 0), 10, nil, taken, covers)
+	want := []struct {
+		name   string
+		status model.FreeRoomStatus
+	}{
+		{"B", model.FreeRoomStatusFree}, {"A", model.FreeRoomStatusFree},
+		{"D", model.FreeRoomStatusBooked}, {"C", model.FreeRoomStatusRequestable},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d rooms, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Room.Name != w.name || got[i].Status != w.status {
+			t.Errorf("got[%d] = %s %s, want %s %s", i, got[i].Room.Name, got[i].Status, w.name, w.status)
+		}
+	}
+	if got[2].CoveredBy == nil || *got[2].CoveredBy != "Anny-Buchung 08:00–11:00, Anny-Buchung 11:00–14:00" {
+		t.Errorf("coveredBy = %v", got[2].CoveredBy)
+	}
+
+	pc, err := ParseRequiredTags([]string{"pc:15"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FreeRooms(rooms, at(10, 15), at(12, 0), 0, pc, taken, covers); len(got) != 1 || got[0].Room.Name != "B" {
+		t.Errorf("with pc:15 want only B, got %d rooms", len(got))
+	}
+}
+
+func TestCovering(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2026, 7, 20, h, 0, 0, 0, time.Local) }
+	covers := []Occupancy{
+		{Room: "D", From: at(8), Until: at(10)},
+		{Room: "D", From: at(11), Until: at(12)},
+	}
+	if Covering("D", covers, at(8), at(10)) == nil {
+		t.Errorf("exact cover not found")
+	}
+	if Covering("D", covers, at(9), at(12)) != nil {
+		t.Errorf("gap 10–11 must not count as covered")
+	}
+	if Covering("X", covers, at(8), at(9)) != nil {
+		t.Errorf("other room must not cover")
+	}
+}
@@ -1,7 +1,7 @@
 // Package roomcalc holds the pure room-seat math of the room preparation: whether a
-// room satisfies an exam's room constraints (EXaHM/SEB/Lab/socket/allowed-rooms
-// feature matching), the required free-seat buffer for an exam, and the free seats an
-// exam currently has across its rooms. All functions are I/O-free over graph/model
+// room satisfies an exam's room constraints (feature tags incl. the built-in
+// EXaHM/SEB/Lab/socket ones, allowed rooms), the required free-seat buffer for an
+// exam, and the free seats an exam currently has across its rooms. All functions are I/O-free over graph/model
 // types; the stateful room allocation and DB access stay in the plexams package.
 package roomcalc
 
@@ -54,44 +54,35 @@ func ExamFreeSeats(roomInfo map[string]*model.Room, examRooms []*model.PlannedRo
 }
 
 // SatisfiesConstraints reports whether a room may host an exam with the given room
-// constraints. A room with a special feature (EXaHM / Lab / SEB) is only used for an
-// exam that requires at least one feature the room actually has; never for one that
-// requires none of them. EXaHM and SEB are compatible: an EXaHM room may also host SEB
-// exams (the T-building EXaHM rooms run SEB too). A Lab room only serves Lab exams (or
-// SEB/EXaHM if it has those). An all-false RoomConstraints object (present but nothing
-// required) must not let such a room slip through.
+// constraints, matching the room's tags against the exam's required tags (see
+// RoomTags/RequiredTags). A room with a special feature (EXaHM / Lab / SEB) is only used
+// for an exam that requires at least one feature the room actually has; never for one
+// that requires none of them. EXaHM and SEB are compatible: an EXaHM room may also host
+// SEB exams (the T-building EXaHM rooms run SEB too). A Lab room only serves Lab exams
+// (or SEB/EXaHM if it has those). An all-false RoomConstraints object (present but
+// nothing required) must not let such a room slip through.
 func SatisfiesConstraints(room *model.Room, constraints *model.Constraints) bool {
 	var rc *model.RoomConstraints
 	if constraints != nil {
 		rc = constraints.RoomConstraints
 	}
+	have, need := RoomTags(room), RequiredTags(rc)
 
-	if room.Exahm || room.Lab || room.Seb {
-		needsFeature := rc != nil && ((rc.Exahm && room.Exahm) ||
-			(rc.Seb && (room.Seb || room.Exahm)) ||
-			(rc.Lab && room.Lab))
+	if have.exclusive() {
+		needsFeature := false
+		for _, name := range exclusiveTags {
+			if _, required := need[name]; required && have.Has(name, 0) {
+				needsFeature = true
+			}
+		}
 		if !needsFeature {
 			return false
 		}
 	}
-
-	if rc == nil {
-		// room without constraints should be no lab!
-		return !room.Exahm && !room.Lab && !room.Seb
-	}
-	if rc.Exahm && !room.Exahm {
-		return false
-	}
-	if rc.Lab && !room.Lab {
-		return false
-	}
-	if rc.PlacesWithSocket && !room.PlacesWithSocket {
-		return false
-	}
-	if rc.Seb && !room.Seb && !room.Exahm { // a SEB exam fits a SEB or an EXaHM room
+	if len(have.Missing(need)) > 0 {
 		return false
 	}
-	if rc.AllowedRooms != nil && !set.NewSet(rc.AllowedRooms...).Contains(room.Name) {
+	if rc != nil && rc.AllowedRooms != nil && !set.NewSet(rc.AllowedRooms...).Contains(room.Name) {
 		return false
 	}
 
@@ -59,6 +59,8 @@ func TestSatisfiesConstraints(t *testing.T) {
 	seb := &model.Room{Name: "S1", Seb: true}
 	lab := &model.Room{Name: "L1", Lab: true}
 	socket := &model.Room{Name: "P1", PlacesWithSocket: true}
+	pool := &model.Room{Name: "R3", Seats: 40, Tags: []string{"pc:30", "projector"}}
+	pcLab := &model.Room{Name: "L2", Lab: true, Tags: []string{"pc:20"}}
 
 	tests := []struct {
 		name string
@@ -87,6 +89,16 @@ func TestSatisfiesConstraints(t *testing.T) {
 		{"exahm exam needs exahm room", plain, constraints(&model.RoomConstraints{Exahm: true}), false},
 		{"lab exam needs lab room", plain, constraints(&model.RoomConstraints{Lab: true}), false},
 
+		// free-form tags
+		{"tagged room, no constraints", pool, nil, true},
+		{"tagged room for pc exam", pool, constraints(&model.RoomConstraints{RequiredTags: []string{"pc"}}), true},
+		{"tagged room meets pc minimum", pool, constraints(&model.RoomConstraints{RequiredTags: []string{"pc:30", "projector"}}), true},
+		{"tagged room below pc minimum", pool, constraints(&model.RoomConstraints{RequiredTags: []string{"pc:31"}}), false},
+		{"plain room for pc exam", plain, constraints(&model.RoomConstraints{RequiredTags: []string{"pc"}}), false},
+		{"lab with pcs for lab+pc exam", pcLab, constraints(&model.RoomConstraints{Lab: true, RequiredTags: []string{"pc"}}), true},
+		{"lab with pcs for pc-only exam", pcLab, constraints(&model.RoomConstraints{RequiredTags: []string{"pc"}}), false},
+		{"lab has sockets", lab, constraints(&model.RoomConstraints{Lab: true, PlacesWithSocket: true}), true},
+
 		// allowed-rooms whitelist
 		{"allowed rooms includes room", plain, constraints(&model.RoomConstraints{AllowedRooms: []string{"R1", "R2"}}), true},
 		{"allowed rooms excludes room", plain, constraints(&model.RoomConstraints{AllowedRooms: []string{"R2"}}), false},
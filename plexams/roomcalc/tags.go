@@ -0,0 +1,202 @@
+package roomcalc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// Room feature tags: a room carries free-form tags ("projector", "wheelchair-access")
+// optionally with a quantity ("pc:30"), an exam requires tags ("pc", "pc:25"). The
+// room booleans are built-in tags, so rooms and constraints that only use them match
+// like tagged ones without any migration.
+const (
+	TagExahm    = "exahm"
+	TagSeb      = "seb"
+	TagLab      = "lab"
+	TagHandicap = "handicap"
+	TagSocket   = "socket"
+)
+
+// BuiltinTags are the tags derived from the room booleans; they cannot be set as
+// free-form tags.
+var BuiltinTags = []string{TagExahm, TagHandicap, TagLab, TagSeb, TagSocket}
+
+// implied lists the tags a built-in tag also satisfies: an EXaHM room runs SEB, a lab
+// has sockets at its places.
+var implied = map[string][]string{
+	TagExahm: {TagSeb},
+	TagLab:   {TagSocket},
+}
+
+// exclusiveTags keep a room for the exams that need them (see SatisfiesConstraints).
+var exclusiveTags = []string{TagExahm, TagLab, TagSeb}
+
+// Tags maps a tag name to its quantity (0 = no quantity given).
+type Tags map[string]int
+
+// ParseTag splits "name" or "name:quantity" into a lower-case name and the quantity
+// (0 without one). Names consist of letters, digits, '-' and '_'; quantities are
+// positive.
+func ParseTag(tag string) (string, int, error) {
+	name, qty, hasQty := strings.Cut(strings.TrimSpace(tag), ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", 0, fmt.Errorf("empty tag %q", tag)
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return "", 0, fmt.Errorf("invalid character %q in tag %q", c, tag)
+		}
+	}
+	if !hasQty {
+		return name, 0, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(qty))
+	if err != nil || n < 1 {
+		return "", 0, fmt.Errorf("invalid quantity in tag %q", tag)
+	}
+	return name, n, nil
+}
+
+// NormalizeTags parses free-form tags and returns them in canonical form (lower
+// case, sorted, one entry per name). Built-in tags are rejected, the room and
+// constraint booleans set those.
+func NormalizeTags(tags []string) ([]string, error) {
+	parsed, err := parseTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range BuiltinTags {
+		if _, ok := parsed[b]; ok {
+			return nil, fmt.Errorf("tag %s is built in, use the %s flag instead", b, b)
+		}
+	}
+	if len(parsed) == 0 {
+		return nil, nil
+	}
+	return parsed.Strings(), nil
+}
+
+func parseTags(tags []string) (Tags, error) {
+	out := make(Tags, len(tags))
+	for _, t := range tags {
+		if strings.TrimSpace(t) == "" {
+			continue
+		}
+		name, qty, err := ParseTag(t)
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := out[name]; ok && prev != qty {
+			return nil, fmt.Errorf("tag %s given twice with different quantities", name)
+		}
+		out[name] = qty
+	}
+	return out, nil
+}
+
+// RoomTags returns all tags of a room: the built-in ones from its booleans plus its
+// free-form tags (invalid free-form tags are skipped; they are rejected on input).
+func RoomTags(room *model.Room) Tags {
+	tags := make(Tags)
+	if room == nil {
+		return tags
+	}
+	for _, t := range room.Tags {
+		if name, qty, err := ParseTag(t); err == nil {
+			tags[name] = qty
+		}
+	}
+	setIf(tags, TagExahm, room.Exahm)
+	setIf(tags, TagSeb, room.Seb)
+	setIf(tags, TagLab, room.Lab)
+	setIf(tags, TagHandicap, room.Handicap)
+	setIf(tags, TagSocket, room.PlacesWithSocket)
+	return tags
+}
+
+// RequiredTags returns the tags every room of an exam must have: the built-in ones
+// from the constraint booleans plus the free-form required tags (quantity = minimum).
+func RequiredTags(rc *model.RoomConstraints) Tags {
+	tags := make(Tags)
+	if rc == nil {
+		return tags
+	}
+	for _, t := range rc.RequiredTags {
+		if name, qty, err := ParseTag(t); err == nil {
+			tags[name] = qty
+		}
+	}
+	setIf(tags, TagExahm, rc.Exahm)
+	setIf(tags, TagSeb, rc.Seb)
+	setIf(tags, TagLab, rc.Lab)
+	setIf(tags, TagSocket, rc.PlacesWithSocket)
+	return tags
+}
+
+func setIf(tags Tags, name string, ok bool) {
+	if ok {
+		tags[name] = 0
+	}
+}
+
+// Has reports whether the tags contain name, directly or implied by a built-in tag.
+// A minimum (atLeast > 0) needs the tag itself with at least that quantity.
+func (t Tags) Has(name string, atLeast int) bool {
+	if qty, ok := t[name]; ok && (atLeast == 0 || qty >= atLeast) {
+		return true
+	}
+	for have, implies := range implied {
+		if _, ok := t[have]; !ok {
+			continue
+		}
+		for _, i := range implies {
+			if i == name && atLeast == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Missing returns the required tags the room tags do not satisfy, sorted.
+func (t Tags) Missing(required Tags) []string {
+	var missing []string
+	for name, atLeast := range required {
+		if !t.Has(name, atLeast) {
+			missing = append(missing, tagString(name, atLeast))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Strings returns the tags as sorted "name" / "name:quantity" strings.
+func (t Tags) Strings() []string {
+	out := make([]string, 0, len(t))
+	for name, qty := range t {
+		out = append(out, tagString(name, qty))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func tagString(name string, qty int) string {
+	if qty == 0 {
+		return name
+	}
+	return name + ":" + strconv.Itoa(qty)
+}
+
+func (t Tags) exclusive() bool {
+	for _, name := range exclusiveTags {
+		if _, ok := t[name]; ok {
+			return true
+		}
+	}
+	return false
+}
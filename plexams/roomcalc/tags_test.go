@@ -0,0 +1,68 @@
+package roomcalc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+func TestParseTag(t *testing.T) {
+	for in, want := range map[string]struct {
+		name string
+		qty  int
+		ok   bool
+	}{
+		"projector":         {"projector", 0, true},
+		" PC:30 ":           {"pc", 30, true},
+		"wheelchair-access": {"wheelchair-access", 0, true},
+		"pc: 5":             {"pc", 5, true},
+		"":                  {"", 0, false},
+		"pc:0":              {"", 0, false},
+		"pc:x":              {"", 0, false},
+		"two words":         {"", 0, false},
+		":3":                {"", 0, false},
+		"smart_board:1":     {"smart_board", 1, true},
+	} {
+		name, qty, err := ParseTag(in)
+		if (err == nil) != want.ok || name != want.name || qty != want.qty {
+			t.Errorf("ParseTag(%q) = %q, %d, %v; want %q, %d, ok=%v", in, name, qty, err, want.name, want.qty, want.ok)
+		}
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got, err := NormalizeTags([]string{"Projector", "pc:30", "", "projector"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"pc:30", "projector"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeTags = %v, want %v", got, want)
+	}
+	if got, err := NormalizeTags(nil); err != nil || got != nil {
+		t.Errorf("NormalizeTags(nil) = %v, %v; want nil, nil", got, err)
+	}
+	for _, bad := range [][]string{{"exahm"}, {"Socket"}, {"pc:30", "pc:20"}, {"pc:-1"}} {
+		if _, err := NormalizeTags(bad); err == nil {
+			t.Errorf("NormalizeTags(%v): want error", bad)
+		}
+	}
+}
+
+func TestRoomAndRequiredTags(t *testing.T) {
+	room := &model.Room{Exahm: true, Handicap: true, PlacesWithSocket: true, Tags: []string{"pc:24"}}
+	if got, want := RoomTags(room).Strings(), []string{"exahm", "handicap", "pc:24", "socket"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RoomTags = %v, want %v", got, want)
+	}
+	rc := &model.RoomConstraints{Seb: true, RequiredTags: []string{"pc:20", "projector"}}
+	if got, want := RequiredTags(rc).Strings(), []string{"pc:20", "projector", "seb"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RequiredTags = %v, want %v", got, want)
+	}
+	// seb is implied by exahm, projector is missing
+	if got, want := RoomTags(room).Missing(RequiredTags(rc)), []string{"projector"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Missing = %v, want %v", got, want)
+	}
+	if len(RoomTags(nil)) != 0 || len(RequiredTags(nil)) != 0 {
+		t.Errorf("nil room/constraints must have no tags")
+	}
+}
@@ -0,0 +1,335 @@
+// Package roomexchange is the machine-readable room-request exchange with the
+// Gebäudemanagement: the active room requests as a table (CSV/XLSX) with an answer
+// column, one ICS invite per room and time, and the parsing of the returned,
+// annotated table into approvals/rejections with a diff against the stored requests.
+// It is I/O-free; storing and logging the changes stays in the plexams package.
+//
+// A row is identified by room + Prüfungsbeginn (the key of a room request), so the
+// Gebäudemanagement may reorder, filter or drop rows and add their own columns.
+package roomexchange
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	ical "github.com/arran4/golang-ical"
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/tableio"
+	"github.com/xuri/excelize/v2"
+)
+
+// Column headers of the exchange table.
+const (
+	ColRoom      = "Raum"
+	ColDate      = "Datum"
+	ColFrom      = "von"
+	ColUntil     = "bis"
+	ColExamStart = "Prüfungsbeginn"
+	ColStatus    = "Status"
+	ColAnswer    = "Genehmigt"
+	ColNote      = "Bemerkung"
+)
+
+// Header is the column order of the exchange table. The answer and note columns are
+// left empty for the Gebäudemanagement to fill in ("ja" / "nein").
+var Header = []string{ColRoom, ColDate, ColFrom, ColUntil, ColExamStart, ColStatus, ColAnswer, ColNote}
+
+// ExamStartLayout formats the Prüfungsbeginn key column.
+const ExamStartLayout = "02.01.2006 15:04"
+
+// examStartLayouts are accepted when reading the key back (spreadsheet programs like
+// to reformat dates).
+var examStartLayouts = []string{ExamStartLayout, "2.1.2006 15:04", "02.01.06 15:04", "2.1.06 15:04", "2006-01-02 15:04", "2006-01-02T15:04:05Z07:00"}
+
+// Active returns the active room requests with a start time, sorted by room and start.
+func Active(requests []*model.RoomRequest) []*model.RoomRequest {
+	out := make([]*model.RoomRequest, 0, len(requests))
+	for _, r := range requests {
+		if r.Active && r.Starttime != nil {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Room != out[j].Room {
+			return out[i].Room < out[j].Room
+		}
+		return out[i].Starttime.Before(*out[j].Starttime)
+	})
+	return out
+}
+
+// Rows builds the table rows (without header) of the active requests.
+func Rows(requests []*model.RoomRequest) [][]string {
+	active := Active(requests)
+	rows := make([][]string, 0, len(active))
+	for _, r := range active {
+		status := "angefragt"
+		if r.Approved {
+			status = "genehmigt"
+		}
+		rows = append(rows, []string{
+			r.Room, r.From.Local().Format("02.01.2006"), r.From.Local().Format("15:04"), r.Until.Local().Format("15:04"),
+			r.Starttime.Local().Format(ExamStartLayout), status, "", "",
+		})
+	}
+	return rows
+}
+
+// CSV renders the exchange table as CSV (UTF-8 with BOM, so Excel shows umlauts).
+func CSV(requests []*model.RoomRequest) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\xEF\xBB\xBF")
+	w := csv.NewWriter(&buf)
+	if err := w.Write(Header); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(Rows(requests)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// XLSX renders the exchange table as an Excel workbook. All cells are text (so the key
+// column survives a round trip) and the answer column offers "ja" / "nein".
+func XLSX(requests []*model.RoomRequest) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close() //nolint:errcheck
+	sheet := "Raumanfragen"
+	if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+		return nil, err
+	}
+	rows := append([][]string{Header}, Rows(requests)...)
+	for i, row := range rows {
+		for j, v := range row {
+			c, err := excelize.CoordinatesToCellName(j+1, i+1)
+			if err != nil {
+				return nil, err
+			}
+			if err := f.SetCellStr(sheet, c, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+	last, _ := excelize.CoordinatesToCellName(len(Header), 1)
+	if err := f.SetCellStyle(sheet, "A1", last, bold); err != nil {
+		return nil, err
+	}
+	if err := f.SetColWidth(sheet, "A", "H", 16); err != nil {
+		return nil, err
+	}
+	if len(rows) > 1 {
+		dv := excelize.NewDataValidation(true)
+		dv.Sqref = fmt.Sprintf("G2:G%d", len(rows))
+		if err := dv.SetDropList([]string{"ja", "nein"}); err != nil {
+			return nil, err
+		}
+		if err := f.AddDataValidation(sheet, dv); err != nil {
+			return nil, err
+		}
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ICS renders one calendar per room with an invite (METHOD:REQUEST) per requested time
+// range, keyed by the room name. organizer and attendee are email addresses (attendee
+// may be empty), stamp is the DTSTAMP of the invites.
+func ICS(semester, organizer, attendee string, requests []*model.RoomRequest, stamp time.Time) map[string][]byte {
+	cals := make(map[string]*ical.Calendar)
+	for _, r := range Active(requests) {
+		cal := cals[r.Room]
+		if cal == nil {
+			cal = ical.NewCalendar()
+			cal.SetMethod(ical.MethodRequest)
+			cal.SetProductId(fmt.Sprintf("-//Plexams Raumanfrage//%s", semester))
+			cals[r.Room] = cal
+		}
+		ev := cal.AddEvent(UID(semester, r))
+		ev.SetSummary(fmt.Sprintf("Prüfung FK07: Raum %s", r.Room))
+		ev.SetLocation(r.Room)
+		ev.SetStartAt(r.From)
+		ev.SetEndAt(r.Until)
+		ev.SetDtStampTime(stamp)
+		ev.SetDescription(fmt.Sprintf("Raumanfrage der Prüfungsplanung FK07 (%s), %s: %s, Prüfungsbeginn %s Uhr.",
+			semester, ColExamStart, r.Starttime.Local().Format(ExamStartLayout), r.Starttime.Local().Format("15:04")))
+		if organizer != "" {
+			ev.SetOrganizer("mailto:" + organizer)
+		}
+		if attendee != "" {
+			ev.AddAttendee("mailto:"+attendee, ical.CalendarUserTypeRoom, ical.ParticipationStatusNeedsAction, ical.WithRSVP(true))
+		}
+	}
+	out := make(map[string][]byte, len(cals))
+	for room, cal := range cals {
+		out[room] = []byte(cal.Serialize())
+	}
+	return out
+}
+
+// UID is the stable ICS UID of a room request, so a re-sent invite updates the event.
+func UID(semester string, r *model.RoomRequest) string {
+	return fmt.Sprintf("roomrequest-%s-%s-%s@plexams",
+		strings.ReplaceAll(semester, " ", "-"), strings.ReplaceAll(r.Room, " ", ""), r.Starttime.UTC().Format("20060102T1504"))
+}
+
+// Answer is one answered row of the returned table.
+type Answer struct {
+	Line      int
+	Room      string
+	ExamStart time.Time
+	Approved  bool
+	Note      string
+}
+
+// Parse reads the returned table (XLSX or CSV with "," or ";") and returns its
+// answered rows. Rows without an answer are skipped; unreadable keys or answers are
+// returned as problems (with the line number) instead of failing the whole file.
+func Parse(data []byte) (answers []Answer, unanswered int, problems []string, err error) {
+	rows, err := tableio.Read(data)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if len(rows) == 0 {
+		return nil, 0, nil, fmt.Errorf("file is empty")
+	}
+	col := make(map[string]int)
+	for i, h := range rows[0] {
+		col[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\xEF\xBB\xBF")))] = i
+	}
+	for _, need := range []string{ColRoom, ColExamStart, ColAnswer} {
+		if _, ok := col[strings.ToLower(need)]; !ok {
+			return nil, 0, nil, fmt.Errorf("column %q missing", need)
+		}
+	}
+	get := func(row []string, name string) string {
+		i, ok := col[strings.ToLower(name)]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+	for n, row := range rows[1:] {
+		line := n + 2
+		room, start, answer := get(row, ColRoom), get(row, ColExamStart), get(row, ColAnswer)
+		if room == "" && start == "" && answer == "" {
+			continue
+		}
+		if answer == "" {
+			unanswered++
+			continue
+		}
+		approved, ok := parseAnswer(answer)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("line %d: cannot read answer %q (ja/nein)", line, answer))
+			continue
+		}
+		t, ok := parseExamStart(start)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("line %d: cannot read %s %q", line, ColExamStart, start))
+			continue
+		}
+		answers = append(answers, Answer{Line: line, Room: room, ExamStart: t, Approved: approved, Note: get(row, ColNote)})
+	}
+	return answers, unanswered, problems, nil
+}
+
+func parseAnswer(s string) (approved, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "ja", "j", "yes", "y", "x", "1", "true", "genehmigt", "ok":
+		return true, true
+	case "nein", "n", "no", "0", "false", "abgelehnt", "-":
+		return false, true
+	}
+	return false, false
+}
+
+func parseExamStart(s string) (time.Time, bool) {
+	for _, layout := range examStartLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Change is one room request the returned table changes.
+type Change struct {
+	Room      string    `json:"room"`
+	Starttime time.Time `json:"starttime"`
+	From      time.Time `json:"from"`
+	Until     time.Time `json:"until"`
+	// Approved/Active before → after; a rejection also deactivates the request, so the
+	// room is no longer used for planning.
+	ApprovedBefore bool   `json:"approvedBefore"`
+	Approved       bool   `json:"approved"`
+	ActiveBefore   bool   `json:"activeBefore"`
+	Active         bool   `json:"active"`
+	Note           string `json:"note,omitempty"`
+}
+
+// Import is the diff preview (and, once applied, the outcome) of a returned table.
+type Import struct {
+	Applied    bool     `json:"applied"`
+	Changes    []Change `json:"changes"`
+	Unchanged  int      `json:"unchanged"`
+	Unanswered int      `json:"unanswered"`
+	// Problems are unreadable or unknown rows; an import with problems is not applied.
+	Problems []string `json:"problems"`
+}
+
+// Diff matches the answers to the stored requests (key: room + start time). "ja"
+// approves a request, "nein" rejects it: not approved and inactive.
+func Diff(requests []*model.RoomRequest, answers []Answer, unanswered int, problems []string) *Import {
+	byKey := make(map[string]*model.RoomRequest, len(requests))
+	for _, r := range requests {
+		if r.Starttime != nil {
+			byKey[key(r.Room, *r.Starttime)] = r
+		}
+	}
+	out := &Import{Changes: make([]Change, 0), Unanswered: unanswered, Problems: append(make([]string, 0), problems...)}
+	seen := make(map[string]int)
+	for _, a := range answers {
+		k := key(a.Room, a.ExamStart)
+		r := byKey[k]
+		if r == nil {
+			out.Problems = append(out.Problems, fmt.Sprintf("line %d: no room request for %s at %s", a.Line, a.Room, a.ExamStart.Format(ExamStartLayout)))
+			continue
+		}
+		if prev, ok := seen[k]; ok {
+			out.Problems = append(out.Problems, fmt.Sprintf("line %d: %s at %s already answered in line %d", a.Line, a.Room, a.ExamStart.Format(ExamStartLayout), prev))
+			continue
+		}
+		seen[k] = a.Line
+		active := r.Active && a.Approved
+		if r.Approved == a.Approved && r.Active == active {
+			out.Unchanged++
+			continue
+		}
+		out.Changes = append(out.Changes, Change{
+			Room: r.Room, Starttime: *r.Starttime, From: r.From, Until: r.Until,
+			ApprovedBefore: r.Approved, Approved: a.Approved, ActiveBefore: r.Active, Active: active, Note: a.Note,
+		})
+	}
+	sort.Slice(out.Changes, func(i, j int) bool {
+		if out.Changes[i].Room != out.Changes[j].Room {
+			return out.Changes[i].Room < out.Changes[j].Room
+		}
+		return out.Changes[i].Starttime.Before(out.Changes[j].Starttime)
+	})
+	return out
+}
+
+func key(room string, start time.Time) string {
+	return strings.ToUpper(strings.ReplaceAll(room, " ", "")) + "@" + start.Local().Format(ExamStartLayout)
+}
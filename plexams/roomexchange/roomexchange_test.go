@@ -0,0 +1,113 @@
+package roomexchange
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/xuri/excelize/v2"
+)
+
+func sampleRequests() []*model.RoomRequest {
+	s1 := time.Date(2026, 7, 13, 8, 30, 0, 0, time.Local)
+	s2 := time.Date(2026, 7, 14, 10, 30, 0, 0, time.Local)
+	return []*model.RoomRequest{
+		{Room: "R3.016", Starttime: &s2, From: s2.Add(-15 * time.Minute), Until: s2.Add(105 * time.Minute), Active: true},
+		{Room: "R1.049", Starttime: &s1, From: s1.Add(-15 * time.Minute), Until: s1.Add(105 * time.Minute), Active: true, Approved: true},
+		{Room: "R1.049", Starttime: &s2, From: s2, Until: s2.Add(time.Hour), Active: false}, // not sent
+	}
+}
+
+func TestRows(t *testing.T) {
+	rows := Rows(sampleRequests())
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want the 2 active requests", len(rows))
+	}
+	want := []string{"R1.049", "13.07.2026", "08:15", "10:15", "13.07.2026 08:30", "genehmigt", "", ""}
+	if strings.Join(rows[0], "|") != strings.Join(want, "|") {
+		t.Errorf("rows[0] = %v, want %v", rows[0], want)
+	}
+}
+
+func TestXLSXRoundTrip(t *testing.T) {
+	requests := sampleRequests()
+	data, err := XLSX(requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the Gebäudemanagement answers the second row and adds a note
+	f, err := excelize.OpenReader(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for cell, v := range map[string]string{"G3": "nein", "H3": "belegt durch Fakultät 03"} {
+		if err := f.SetCellStr("Raumanfragen", cell, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers, unanswered, problems, err := Parse(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(answers) != 1 || unanswered != 1 || len(problems) != 0 {
+		t.Fatalf("answers=%v unanswered=%d problems=%v", answers, unanswered, problems)
+	}
+	got := Diff(requests, answers, unanswered, problems)
+	if len(got.Changes) != 1 {
+		t.Fatalf("changes = %+v, want 1", got.Changes)
+	}
+	c := got.Changes[0]
+	if c.Room != "R3.016" || c.Approved || c.Active || !c.ActiveBefore || c.Note != "belegt durch Fakultät 03" {
+		t.Errorf("change = %+v, want R3.016 rejected and deactivated", c)
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	// German Excel: semicolons, reformatted date, own extra column, reordered rows
+	csv := "\xEF\xBB\xBFRaum;Prüfungsbeginn;Genehmigt;Bemerkung;Intern\n" +
+		"R3.016;14.07.2026 10:30;ja;;x\n" +
+		"R1.049;13.7.2026 08:30;Ja;;\n" +
+		"R1.049;15.07.2026 08:30;ja;;\n" +
+		"R9.999;kein Datum;ja;;\n" +
+		"R3.016;14.07.2026 10:30;vielleicht;;\n"
+	answers, unanswered, problems, err := Parse([]byte(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(answers) != 3 || unanswered != 0 || len(problems) != 2 {
+		t.Fatalf("answers=%d unanswered=%d problems=%v", len(answers), unanswered, problems)
+	}
+	got := Diff(sampleRequests(), answers, unanswered, problems)
+	if got.Unchanged != 1 || len(got.Changes) != 1 || !got.Changes[0].Approved || !got.Changes[0].Active {
+		t.Errorf("diff = %+v, want R3.016 approved and R1.049 unchanged", got)
+	}
+	if len(got.Problems) != 3 || !strings.Contains(got.Problems[2], "line 4: no room request") {
+		t.Errorf("problems = %v", got.Problems)
+	}
+
+	if _, _, _, err := Parse([]byte("Raum;Genehmigt\nR1;ja\n")); err == nil {
+		t.Errorf("missing key column must fail")
+	}
+}
+
+func TestICS(t *testing.T) {
+	files := ICS("2026 SS", "planer@hm.edu", "gm@hm.edu", sampleRequests(), time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC))
+	if len(files) != 2 {
+		t.Fatalf("got %d calendars, want one per room", len(files))
+	}
+	ics := string(files["R1.049"])
+	for _, want := range []string{"METHOD:REQUEST", "LOCATION:R1.049", "mailto:gm@hm.edu", "UID:roomrequest-2026-SS-R1.049-"} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("ics lacks %q:\n%s", want, ics)
+		}
+	}
+	if strings.Count(ics, "BEGIN:VEVENT") != 1 {
+		t.Errorf("inactive request must not be invited")
+	}
+}
@@ -0,0 +1,395 @@
+package roomplan
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/obcode/plexams.go/plexams/milp"
+)
+
+// MILP is the room plan as a mixed-integer linear program for an external exact solver.
+// Seats of one exam are interchangeable, so instead of a variable per seat it counts:
+// n[e][r] is the number of movable Normal seats of exam e in room r, a[k][r] is 1 when the
+// NTA-alone seat k gets room r, and u[e][r] is 1 when exam e uses room r at all.
+//
+// All hard constraints are modelled (allowed rooms, capacity, NTA-alone exclusivity, fixed
+// seats, the room turnaround pairwise per exam and the summer cooldown). The soft objective
+// covers placement, split (rooms and buildings), compaction, the per-seat room preferences (heat floor, SEB
+// avoiding EXaHM rooms, own EXaHM room as fallback) and the user-defined rules (per seat on
+// n and a, per use on u). The free-seat buffer and churn are not modelled; the imported
+// state is scored with the full registry anyway.
+type MILP struct {
+	Model *milp.Model
+
+	prob  *Problem
+	n     map[[2]int]int // (exam, room) → movable Normal seats
+	u     map[[2]int]int // (exam, room) → uses the room
+	a     map[[2]int]int // (seat, room) → NTA-alone seat in the room
+	fixed [][]int        // [exam][room] fixed seats
+}
+
+// BuildMILP builds the program for the whole problem; use OnDay to restrict it first.
+func BuildMILP(p *Problem) *MILP {
+	m := &MILP{
+		Model: milp.New("plexams room plan"),
+		prob:  p,
+		n:     make(map[[2]int]int),
+		u:     make(map[[2]int]int),
+		a:     make(map[[2]int]int),
+		fixed: make2D(len(p.Exams), len(p.Rooms)),
+	}
+	m.Model.Comments = []string{
+		"n_<exam>_<room>: movable seats of the exam in the room; a_<seat>_<room> = 1: NTA alone in the room.",
+		"Not modelled: free-seat buffer, churn.",
+	}
+
+	fixedAlone := make2D(len(p.Rooms), len(p.Slots)) // [room][slot] fixed NTA-alone seats
+	fixedUsed := make2D(len(p.Rooms), len(p.Slots))  // [room][slot] fixed seats
+	fixedLoad := make2D(len(p.Rooms), len(p.Slots))  // [room][slot] capacity the fixed seats take (see loadOf)
+	normalMovable := make([]int, len(p.Exams))
+	repr := make([]int, len(p.Exams)) // a movable Normal seat per exam, for the per-seat costs
+	for i := range repr {
+		repr[i] = -1
+	}
+	for i := range p.Seats {
+		s := &p.Seats[i]
+		if s.Fixed {
+			m.fixed[s.Exam][s.FixedRoom]++
+			fixedUsed[s.FixedRoom][p.Exams[s.Exam].Slot]++
+			fixedLoad[s.FixedRoom][p.Exams[s.Exam].Slot] += p.loadOf(i)
+			if s.Kind == NTAAlone {
+				fixedAlone[s.FixedRoom][p.Exams[s.Exam].Slot]++
+			}
+			continue
+		}
+		if s.Kind == Normal {
+			normalMovable[s.Exam]++
+			if repr[s.Exam] < 0 {
+				repr[s.Exam] = i
+			}
+		}
+	}
+
+	// usage variables: every allowed room plus the fixed ones
+	for e := range p.Exams {
+		rooms := make(map[int]bool)
+		for _, r := range p.Exams[e].AllowedNormal {
+			rooms[r] = true
+		}
+		for _, r := range p.Exams[e].AllowedAlone {
+			rooms[r] = true
+		}
+		for r, c := range m.fixed[e] {
+			if c > 0 {
+				rooms[r] = true
+			}
+		}
+		for _, r := range sortedKeys(rooms) {
+			u := m.Model.Binary(fmt.Sprintf("u_%d_%d", e, r))
+			m.u[[2]int{e, r}] = u
+			if c := p.ruleUseCostOf(e, r); c != 0 {
+				m.Model.Minimize(u, c)
+			}
+			if m.fixed[e][r] > 0 {
+				m.Model.Add(fmt.Sprintf("fixed_%d_%d", e, r), milp.EQ, 1, milp.T(u, 1))
+			}
+		}
+	}
+
+	// movable Normal seats
+	for e := range p.Exams {
+		s := p.Exams[e].Slot
+		if normalMovable[e] == 0 {
+			continue
+		}
+		var placed []milp.Term
+		for _, r := range p.Exams[e].AllowedNormal {
+			if fixedAlone[r][s] > 0 {
+				continue
+			}
+			cap := (p.Rooms[r].Seats*100 - fixedLoad[r][s]) / p.Exams[e].attendanceLoad()
+			if cap <= 0 {
+				continue
+			}
+			hi := min(cap, normalMovable[e])
+			n := m.Model.Integer(fmt.Sprintf("n_%d_%d", e, r), 0, float64(hi))
+			m.n[[2]int{e, r}] = n
+			placed = append(placed, milp.T(n, 1))
+			m.Model.Add(fmt.Sprintf("use_%d_%d", e, r), milp.LE, 0, milp.T(n, 1), milp.T(m.u[[2]int{e, r}], -float64(hi)))
+			i := repr[e]
+			m.Model.Minimize(n, p.heatCostOf(i, r)+p.sebAvoidCostOf(i, r)+p.ownExahmCostOf(i, r)+p.ruleSeatCostOf(i, r)-p.W.Unplaced)
+		}
+		m.Model.Add(fmt.Sprintf("seats_%d", e), milp.LE, float64(normalMovable[e]), placed...)
+		m.Model.Offset += p.W.Unplaced * float64(normalMovable[e])
+	}
+
+	// movable NTA-alone seats
+	for _, i := range p.movable {
+		if p.Seats[i].Kind != NTAAlone {
+			continue
+		}
+		e := p.Seats[i].Exam
+		s := p.Exams[e].Slot
+		var placed []milp.Term
+		for _, r := range p.Exams[e].AllowedAlone {
+			if fixedUsed[r][s] > 0 || p.Rooms[r].Seats < 1 {
+				continue
+			}
+			a := m.Model.Binary(fmt.Sprintf("a_%d_%d", i, r))
+			m.a[[2]int{i, r}] = a
+			placed = append(placed, milp.T(a, 1))
+			m.Model.Add(fmt.Sprintf("usea_%d_%d", i, r), milp.LE, 0, milp.T(a, 1), milp.T(m.u[[2]int{e, r}], -1))
+			m.Model.Minimize(a, p.sebAvoidCostOf(i, r)+p.ownExahmCostOf(i, r)+p.ruleSeatCostOf(i, r)-p.W.Unplaced)
+		}
+		m.Model.Add(fmt.Sprintf("alone_%d", i), milp.LE, 1, placed...)
+		m.Model.Offset += p.W.Unplaced
+	}
+
+	m.addCells(fixedLoad)
+	m.addTurnaround()
+	m.addSoft()
+	return m
+}
+
+// addCells adds the per-(room, slot) capacity (a Normal seat weighted by its exam's
+// expected attendance, see loadOf) and the NTA-alone exclusivity.
+func (m *MILP) addCells(fixedLoad [][]int) {
+	p := m.prob
+	for r := range p.Rooms {
+		for s := range p.Slots {
+			var normal, alone []milp.Term
+			for _, e := range p.examsInSlot[s] {
+				if v, ok := m.n[[2]int{e, r}]; ok {
+					normal = append(normal, milp.T(v, float64(p.Exams[e].attendanceLoad())/100))
+				}
+				for _, i := range p.seatsOfExam[e] {
+					if v, ok := m.a[[2]int{i, r}]; ok {
+						alone = append(alone, milp.T(v, 1))
+					}
+				}
+			}
+			if len(normal)+len(alone) == 0 {
+				continue
+			}
+			capacity := float64(p.Rooms[r].Seats*100-fixedLoad[r][s]) / 100
+			m.Model.Add(fmt.Sprintf("cap_%d_%d", r, s), milp.LE, capacity, append(append([]milp.Term{}, normal...), alone...)...)
+			// an NTA alone in the cell: no other seat (others + seats·a ≤ seats)
+			for k, t := range alone {
+				terms := []milp.Term{milp.T(t.Var, capacity)}
+				terms = append(terms, normal...)
+				for j, o := range alone {
+					if j != k {
+						terms = append(terms, o)
+					}
+				}
+				m.Model.Add(fmt.Sprintf("excl_%d_%d_%d", r, s, t.Var), milp.LE, capacity, terms...)
+			}
+		}
+	}
+}
+
+// addTurnaround forbids two exams in different same-day slots from sharing a room when
+// the gap between them is shorter than the turnaround, and — in summer — an own room in
+// two directly consecutive slots. Both are pairwise on the usage variables.
+func (m *MILP) addTurnaround() {
+	p := m.prob
+	lag := p.timelag()
+	for e := range p.Exams {
+		s := p.Exams[e].Slot
+		for _, t := range p.sameDaySlots[s] {
+			d := int(p.Slots[t].Start.Sub(p.Slots[s].Start).Minutes())
+			if d < 0 {
+				continue // each pair once, from the earlier exam
+			}
+			for _, f := range p.examsInSlot[t] {
+				conflict := d < p.Exams[e].Duration+lag+p.Exams[e].PostExtra+p.Exams[f].PreExtra
+				cooldown := p.Summer && p.nextInDay[s] == t
+				if !conflict && !cooldown {
+					continue
+				}
+				for r := range p.Rooms {
+					if !conflict && !p.Rooms[r].OwnRoom {
+						continue
+					}
+					ue, ok1 := m.u[[2]int{e, r}]
+					uf, ok2 := m.u[[2]int{f, r}]
+					if ok1 && ok2 {
+						m.Model.Add(fmt.Sprintf("turn_%d_%d_%d", e, f, r), milp.LE, 1, milp.T(ue, 1), milp.T(uf, 1))
+					}
+				}
+			}
+		}
+	}
+}
+
+// addSoft adds split (rooms beyond the first per exam), split buildings (buildings beyond
+// the first per exam) and compaction (distinct rooms).
+func (m *MILP) addSoft() {
+	p := m.prob
+	used := make(map[int][]int) // room → usage variables
+	for e := range p.Exams {
+		var terms []milp.Term
+		for r := range p.Rooms {
+			if u, ok := m.u[[2]int{e, r}]; ok {
+				terms = append(terms, milp.T(u, 1))
+				used[r] = append(used[r], u)
+			}
+		}
+		if len(terms) > 1 && p.W.Split != 0 {
+			sp := m.Model.NonNegative(fmt.Sprintf("split_%d", e))
+			m.Model.Add(fmt.Sprintf("splitdef_%d", e), milp.GE, -1, append([]milp.Term{milp.T(sp, 1)}, negate(terms)...)...)
+			m.Model.Minimize(sp, p.W.Split)
+		}
+		m.addSplitBuilding(e)
+	}
+	if p.W.Compaction == 0 {
+		return
+	}
+	rooms := make([]int, 0, len(used))
+	for r := range used {
+		rooms = append(rooms, r)
+	}
+	sort.Ints(rooms)
+	for _, r := range rooms {
+		z := m.Model.Binary(fmt.Sprintf("room_%d", r))
+		for _, u := range used[r] {
+			m.Model.Add(fmt.Sprintf("roomdef_%d_%d", r, u), milp.LE, 0, milp.T(u, 1), milp.T(z, -1))
+		}
+		m.Model.Minimize(z, p.W.Compaction)
+	}
+}
+
+// addSplitBuilding adds, for an exam that may use rooms in more than one building, a binary
+// b_e_x per building x (1 when the exam uses a room there) and penalizes every building
+// beyond the first.
+func (m *MILP) addSplitBuilding(e int) {
+	p := m.prob
+	if p.W.SplitBuilding == 0 {
+		return
+	}
+	byBuilding := make(map[int][]int) // building → usage variables of the exam
+	for r := range p.Rooms {
+		if u, ok := m.u[[2]int{e, r}]; ok && p.buildingOf[r] >= 0 {
+			byBuilding[p.buildingOf[r]] = append(byBuilding[p.buildingOf[r]], u)
+		}
+	}
+	if len(byBuilding) < 2 {
+		return
+	}
+	buildings := make([]int, 0, len(byBuilding))
+	for x := range byBuilding {
+		buildings = append(buildings, x)
+	}
+	sort.Ints(buildings)
+	terms := make([]milp.Term, 0, len(buildings))
+	for _, x := range buildings {
+		b := m.Model.Binary(fmt.Sprintf("b_%d_%d", e, x))
+		for _, u := range byBuilding[x] {
+			m.Model.Add(fmt.Sprintf("bdef_%d_%d_%d", e, x, u), milp.LE, 0, milp.T(u, 1), milp.T(b, -1))
+		}
+		terms = append(terms, milp.T(b, 1))
+	}
+	sb := m.Model.NonNegative(fmt.Sprintf("splitb_%d", e))
+	m.Model.Add(fmt.Sprintf("splitbdef_%d", e), milp.GE, -1, append([]milp.Term{milp.T(sb, 1)}, negate(terms)...)...)
+	m.Model.Minimize(sb, p.W.SplitBuilding)
+}
+
+// State maps a solution back to a room plan. Seats of an exam are handed out to the rooms
+// in seat order; the caller validates the state with Registry().HardViolations.
+func (m *MILP) State(sol milp.Solution) (*State, error) {
+	p := m.prob
+	st := newState(p)
+	for e := range p.Exams {
+		var seats []int
+		for _, i := range p.seatsOfExam[e] {
+			if !p.Seats[i].Fixed && p.Seats[i].Kind == Normal {
+				seats = append(seats, i)
+			}
+		}
+		for _, r := range p.Exams[e].AllowedNormal {
+			v, ok := m.n[[2]int{e, r}]
+			if !ok {
+				continue
+			}
+			count := sol.Int(v)
+			if count > len(seats) {
+				return nil, fmt.Errorf("exam %d: %d seats in room %s, only %d left", p.Exams[e].Ancode, count, p.Rooms[r].Name, len(seats))
+			}
+			for _, i := range seats[:max(count, 0)] {
+				st.roomOf[i] = r
+			}
+			seats = seats[max(count, 0):]
+		}
+	}
+	for k, v := range m.a {
+		if !sol.On(v) {
+			continue
+		}
+		i, r := k[0], k[1]
+		if st.roomOf[i] >= 0 {
+			return nil, fmt.Errorf("NTA seat %d is placed in room %s and %s", i, p.Rooms[st.roomOf[i]].Name, p.Rooms[r].Name)
+		}
+		st.roomOf[i] = r
+	}
+	st.rebuild()
+	return st, nil
+}
+
+// OnDay returns the sub-problem of the exams on the given calendar day. Rooms are only
+// shared within a day, so a day can be planned on its own.
+func (p *Problem) OnDay(day time.Time) *Problem {
+	key := dayKey(day)
+	var exams []Exam
+	var seats []Seat
+	var onDay []int // sub-problem exam index → exam index in p
+	for e := range p.Exams {
+		ex := p.Exams[e]
+		if dayKey(p.Slots[ex.Slot].Start) != key {
+			continue
+		}
+		idx := len(exams)
+		onDay = append(onDay, e)
+		ex.allowedNormalSet, ex.allowedAloneSet = nil, nil
+		exams = append(exams, ex)
+		for _, i := range p.seatsOfExam[e] {
+			s := p.Seats[i]
+			s.Exam = idx
+			seats = append(seats, s)
+		}
+	}
+	sub := NewProblem(p.Slots, p.Rooms, exams, seats, p.W)
+	sub.Summer = p.Summer
+	sub.TimelagMin = p.TimelagMin
+	if p.rules != nil {
+		rules := make([]Rule, len(p.rules))
+		for k, r := range p.rules {
+			rules[k] = r
+			rules[k].Cost = make([][]float64, len(onDay))
+			for idx, e := range onDay {
+				if e < len(r.Cost) {
+					rules[k].Cost[idx] = r.Cost[e]
+				}
+			}
+		}
+		sub.SetRules(rules)
+	}
+	return sub
+}
+
+func sortedKeys(set map[int]bool) []int {
+	out := make([]int, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	sort.Ints(out)
+	return out
+}
+
+func negate(terms []milp.Term) []milp.Term {
+	out := make([]milp.Term, len(terms))
+	for i, t := range terms {
+		out[i] = milp.T(t.Var, -t.Coef)
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package roomplan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obcode/plexams.go/plexams/milp"
+)
+
+func TestMILPStateRoundtrip(t *testing.T) {
+	p := buildScenario(false)
+	m := BuildMILP(p)
+
+	// A split over R0/R1, B in R2, C in R0 with its NTA (seat 11) alone in R1, D in the T-room.
+	in := "n_0_0 4\nn_0_1 2\nn_1_2 3\nn_2_0 2\na_11_1 1\nn_3_3 3\n"
+	sol, err := milp.ReadSolution(strings.NewReader(in), m.Model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := m.State(sol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.UnplacedCount() != 0 {
+		t.Fatalf("expected every seat placed, %d unplaced", st.UnplacedCount())
+	}
+	if hard := p.Registry().HardViolations(st); len(hard) != 0 {
+		t.Fatalf("expected no hard violations, got %v", hard)
+	}
+	if got := st.examRooms[0]; got != 2 {
+		t.Errorf("exam A should use 2 rooms, got %d", got)
+	}
+}
+
+func TestMILPModelsNTAExclusivity(t *testing.T) {
+	p := buildScenario(false)
+	m := BuildMILP(p)
+	found := false
+	for _, c := range m.Model.Constraints {
+		if strings.HasPrefix(c.Name, "excl_") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected NTA-alone exclusivity constraints")
+	}
+
+	// C's NTA alone in R0 together with C's normal seats violates exclusivity
+	sol, err := milp.ReadSolution(strings.NewReader("n_2_0 2\na_11_0 1\n"), m.Model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := m.State(sol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Registry().HardViolations(st)) == 0 {
+		t.Error("an infeasible solution must be reported as hard violation")
+	}
+}
+
+func TestOnDayKeepsOnlyThatDay(t *testing.T) {
+	p := buildScenario(false)
+	p.Slots = append(p.Slots, Slot{Start: at(7, 9)})
+	p.Exams = append(p.Exams, Exam{Ancode: 500, Slot: 3, NormalCount: 1, AllowedNormal: []int{0}})
+	p.Seats = append(p.Seats, Seat{Exam: 4, Mtknr: "E0"})
+	p = NewProblem(p.Slots, p.Rooms, p.Exams, p.Seats, p.W)
+
+	sub := p.OnDay(at(7, 0))
+	if len(sub.Exams) != 1 || sub.Exams[0].Ancode != 500 || len(sub.Seats) != 1 || sub.Seats[0].Exam != 0 {
+		t.Fatalf("unexpected sub-problem: %d exams, %d seats", len(sub.Exams), len(sub.Seats))
+	}
+}
+
+func TestMILPModelsSplitBuilding(t *testing.T) {
+	base := buildScenario(false)
+	exams := append([]Exam(nil), base.Exams...)
+	exams[0].AllowedNormal = []int{0, 1, 2, 3} // exam A may also use the T-room
+	m := BuildMILP(NewProblem(base.Slots, base.Rooms, exams, base.Seats, base.W))
+	found := false
+	for _, c := range m.Model.Constraints {
+		if strings.HasPrefix(c.Name, "splitbdef_0") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected a split-building constraint for exam A")
+	}
+}
+
+func TestMILPUserRules(t *testing.T) {
+	p := buildScenario(false)
+	avoid := make([][]float64, len(p.Exams))
+	avoid[1] = []float64{500, 0, 0, 0}
+	perSeat := make([][]float64, len(p.Exams))
+	perSeat[0] = []float64{0, 0, 7, 0}
+	p.SetRules([]Rule{{Name: "use", Cost: avoid}, {Name: "seat", PerSeat: true, Cost: perSeat}})
+	m := BuildMILP(p)
+
+	coef := func(name string) float64 {
+		v, ok := m.Model.Lookup(name)
+		if !ok {
+			t.Fatalf("missing variable %s", name)
+		}
+		var c float64
+		for _, term := range m.Model.Objective {
+			if term.Var == v {
+				c += term.Coef
+			}
+		}
+		return c
+	}
+	if got := coef("u_1_0"); got != 500 {
+		t.Errorf("the per-use rule should cost 500 on u_1_0, got %g", got)
+	}
+	if got := coef("n_0_2") - coef("n_0_0"); got != 7 {
+		t.Errorf("the per-seat rule should add 7 to n_0_2, got %g", got)
+	}
+}
@@ -19,9 +19,12 @@ type State struct {
 
 	// structural counters (derived from roomOf), maintained incrementally
 	cellUsed  [][]int // [room][slot] seats placed (normal + alone) in this slot
+	cellLoad  [][]int // [room][slot] capacity taken, in hundredths of a seat (see loadOf)
 	cellAlone [][]int // [room][slot] NTA-alone seats present (exclusivity; ≤1)
 	examRoom  [][]int // [exam][room] seats of the exam in the room
 	examRooms []int   // [exam] number of distinct rooms the exam uses
+	examBldg  [][]int // [exam][building] seats of the exam in the building
+	examBldgs []int   // [exam] number of distinct buildings the exam uses
 	roomAny   []int   // [room] total seats across all slots (for the distinct-room count)
 
 	distinctRooms int // rooms with any use (compaction)
@@ -31,9 +34,11 @@ type State struct {
 	bufferTotal  float64
 	heatTotal    float64
 	splitTotal   float64
+	bldgTotal    float64 // split across buildings
 	compactTotal float64
 	churnTotal   float64
 	prefTotal    float64 // SEB-avoid-EXaHM + own-EXaHM-fallback room preferences
+	ruleTotal    float64 // user-defined soft rules (Problem.rules)
 	nUnplaced    int
 }
 
@@ -59,9 +64,12 @@ func newState(p *Problem) *State {
 func (st *State) rebuild() {
 	p := st.P
 	st.cellUsed = make2D(len(p.Rooms), len(p.Slots))
+	st.cellLoad = make2D(len(p.Rooms), len(p.Slots))
 	st.cellAlone = make2D(len(p.Rooms), len(p.Slots))
 	st.examRoom = make2D(len(p.Exams), len(p.Rooms))
 	st.examRooms = make([]int, len(p.Exams))
+	st.examBldg = make2D(len(p.Exams), p.nBuildings)
+	st.examBldgs = make([]int, len(p.Exams))
 	st.roomAny = make([]int, len(p.Rooms))
 	st.distinctRooms = 0
 
@@ -81,15 +89,24 @@ func (st *State) rebuild() {
 	st.heatTotal = 0
 	st.churnTotal = 0
 	st.prefTotal = 0
+	st.ruleTotal = 0
 	for i := range st.roomOf {
 		r := st.roomOf[i]
 		st.heatTotal += p.heatCostOf(i, r)
 		st.churnTotal += st.churnCostOf(i, r)
 		st.prefTotal += p.sebAvoidCostOf(i, r) + p.ownExahmCostOf(i, r)
+		st.ruleTotal += p.ruleSeatCostOf(i, r)
 	}
 	st.splitTotal = 0
+	st.bldgTotal = 0
 	for e := range p.Exams {
 		st.splitTotal += p.W.Split * float64(extraRooms(st.examRooms[e]))
+		st.bldgTotal += p.W.SplitBuilding * float64(extraRooms(st.examBldgs[e]))
+		for r := range p.Rooms {
+			if st.examRoom[e][r] > 0 {
+				st.ruleTotal += p.ruleUseCostOf(e, r)
+			}
+		}
 	}
 	st.compactTotal = p.W.Compaction * float64(st.distinctRooms)
 	st.bufferTotal = 0
@@ -105,6 +122,7 @@ func (st *State) structuralAdd(i, r int) {
 	e := p.Seats[i].Exam
 	s := p.Exams[e].Slot
 	st.cellUsed[r][s]++
+	st.cellLoad[r][s] += p.loadOf(i)
 	if p.Seats[i].Kind == NTAAlone {
 		st.cellAlone[r][s]++
 	}
@@ -112,6 +130,12 @@ func (st *State) structuralAdd(i, r int) {
 		st.examRooms[e]++
 	}
 	st.examRoom[e][r]++
+	if b := p.buildingOf[r]; b >= 0 {
+		if st.examBldg[e][b] == 0 {
+			st.examBldgs[e]++
+		}
+		st.examBldg[e][b]++
+	}
 	if st.roomAny[r] == 0 {
 		st.distinctRooms++
 	}
@@ -124,6 +148,7 @@ func (st *State) structuralRemove(i, r int) {
 	e := p.Seats[i].Exam
 	s := p.Exams[e].Slot
 	st.cellUsed[r][s]--
+	st.cellLoad[r][s] -= p.loadOf(i)
 	if p.Seats[i].Kind == NTAAlone {
 		st.cellAlone[r][s]--
 	}
@@ -131,6 +156,12 @@ func (st *State) structuralRemove(i, r int) {
 	if st.examRoom[e][r] == 0 {
 		st.examRooms[e]--
 	}
+	if b := p.buildingOf[r]; b >= 0 {
+		st.examBldg[e][b]--
+		if st.examBldg[e][b] == 0 {
+			st.examBldgs[e]--
+		}
+	}
 	st.roomAny[r]--
 	if st.roomAny[r] == 0 {
 		st.distinctRooms--
@@ -157,7 +188,7 @@ func (st *State) feasible(i, r int) bool {
 		if st.cellAlone[r][s] > 0 { // a room held alone by an NTA takes no one else
 			return false
 		}
-		if st.cellUsed[r][s]+1 > p.Rooms[r].Seats {
+		if st.cellLoad[r][s]+p.loadOf(i) > p.Rooms[r].Seats*100 {
 			return false
 		}
 	}
@@ -249,12 +280,20 @@ func (st *State) moveSeat(i, newRoom int) func() {
 	for k, x := range affExams {
 		savedBuf[k] = st.bufferByExam[x]
 	}
-	saved := costTotals{st.bufferTotal, st.heatTotal, st.splitTotal, st.compactTotal, st.churnTotal, st.prefTotal, st.nUnplaced}
+	saved := costTotals{st.bufferTotal, st.heatTotal, st.splitTotal, st.bldgTotal, st.compactTotal, st.churnTotal, st.prefTotal, st.ruleTotal, st.nUnplaced}
 
 	beforeExtra := extraRooms(st.examRooms[e])
+	beforeBldg := extraRooms(st.examBldgs[e])
 	deltaHeat := p.heatCostOf(i, newRoom) - p.heatCostOf(i, old)
 	deltaChurn := st.churnCostOf(i, newRoom) - st.churnCostOf(i, old)
 	deltaPref := (p.sebAvoidCostOf(i, newRoom) + p.ownExahmCostOf(i, newRoom)) - (p.sebAvoidCostOf(i, old) + p.ownExahmCostOf(i, old))
+	deltaRule := p.ruleSeatCostOf(i, newRoom) - p.ruleSeatCostOf(i, old)
+	if old >= 0 && st.examRoom[e][old] == 1 { // the seat was the exam's last one in old
+		deltaRule -= p.ruleUseCostOf(e, old)
+	}
+	if newRoom >= 0 && st.examRoom[e][newRoom] == 0 { // the exam starts using newRoom
+		deltaRule += p.ruleUseCostOf(e, newRoom)
+	}
 
 	if old >= 0 {
 		st.structuralRemove(i, old)
@@ -272,7 +311,9 @@ func (st *State) moveSeat(i, newRoom int) func() {
 	st.heatTotal += deltaHeat
 	st.churnTotal += deltaChurn
 	st.prefTotal += deltaPref
+	st.ruleTotal += deltaRule
 	st.splitTotal += p.W.Split * float64(extraRooms(st.examRooms[e])-beforeExtra)
+	st.bldgTotal += p.W.SplitBuilding * float64(extraRooms(st.examBldgs[e])-beforeBldg)
 	st.compactTotal = p.W.Compaction * float64(st.distinctRooms)
 	for _, x := range affExams {
 		st.bufferTotal -= st.bufferByExam[x]
@@ -291,9 +332,11 @@ func (st *State) moveSeat(i, newRoom int) func() {
 		st.bufferTotal = saved.buffer
 		st.heatTotal = saved.heat
 		st.splitTotal = saved.split
+		st.bldgTotal = saved.bldg
 		st.compactTotal = saved.compact
 		st.churnTotal = saved.churn
 		st.prefTotal = saved.pref
+		st.ruleTotal = saved.rule
 		st.nUnplaced = saved.nUnplaced
 		for k, x := range affExams {
 			st.bufferByExam[x] = savedBuf[k]
@@ -302,8 +345,8 @@ func (st *State) moveSeat(i, newRoom int) func() {
 }
 
 type costTotals struct {
-	buffer, heat, split, compact, churn, pref float64
-	nUnplaced                                 int
+	buffer, heat, split, bldg, compact, churn, pref, rule float64
+	nUnplaced                                             int
 }
 
 // churnCostOf is the warm-start churn penalty for seat i sitting in room r: W.Churn when a
@@ -322,7 +365,8 @@ func (st *State) churnCostOf(i, r int) float64 {
 // bufferPenaltyOf is exam e's free-seat-buffer shortfall penalty: W.Buffer times how far
 // the free seats across the exam's (non-alone) rooms fall below the required buffer
 // (max(2, ceil(5% of the normal students))). Free seats are shared-cell aware (they count
-// the seats actually left in each cell). 0 for an exam with no placed normal seats.
+// the seats actually left in each cell, in expected seats when sized by attendance). 0 for
+// an exam with no placed normal seats.
 func (st *State) bufferPenaltyOf(e int) float64 {
 	p := st.P
 	if p.W.Buffer == 0 {
@@ -338,7 +382,7 @@ func (st *State) bufferPenaltyOf(e int) float64 {
 			continue
 		}
 		placedNormal += st.examRoom[e][r]
-		if f := p.Rooms[r].Seats - st.cellUsed[r][s]; f > 0 {
+		if f := (p.Rooms[r].Seats*100 - st.cellLoad[r][s]) / 100; f > 0 {
 			free += f
 		}
 	}
@@ -409,7 +453,7 @@ func (st *State) allowedRooms(i int) []int {
 
 // Cost is the maintained total soft objective (O(1)).
 func (st *State) Cost() float64 {
-	return st.P.W.Unplaced*float64(st.nUnplaced) + st.bufferTotal + st.splitTotal + st.compactTotal + st.heatTotal + st.churnTotal + st.prefTotal
+	return st.P.W.Unplaced*float64(st.nUnplaced) + st.bufferTotal + st.splitTotal + st.bldgTotal + st.compactTotal + st.heatTotal + st.churnTotal + st.prefTotal + st.ruleTotal
 }
 
 func (st *State) Snapshot() any {
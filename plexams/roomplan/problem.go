@@ -14,12 +14,14 @@
 //
 // Hard constraints (enforced inside Propose, re-validated for reporting): a seat only
 // in a room allowed for it (feature/availability/handicap pre-filtered into AllowedX),
-// per-(room, slot) seat capacity, NTA-alone exclusivity, fixed pre-planned seats, an
+// per-(room, slot) seat capacity (in expected seats when rooms are sized by expected
+// attendance, see Exam.Attendance), NTA-alone exclusivity, fixed pre-planned seats, an
 // exam overrunning (long/EXaHM Nachlauf) keeping its rooms in later slots, and — in
 // summer — an own room never used in two directly consecutive slots (heat cooldown).
 // Soft objective (weighted): place every seat (dominant), a free-seat buffer per exam,
-// keep an exam together (few rooms), few distinct rooms overall (compaction), and — in
-// summer — the later a slot, the lower the floor (Hitzeschutz).
+// keep an exam together (few rooms, and those in one building), few distinct rooms
+// overall (compaction), in summer — the later a slot, the lower the floor
+// (Hitzeschutz) — and the user-defined rules (see Rule).
 package roomplan
 
 import (
@@ -57,9 +59,12 @@ type Room struct {
 	Seats   int
 	OwnRoom bool // RequestWith == NONE: subject to the summer heat constraints
 	// HeatLevel is the summer heat score (higher = hotter = worse late in the day):
-	// the R-building floor from the name, or an explicit Hitzewert override; 0 for
-	// booked/requested rooms (OwnRoom == false) and ground floor. Only used in summer.
+	// the floor from the campus model, or an explicit Hitzewert override; 0 for
+	// booked/requested rooms (OwnRoom == false), ground floor and basement. Only used in
+	// summer.
 	HeatLevel int
+	// Building groups rooms for the split-building term ("" = no building, e.g. online).
+	Building string
 
 	// descriptive feature flags (for the reporting Check functions; enforcement is via
 	// the pre-computed AllowedNormal/AllowedAlone lists)
@@ -82,6 +87,10 @@ type Exam struct {
 	NormalCount         int   // number of Normal seats (for the free-seat buffer target)
 	AllowedNormal       []int // room indices allowed for Normal seats
 	AllowedAlone        []int // room indices allowed for NTAAlone seats
+	// Attendance is the share (percent) of the Normal seats expected to show up when the
+	// rooms are sized by expected attendance; 0 = all of them (sized by registrations).
+	// A Normal seat then takes only Attendance/100 of a room seat (see loadOf).
+	Attendance int
 
 	allowedNormalSet map[int]bool
 	allowedAloneSet  map[int]bool
@@ -90,12 +99,15 @@ type Exam struct {
 // Weights scale the soft objective. Placeholder values — to be calibrated against real
 // data (Test26SS). Unplaced dominates so every seat is placed first.
 type Weights struct {
-	Unplaced   float64 // per unplaced seat (dominant)
-	Buffer     float64 // per seat the free-seat buffer of an exam falls short
-	Split      float64 // per extra room an exam is split across (keep it together)
-	Compaction float64 // per distinct room used overall (request/open fewer rooms)
-	HeatFloor  float64 // summer: per (heat level × lateness × seat) in an own room
-	Churn      float64 // per seat whose room differs from the previous plan (warm start)
+	Unplaced float64 // per unplaced seat (dominant)
+	Buffer   float64 // per seat the free-seat buffer of an exam falls short
+	Split    float64 // per extra room an exam is split across (keep it together)
+	// SplitBuilding is per extra building a split exam spans (keep the rooms of a split
+	// exam in one building, so the examer can walk between them).
+	SplitBuilding float64
+	Compaction    float64 // per distinct room used overall (request/open fewer rooms)
+	HeatFloor     float64 // summer: per (heat level × lateness × seat) in an own room
+	Churn         float64 // per seat whose room differs from the previous plan (warm start)
 
 	// SebAvoidExahm penalizes, per seat, a SEB (non-EXaHM) exam placed in an EXaHM-capable
 	// room, so SEB exams prefer plain SEB rooms (R-building) and leave the scarce booked
@@ -118,6 +130,7 @@ func DefaultWeights() Weights {
 		Unplaced:         1_000_000,
 		Buffer:           50,
 		Split:            30,
+		SplitBuilding:    60,
 		Compaction:       20,
 		HeatFloor:        5,
 		Churn:            0,  // off by default; warm-start construction already limits churn
@@ -147,7 +160,15 @@ type Problem struct {
 	// (-1 = none), for the churn soft term and the warm start. nil disables churn.
 	PrevRoom []int
 
+	// rules are the user-defined soft constraints (GenerationConfig), evaluated by the
+	// caller into per-(exam, room) penalties. Set via SetRules; nil = none.
+	rules    []Rule
+	ruleSeat [][]float64 // [exam][room] summed per-seat penalty of all rules (nil row = 0)
+	ruleUse  [][]float64 // [exam][room] summed per-use penalty of all rules (nil row = 0)
+
 	// derived
+	buildingOf   []int     // per room: building index (-1 = none)
+	nBuildings   int       // distinct buildings
 	movable      []int     // seat indices that may move (not Fixed)
 	seatsOfExam  [][]int   // exam -> its seat indices
 	examsInSlot  [][]int   // slot -> exam indices running in it (for shared-cell buffer updates)
@@ -188,6 +209,19 @@ func NewProblem(slots []Slot, rooms []Room, exams []Exam, seats []Seat, w Weight
 		}
 	}
 
+	p.buildingOf = make([]int, len(p.Rooms))
+	buildingIdx := make(map[string]int)
+	for r := range p.Rooms {
+		p.buildingOf[r] = -1
+		if b := p.Rooms[r].Building; b != "" {
+			if _, ok := buildingIdx[b]; !ok {
+				buildingIdx[b] = len(buildingIdx)
+			}
+			p.buildingOf[r] = buildingIdx[b]
+		}
+	}
+	p.nBuildings = len(buildingIdx)
+
 	p.seatsOfExam = make([][]int, len(p.Exams))
 	for i := range p.Seats {
 		s := &p.Seats[i]
@@ -285,6 +319,24 @@ func (p *Problem) allows(i, r int) bool {
 	return p.allowsNormal(s.Exam, r)
 }
 
+// loadOf is the room capacity seat i takes, in hundredths of a seat: 100, or the
+// exam's Attendance for a Normal seat when rooms are sized by expected attendance.
+func (p *Problem) loadOf(i int) int {
+	s := &p.Seats[i]
+	if s.Kind == Normal {
+		return p.Exams[s.Exam].attendanceLoad()
+	}
+	return 100
+}
+
+// attendanceLoad is the capacity a Normal seat of the exam takes, in hundredths of a seat.
+func (e *Exam) attendanceLoad() int {
+	if e.Attendance > 0 && e.Attendance < 100 {
+		return e.Attendance
+	}
+	return 100
+}
+
 // heatCostOf is the summer heat penalty of placing a Normal seat of the given exam into
 // room r: HeatFloor × room heat level × slot lateness. 0 outside summer, for booked
 // rooms, NTA-alone seats or an unplaced seat.
@@ -328,20 +380,74 @@ func (p *Problem) ownExahmCostOf(i, r int) float64 {
 	return 0
 }
 
-// FloorFromName extracts the R-building floor from a room name of the form "Rx.abc"
-// (x = floor digit), e.g. "R2.007" → 2, "R0.011" → 0. Names not matching that pattern
-// (other buildings, online rooms) return 0. It is the default heat level for an own room
-// when no explicit Hitzewert override is set; kept here so the build step and the tests
-// share one definition.
-func FloorFromName(name string) int {
-	if len(name) < 3 || (name[0] != 'R' && name[0] != 'r') || name[2] != '.' {
+// Rule is a user-defined soft constraint (a planner's condition such as "exams of examer X
+// not in building T", see plexams/softrule). The solver knows nothing about the condition:
+// the caller evaluates it for every exam and room, and Cost[e][r] is the (already weighted)
+// penalty of exam e in room r — per seat of the exam in the room with PerSeat, else once
+// per room the exam uses. A nil row means the rule never applies to the exam.
+type Rule struct {
+	Name        string
+	Title       string
+	Description string
+	Weight      float64
+	PerSeat     bool
+	Cost        [][]float64
+}
+
+// cost is the penalty of rule r for exam e in room rm (0 when unplaced).
+func (r *Rule) cost(e, rm int) float64 {
+	if rm < 0 || e >= len(r.Cost) || rm >= len(r.Cost[e]) {
+		return 0
+	}
+	return r.Cost[e][rm]
+}
+
+// SetRules installs the user-defined soft constraints. Call after NewProblem, before Solve.
+func (p *Problem) SetRules(rules []Rule) {
+	p.rules = rules
+	p.ruleSeat = make([][]float64, len(p.Exams))
+	p.ruleUse = make([][]float64, len(p.Exams))
+	for i := range rules {
+		table := p.ruleUse
+		if rules[i].PerSeat {
+			table = p.ruleSeat
+		}
+		for e, row := range rules[i].Cost {
+			if row == nil || e >= len(p.Exams) {
+				continue
+			}
+			if table[e] == nil {
+				table[e] = make([]float64, len(p.Rooms))
+			}
+			for r, c := range row {
+				if r < len(p.Rooms) {
+					table[e][r] += c
+				}
+			}
+		}
+	}
+}
+
+// ruleSeatCostOf is the summed per-seat penalty of the user rules for seat i in room r.
+func (p *Problem) ruleSeatCostOf(i, r int) float64 {
+	if r < 0 || p.ruleSeat == nil {
 		return 0
 	}
-	c := name[1]
-	if c < '0' || c > '9' {
+	if row := p.ruleSeat[p.Seats[i].Exam]; row != nil {
+		return row[r]
+	}
+	return 0
+}
+
+// ruleUseCostOf is the summed per-use penalty of the user rules for exam e using room r.
+func (p *Problem) ruleUseCostOf(e, r int) float64 {
+	if r < 0 || p.ruleUse == nil {
 		return 0
 	}
-	return int(c - '0')
+	if row := p.ruleUse[e]; row != nil {
+		return row[r]
+	}
+	return 0
 }
 
 func dayKey(t time.Time) int {
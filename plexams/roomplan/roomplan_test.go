@@ -28,10 +28,10 @@ func at(day, hour int) time.Time { return time.Date(2026, 7, day, hour, 0, 0, 0,
 // share a slot, need an EXaHM room, and one NTA-alone seat.
 func buildScenario(summer bool) *Problem {
 	rooms := []Room{
-		{Name: "R0.001", Seats: 4, OwnRoom: true, HeatLevel: 0},                          // 0: floor 0
-		{Name: "R1.001", Seats: 4, OwnRoom: true, HeatLevel: 1},                          // 1: floor 1
-		{Name: "R2.001", Seats: 3, OwnRoom: true, HeatLevel: 2},                          // 2: floor 2
-		{Name: "T3.001", Seats: 4, OwnRoom: false, HeatLevel: 0, Exahm: true, Seb: true}, // 3: booked
+		{Name: "R0.001", Seats: 4, OwnRoom: true, HeatLevel: 0, Building: "R"},                          // 0: floor 0
+		{Name: "R1.001", Seats: 4, OwnRoom: true, HeatLevel: 1, Building: "R"},                          // 1: floor 1
+		{Name: "R2.001", Seats: 3, OwnRoom: true, HeatLevel: 2, Building: "R"},                          // 2: floor 2
+		{Name: "T3.001", Seats: 4, OwnRoom: false, HeatLevel: 0, Exahm: true, Seb: true, Building: "T"}, // 3: booked
 	}
 	slots := []Slot{{Start: at(6, 9)}, {Start: at(6, 12)}, {Start: at(6, 15)}} // one day, 3 slots
 	ownRooms := []int{0, 1, 2}
@@ -79,18 +79,6 @@ func recomputeSoftCost(p *Problem, st *State) float64 {
 
 // --- tests ---
 
-func TestFloorFromName(t *testing.T) {
-	cases := map[string]int{
-		"R2.007": 2, "R0.011": 0, "R1.046": 1, "R3.026": 3,
-		"T3.015": 0, "ONLINE_1": 0, "E0.103": 0, "": 0, "R": 0, "RX.001": 0,
-	}
-	for name, want := range cases {
-		if got := FloorFromName(name); got != want {
-			t.Errorf("FloorFromName(%q) = %d, want %d", name, got, want)
-		}
-	}
-}
-
 func TestConstructIsHardFeasible(t *testing.T) {
 	for _, summer := range []bool{false, true} {
 		p := buildScenario(summer)
@@ -158,6 +146,31 @@ func TestSolvePlacesEveryoneAndValidates(t *testing.T) {
 	}
 }
 
+// TestExpectedAttendance: sized by expected attendance, a room takes more registered
+// students than it has seats; sized by registrations, one of them stays unplaced.
+func TestExpectedAttendance(t *testing.T) {
+	for _, tt := range []struct {
+		attendance, unplaced int
+	}{{80, 0}, {0, 1}} {
+		exams := []Exam{{Ancode: 100, Slot: 0, NormalCount: 5, AllowedNormal: []int{0}, Attendance: tt.attendance}}
+		var seats []Seat
+		for k := 0; k < 5; k++ {
+			seats = append(seats, Seat{Exam: 0, Mtknr: mtk(0, k), Kind: Normal})
+		}
+		p := NewProblem([]Slot{{Start: at(6, 9)}}, []Room{{Name: "R0.001", Seats: 4, OwnRoom: true}}, exams, seats, DefaultWeights())
+		opts := optimize.DefaultOptions()
+		opts.Iterations = 5000
+		opts.Seed = 7
+		st, _ := Solve(p, opts, false)
+		if n := st.UnplacedCount(); n != tt.unplaced {
+			t.Errorf("attendance %d: %d unplaced, want %d", tt.attendance, n, tt.unplaced)
+		}
+		if vs := p.Registry().HardViolations(st); len(vs) > 0 {
+			t.Errorf("attendance %d: hard violations %+v", tt.attendance, vs)
+		}
+	}
+}
+
 // TestSummerCooldown: an own room must never be used in two directly consecutive slots.
 func TestSummerCooldown(t *testing.T) {
 	// two exams, each 3 students, only room R0.001 allowed, in consecutive slots. In summer
@@ -224,6 +237,37 @@ func TestHeatFloorPrefersLowerFloor(t *testing.T) {
 	}
 }
 
+// TestSplitBuildingKeepsOneBuilding: an exam that has to split picks two rooms of the
+// same building over one room in each of two buildings.
+func TestSplitBuildingKeepsOneBuilding(t *testing.T) {
+	rooms := []Room{
+		{Name: "R1.001", Seats: 3, Building: "R"},
+		{Name: "T1.001", Seats: 3, Building: "T"},
+		{Name: "R1.002", Seats: 3, Building: "R"},
+	}
+	slots := []Slot{{Start: at(6, 9)}}
+	exams := []Exam{{Ancode: 1, Slot: 0, NormalCount: 5, AllowedNormal: []int{0, 1, 2}, AllowedAlone: []int{0, 1, 2}}}
+	var seats []Seat
+	for k := 0; k < 5; k++ {
+		seats = append(seats, Seat{Exam: 0, Mtknr: mtk(0, k), Kind: Normal})
+	}
+	w := DefaultWeights()
+	w.Buffer = 0 // 5 of 6 seats: no buffer pressure towards a third room
+	p := NewProblem(slots, rooms, exams, seats, w)
+	opts := optimize.DefaultOptions()
+	opts.Iterations = 20000
+	opts.Seed = 5
+	st, _ := Solve(p, opts, false)
+	for _, a := range st.Assignments() {
+		if a.Room == "T1.001" {
+			t.Errorf("split exam placed across buildings: %+v", st.Assignments())
+		}
+	}
+	if got, want := st.Cost(), recomputeSoftCost(p, st); got != want {
+		t.Errorf("incremental cost %.4f != recompute %.4f", got, want)
+	}
+}
+
 // TestPrePlannedFixed: a fixed seat stays in its room through solving.
 func TestPrePlannedFixed(t *testing.T) {
 	rooms := []Room{{Name: "R0.001", Seats: 5, OwnRoom: true}, {Name: "R1.001", Seats: 5, OwnRoom: true}}
@@ -322,8 +366,8 @@ func TestRoomPreferences(t *testing.T) {
 func TestRegistryDescribeCoversAll(t *testing.T) {
 	p := buildScenario(true)
 	infos := p.Registry().Describe()
-	if len(infos) != 15 { // 7 hard + 8 soft
-		t.Fatalf("expected 15 constraints, got %d", len(infos))
+	if len(infos) != 16 { // 7 hard + 9 soft
+		t.Fatalf("expected 16 constraints, got %d", len(infos))
 	}
 	seen := map[string]bool{}
 	for _, in := range infos {
@@ -336,3 +380,58 @@ func TestRegistryDescribeCoversAll(t *testing.T) {
 		seen[in.Name] = true
 	}
 }
+
+func TestUserRules(t *testing.T) {
+	p := buildScenario(false)
+	avoid := make([][]float64, len(p.Exams))
+	avoid[1] = []float64{500, 0, 0, 0} // B not in R0.001, per room use
+	perSeat := make([][]float64, len(p.Exams))
+	perSeat[0] = []float64{0, 0, 7, 0} // A pays 7 per seat in R2.001
+	p.SetRules([]Rule{
+		{Name: "b-not-r0", Weight: 500, Cost: avoid},
+		{Name: "a-seats-r2", Weight: 7, PerSeat: true, Cost: perSeat},
+	})
+
+	infos := p.Registry().Describe()
+	names := map[string]bool{}
+	for _, in := range infos {
+		names[in.Name] = true
+	}
+	if !names["rule:b-not-r0"] || !names["rule:a-seats-r2"] {
+		t.Fatalf("user rules must be listed in Describe, got %v", names)
+	}
+
+	// the incremental rule total must follow single-seat moves and undos
+	st := construct(p)
+	rng := rand.New(rand.NewSource(3))
+	for it := 0; it < 20000; it++ {
+		undo := st.Propose(rng)
+		if undo == nil {
+			continue
+		}
+		if got, want := st.Cost(), recomputeSoftCost(p, st); math.Abs(got-want) > 1e-6 {
+			t.Fatalf("iter %d: incremental cost %.4f != recompute %.4f", it, got, want)
+		}
+		if rng.Float64() < 0.5 {
+			undo()
+		}
+	}
+
+	opts := optimize.DefaultOptions()
+	opts.Iterations = 50000
+	opts.Seed = 7
+	st, _ = Solve(p, opts, false)
+	for _, a := range st.Assignments() {
+		if a.Ancode == 200 && a.Room == "R0.001" {
+			t.Errorf("exam B placed in R0.001 despite the rule")
+		}
+	}
+	if _, vs := (ruleC{r: &p.rules[0]}).Cost(st); len(vs) != 0 {
+		t.Errorf("expected no violation of b-not-r0, got %v", vs)
+	}
+
+	// OnDay keeps the rules for the exams of the day
+	if sub := p.OnDay(at(6, 0)); len(sub.Registry().Soft) != len(p.Registry().Soft) || sub.ruleUse[1][0] != 500 {
+		t.Error("OnDay must carry the user rules over to the sub-problem")
+	}
+}
@@ -93,7 +93,7 @@ func (st *State) chooseRoom(i int) int {
 		if !st.feasible(i, r) {
 			continue
 		}
-		score := [2]int{st.cellUsed[r][p.Exams[p.Seats[i].Exam].Slot], p.Rooms[r].Seats}
+		score := [2]int{st.cellLoad[r][p.Exams[p.Seats[i].Exam].Slot], p.Rooms[r].Seats}
 		if best < 0 || less2(bestScore, score) {
 			best, bestScore = r, score
 		}
@@ -105,13 +105,17 @@ func (st *State) chooseRoom(i int) int {
 // read-only "which constraints are applied" view. Hard constraints are enforced inside
 // Propose; their Check re-validates a finished state.
 func (p *Problem) Registry() optimize.Registry[*State] {
+	soft := []optimize.SoftConstraint[*State]{
+		placementC{}, bufferC{}, splitC{}, splitBuildingC{}, compactionC{}, sebRbauC{}, exahmBookedC{}, heatFloorC{}, churnC{},
+	}
+	for i := range p.rules {
+		soft = append(soft, ruleC{i: i, r: &p.rules[i]})
+	}
 	return optimize.Registry[*State]{
 		Hard: []optimize.HardConstraint[*State]{
 			allowedRoomC{}, capacityC{}, ntaAloneC{}, prePlannedC{}, overrunC{}, summerCooldownC{}, exahmWindowC{},
 		},
-		Soft: []optimize.SoftConstraint[*State]{
-			placementC{}, bufferC{}, splitC{}, compactionC{}, sebRbauC{}, exahmBookedC{}, heatFloorC{}, churnC{},
-		},
+		Soft: soft,
 	}
 }
 
@@ -138,13 +142,13 @@ type capacityC struct{}
 
 func (capacityC) Info() optimize.Info {
 	return optimize.Info{Name: "capacity", Title: "Raumkapazität pro Slot", Kind: optimize.KindHard, Tier: 2,
-		Description: "Belegte Sitze je (Raum, Slot) – inkl. der aus Nachbarslots hereinlaufenden Nachlauf-Belegung – dürfen die Sitzplätze des Raums nicht übersteigen. Räume werden von mehreren Prüfungen geteilt."}
+		Description: "Belegte Sitze je (Raum, Slot) – inkl. der aus Nachbarslots hereinlaufenden Nachlauf-Belegung – dürfen die Sitzplätze des Raums nicht übersteigen. Räume werden von mehreren Prüfungen geteilt. Bei Raumbemessung nach erwarteter Teilnahme zählt ein Sitz nur mit der erwarteten Quote seiner Prüfung."}
 }
 func (capacityC) Check(st *State) []optimize.Violation {
 	var vs []optimize.Violation
 	for r := range st.P.Rooms {
 		for s := range st.P.Slots {
-			if st.cellUsed[r][s] > st.P.Rooms[r].Seats {
+			if st.cellLoad[r][s] > st.P.Rooms[r].Seats*100 {
 				vs = append(vs, optimize.Violation{Constraint: "capacity",
 					Message: fmt.Sprintf("Raum %s zum Slot %s überbelegt", st.P.Rooms[r].Name, st.P.Slots[s].Start.Format("02.01. 15:04"))})
 			}
@@ -326,6 +330,26 @@ func (splitC) Cost(st *State) (float64, []optimize.Violation) {
 	return total, nil
 }
 
+type splitBuildingC struct{}
+
+func (splitBuildingC) Info() optimize.Info {
+	return optimize.Info{Name: "exam-split-building", Title: "Geteilte Prüfung in einem Gebäude", Kind: optimize.KindSoft, Tier: 21,
+		Description: "Muss eine Prüfung auf mehrere Räume verteilt werden, sollen diese im selben Gebäude liegen (Gebäude aus dem Campus-Modell); jedes weitere Gebäude wird bestraft."}
+}
+func (splitBuildingC) Cost(st *State) (float64, []optimize.Violation) {
+	var total float64
+	var vs []optimize.Violation
+	for e := range st.P.Exams {
+		if extra := extraRooms(st.examBldgs[e]); extra > 0 {
+			pen := st.P.W.SplitBuilding * float64(extra)
+			total += pen
+			vs = append(vs, optimize.Violation{Constraint: "exam-split-building", Penalty: pen,
+				Message: fmt.Sprintf("Prüfung auf %d Gebäude verteilt", st.examBldgs[e]), Refs: []int{st.P.Exams[e].Ancode}})
+		}
+	}
+	return total, vs
+}
+
 type compactionC struct{}
 
 func (compactionC) Info() optimize.Info {
@@ -392,6 +416,44 @@ func (churnC) Cost(st *State) (float64, []optimize.Violation) {
 	return total, nil
 }
 
+// ruleC is one user-defined soft rule (Problem.rules), listed under "rule:<name>".
+type ruleC struct {
+	i int
+	r *Rule
+}
+
+func (c ruleC) Info() optimize.Info {
+	title := c.r.Title
+	if title == "" {
+		title = c.r.Name
+	}
+	return optimize.Info{Name: "rule:" + c.r.Name, Title: "Eigene Regel: " + title, Kind: optimize.KindSoft, Weight: c.r.Weight, Tier: 40 + c.i,
+		Description: c.r.Description}
+}
+func (c ruleC) Cost(st *State) (float64, []optimize.Violation) {
+	p := st.P
+	var total float64
+	var vs []optimize.Violation
+	for e := range p.Exams {
+		for r := range p.Rooms {
+			seats := st.examRoom[e][r]
+			if seats == 0 {
+				continue
+			}
+			pen := c.r.cost(e, r)
+			if c.r.PerSeat {
+				pen *= float64(seats)
+			}
+			if pen > 0 {
+				total += pen
+				vs = append(vs, optimize.Violation{Constraint: "rule:" + c.r.Name, Penalty: pen, Refs: []int{p.Exams[e].Ancode},
+					Message: "Regel „" + c.r.Name + "“ verletzt (Raum " + p.Rooms[r].Name + ")"})
+			}
+		}
+	}
+	return total, vs
+}
+
 // --- helpers ---
 
 // less2 orders the chooseRoom score tuples (higher is better in each component).
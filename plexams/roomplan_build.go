@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/campus"
 	"github.com/obcode/plexams.go/plexams/optimize"
 	"github.com/obcode/plexams.go/plexams/roomcalc"
 	"github.com/obcode/plexams.go/plexams/roomplan"
@@ -32,7 +33,9 @@ type RoomPlanResult struct {
 // buildRoomPlanProblem assembles the room-allocation optimization problem from the current
 // data: every planned exam (with its registrations/NTAs and per-exam room constraints), the
 // concrete rooms with their per-slot availability, the manually pre-planned rooms (fixed),
-// and — for the summer heat constraints — each room's floor/heat level. The exam times are
+// the expected attendance per exam when the rooms are sized by it (see seat_demand.go),
+// and — from the campus model — each room's building (split exams stay in one building),
+// campus (an exam only gets rooms on its campus) and floor/heat level. The exam times are
 // fixed (Terminplan); the solver only decides the rooms.
 func (p *Plexams) buildRoomPlanProblem(ctx context.Context) (*roomplan.Problem, error) {
 	sc := p.semesterConfig
@@ -46,6 +49,11 @@ func (p *Plexams) buildRoomPlanProblem(ctx context.Context) (*roomplan.Problem,
 		return nil, err
 	}
 	sort.Slice(allRooms, func(i, j int) bool { return allRooms[i].Name < allRooms[j].Name })
+	sites, err := p.campusModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roomCampus := make([]string, len(allRooms)) // "" = no building, usable from any campus
 	roomInfo := make(map[string]*model.Room, len(allRooms))
 	roomIdx := make(map[string]int, len(allRooms))
 	rooms := make([]roomplan.Room, len(allRooms))
@@ -53,8 +61,12 @@ func (p *Plexams) buildRoomPlanProblem(ctx context.Context) (*roomplan.Problem,
 		roomInfo[r.Name] = r
 		roomIdx[r.Name] = i
 		own := r.RequestWith == model.RoomRequestTypeNone
+		loc := sites.Room(r.Name)
+		if loc.Building != "" {
+			roomCampus[i] = loc.Campus
+		}
 		rooms[i] = roomplan.Room{
-			Name: r.Name, Seats: r.Seats, OwnRoom: own, HeatLevel: heatLevelOf(r),
+			Name: r.Name, Seats: r.Seats, OwnRoom: own, HeatLevel: heatLevelOf(r, loc.Floor), Building: loc.Building,
 			Exahm: r.Exahm, Seb: r.Seb, Lab: r.Lab, Handicap: r.Handicap, PlacesWithSocket: r.PlacesWithSocket,
 		}
 	}
@@ -89,8 +101,19 @@ func (p *Plexams) buildRoomPlanProblem(ctx context.Context) (*roomplan.Problem,
 
 	prePlanned := p.prePlannedRooms(ctx, roomInfo) // ancode -> []*PrePlannedRoom (sorted)
 
+	genCfg, err := p.GenerationConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// sized by expected attendance: the no-show history of the previous semesters
+	sizing, err := p.roomSizingHistory(ctx, genCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// --- exams + seats ---
 	var exams []roomplan.Exam
+	var planned []*model.PlannedExam // the planned exam behind exams[i] (for the user rules)
 	var seats []roomplan.Seat
 	examIdxByAncode := make(map[int]int)
 
@@ -122,12 +145,16 @@ func (p *Plexams) buildRoomPlanProblem(ctx context.Context) (*roomplan.Problem,
 				Ancode: exam.Ancode, Slot: si, Duration: exam.MaxDuration, Exahm: exahm, Seb: seb,
 				PreExtra: preExtra, PostExtra: postExtra,
 				NormalCount:   normalCount,
-				AllowedNormal: allowedRoomsFor(rooms, allowedInSlot[si], c, false),
-				AllowedAlone:  allowedRoomsFor(rooms, allowedInSlot[si], c, true),
+				AllowedNormal: onCampus(allowedRoomsFor(allRooms, allowedInSlot[si], c, false), roomCampus, sites, c),
+				AllowedAlone:  onCampus(allowedRoomsFor(allRooms, allowedInSlot[si], c, true), roomCampus, sites, c),
+			}
+			if sizing != nil {
+				e.Attendance = seatDemandOf(sizing, genCfg.RoomSizingMargin, exam).Attendance
 			}
 			eIdx := len(exams)
 			examIdxByAncode[exam.Ancode] = eIdx
 			exams = append(exams, e)
+			planned = append(planned, exam)
 
 			// seats: normal regs, NTA-in-normal (folded as Normal), additionalSeats dummies,
 			// then NTA-alone.
@@ -149,26 +176,46 @@ func (p *Plexams) buildRoomPlanProblem(ctx context.Context) (*roomplan.Problem,
 	// --- honor pre-planned rooms as fixed seats ---
 	applyPrePlannedRooms(seats, examIdxByAncode, roomIdx, roomInfo, prePlanned)
 
-	genCfg, err := p.GenerationConfig(ctx)
-	if err != nil {
-		return nil, err
-	}
 	prob := roomplan.NewProblem(slots, rooms, exams, seats, roomPlanWeights(genCfg))
 	prob.Summer = p.resolveRoomHeat(genCfg)
 	prob.TimelagMin = p.generationTimelagMin(ctx)
+	// user-defined soft rules (validated on save; see buildExamPlanProblem)
+	if rules, err := compileSoftRules(genCfg.SoftRules); err != nil {
+		log.Error().Err(err).Msg("user-defined soft rules ignored")
+	} else {
+		prob.SetRules(roomPlanRules(rules, prob, planned))
+	}
 	return prob, nil
 }
 
 // heatLevelOf is a room's summer heat level: 0 for booked/requested rooms (always fine), else
-// the explicit Hitzewert override, else the R-building floor parsed from the name.
-func heatLevelOf(r *model.Room) int {
+// the explicit Hitzewert override, else the room's floor from the campus model (basement
+// floors count as ground floor).
+func heatLevelOf(r *model.Room, floor int) int {
 	if r.RequestWith != model.RoomRequestTypeNone {
 		return 0
 	}
 	if r.Hitzewert != nil {
 		return *r.Hitzewert
 	}
-	return roomplan.FloorFromName(r.Name)
+	return max(floor, 0)
+}
+
+// onCampus keeps the rooms on the exam's campus (Constraints.Location, "" = default campus).
+// Without campuses in the campus model every room stays; rooms without a building (online)
+// fit every campus.
+func onCampus(allowed []int, roomCampus []string, sites *campus.Model, c *model.Constraints) []int {
+	if sites.DefaultCampus() == "" {
+		return allowed
+	}
+	want := sites.Campus(locationOf(c))
+	out := make([]int, 0, len(allowed))
+	for _, ri := range allowed {
+		if roomCampus[ri] == "" || roomCampus[ri] == want {
+			out = append(out, ri)
+		}
+	}
+	return out
 }
 
 // roomPlanWeights maps the generation config to the roomplan solver weights.
@@ -180,6 +227,7 @@ func roomPlanWeights(cfg *model.GenerationConfig) roomplan.Weights {
 	w.Unplaced = cfg.RoomUnplaced
 	w.Buffer = cfg.RoomBuffer
 	w.Split = cfg.RoomSplit
+	w.SplitBuilding = cfg.RoomSplitBuilding
 	w.Compaction = cfg.RoomCompaction
 	w.HeatFloor = cfg.RoomHeatFloor
 	w.Churn = cfg.RoomChurn
@@ -205,18 +253,13 @@ func (p *Plexams) resolveRoomHeat(cfg *model.GenerationConfig) bool {
 }
 
 // allowedRoomsFor returns the room indices an exam's Normal (alone=false) or NTA-alone
-// (alone=true) seats may use: available in the slot, feature-satisfying
+// (alone=true) seats may use: available in the slot, tag-satisfying
 // (roomcalc.SatisfiesConstraints), and — for Normal seats — not a handicap room (those are
 // reserved for NTAs).
-func allowedRoomsFor(rooms []roomplan.Room, avail map[int]bool, c *model.Constraints, alone bool) []int {
+func allowedRoomsFor(rooms []*model.Room, avail map[int]bool, c *model.Constraints, alone bool) []int {
 	var out []int
 	for ri := range avail {
-		room := &model.Room{
-			Name: rooms[ri].Name, Seats: rooms[ri].Seats, Exahm: rooms[ri].Exahm,
-			Seb: rooms[ri].Seb, Lab: rooms[ri].Lab, Handicap: rooms[ri].Handicap,
-			PlacesWithSocket: rooms[ri].PlacesWithSocket,
-		}
-		if !roomcalc.SatisfiesConstraints(room, c) {
+		if !roomcalc.SatisfiesConstraints(rooms[ri], c) {
 			continue
 		}
 		if !alone && rooms[ri].Handicap {
@@ -471,8 +514,17 @@ func summerNote(summer bool) string {
 }
 
 // RoomPlanConstraints returns the read-only description of the hard/soft constraints the
-// room-plan generator applies.
-func (p *Plexams) RoomPlanConstraints() []optimize.Info {
+// room-plan generator applies, including the enabled user rules.
+func (p *Plexams) RoomPlanConstraints(ctx context.Context) ([]optimize.Info, error) {
 	prob := &roomplan.Problem{W: roomplan.DefaultWeights()}
-	return prob.Registry().Describe()
+	cfg, err := p.GenerationConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := compileSoftRules(cfg.SoftRules)
+	if err != nil {
+		return nil, err
+	}
+	prob.SetRules(roomPlanRules(rules, nil, nil))
+	return prob.Registry().Describe(), nil
 }
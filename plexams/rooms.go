@@ -8,6 +8,7 @@ import (
 
 	"github.com/obcode/plexams.go/graph/model"
 	"github.com/obcode/plexams.go/plexams/anny"
+	"github.com/obcode/plexams.go/plexams/roomcalc"
 	"github.com/rs/zerolog/log"
 )
 
@@ -131,7 +132,11 @@ func (p *Plexams) SetRoomActive(ctx context.Context, name string, active bool) (
 	return p.dbClient.SetRoomDeactivated(ctx, name, !active)
 }
 
-func roomInputToRoom(input model.RoomInput) *model.Room {
+func roomInputToRoom(input model.RoomInput) (*model.Room, error) {
+	tags, err := roomcalc.NormalizeTags(input.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("room %s: %w", input.Name, err)
+	}
 	return &model.Room{
 		Name:             input.Name,
 		Seats:            input.Seats,
@@ -146,7 +151,8 @@ func roomInputToRoom(input model.RoomInput) *model.Room {
 		SebSeats:         input.SebSeats,
 		HmebSeats:        input.HmebSeats,
 		Hitzewert:        input.Hitzewert,
-	}
+		Tags:             tags,
+	}, nil
 }
 
 // AddRoom creates a new room (key: name). Errors if a room with that name
@@ -159,7 +165,11 @@ func (p *Plexams) AddRoom(ctx context.Context, input model.RoomInput) (*model.Ro
 	if exists {
 		return nil, fmt.Errorf("room %s already exists", input.Name)
 	}
-	room, err := p.dbClient.AddRoom(ctx, roomInputToRoom(input))
+	room, err := roomInputToRoom(input)
+	if err != nil {
+		return nil, err
+	}
+	room, err = p.dbClient.AddRoom(ctx, room)
 	if err != nil {
 		return nil, err
 	}
@@ -176,7 +186,10 @@ func (p *Plexams) UpdateRoom(ctx context.Context, input model.RoomInput) (*model
 	if err != nil {
 		return nil, err
 	}
-	updated := roomInputToRoom(input)
+	updated, err := roomInputToRoom(input)
+	if err != nil {
+		return nil, err
+	}
 	updated.Deactivated = existing.Deactivated // toggle owns the active state
 	room, err := p.dbClient.ReplaceRoom(ctx, updated)
 	if err != nil {
@@ -187,6 +200,20 @@ func (p *Plexams) UpdateRoom(ctx context.Context, input model.RoomInput) (*model
 	return room, nil
 }
 
+// RoomFeatures returns all tags of every room: the built-in ones from the room
+// booleans plus the free-form tags.
+func (p *Plexams) RoomFeatures(ctx context.Context) ([]*model.RoomFeatures, error) {
+	rooms, err := p.dbClient.Rooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	features := make([]*model.RoomFeatures, 0, len(rooms))
+	for _, room := range rooms {
+		features = append(features, &model.RoomFeatures{Room: room.Name, Tags: roomcalc.RoomTags(room).Strings()})
+	}
+	return features, nil
+}
+
 // RoomsForSlots computes the allowed rooms per slot live from the current state
 // (global rooms, EXaHM/Anny bookings, building-management requests, room blocks).
 // There is no stored cache anymore.
@@ -47,13 +47,17 @@ func (p *Plexams) BlockRoomForSlot(ctx context.Context, room string, starttime t
 	return block, nil
 }
 
-// UnblockRoomForSlot removes a room-slot block. Errors if no such block exists.
-func (p *Plexams) UnblockRoomForSlot(ctx context.Context, room string, starttime time.Time) (bool, error) {
-	removed, err := p.dbClient.UnblockRoomForSlot(ctx, room, starttime)
+// UnblockRoomForSlot removes a room-slot block, or with until the time-range block
+// [starttime, until) (a reservation). Errors if no such block exists.
+func (p *Plexams) UnblockRoomForSlot(ctx context.Context, room string, starttime time.Time, until *time.Time) (bool, error) {
+	removed, err := p.dbClient.UnblockRoomForSlot(ctx, room, starttime, until)
 	if err != nil {
 		return false, err
 	}
 	if !removed {
+		if until != nil {
+			return false, fmt.Errorf("no block for room %s from %s to %s", room, formatBlockedRoomTime(&starttime), until.Format("15:04"))
+		}
 		return false, fmt.Errorf("no block for room %s at %s", room, formatBlockedRoomTime(&starttime))
 	}
 	return true, nil
@@ -100,16 +104,29 @@ func (p *Plexams) applyRoomBlocks(ctx context.Context, slotsWithRoomNames map[ti
 		plannedInSlot[key].Add(pr.RoomName)
 	}
 
+	slotBlock := slotBlockDuration(p.semesterConfig.Starttimes)
 	for _, b := range blocks {
 		if b.Starttime == nil {
 			continue
 		}
-		key := *b.Starttime
-		if roomNames, ok := slotsWithRoomNames[key]; ok {
-			roomNames.Remove(b.Room)
+		keys := []time.Time{*b.Starttime}
+		if b.Until != nil {
+			keys = blockedSlots(b, slotsWithRoomNames, slotBlock)
 		}
 		when := formatBlockedRoomTime(b.Starttime)
-		if planned, ok := plannedInSlot[key]; ok && planned.Contains(b.Room) {
+		if b.Until != nil {
+			when += " – " + b.Until.Format("15:04")
+		}
+		plannedThere := false
+		for _, key := range keys {
+			if roomNames, ok := slotsWithRoomNames[key]; ok {
+				roomNames.Remove(b.Room)
+			}
+			if planned, ok := plannedInSlot[key]; ok && planned.Contains(b.Room) {
+				plannedThere = true
+			}
+		}
+		if plannedThere {
 			reporter.Warnf(aurora.Sprintf(
 				aurora.Red("room %s is blocked at %s but currently planned there; it will be dropped on the next rooms-for-exams run"),
 				b.Room, when))
@@ -119,3 +136,15 @@ func (p *Plexams) applyRoomBlocks(ctx context.Context, slotsWithRoomNames map[ti
 	}
 	return nil
 }
+
+// blockedSlots returns the slot start times a time-range block overlaps (a slot
+// spans slotBlock from its start).
+func blockedSlots[V any](b *model.BlockedRoom, slots map[time.Time]V, slotBlock time.Duration) []time.Time {
+	var keys []time.Time
+	for start := range slots {
+		if start.Before(*b.Until) && start.Add(slotBlock).After(*b.Starttime) {
+			keys = append(keys, start)
+		}
+	}
+	return keys
+}
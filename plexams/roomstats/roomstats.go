@@ -0,0 +1,292 @@
+// Package roomstats holds the pure room-utilization math: from the planned rooms, the
+// room seat numbers and the Anny bookings it derives seats used vs. available per room
+// and start time, the idle booked Anny minutes, the own-vs-requested share and the peak
+// start times. All functions are I/O-free; the DB access stays in the plexams package.
+//
+// A (room, start time) pair counts with all the room's seats, however many exams share
+// it: the room is opened (or booked) for that start time either way.
+package roomstats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+// PeakSlotsLimit caps the list of the busiest start times.
+const PeakSlotsLimit = 10
+
+// Room is what the statistics need to know about a room.
+type Room struct {
+	Seats       int
+	RequestWith model.RoomRequestType
+}
+
+// Use is one planned room of an exam at a start time.
+type Use struct {
+	Room     string
+	Start    time.Time
+	Duration int // minutes, incl. NTA extension
+	Ancode   int
+	Students int
+	Reserve  bool
+}
+
+// Booking is one (not canceled) Anny booking of a room.
+type Booking struct {
+	Room        string
+	From, Until time.Time
+}
+
+type cellKey struct {
+	room  string
+	start int64
+}
+
+type cell struct {
+	room     string
+	start    time.Time
+	exams    map[int]bool
+	students int
+}
+
+// Compute aggregates the uses and bookings of one workspace. Rooms missing from rooms
+// count with 0 seats as own rooms.
+func Compute(workspace string, rooms map[string]Room, uses []Use, bookings []Booking) *model.RoomUtilization {
+	cells := make(map[cellKey]*cell)
+	reserve := make(map[cellKey]bool)
+	exams := make(map[int]bool)
+	for _, u := range uses {
+		k := cellKey{u.Room, u.Start.Unix()}
+		if u.Reserve {
+			reserve[k] = true
+			continue
+		}
+		c := cells[k]
+		if c == nil {
+			c = &cell{room: u.Room, start: u.Start, exams: make(map[int]bool)}
+			cells[k] = c
+		}
+		c.exams[u.Ancode] = true
+		c.students += u.Students
+		exams[u.Ancode] = true
+	}
+	for k := range reserve {
+		if cells[k] != nil {
+			delete(reserve, k) // a reserve room also used by an exam is just used
+		}
+	}
+
+	sum := &model.RoomUtilizationSummary{Workspace: workspace, Exams: len(exams), ReserveRoomSlots: len(reserve)}
+	out := &model.RoomUtilization{
+		Summary:      sum,
+		Rooms:        make([]*model.RoomUsage, 0),
+		Cells:        make([]*model.RoomSlotUsage, 0, len(cells)),
+		PeakSlots:    make([]*model.SlotUsage, 0),
+		AnnyBookings: make([]*model.AnnyBookingUsage, 0),
+	}
+
+	byRoom := make(map[string]*model.RoomUsage)
+	bySlot := make(map[int64]*model.SlotUsage)
+	byKind := make(map[model.RoomRequestType]*model.RoomKindShare)
+	kindRooms := make(map[model.RoomRequestType]map[string]bool)
+	for _, c := range cells {
+		info := roomInfo(rooms, c.room)
+		out.Cells = append(out.Cells, &model.RoomSlotUsage{
+			Room: c.room, RequestWith: info.RequestWith, Starttime: c.start, Seats: info.Seats,
+			Exams: len(c.exams), Students: c.students, Utilization: Percent(c.students, info.Seats),
+		})
+
+		r := byRoom[c.room]
+		if r == nil {
+			r = &model.RoomUsage{Room: c.room, RequestWith: info.RequestWith, Seats: info.Seats}
+			byRoom[c.room] = r
+		}
+		r.SlotsUsed++
+		r.SeatsAvailable += info.Seats
+		r.SeatsUsed += c.students
+
+		s := bySlot[c.start.Unix()]
+		if s == nil {
+			s = &model.SlotUsage{Starttime: c.start}
+			bySlot[c.start.Unix()] = s
+		}
+		s.Rooms++
+		s.SeatsAvailable += info.Seats
+		s.Students += c.students
+
+		k := byKind[info.RequestWith]
+		if k == nil {
+			k = &model.RoomKindShare{RequestWith: info.RequestWith}
+			byKind[info.RequestWith] = k
+			kindRooms[info.RequestWith] = make(map[string]bool)
+		}
+		kindRooms[info.RequestWith][c.room] = true
+		k.RoomSlots++
+		k.SeatsAvailable += info.Seats
+		k.SeatsUsed += c.students
+
+		sum.RoomSlots++
+		sum.SeatsAvailable += info.Seats
+		sum.SeatsUsed += c.students
+	}
+	sum.Utilization = Percent(sum.SeatsUsed, sum.SeatsAvailable)
+	sum.RoomsUsed = len(byRoom)
+	sum.SlotsUsed = len(bySlot)
+
+	sort.Slice(out.Cells, func(i, j int) bool {
+		if !out.Cells[i].Starttime.Equal(out.Cells[j].Starttime) {
+			return out.Cells[i].Starttime.Before(out.Cells[j].Starttime)
+		}
+		return out.Cells[i].Room < out.Cells[j].Room
+	})
+
+	for _, r := range byRoom {
+		r.Utilization = Percent(r.SeatsUsed, r.SeatsAvailable)
+		out.Rooms = append(out.Rooms, r)
+	}
+	sort.Slice(out.Rooms, func(i, j int) bool {
+		if out.Rooms[i].SlotsUsed != out.Rooms[j].SlotsUsed {
+			return out.Rooms[i].SlotsUsed > out.Rooms[j].SlotsUsed
+		}
+		return out.Rooms[i].Room < out.Rooms[j].Room
+	})
+
+	examsAt := make(map[int64]map[int]bool)
+	for _, c := range cells {
+		if examsAt[c.start.Unix()] == nil {
+			examsAt[c.start.Unix()] = make(map[int]bool)
+		}
+		for a := range c.exams {
+			examsAt[c.start.Unix()][a] = true
+		}
+	}
+	slots := make([]*model.SlotUsage, 0, len(bySlot))
+	for t, s := range bySlot {
+		s.Exams = len(examsAt[t])
+		s.Utilization = Percent(s.Students, s.SeatsAvailable)
+		slots = append(slots, s)
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		if slots[i].Students != slots[j].Students {
+			return slots[i].Students > slots[j].Students
+		}
+		return slots[i].Starttime.Before(slots[j].Starttime)
+	})
+	if len(slots) > PeakSlotsLimit {
+		slots = slots[:PeakSlotsLimit]
+	}
+	out.PeakSlots = slots
+	if len(slots) > 0 {
+		sum.PeakStudents = slots[0].Students
+		peak := slots[0].Starttime
+		sum.PeakStarttime = &peak
+	}
+
+	for _, kind := range model.AllRoomRequestType {
+		k := byKind[kind]
+		if k == nil {
+			k = &model.RoomKindShare{RequestWith: kind}
+		}
+		k.Rooms = len(kindRooms[kind])
+		k.Utilization = Percent(k.SeatsUsed, k.SeatsAvailable)
+		k.Share = Percent(k.SeatsUsed, sum.SeatsUsed)
+		sum.ByKind = append(sum.ByKind, k)
+	}
+
+	for _, b := range bookings {
+		bu := bookingUsage(b, uses)
+		out.AnnyBookings = append(out.AnnyBookings, bu)
+		sum.AnnyBookings++
+		sum.AnnyBookedMinutes += bu.BookedMinutes
+		sum.AnnyIdleMinutes += bu.IdleMinutes
+		if bu.Exams == 0 {
+			sum.AnnyUnusedBookings++
+		}
+	}
+	sort.SliceStable(out.AnnyBookings, func(i, j int) bool {
+		if !out.AnnyBookings[i].From.Equal(out.AnnyBookings[j].From) {
+			return out.AnnyBookings[i].From.Before(out.AnnyBookings[j].From)
+		}
+		return out.AnnyBookings[i].Room < out.AnnyBookings[j].Room
+	})
+	sum.AnnyIdleShare = Percent(sum.AnnyIdleMinutes, sum.AnnyBookedMinutes)
+
+	return out
+}
+
+// bookingUsage measures one booking: the minutes covered by an exam in the booked room
+// (the union of the exams' windows clipped to the booking), the exams and the most
+// students at one start time.
+func bookingUsage(b Booking, uses []Use) *model.AnnyBookingUsage {
+	type window struct{ from, until time.Time }
+	var windows []window
+	exams := make(map[int]bool)
+	studentsAt := make(map[int64]int)
+	for _, u := range uses {
+		if u.Room != b.Room || u.Reserve {
+			continue
+		}
+		from, until := u.Start, u.Start.Add(time.Duration(u.Duration)*time.Minute)
+		if !from.Before(b.Until) || !until.After(b.From) {
+			continue
+		}
+		if from.Before(b.From) {
+			from = b.From
+		}
+		if until.After(b.Until) {
+			until = b.Until
+		}
+		windows = append(windows, window{from, until})
+		exams[u.Ancode] = true
+		studentsAt[u.Start.Unix()] += u.Students
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].from.Before(windows[j].from) })
+	used := time.Duration(0)
+	var cur *window
+	for i := range windows {
+		w := windows[i]
+		switch {
+		case cur == nil:
+			cur = &w
+		case !w.from.After(cur.until):
+			if w.until.After(cur.until) {
+				cur.until = w.until
+			}
+		default:
+			used += cur.until.Sub(cur.from)
+			cur = &w
+		}
+	}
+	if cur != nil {
+		used += cur.until.Sub(cur.from)
+	}
+	maxStudents := 0
+	for _, n := range studentsAt {
+		if n > maxStudents {
+			maxStudents = n
+		}
+	}
+	booked := int(b.Until.Sub(b.From).Minutes())
+	usedMinutes := int(used.Minutes())
+	return &model.AnnyBookingUsage{
+		Room: b.Room, From: b.From, Until: b.Until, BookedMinutes: booked, UsedMinutes: usedMinutes,
+		IdleMinutes: booked - usedMinutes, Exams: len(exams), MaxStudents: maxStudents,
+	}
+}
+
+func roomInfo(rooms map[string]Room, name string) Room {
+	if r, ok := rooms[name]; ok {
+		return r
+	}
+	return Room{RequestWith: model.RoomRequestTypeNone}
+}
+
+// Percent returns part/total in percent (0 for an empty total).
+func Percent(part, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return 100 * float64(part) / float64(total)
+}
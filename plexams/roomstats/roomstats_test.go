@@ -0,0 +1,90 @@
+package roomstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+func TestCompute(t *testing.T) {
+	s0 := time.Date(2026, 7, 13, 8, 30, 0, 0, time.Local)
+	s1 := time.Date(2026, 7, 13, 10, 30, 0, 0, time.Local)
+	rooms := map[string]Room{
+		"A": {Seats: 10, RequestWith: model.RoomRequestTypeNone},
+		"B": {Seats: 20, RequestWith: model.RoomRequestTypeAnny},
+	}
+	uses := []Use{
+		{Room: "A", Start: s0, Duration: 90, Ancode: 1, Students: 4},
+		{Room: "A", Start: s0, Duration: 60, Ancode: 2, Students: 3}, // shares A at s0
+		{Room: "B", Start: s0, Duration: 90, Ancode: 1, Students: 10},
+		{Room: "B", Start: s0, Ancode: 2, Reserve: true}, // B is used anyway
+		{Room: "A", Start: s1, Ancode: 3, Reserve: true},
+		{Room: "B", Start: s1, Duration: 60, Ancode: 3, Students: 5},
+	}
+	bookings := []Booking{
+		{Room: "B", From: s0, Until: s0.Add(4 * time.Hour)},
+		{Room: "B", From: s0.Add(24 * time.Hour), Until: s0.Add(26 * time.Hour)},
+	}
+
+	got := Compute("2026-SS", rooms, uses, bookings)
+	sum := got.Summary
+	if sum.Exams != 3 || sum.SlotsUsed != 2 || sum.RoomsUsed != 2 || sum.RoomSlots != 3 {
+		t.Errorf("counts = %d exams, %d slots, %d rooms, %d room slots", sum.Exams, sum.SlotsUsed, sum.RoomsUsed, sum.RoomSlots)
+	}
+	if sum.SeatsAvailable != 50 || sum.SeatsUsed != 22 || sum.ReserveRoomSlots != 1 {
+		t.Errorf("seats = %d/%d, reserve = %d; want 22/50, 1", sum.SeatsUsed, sum.SeatsAvailable, sum.ReserveRoomSlots)
+	}
+	if sum.Utilization != 44 {
+		t.Errorf("utilization = %v, want 44", sum.Utilization)
+	}
+	if sum.PeakStudents != 17 || sum.PeakStarttime == nil || !sum.PeakStarttime.Equal(s0) {
+		t.Errorf("peak = %d at %v, want 17 at s0", sum.PeakStudents, sum.PeakStarttime)
+	}
+
+	if len(got.Cells) != 3 || got.Cells[0].Room != "A" || got.Cells[0].Exams != 2 || got.Cells[0].Students != 7 || got.Cells[2].Starttime != s1 {
+		t.Errorf("cells not merged or sorted: %+v", got.Cells)
+	}
+	if got.Rooms[0].Room != "B" || got.Rooms[0].SlotsUsed != 2 || got.Rooms[0].SeatsUsed != 15 {
+		t.Errorf("rooms[0] = %+v, want B with 2 slots", got.Rooms[0])
+	}
+
+	kinds := map[model.RoomRequestType]*model.RoomKindShare{}
+	for _, k := range sum.ByKind {
+		kinds[k.RequestWith] = k
+	}
+	if len(sum.ByKind) != len(model.AllRoomRequestType) || kinds[model.RoomRequestTypeAnny].SeatsUsed != 15 || kinds[model.RoomRequestTypeNone].Rooms != 1 {
+		t.Errorf("byKind = %+v", sum.ByKind)
+	}
+	if share := kinds[model.RoomRequestTypeNone].Share + kinds[model.RoomRequestTypeAnny].Share; share < 99.99 || share > 100.01 {
+		t.Errorf("shares add up to %v", share)
+	}
+
+	// booking 1: 08:30–10:00 and 10:30–11:30 used of 240 min; booking 2 unused
+	if sum.AnnyBookings != 2 || sum.AnnyBookedMinutes != 360 || sum.AnnyIdleMinutes != 210 || sum.AnnyUnusedBookings != 1 {
+		t.Errorf("anny = %d bookings, %d booked, %d idle, %d unused", sum.AnnyBookings, sum.AnnyBookedMinutes, sum.AnnyIdleMinutes, sum.AnnyUnusedBookings)
+	}
+	if b := got.AnnyBookings[0]; b.UsedMinutes != 150 || b.Exams != 2 || b.MaxStudents != 10 {
+		t.Errorf("booking = %+v", b)
+	}
+}
+
+func TestBookingUsageClipsAndMerges(t *testing.T) {
+	s := time.Date(2026, 7, 13, 8, 0, 0, 0, time.Local)
+	uses := []Use{
+		{Room: "B", Start: s.Add(-30 * time.Minute), Duration: 60, Ancode: 1, Students: 2}, // 30 min inside
+		{Room: "B", Start: s.Add(15 * time.Minute), Duration: 60, Ancode: 2, Students: 2},  // overlaps the first
+		{Room: "C", Start: s, Duration: 60, Ancode: 3, Students: 9},                        // other room
+	}
+	got := bookingUsage(Booking{Room: "B", From: s, Until: s.Add(time.Hour)}, uses)
+	if got.UsedMinutes != 60 || got.IdleMinutes != 0 || got.Exams != 2 {
+		t.Errorf("got %+v, want 60 used, 0 idle, 2 exams", got)
+	}
+}
+
+func TestComputeEmpty(t *testing.T) {
+	got := Compute("2026-SS", nil, nil, nil)
+	if got.Summary.Utilization != 0 || got.Summary.PeakStarttime != nil || len(got.Summary.ByKind) != len(model.AllRoomRequestType) {
+		t.Errorf("empty = %+v", got.Summary)
+	}
+}
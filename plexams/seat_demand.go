@@ -0,0 +1,131 @@
+package plexams
+
+import (
+	"context"
+	"sort"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/noshow"
+	"github.com/obcode/plexams.go/plexams/roomcalc"
+)
+
+// Room sizing by expected attendance (see noshow): the rooms of an exam are sized for the
+// registered students minus the no-show rate of previous semesters plus a safety margin,
+// unless the exam's room constraints set the expected attendance explicitly.
+
+const (
+	defaultRoomSizingMargin    = 5
+	defaultRoomSizingSemesters = 2
+)
+
+// noShowHistory collects the exam protocols of the previous semesters (up to semesters
+// back; workspaces that do not exist are skipped) and returns the history with the
+// workspaces it is based on.
+func (p *Plexams) noShowHistory(ctx context.Context, semesters int) (*noshow.History, []string, error) {
+	records := make([]noshow.Record, 0)
+	workspaces := p.previousWorkspaces(ctx, semesters)
+	for _, db := range workspaces {
+		protocols, err := p.dbClient.ExamProtocolsForDatabase(ctx, db)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, pr := range protocols {
+			records = append(records, noshow.Record{Module: pr.Module, MainExamer: pr.MainExamer, Present: pr.Present, Absent: pr.Absent})
+		}
+	}
+	return noshow.NewHistory(records), workspaces, nil
+}
+
+// seatDemandOf predicts the seat demand of an exam: its students in normal rooms (NTAs
+// alone in a room excluded, additional seats included) at the override or predicted
+// attendance.
+func seatDemandOf(history *noshow.History, margin int, exam *model.PlannedExam) *model.SeatDemand {
+	normalRegs, ntasNormal, _ := roomcalc.ExamRegsAndNTAs(exam)
+	registered := len(normalRegs) + len(ntasNormal)
+	d := &model.SeatDemand{Ancode: exam.Ancode}
+	if exam.ZpaExam != nil {
+		d.Module, d.MainExamer = exam.ZpaExam.Module, exam.ZpaExam.MainExamer
+	}
+	if c := exam.Constraints; c != nil && c.RoomConstraints != nil {
+		if c.RoomConstraints.AdditionalSeats != nil {
+			registered += *c.RoomConstraints.AdditionalSeats
+		}
+		d.Override = c.RoomConstraints.ExpectedAttendance
+	}
+	prediction := history.Predict(d.Module, d.MainExamer)
+	d.Registered = registered
+	d.NoShowRate = prediction.Rate
+	d.Basis = model.NoShowBasis(prediction.Basis)
+	d.BasedOnStudents = prediction.Students
+	d.Attendance = noshow.Attendance(prediction.Rate, margin)
+	if d.Override != nil {
+		d.Attendance = *d.Override
+	}
+	d.ExpectedSeats = noshow.ExpectedSeats(registered, d.Attendance)
+	return d
+}
+
+// SeatDemandPrediction predicts the seat demand of every exam planned by us.
+func (p *Plexams) SeatDemandPrediction(ctx context.Context) (*model.SeatDemandPrediction, error) {
+	cfg, err := p.GenerationConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	history, workspaces, err := p.noShowHistory(ctx, cfg.RoomSizingSemesters)
+	if err != nil {
+		return nil, err
+	}
+	exams, err := p.PlannedExams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prediction := &model.SeatDemandPrediction{
+		Mode: cfg.RoomSizing, Margin: cfg.RoomSizingMargin, Workspaces: workspaces, Exams: make([]*model.SeatDemand, 0, len(exams)),
+	}
+	for _, exam := range exams {
+		if exam.Constraints != nil && exam.Constraints.NotPlannedByMe {
+			continue
+		}
+		d := seatDemandOf(history, cfg.RoomSizingMargin, exam)
+		if d.Registered == 0 {
+			continue
+		}
+		prediction.Exams = append(prediction.Exams, d)
+		prediction.Registered += d.Registered
+		prediction.Expected += d.ExpectedSeats
+	}
+	sort.Slice(prediction.Exams, func(i, j int) bool { return prediction.Exams[i].Ancode < prediction.Exams[j].Ancode })
+	return prediction, nil
+}
+
+// roomSizingHistory returns the no-show history when the rooms are sized by expected
+// attendance, nil when they are sized by registrations.
+func (p *Plexams) roomSizingHistory(ctx context.Context, cfg *model.GenerationConfig) (*noshow.History, error) {
+	if cfg == nil || cfg.RoomSizing != model.RoomSizingModeExpectedAttendance {
+		return nil, nil
+	}
+	history, _, err := p.noShowHistory(ctx, cfg.RoomSizingSemesters)
+	return history, err
+}
+
+// expectedAttendance returns the attendance (percent) per ancode the rooms are sized
+// for, or nil when they are sized by registrations.
+func (p *Plexams) expectedAttendance(ctx context.Context) (map[int]int, error) {
+	cfg, err := p.GenerationConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	history, err := p.roomSizingHistory(ctx, cfg)
+	if err != nil || history == nil {
+		return nil, err
+	}
+	exams, err := p.PlannedExams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	attendance := make(map[int]int, len(exams))
+	for _, exam := range exams {
+		attendance[exam.Ancode] = seatDemandOf(history, cfg.RoomSizingMargin, exam).Attendance
+	}
+	return attendance, nil
+}
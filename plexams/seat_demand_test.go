@@ -0,0 +1,37 @@
+package plexams
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/noshow"
+)
+
+func TestSeatDemandOf(t *testing.T) {
+	regs := make([]*model.EnhancedStudentReg, 0, 40)
+	for k := 0; k < 40; k++ {
+		regs = append(regs, &model.EnhancedStudentReg{Mtknr: fmt.Sprintf("m%d", k)})
+	}
+	extra := 2
+	exam := &model.PlannedExam{
+		Ancode:       324,
+		ZpaExam:      &model.ZPAExam{AnCode: 324, Module: "Datenbanken", MainExamer: "Mustermann"},
+		PrimussExams: []*model.EnhancedPrimussExam{{StudentRegs: regs}},
+		Ntas:         []*model.NTA{{Mtknr: "m0", NeedsRoomAlone: true}},
+		Constraints:  &model.Constraints{RoomConstraints: &model.RoomConstraints{AdditionalSeats: &extra}},
+	}
+	history := noshow.NewHistory([]noshow.Record{{Module: "Datenbanken", Present: 40, Absent: 10}})
+
+	d := seatDemandOf(history, 5, exam)
+	// 39 normal students + 2 additional seats, 20 % no-shows + 5 points margin
+	if d.Registered != 41 || d.NoShowRate != 20 || d.Basis != model.NoShowBasisModule || d.Attendance != 85 || d.ExpectedSeats != 35 {
+		t.Errorf("predicted demand = %+v", d)
+	}
+
+	override := 95
+	exam.Constraints.RoomConstraints.ExpectedAttendance = &override
+	if d := seatDemandOf(history, 5, exam); d.Attendance != 95 || d.ExpectedSeats != 39 || d.Override == nil {
+		t.Errorf("overridden demand = %+v", d)
+	}
+}
@@ -0,0 +1,397 @@
+package plexams
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jszwec/csvutil"
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/csvgen"
+	"github.com/obcode/plexams.go/plexams/pdfgen"
+	"github.com/obcode/plexams.go/plexams/seatplan"
+	"github.com/rs/zerolog/log"
+)
+
+// Seating plans: the room plan decides the room of every student, the seating plan the
+// seat in it (see plexams/seatplan). Layouts are stored per room (global); rooms
+// without a layout get seatplan.DefaultLayout. The plans are derived from the planned
+// rooms on every request, so they always match the current room plan.
+
+var seatSpacings = map[model.SeatSpacing]seatplan.Spacing{
+	model.SeatSpacingNone:           seatplan.NoSpacing,
+	model.SeatSpacingEveryOtherSeat: seatplan.EveryOtherSeat,
+	model.SeatSpacingEveryOtherRow:  seatplan.EveryOtherRow,
+	model.SeatSpacingCheckerboard:   seatplan.Checkerboard,
+}
+
+// RoomLayouts returns the stored room layouts with their capacity.
+func (p *Plexams) RoomLayouts(ctx context.Context) ([]*model.RoomLayout, error) {
+	layouts, err := p.dbClient.RoomLayouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range layouts {
+		l.Capacity = toSeatLayout(l).Capacity()
+	}
+	return layouts, nil
+}
+
+// SetRoomLayout validates and stores the layout of a room. Errors on an unknown room,
+// an empty layout or positions outside it.
+func (p *Plexams) SetRoomLayout(ctx context.Context, input model.RoomLayoutInput) (*model.RoomLayout, error) {
+	room, err := p.dbClient.RoomByName(ctx, input.Room)
+	if err != nil || room == nil {
+		return nil, fmt.Errorf("room %s not found", input.Room)
+	}
+	if input.Rows < 1 || input.SeatsPerRow < 1 {
+		return nil, fmt.Errorf("a layout needs at least one row and one seat per row")
+	}
+	if _, ok := seatSpacings[input.Spacing]; !ok {
+		return nil, fmt.Errorf("unknown spacing %s", input.Spacing)
+	}
+	layout := &model.RoomLayout{
+		Room:        input.Room,
+		Rows:        input.Rows,
+		SeatsPerRow: input.SeatsPerRow,
+		Aisles:      []int{},
+		Spacing:     input.Spacing,
+	}
+	seen := make(map[int]bool)
+	for _, a := range input.Aisles {
+		if a < 1 || a >= input.SeatsPerRow {
+			return nil, fmt.Errorf("aisle after seat %d is outside the row (1–%d)", a, input.SeatsPerRow-1)
+		}
+		if !seen[a] {
+			seen[a] = true
+			layout.Aisles = append(layout.Aisles, a)
+		}
+	}
+	sort.Ints(layout.Aisles)
+	if layout.Blocked, err = seatPositions(input.Blocked, input.Rows, input.SeatsPerRow); err != nil {
+		return nil, err
+	}
+	if layout.Reserved, err = seatPositions(input.Reserved, input.Rows, input.SeatsPerRow); err != nil {
+		return nil, err
+	}
+	for _, r := range layout.Reserved {
+		for _, b := range layout.Blocked {
+			if *r == *b {
+				return nil, fmt.Errorf("seat %d/%d is both blocked and reserved", r.Row, r.Seat)
+			}
+		}
+	}
+	if err := p.dbClient.SetRoomLayout(ctx, layout); err != nil {
+		return nil, err
+	}
+	layout.Capacity = toSeatLayout(layout).Capacity()
+	return layout, nil
+}
+
+// RemoveRoomLayout removes the layout of a room. Errors if none exists.
+func (p *Plexams) RemoveRoomLayout(ctx context.Context, room string) (bool, error) {
+	removed, err := p.dbClient.RemoveRoomLayout(ctx, room)
+	if err != nil {
+		return false, err
+	}
+	if !removed {
+		return false, fmt.Errorf("no layout for room %s", room)
+	}
+	return true, nil
+}
+
+func seatPositions(input []*model.SeatPositionInput, rows, seatsPerRow int) ([]*model.SeatPosition, error) {
+	positions := make([]*model.SeatPosition, 0, len(input))
+	seen := make(map[model.SeatPosition]bool)
+	for _, in := range input {
+		pos := model.SeatPosition{Row: in.Row, Seat: in.Seat}
+		if pos.Row < 1 || pos.Row > rows || pos.Seat < 1 || pos.Seat > seatsPerRow {
+			return nil, fmt.Errorf("seat %d/%d is outside the layout (%d rows, %d seats)", pos.Row, pos.Seat, rows, seatsPerRow)
+		}
+		if !seen[pos] {
+			seen[pos] = true
+			positions = append(positions, &pos)
+		}
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Row != positions[j].Row {
+			return positions[i].Row < positions[j].Row
+		}
+		return positions[i].Seat < positions[j].Seat
+	})
+	return positions, nil
+}
+
+func toSeatLayout(l *model.RoomLayout) seatplan.Layout {
+	layout := seatplan.Layout{
+		Rows:        l.Rows,
+		SeatsPerRow: l.SeatsPerRow,
+		Aisles:      l.Aisles,
+		Blocked:     make(map[seatplan.Pos]bool, len(l.Blocked)),
+		Reserved:    make(map[seatplan.Pos]bool, len(l.Reserved)),
+		Spacing:     seatSpacings[l.Spacing],
+	}
+	for _, b := range l.Blocked {
+		layout.Blocked[seatplan.Pos{Row: b.Row, Seat: b.Seat}] = true
+	}
+	for _, r := range l.Reserved {
+		layout.Reserved[seatplan.Pos{Row: r.Row, Seat: r.Seat}] = true
+	}
+	return layout
+}
+
+// seatingData is everything needed to compute seating plans, loaded once.
+type seatingData struct {
+	layouts  map[string]*model.RoomLayout
+	seats    map[string]int // room → seats (for the default layout)
+	students map[string]*model.Student
+	planned  []*model.PlannedRoom
+}
+
+func (p *Plexams) loadSeatingData(ctx context.Context) (*seatingData, error) {
+	layouts, err := p.dbClient.RoomLayouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := p.dbClient.Rooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	students, err := p.StudentRegsPerStudentPlanned(ctx)
+	if err != nil {
+		return nil, err
+	}
+	planned, err := p.dbClient.PlannedRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data := &seatingData{
+		layouts:  make(map[string]*model.RoomLayout, len(layouts)),
+		seats:    make(map[string]int, len(rooms)),
+		students: make(map[string]*model.Student, len(students)),
+		planned:  planned,
+	}
+	for _, l := range layouts {
+		data.layouts[l.Room] = l
+	}
+	for _, r := range rooms {
+		data.seats[r.Name] = r.Seats
+	}
+	for _, s := range students {
+		data.students[s.Mtknr] = s
+	}
+	return data, nil
+}
+
+type seatingKey struct {
+	room  string
+	start int64
+}
+
+// slots groups the planned rooms by room and start time (reserve rooms are skipped),
+// sorted by start time, then room.
+func (d *seatingData) slots() ([]seatingKey, map[seatingKey][]*model.PlannedRoom) {
+	byKey := make(map[seatingKey][]*model.PlannedRoom)
+	keys := make([]seatingKey, 0)
+	for _, pr := range d.planned {
+		if pr.Reserve || pr.Starttime == nil || len(pr.StudentsInRoom) == 0 {
+			continue
+		}
+		k := seatingKey{pr.RoomName, pr.Starttime.Unix()}
+		if _, ok := byKey[k]; !ok {
+			keys = append(keys, k)
+		}
+		byKey[k] = append(byKey[k], pr)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].start != keys[j].start {
+			return keys[i].start < keys[j].start
+		}
+		return keys[i].room < keys[j].room
+	})
+	return keys, byKey
+}
+
+// plan seats the students of the planned rooms of one room and start time.
+func (d *seatingData) plan(room string, start time.Time, planned []*model.PlannedRoom) *model.SeatingPlan {
+	stored, ok := d.layouts[room]
+	var layout seatplan.Layout
+	if ok {
+		layout = toSeatLayout(stored)
+	} else {
+		layout = seatplan.DefaultLayout(d.seats[room])
+	}
+
+	byAncode := make(map[int]*seatplan.Group)
+	ancodes := make([]int, 0)
+	for _, pr := range planned {
+		g, ok := byAncode[pr.Ancode]
+		if !ok {
+			g = &seatplan.Group{Ancode: pr.Ancode}
+			byAncode[pr.Ancode] = g
+			ancodes = append(ancodes, pr.Ancode)
+		}
+		for _, mtknr := range pr.StudentsInRoom {
+			st := seatplan.Student{Mtknr: mtknr}
+			if s, ok := d.students[mtknr]; ok {
+				st.Name, st.NTA = s.Name, s.Nta != nil
+			}
+			g.Students = append(g.Students, st)
+		}
+	}
+	sort.Ints(ancodes)
+	groups := make([]seatplan.Group, 0, len(ancodes))
+	for _, a := range ancodes {
+		groups = append(groups, *byAncode[a])
+	}
+
+	sp := seatplan.Assign(layout, groups)
+	plan := &model.SeatingPlan{
+		Room:            room,
+		Starttime:       start,
+		Ancodes:         ancodes,
+		Rows:            layout.Rows,
+		SeatsPerRow:     layout.SeatsPerRow,
+		Aisles:          layout.Aisles,
+		Blocked:         []*model.SeatPosition{},
+		Reserved:        []*model.SeatPosition{},
+		DefaultLayout:   !ok,
+		Seats:           make([]*model.SeatAssignment, 0, len(sp.Seats)),
+		Unseated:        make([]*model.SeatAssignment, 0, len(sp.Unseated)),
+		MixingConflicts: sp.MixingConflicts,
+	}
+	if plan.Aisles == nil {
+		plan.Aisles = []int{}
+	}
+	if ok {
+		plan.Blocked, plan.Reserved = stored.Blocked, stored.Reserved
+	}
+	for _, s := range sp.Seats {
+		plan.Seats = append(plan.Seats, seatAssignment(s))
+	}
+	for _, s := range sp.Unseated {
+		plan.Unseated = append(plan.Unseated, seatAssignment(s))
+	}
+	return plan
+}
+
+func seatAssignment(s seatplan.Seat) *model.SeatAssignment {
+	return &model.SeatAssignment{
+		Row: s.Row, Seat: s.Seat, Ancode: s.Ancode,
+		Mtknr: s.Student.Mtknr, Name: s.Student.Name, Nta: s.Student.NTA, Reserved: s.Reserved,
+	}
+}
+
+// SeatingPlans returns the seating plans of all planned rooms (sorted by start time,
+// then room), restricted to the rooms of ancode if ancode > 0.
+func (p *Plexams) SeatingPlans(ctx context.Context, ancode int) ([]*model.SeatingPlan, error) {
+	data, err := p.loadSeatingData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys, byKey := data.slots()
+	plans := make([]*model.SeatingPlan, 0, len(keys))
+	for _, k := range keys {
+		planned := byKey[k]
+		if ancode > 0 && !plannedRoomsContain(planned, ancode) {
+			continue
+		}
+		plans = append(plans, data.plan(k.room, *planned[0].Starttime, planned))
+	}
+	return plans, nil
+}
+
+// SeatingPlan returns the seating plan of a room at a start time, nil if no students
+// are planned there.
+func (p *Plexams) SeatingPlan(ctx context.Context, room string, starttime time.Time) (*model.SeatingPlan, error) {
+	data, err := p.loadSeatingData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, byKey := data.slots()
+	planned, ok := byKey[seatingKey{room, starttime.Unix()}]
+	if !ok {
+		return nil, nil
+	}
+	return data.plan(room, *planned[0].Starttime, planned), nil
+}
+
+func plannedRoomsContain(planned []*model.PlannedRoom, ancode int) bool {
+	for _, pr := range planned {
+		if pr.Ancode == ancode {
+			return true
+		}
+	}
+	return false
+}
+
+// SeatingChartsZipBytes builds a ZIP with one seating chart PDF per room and start time.
+func (p *Plexams) SeatingChartsZipBytes(ctx context.Context) ([]byte, error) {
+	plans, err := p.SeatingPlans(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	modules, err := p.seatingModules(ctx, plans)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, plan := range plans {
+		data, err := marotoBytes(pdfgen.SeatingChart(p.semesterFull(), plan, modules))
+		if err != nil {
+			return nil, fmt.Errorf("seating chart %s %s: %w", plan.Room, plan.Starttime.Format("2006-01-02 15:04"), err)
+		}
+		f, err := zw.Create(fmt.Sprintf("Sitzplan_%s_%s.pdf", plan.Starttime.Local().Format("2006-01-02_1504"), plan.Room))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// seatingModules maps the ancodes of the plans to "ancode. module (examer)" labels.
+func (p *Plexams) seatingModules(ctx context.Context, plans []*model.SeatingPlan) (map[int]string, error) {
+	modules := make(map[int]string)
+	for _, plan := range plans {
+		for _, ancode := range plan.Ancodes {
+			if _, ok := modules[ancode]; ok {
+				continue
+			}
+			exam, err := p.dbClient.GetZpaExamByAncode(ctx, ancode)
+			if err != nil || exam == nil {
+				modules[ancode] = fmt.Sprintf("%d.", ancode)
+				continue
+			}
+			modules[ancode] = fmt.Sprintf("%d. %s (%s)", ancode, exam.Module, exam.MainExamer)
+		}
+	}
+	return modules, nil
+}
+
+// CsvSeatingBytes builds the examiners' seat list CSV (all exams, or only ancode).
+func (p *Plexams) CsvSeatingBytes(ctx context.Context, ancode int) ([]byte, error) {
+	plans, err := p.SeatingPlans(ctx, ancode)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]csvgen.SeatingRow, 0)
+	for _, plan := range plans {
+		rows = append(rows, csvgen.SeatingRows(plan, ancode)...)
+	}
+	b, err := csvutil.Marshal(rows)
+	if err != nil {
+		log.Error().Err(err).Msg("error when marshaling to csv")
+		return nil, err
+	}
+	return b, nil
+}
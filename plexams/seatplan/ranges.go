@@ -0,0 +1,130 @@
+package seatplan
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Name ranges: an exam split across several rooms gets one alphabetical range per room
+// ("A–K → R1.006, L–Z → R1.046") for the door notices. Rebalance moves the exam's
+// students between its rooms so that the ranges are contiguous; every room keeps its
+// number of the exam's students (so no capacity changes) and pinned students (NTAs
+// planned into a specific room) stay where they are and are left out of the ranges.
+
+// Split is one room of a split exam.
+type Split struct {
+	Room     string
+	Students []Student // all of the exam's students in the room
+}
+
+// Range is the alphabetical range of one room. From/To are empty if the room holds only
+// pinned students.
+type Range struct {
+	Room     string
+	From, To string
+	Count    int // students in the range
+	Pinned   int // pinned students in the room, outside the range
+}
+
+var nameFolds = strings.NewReplacer("ä", "a", "ö", "o", "ü", "u", "ß", "ss",
+	"á", "a", "à", "a", "é", "e", "è", "e", "í", "i", "ó", "o", "ú", "u", "ç", "c", "ñ", "n")
+
+// NameKey is the sort and range key of a name: lower case, umlauts and common accents
+// folded (Ä sorts with A, as on the door notices), leading and trailing blanks removed.
+func NameKey(name string) string {
+	return nameFolds.Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// Rebalance redistributes the unpinned students of the splits over the rooms (in the
+// given order) by name, keeping every room's count; pinned reports whether a student
+// must stay in its room. It returns the new splits.
+func Rebalance(splits []Split, pinned func(Student) bool) []Split {
+	free := make([]Student, 0)
+	quota := make([]int, len(splits))
+	out := make([]Split, len(splits))
+	for i, sp := range splits {
+		out[i].Room = sp.Room
+		for _, s := range sp.Students {
+			if pinned(s) {
+				out[i].Students = append(out[i].Students, s)
+			} else {
+				free = append(free, s)
+				quota[i]++
+			}
+		}
+	}
+	sort.SliceStable(free, func(i, j int) bool { return lessStudent(free[i], free[j]) })
+	next := 0
+	for i := range out {
+		out[i].Students = append(out[i].Students, free[next:next+quota[i]]...)
+		next += quota[i]
+	}
+	return out
+}
+
+// Ranges derives the range of every split from its unpinned students. The rooms with a
+// range are ordered by their first name; contiguous reports whether the ranges do not
+// overlap (always true after Rebalance). Rooms with only pinned students come last.
+func Ranges(splits []Split, pinned func(Student) bool) (ranges []Range, contiguous bool) {
+	type span struct {
+		r           Range
+		first, last string
+	}
+	spans := make([]span, 0, len(splits))
+	var empty []Range
+	for _, sp := range splits {
+		r := Range{Room: sp.Room}
+		var keys []string
+		for _, s := range sp.Students {
+			if pinned(s) {
+				r.Pinned++
+				continue
+			}
+			keys = append(keys, NameKey(s.Name))
+		}
+		r.Count = len(keys)
+		if len(keys) == 0 {
+			empty = append(empty, r)
+			continue
+		}
+		sort.Strings(keys)
+		spans = append(spans, span{r, keys[0], keys[len(keys)-1]})
+	}
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].first < spans[j].first })
+
+	contiguous = true
+	for i := range spans {
+		from, to := "A", "Z"
+		if i > 0 {
+			from = distinguishingPrefix(spans[i].first, spans[i-1].last)
+			if spans[i].first <= spans[i-1].last {
+				contiguous = false
+			}
+		}
+		if i < len(spans)-1 {
+			to = distinguishingPrefix(spans[i].last, spans[i+1].first)
+		}
+		spans[i].r.From, spans[i].r.To = from, to
+		ranges = append(ranges, spans[i].r)
+	}
+	return append(ranges, empty...), contiguous
+}
+
+// distinguishingPrefix is the shortest prefix of key that is not a prefix of other,
+// capitalised ("ke" for "keller" next to "kaiser"); the whole key if there is none.
+func distinguishingPrefix(key, other string) string {
+	k, o := []rune(key), []rune(other)
+	n := len(k)
+	for i := range k {
+		if i >= len(o) || k[i] != o[i] {
+			n = i + 1
+			break
+		}
+	}
+	prefix := k[:n]
+	if len(prefix) > 0 {
+		prefix[0] = unicode.ToUpper(prefix[0])
+	}
+	return string(prefix)
+}
@@ -0,0 +1,58 @@
+package seatplan
+
+import (
+	"reflect"
+	"testing"
+)
+
+func named(names ...string) []Student {
+	out := make([]Student, len(names))
+	for i, n := range names {
+		out[i] = Student{Mtknr: n, Name: n}
+	}
+	return out
+}
+
+func TestRebalanceMakesRangesContiguous(t *testing.T) {
+	nta := Student{Mtknr: "n", Name: "Zander", NTA: true}
+	splits := []Split{
+		{Room: "R1.006", Students: append(named("Lang", "Adam", "Müller"), nta)},
+		{Room: "R1.046", Students: named("Kaiser", "Keller", "Becker")},
+	}
+	pinned := func(s Student) bool { return s.NTA }
+
+	if _, contiguous := Ranges(splits, pinned); contiguous {
+		t.Fatal("initial assignment must not be contiguous")
+	}
+
+	balanced := Rebalance(splits, pinned)
+	for i := range splits {
+		if len(balanced[i].Students) != len(splits[i].Students) {
+			t.Errorf("%s: %d students, want %d", splits[i].Room, len(balanced[i].Students), len(splits[i].Students))
+		}
+	}
+	if balanced[0].Students[0] != nta {
+		t.Errorf("NTA must stay in R1.006: %v", balanced[0].Students)
+	}
+
+	ranges, contiguous := Ranges(balanced, pinned)
+	if !contiguous {
+		t.Fatalf("rebalanced ranges must be contiguous: %v", ranges)
+	}
+	want := []Range{
+		{Room: "R1.006", From: "A", To: "Ka", Count: 3, Pinned: 1},
+		{Room: "R1.046", From: "Ke", To: "Z", Count: 3},
+	}
+	if !reflect.DeepEqual(ranges, want) {
+		t.Errorf("Ranges =\n%+v\nwant\n%+v", ranges, want)
+	}
+}
+
+func TestNameKeyFoldsUmlauts(t *testing.T) {
+	if NameKey(" Öztürk") != "ozturk" {
+		t.Errorf("NameKey = %q", NameKey(" Öztürk"))
+	}
+	if got := distinguishingPrefix("muller", "mahler"); got != "Mu" {
+		t.Errorf("distinguishingPrefix = %q, want Mu", got)
+	}
+}
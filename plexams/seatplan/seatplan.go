@@ -0,0 +1,264 @@
+// Package seatplan assigns the students of a room (one or more exams sharing it in a
+// slot) to concrete seats. The room plan (plexams/roomplan) only decides the room; this
+// package turns a room layout — rows, seats per row, aisles, blocked seats, a spacing
+// pattern and seats reserved for NTAs/handicap needs — plus the students per exam into
+// a seat per student. It is DB-agnostic; the plexams package builds the input from the
+// planned rooms and renders the result (pdfgen, CSV).
+//
+// Rules: NTA students take the reserved seats first (then ordinary seats); the other
+// seats are filled front to back, left to right, each exam's students in name order.
+// When exams share a room they are mixed so that neighbours (the nearest usable seat to
+// the left and in front) belong to a different exam wherever the remaining counts allow it.
+package seatplan
+
+import (
+	"sort"
+)
+
+// Spacing is the exam-spacing pattern that leaves seats empty between students.
+type Spacing int
+
+const (
+	NoSpacing      Spacing = iota
+	EveryOtherSeat         // seats 1, 3, 5, … in every row
+	EveryOtherRow          // rows 1, 3, 5, …
+	Checkerboard           // odd seats in odd rows, even seats in even rows
+)
+
+// Pos is a seat position, 1-based (row 1 is the front row, seat 1 the leftmost).
+type Pos struct {
+	Row, Seat int
+}
+
+// Layout is the seating layout of a room. Aisles lists the seat numbers an aisle
+// follows (e.g. 6 for an aisle between seat 6 and 7); it is used for rendering only.
+type Layout struct {
+	Rows, SeatsPerRow int
+	Aisles            []int
+	Blocked           map[Pos]bool
+	Reserved          map[Pos]bool // only for NTA students; exempt from the spacing
+	Spacing           Spacing
+}
+
+// DefaultLayout is the fallback for a room without a stored layout: rows of ten seats
+// holding the room's exam capacity, without further spacing (the capacity of a room
+// already is its exam capacity).
+func DefaultLayout(seats int) Layout {
+	perRow := min(max(seats, 1), 10)
+	return Layout{Rows: (max(seats, 1) + perRow - 1) / perRow, SeatsPerRow: perRow}
+}
+
+// inPattern reports whether the spacing pattern leaves the seat usable.
+func (l Layout) inPattern(p Pos) bool {
+	switch l.Spacing {
+	case EveryOtherSeat:
+		return p.Seat%2 == 1
+	case EveryOtherRow:
+		return p.Row%2 == 1
+	case Checkerboard:
+		return p.Row%2 == p.Seat%2
+	default:
+		return true
+	}
+}
+
+// usable reports whether a student may sit on the seat: not blocked, and reserved or
+// left usable by the spacing pattern.
+func (l Layout) usable(p Pos) bool {
+	return p.Row >= 1 && p.Seat >= 1 && !l.Blocked[p] && (l.Reserved[p] || l.inPattern(p))
+}
+
+// neighbourSeats returns the nearest usable seat to the left in the same row and the
+// nearest usable seat in front in the same column (under a spacing pattern the adjacent
+// seats are empty, the next student sits beyond them).
+func (l Layout) neighbourSeats(p Pos) []Pos {
+	out := make([]Pos, 0, 2)
+	for s := p.Seat - 1; s >= 1; s-- {
+		if q := (Pos{p.Row, s}); l.usable(q) {
+			out = append(out, q)
+			break
+		}
+	}
+	for r := p.Row - 1; r >= 1; r-- {
+		if q := (Pos{r, p.Seat}); l.usable(q) {
+			out = append(out, q)
+			break
+		}
+	}
+	return out
+}
+
+// Seats returns the usable ordinary seats (front to back, left to right) and the
+// usable reserved seats.
+func (l Layout) Seats() (ordinary, reserved []Pos) {
+	for r := 1; r <= l.Rows; r++ {
+		for s := 1; s <= l.SeatsPerRow; s++ {
+			p := Pos{r, s}
+			switch {
+			case l.Blocked[p]:
+			case l.Reserved[p]:
+				reserved = append(reserved, p)
+			case l.inPattern(p):
+				ordinary = append(ordinary, p)
+			}
+		}
+	}
+	return ordinary, reserved
+}
+
+// Capacity is the number of usable seats (ordinary plus reserved).
+func (l Layout) Capacity() int {
+	ordinary, reserved := l.Seats()
+	return len(ordinary) + len(reserved)
+}
+
+// Student is one student to seat.
+type Student struct {
+	Mtknr string
+	Name  string
+	NTA   bool
+}
+
+// Group is the students of one exam in the room.
+type Group struct {
+	Ancode   int
+	Students []Student
+}
+
+// Seat is one seated (or, in Plan.Unseated, unseated) student.
+type Seat struct {
+	Pos
+	Ancode   int
+	Student  Student
+	Reserved bool // on a reserved seat
+}
+
+// Plan is the seat assignment of a room.
+type Plan struct {
+	Layout   Layout
+	Seats    []Seat // sorted by position
+	Unseated []Seat // students without a seat (Pos is zero)
+	// MixingConflicts counts neighbouring pairs (nearest usable seat to the left or in
+	// front) of the same exam
+	// in a room shared by several exams; 0 when the exams could be fully mixed.
+	MixingConflicts int
+}
+
+// Assign seats the groups' students in the layout.
+func Assign(l Layout, groups []Group) *Plan {
+	plan := &Plan{Layout: l}
+	ordinary, reserved := l.Seats()
+
+	type pending struct {
+		ancode   int
+		students []Student
+	}
+	queues := make([]*pending, 0, len(groups))
+	var ntas []Seat
+	for _, g := range groups {
+		students := append([]Student(nil), g.Students...)
+		sort.SliceStable(students, func(i, j int) bool { return lessStudent(students[i], students[j]) })
+		q := &pending{ancode: g.Ancode}
+		for _, s := range students {
+			if s.NTA {
+				ntas = append(ntas, Seat{Ancode: g.Ancode, Student: s})
+			} else {
+				q.students = append(q.students, s)
+			}
+		}
+		queues = append(queues, q)
+	}
+
+	// NTAs: reserved seats first, the rest join their exam's queue (front of it)
+	for i, nta := range ntas {
+		if i < len(reserved) {
+			nta.Pos, nta.Reserved = reserved[i], true
+			plan.Seats = append(plan.Seats, nta)
+			continue
+		}
+		for _, q := range queues {
+			if q.ancode == nta.Ancode {
+				q.students = append([]Student{nta.Student}, q.students...)
+			}
+		}
+	}
+
+	byPos := make(map[Pos]int) // seat → ancode
+	for _, s := range plan.Seats {
+		byPos[s.Pos] = s.Ancode
+	}
+	for _, pos := range ordinary {
+		best := -1
+		for i, q := range queues {
+			if len(q.students) == 0 {
+				continue
+			}
+			if best < 0 || better(l, q.ancode, len(q.students), queues[best].ancode, len(queues[best].students), pos, byPos) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		q := queues[best]
+		plan.Seats = append(plan.Seats, Seat{Pos: pos, Ancode: q.ancode, Student: q.students[0]})
+		byPos[pos] = q.ancode
+		q.students = q.students[1:]
+	}
+	for _, q := range queues {
+		for _, s := range q.students {
+			plan.Unseated = append(plan.Unseated, Seat{Ancode: q.ancode, Student: s})
+		}
+	}
+
+	sort.Slice(plan.Seats, func(i, j int) bool {
+		a, b := plan.Seats[i].Pos, plan.Seats[j].Pos
+		if a.Row != b.Row {
+			return a.Row < b.Row
+		}
+		return a.Seat < b.Seat
+	})
+	if len(groups) > 1 {
+		for _, s := range plan.Seats {
+			plan.MixingConflicts += neighbours(l, s.Ancode, s.Pos, byPos)
+		}
+	}
+	return plan
+}
+
+// better reports whether exam a (with na students left) should take the seat rather
+// than exam b: an exam not sitting next to (left of / in front of) the seat wins, then
+// the one with more students left, then the lower ancode.
+func better(l Layout, a, na, b, nb int, pos Pos, byPos map[Pos]int) bool {
+	ca, cb := neighbours(l, a, pos, byPos), neighbours(l, b, pos, byPos)
+	if ca != cb {
+		return ca < cb
+	}
+	if na != nb {
+		return na > nb
+	}
+	return a < b
+}
+
+// neighbours counts the neighbouring seats (see Layout.neighbourSeats) taken by ancode.
+func neighbours(l Layout, ancode int, pos Pos, byPos map[Pos]int) int {
+	n := 0
+	for _, q := range l.neighbourSeats(pos) {
+		if a, ok := byPos[q]; ok && a == ancode {
+			n++
+		}
+	}
+	return n
+}
+
+// lessStudent orders students by NameKey, then Mtknr; students without a name go last.
+func lessStudent(a, b Student) bool {
+	an, bn := NameKey(a.Name), NameKey(b.Name)
+	if (an == "") != (bn == "") {
+		return bn == ""
+	}
+	if an != bn {
+		return an < bn
+	}
+	return a.Mtknr < b.Mtknr
+}
@@ -0,0 +1,89 @@
+package seatplan
+
+import "testing"
+
+func students(prefix string, n int) []Student {
+	out := make([]Student, n)
+	for i := range out {
+		out[i] = Student{Mtknr: prefix + string(rune('0'+i)), Name: prefix + string(rune('a'+i))}
+	}
+	return out
+}
+
+func TestSpacingPatterns(t *testing.T) {
+	for _, tc := range []struct {
+		spacing Spacing
+		want    int
+	}{
+		{NoSpacing, 24},
+		{EveryOtherSeat, 12},
+		{EveryOtherRow, 12},
+		{Checkerboard, 12},
+	} {
+		l := Layout{Rows: 4, SeatsPerRow: 6, Spacing: tc.spacing}
+		if got := l.Capacity(); got != tc.want {
+			t.Errorf("spacing %d: capacity %d, want %d", tc.spacing, got, tc.want)
+		}
+	}
+
+	l := Layout{Rows: 2, SeatsPerRow: 2, Blocked: map[Pos]bool{{1, 1}: true}}
+	if got := l.Capacity(); got != 3 {
+		t.Errorf("blocked seat must not count, capacity %d", got)
+	}
+}
+
+func TestNTATakesReservedSeat(t *testing.T) {
+	l := Layout{Rows: 2, SeatsPerRow: 3, Reserved: map[Pos]bool{{2, 3}: true}}
+	group := Group{Ancode: 1, Students: append(students("s", 3), Student{Mtknr: "n", Name: "nta", NTA: true})}
+	plan := Assign(l, []Group{group})
+	if len(plan.Unseated) != 0 {
+		t.Fatalf("unexpected unseated %v", plan.Unseated)
+	}
+	for _, s := range plan.Seats {
+		if s.Student.NTA != (s.Pos == Pos{2, 3}) || s.Student.NTA != s.Reserved {
+			t.Errorf("NTA must sit on the reserved seat, got %+v", s)
+		}
+	}
+	if first := plan.Seats[0]; first.Student.Name != "sa" {
+		t.Errorf("students are seated in name order, first is %q", first.Student.Name)
+	}
+}
+
+func TestSharedRoomIsMixed(t *testing.T) {
+	for _, spacing := range []Spacing{NoSpacing, EveryOtherSeat, EveryOtherRow, Checkerboard} {
+		l := Layout{Rows: 4, SeatsPerRow: 6, Spacing: spacing}
+		half := l.Capacity() / 2
+		plan := Assign(l, []Group{{Ancode: 1, Students: students("a", half)}, {Ancode: 2, Students: students("b", half)}})
+		if plan.MixingConflicts != 0 {
+			t.Errorf("spacing %d: two equal exams should be fully mixed, %d conflicts", spacing, plan.MixingConflicts)
+		}
+		for _, s := range plan.Seats {
+			for _, q := range l.neighbourSeats(s.Pos) {
+				for _, o := range plan.Seats {
+					if o.Pos == q && o.Ancode == s.Ancode {
+						t.Errorf("spacing %d: %v and %v both sit exam %d", spacing, s.Pos, q, s.Ancode)
+					}
+				}
+			}
+		}
+
+		// an exam with far more students cannot be mixed: the conflicts must show
+		plan = Assign(l, []Group{{Ancode: 1, Students: students("a", l.Capacity()-2)}, {Ancode: 2, Students: students("b", 2)}})
+		if plan.MixingConflicts == 0 {
+			t.Errorf("spacing %d: unbalanced exams reported as fully mixed", spacing)
+		}
+	}
+
+	// a blocked seat is skipped: the next usable seat is the neighbour
+	l := Layout{Rows: 1, SeatsPerRow: 5, Spacing: EveryOtherSeat, Blocked: map[Pos]bool{{1, 3}: true}}
+	if got := l.neighbourSeats(Pos{1, 5}); len(got) != 1 || got[0] != (Pos{1, 1}) {
+		t.Errorf("neighbours of 1/5 = %v, want [1/1]", got)
+	}
+}
+
+func TestUnseatedWhenFull(t *testing.T) {
+	plan := Assign(Layout{Rows: 1, SeatsPerRow: 2}, []Group{{Ancode: 1, Students: students("a", 3)}})
+	if len(plan.Seats) != 2 || len(plan.Unseated) != 1 {
+		t.Fatalf("expected 2 seated, 1 unseated, got %d/%d", len(plan.Seats), len(plan.Unseated))
+	}
+}
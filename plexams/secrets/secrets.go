@@ -6,8 +6,11 @@ package secrets
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -30,6 +33,8 @@ type SealedValue struct {
 // *Sealer means "no key configured" and every operation fails closed (ErrNoKey).
 type Sealer struct {
 	gcm cipher.AEAD
+	// pseudonymKey is derived from the KEK (never the KEK itself), see Pseudonym.
+	pseudonymKey []byte
 }
 
 // ErrNoKey is returned when a seal/open is attempted without a configured KEK.
@@ -57,7 +62,21 @@ func NewSealer(keyB64 string) (*Sealer, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Sealer{gcm: gcm}, nil
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("plexams pseudonym v1"))
+	return &Sealer{gcm: gcm, pseudonymKey: mac.Sum(nil)}, nil
+}
+
+// Pseudonym maps a personal identifier (e.g. a Matrikelnummer) to a stable pseudonym:
+// an HMAC-SHA256 with a key derived from the KEK. The same value always gets the same
+// pseudonym, but without the KEK it cannot be reversed by trying all identifiers.
+func (s *Sealer) Pseudonym(value string) (string, error) {
+	if s == nil {
+		return "", ErrNoKey
+	}
+	mac := hmac.New(sha256.New, s.pseudonymKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil)[:16]), nil
 }
 
 // Seal encrypts plaintext with a fresh random nonce.
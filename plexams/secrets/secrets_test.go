@@ -78,3 +78,32 @@ func TestBadKeyRejected(t *testing.T) {
 		t.Error("expected a length error for a 16-byte key")
 	}
 }
+
+func TestPseudonym(t *testing.T) {
+	key := randKeyB64(t)
+	s, err := NewSealer(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := s.Pseudonym("12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := s.Pseudonym("12345678"); a != b {
+		t.Errorf("pseudonym not stable: %s != %s", a, b)
+	}
+	if b, _ := s.Pseudonym("12345679"); a == b {
+		t.Error("different values got the same pseudonym")
+	}
+	other, _ := NewSealer(randKeyB64(t))
+	if b, _ := other.Pseudonym("12345678"); a == b {
+		t.Error("pseudonym does not depend on the key")
+	}
+	if len(a) != 32 {
+		t.Errorf("pseudonym %q: want 32 hex chars", a)
+	}
+	var none *Sealer
+	if _, err := none.Pseudonym("12345678"); err != ErrNoKey {
+		t.Errorf("nil sealer: err = %v, want ErrNoKey", err)
+	}
+}
@@ -0,0 +1,393 @@
+package plexams
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/examplan"
+	"github.com/obcode/plexams.go/plexams/invigplan"
+	"github.com/obcode/plexams.go/plexams/roomplan"
+	"github.com/obcode/plexams.go/plexams/softrule"
+)
+
+// timeRuleAttributes describe the start time the solver is evaluating (the candidate slot
+// of an exam, the fixed slot of a room use or an invigilation); time and date are
+// zero-padded so that string comparison orders them chronologically.
+var timeRuleAttributes = []softrule.Attribute{
+	{Name: "weekday", Type: softrule.TypeString, Description: "Wochentag des Termins: Mo, Di, Mi, Do, Fr, Sa, So"},
+	{Name: "date", Type: softrule.TypeString, Description: "Datum des Termins, z.B. \"2026-07-11\""},
+	{Name: "time", Type: softrule.TypeString, Description: "Beginn des Termins, z.B. \"16:00\""},
+	{Name: "hour", Type: softrule.TypeNumber, Description: "Beginn als Dezimalstunde, z.B. 16.5 für 16:30"},
+}
+
+// examRuleAttributes describe the exam of an exam-schedule or room-plan rule.
+var examRuleAttributes = []softrule.Attribute{
+	{Name: "ancode", Type: softrule.TypeNumber, Description: "Ancode der Prüfung"},
+	{Name: "module", Type: softrule.TypeString, Description: "Modulname"},
+	{Name: "examer", Type: softrule.TypeString, Description: "Name der/des Erstprüfenden (wie im ZPA)"},
+	{Name: "examType", Type: softrule.TypeString, Description: "Prüfungsform (wie im ZPA)"},
+	{Name: "program", Type: softrule.TypeString, Description: "erster Studiengang der Prüfung, z.B. \"IF\""},
+	{Name: "programs", Type: softrule.TypeStringList, Description: "alle Studiengänge der Prüfung, z.B. \"DC\" in programs"},
+	{Name: "seats", Type: softrule.TypeNumber, Description: "Anzahl Anmeldungen"},
+	{Name: "duration", Type: softrule.TypeNumber, Description: "Prüfungsdauer in Minuten"},
+	{Name: "ntas", Type: softrule.TypeNumber, Description: "Anzahl Studierende mit Nachteilsausgleich"},
+	{Name: "exahm", Type: softrule.TypeBool, Description: "EXaHM-Prüfung"},
+	{Name: "seb", Type: softrule.TypeBool, Description: "SEB-Prüfung"},
+	{Name: "repeater", Type: softrule.TypeBool, Description: "Wiederholungsprüfung"},
+	{Name: "location", Type: softrule.TypeString, Description: "Standort (leer = Standard)"},
+}
+
+// roomRuleAttributes describe the room of a room-plan rule.
+var roomRuleAttributes = []softrule.Attribute{
+	{Name: "room", Type: softrule.TypeString, Description: "Raumname, z.B. \"R1.046\""},
+	{Name: "roomSeats", Type: softrule.TypeNumber, Description: "Sitzplätze des Raums"},
+	{Name: "building", Type: softrule.TypeString, Description: "Gebäude des Raums laut Campus-Modell (leer = keins)"},
+	{Name: "ownRoom", Type: softrule.TypeBool, Description: "eigener Raum (nicht gebucht/angefragt)"},
+	{Name: "roomExahm", Type: softrule.TypeBool, Description: "EXaHM-Raum"},
+	{Name: "roomSeb", Type: softrule.TypeBool, Description: "SEB-Raum"},
+	{Name: "roomLab", Type: softrule.TypeBool, Description: "Labor"},
+	{Name: "roomHandicap", Type: softrule.TypeBool, Description: "Raum für Studierende mit Nachteilsausgleich"},
+}
+
+// invigilationRuleAttributes describe the invigilator and the invigilation of an
+// invigilation-plan rule.
+var invigilationRuleAttributes = []softrule.Attribute{
+	{Name: "invigilator", Type: softrule.TypeString, Description: "Kürzel der Aufsicht (wie im ZPA)"},
+	{Name: "invigilatorName", Type: softrule.TypeString, Description: "Name der Aufsicht (wie im ZPA)"},
+	{Name: "prof", Type: softrule.TypeBool, Description: "Aufsicht ist Professor:in"},
+	{Name: "lba", Type: softrule.TypeBool, Description: "Aufsicht ist Lehrbeauftragte:r"},
+	{Name: "staff", Type: softrule.TypeBool, Description: "Aufsicht ist Mitarbeiter:in"},
+	{Name: "partTime", Type: softrule.TypeNumber, Description: "Teilzeitfaktor der Aufsicht (1 = Vollzeit)"},
+	{Name: "ownExamDay", Type: softrule.TypeBool, Description: "die Aufsicht hat an dem Tag eine eigene Prüfung"},
+	{Name: "room", Type: softrule.TypeString, Description: "Raum der Aufsicht (leer = Reserve)"},
+	{Name: "reserve", Type: softrule.TypeBool, Description: "Reserveaufsicht"},
+	{Name: "nta", Type: softrule.TypeBool, Description: "Aufsicht in einem NTA-Raum"},
+	{Name: "minutes", Type: softrule.TypeNumber, Description: "Dauer der Aufsicht in Minuten"},
+	{Name: "campus", Type: softrule.TypeString, Description: "Campus des Raums (leer = Standard)"},
+}
+
+// softRuleSchemas lists, per target, the attributes a user-defined soft rule may use.
+var softRuleSchemas = map[model.SoftRuleTarget]softrule.Schema{
+	model.SoftRuleTargetExams:         ruleSchema(examRuleAttributes, timeRuleAttributes),
+	model.SoftRuleTargetRooms:         ruleSchema(examRuleAttributes, roomRuleAttributes, timeRuleAttributes),
+	model.SoftRuleTargetInvigilations: ruleSchema(invigilationRuleAttributes, timeRuleAttributes),
+}
+
+func ruleSchema(groups ...[]softrule.Attribute) softrule.Schema {
+	schema := make(softrule.Schema)
+	for _, attrs := range groups {
+		for _, a := range attrs {
+			schema[a.Name] = a
+		}
+	}
+	return schema
+}
+
+// softRuleTarget is the target of r; rules stored before targets existed schedule exams.
+func softRuleTarget(r *model.SoftRule) model.SoftRuleTarget {
+	if r.Target == "" {
+		return model.SoftRuleTargetExams
+	}
+	return r.Target
+}
+
+// SoftRuleAttributes returns the attributes available to soft rule conditions of the
+// given target, by name.
+func (p *Plexams) SoftRuleAttributes(target model.SoftRuleTarget) []*model.SoftRuleAttribute {
+	schema := softRuleSchemas[target]
+	out := make([]*model.SoftRuleAttribute, 0, len(schema))
+	for _, a := range schema {
+		out = append(out, &model.SoftRuleAttribute{Name: a.Name, Type: a.Type.String(), Description: a.Description})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// compiledSoftRule is an enabled soft rule with its parsed condition.
+type compiledSoftRule struct {
+	rule   *model.SoftRule
+	target model.SoftRuleTarget
+	cond   *softrule.Expr
+}
+
+// compileSoftRules parses the enabled rules of the generation config, each against the
+// schema of its target. Names must be unique and non-empty, weights non-negative; an
+// error names the offending rule.
+func compileSoftRules(rules []*model.SoftRule) ([]compiledSoftRule, error) {
+	out := make([]compiledSoftRule, 0, len(rules))
+	seen := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		if r == nil {
+			continue
+		}
+		if r.Name == "" {
+			return nil, fmt.Errorf("soft rule without a name")
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("soft rule %q defined twice", r.Name)
+		}
+		seen[r.Name] = true
+		if r.Weight < 0 {
+			return nil, fmt.Errorf("soft rule %q: weight must not be negative", r.Name)
+		}
+		target := softRuleTarget(r)
+		schema, ok := softRuleSchemas[target]
+		if !ok {
+			return nil, fmt.Errorf("soft rule %q: unknown target %s", r.Name, target)
+		}
+		if r.PerSeat && target == model.SoftRuleTargetInvigilations {
+			return nil, fmt.Errorf("soft rule %q: perSeat is not available for invigilation rules", r.Name)
+		}
+		cond, err := softrule.Compile(r.Condition, schema)
+		if err != nil {
+			return nil, fmt.Errorf("soft rule %q: %w", r.Name, err)
+		}
+		if r.Enabled {
+			out = append(out, compiledSoftRule{rule: r, target: target, cond: cond})
+		}
+	}
+	return out, nil
+}
+
+// examRuleEnv is the attribute environment of exam e starting at start.
+func examRuleEnv(e *model.AssembledExam, c *model.Constraints, start time.Time) softrule.Env {
+	programs := make([]string, 0, len(e.PrimussExams))
+	for _, pe := range e.PrimussExams {
+		if pe != nil && pe.Exam != nil {
+			programs = append(programs, pe.Exam.Program)
+		}
+	}
+	env := timeRuleEnv(start)
+	env["ancode"] = float64(e.Ancode)
+	env["program"] = firstProgram(e)
+	env["programs"] = programs
+	env["seats"] = float64(e.StudentRegsCount)
+	env["duration"] = float64(e.MaxDuration)
+	env["ntas"] = float64(len(e.Ntas))
+	env["location"] = locationOf(c)
+	if e.ZpaExam != nil {
+		env["module"] = e.ZpaExam.Module
+		env["examer"] = e.ZpaExam.MainExamer
+		env["examType"] = e.ZpaExam.ExamType
+		env["repeater"] = e.ZpaExam.IsRepeaterExam
+	}
+	if c != nil && c.RoomConstraints != nil {
+		env["exahm"] = c.RoomConstraints.Exahm
+		env["seb"] = c.RoomConstraints.Seb
+	}
+	return env
+}
+
+// timeRuleEnv is the environment holding the time attributes of start.
+func timeRuleEnv(start time.Time) softrule.Env {
+	local := start.Local()
+	return softrule.Env{
+		"weekday": weekdaysDE[int(local.Weekday())],
+		"date":    local.Format("2006-01-02"),
+		"time":    local.Format("15:04"),
+		"hour":    float64(local.Hour()) + float64(local.Minute())/60,
+	}
+}
+
+// examPlanRules evaluates the compiled rules for every movable unit and slot into the
+// solver's per-(unit, slot) penalty tables. A unit of several sameSlot exams pays for
+// each member the condition matches. With slots == nil the tables are left empty (for
+// the read-only constraint listing).
+func examPlanRules(rules []compiledSoftRule, units []examplan.Unit, slots []examplan.Slot,
+	exams map[int]*model.AssembledExam, constraints map[int]*model.Constraints,
+) []examplan.Rule {
+	out := make([]examplan.Rule, 0, len(rules))
+	for _, cr := range rules {
+		if cr.target != model.SoftRuleTargetExams {
+			continue
+		}
+		r := examplan.Rule{
+			Name:        cr.rule.Name,
+			Title:       cr.rule.Name,
+			Description: softRuleDescription(cr.rule),
+			Weight:      cr.rule.Weight,
+		}
+		if slots != nil {
+			r.Cost = make([][]float64, len(units))
+			for u := range units {
+				if units[u].Fixed {
+					continue
+				}
+				for _, a := range units[u].Ancodes {
+					e := exams[a]
+					if e == nil {
+						continue
+					}
+					pen := cr.rule.Weight
+					if cr.rule.PerSeat {
+						pen *= float64(e.StudentRegsCount)
+					}
+					for s := range slots {
+						if cr.cond.Match(examRuleEnv(e, constraints[a], slots[s].Start)) {
+							if r.Cost[u] == nil {
+								r.Cost[u] = make([]float64, len(slots))
+							}
+							r.Cost[u][s] += pen
+						}
+					}
+				}
+			}
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// roomPlanRules evaluates the compiled room rules for every exam of the room-plan problem
+// and every room it may use into the solver's per-(exam, room) penalty tables; exams[e] is
+// the planned exam behind prob.Exams[e]. With prob == nil the tables are left empty (for
+// the read-only constraint listing).
+func roomPlanRules(rules []compiledSoftRule, prob *roomplan.Problem, exams []*model.PlannedExam) []roomplan.Rule {
+	out := make([]roomplan.Rule, 0, len(rules))
+	for _, cr := range rules {
+		if cr.target != model.SoftRuleTargetRooms {
+			continue
+		}
+		r := roomplan.Rule{
+			Name:        cr.rule.Name,
+			Title:       cr.rule.Name,
+			Description: softRuleDescription(cr.rule),
+			Weight:      cr.rule.Weight,
+			PerSeat:     cr.rule.PerSeat,
+		}
+		if prob != nil {
+			r.Cost = make([][]float64, len(prob.Exams))
+			for e := range prob.Exams {
+				ex := &prob.Exams[e]
+				env := examRuleEnv(assembledOf(exams[e]), exams[e].Constraints, prob.Slots[ex.Slot].Start)
+				for _, rm := range roomsOfExam(ex) {
+					room := &prob.Rooms[rm]
+					env["room"] = room.Name
+					env["roomSeats"] = float64(room.Seats)
+					env["building"] = room.Building
+					env["ownRoom"] = room.OwnRoom
+					env["roomExahm"] = room.Exahm
+					env["roomSeb"] = room.Seb
+					env["roomLab"] = room.Lab
+					env["roomHandicap"] = room.Handicap
+					if cr.cond.Match(env) {
+						if r.Cost[e] == nil {
+							r.Cost[e] = make([]float64, len(prob.Rooms))
+						}
+						r.Cost[e][rm] = cr.rule.Weight
+					}
+				}
+			}
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// roomsOfExam returns the rooms a seat of the exam may use (normal or alone), sorted.
+func roomsOfExam(ex *roomplan.Exam) []int {
+	set := make(map[int]bool, len(ex.AllowedNormal)+len(ex.AllowedAlone))
+	for _, r := range ex.AllowedNormal {
+		set[r] = true
+	}
+	for _, r := range ex.AllowedAlone {
+		set[r] = true
+	}
+	out := make([]int, 0, len(set))
+	for r := range set {
+		out = append(out, r)
+	}
+	sort.Ints(out)
+	return out
+}
+
+// assembledOf is the assembled part of a planned exam (for the exam attributes).
+func assembledOf(e *model.PlannedExam) *model.AssembledExam {
+	return &model.AssembledExam{
+		Ancode: e.Ancode, ZpaExam: e.ZpaExam, PrimussExams: e.PrimussExams, Constraints: e.Constraints,
+		Conflicts: e.Conflicts, StudentRegsCount: e.StudentRegsCount, Ntas: e.Ntas, MaxDuration: e.MaxDuration,
+	}
+}
+
+// invigilationRules evaluates the compiled invigilation rules for every open position of
+// the invigilation problem and every invigilator into the optimizer's penalty tables;
+// invigilators maps the invigilator IDs to their todos entries (for the person attributes).
+func invigilationRules(rules []compiledSoftRule, problem *invigplan.Problem, invigilators map[int]*model.Invigilator) []invigplan.Rule {
+	var active []compiledSoftRule
+	for _, cr := range rules {
+		if cr.target == model.SoftRuleTargetInvigilations {
+			active = append(active, cr)
+		}
+	}
+	out := make([]invigplan.Rule, len(active))
+	for k, cr := range active {
+		out[k] = invigplan.Rule{Name: cr.rule.Name, Cost: make([]map[int]float64, len(problem.Positions))}
+	}
+	if len(active) == 0 {
+		return out
+	}
+	for posIdx, pos := range problem.Positions {
+		if _, fixed := problem.Fixed[posIdx]; fixed {
+			continue
+		}
+		env := timeRuleEnv(pos.Start)
+		env["room"] = pos.Room
+		env["reserve"] = pos.IsReserve
+		env["nta"] = pos.IsNTA
+		env["minutes"] = float64(pos.Block)
+		env["campus"] = pos.Campus
+		for i := range problem.Invigilators {
+			in := &problem.Invigilators[i]
+			env["invigilator"], env["invigilatorName"] = "", ""
+			env["prof"], env["lba"], env["staff"] = false, false, false
+			env["partTime"] = 0.0
+			if inv := invigilators[in.ID]; inv != nil {
+				if inv.Teacher != nil {
+					env["invigilator"] = inv.Teacher.Shortname
+					env["invigilatorName"] = inv.Teacher.Fullname
+					env["prof"] = inv.Teacher.IsProf
+					env["lba"] = inv.Teacher.IsLBA
+					env["staff"] = inv.Teacher.IsStaff
+				}
+				if inv.Requirements != nil {
+					env["partTime"] = inv.Requirements.PartTime
+				}
+			}
+			env["ownExamDay"] = in.OwnExamDays[dateOrdinal(pos.Start)]
+			for k, cr := range active {
+				if cr.cond.Match(env) {
+					if out[k].Cost[posIdx] == nil {
+						out[k].Cost[posIdx] = make(map[int]float64)
+					}
+					out[k].Cost[posIdx][in.ID] = cr.rule.Weight
+				}
+			}
+		}
+	}
+	return out
+}
+
+func softRuleDescription(r *model.SoftRule) string {
+	var unit string
+	switch softRuleTarget(r) {
+	case model.SoftRuleTargetRooms:
+		unit = "je genutztem Raum"
+		if r.PerSeat {
+			unit = "je Sitzplatz im Raum"
+		}
+	case model.SoftRuleTargetInvigilations:
+		unit = "je Aufsicht"
+	default:
+		unit = "je Prüfung"
+		if r.PerSeat {
+			unit = "je Anmeldung"
+		}
+	}
+	desc := fmt.Sprintf("Strafe %g %s, wenn gilt: %s", r.Weight, unit, r.Condition)
+	if r.Description != "" {
+		desc = r.Description + " — " + desc
+	}
+	return desc
+}
@@ -0,0 +1,225 @@
+package plexams
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/examplan"
+	"github.com/obcode/plexams.go/plexams/invigplan"
+	"github.com/obcode/plexams.go/plexams/roomplan"
+)
+
+func TestCompileSoftRules(t *testing.T) {
+	rules := []*model.SoftRule{
+		{Name: "late-if", Condition: `program == "IF" && time > "16:00"`, Weight: 100, Enabled: true},
+		{Name: "off", Condition: `seats > 10`, Weight: 1, Enabled: false},
+	}
+	compiled, err := compileSoftRules(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(compiled) != 1 || compiled[0].rule.Name != "late-if" {
+		t.Errorf("only enabled rules are compiled, got %d", len(compiled))
+	}
+	if compiled[0].target != model.SoftRuleTargetExams {
+		t.Errorf("a rule without a target schedules exams, got %s", compiled[0].target)
+	}
+
+	compiled, err = compileSoftRules([]*model.SoftRule{
+		{Name: "t-bau", Target: model.SoftRuleTargetRooms, Condition: `examer == "Braun" && building == "T"`, Enabled: true},
+		{Name: "reserve", Target: model.SoftRuleTargetInvigilations, Condition: `invigilator == "BR" && reserve`, Enabled: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(compiled) != 2 || compiled[0].target != model.SoftRuleTargetRooms || compiled[1].target != model.SoftRuleTargetInvigilations {
+		t.Errorf("room and invigilation rules expected, got %+v", compiled)
+	}
+
+	bad := []struct {
+		rules []*model.SoftRule
+		want  string
+	}{
+		{[]*model.SoftRule{{Name: "", Condition: "exahm"}}, "without a name"},
+		{[]*model.SoftRule{{Name: "a", Condition: "exahm"}, {Name: "a", Condition: "seb"}}, "defined twice"},
+		{[]*model.SoftRule{{Name: "a", Condition: "exahm", Weight: -1}}, "negative"},
+		// disabled rules are still validated, so they can be enabled later without surprise
+		{[]*model.SoftRule{{Name: "a", Condition: `room == "R1"`}}, `soft rule "a": unknown attribute`},
+		// each target has its own attributes
+		{[]*model.SoftRule{{Name: "a", Target: model.SoftRuleTargetInvigilations, Condition: `examer == "Braun"`}}, "unknown attribute"},
+		{[]*model.SoftRule{{Name: "a", Target: model.SoftRuleTargetInvigilations, Condition: "reserve", PerSeat: true}}, "perSeat"},
+	}
+	for _, b := range bad {
+		if _, err := compileSoftRules(b.rules); err == nil || !strings.Contains(err.Error(), b.want) {
+			t.Errorf("got %v, want error containing %q", err, b.want)
+		}
+	}
+}
+
+func TestExamPlanRulesCostTables(t *testing.T) {
+	day := time.Date(2026, 7, 13, 0, 0, 0, 0, time.Local) // a Monday
+	slots := []examplan.Slot{
+		{SlotRef: examplan.SlotRef{Start: day.Add(8*time.Hour + 30*time.Minute)}},
+		{SlotRef: examplan.SlotRef{Start: day.Add(16*time.Hour + 30*time.Minute)}},
+		{SlotRef: examplan.SlotRef{Start: day.Add(24*time.Hour + 16*time.Hour + 30*time.Minute)}},
+	}
+	zpa := func(ancode int, examer string) *model.ZPAExam {
+		return &model.ZPAExam{AnCode: ancode, MainExamer: examer, Module: "M"}
+	}
+	prog := func(p string) []*model.EnhancedPrimussExam {
+		return []*model.EnhancedPrimussExam{{Exam: &model.PrimussExam{Program: p}}}
+	}
+	exams := map[int]*model.AssembledExam{
+		1: {Ancode: 1, ZpaExam: zpa(1, "Braun"), PrimussExams: prog("IF"), StudentRegsCount: 30},
+		2: {Ancode: 2, ZpaExam: zpa(2, "Braun"), PrimussExams: prog("IF"), StudentRegsCount: 10},
+		3: {Ancode: 3, ZpaExam: zpa(3, "Huber"), PrimussExams: prog("DC"), StudentRegsCount: 50},
+	}
+	units := []examplan.Unit{
+		{ID: 1, Ancodes: []int{1, 2}},
+		{ID: 3, Ancodes: []int{3}},
+		{ID: 4, Ancodes: []int{4}, Fixed: true},
+	}
+	compiled, err := compileSoftRules([]*model.SoftRule{
+		{Name: "if-late", Condition: `"IF" in programs && hour >= 16`, Weight: 2, PerSeat: true, Enabled: true},
+		{Name: "braun-monday", Condition: `examer == "Braun" && weekday != "Mo"`, Weight: 100, Enabled: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := examPlanRules(compiled, units, slots, exams, nil)
+
+	late := rules[0].Cost
+	if late[0][0] != 0 || late[0][1] != 2*40 || late[0][2] != 2*40 {
+		t.Errorf("per-seat penalty over both sameSlot members expected, got %v", late[0])
+	}
+	if late[1] != nil || late[2] != nil {
+		t.Errorf("non-matching and fixed units must have no row, got %v / %v", late[1], late[2])
+	}
+	monday := rules[1].Cost
+	if monday[0][0] != 0 || monday[0][1] != 0 || monday[0][2] != 200 {
+		t.Errorf("per-exam penalty on the Tuesday for both members expected, got %v", monday[0])
+	}
+	if !strings.Contains(rules[1].Description, `examer == "Braun"`) {
+		t.Errorf("description should show the condition, got %q", rules[1].Description)
+	}
+}
+
+func TestSoftRuleAttributesPerTarget(t *testing.T) {
+	has := func(target model.SoftRuleTarget, name string) bool {
+		for _, a := range (&Plexams{}).SoftRuleAttributes(target) {
+			if a.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	if !has(model.SoftRuleTargetExams, "examer") || has(model.SoftRuleTargetExams, "room") {
+		t.Error("exam rules have the exam attributes only")
+	}
+	if !has(model.SoftRuleTargetRooms, "examer") || !has(model.SoftRuleTargetRooms, "building") {
+		t.Error("room rules have the exam and the room attributes")
+	}
+	if !has(model.SoftRuleTargetInvigilations, "invigilator") || !has(model.SoftRuleTargetInvigilations, "hour") ||
+		has(model.SoftRuleTargetInvigilations, "examer") {
+		t.Error("invigilation rules have the invigilator and time attributes")
+	}
+}
+
+func TestRoomPlanRulesCostTables(t *testing.T) {
+	day := time.Date(2026, 7, 13, 0, 0, 0, 0, time.Local)
+	rooms := []roomplan.Room{
+		{Name: "R1.046", Seats: 30, OwnRoom: true, Building: "R"},
+		{Name: "T3.021", Seats: 20, Exahm: true, Building: "T"},
+	}
+	exams := []roomplan.Exam{
+		{Ancode: 1, Slot: 0, NormalCount: 2, AllowedNormal: []int{0, 1}},
+		{Ancode: 2, Slot: 0, NormalCount: 1, AllowedNormal: []int{0}, AllowedAlone: []int{1}},
+	}
+	seats := []roomplan.Seat{{Exam: 0, Kind: roomplan.Normal}, {Exam: 0, Kind: roomplan.Normal}, {Exam: 1, Kind: roomplan.NTAAlone}}
+	prob := roomplan.NewProblem([]roomplan.Slot{{Start: day.Add(8*time.Hour + 30*time.Minute)}}, rooms, exams, seats, roomplan.DefaultWeights())
+	planned := []*model.PlannedExam{
+		{Ancode: 1, ZpaExam: &model.ZPAExam{AnCode: 1, MainExamer: "Braun"}},
+		{Ancode: 2, ZpaExam: &model.ZPAExam{AnCode: 2, MainExamer: "Huber"}},
+	}
+	compiled, err := compileSoftRules([]*model.SoftRule{
+		{Name: "exam-only", Condition: `examer == "Braun"`, Weight: 1, Enabled: true},
+		{Name: "braun-not-t", Target: model.SoftRuleTargetRooms, Condition: `examer == "Braun" && building == "T"`, Weight: 300, Enabled: true},
+		{Name: "morning-exahm", Target: model.SoftRuleTargetRooms, Condition: `roomExahm && hour < 9`, Weight: 5, PerSeat: true, Enabled: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := roomPlanRules(compiled, prob, planned)
+	if len(rules) != 2 {
+		t.Fatalf("only the room rules belong to the room plan, got %d", len(rules))
+	}
+	if c := rules[0].Cost; c[0] == nil || c[0][0] != 0 || c[0][1] != 300 || c[1] != nil {
+		t.Errorf("braun-not-t: only exam 1 in T3.021 should pay, got %v", c)
+	}
+	if c := rules[1].Cost; !rules[1].PerSeat || c[0][1] != 5 || c[1][1] != 5 || c[1][0] != 0 {
+		t.Errorf("morning-exahm: every exam in the EXaHM room pays per seat, got %v", c)
+	}
+	if !strings.Contains(rules[1].Description, "je Sitzplatz im Raum") {
+		t.Errorf("description should show the per-seat unit, got %q", rules[1].Description)
+	}
+
+	prob.SetRules(rules)
+	listed := map[string]bool{}
+	for _, info := range prob.Registry().Describe() {
+		listed[info.Name] = true
+	}
+	if !listed["rule:braun-not-t"] || !listed["rule:morning-exahm"] || listed["rule:exam-only"] {
+		t.Errorf("the room registry should list the room rules only, got %v", listed)
+	}
+}
+
+func TestInvigilationRulesCostTables(t *testing.T) {
+	day := time.Date(2026, 7, 13, 0, 0, 0, 0, time.Local)
+	problem := &invigplan.Problem{
+		Positions: []invigplan.Position{
+			{Room: "R1.046", Minutes: 90, Block: 90, Start: day.Add(8 * time.Hour)},
+			{IsReserve: true, Minutes: 60, Block: 90, Start: day.Add(14 * time.Hour)},
+			{IsReserve: true, Minutes: 60, Block: 90, Start: day.Add(16 * time.Hour)},
+		},
+		Invigilators: []invigplan.Invigilator{
+			{ID: 7, OwnExamDays: map[int]bool{dateOrdinal(day): true}},
+			{ID: 8},
+		},
+		Fixed: map[int]int{2: 8},
+	}
+	problem.Prepare()
+	invigilators := map[int]*model.Invigilator{
+		7: {Teacher: &model.Teacher{ID: 7, Shortname: "BR", IsProf: true}},
+		8: {Teacher: &model.Teacher{ID: 8, Shortname: "HU"}},
+	}
+	compiled, err := compileSoftRules([]*model.SoftRule{
+		{Name: "br-no-late-reserve", Target: model.SoftRuleTargetInvigilations, Condition: `invigilator == "BR" && reserve && hour >= 14`, Weight: 400, Enabled: true},
+		{Name: "exam-day-room", Target: model.SoftRuleTargetInvigilations, Condition: `ownExamDay && !reserve`, Weight: 20, Enabled: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := invigilationRules(compiled, problem, invigilators)
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 invigilation rules, got %d", len(rules))
+	}
+	late := rules[0].Cost
+	if late[1][7] != 400 || late[1][8] != 0 || late[0] != nil {
+		t.Errorf("br-no-late-reserve: only BR on the 14:00 reserve should pay, got %v", late)
+	}
+	if late[2] != nil {
+		t.Errorf("fixed positions must have no entries, got %v", late[2])
+	}
+	if c := rules[1].Cost; c[0][7] != 20 || c[0][8] != 0 {
+		t.Errorf("exam-day-room: only the invigilator with an own exam that day should pay, got %v", c)
+	}
+
+	plan := invigplan.NewPlan(problem)
+	plan.Set(1, 7)
+	_, byConstraint, _ := invigplan.DefaultRegistry(rules...).Cost(problem, plan)
+	if byConstraint["rule:br-no-late-reserve"] != 400 {
+		t.Errorf("the registry should score the rule, got %v", byConstraint)
+	}
+}
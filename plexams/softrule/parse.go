@@ -0,0 +1,273 @@
+package softrule
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp // operators and punctuation
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+// twoCharOps are matched before the single-character ones.
+var twoCharOps = []string{"==", "!=", "<=", ">=", "&&", "||"}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	rs := []rune(src)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			j := i + 1
+			for j < len(rs) && rs[j] != '"' {
+				j++
+			}
+			if j == len(rs) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			toks = append(toks, token{tokString, string(rs[i+1 : j]), i})
+			i = j + 1
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			toks = append(toks, token{tokNumber, string(rs[i:j]), i})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_') {
+				j++
+			}
+			toks = append(toks, token{tokIdent, string(rs[i:j]), i})
+			i = j
+		default:
+			if i+1 < len(rs) {
+				if two := string(rs[i : i+2]); slices.Contains(twoCharOps, two) {
+					toks = append(toks, token{tokOp, two, i})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("<>!()[],", r) {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			toks = append(toks, token{tokOp, string(r), i})
+			i++
+		}
+	}
+	return append(toks, token{kind: tokEOF, text: "end of condition", pos: len(rs)}), nil
+}
+
+// parser is a recursive-descent parser that type-checks while it builds the tree:
+//
+//	or   := and { "||" and }
+//	and  := not { "&&" not }
+//	not  := "!" not | cmp
+//	cmp  := atom [ ("=="|"!="|"<"|"<="|">"|">="|"in") atom ]
+//	atom := number | string | true | false | ident | "(" or ")" | "[" [atom {"," atom}] "]"
+type parser struct {
+	toks   []token
+	i      int
+	schema Schema
+	nodes  int
+}
+
+func (ps *parser) peek() token { return ps.toks[ps.i] }
+
+func (ps *parser) next() token {
+	t := ps.toks[ps.i]
+	if t.kind != tokEOF {
+		ps.i++
+	}
+	return t
+}
+
+func (ps *parser) accept(text string) bool {
+	t := ps.peek()
+	if (t.kind == tokOp || t.kind == tokIdent && text == "in") && t.text == text {
+		ps.i++
+		return true
+	}
+	return false
+}
+
+func (ps *parser) mk(n *node) *node {
+	ps.nodes++
+	return n
+}
+
+func (ps *parser) parseOr() (*node, error) {
+	l, err := ps.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for ps.peek().text == "||" {
+		pos := ps.next().pos
+		r, err := ps.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if l.typ != TypeBool || r.typ != TypeBool {
+			return nil, fmt.Errorf("|| needs boolean operands (position %d)", pos)
+		}
+		l = ps.mk(&node{op: opOr, typ: TypeBool, l: l, r: r})
+	}
+	return l, nil
+}
+
+func (ps *parser) parseAnd() (*node, error) {
+	l, err := ps.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for ps.peek().text == "&&" {
+		pos := ps.next().pos
+		r, err := ps.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if l.typ != TypeBool || r.typ != TypeBool {
+			return nil, fmt.Errorf("&& needs boolean operands (position %d)", pos)
+		}
+		l = ps.mk(&node{op: opAnd, typ: TypeBool, l: l, r: r})
+	}
+	return l, nil
+}
+
+func (ps *parser) parseNot() (*node, error) {
+	if t := ps.peek(); t.kind == tokOp && t.text == "!" {
+		ps.next()
+		x, err := ps.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if x.typ != TypeBool {
+			return nil, fmt.Errorf("! needs a boolean operand (position %d)", t.pos)
+		}
+		return ps.mk(&node{op: opNot, typ: TypeBool, l: x}), nil
+	}
+	return ps.parseCmp()
+}
+
+var cmpOps = map[string]op{"==": opEq, "!=": opNe, "<": opLt, "<=": opLe, ">": opGt, ">=": opGe}
+
+func (ps *parser) parseCmp() (*node, error) {
+	l, err := ps.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	t := ps.peek()
+	if o, ok := cmpOps[t.text]; ok && t.kind == tokOp {
+		ps.next()
+		r, err := ps.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		if l.typ != r.typ || l.typ == TypeStringList || l.typ == typeList {
+			return nil, fmt.Errorf("cannot compare %s with %s (position %d)", l.typ, r.typ, t.pos)
+		}
+		if l.typ == TypeBool && o != opEq && o != opNe {
+			return nil, fmt.Errorf("%s is not defined for booleans (position %d)", t.text, t.pos)
+		}
+		return ps.mk(&node{op: o, typ: TypeBool, l: l, r: r}), nil
+	}
+	if ps.accept("in") {
+		r, err := ps.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case r.typ == TypeStringList && l.typ == TypeString:
+		case r.typ == typeList && (r.elem == l.typ || r.l == nil):
+		default:
+			return nil, fmt.Errorf("cannot test %s in %s (position %d)", l.typ, r.typ, t.pos)
+		}
+		return ps.mk(&node{op: opIn, typ: TypeBool, l: l, r: r}), nil
+	}
+	return l, nil
+}
+
+func (ps *parser) parseAtom() (*node, error) {
+	t := ps.next()
+	switch t.kind {
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", t.text, t.pos)
+		}
+		return ps.mk(&node{op: opLit, typ: TypeNumber, value: f}), nil
+	case tokString:
+		return ps.mk(&node{op: opLit, typ: TypeString, value: t.text}), nil
+	case tokIdent:
+		switch t.text {
+		case "true", "false":
+			return ps.mk(&node{op: opLit, typ: TypeBool, value: t.text == "true"}), nil
+		}
+		a, ok := ps.schema[t.text]
+		if !ok {
+			return nil, fmt.Errorf("unknown attribute %q at position %d", t.text, t.pos)
+		}
+		return ps.mk(&node{op: opAttr, typ: a.Type, name: t.text}), nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			x, err := ps.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !ps.accept(")") {
+				return nil, fmt.Errorf("missing ) for ( at position %d", t.pos)
+			}
+			return x, nil
+		case "[":
+			return ps.parseList(t)
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
+
+// parseList parses a literal list of numbers or strings; its value is []any so that
+// membership compares with ==.
+func (ps *parser) parseList(open token) (*node, error) {
+	n := &node{op: opLit, typ: typeList}
+	var values []any
+	for !ps.accept("]") {
+		if len(values) > 0 && !ps.accept(",") {
+			return nil, fmt.Errorf("expected , or ] in list at position %d", ps.peek().pos)
+		}
+		x, err := ps.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		if x.op != opLit || (x.typ != TypeNumber && x.typ != TypeString) {
+			return nil, fmt.Errorf("list at position %d may only contain number or string literals", open.pos)
+		}
+		if len(values) > 0 && x.typ != n.elem {
+			return nil, fmt.Errorf("list at position %d mixes types", open.pos)
+		}
+		n.elem = x.typ
+		n.l = x // marks the list as non-empty for the in type check
+		values = append(values, x.value)
+	}
+	n.value = values
+	return ps.mk(n), nil
+}
@@ -0,0 +1,216 @@
+// Package softrule is a tiny, side-effect-free condition language for user-defined soft
+// constraints. A planner writes a boolean condition over the attributes of one placement
+// (an exam at a start time, an exam in a room, an invigilator on an invigilation), e.g.
+//
+//	program == "IF" && time > "16:00"
+//	examer == "Braun" && building != "T"
+//	invigilator == "Braun" && reserve && hour >= 14
+//
+// and the generator adds the rule's penalty whenever the condition holds. The language
+// has literals (numbers, "strings", true/false, [lists]), attributes, comparison
+// (== != < <= > >=), membership (in), negation (!), && / || and parentheses — no
+// function calls, no assignment, no loops, so evaluating a compiled rule always
+// terminates. Conditions are parsed and type-checked once against a Schema (unknown
+// attributes and type mismatches are compile errors); Match then cannot fail.
+//
+// The package is pure: it knows nothing about exams, rooms or invigilators; the caller
+// defines the Schema and fills the Env.
+package softrule
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Type is the type of an attribute or expression.
+type Type int
+
+const (
+	TypeBool Type = iota
+	TypeNumber
+	TypeString
+	TypeStringList
+	typeList // literal list, element type in node.elem
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeBool:
+		return "bool"
+	case TypeNumber:
+		return "number"
+	case TypeString:
+		return "string"
+	case TypeStringList:
+		return "string list"
+	}
+	return "list"
+}
+
+// Attribute describes one attribute available to conditions.
+type Attribute struct {
+	Name        string
+	Type        Type
+	Description string
+}
+
+// Schema is the set of attributes a condition may reference, keyed by name.
+type Schema map[string]Attribute
+
+// Env holds the attribute values of one evaluation: bool, float64, string or []string
+// according to the attribute's Type. A missing attribute evaluates to its zero value.
+type Env map[string]any
+
+// maxSourceLen and maxNodes bound what a (GUI-entered) condition may cost to evaluate.
+const (
+	maxSourceLen = 1000
+	maxNodes     = 200
+)
+
+// Expr is a compiled, type-checked condition.
+type Expr struct {
+	src  string
+	root *node
+}
+
+// String returns the source of the condition.
+func (e *Expr) String() string { return e.src }
+
+// Compile parses src and type-checks it against schema. The result must be boolean.
+func Compile(src string, schema Schema) (*Expr, error) {
+	if len(src) > maxSourceLen {
+		return nil, fmt.Errorf("condition too long (%d > %d characters)", len(src), maxSourceLen)
+	}
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	ps := &parser{toks: toks, schema: schema}
+	root, err := ps.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := ps.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+	if ps.nodes > maxNodes {
+		return nil, fmt.Errorf("condition too complex (%d > %d terms)", ps.nodes, maxNodes)
+	}
+	if root.typ != TypeBool {
+		return nil, fmt.Errorf("condition must be a boolean expression, got %s", root.typ)
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// Match evaluates the condition for one environment.
+func (e *Expr) Match(env Env) bool {
+	v, _ := e.root.eval(env).(bool)
+	return v
+}
+
+type op int
+
+const (
+	opLit op = iota
+	opAttr
+	opNot
+	opAnd
+	opOr
+	opEq
+	opNe
+	opLt
+	opLe
+	opGt
+	opGe
+	opIn
+)
+
+type node struct {
+	op    op
+	typ   Type
+	elem  Type // element type of a literal list
+	value any  // literal value
+	name  string
+	l, r  *node
+}
+
+func (n *node) eval(env Env) any {
+	switch n.op {
+	case opLit:
+		return n.value
+	case opAttr:
+		if v, ok := env[n.name]; ok {
+			return v
+		}
+		return zero(n.typ)
+	case opNot:
+		return !n.l.eval(env).(bool)
+	case opAnd:
+		return n.l.eval(env).(bool) && n.r.eval(env).(bool)
+	case opOr:
+		return n.l.eval(env).(bool) || n.r.eval(env).(bool)
+	case opIn:
+		x := n.l.eval(env)
+		switch list := n.r.eval(env).(type) {
+		case []string:
+			s, _ := x.(string)
+			return slices.Contains(list, s)
+		case []any:
+			return slices.Contains(list, x)
+		}
+		return false
+	}
+	a, b := n.l.eval(env), n.r.eval(env)
+	switch n.op {
+	case opEq:
+		return a == b
+	case opNe:
+		return a != b
+	}
+	c := compare(a, b)
+	switch n.op {
+	case opLt:
+		return c < 0
+	case opLe:
+		return c <= 0
+	case opGt:
+		return c > 0
+	}
+	return c >= 0
+}
+
+// compare orders two numbers or two strings (type-checked at compile time).
+func compare(a, b any) int {
+	switch x := a.(type) {
+	case float64:
+		y := b.(float64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case string:
+		y := b.(string)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+func zero(t Type) any {
+	switch t {
+	case TypeBool:
+		return false
+	case TypeNumber:
+		return 0.0
+	case TypeString:
+		return ""
+	}
+	return []string(nil)
+}
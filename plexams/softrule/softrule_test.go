@@ -0,0 +1,85 @@
+package softrule
+
+import (
+	"strings"
+	"testing"
+)
+
+var testSchema = Schema{
+	"program":  {Name: "program", Type: TypeString},
+	"programs": {Name: "programs", Type: TypeStringList},
+	"examer":   {Name: "examer", Type: TypeString},
+	"seats":    {Name: "seats", Type: TypeNumber},
+	"exahm":    {Name: "exahm", Type: TypeBool},
+	"time":     {Name: "time", Type: TypeString},
+	"weekday":  {Name: "weekday", Type: TypeString},
+}
+
+func TestMatch(t *testing.T) {
+	env := Env{
+		"program":  "IF",
+		"programs": []string{"IF", "DC"},
+		"examer":   "Braun",
+		"seats":    120.0,
+		"exahm":    false,
+		"time":     "16:30",
+		"weekday":  "Di",
+	}
+	cases := []struct {
+		src  string
+		want bool
+	}{
+		{`program == "IF" && time > "16:00"`, true},
+		{`program == "IF" && time > "17:00"`, false},
+		{`examer == "Braun" && weekday != "Mo"`, true},
+		{`"DC" in programs && seats >= 100 && !exahm`, true},
+		{`"GS" in programs`, false},
+		{`weekday in ["Mo", "Fr"] || seats < 50`, false},
+		{`seats in [60, 120]`, true},
+		{`!(program == "IF" || exahm)`, false},
+		{`exahm == false`, true},
+		{`seats > 100 && (time < "10:00" || time >= "16:00")`, true},
+	}
+	for _, c := range cases {
+		e, err := Compile(c.src, testSchema)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", c.src, err)
+		}
+		if got := e.Match(env); got != c.want {
+			t.Errorf("%q: got %v, want %v", c.src, got, c.want)
+		}
+	}
+}
+
+func TestMissingAttributeIsZero(t *testing.T) {
+	e, err := Compile(`seats == 0 && program == "" && !exahm`, testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.Match(Env{}) {
+		t.Error("missing attributes must evaluate to their zero value")
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	cases := []struct{ src, want string }{
+		{`room == "R1.046"`, "unknown attribute"},
+		{`seats == "100"`, "cannot compare"},
+		{`program`, "must be a boolean"},
+		{`program == "IF" &&`, "unexpected"},
+		{`(program == "IF"`, "missing )"},
+		{`program == "IF`, "unterminated string"},
+		{`seats > 10 + 5`, "unexpected character"},
+		{`exahm < true`, "not defined for booleans"},
+		{`seats in programs`, "cannot test"},
+		{`program in ["IF", 3]`, "mixes types"},
+		{`!seats`, "boolean operand"},
+		{strings.Repeat(`seats > 1 && `, 100) + `exahm`, "too long"},
+	}
+	for _, c := range cases {
+		_, err := Compile(c.src, testSchema)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("Compile(%q): got %v, want error containing %q", c.src, err, c.want)
+		}
+	}
+}
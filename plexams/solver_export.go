@@ -0,0 +1,310 @@
+package plexams
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/invigplan"
+	"github.com/obcode/plexams.go/plexams/milp"
+	"github.com/obcode/plexams.go/plexams/roomplan"
+	"github.com/rs/zerolog/log"
+)
+
+// Solver export: the pre-plan, room-plan and invigilation problems (or a single day of
+// the latter two) as an LP or MiniZinc model for an external exact solver. The solver
+// runs offline; its solution file is uploaded again, mapped back onto the problem rebuilt
+// from the current data, validated against the generator's hard constraints and applied
+// only when there are none. The model is rebuilt on upload, so the data must not change
+// between download and upload (a changed problem shows up as hard violations).
+
+// SolverImport is the outcome of an uploaded solver solution.
+type SolverImport struct {
+	Applied        bool     `json:"applied"`
+	HardViolations []string `json:"hardViolations"`
+	Cost           float64  `json:"cost"` // the generator's own soft cost of the imported plan
+	Open           int      `json:"open"` // unplaced units / seats without a room / open positions
+}
+
+// solverModel is an exported problem: the program plus how to apply a solution to it.
+type solverModel struct {
+	name  string
+	model *milp.Model
+	apply func(ctx context.Context, sol milp.Solution, dryRun bool) (*SolverImport, error)
+}
+
+// solverModelFor builds the model of problem ("preplan", "roomplan", "invigplan"),
+// restricted to day for the room and invigilation plan (nil = everything).
+func (p *Plexams) solverModelFor(ctx context.Context, problem string, day *time.Time) (*solverModel, error) {
+	switch problem {
+	case "preplan":
+		if day != nil {
+			return nil, fmt.Errorf("the pre-plan cannot be restricted to a day")
+		}
+		return p.preplanSolverModel(ctx)
+	case "roomplan":
+		return p.roomPlanSolverModel(ctx, day)
+	case "invigplan":
+		return p.invigilationSolverModel(ctx, day)
+	default:
+		return nil, fmt.Errorf("unknown problem %q (known: preplan, roomplan, invigplan)", problem)
+	}
+}
+
+func (p *Plexams) preplanSolverModel(ctx context.Context) (*solverModel, error) {
+	if err := p.generationAllowed(ctx, model.PlanningGateExams); err != nil {
+		return nil, err
+	}
+	inst, err := p.buildPreplanInstance(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if inst == nil {
+		return nil, fmt.Errorf("no pre-exams")
+	}
+	m := buildPreplanMILP(inst)
+	return &solverModel{name: "preplan", model: m.model, apply: func(ctx context.Context, sol milp.Solution, dryRun bool) (*SolverImport, error) {
+		assign, violations := m.assignment(sol)
+		res := &SolverImport{HardViolations: violations, Open: countUnplaced(assign)}
+		if dryRun || len(violations) > 0 {
+			return res, nil
+		}
+		if _, err := p.applyPreplanAssignment(ctx, inst, assign); err != nil {
+			return res, err
+		}
+		res.Applied = true
+		return res, nil
+	}}, nil
+}
+
+func (p *Plexams) roomPlanSolverModel(ctx context.Context, day *time.Time) (*solverModel, error) {
+	if err := p.generationAllowed(ctx, model.PlanningGateRooms); err != nil {
+		return nil, err
+	}
+	prob, err := p.buildRoomPlanProblem(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name := "roomplan"
+	if day != nil {
+		prob = prob.OnDay(*day)
+		name += "_" + day.Format("2006-01-02")
+	}
+	m := roomplan.BuildMILP(prob)
+	return &solverModel{name: name, model: m.Model, apply: func(ctx context.Context, sol milp.Solution, dryRun bool) (*SolverImport, error) {
+		st, err := m.State(sol)
+		if err != nil {
+			return &SolverImport{HardViolations: []string{err.Error()}}, nil
+		}
+		reg := prob.Registry()
+		total, _, _ := reg.Cost(st)
+		res := &SolverImport{Cost: total, Open: st.UnplacedCount(), HardViolations: []string{}}
+		for _, v := range reg.HardViolations(st) {
+			res.HardViolations = append(res.HardViolations, fmt.Sprintf("%s: %s %v", v.Constraint, v.Message, v.Refs))
+		}
+		if dryRun || len(res.HardViolations) > 0 {
+			return res, nil
+		}
+		if err := p.applyRoomPlanState(ctx, st, day); err != nil {
+			return res, err
+		}
+		res.Applied = true
+		return res, nil
+	}}, nil
+}
+
+// applyRoomPlanState writes an imported room plan like GenerateRoomPlan. For a single day
+// only that day's planned rooms and unplaced exams are replaced.
+func (p *Plexams) applyRoomPlanState(ctx context.Context, st *roomplan.State, day *time.Time) error {
+	plannedRooms := p.assignmentsToPlannedRooms(st.Assignments(), roomInfoMap(st.P), prePlannedSet(ctx, p))
+	unplaced := groupUnplaced(st.Unplaced())
+	if day != nil {
+		existing, err := p.dbClient.PlannedRooms(ctx)
+		if err != nil {
+			return err
+		}
+		for _, pr := range existing {
+			if pr.Starttime != nil && !sameDay(*pr.Starttime, *day) {
+				plannedRooms = append(plannedRooms, pr)
+			}
+		}
+		existingUnplaced, err := p.dbClient.UnplacedExams(ctx)
+		if err != nil {
+			return err
+		}
+		for _, u := range existingUnplaced {
+			if u.Starttime != nil && !sameDay(*u.Starttime, *day) {
+				unplaced = append(unplaced, u)
+			}
+		}
+	}
+	if err := p.dbClient.ReplacePlannedRooms(ctx, plannedRooms); err != nil {
+		return err
+	}
+	if err := p.dbClient.ReplaceUnplacedExams(ctx, unplaced); err != nil {
+		return err
+	}
+	p.markCondition(ctx, condRoomsAssigned)
+	return nil
+}
+
+func (p *Plexams) invigilationSolverModel(ctx context.Context, day *time.Time) (*solverModel, error) {
+	if err := p.generationAllowed(ctx, model.PlanningGateInvigilations); err != nil {
+		return nil, err
+	}
+	problem, err := p.buildInvigilationProblem(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	base, _, err := p.persistedInvigilationPlan(ctx, problem, nil)
+	if err != nil {
+		return nil, err
+	}
+	name := "invigplan"
+	var free func(int) bool
+	if day != nil {
+		name += "_" + day.Format("2006-01-02")
+		free = func(pos int) bool { return sameDay(problem.Positions[pos].Start, *day) }
+	}
+	rules, err := p.invigilationSoftRules(ctx, problem)
+	if err != nil {
+		return nil, err
+	}
+	m := invigplan.BuildMILP(problem, base, free, rules...)
+	return &solverModel{name: name, model: m.Model, apply: func(ctx context.Context, sol milp.Solution, dryRun bool) (*SolverImport, error) {
+		plan, err := m.Plan(sol)
+		if err != nil {
+			return &SolverImport{HardViolations: []string{err.Error()}}, nil
+		}
+		reg := invigplan.DefaultRegistry(rules...)
+		total, _, _ := reg.Cost(problem, plan)
+		res := &SolverImport{Cost: total, Open: len(plan.Unfilled()), HardViolations: []string{}}
+		for _, v := range reg.HardViolations(problem, plan) {
+			res.HardViolations = append(res.HardViolations, fmt.Sprintf("[%s] %s", v.Constraint, v.Message))
+		}
+		if dryRun || len(res.HardViolations) > 0 {
+			return res, nil
+		}
+		if err := p.saveInvigilationPlan(ctx, problem, plan, NewLogReporter()); err != nil {
+			return res, err
+		}
+		res.Applied = true
+		return res, nil
+	}}, nil
+}
+
+// solverDay parses the optional ?day=YYYY-MM-DD query parameter.
+func solverDay(r *http.Request) (*time.Time, error) {
+	s := r.URL.Query().Get("day")
+	if s == "" {
+		return nil, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid day %q (expected YYYY-MM-DD)", s)
+	}
+	return &day, nil
+}
+
+// HTTPDownloadSolverModel serves GET /download/solver/{problem}/{format}[?day=YYYY-MM-DD]:
+// the problem (preplan, roomplan, invigplan) as an LP file (format lp) or a MiniZinc
+// model (format mzn).
+func (p *Plexams) HTTPDownloadSolverModel(w http.ResponseWriter, r *http.Request) {
+	problem, format := chi.URLParam(r, "problem"), chi.URLParam(r, "format")
+	if format != "lp" && format != "mzn" {
+		http.Error(w, fmt.Sprintf("unknown format %q (known: lp, mzn)", format), http.StatusBadRequest)
+		return
+	}
+	day, err := solverDay(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sm, err := p.solverModelFor(r.Context(), problem, day)
+	if err != nil {
+		http.Error(w, "cannot build model: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	if format == "lp" {
+		err = sm.model.WriteLP(&buf)
+	} else {
+		err = sm.model.WriteMiniZinc(&buf)
+	}
+	if err != nil {
+		http.Error(w, "cannot write model: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("%s_%s.%s", strings.ReplaceAll(p.semester, " ", "_"), sm.name, format)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Error().Err(err).Str("problem", problem).Msg("cannot write solver model download")
+	}
+}
+
+// HTTPUploadSolverSolution handles POST /upload/solver-solution/{problem}[?day=…][&dryRun=true]
+// (multipart: file): it reads an external solver's solution for the model downloaded with
+// the same problem and day, validates it and — unless dryRun or a hard constraint is
+// violated — applies it.
+func (p *Plexams) HTTPUploadSolverSolution(w http.ResponseWriter, r *http.Request) {
+	if !p.WritesAllowed() {
+		http.Error(w, "a validation or transfer/email is running, cannot upload now", http.StatusConflict)
+		return
+	}
+	if p.IsReadOnly() {
+		http.Error(w, "semester is read-only", http.StatusConflict)
+		return
+	}
+	if err := r.ParseMultipartForm(64 << 20); err != nil {
+		http.Error(w, "cannot parse upload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "missing file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close() //nolint:errcheck
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "cannot read solution: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	day, err := solverDay(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+
+	ctx := r.Context()
+	problem := chi.URLParam(r, "problem")
+	sm, err := p.solverModelFor(ctx, problem, day)
+	if err != nil {
+		http.Error(w, "cannot build model: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	sol, err := milp.ReadSolution(bytes.NewReader(data), sm.model)
+	if err != nil {
+		http.Error(w, "cannot read solution: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := sm.apply(ctx, sol, dryRun)
+	if err != nil {
+		http.Error(w, "import failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if result.Applied {
+		p.LogUpload(ctx, "uploadSolverSolution", "problem", problem, "file", header.Filename)
+	}
+	writeJSON(w, result)
+}
@@ -0,0 +1,197 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/invigplan"
+	"github.com/obcode/plexams.go/plexams/optimize"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	solverRunKindInvigilations = "invigilations"
+	// solverRunCheckpoints is how often (spread over the planned iterations) a run
+	// persists its best plan: a restart loses at most 1/solverRunCheckpoints of it.
+	solverRunCheckpoints = 50
+)
+
+// activeSolverRuns holds the IDs of the runs executing in this process. A run stored
+// as RUNNING but not active here was cut off by a restart (INTERRUPTED).
+var activeSolverRuns = struct {
+	sync.Mutex
+	ids map[string]bool
+}{ids: make(map[string]bool)}
+
+func activateSolverRun(id string) {
+	activeSolverRuns.Lock()
+	defer activeSolverRuns.Unlock()
+	activeSolverRuns.ids[id] = true
+}
+
+// tryActivateSolverRun marks the run active unless it already is (check and set under
+// the same lock, so two concurrent resumes cannot both start it).
+func tryActivateSolverRun(id string) bool {
+	activeSolverRuns.Lock()
+	defer activeSolverRuns.Unlock()
+	if activeSolverRuns.ids[id] {
+		return false
+	}
+	activeSolverRuns.ids[id] = true
+	return true
+}
+
+func deactivateSolverRun(id string) {
+	activeSolverRuns.Lock()
+	defer activeSolverRuns.Unlock()
+	delete(activeSolverRuns.ids, id)
+}
+
+func solverRunActive(id string) bool {
+	activeSolverRuns.Lock()
+	defer activeSolverRuns.Unlock()
+	return activeSolverRuns.ids[id]
+}
+
+// SolverRuns returns the checkpointed optimizer runs of the semester, newest first.
+func (p *Plexams) SolverRuns(ctx context.Context) ([]*model.SolverRun, error) {
+	runs, err := p.dbClient.SolverRuns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs {
+		if run.Status == model.SolverRunStatusRunning && !solverRunActive(run.ID) {
+			run.Status = model.SolverRunStatusInterrupted
+		}
+	}
+	return runs, nil
+}
+
+func newInvigilationRun(problem *invigplan.Problem, dryRun bool, opts invigplan.Options) *model.SolverRun {
+	now := time.Now()
+	return &model.SolverRun{
+		ID:          primitive.NewObjectID().Hex(),
+		Kind:        solverRunKindInvigilations,
+		Status:      model.SolverRunStatusRunning,
+		DryRun:      dryRun,
+		Seed:        opts.Seed,
+		StartTemp:   opts.StartTemp,
+		EndTemp:     opts.EndTemp,
+		Iterations:  opts.Iterations,
+		Fingerprint: problem.Fingerprint(),
+		StartedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// checkpointSolverRun stores the checkpoint in run and persists it. A failing write
+// is only logged: the run itself goes on, it is just less resumable.
+func (p *Plexams) checkpointSolverRun(ctx context.Context, run *model.SolverRun, cp invigplan.Checkpoint) {
+	run.Iteration = cp.Iteration
+	run.Iterations = cp.Total
+	run.BestCost = cp.BestCost
+	run.BestIter = cp.BestIter
+	run.Assign = cp.Assign
+	run.RandSeed = cp.Rand.Seed
+	run.RandDraws = int64(cp.Rand.Draws) //nolint:gosec // a draw count never exceeds int64
+	p.saveSolverRun(ctx, run)
+}
+
+// failSolverRun marks the run FAILED with err and persists it; returns err.
+func (p *Plexams) failSolverRun(ctx context.Context, run *model.SolverRun, err error) error {
+	msg := err.Error()
+	run.Status = model.SolverRunStatusFailed
+	run.Error = &msg
+	p.saveSolverRun(ctx, run)
+	return err
+}
+
+// saveSolverRun persists the run. It is detached from ctx: the solver goes on after the
+// client of the request that started it disconnects, and so must its checkpoints.
+func (p *Plexams) saveSolverRun(ctx context.Context, run *model.SolverRun) {
+	run.UpdatedAt = time.Now()
+	if err := p.dbClient.SaveSolverRun(context.WithoutCancel(ctx), run); err != nil {
+		log.Error().Err(err).Str("run", run.ID).Msg("cannot checkpoint solver run")
+	}
+}
+
+// ResumeInvigilationRun continues a checkpointed invigilation run in the background
+// from its best plan and random stream, additionalIterations beyond its planned
+// length. The planning inputs must be unchanged since the run started (same problem
+// fingerprint), otherwise the stored assignment means something else. The output of
+// the resumed run goes to the server log; the run record shows its progress.
+func (p *Plexams) ResumeInvigilationRun(ctx context.Context, id string, additionalIterations int, dryRun bool) (*model.SolverRun, error) {
+	if err := p.generationAllowed(ctx, model.PlanningGateInvigilations); err != nil {
+		return nil, err
+	}
+	run, err := p.dbClient.SolverRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil || run.Kind != solverRunKindInvigilations {
+		return nil, fmt.Errorf("invigilation run %s not found", id)
+	}
+	if additionalIterations < 0 {
+		return nil, fmt.Errorf("additionalIterations must not be negative")
+	}
+	if run.Assign == nil {
+		return nil, fmt.Errorf("run %s has no checkpoint yet, start a new run instead", id)
+	}
+	total := run.Iterations + additionalIterations
+	if run.Iteration >= total {
+		return nil, fmt.Errorf("run %s is complete, pass additionalIterations to continue it", id)
+	}
+
+	if !tryActivateSolverRun(id) {
+		return nil, fmt.Errorf("run %s is still running", id)
+	}
+	started := false
+	defer func() {
+		if !started {
+			deactivateSolverRun(id)
+		}
+	}()
+
+	reporter := NewLogReporter()
+	problem, err := p.prepareInvigilationProblem(ctx, reporter)
+	if err != nil {
+		return nil, err
+	}
+	if problem.Fingerprint() != run.Fingerprint {
+		return nil, fmt.Errorf("the invigilation planning inputs changed since run %s was started, start a new run instead", id)
+	}
+
+	opts := invigplan.DefaultOptions()
+	opts.Seed, opts.StartTemp, opts.EndTemp, opts.Iterations = run.Seed, run.StartTemp, run.EndTemp, total
+	opts.Resume = &invigplan.Checkpoint{
+		Iteration: run.Iteration,
+		Total:     total,
+		BestCost:  run.BestCost,
+		// A run that stopped early on stagnation would stop again right away; count the
+		// stagnation from the resume point instead.
+		BestIter: max(run.BestIter, run.Iteration),
+		Assign:   run.Assign,
+		Rand:     optimize.RandState{Seed: run.RandSeed, Draws: uint64(run.RandDraws)}, //nolint:gosec // stored from a uint64
+	}
+
+	run.Status = model.SolverRunStatusRunning
+	run.DryRun = dryRun
+	run.Iterations = total
+	run.Error = nil
+	p.saveSolverRun(ctx, run)
+
+	result := *run
+	started = true
+	go func() {
+		defer deactivateSolverRun(id)
+		// runInvigilationOptimizer has already marked the run FAILED on an error
+		if _, err := p.runInvigilationOptimizer(context.Background(), problem, run, dryRun, opts, reporter); err != nil {
+			log.Error().Err(err).Str("run", id).Msg("resumed invigilation run failed")
+		}
+	}()
+	return &result, nil
+}
@@ -0,0 +1,21 @@
+package plexams
+
+import "testing"
+
+func TestTryActivateSolverRun(t *testing.T) {
+	const id = "test-run"
+	if !tryActivateSolverRun(id) {
+		t.Fatal("first activation refused")
+	}
+	if tryActivateSolverRun(id) {
+		t.Error("second activation of an active run accepted")
+	}
+	if !solverRunActive(id) {
+		t.Error("run not active")
+	}
+	deactivateSolverRun(id)
+	if !tryActivateSolverRun(id) {
+		t.Error("activation after deactivation refused")
+	}
+	deactivateSolverRun(id)
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/obcode/plexams.go/graph/model"
-	"github.com/obcode/plexams.go/plexams/repeatcalc"
 	"github.com/obcode/plexams.go/plexams/spreadcalc"
 )
 
@@ -53,6 +52,7 @@ func (p *Plexams) ExamSpreadStatistics(ctx context.Context) (*model.ExamSpreadSt
 	notTooClose := p.semesterConfig.NotTooCloseMinutes
 	durByAncode := p.examDurationsByAncode(ctx)
 	info := p.examInfoMap(ctx)
+	repeats := p.repeatDecider(ctx)
 
 	// The statistic covers OUR students — those enrolled in an FK07 or MUC.DAI program —
 	// because only for them do we hold the COMPLETE set of exams in the period (incl. the
@@ -102,12 +102,11 @@ func (p *Plexams) ExamSpreadStatistics(ctx context.Context) (*model.ExamSpreadSt
 		if restrictProgram && !ownProgram[s.Program] {
 			continue // not one of our (fully-known) students
 		}
-		studSem := repeatcalc.SemesterOf(s.Group)
 		rec := &studentSpreadRecord{student: s}
 		nonRepeat := 0
 		for _, ancode := range s.ZpaAncodes {
 			ei := info[ancode]
-			if !repeatcalc.RepeatForStudent(studSem, ei.repeater, ei.minSem) {
+			if !repeats.decide(s.Mtknr, s.Group, ei.module, ei.repeater, ei.minSem).Repeat {
 				nonRepeat++
 			}
 			if start, ok := startByAncode[ancode]; ok {
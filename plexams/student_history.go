@@ -0,0 +1,210 @@
+package plexams
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/repeatcalc"
+	"github.com/obcode/plexams.go/plexams/studenthistory"
+	"github.com/rs/zerolog/log"
+)
+
+// Student history (see studenthistory): the registrations of the earlier semester
+// workspaces and the imported attendance are collected per student under a pseudonym
+// (keyed hash of the Matrikelnummer, needs secrets.key), so a repeat is recognized by the
+// student's own history. Only students without a history fall back to the study-group
+// heuristic of repeatcalc. It drives the conflict auto-accept and the plan down-weighting.
+
+const defaultHistorySemesters = 6
+
+// RebuildStudentHistory replaces the stored student history with the one built from the
+// previous semester workspaces (up to semesters back).
+func (p *Plexams) RebuildStudentHistory(ctx context.Context, semesters *int) (*model.StudentHistorySummary, error) {
+	if p.sealer == nil {
+		return nil, fmt.Errorf("no secrets key configured, cannot pseudonymize the students")
+	}
+	n := defaultHistorySemesters
+	if semesters != nil {
+		if *semesters < 1 {
+			return nil, fmt.Errorf("semesters must be at least 1, got %d", *semesters)
+		}
+		n = *semesters
+	}
+	input := make([]studenthistory.Semester, 0, n)
+	for _, db := range p.previousWorkspaces(ctx, n) {
+		exams, err := p.dbClient.AssembledExamsForDatabase(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		students, err := p.dbClient.StudentRegsPerStudentPlannedForDatabase(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		attendance, err := p.dbClient.StudentAttendanceForDatabase(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		s := studenthistory.Semester{Workspace: db, Modules: make(map[int]string, len(exams)),
+			Registrations: make([]studenthistory.Registration, 0, len(students)), Attendance: attendance}
+		for _, e := range exams {
+			if e.ZpaExam != nil {
+				s.Modules[e.Ancode] = e.ZpaExam.Module
+			}
+		}
+		for _, st := range students {
+			s.Registrations = append(s.Registrations, studenthistory.Registration{Mtknr: st.Mtknr, Ancodes: st.ZpaAncodes})
+		}
+		input = append(input, s)
+	}
+	// collected newest first
+	for i, j := 0, len(input)-1; i < j; i, j = i+1, j-1 {
+		input[i], input[j] = input[j], input[i]
+	}
+
+	histories, err := studenthistory.Build(input, p.sealer.Pseudonym)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.dbClient.ReplaceStudentHistories(ctx, histories); err != nil {
+		return nil, err
+	}
+	summary := &model.StudentHistorySummary{Workspaces: make([]string, 0, len(input)), Students: len(histories)}
+	for _, s := range input {
+		summary.Workspaces = append(summary.Workspaces, s.Workspace)
+	}
+	for _, h := range histories {
+		summary.Records += len(h.Records)
+		for _, r := range h.Records {
+			if r.Sat != nil {
+				summary.WithAttendance++
+			}
+		}
+	}
+	log.Info().Strs("workspaces", summary.Workspaces).Int("students", summary.Students).Int("records", summary.Records).
+		Msg("student history rebuilt")
+	return summary, nil
+}
+
+// repeatDecider decides per student and exam whether it is a repeat, from the stored
+// student history or, without one, by the study-group heuristic.
+type repeatDecider struct {
+	history    *studenthistory.History
+	pseudonym  func(string) (string, error)
+	pseudonyms map[string]string
+}
+
+// repeatDecider loads the stored student history. Without a secrets key or history (or
+// if it cannot be read) every decision falls back to the heuristic.
+func (p *Plexams) repeatDecider(ctx context.Context) *repeatDecider {
+	d := &repeatDecider{pseudonyms: make(map[string]string)}
+	if p.sealer == nil {
+		return d
+	}
+	histories, err := p.dbClient.StudentHistories(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot get student history, using the repeat heuristic")
+		return d
+	}
+	d.history = studenthistory.NewHistory(histories)
+	d.pseudonym = p.sealer.Pseudonym
+	return d
+}
+
+// decide reports whether the exam (module, repeater flag, smallest group semester) is a
+// repeat for the student (Matrikelnummer, study group).
+func (d *repeatDecider) decide(mtknr, group, module string, examRepeater bool, examSemester int) studenthistory.Decision {
+	pn, ok := d.pseudonyms[mtknr]
+	if !ok && d.history.Len() > 0 && mtknr != "" {
+		var err error
+		if pn, err = d.pseudonym(mtknr); err != nil {
+			log.Error().Err(err).Msg("cannot pseudonymize student")
+		}
+		d.pseudonyms[mtknr] = pn
+	}
+	return d.history.Decide(pn, module, examRepeater, repeatcalc.SemesterOf(group), examSemester)
+}
+
+// repeatEvidence converts a repeat decision into the evidence shown in the conflict views.
+func repeatEvidence(ancode int, module string, d studenthistory.Decision) *model.RepeatEvidence {
+	earlier := d.Earlier
+	if earlier == nil {
+		earlier = []*model.StudentHistoryRecord{}
+	}
+	return &model.RepeatEvidence{Ancode: ancode, Module: module, Source: model.RepeatSource(d.Source), Earlier: earlier}
+}
+
+// ImportStudentAttendance reads an attendance table of the semester's exams. dryRun (or
+// any problem in the file) only returns the preview.
+func (p *Plexams) ImportStudentAttendance(ctx context.Context, data []byte, dryRun bool) (*studenthistory.Import, error) {
+	entries, problems, err := studenthistory.ParseAttendance(data)
+	if err != nil {
+		return nil, err
+	}
+	students, err := p.StudentRegsPerStudentPlanned(ctx)
+	if err != nil {
+		return nil, err
+	}
+	registered := make(map[int]map[string]bool)
+	for _, s := range students {
+		for _, a := range s.ZpaAncodes {
+			if registered[a] == nil {
+				registered[a] = make(map[string]bool)
+			}
+			registered[a][s.Mtknr] = true
+		}
+	}
+	result := studenthistory.Check(entries, problems, registered)
+	if dryRun || len(result.Problems) > 0 || len(result.Entries) == 0 {
+		return result, nil
+	}
+	if err := p.dbClient.SaveStudentAttendance(ctx, result.Entries); err != nil {
+		return nil, err
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// HTTPUploadStudentAttendance imports which registered students sat which exam
+// (multipart field "file", XLSX or CSV with the columns Ancode;Mtknr;anwesend). With
+// ?dryRun=true it only returns the preview; otherwise it replaces the attendance of the
+// exams in the file unless it has problems. Later semesters read it into the student
+// history.
+// POST /upload/student-attendance
+func (p *Plexams) HTTPUploadStudentAttendance(w http.ResponseWriter, r *http.Request) {
+	if !p.WritesAllowed() {
+		http.Error(w, "a validation or transfer/email is running, cannot upload now", http.StatusConflict)
+		return
+	}
+	if p.IsReadOnly() {
+		http.Error(w, "semester is read-only", http.StatusConflict)
+		return
+	}
+	if err := r.ParseMultipartForm(16 << 20); err != nil {
+		http.Error(w, "cannot parse upload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "missing file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close() //nolint:errcheck
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "cannot read file: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+	result, err := p.ImportStudentAttendance(r.Context(), data, dryRun)
+	if err != nil {
+		http.Error(w, "cannot import student attendance: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if result.Applied {
+		p.LogUpload(r.Context(), "uploadStudentAttendance", "file", header.Filename, "changes", result.Summary())
+	}
+	writeJSON(w, result)
+}
@@ -0,0 +1,308 @@
+// Package studenthistory builds the pseudonymized exam history of the students across
+// the earlier semester workspaces (which exams and modules a student was registered for
+// and, where the attendance was imported, whether they sat the exam) and decides from it
+// whether an exam is a repeat for a student. Only if a student has no history the
+// study-group heuristic of repeatcalc is used. It is I/O-free; reading the workspaces,
+// computing the pseudonyms and storing the history stays in the plexams package.
+package studenthistory
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/repeatcalc"
+	"github.com/obcode/plexams.go/plexams/tableio"
+)
+
+// Registration is the list of exams a student was registered for in a semester.
+type Registration struct {
+	Mtknr   string
+	Ancodes []int
+}
+
+// Semester is the input of one earlier semester workspace.
+type Semester struct {
+	Workspace     string
+	Modules       map[int]string // ancode -> module
+	Registrations []Registration
+	Attendance    []*model.StudentAttendance
+}
+
+// Build collects the records of all students of the semesters (oldest first), keyed by
+// the pseudonym of the Matrikelnummer, sorted by pseudonym. Registrations for exams
+// without a module (not planned in that semester) are skipped.
+func Build(semesters []Semester, pseudonym func(mtknr string) (string, error)) ([]*model.StudentHistory, error) {
+	byPseudonym := make(map[string]*model.StudentHistory)
+	for _, sem := range semesters {
+		sat := make(map[string]map[int]bool, len(sem.Attendance))
+		for _, a := range sem.Attendance {
+			if sat[a.Mtknr] == nil {
+				sat[a.Mtknr] = make(map[int]bool)
+			}
+			sat[a.Mtknr][a.Ancode] = a.Present
+		}
+		for _, reg := range sem.Registrations {
+			if reg.Mtknr == "" {
+				continue
+			}
+			pn, err := pseudonym(reg.Mtknr)
+			if err != nil {
+				return nil, err
+			}
+			ancodes := append([]int(nil), reg.Ancodes...)
+			sort.Ints(ancodes)
+			for i, ancode := range ancodes {
+				module, ok := sem.Modules[ancode]
+				if !ok || i > 0 && ancodes[i-1] == ancode {
+					continue
+				}
+				rec := &model.StudentHistoryRecord{Workspace: sem.Workspace, Ancode: ancode, Module: module}
+				if present, ok := sat[reg.Mtknr][ancode]; ok {
+					rec.Sat = &present
+				}
+				h := byPseudonym[pn]
+				if h == nil {
+					h = &model.StudentHistory{Pseudonym: pn}
+					byPseudonym[pn] = h
+				}
+				h.Records = append(h.Records, rec)
+			}
+		}
+	}
+	result := make([]*model.StudentHistory, 0, len(byPseudonym))
+	for _, h := range byPseudonym {
+		result = append(result, h)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Pseudonym < result[j].Pseudonym })
+	return result, nil
+}
+
+// Source is what a repeat decision is based on.
+type Source string
+
+const (
+	// SourceExam: the exam itself is a repeater exam.
+	SourceExam Source = "EXAM"
+	// SourceHistory: the student's history across the earlier workspaces.
+	SourceHistory Source = "HISTORY"
+	// SourceHeuristic: no history of the student, the study-group numbers decide.
+	SourceHeuristic Source = "HEURISTIC"
+)
+
+// Decision is whether an exam is a repeat for a student, and why. Earlier holds the
+// records of the same module in the earlier workspaces (the evidence), oldest first.
+type Decision struct {
+	Repeat  bool
+	Source  Source
+	Earlier []*model.StudentHistoryRecord
+}
+
+// History is the index of the stored student histories by pseudonym.
+type History struct {
+	byPseudonym map[string][]*model.StudentHistoryRecord
+}
+
+// NewHistory indexes the histories. A nil History is an empty one.
+func NewHistory(students []*model.StudentHistory) *History {
+	h := &History{byPseudonym: make(map[string][]*model.StudentHistoryRecord, len(students))}
+	for _, s := range students {
+		if len(s.Records) > 0 {
+			h.byPseudonym[s.Pseudonym] = s.Records
+		}
+	}
+	return h
+}
+
+// Len returns the number of students with a history.
+func (h *History) Len() int {
+	if h == nil {
+		return 0
+	}
+	return len(h.byPseudonym)
+}
+
+// Decide reports whether the exam (module) is a repeat for the student with the given
+// pseudonym ("" if unknown). A repeater exam always is. A student with a history is a
+// repeater iff they were registered for the same module in an earlier workspace (sat or
+// not); the history is authoritative for them, also when the study-group numbers say
+// otherwise. Without a history the study-group heuristic decides.
+func (h *History) Decide(pseudonym, module string, examRepeater bool, studentSemester, examSemester int) Decision {
+	records, known := h.records(pseudonym)
+	var earlier []*model.StudentHistoryRecord
+	if known {
+		m := normalize(module)
+		for _, r := range records {
+			if normalize(r.Module) == m {
+				earlier = append(earlier, r)
+			}
+		}
+	}
+	switch {
+	case examRepeater:
+		return Decision{Repeat: true, Source: SourceExam, Earlier: earlier}
+	case known:
+		return Decision{Repeat: len(earlier) > 0, Source: SourceHistory, Earlier: earlier}
+	}
+	return Decision{Repeat: repeatcalc.RepeatForStudent(studentSemester, false, examSemester), Source: SourceHeuristic}
+}
+
+func (h *History) records(pseudonym string) ([]*model.StudentHistoryRecord, bool) {
+	if h == nil || pseudonym == "" {
+		return nil, false
+	}
+	records, ok := h.byPseudonym[pseudonym]
+	return records, ok
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// Column headers of the attendance table.
+const (
+	ColAncode  = "Ancode"
+	ColMtknr   = "Mtknr"
+	ColPresent = "anwesend"
+)
+
+// Header is the column order of the attendance table.
+var Header = []string{ColAncode, ColMtknr, ColPresent}
+
+// aliases are the accepted (lower-case) header names per column.
+var aliases = map[string][]string{
+	ColAncode:  {"ancode", "prüfungsnummer", "anco"},
+	ColMtknr:   {"mtknr", "matrikelnummer", "matrikel", "matrnr"},
+	ColPresent: {"anwesend", "present", "teilgenommen", "status"},
+}
+
+// ParseAttendance reads the attendance table (XLSX or CSV with "," or ";"), one row per
+// registered student and exam. Unreadable rows are returned as problems (with the line
+// number) instead of failing the whole file.
+func ParseAttendance(data []byte) (entries []*model.StudentAttendance, problems []string, err error) {
+	rows, err := tableio.Read(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("file is empty")
+	}
+	col := make(map[string]int)
+	for i, h := range rows[0] {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\xEF\xBB\xBF")))
+		for name, names := range aliases {
+			for _, alias := range names {
+				if _, ok := col[name]; !ok && h == alias {
+					col[name] = i
+				}
+			}
+		}
+	}
+	for _, need := range Header {
+		if _, ok := col[need]; !ok {
+			return nil, nil, fmt.Errorf("column %q missing", need)
+		}
+	}
+	get := func(row []string, name string) string {
+		if i := col[name]; i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	for n, row := range rows[1:] {
+		line := n + 2
+		ancodeStr, mtknr, presentStr := get(row, ColAncode), get(row, ColMtknr), get(row, ColPresent)
+		if ancodeStr == "" && mtknr == "" && presentStr == "" {
+			continue
+		}
+		ancode, err := strconv.Atoi(ancodeStr)
+		if err != nil || ancode < 1 {
+			problems = append(problems, fmt.Sprintf("line %d: cannot read %s %q", line, ColAncode, ancodeStr))
+			continue
+		}
+		if mtknr == "" {
+			problems = append(problems, fmt.Sprintf("line %d: %s is needed", line, ColMtknr))
+			continue
+		}
+		present, ok := ParsePresent(presentStr)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("line %d: cannot read %s %q (ja/nein)", line, ColPresent, presentStr))
+			continue
+		}
+		entries = append(entries, &model.StudentAttendance{Mtknr: mtknr, Ancode: ancode, Present: present})
+	}
+	return entries, problems, nil
+}
+
+// ParsePresent reads the attendance column: ja/x/anwesend/true for present,
+// nein/abwesend/NE (nicht erschienen)/false for absent.
+func ParsePresent(s string) (present, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "ja", "j", "x", "1", "true", "yes", "anwesend", "present", "teilgenommen":
+		return true, true
+	case "nein", "n", "0", "false", "no", "abwesend", "absent", "ne", "nicht erschienen":
+		return false, true
+	}
+	return false, false
+}
+
+// Import is the result (or, not Applied, the preview) of an attendance upload.
+type Import struct {
+	Applied  bool     `json:"applied"`
+	Rows     int      `json:"rows"`
+	Exams    int      `json:"exams"`
+	Present  int      `json:"present"`
+	Absent   int      `json:"absent"`
+	Problems []string `json:"problems"`
+	// Entries are the rows to store (only when there are no problems).
+	Entries []*model.StudentAttendance `json:"-"`
+}
+
+// Summary is a one-line description of the import for the mutation log.
+func (i *Import) Summary() string {
+	return fmt.Sprintf("%d rows, %d exams, %d present, %d absent", i.Rows, i.Exams, i.Present, i.Absent)
+}
+
+// Check validates the parsed rows against the registrations of the semester (ancode ->
+// registered Matrikelnummern): unknown exams, students not registered for the exam and
+// contradicting duplicate rows are problems.
+func Check(entries []*model.StudentAttendance, problems []string, registered map[int]map[string]bool) *Import {
+	result := &Import{Problems: append([]string{}, problems...)}
+	seen := make(map[int]map[string]bool)
+	for _, e := range entries {
+		regs, ok := registered[e.Ancode]
+		if !ok {
+			result.Problems = append(result.Problems, fmt.Sprintf("unknown exam %d", e.Ancode))
+			continue
+		}
+		if !regs[e.Mtknr] {
+			result.Problems = append(result.Problems, fmt.Sprintf("student %s is not registered for exam %d", e.Mtknr, e.Ancode))
+			continue
+		}
+		if seen[e.Ancode] == nil {
+			seen[e.Ancode] = make(map[string]bool)
+		}
+		if present, dup := seen[e.Ancode][e.Mtknr]; dup {
+			if present != e.Present {
+				result.Problems = append(result.Problems, fmt.Sprintf("student %s: contradicting rows for exam %d", e.Mtknr, e.Ancode))
+			}
+			continue
+		}
+		seen[e.Ancode][e.Mtknr] = e.Present
+		result.Entries = append(result.Entries, e)
+		result.Rows++
+		if e.Present {
+			result.Present++
+		} else {
+			result.Absent++
+		}
+	}
+	result.Exams = len(seen)
+	if len(result.Problems) > 0 {
+		result.Entries = nil
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package studenthistory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obcode/plexams.go/graph/model"
+)
+
+func fakePseudonym(mtknr string) (string, error) { return "p" + mtknr, nil }
+
+func TestBuildAndDecide(t *testing.T) {
+	present, absent := true, false
+	semesters := []Semester{
+		{
+			Workspace: "2024-WS",
+			Modules:   map[int]string{100: "Datenbanken", 200: "Compilerbau"},
+			Registrations: []Registration{
+				{Mtknr: "1", Ancodes: []int{100, 200, 999}}, // 999: not planned, skipped
+				{Mtknr: "2", Ancodes: []int{200}},
+			},
+			Attendance: []*model.StudentAttendance{{Mtknr: "1", Ancode: 100, Present: false}},
+		},
+		{
+			Workspace:     "2025-SS",
+			Modules:       map[int]string{110: "datenbanken "},
+			Registrations: []Registration{{Mtknr: "1", Ancodes: []int{110, 110}}},
+			Attendance:    []*model.StudentAttendance{{Mtknr: "1", Ancode: 110, Present: true}},
+		},
+	}
+	students, err := Build(semesters, fakePseudonym)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(students) != 2 || students[0].Pseudonym != "p1" || len(students[0].Records) != 3 {
+		t.Fatalf("students = %+v", students)
+	}
+	if r := students[0].Records[0]; r.Workspace != "2024-WS" || r.Ancode != 100 || r.Sat == nil || *r.Sat != absent {
+		t.Errorf("first record = %+v", r)
+	}
+	if r := students[0].Records[2]; r.Ancode != 110 || r.Sat == nil || *r.Sat != present {
+		t.Errorf("last record = %+v", r)
+	}
+	if students[1].Records[0].Sat != nil {
+		t.Errorf("no attendance imported, Sat = %v", *students[1].Records[0].Sat)
+	}
+
+	h := NewHistory(students)
+	if h.Len() != 2 {
+		t.Errorf("Len = %d", h.Len())
+	}
+	// second attempt at Datenbanken, both earlier registrations are the evidence
+	if d := h.Decide("p1", "Datenbanken", false, 3, 3); !d.Repeat || d.Source != SourceHistory || len(d.Earlier) != 2 {
+		t.Errorf("p1 Datenbanken: %+v", d)
+	}
+	// known student, new module: no repeat even if the group number says so
+	if d := h.Decide("p2", "Datenbanken", false, 6, 3); d.Repeat || d.Source != SourceHistory {
+		t.Errorf("p2 Datenbanken: %+v", d)
+	}
+	// repeater exam: always a repeat, evidence still attached
+	if d := h.Decide("p2", "Compilerbau", true, 0, 0); !d.Repeat || d.Source != SourceExam || len(d.Earlier) != 1 {
+		t.Errorf("p2 Compilerbau: %+v", d)
+	}
+	// unknown student: heuristic
+	if d := h.Decide("p3", "Datenbanken", false, 6, 3); !d.Repeat || d.Source != SourceHeuristic {
+		t.Errorf("p3: %+v", d)
+	}
+	var empty *History
+	if d := empty.Decide("", "Datenbanken", false, 2, 3); d.Repeat || d.Source != SourceHeuristic {
+		t.Errorf("nil history: %+v", d)
+	}
+}
+
+func TestParseAndCheckAttendance(t *testing.T) {
+	data := "\xEF\xBB\xBFAncode;Matrikelnummer;anwesend\n" +
+		"100;1;ja\n" +
+		"100;2;nein\n" +
+		"100;2;nein\n" +
+		"200;1;NE\n" +
+		"abc;1;ja\n" +
+		"100;3;vielleicht\n" +
+		";;\n"
+	entries, problems, err := ParseAttendance([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 || len(problems) != 2 {
+		t.Fatalf("entries %d, problems %v", len(entries), problems)
+	}
+	registered := map[int]map[string]bool{100: {"1": true, "2": true}, 200: {"1": true}}
+	result := Check(entries, nil, registered)
+	if len(result.Problems) != 0 || result.Rows != 3 || result.Exams != 2 || result.Present != 1 || result.Absent != 2 {
+		t.Errorf("result = %s, problems %v", result.Summary(), result.Problems)
+	}
+	if len(result.Entries) != 3 {
+		t.Errorf("entries = %d", len(result.Entries))
+	}
+
+	result = Check(append(entries, &model.StudentAttendance{Mtknr: "9", Ancode: 200}, &model.StudentAttendance{Mtknr: "1", Ancode: 100, Present: false}), problems, registered)
+	if len(result.Problems) != 4 || !strings.Contains(result.Problems[2], "not registered") || !strings.Contains(result.Problems[3], "contradicting") {
+		t.Errorf("problems = %v", result.Problems)
+	}
+	if result.Entries != nil {
+		t.Error("entries kept despite problems")
+	}
+}
@@ -15,7 +15,8 @@ func (p *Plexams) StudyPrograms(ctx context.Context) ([]*model.StudyProgram, err
 	return p.dbClient.StudyPrograms(ctx)
 }
 
-// UpsertStudyProgram creates or updates one study program (key: shortname).
+// UpsertStudyProgram creates or updates one study program (key: shortname). The stored
+// curriculum is kept unless the program carries one.
 func (p *Plexams) UpsertStudyProgram(ctx context.Context, program *model.StudyProgram) (*model.StudyProgram, error) {
 	program.Shortname = strings.TrimSpace(program.Shortname)
 	if program.Shortname == "" {
@@ -24,6 +25,17 @@ func (p *Plexams) UpsertStudyProgram(ctx context.Context, program *model.StudyPr
 	if program.Category == "" {
 		program.Category = "misc"
 	}
+	// the curriculum is imported separately (/upload/curriculum) and not part of the
+	// input: keep the stored one
+	if program.Curriculum == nil {
+		existing, err := p.dbClient.StudyProgram(ctx, program.Shortname)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			program.Curriculum = existing.Curriculum
+		}
+	}
 	if err := p.dbClient.UpsertStudyProgram(ctx, program); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,37 @@
+// Package tableio reads the tables uploaded by the planners (XLSX, or CSV as saved by
+// Excel or by hand) into plain rows, so the import packages only deal with columns.
+package tableio
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Read returns the rows of the first sheet of an XLSX (a ZIP) or of a CSV. The CSV
+// separator is ";" if the first line has more semicolons than commas, else ","; a
+// leading UTF-8 BOM is dropped and rows may have different lengths.
+func Read(data []byte) ([][]string, error) {
+	if bytes.HasPrefix(data, []byte("PK")) {
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close() //nolint:errcheck
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("xlsx has no sheet")
+		}
+		return f.GetRows(sheets[0])
+	}
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	r := csv.NewReader(bytes.NewReader(data))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		r.Comma = ';' // German Excel saves CSV with semicolons
+	}
+	r.FieldsPerRecord = -1
+	return r.ReadAll()
+}
@@ -0,0 +1,47 @@
+package tableio
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestReadCSV(t *testing.T) {
+	rows, err := Read([]byte("\xEF\xBB\xBFAncode;Name\n1;Datenbanken, Teil 1\n2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0][0] != "Ancode" || rows[1][1] != "Datenbanken, Teil 1" || len(rows[2]) != 1 {
+		t.Errorf("rows = %q", rows)
+	}
+
+	rows, err = Read([]byte("a,b\n1,2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1][1] != "2" {
+		t.Errorf("comma rows = %q", rows)
+	}
+}
+
+func TestReadXLSX(t *testing.T) {
+	f := excelize.NewFile()
+	if err := f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Ancode", "Name"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetSheetRow("Sheet1", "A2", &[]interface{}{100, "Compilerbau"}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := Read(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1][0] != "100" || rows[1][1] != "Compilerbau" {
+		t.Errorf("rows = %q", rows)
+	}
+}
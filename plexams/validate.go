@@ -11,6 +11,7 @@ import (
 	"github.com/logrusorgru/aurora"
 	"github.com/obcode/plexams.go/graph/model"
 	"github.com/obcode/plexams.go/plexams/anny"
+	"github.com/obcode/plexams.go/plexams/campus"
 	"github.com/obcode/plexams.go/plexams/conflictcalc"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
@@ -189,10 +190,16 @@ func (p *Plexams) ValidateConflicts(onlyPlannedByMe bool, ancode int, reporter R
 		locByAncode[a] = locationOf(c)
 	}
 
+	sites, err := p.campusModel(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot get campus model")
+		return nil, err
+	}
+
 	v.step("validating students")
 	for _, student := range students {
 		p.validateStudentReg(student, planAncodeEntries, foreignAncodes, onlyPlannedByMe,
-			accepted, ancode, durByAncode, locByAncode, &validationMessages)
+			accepted, ancode, durByAncode, locByAncode, sites, &validationMessages)
 	}
 
 	conflictingAncodesSlice, normalizedValidationMessages := p.sortConflictingAncodes(validationMessages)
@@ -471,7 +478,7 @@ func (plexams *Plexams) sortConflictingAncodes(validationMessages map[conflictin
 
 func (plexams *Plexams) validateStudentReg(student *model.Student, planAncodeEntries []*model.PlanEntry,
 	foreignAncodes set.Set[int], onlyPlannedByMe bool, accepted set.Set[studentPair], ancode int,
-	durByAncode map[int]examDurations, locByAncode map[int]string, validationMessages *map[conflictingAncodes]*problemWithStudents) {
+	durByAncode map[int]examDurations, locByAncode map[int]string, sites *campus.Model, validationMessages *map[conflictingAncodes]*problemWithStudents) {
 	log.Debug().Str("name", student.Name).Str("mtknr", student.Mtknr).Msg("checking regs for student")
 
 	planAncodeEntriesForStudent := make([]*model.PlanEntry, 0)
@@ -529,7 +536,8 @@ func (plexams *Plexams) validateStudentReg(student *model.Student, planAncodeEnt
 			}
 
 			problem := ""
-			gap := effectiveGapMinutes(plexams.semesterConfig.ExamGapMinutes, plexams.crossCampusGapMinutes(), locByAncode[p[i].Ancode], locByAncode[p[j].Ancode])
+			locA, locB := locByAncode[p[i].Ancode], locByAncode[p[j].Ancode]
+			gap := effectiveGapMinutes(plexams.semesterConfig.ExamGapMinutes, plexams.travelMinutes(sites, locA, locB), locA, locB)
 			switch _, label := conflictcalc.TimeProximity(
 				*p[i].Starttime, endOf(p[i]), *p[j].Starttime, endOf(p[j]),
 				gap, plexams.semesterConfig.NotTooCloseMinutes); label {
@@ -604,8 +612,22 @@ func (p *Plexams) ValidateConstraints(reporter Reporter) (*model.ValidationRepor
 		return nil, err
 	}
 
+	v.step("get campus model")
+	sites, err := p.campusModel(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot get campus model")
+		return nil, err
+	}
+	withCampuses := sites.DefaultCampus() != ""
+
 	for _, constraint := range constraints {
 		v.step("check constraints for exam %d", constraint.Ancode)
+		location := locationOf(constraint)
+		if withCampuses && !sites.Known(location) {
+			v.warnf(ref{Ancode: ptr(constraint.Ancode)},
+				"Exam %d has location %s, which is no known campus (travel time falls back to %d min)",
+				constraint.Ancode, location, p.crossCampusGapMinutes())
+		}
 		slot, err := p.SlotForAncode(ctx, constraint.Ancode)
 		if err != nil {
 			log.Error().Err(err).Int("ancode", constraint.Ancode).Msg("cannot get slot for ancode")
@@ -691,6 +713,19 @@ func (p *Plexams) ValidateConstraints(reporter Reporter) (*model.ValidationRepor
 			}
 		}
 
+		if withCampuses && location != "" {
+			plannedRooms, err := p.dbClient.PlannedRoomsForAncode(ctx, constraint.Ancode)
+			if err != nil {
+				log.Error().Err(err).Int("ancode", constraint.Ancode).Msg("cannot get rooms for ancode")
+			}
+			for _, room := range plannedRooms {
+				if loc := sites.Room(room.RoomName); loc.Building != "" && loc.Campus != sites.Campus(location) {
+					v.errorf(ref{Ancode: ptr(constraint.Ancode), Room: ptr(room.RoomName), Starttime: &slot.Starttime},
+						"Exam %d takes place at %s, but room %s is at %s", constraint.Ancode, sites.Campus(location), room.RoomName, loc.Campus)
+				}
+			}
+		}
+
 		if constraint.RoomConstraints != nil && len(constraint.RoomConstraints.AllowedRooms) > 0 {
 			plannedRooms, err := p.dbClient.PlannedRoomsForAncode(ctx, constraint.Ancode)
 			if err != nil {
@@ -117,8 +117,9 @@ func (p *Plexams) ValidateInvigilationDups(reporter Reporter) (*model.Validation
 	}
 
 	type key struct {
-		room  string
-		start int64 // Unix seconds of the absolute start
+		room     string
+		start    int64 // Unix seconds of the absolute start
+		position int   // seat of a room with several invigilators
 	}
 
 	invigilationsMap := make(map[key]*model.Invigilation)
@@ -135,15 +136,16 @@ func (p *Plexams) ValidateInvigilationDups(reporter Reporter) (*model.Validation
 			room = *invigilation.RoomName
 		}
 		key := key{
-			room:  room,
-			start: invigilation.Starttime.Unix(),
+			room:     room,
+			start:    invigilation.Starttime.Unix(),
+			position: invigilation.Position,
 		}
 
 		_, ok := invigilationsMap[key]
 		if ok {
 			v.errorf(ref{Room: invigilation.RoomName, InvigilatorID: ptr(invigilation.InvigilatorID), Starttime: invigilation.Starttime},
-				"double entry for {roomname: %s, start: %s}",
-				room, invigilation.Starttime.Format("02.01. 15:04"))
+				"double entry for {roomname: %s, start: %s, position: %d}",
+				room, invigilation.Starttime.Format("02.01. 15:04"), invigilation.Position)
 		} else {
 			invigilationsMap[key] = invigilation
 		}
@@ -170,18 +172,30 @@ func (p *Plexams) ValidateInvigilatorSlots(reporter Reporter) (*model.Validation
 	maxInvigsMissingInOneSlot := make(map[string]int)
 	dateByKey := make(map[string]time.Time)
 
-	// all rooms and reserve max one invigilator
+	staffing, err := p.newRoomStaffing(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// every room has as many invigilators as the staffing rules ask for (default
+	// one), the reserve exactly one
 	for _, slot := range p.semesterConfig.Slots {
 		invigsMissing := 0
 		v.step("checking slot %s", slot.Starttime.Format("02.01. 15:04"))
 
-		rooms, err := p.plannedRoomNamesAt(ctx, slot.Starttime)
+		plannedRooms, err := p.plannedRoomsAt(ctx, slot.Starttime)
 		if err != nil {
 			log.Error().Err(err).Time("start", slot.Starttime).Msg("cannot get rooms for")
 		}
-		if len(rooms) == 0 {
+		if len(plannedRooms) == 0 {
 			continue
 		}
+		needed := staffing.needed(plannedRooms)
+		rooms := make([]string, 0, len(needed))
+		for room := range needed {
+			rooms = append(rooms, room)
+		}
+		sort.Strings(rooms)
 		dayKey := slot.Starttime.Format("2006-01-02")
 		dateByKey[dayKey] = slot.Starttime
 
@@ -204,12 +218,12 @@ func (p *Plexams) ValidateInvigilatorSlots(reporter Reporter) (*model.Validation
 				log.Error().Err(err).Time("start", slot.Starttime).Str("room", room).
 					Msg("cannot get reserve invigilator")
 			}
-			if len(invigilations) == 0 {
-				roomWithoutInvigilatorDay[dayKey]++
-				invigsMissing++
-			} else if len(invigilations) > 1 {
+			if missing := needed[room] - len(invigilations); missing > 0 {
+				roomWithoutInvigilatorDay[dayKey] += missing
+				invigsMissing += missing
+			} else if missing < 0 {
 				v.warnf(ref{Room: ptr(room), Starttime: &slot.Starttime},
-					"more than one invigilator for room %s at %s", room, slot.Starttime.Format("02.01. 15:04"))
+					"more than %d invigilator(s) for room %s at %s", needed[room], room, slot.Starttime.Format("02.01. 15:04"))
 			}
 		}
 		if invigsMissing > maxInvigsMissingInOneSlot[dayKey] {
@@ -236,7 +250,7 @@ func (p *Plexams) ValidateInvigilatorSlots(reporter Reporter) (*model.Validation
 			if roomsWithoutInvig+slotsWithoutReserve > 0 {
 				dayStart := dateByKey[dayKey]
 				v.warnf(ref{Starttime: &dayStart},
-					"Tag %s: %d open invigilations (%d max. in one slot), %d rooms without invigilator, %d slots without reserve",
+					"Tag %s: %d open invigilations (%d max. in one slot), %d missing room invigilators, %d slots without reserve",
 					dateByKey[dayKey].Format("02.01."), roomsWithoutInvig+slotsWithoutReserve, maxInvigsMissingInOneSlot[dayKey], roomsWithoutInvig, slotsWithoutReserve)
 			}
 		}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/obcode/plexams.go/graph/model"
@@ -34,43 +35,16 @@ func (p *Plexams) ValidateInvigilationConstraints(reporter Reporter) (*model.Val
 
 	// Build the plan from what is actually persisted, not from the fixed seeds.
 	problem.Fixed = map[int]int{}
-	plan := invigplan.NewPlan(problem)
-
-	index := make(map[string]int, len(problem.Positions))
-	for i, pos := range problem.Positions {
-		index[positionKey(pos.Start, pos.IsReserve, pos.Room)] = i
-	}
-
-	invigilations, err := p.dbClient.GetAllInvigilations(ctx)
+	plan, index, err := p.persistedInvigilationPlan(ctx, problem, func(inv *model.Invigilation, where string) {
+		v.warnf(ref{Room: inv.RoomName, InvigilatorID: ptr(inv.InvigilatorID), Starttime: inv.Starttime},
+			"invigilation for %s at %s has no matching position (room/slot not planned)",
+			where, inv.Starttime.Format("02.01. 15:04"))
+	})
 	if err != nil {
 		reporter.StopProgressFail(fmt.Sprintf("cannot get invigilations: %v", err))
 		return nil, err
 	}
 
-	for _, inv := range invigilations {
-		isReserve := inv.RoomName == nil
-		room := ""
-		if inv.RoomName != nil {
-			room = *inv.RoomName
-		}
-		if inv.Starttime == nil {
-			continue
-		}
-		key := positionKey(*inv.Starttime, isReserve, room)
-		idx, ok := index[key]
-		if !ok {
-			where := room
-			if isReserve {
-				where = "reserve"
-			}
-			v.warnf(ref{Room: inv.RoomName, InvigilatorID: ptr(inv.InvigilatorID), Starttime: inv.Starttime},
-				"invigilation for %s at %s has no matching position (room/slot not planned)",
-				where, inv.Starttime.Format("02.01. 15:04"))
-			continue
-		}
-		plan.Set(idx, inv.InvigilatorID)
-	}
-
 	// Check that every pre-planned invigilation is actually honored in the
 	// persisted plan (a later manual change could have overridden it).
 	prePlanned, err := p.PrePlannedInvigilations(ctx)
@@ -87,26 +61,57 @@ func (p *Plexams) ValidateInvigilationConstraints(reporter Reporter) (*model.Val
 			continue
 		}
 		when := pp.Starttime.Format("02.01. 15:04")
-		idx, ok := index[positionKey(*pp.Starttime, pp.RoomName == nil, room)]
-		if !ok {
+		// a room with several invigilators: the pre-planned one may hold any seat
+		seats := make([]int, 0, 1)
+		for seat := 0; ; seat++ {
+			idx, ok := index[positionKey(*pp.Starttime, pp.RoomName == nil, room, seat)]
+			if !ok {
+				break
+			}
+			seats = append(seats, idx)
+		}
+		if len(seats) == 0 {
 			v.errorf(ref{Room: pp.RoomName, InvigilatorID: ptr(pp.InvigilatorID), Starttime: pp.Starttime},
 				"pre-planned %s at %s has no matching position (room/slot not planned)",
 				room, when)
 			continue
 		}
-		switch assigned := plan.Assign[idx]; {
-		case assigned == invigplan.Unassigned:
+		honored, others := false, make([]int, 0, len(seats))
+		for _, idx := range seats {
+			switch assigned := plan.Assign[idx]; assigned {
+			case pp.InvigilatorID:
+				honored = true
+			case invigplan.Unassigned:
+			default:
+				others = append(others, assigned)
+			}
+		}
+		switch {
+		case honored:
+		case len(others) < len(seats):
 			v.errorf(ref{Room: pp.RoomName, InvigilatorID: ptr(pp.InvigilatorID), Starttime: pp.Starttime},
 				"pre-planned invigilator %d for %s at %s is missing in the plan",
 				pp.InvigilatorID, room, when)
-		case assigned != pp.InvigilatorID:
+		default:
 			v.errorf(ref{Room: pp.RoomName, InvigilatorID: ptr(pp.InvigilatorID), Starttime: pp.Starttime},
-				"pre-planned invigilator %d for %s at %s was overridden by %d",
-				pp.InvigilatorID, room, when, assigned)
+				"pre-planned invigilator %d for %s at %s was overridden by %s",
+				pp.InvigilatorID, room, when, strings.Trim(fmt.Sprint(others), "[]"))
 		}
 	}
 
-	reg := invigplan.DefaultRegistry()
+	// Rooms re-planned after the invigilations (room outage, room sizing by expected
+	// attendance) may need more or fewer invigilators than are stored.
+	if err := p.validateRoomStaffing(ctx, v); err != nil {
+		reporter.StopProgressFail(fmt.Sprintf("cannot check room staffing: %v", err))
+		return nil, err
+	}
+
+	rules, err := p.invigilationSoftRules(ctx, problem)
+	if err != nil {
+		reporter.StopProgressFail(fmt.Sprintf("cannot evaluate soft rules: %v", err))
+		return nil, err
+	}
+	reg := invigplan.DefaultRegistry(rules...)
 	hard := reg.HardViolations(problem, plan)
 	_, costByConstraint, soft := reg.Cost(problem, plan)
 
@@ -136,12 +141,102 @@ func (p *Plexams) ValidateInvigilationConstraints(reporter Reporter) (*model.Val
 	return report, nil
 }
 
+// validateRoomStaffing warns for every planned room whose stored invigilators (distinct
+// seats) differ from what the staffing rules ask for it now.
+func (p *Plexams) validateRoomStaffing(ctx context.Context, v *validation) error {
+	staffing, err := p.newRoomStaffing(ctx)
+	if err != nil {
+		return err
+	}
+	invigilations, err := p.dbClient.GetAllInvigilations(ctx)
+	if err != nil {
+		return err
+	}
+	stored := make(map[string]map[int]bool) // positionKey of seat 0 -> seats
+	for _, inv := range invigilations {
+		if inv.Starttime == nil || inv.RoomName == nil {
+			continue
+		}
+		key := positionKey(*inv.Starttime, false, *inv.RoomName, 0)
+		if stored[key] == nil {
+			stored[key] = make(map[int]bool)
+		}
+		stored[key][inv.Position] = true
+	}
+	for _, slot := range p.semesterConfig.Slots {
+		start := slot.Starttime
+		rooms, err := p.PlannedRoomsInSlot(ctx, start)
+		if err != nil {
+			return err
+		}
+		if len(rooms) == 0 {
+			continue
+		}
+		needed := staffing.needed(rooms)
+		names := make([]string, 0, len(needed))
+		for name := range needed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			has := len(stored[positionKey(start, false, name, 0)])
+			if has != needed[name] {
+				v.warnf(ref{Room: ptr(name), Starttime: ptr(start)},
+					"room %s at %s has %d invigilators, the staffing rules ask for %d",
+					name, start.Format("02.01. 15:04"), has, needed[name])
+			}
+		}
+	}
+	return nil
+}
+
+// persistedInvigilationPlan maps the persisted invigilations onto the problem's positions
+// and also returns the position index by positionKey. Invigilations without a matching
+// position are passed to unmatched (where is the room or "reserve").
+func (p *Plexams) persistedInvigilationPlan(ctx context.Context, problem *invigplan.Problem,
+	unmatched func(inv *model.Invigilation, where string),
+) (*invigplan.Plan, map[string]int, error) {
+	plan := invigplan.NewPlan(problem)
+	index := make(map[string]int, len(problem.Positions))
+	for i, pos := range problem.Positions {
+		index[positionKey(pos.Start, pos.IsReserve, pos.Room, pos.Seat)] = i
+	}
+
+	invigilations, err := p.dbClient.GetAllInvigilations(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, inv := range invigilations {
+		isReserve := inv.RoomName == nil
+		room := ""
+		if inv.RoomName != nil {
+			room = *inv.RoomName
+		}
+		if inv.Starttime == nil {
+			continue
+		}
+		idx, ok := index[positionKey(*inv.Starttime, isReserve, room, inv.Position)]
+		if !ok {
+			if unmatched != nil {
+				where := room
+				if isReserve {
+					where = "reserve"
+				}
+				unmatched(inv, where)
+			}
+			continue
+		}
+		plan.Set(idx, inv.InvigilatorID)
+	}
+	return plan, index, nil
+}
+
 // positionKey is the lookup key matching a persisted invigilation to a problem
 // position. It is keyed on the absolute start time (Unix seconds) instead of the
-// former day/slot ordinals.
-func positionKey(start time.Time, isReserve bool, room string) string {
+// former day/slot ordinals, plus the seat of a room with several invigilators.
+func positionKey(start time.Time, isReserve bool, room string, seat int) string {
 	if isReserve {
 		return fmt.Sprintf("%d/\x00reserve", start.Unix())
 	}
-	return fmt.Sprintf("%d/%s", start.Unix(), room)
+	return fmt.Sprintf("%d/%s#%d", start.Unix(), room, seat)
 }
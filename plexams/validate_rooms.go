@@ -9,6 +9,7 @@ import (
 
 	set "github.com/deckarep/golang-set/v2"
 	"github.com/obcode/plexams.go/graph/model"
+	"github.com/obcode/plexams.go/plexams/noshow"
 	"github.com/obcode/plexams.go/plexams/roomcalc"
 	"github.com/rs/zerolog/log"
 )
@@ -60,6 +61,12 @@ func (p *Plexams) ValidateRoomsPerSlot(reporter Reporter) (*model.ValidationRepo
 		allowedByStart[startKey(rfs.Starttime)] = names
 	}
 
+	// sized by expected attendance, a room may take more registered students than seats
+	attendance, err := p.expectedAttendance(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// group all planned rooms by their absolute start time.
 	plannedRooms, err := p.dbClient.PlannedRooms(ctx)
 	if err != nil {
@@ -111,6 +118,7 @@ func (p *Plexams) ValidateRoomsPerSlot(reporter Reporter) (*model.ValidationRepo
 
 		type roomSeats struct {
 			seatsPlanned, seats int
+			load                int // expected seats ×100 (= seatsPlanned×100 when sized by registrations)
 		}
 		seats := make(map[string]roomSeats)
 
@@ -128,17 +136,27 @@ func (p *Plexams) ValidateRoomsPerSlot(reporter Reporter) (*model.ValidationRepo
 				continue
 			}
 
+			share := 100
+			if a, ok := attendance[plannedRoom.Ancode]; ok && plannedRoom.NtaMtknr == nil {
+				share = a
+			}
 			entry := seats[plannedRoom.RoomName]
 			seats[plannedRoom.RoomName] = roomSeats{
 				seatsPlanned: len(plannedRoom.StudentsInRoom) + entry.seatsPlanned,
 				seats:        roomInfo.Seats,
+				load:         len(plannedRoom.StudentsInRoom)*share + entry.load,
 			}
 		}
 
 		for roomName, roomSeats := range seats {
-			if roomSeats.seatsPlanned > roomSeats.seats {
+			switch {
+			case roomSeats.load > roomSeats.seats*100:
 				v.errorf(ref{Room: ptr(roomName), Starttime: &g.start},
 					"Room %s is overbooked at %s: %d seats planned, but only %d available",
+					roomName, startStr, (roomSeats.load+99)/100, roomSeats.seats)
+			case roomSeats.seatsPlanned > roomSeats.seats:
+				v.warnf(ref{Room: ptr(roomName), Starttime: &g.start},
+					"Room %s at %s is sized by expected attendance: %d students registered for %d seats",
 					roomName, startStr, roomSeats.seatsPlanned, roomSeats.seats)
 			}
 		}
@@ -271,6 +289,12 @@ func (p *Plexams) ValidateRoomsBlocked(reporter Reporter) (*model.ValidationRepo
 		return nil, err
 	}
 
+	constraints, err := p.ConstraintsMap(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot get constraints")
+		return nil, err
+	}
+
 	// key a planned room by its room name + absolute start time.
 	planned := make(map[string]bool)
 	for _, pr := range plannedRooms {
@@ -285,6 +309,26 @@ func (p *Plexams) ValidateRoomsBlocked(reporter Reporter) (*model.ValidationRepo
 			continue
 		}
 		startStr := b.Starttime.Format("02.01. 15:04")
+		if b.Until != nil {
+			// a time-range block (reserveFreeRoom) is hit by any exam occupying the room
+			// in the range, incl. its Vor-/Nachlauf (as freeRooms counts it)
+			startStr += " – " + b.Until.Format("15:04")
+			v.step("checking block %s at %s", b.Room, startStr)
+			for _, pr := range plannedRooms {
+				if pr.Starttime == nil || pr.RoomName != b.Room {
+					continue
+				}
+				pre, post := occupancyBuffers(constraints[pr.Ancode])
+				from := pr.Starttime.Add(-pre)
+				end := pr.Starttime.Add(time.Duration(pr.Duration)*time.Minute + post)
+				if from.Before(*b.Until) && end.After(*b.Starttime) {
+					v.errorf(ref{Room: ptr(b.Room), Starttime: pr.Starttime},
+						"room %s is blocked at %s but still planned there for ancode %d; regenerate rooms for exams",
+						b.Room, startStr, pr.Ancode)
+				}
+			}
+			continue
+		}
 		v.step("checking block %s at %s", b.Room, startStr)
 		if planned[b.Room+"@"+startKey(*b.Starttime)] {
 			v.errorf(ref{Room: ptr(b.Room), Starttime: b.Starttime},
@@ -324,6 +368,17 @@ func (p *Plexams) ValidateRoomsEnoughSeats(reporter Reporter) (*model.Validation
 	if err != nil {
 		return nil, err
 	}
+	// sized by expected attendance, only the expected students take a seat
+	attendance, err := p.expectedAttendance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	expected := func(ancode, students int) int {
+		if a, ok := attendance[ancode]; ok {
+			return noshow.ExpectedSeats(students, a)
+		}
+		return students
+	}
 
 	bufferByAncode := make(map[int]int)
 	for _, exam := range plannedExams {
@@ -348,7 +403,7 @@ func (p *Plexams) ValidateRoomsEnoughSeats(reporter Reporter) (*model.Validation
 		v.step("checking free seats for exam %d", exam.Ancode)
 		buffer := roomcalc.FreeSeatsBuffer(normal)
 		bufferByAncode[exam.Ancode] = buffer
-		free := capacity - normal + reserveSeats
+		free := capacity - expected(exam.Ancode, normal) + reserveSeats
 		if free < buffer {
 			module := ""
 			if exam.ZpaExam != nil {
@@ -384,8 +439,10 @@ func (p *Plexams) ValidateRoomsEnoughSeats(reporter Reporter) (*model.Validation
 			}
 			key := startRoom{startKey(*r.Starttime), r.RoomName}
 			startRep[key] = *r.Starttime
-			occupants[key] += len(r.StudentsInRoom)
-			if r.NtaMtknr == nil { // normal block or (empty) reserve
+			if r.NtaMtknr != nil {
+				occupants[key] += len(r.StudentsInRoom)
+			} else { // normal block or (empty) reserve
+				occupants[key] += expected(exam.Ancode, len(r.StudentsInRoom))
 				if sharers[key] == nil {
 					sharers[key] = make(map[int]bool)
 				}
@@ -497,37 +554,36 @@ func (p *Plexams) ValidateRoomsPerExam(reporter Reporter) (*model.ValidationRepo
 					}
 				}
 			}
-			checkRoomConstraint := func(name, label string, ok func(*model.Room) bool) {
+			required := roomcalc.RequiredTags(exam.Constraints.RoomConstraints)
+			if len(required) > 0 {
 				for _, room := range exam.PlannedRooms {
 					roomInfo := roomInfos[room.RoomName]
 					if roomInfo == nil {
 						v.warnf(ref{Ancode: ptr(exam.Ancode), Room: ptr(room.RoomName), Starttime: exam.PlanEntry.Starttime},
-							"No room info found for room %s for exam %d. %s (%s) at %s; cannot check %s",
+							"No room info found for room %s for exam %d. %s (%s) at %s; cannot check required tags",
 							room.RoomName, exam.Ancode, exam.ZpaExam.Module, exam.ZpaExam.MainExamer,
-							examStart, name)
+							examStart)
 						continue
 					}
-					if !ok(roomInfo) {
+					have := roomcalc.RoomTags(roomInfo)
+					if missing := have.Missing(required); len(missing) > 0 {
 						v.errorf(ref{Ancode: ptr(exam.Ancode), Room: ptr(room.RoomName), Starttime: exam.PlanEntry.Starttime},
-							"%s %s for exam %d. %s (%s) at %s",
-							label, room.RoomName, exam.Ancode, exam.ZpaExam.Module, exam.ZpaExam.MainExamer,
+							"Room %s lacks %s for exam %d. %s (%s) at %s",
+							room.RoomName, strings.Join(missing, ", "), exam.Ancode, exam.ZpaExam.Module, exam.ZpaExam.MainExamer,
 							examStart)
+						continue
+					}
+					// a room with pc:30 seats at most 30 students of an exam requiring pc
+					for name := range required {
+						if qty := have[name]; qty > 0 && len(room.StudentsInRoom) > qty {
+							v.errorf(ref{Ancode: ptr(exam.Ancode), Room: ptr(room.RoomName), Starttime: exam.PlanEntry.Starttime},
+								"Room %s has %d %s but %d students of exam %d. %s (%s) at %s",
+								room.RoomName, qty, name, len(room.StudentsInRoom), exam.Ancode, exam.ZpaExam.Module,
+								exam.ZpaExam.MainExamer, examStart)
+						}
 					}
 				}
 			}
-			if exam.Constraints.RoomConstraints.Exahm {
-				checkRoomConstraint("exahm", "Is not an exahm room", func(r *model.Room) bool { return r.Exahm })
-			}
-			if exam.Constraints.RoomConstraints.Seb {
-				checkRoomConstraint("seb", "Is not a seb room", func(r *model.Room) bool { return r.Seb })
-			}
-			if exam.Constraints.RoomConstraints.Lab {
-				checkRoomConstraint("lab", "Is not a lab", func(r *model.Room) bool { return r.Lab })
-			}
-			if exam.Constraints.RoomConstraints.PlacesWithSocket {
-				checkRoomConstraint("places with sockets", "Is not a room with places with sockets",
-					func(r *model.Room) bool { return r.PlacesWithSocket || r.Lab })
-			}
 		}
 
 		// check rooms for NTAs
@@ -205,14 +205,21 @@ func (p *Plexams) UploadPlan(ctx context.Context, withRooms, withInvigilators, u
 
 					for _, roomForAncode := range roomsForAncode {
 						invigilatorID := 0
+						var additionalInvigilatorIDs []int
 						if withInvigilators {
-							invigilator, err := p.invigilatorForRoomAtTime(ctx, roomForAncode.RoomName, start)
+							invigilators, err := p.invigilatorsForRoomAtTime(ctx, roomForAncode.RoomName, start)
 							if err != nil {
 								log.Error().Err(err).Int("ancode", exam.Ancode).Str("room", roomForAncode.RoomName).
 									Msg("cannot get invigilator for room")
 								return nil, err
 							}
-							invigilatorID = invigilator.ID
+							if len(invigilators) == 0 {
+								return nil, fmt.Errorf("no invigilator for room %s at %s", roomForAncode.RoomName, start.Format("02.01. 15:04"))
+							}
+							invigilatorID = invigilators[0].ID
+							for _, invigilator := range invigilators[1:] {
+								additionalInvigilatorIDs = append(additionalInvigilatorIDs, invigilator.ID)
+							}
 						}
 
 						roomName := roomForAncode.RoomName
@@ -230,12 +237,13 @@ func (p *Plexams) UploadPlan(ctx context.Context, withRooms, withInvigilators, u
 							roomWithDuration = make([]*model.ZPAExamPlanRoom, 0, 1)
 						}
 						roomsMap[roomNameWithDuration] = append(roomWithDuration, &model.ZPAExamPlanRoom{
-							RoomName:      roomName,
-							InvigilatorID: invigilatorID,
-							Duration:      roomForAncode.Duration,
-							IsReserve:     roomForAncode.Reserve,
-							StudentCount:  len(roomForAncode.StudentsInRoom),
-							IsHandicap:    roomForAncode.Handicap,
+							RoomName:                 roomName,
+							InvigilatorID:            invigilatorID,
+							AdditionalInvigilatorIDs: additionalInvigilatorIDs,
+							Duration:                 roomForAncode.Duration,
+							IsReserve:                roomForAncode.Reserve,
+							StudentCount:             len(roomForAncode.StudentsInRoom),
+							IsHandicap:               roomForAncode.Handicap,
 						})
 					}
 